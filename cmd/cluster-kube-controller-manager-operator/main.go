@@ -12,11 +12,14 @@ import (
 	"github.com/openshift/library-go/pkg/operator/staticpod/installerpod"
 	"github.com/openshift/library-go/pkg/operator/staticpod/prune"
 
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/cmd/check"
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/cmd/managedresources"
 	operatorcmd "github.com/openshift/cluster-kube-controller-manager-operator/pkg/cmd/operator"
 	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/cmd/recoverycontroller"
 	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/cmd/render"
 	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/cmd/resourcegraph"
-	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator"
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/cmd/rolloutbarrier"
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/operatorruntime"
 )
 
 func main() {
@@ -38,10 +41,13 @@ func NewSSCSCommand(ctx context.Context) *cobra.Command {
 	cmd.AddCommand(operatorcmd.NewOperator())
 	cmd.AddCommand(render.NewRenderCommand(nil))
 	cmd.AddCommand(installerpod.NewInstaller(ctx))
+	cmd.AddCommand(rolloutbarrier.NewRolloutBarrierCommand())
 	cmd.AddCommand(prune.NewPrune())
 	cmd.AddCommand(resourcegraph.NewResourceChainCommand())
-	cmd.AddCommand(certsyncpod.NewCertSyncControllerCommand(operator.CertConfigMaps, operator.CertSecrets))
+	cmd.AddCommand(certsyncpod.NewCertSyncControllerCommand(operatorruntime.CertConfigMaps, operatorruntime.CertSecrets))
 	cmd.AddCommand(recoverycontroller.NewCertRecoveryControllerCommand(ctx))
+	cmd.AddCommand(check.NewCheckCommand(operatorruntime.ManagedResources()))
+	cmd.AddCommand(managedresources.NewManagedResourcesCommand())
 
 	return cmd
 }
@@ -0,0 +1,259 @@
+package check
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/clientcmd"
+	"k8s.io/client-go/util/cert"
+	"k8s.io/klog/v2"
+
+	operatorv1 "github.com/openshift/api/operator/v1"
+	operatorv1client "github.com/openshift/client-go/operator/clientset/versioned/typed/operator/v1"
+	"github.com/openshift/library-go/pkg/operator/staticpod/controller/installer"
+
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/operatorclient"
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/targetconfigcontroller"
+)
+
+// Status is the outcome of a single check.
+type Status string
+
+const (
+	Passed  Status = "Passed"
+	Failed  Status = "Failed"
+	Skipped Status = "Skipped"
+)
+
+// Result is the outcome of a check of a single resource.
+type Result struct {
+	// Check names what was verified: exists, content, certificate or status.
+	Check    string `json:"check"`
+	Resource string `json:"resource"`
+	Status   Status `json:"status"`
+	Message  string `json:"message,omitempty"`
+}
+
+// Report is the machine-readable output of the check command.
+type Report struct {
+	Passed  bool     `json:"passed"`
+	Results []Result `json:"results"`
+}
+
+func (r *Report) add(check, resource string, status Status, messageFmt string, args ...interface{}) {
+	r.Results = append(r.Results, Result{Check: check, Resource: resource, Status: status, Message: fmt.Sprintf(messageFmt, args...)})
+	if status == Failed {
+		r.Passed = false
+	}
+}
+
+// certificateCheck verifies a serving or client certificate of the target namespace against the CA bundle its peers
+// trust it with.
+type certificateCheck struct {
+	secret            string
+	caBundleNamespace string
+	caBundle          string
+}
+
+var certificateChecks = []certificateCheck{
+	// the kubelet client certificates issued by the signer are verified by the kube-apiserver with its client CA
+	{secret: "csr-signer", caBundleNamespace: operatorclient.GlobalMachineSpecifiedConfigNamespace, caBundle: "kube-apiserver-client-ca"},
+	{secret: "kube-controller-manager-client-cert-key", caBundleNamespace: operatorclient.TargetNamespace, caBundle: "client-ca"},
+	{secret: "serving-cert", caBundleNamespace: operatorclient.TargetNamespace, caBundle: "service-ca"},
+	{secret: "metrics-serving-cert", caBundleNamespace: operatorclient.TargetNamespace, caBundle: "service-ca"},
+}
+
+type checkOpts struct {
+	kubeconfig string
+	configMaps []installer.UnrevisionedResource
+	secrets    []installer.UnrevisionedResource
+	out        io.Writer
+}
+
+// NewCheckCommand creates a command verifying the resources the operator manages in a running cluster, for
+// must-gather and support tooling. It exits with an error when any check fails.
+func NewCheckCommand(configMaps, secrets []installer.UnrevisionedResource) *cobra.Command {
+	o := &checkOpts{configMaps: configMaps, secrets: secrets, out: os.Stdout}
+	cmd := &cobra.Command{
+		Use:   "check",
+		Short: "Verify the resources managed by the operator and print a JSON report",
+		Run: func(cmd *cobra.Command, args []string) {
+			if err := o.Run(cmd.Context()); err != nil {
+				klog.Fatal(err)
+			}
+		},
+	}
+	o.AddFlags(cmd.Flags())
+	return cmd
+}
+
+func (o *checkOpts) AddFlags(fs *pflag.FlagSet) {
+	fs.StringVar(&o.kubeconfig, "kubeconfig", o.kubeconfig, "Path to the kubeconfig of the cluster, the in-cluster config is used when empty.")
+}
+
+func (o *checkOpts) Run(ctx context.Context) error {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	loadingRules := clientcmd.NewDefaultClientConfigLoadingRules()
+	loadingRules.ExplicitPath = o.kubeconfig
+	config, err := clientcmd.NewNonInteractiveDeferredLoadingClientConfig(loadingRules, &clientcmd.ConfigOverrides{}).ClientConfig()
+	if err != nil {
+		return err
+	}
+	kubeClient, err := kubernetes.NewForConfig(config)
+	if err != nil {
+		return err
+	}
+	operatorClient, err := operatorv1client.NewForConfig(config)
+	if err != nil {
+		return err
+	}
+
+	report := Check(ctx, kubeClient, operatorClient, o.configMaps, o.secrets, time.Now())
+	data, err := json.MarshalIndent(report, "", "  ")
+	if err != nil {
+		return err
+	}
+	if _, err := fmt.Fprintln(o.out, string(data)); err != nil {
+		return err
+	}
+	if !report.Passed {
+		return fmt.Errorf("some checks failed")
+	}
+	return nil
+}
+
+// Check verifies the operator resource, that the given configmaps and secrets of the target namespace exist, that
+// the configmaps the operator renders were not modified outside of it, and that the certificates chain up to the CA
+// bundles they are trusted with.
+func Check(ctx context.Context, kubeClient kubernetes.Interface, operatorClient operatorv1client.KubeControllerManagersGetter, configMaps, secrets []installer.UnrevisionedResource, now time.Time) Report {
+	report := Report{Passed: true}
+	checkOperator(ctx, &report, operatorClient)
+
+	for _, resource := range configMaps {
+		name := fmt.Sprintf("configmap/%s -n %s", resource.Name, operatorclient.TargetNamespace)
+		configMap, err := kubeClient.CoreV1().ConfigMaps(operatorclient.TargetNamespace).Get(ctx, resource.Name, metav1.GetOptions{})
+		if !checkExists(&report, name, resource.Optional, err) {
+			continue
+		}
+		switch {
+		case len(configMap.Annotations[targetconfigcontroller.ContentHashAnnotation]) == 0:
+		case targetconfigcontroller.IsModifiedOutsideOperator(configMap):
+			report.add("content", name, Failed, "the data does not match the %s annotation, it was modified outside of the operator", targetconfigcontroller.ContentHashAnnotation)
+		default:
+			report.add("content", name, Passed, "")
+		}
+	}
+
+	fetched := map[string]*corev1.Secret{}
+	for _, resource := range secrets {
+		name := fmt.Sprintf("secret/%s -n %s", resource.Name, operatorclient.TargetNamespace)
+		secret, err := kubeClient.CoreV1().Secrets(operatorclient.TargetNamespace).Get(ctx, resource.Name, metav1.GetOptions{})
+		if checkExists(&report, name, resource.Optional, err) {
+			fetched[resource.Name] = secret
+		}
+	}
+
+	for _, certificate := range certificateChecks {
+		name := fmt.Sprintf("secret/%s -n %s", certificate.secret, operatorclient.TargetNamespace)
+		secret, ok := fetched[certificate.secret]
+		if !ok {
+			report.add("certificate", name, Skipped, "the secret is not available")
+			continue
+		}
+		caBundle, err := kubeClient.CoreV1().ConfigMaps(certificate.caBundleNamespace).Get(ctx, certificate.caBundle, metav1.GetOptions{})
+		if err != nil {
+			report.add("certificate", name, Failed, "failed to get configmap/%s -n %s: %v", certificate.caBundle, certificate.caBundleNamespace, err)
+			continue
+		}
+		if err := verifyCertificate(secret, []byte(caBundle.Data["ca-bundle.crt"]), now); err != nil {
+			report.add("certificate", name, Failed, "not trusted by configmap/%s -n %s: %v", certificate.caBundle, certificate.caBundleNamespace, err)
+			continue
+		}
+		report.add("certificate", name, Passed, "")
+	}
+
+	return report
+}
+
+func checkOperator(ctx context.Context, report *Report, operatorClient operatorv1client.KubeControllerManagersGetter) {
+	const name = "kubecontrollermanager/cluster"
+	operator, err := operatorClient.KubeControllerManagers().Get(ctx, "cluster", metav1.GetOptions{})
+	if err != nil {
+		report.add("status", name, Failed, "%v", err)
+		return
+	}
+	if operator.Spec.ManagementState != operatorv1.Managed {
+		report.add("status", name, Skipped, "the management state is %s", operator.Spec.ManagementState)
+		return
+	}
+	degraded := []string{}
+	for _, condition := range operator.Status.Conditions {
+		if condition.Status == operatorv1.ConditionTrue && strings.HasSuffix(condition.Type, "Degraded") {
+			degraded = append(degraded, fmt.Sprintf("%s: %s", condition.Type, condition.Message))
+		}
+	}
+	if len(degraded) > 0 {
+		report.add("status", name, Failed, "%s", strings.Join(degraded, "; "))
+		return
+	}
+	report.add("status", name, Passed, "")
+}
+
+// checkExists reports whether the resource could be read, a missing optional resource is skipped.
+func checkExists(report *Report, name string, optional bool, err error) bool {
+	switch {
+	case apierrors.IsNotFound(err) && optional:
+		report.add("exists", name, Skipped, "the resource is optional")
+	case err != nil:
+		report.add("exists", name, Failed, "%v", err)
+	default:
+		report.add("exists", name, Passed, "")
+		return true
+	}
+	return false
+}
+
+// verifyCertificate checks the key pair of a TLS secret and that its certificate chains up to the CA bundle.
+func verifyCertificate(secret *corev1.Secret, caBundle []byte, now time.Time) error {
+	if _, err := tls.X509KeyPair(secret.Data[corev1.TLSCertKey], secret.Data[corev1.TLSPrivateKeyKey]); err != nil {
+		return err
+	}
+	certs, err := cert.ParseCertsPEM(secret.Data[corev1.TLSCertKey])
+	if err != nil {
+		return err
+	}
+	roots := x509.NewCertPool()
+	caCerts, err := cert.ParseCertsPEM(caBundle)
+	if err != nil {
+		return err
+	}
+	for _, caCert := range caCerts {
+		roots.AddCert(caCert)
+	}
+	intermediates := x509.NewCertPool()
+	for _, intermediate := range certs[1:] {
+		intermediates.AddCert(intermediate)
+	}
+	_, err = certs[0].Verify(x509.VerifyOptions{
+		Roots:         roots,
+		Intermediates: intermediates,
+		CurrentTime:   now,
+		KeyUsages:     []x509.ExtKeyUsage{x509.ExtKeyUsageAny},
+	})
+	return err
+}
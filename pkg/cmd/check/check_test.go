@@ -0,0 +1,165 @@
+package check
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/client-go/kubernetes/fake"
+
+	operatorv1 "github.com/openshift/api/operator/v1"
+	operatorv1client "github.com/openshift/client-go/operator/clientset/versioned/typed/operator/v1"
+	"github.com/openshift/library-go/pkg/crypto"
+	"github.com/openshift/library-go/pkg/operator/staticpod/controller/installer"
+
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/operatorclient"
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/targetconfigcontroller"
+)
+
+type fakeOperatorClient struct {
+	operatorv1client.KubeControllerManagerInterface
+	operator *operatorv1.KubeControllerManager
+}
+
+func (c fakeOperatorClient) KubeControllerManagers() operatorv1client.KubeControllerManagerInterface {
+	return c
+}
+
+func (c fakeOperatorClient) Get(_ context.Context, _ string, _ metav1.GetOptions) (*operatorv1.KubeControllerManager, error) {
+	return c.operator, nil
+}
+
+func TestCheck(t *testing.T) {
+	caConfig, err := crypto.MakeSelfSignedCAConfigForDuration("service-ca", time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	caCert, _, err := caConfig.GetPEMBytes()
+	if err != nil {
+		t.Fatal(err)
+	}
+	ca := &crypto.CA{Config: caConfig, SerialGenerator: &crypto.RandomSerialGenerator{}}
+	servingConfig, err := ca.MakeServerCertForDuration(sets.NewString("kube-controller-manager.openshift-kube-controller-manager.svc"), time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	servingCert, servingKey, err := servingConfig.GetPEMBytes()
+	if err != nil {
+		t.Fatal(err)
+	}
+	otherCAConfig, err := crypto.MakeSelfSignedCAConfigForDuration("other-ca", time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	otherCACert, _, err := otherCAConfig.GetPEMBytes()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	renderedConfig := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Namespace: operatorclient.TargetNamespace, Name: "config"},
+		Data:       map[string]string{"config.yaml": "{}"},
+	}
+	renderedConfig.Annotations = map[string]string{targetconfigcontroller.ContentHashAnnotation: contentHash(t, renderedConfig.Data)}
+	modifiedConfig := renderedConfig.DeepCopy()
+	modifiedConfig.Data["config.yaml"] = `{"modified": true}`
+
+	managedOperator := &operatorv1.KubeControllerManager{
+		ObjectMeta: metav1.ObjectMeta{Name: "cluster"},
+		Spec:       operatorv1.KubeControllerManagerSpec{StaticPodOperatorSpec: operatorv1.StaticPodOperatorSpec{OperatorSpec: operatorv1.OperatorSpec{ManagementState: operatorv1.Managed}}},
+	}
+	degradedOperator := managedOperator.DeepCopy()
+	degradedOperator.Status.Conditions = []operatorv1.OperatorCondition{{Type: "TargetConfigControllerDegraded", Status: operatorv1.ConditionTrue, Message: "broken"}}
+
+	configMaps := []installer.UnrevisionedResource{{Name: "config"}, {Name: "cloud-config", Optional: true}}
+	secrets := []installer.UnrevisionedResource{{Name: "serving-cert", Optional: true}}
+
+	tests := []struct {
+		name           string
+		operator       *operatorv1.KubeControllerManager
+		objects        []runtime.Object
+		expectedPassed bool
+		expected       map[string]Status
+	}{
+		{
+			name:     "healthy",
+			operator: managedOperator,
+			objects: []runtime.Object{
+				renderedConfig,
+				&corev1.Secret{ObjectMeta: metav1.ObjectMeta{Namespace: operatorclient.TargetNamespace, Name: "serving-cert"}, Data: map[string][]byte{"tls.crt": servingCert, "tls.key": servingKey}},
+				&corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Namespace: operatorclient.TargetNamespace, Name: "service-ca"}, Data: map[string]string{"ca-bundle.crt": string(caCert)}},
+			},
+			expectedPassed: true,
+			expected: map[string]Status{
+				"status kubecontrollermanager/cluster":                                 Passed,
+				"exists configmap/config -n openshift-kube-controller-manager":         Passed,
+				"content configmap/config -n openshift-kube-controller-manager":        Passed,
+				"exists configmap/cloud-config -n openshift-kube-controller-manager":   Skipped,
+				"certificate secret/serving-cert -n openshift-kube-controller-manager": Passed,
+				"certificate secret/csr-signer -n openshift-kube-controller-manager":   Skipped,
+			},
+		},
+		{
+			name:     "modified, untrusted and degraded",
+			operator: degradedOperator,
+			objects: []runtime.Object{
+				modifiedConfig,
+				&corev1.Secret{ObjectMeta: metav1.ObjectMeta{Namespace: operatorclient.TargetNamespace, Name: "serving-cert"}, Data: map[string][]byte{"tls.crt": servingCert, "tls.key": servingKey}},
+				&corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Namespace: operatorclient.TargetNamespace, Name: "service-ca"}, Data: map[string]string{"ca-bundle.crt": string(otherCACert)}},
+			},
+			expected: map[string]Status{
+				"status kubecontrollermanager/cluster":                                 Failed,
+				"content configmap/config -n openshift-kube-controller-manager":        Failed,
+				"certificate secret/serving-cert -n openshift-kube-controller-manager": Failed,
+			},
+		},
+		{
+			name:     "missing",
+			operator: managedOperator,
+			expected: map[string]Status{
+				"exists configmap/config -n openshift-kube-controller-manager":         Failed,
+				"exists secret/serving-cert -n openshift-kube-controller-manager":      Skipped,
+				"certificate secret/serving-cert -n openshift-kube-controller-manager": Skipped,
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			kubeClient := fake.NewSimpleClientset(test.objects...)
+
+			report := Check(context.Background(), kubeClient, fakeOperatorClient{operator: test.operator}, configMaps, secrets, time.Now())
+			if report.Passed != test.expectedPassed {
+				t.Errorf("expected passed %v, got %#v", test.expectedPassed, report)
+			}
+			results := map[string]Result{}
+			for _, result := range report.Results {
+				results[result.Check+" "+result.Resource] = result
+			}
+			for key, expected := range test.expected {
+				if result, ok := results[key]; !ok || result.Status != expected {
+					t.Errorf("%s: expected %s, got %#v", key, expected, result)
+				}
+			}
+		})
+	}
+}
+
+// contentHash mirrors the hash the target config controller stamps on the configmaps it renders.
+func contentHash(t *testing.T, data map[string]string) string {
+	content, err := json.Marshal(struct {
+		Data map[string]string `json:"data,omitempty"`
+	}{data})
+	if err != nil {
+		t.Fatal(err)
+	}
+	hash := sha256.Sum256(content)
+	return hex.EncodeToString(hash[:])
+}
@@ -0,0 +1,70 @@
+package managedresources
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/clientcmd"
+	"k8s.io/klog/v2"
+
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/ownershipcontroller"
+)
+
+type managedResourcesOpts struct {
+	kubeconfig string
+	out        io.Writer
+}
+
+// NewManagedResourcesCommand creates a command listing the configmaps and secrets stamped as owned by the operator,
+// for backup and GitOps tooling.
+func NewManagedResourcesCommand() *cobra.Command {
+	o := &managedResourcesOpts{out: os.Stdout}
+	cmd := &cobra.Command{
+		Use:   "managed-resources",
+		Short: "List the configmaps and secrets owned by the operator as JSON",
+		Run: func(cmd *cobra.Command, args []string) {
+			if err := o.Run(cmd.Context()); err != nil {
+				klog.Fatal(err)
+			}
+		},
+	}
+	o.AddFlags(cmd.Flags())
+	return cmd
+}
+
+func (o *managedResourcesOpts) AddFlags(fs *pflag.FlagSet) {
+	fs.StringVar(&o.kubeconfig, "kubeconfig", o.kubeconfig, "Path to the kubeconfig of the cluster, the in-cluster config is used when empty.")
+}
+
+func (o *managedResourcesOpts) Run(ctx context.Context) error {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	loadingRules := clientcmd.NewDefaultClientConfigLoadingRules()
+	loadingRules.ExplicitPath = o.kubeconfig
+	config, err := clientcmd.NewNonInteractiveDeferredLoadingClientConfig(loadingRules, &clientcmd.ConfigOverrides{}).ClientConfig()
+	if err != nil {
+		return err
+	}
+	kubeClient, err := kubernetes.NewForConfig(config)
+	if err != nil {
+		return err
+	}
+
+	owned, err := ownershipcontroller.List(ctx, kubeClient.CoreV1())
+	if err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(owned, "", "  ")
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintln(o.out, string(data))
+	return err
+}
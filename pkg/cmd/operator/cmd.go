@@ -9,11 +9,16 @@ import (
 )
 
 func NewOperator() *cobra.Command {
-	cmd := controllercmd.
-		NewControllerCommandConfig("kube-controller-manager-operator", version.Get(), operator.RunOperator).
-		NewCommand()
+	operatorOptions := &operator.Options{}
+	leaderElection := &leaderElectionOptions{}
+	config := controllercmd.NewControllerCommandConfig(componentName, version.Get(), leaderElection.Run(operatorOptions.Run))
+	// the operator runs its own leader election, see leaderElectionOptions
+	config.DisableLeaderElection = true
+	cmd := config.NewCommand()
 	cmd.Use = "operator"
 	cmd.Short = "Start the Cluster kube-controller-manager Operator"
+	operatorOptions.AddFlags(cmd.Flags())
+	leaderElection.AddFlags(cmd.Flags())
 
 	return cmd
 }
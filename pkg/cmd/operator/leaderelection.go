@@ -0,0 +1,263 @@
+package operator
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/spf13/pflag"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/leaderelection"
+	"k8s.io/client-go/tools/leaderelection/resourcelock"
+	"k8s.io/component-base/metrics"
+	"k8s.io/component-base/metrics/legacyregistry"
+	"k8s.io/klog/v2"
+
+	configv1 "github.com/openshift/api/config/v1"
+	configv1client "github.com/openshift/client-go/config/clientset/versioned/typed/config/v1"
+	leaderelectionconverter "github.com/openshift/library-go/pkg/config/leaderelection"
+	"github.com/openshift/library-go/pkg/controller/controllercmd"
+	"github.com/openshift/library-go/pkg/operator/events"
+)
+
+const (
+	componentName = "kube-controller-manager-operator"
+	// leaseName is the lease library-go elected the operator with, replicas of older versions keep competing for it
+	// during an upgrade
+	leaseName = componentName + "-lock"
+
+	// gracefulTerminationDuration is the time the controllers get to finish their workers once the lease is released
+	gracefulTerminationDuration = 10 * time.Second
+
+	// the previous holders of the lease the acquisitions are counted by
+	previousLeaderNone     = "none"
+	previousLeaderReleased = "released"
+	previousLeaderExpired  = "expired"
+)
+
+var (
+	leaderAcquisitions = metrics.NewCounterVec(
+		&metrics.CounterOpts{
+			Name:           "kube_controller_manager_operator_leader_election_acquisitions_total",
+			Help:           "Number of times a replica of the operator acquired the leader lease, by whether the previous leader released it, let it expire or there was none.",
+			StabilityLevel: metrics.ALPHA,
+		},
+		[]string{"previous_leader"},
+	)
+	leaderHandoffDuration = metrics.NewHistogramVec(
+		&metrics.HistogramOpts{
+			Name:           "kube_controller_manager_operator_leader_election_handoff_duration_seconds",
+			Help:           "Time between the last renewal or the release of the leader lease by the previous leader and its acquisition by this replica, by whether the previous leader released it or let it expire.",
+			Buckets:        []float64{1, 2, 5, 10, 20, 30, 60, 120, 180, 300},
+			StabilityLevel: metrics.ALPHA,
+		},
+		[]string{"previous_leader"},
+	)
+)
+
+func init() {
+	legacyregistry.MustRegister(leaderAcquisitions, leaderHandoffDuration)
+}
+
+// leaderElectionOptions elect the operator instead of library-go, so that the lease can be tuned and its handoffs are
+// visible in metrics and events. Two replicas of the operator can run, the standby one takes over within the lease
+// duration when the leader is gone and within the retry period when the leader releases the lease on shutdown.
+type leaderElectionOptions struct {
+	LeaseDuration time.Duration
+	RenewDeadline time.Duration
+	RetryPeriod   time.Duration
+}
+
+func (o *leaderElectionOptions) AddFlags(flags *pflag.FlagSet) {
+	flags.DurationVar(&o.LeaseDuration, "leader-elect-lease-duration", o.LeaseDuration, "The duration a standby replica waits after the last renewal before it takes over the leader lease. Defaults to 137s, 270s on single replica control planes.")
+	flags.DurationVar(&o.RenewDeadline, "leader-elect-renew-deadline", o.RenewDeadline, "The duration the leader retries renewing the lease before it gives up leading. Defaults to 107s, 240s on single replica control planes.")
+	flags.DurationVar(&o.RetryPeriod, "leader-elect-retry-period", o.RetryPeriod, "The duration between attempts to acquire or renew the lease. Defaults to 26s, 60s on single replica control planes.")
+}
+
+// Validate makes sure the lease parameters are accepted by client-go, which panics on invalid ones.
+func (o *leaderElectionOptions) Validate() error {
+	if o.LeaseDuration < 0 || o.RenewDeadline < 0 || o.RetryPeriod < 0 {
+		return fmt.Errorf("the leader election durations must not be negative")
+	}
+	if !o.explicit() {
+		return nil
+	}
+	config := o.config(false)
+	if config.LeaseDuration.Duration <= config.RenewDeadline.Duration {
+		return fmt.Errorf("--leader-elect-lease-duration (%s) must be greater than --leader-elect-renew-deadline (%s)", config.LeaseDuration.Duration, config.RenewDeadline.Duration)
+	}
+	if float64(config.RenewDeadline.Duration) <= leaderelection.JitterFactor*float64(config.RetryPeriod.Duration) {
+		return fmt.Errorf("--leader-elect-renew-deadline (%s) must be greater than %.1f times --leader-elect-retry-period (%s)", config.RenewDeadline.Duration, leaderelection.JitterFactor, config.RetryPeriod.Duration)
+	}
+	return nil
+}
+
+func (o *leaderElectionOptions) explicit() bool {
+	return o.LeaseDuration != 0 || o.RenewDeadline != 0 || o.RetryPeriod != 0
+}
+
+// config defaults the durations that are not set the way library-go does. The longer single replica durations are
+// only used when none of the durations is set.
+func (o *leaderElectionOptions) config(singleReplica bool) configv1.LeaderElection {
+	config := leaderelectionconverter.LeaderElectionDefaulting(configv1.LeaderElection{
+		LeaseDuration: metav1.Duration{Duration: o.LeaseDuration},
+		RenewDeadline: metav1.Duration{Duration: o.RenewDeadline},
+		RetryPeriod:   metav1.Duration{Duration: o.RetryPeriod},
+	}, "", leaseName)
+	if singleReplica && !o.explicit() {
+		config = leaderelectionconverter.LeaderElectionSNOConfig(config)
+	}
+	return config
+}
+
+// Run returns a start function that runs the given one while this replica holds the leader lease.
+func (o *leaderElectionOptions) Run(start controllercmd.StartFunc) controllercmd.StartFunc {
+	return func(ctx context.Context, cc *controllercmd.ControllerContext) error {
+		if err := o.Validate(); err != nil {
+			return err
+		}
+		config := o.config(isSingleReplica(ctx, cc.KubeConfig, cc.EventRecorder))
+		config.Namespace = cc.OperatorNamespace
+
+		// ensure blocking TCP connections don't block the leader election
+		leaderConfig := rest.CopyConfig(cc.ProtoKubeConfig)
+		leaderConfig.Timeout = config.RenewDeadline.Duration
+		election, err := leaderelectionconverter.ToLeaderElectionWithLease(leaderConfig, config, componentName, "")
+		if err != nil {
+			return err
+		}
+		lock := &observingLock{Interface: election.Lock}
+		election.Lock = lock
+
+		stopped := make(chan struct{})
+		election.Callbacks = leaderelection.LeaderCallbacks{
+			OnStartedLeading: func(leaderCtx context.Context) {
+				defer close(stopped)
+				recordAcquisition(cc.EventRecorder, lock.Identity(), lock.previousRecord(), time.Now())
+				if err := start(leaderCtx, cc); err != nil {
+					klog.Errorf("The controllers failed: %v", err)
+				}
+				if leaderCtx.Err() == nil {
+					klog.Fatalf("The controllers terminated prematurely")
+				}
+			},
+			OnStoppedLeading: func() {
+				if !lock.acquired() {
+					return
+				}
+				if ctx.Err() == nil {
+					// the replica taking over counts the loss, this one is gone before the next scrape
+					cc.EventRecorder.Warningf("LeaderElectionLost", "%s lost the leader lease %s/%s and exits", lock.Identity(), config.Namespace, config.Name)
+					cc.EventRecorder.Shutdown()
+					os.Exit(0)
+				}
+				// the lease is released on shutdown already, a standby replica takes over while the controllers
+				// finish their workers
+				select {
+				case <-stopped:
+				case <-time.After(gracefulTerminationDuration):
+					klog.Fatalf("The controllers did not finish within %s", gracefulTerminationDuration)
+				}
+			},
+		}
+
+		klog.Infof("Waiting for the leader lease %s/%s: lease duration %s, renew deadline %s, retry period %s", config.Namespace, config.Name, config.LeaseDuration.Duration, config.RenewDeadline.Duration, config.RetryPeriod.Duration)
+		leaderelection.RunOrDie(ctx, election)
+		return nil
+	}
+}
+
+// isSingleReplica tells whether the control plane runs a single replica, where the leader election gets longer
+// durations to put less load on the kube-apiserver.
+func isSingleReplica(ctx context.Context, kubeConfig *rest.Config, recorder events.Recorder) bool {
+	client, err := configv1client.NewForConfig(kubeConfig)
+	if err != nil {
+		klog.Warningf("Using the leader election durations of highly available clusters: %v", err)
+		return false
+	}
+	infrastructure, err := client.Infrastructures().Get(ctx, "cluster", metav1.GetOptions{})
+	if err != nil {
+		recorder.Warningf("ControlPlaneTopology", "unable to get control plane topology, using HA cluster values for leader election: %v", err)
+		return false
+	}
+	return infrastructure.Status.ControlPlaneTopology == configv1.SingleReplicaTopologyMode
+}
+
+// recordAcquisition counts an acquisition of the lease by the previous holder this replica observed before.
+func recordAcquisition(recorder events.Recorder, identity string, previous *resourcelock.LeaderElectionRecord, now time.Time) {
+	switch {
+	case previous == nil:
+		leaderAcquisitions.WithLabelValues(previousLeaderNone).Inc()
+		recorder.Eventf("LeaderElectionAcquired", "%s acquired the leader lease", identity)
+	case len(previous.HolderIdentity) == 0:
+		handoff := now.Sub(previous.RenewTime.Time)
+		leaderAcquisitions.WithLabelValues(previousLeaderReleased).Inc()
+		leaderHandoffDuration.WithLabelValues(previousLeaderReleased).Observe(handoff.Seconds())
+		recorder.Eventf("LeaderElectionAcquired", "%s acquired the leader lease %s after the previous leader released it", identity, handoff.Round(time.Second))
+	default:
+		handoff := now.Sub(previous.RenewTime.Time)
+		leaderAcquisitions.WithLabelValues(previousLeaderExpired).Inc()
+		leaderHandoffDuration.WithLabelValues(previousLeaderExpired).Observe(handoff.Seconds())
+		recorder.Warningf("LeaderElectionTakeover", "%s took over the leader lease from %s, which stopped renewing it %s ago", identity, previous.HolderIdentity, handoff.Round(time.Second))
+	}
+}
+
+// observingLock remembers the last record of the lease held by another replica, or released by one, and whether this
+// replica acquired the lease.
+type observingLock struct {
+	resourcelock.Interface
+
+	lock     sync.Mutex
+	previous *resourcelock.LeaderElectionRecord
+	leading  bool
+}
+
+func (l *observingLock) Get(ctx context.Context) (*resourcelock.LeaderElectionRecord, []byte, error) {
+	record, raw, err := l.Interface.Get(ctx)
+	if err == nil && record.HolderIdentity != l.Identity() {
+		l.lock.Lock()
+		if !l.leading {
+			previous := *record
+			l.previous = &previous
+		}
+		l.lock.Unlock()
+	}
+	return record, raw, err
+}
+
+func (l *observingLock) Create(ctx context.Context, record resourcelock.LeaderElectionRecord) error {
+	err := l.Interface.Create(ctx, record)
+	l.observeWrite(record, err)
+	return err
+}
+
+func (l *observingLock) Update(ctx context.Context, record resourcelock.LeaderElectionRecord) error {
+	err := l.Interface.Update(ctx, record)
+	l.observeWrite(record, err)
+	return err
+}
+
+func (l *observingLock) observeWrite(record resourcelock.LeaderElectionRecord, err error) {
+	if err != nil || record.HolderIdentity != l.Identity() {
+		return
+	}
+	l.lock.Lock()
+	defer l.lock.Unlock()
+	l.leading = true
+}
+
+func (l *observingLock) previousRecord() *resourcelock.LeaderElectionRecord {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+	return l.previous
+}
+
+func (l *observingLock) acquired() bool {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+	return l.leading
+}
@@ -0,0 +1,177 @@
+package operator
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/tools/leaderelection/resourcelock"
+	"k8s.io/component-base/metrics/testutil"
+
+	"github.com/openshift/library-go/pkg/operator/events"
+)
+
+func TestLeaderElectionOptions(t *testing.T) {
+	tests := []struct {
+		name          string
+		options       leaderElectionOptions
+		singleReplica bool
+		expected      [3]time.Duration
+		expectedErr   bool
+	}{
+		{
+			name:     "defaults",
+			expected: [3]time.Duration{137 * time.Second, 107 * time.Second, 26 * time.Second},
+		},
+		{
+			name:          "single replica defaults",
+			singleReplica: true,
+			expected:      [3]time.Duration{270 * time.Second, 240 * time.Second, 60 * time.Second},
+		},
+		{
+			name:          "fast failover",
+			options:       leaderElectionOptions{LeaseDuration: 15 * time.Second, RenewDeadline: 10 * time.Second, RetryPeriod: 2 * time.Second},
+			singleReplica: true,
+			expected:      [3]time.Duration{15 * time.Second, 10 * time.Second, 2 * time.Second},
+		},
+		{
+			name:     "partially set",
+			options:  leaderElectionOptions{RetryPeriod: 5 * time.Second},
+			expected: [3]time.Duration{137 * time.Second, 107 * time.Second, 5 * time.Second},
+		},
+		{
+			name:        "renew deadline beyond the lease",
+			options:     leaderElectionOptions{LeaseDuration: 15 * time.Second, RenewDeadline: 20 * time.Second},
+			expectedErr: true,
+		},
+		{
+			name:        "retry period beyond the renew deadline",
+			options:     leaderElectionOptions{RetryPeriod: 90 * time.Second},
+			expectedErr: true,
+		},
+		{
+			name:        "negative",
+			options:     leaderElectionOptions{RetryPeriod: -time.Second},
+			expectedErr: true,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			err := test.options.Validate()
+			if test.expectedErr != (err != nil) {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if err != nil {
+				return
+			}
+			config := test.options.config(test.singleReplica)
+			if actual := [3]time.Duration{config.LeaseDuration.Duration, config.RenewDeadline.Duration, config.RetryPeriod.Duration}; actual != test.expected {
+				t.Errorf("expected %v, got %v", test.expected, actual)
+			}
+			if config.Name != leaseName {
+				t.Errorf("expected the lease %q, got %q", leaseName, config.Name)
+			}
+		})
+	}
+}
+
+func TestObservingLock(t *testing.T) {
+	renewTime := metav1.NewTime(time.Now().Add(-20 * time.Second).Truncate(time.Second))
+	tests := []struct {
+		name             string
+		previous         *resourcelock.LeaderElectionRecord
+		expectedLabel    string
+		expectedHandoffs uint64
+		expectedReason   string
+	}{
+		{
+			name:           "no previous leader",
+			expectedLabel:  previousLeaderNone,
+			expectedReason: "LeaderElectionAcquired",
+		},
+		{
+			name:             "released",
+			previous:         &resourcelock.LeaderElectionRecord{LeaseDurationSeconds: 1, RenewTime: renewTime, AcquireTime: renewTime},
+			expectedLabel:    previousLeaderReleased,
+			expectedHandoffs: 1,
+			expectedReason:   "LeaderElectionAcquired",
+		},
+		{
+			name:             "expired",
+			previous:         &resourcelock.LeaderElectionRecord{HolderIdentity: "other", LeaseDurationSeconds: 15, RenewTime: renewTime, AcquireTime: renewTime},
+			expectedLabel:    previousLeaderExpired,
+			expectedHandoffs: 1,
+			expectedReason:   "LeaderElectionTakeover",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			leaderAcquisitions.Reset()
+			leaderHandoffDuration.Reset()
+
+			client := fake.NewSimpleClientset()
+			lock := &observingLock{Interface: &resourcelock.LeaseLock{
+				LeaseMeta:  metav1.ObjectMeta{Namespace: "ns", Name: leaseName},
+				Client:     client.CoordinationV1(),
+				LockConfig: resourcelock.ResourceLockConfig{Identity: "self"},
+			}}
+			ctx := context.TODO()
+			if test.previous != nil {
+				if err := lock.Interface.Create(ctx, *test.previous); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			if _, _, err := lock.Get(ctx); err == nil {
+				now := metav1.Now()
+				if err := lock.Update(ctx, resourcelock.LeaderElectionRecord{HolderIdentity: "self", LeaseDurationSeconds: 15, RenewTime: now, AcquireTime: now}); err != nil {
+					t.Fatal(err)
+				}
+			} else {
+				now := metav1.Now()
+				if err := lock.Create(ctx, resourcelock.LeaderElectionRecord{HolderIdentity: "self", LeaseDurationSeconds: 15, RenewTime: now, AcquireTime: now}); err != nil {
+					t.Fatal(err)
+				}
+			}
+			if !lock.acquired() {
+				t.Fatalf("expected the lease to be acquired")
+			}
+			// the renewals of this replica don't replace the previous leader
+			if _, _, err := lock.Get(ctx); err != nil {
+				t.Fatal(err)
+			}
+
+			recorder := events.NewInMemoryRecorder("test")
+			recordAcquisition(recorder, lock.Identity(), lock.previousRecord(), time.Now())
+
+			count, err := testutil.GetCounterMetricValue(leaderAcquisitions.WithLabelValues(test.expectedLabel))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if count != 1 {
+				t.Errorf("expected an acquisition with previous leader %q, got %v", test.expectedLabel, count)
+			}
+			handoffs, err := testutil.GetHistogramMetricCount(leaderHandoffDuration.WithLabelValues(test.expectedLabel))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if handoffs != test.expectedHandoffs {
+				t.Errorf("expected %d handoffs, got %d", test.expectedHandoffs, handoffs)
+			}
+			if test.expectedHandoffs > 0 {
+				handoff, err := testutil.GetHistogramMetricValue(leaderHandoffDuration.WithLabelValues(test.expectedLabel))
+				if err != nil {
+					t.Fatal(err)
+				}
+				if handoff < 20 || handoff > 30 {
+					t.Errorf("expected a handoff of about 20s, got %vs", handoff)
+				}
+			}
+			if events := recorder.Events(); len(events) != 1 || events[0].Reason != test.expectedReason {
+				t.Errorf("expected a %s event, got %v", test.expectedReason, events)
+			}
+		})
+	}
+}
@@ -71,13 +71,9 @@ func (o *Options) Run(ctx context.Context) error {
 		return fmt.Errorf("can't build kubernetes client: %w", err)
 	}
 
-	kubeInformersForNamespaces := v1helpers.NewKubeInformersForNamespaces(
-		kubeClient,
-		operatorclient.GlobalMachineSpecifiedConfigNamespace,
-		operatorclient.GlobalUserSpecifiedConfigNamespace,
-		operatorclient.OperatorNamespace,
-		operatorclient.TargetNamespace,
-	)
+	kubeInformersForNamespaces := v1helpers.NewKubeInformersForNamespaces(kubeClient, operatorclient.ConfigNamespaces...)
+	operatorclient.FilterSecretsByName(kubeInformersForNamespaces, operatorclient.GlobalUserSpecifiedConfigNamespace, operatorclient.UserSpecifiedConfigSecretName)
+	operatorclient.FilterSecretsByName(kubeInformersForNamespaces, operatorclient.GlobalMachineSpecifiedConfigNamespace, operatorclient.MachineSpecifiedConfigSecretName)
 
 	operatorClient, dynamicInformers, err := genericoperatorclient.NewStaticPodOperatorClient(o.controllerContext.KubeConfig, operatorv1.GroupVersion.WithResource("kubecontrollermanagers"))
 	if err != nil {
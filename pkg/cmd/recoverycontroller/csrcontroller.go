@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"time"
 
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
 	"k8s.io/apimachinery/pkg/util/wait"
 	"k8s.io/client-go/kubernetes"
@@ -17,6 +18,7 @@ import (
 	"github.com/openshift/library-go/pkg/operator/resourcesynccontroller"
 	"github.com/openshift/library-go/pkg/operator/v1helpers"
 
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/cabundle"
 	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/operatorclient"
 	operatorresourcesync "github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/resourcesynccontroller"
 	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/targetconfigcontroller"
@@ -34,6 +36,9 @@ type CSRController struct {
 
 	eventRecorder events.Recorder
 
+	// caBundles keeps the parsed inputs of the CSR CA bundles across syncs
+	caBundles *cabundle.Registry
+
 	cachesToSync []cache.InformerSynced
 
 	// queue only ever has one item, but it has nice error handling backoff/retry semantics
@@ -53,23 +58,13 @@ func NewCSRController(
 		secretLister:    kubeInformersForNamespaces.SecretLister(),
 		configMapLister: kubeInformersForNamespaces.ConfigMapLister(),
 		eventRecorder:   eventRecorder.WithComponentSuffix("csr-controller"),
+		caBundles:       cabundle.NewRegistry(),
 		queue:           workqueue.NewNamedRateLimitingQueue(workqueue.DefaultControllerRateLimiter(), "CSRRecoveryController"),
 	}
 
-	handler := cache.ResourceEventHandlerFuncs{
-		AddFunc:    func(obj interface{}) { c.queue.Add(workQueueKey) },
-		UpdateFunc: func(old, new interface{}) { c.queue.Add(workQueueKey) },
-		DeleteFunc: func(obj interface{}) { c.queue.Add(workQueueKey) },
-	}
-
 	// we react to some config changes
-	namespaces := []string{
-		operatorclient.GlobalUserSpecifiedConfigNamespace,
-		operatorclient.GlobalMachineSpecifiedConfigNamespace,
-		operatorclient.OperatorNamespace,
-		operatorclient.TargetNamespace,
-	}
-	for _, namespace := range namespaces {
+	handler := operatorclient.NamespaceEventHandler(operatorclient.ConfigNamespaces, func(metav1.Object) { c.queue.Add(workQueueKey) })
+	for _, namespace := range operatorclient.ConfigNamespaces {
 		informers := kubeInformersForNamespaces.InformersFor(namespace)
 		informers.Core().V1().ConfigMaps().Informer().AddEventHandler(handler)
 		c.cachesToSync = append(c.cachesToSync, informers.Core().V1().ConfigMaps().Informer().HasSynced)
@@ -162,7 +157,7 @@ func (c *CSRController) sync(ctx context.Context) error {
 		return nil
 	}
 
-	_, changed, err := targetconfigcontroller.ManageCSRIntermediateCABundle(ctx, c.secretLister, c.kubeClient.CoreV1(), c.eventRecorder)
+	_, changed, err := targetconfigcontroller.ManageCSRIntermediateCABundle(ctx, c.caBundles, c.secretLister, c.kubeClient.CoreV1(), c.eventRecorder)
 	if err != nil {
 		return err
 	}
@@ -170,7 +165,7 @@ func (c *CSRController) sync(ctx context.Context) error {
 		klog.Info("Refreshed CSRIntermediateCABundle.")
 	}
 
-	_, changed, err = targetconfigcontroller.ManageCSRCABundle(ctx, c.configMapLister, c.kubeClient.CoreV1(), c.eventRecorder)
+	_, changed, err = targetconfigcontroller.ManageCSRCABundle(ctx, c.caBundles, c.configMapLister, c.kubeClient.CoreV1(), c.eventRecorder)
 	if err != nil {
 		return err
 	}
@@ -178,7 +173,8 @@ func (c *CSRController) sync(ctx context.Context) error {
 		klog.Info("Refreshed CSRCABundle.")
 	}
 
-	_, requeueDelay, changed, err := targetconfigcontroller.ManageCSRSigner(ctx, c.secretLister, c.kubeClient.CoreV1(), c.eventRecorder)
+	// the recovery has to work with a broken tuning config, it keeps the default delays
+	_, requeueDelay, changed, err := targetconfigcontroller.ManageCSRSigner(ctx, c.secretLister, c.configMapLister, c.kubeClient.CoreV1(), c.eventRecorder, targetconfigcontroller.DefaultCSRSignerRequeueDelays)
 	if err != nil {
 		return err
 	}
@@ -0,0 +1,79 @@
+package rolloutbarrier
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/clientcmd"
+	"k8s.io/klog/v2"
+
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/rolloutbarrier"
+)
+
+type rolloutBarrierOpts struct {
+	kubeconfig string
+	nodeName   string
+	revision   int32
+	timeout    time.Duration
+}
+
+// NewRolloutBarrierCommand creates the command the installer pods wait for the rollout barrier of their node with,
+// before the install of a revision restarts the operand there.
+func NewRolloutBarrierCommand() *cobra.Command {
+	o := &rolloutBarrierOpts{timeout: rolloutbarrier.DefaultTimeout}
+	cmd := &cobra.Command{
+		Use:   "rollout-barrier",
+		Short: "Wait until no other control plane operator rolls out on the node and take the rollout lease of the node",
+		Run: func(cmd *cobra.Command, args []string) {
+			if err := o.Validate(); err != nil {
+				klog.Fatal(err)
+			}
+			if err := o.Run(cmd.Context()); err != nil {
+				klog.Fatal(err)
+			}
+		},
+	}
+	o.AddFlags(cmd.Flags())
+	return cmd
+}
+
+func (o *rolloutBarrierOpts) AddFlags(fs *pflag.FlagSet) {
+	fs.StringVar(&o.kubeconfig, "kubeconfig", o.kubeconfig, "Path to the kubeconfig of the cluster, the in-cluster config is used when empty.")
+	fs.StringVar(&o.nodeName, "node-name", o.nodeName, "Name of the node the revision is installed on.")
+	fs.Int32Var(&o.revision, "revision", o.revision, "Revision installed, it waits for the canary rollout of the revision when set.")
+	fs.DurationVar(&o.timeout, "timeout", o.timeout, "How long to wait for the barrier before the install goes ahead anyway.")
+}
+
+func (o *rolloutBarrierOpts) Validate() error {
+	if len(o.nodeName) == 0 {
+		return fmt.Errorf("--node-name is required")
+	}
+	if o.revision < 0 {
+		return fmt.Errorf("--revision must not be negative")
+	}
+	if o.timeout <= 0 {
+		return fmt.Errorf("--timeout must be positive")
+	}
+	return nil
+}
+
+func (o *rolloutBarrierOpts) Run(ctx context.Context) error {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	loadingRules := clientcmd.NewDefaultClientConfigLoadingRules()
+	loadingRules.ExplicitPath = o.kubeconfig
+	config, err := clientcmd.NewNonInteractiveDeferredLoadingClientConfig(loadingRules, &clientcmd.ConfigOverrides{}).ClientConfig()
+	if err != nil {
+		return err
+	}
+	kubeClient, err := kubernetes.NewForConfig(config)
+	if err != nil {
+		return err
+	}
+	return rolloutbarrier.Wait(ctx, kubeClient, o.nodeName, o.revision, o.timeout)
+}
@@ -0,0 +1,149 @@
+package aggregatorclientcacontroller
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/client-go/kubernetes"
+	corev1client "k8s.io/client-go/kubernetes/typed/core/v1"
+	corev1listers "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/util/cert"
+
+	operatorv1 "github.com/openshift/api/operator/v1"
+	"github.com/openshift/library-go/pkg/controller/factory"
+	"github.com/openshift/library-go/pkg/operator/events"
+	"github.com/openshift/library-go/pkg/operator/management"
+	"github.com/openshift/library-go/pkg/operator/v1helpers"
+
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/operatorclient"
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/targetconfigcontroller"
+)
+
+const (
+	// SourceConfigMapName is the aggregator (front-proxy) client CA the kube-apiserver-operator rotates.
+	SourceConfigMapName = "kube-apiserver-aggregator-client-ca"
+	// ConfigMapName is the copy the kube-controller-manager trusts as --requestheader-client-ca-file. It is not
+	// revisioned, the cert-syncer replaces the mounted bundle on rotation and the operand reloads it.
+	ConfigMapName = "aggregator-client-ca"
+
+	caBundleKey = "ca-bundle.crt"
+)
+
+// AggregatorClientCAController copies the aggregator client CA into the target namespace. Unlike a plain resource
+// sync it keeps the previous copy when the source disappears or holds no unexpired certificate, so that a broken
+// rotation does not remove the trust of the requests proxied by the kube-apiserver, and it reports the rotations.
+// The cluster-policy-controller reads the same CA from the extension-apiserver-authentication configmap of kube-system.
+type AggregatorClientCAController struct {
+	operatorClient  v1helpers.StaticPodOperatorClient
+	configMapLister corev1listers.ConfigMapLister
+	configMapClient corev1client.ConfigMapsGetter
+	now             func() time.Time
+}
+
+func NewAggregatorClientCAController(
+	operatorClient v1helpers.StaticPodOperatorClient,
+	kubeInformersForNamespaces v1helpers.KubeInformersForNamespaces,
+	kubeClient kubernetes.Interface,
+	eventRecorder events.Recorder,
+) factory.Controller {
+	c := &AggregatorClientCAController{
+		operatorClient:  operatorClient,
+		configMapLister: kubeInformersForNamespaces.ConfigMapLister(),
+		configMapClient: kubeClient.CoreV1(),
+		now:             time.Now,
+	}
+
+	return factory.New().WithInformers(
+		operatorClient.Informer(),
+	).WithFilteredEventsInformers(
+		factory.NamesFilter(SourceConfigMapName, ConfigMapName),
+		kubeInformersForNamespaces.InformersFor(operatorclient.GlobalMachineSpecifiedConfigNamespace).Core().V1().ConfigMaps().Informer(),
+		// this is for watching our output in case someone changes it
+		kubeInformersForNamespaces.InformersFor(operatorclient.TargetNamespace).Core().V1().ConfigMaps().Informer(),
+	).ResyncEvery(time.Minute).WithSync(c.sync).ToController("AggregatorClientCAController", eventRecorder)
+}
+
+func (c *AggregatorClientCAController) sync(ctx context.Context, syncCtx factory.SyncContext) error {
+	operatorSpec, _, _, err := c.operatorClient.GetStaticPodOperatorState()
+	if err != nil {
+		return err
+	}
+	if !management.IsOperatorManaged(operatorSpec.ManagementState) {
+		return nil
+	}
+
+	syncErr := c.syncAggregatorClientCA(ctx, syncCtx.Recorder())
+
+	condition := operatorv1.OperatorCondition{
+		Type:   "AggregatorClientCAControllerDegraded",
+		Status: operatorv1.ConditionFalse,
+	}
+	if syncErr != nil {
+		condition.Status = operatorv1.ConditionTrue
+		condition.Reason = "SynchronizationError"
+		condition.Message = syncErr.Error()
+	}
+	if _, _, updateErr := v1helpers.UpdateStaticPodStatus(ctx, c.operatorClient, v1helpers.UpdateStaticPodConditionFn(condition)); updateErr != nil {
+		return updateErr
+	}
+	return syncErr
+}
+
+func (c *AggregatorClientCAController) syncAggregatorClientCA(ctx context.Context, recorder events.Recorder) error {
+	existing, err := c.configMapLister.ConfigMaps(operatorclient.TargetNamespace).Get(ConfigMapName)
+	if err != nil && !apierrors.IsNotFound(err) {
+		return err
+	}
+	source, err := c.configMapLister.ConfigMaps(operatorclient.GlobalMachineSpecifiedConfigNamespace).Get(SourceConfigMapName)
+	switch {
+	case apierrors.IsNotFound(err) && existing == nil:
+		// the kube-apiserver-operator has not issued it yet
+		return nil
+	case apierrors.IsNotFound(err):
+		return fmt.Errorf("configmap/%s in %q is missing, keeping configmap/%s in %q", SourceConfigMapName, operatorclient.GlobalMachineSpecifiedConfigNamespace, ConfigMapName, operatorclient.TargetNamespace)
+	case err != nil:
+		return err
+	}
+
+	caBundle := source.Data[caBundleKey]
+	expiry, err := latestExpiry([]byte(caBundle), c.now())
+	if err != nil {
+		return fmt.Errorf("not rolling out configmap/%s in %q: %v", SourceConfigMapName, operatorclient.GlobalMachineSpecifiedConfigNamespace, err)
+	}
+
+	required := source.DeepCopy()
+	required.Namespace = operatorclient.TargetNamespace
+	required.Name = ConfigMapName
+	required.ResourceVersion = ""
+	required.OwnerReferences = nil
+	if _, _, err := targetconfigcontroller.ApplyConfigMap(ctx, c.configMapClient, recorder, required); err != nil {
+		return err
+	}
+	if existing != nil && existing.Data[caBundleKey] != caBundle {
+		recorder.Eventf("AggregatorClientCARotated", "The aggregator client CA in configmap/%s in %q was rotated, its latest certificate expires at %s", ConfigMapName, operatorclient.TargetNamespace, expiry.UTC().Format(time.RFC3339))
+	}
+	return nil
+}
+
+// latestExpiry returns the latest expiry of the certificates of the bundle, which has to hold an unexpired one.
+func latestExpiry(caBundle []byte, now time.Time) (time.Time, error) {
+	if len(caBundle) == 0 {
+		return time.Time{}, fmt.Errorf("missing %s", caBundleKey)
+	}
+	certificates, err := cert.ParseCertsPEM(caBundle)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("%s is malformed: %v", caBundleKey, err)
+	}
+	var expiry time.Time
+	for _, certificate := range certificates {
+		if certificate.NotAfter.After(expiry) {
+			expiry = certificate.NotAfter
+		}
+	}
+	if !expiry.After(now) {
+		return time.Time{}, fmt.Errorf("all the certificates of %s expired, the latest at %s", caBundleKey, expiry.UTC().Format(time.RFC3339))
+	}
+	return expiry, nil
+}
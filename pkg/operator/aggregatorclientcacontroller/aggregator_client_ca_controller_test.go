@@ -0,0 +1,174 @@
+package aggregatorclientcacontroller
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+	corev1listers "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/util/cert"
+
+	operatorv1 "github.com/openshift/api/operator/v1"
+	"github.com/openshift/library-go/pkg/controller/factory"
+	"github.com/openshift/library-go/pkg/operator/events"
+	"github.com/openshift/library-go/pkg/operator/v1helpers"
+
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/operatorclient"
+)
+
+func newCA(t *testing.T, serial int64, notBefore time.Time, lifetime time.Duration) string {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(serial),
+		Subject:               pkix.Name{CommonName: "aggregator-signer"},
+		NotBefore:             notBefore,
+		NotAfter:              notBefore.Add(lifetime),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageCertSign,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(pem.EncodeToMemory(&pem.Block{Type: cert.CertificateBlockType, Bytes: der}))
+}
+
+func TestSync(t *testing.T) {
+	now := time.Date(2026, 1, 31, 0, 0, 0, 0, time.UTC)
+	current := newCA(t, 1, now.Add(-24*time.Hour), 30*24*time.Hour)
+	rotated := newCA(t, 2, now.Add(-time.Hour), 30*24*time.Hour)
+	expired := newCA(t, 3, now.Add(-60*24*time.Hour), 30*24*time.Hour)
+	configMap := func(namespace, name, caBundle string) *corev1.ConfigMap {
+		return &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: name},
+			Data:       map[string]string{caBundleKey: caBundle},
+		}
+	}
+	source := func(caBundle string) *corev1.ConfigMap {
+		return configMap(operatorclient.GlobalMachineSpecifiedConfigNamespace, SourceConfigMapName, caBundle)
+	}
+	target := func(caBundle string) *corev1.ConfigMap {
+		return configMap(operatorclient.TargetNamespace, ConfigMapName, caBundle)
+	}
+
+	tests := []struct {
+		name              string
+		existing          []*corev1.ConfigMap
+		expectedCABundle  string
+		expectedCondition operatorv1.ConditionStatus
+		expectedEvent     bool
+	}{
+		{
+			name:              "source not issued yet",
+			expectedCondition: operatorv1.ConditionFalse,
+		},
+		{
+			name:              "initial copy",
+			existing:          []*corev1.ConfigMap{source(current)},
+			expectedCABundle:  current,
+			expectedCondition: operatorv1.ConditionFalse,
+		},
+		{
+			name:              "rotation",
+			existing:          []*corev1.ConfigMap{source(current + rotated), target(current)},
+			expectedCABundle:  current + rotated,
+			expectedCondition: operatorv1.ConditionFalse,
+			expectedEvent:     true,
+		},
+		{
+			name:              "source removed",
+			existing:          []*corev1.ConfigMap{target(current)},
+			expectedCABundle:  current,
+			expectedCondition: operatorv1.ConditionTrue,
+		},
+		{
+			name:              "empty source",
+			existing:          []*corev1.ConfigMap{source(""), target(current)},
+			expectedCABundle:  current,
+			expectedCondition: operatorv1.ConditionTrue,
+		},
+		{
+			name:              "malformed source",
+			existing:          []*corev1.ConfigMap{source("not a certificate"), target(current)},
+			expectedCABundle:  current,
+			expectedCondition: operatorv1.ConditionTrue,
+		},
+		{
+			name:              "expired source",
+			existing:          []*corev1.ConfigMap{source(expired), target(current)},
+			expectedCABundle:  current,
+			expectedCondition: operatorv1.ConditionTrue,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			indexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc})
+			var objects []runtime.Object
+			for _, configMap := range test.existing {
+				if err := indexer.Add(configMap); err != nil {
+					t.Fatal(err)
+				}
+				objects = append(objects, configMap)
+			}
+			kubeClient := fake.NewSimpleClientset(objects...)
+			operatorClient := v1helpers.NewFakeStaticPodOperatorClient(
+				&operatorv1.StaticPodOperatorSpec{OperatorSpec: operatorv1.OperatorSpec{ManagementState: operatorv1.Managed}},
+				&operatorv1.StaticPodOperatorStatus{},
+				nil, nil,
+			)
+			recorder := events.NewInMemoryRecorder("test")
+
+			c := &AggregatorClientCAController{
+				operatorClient:  operatorClient,
+				configMapLister: corev1listers.NewConfigMapLister(indexer),
+				configMapClient: kubeClient.CoreV1(),
+				now:             func() time.Time { return now },
+			}
+			err := c.sync(context.TODO(), factory.NewSyncContext("test", recorder))
+			if (err != nil) != (test.expectedCondition == operatorv1.ConditionTrue) {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			copied, err := kubeClient.CoreV1().ConfigMaps(operatorclient.TargetNamespace).Get(context.TODO(), ConfigMapName, metav1.GetOptions{})
+			switch {
+			case apierrors.IsNotFound(err) && len(test.expectedCABundle) == 0:
+			case err != nil:
+				t.Fatal(err)
+			case copied.Data[caBundleKey] != test.expectedCABundle:
+				t.Errorf("expected the copy to hold %q, got %q", test.expectedCABundle, copied.Data[caBundleKey])
+			}
+
+			_, status, _, _ := operatorClient.GetStaticPodOperatorState()
+			condition := v1helpers.FindOperatorCondition(status.Conditions, "AggregatorClientCAControllerDegraded")
+			if condition == nil || condition.Status != test.expectedCondition {
+				t.Errorf("expected AggregatorClientCAControllerDegraded to be %s, got %v", test.expectedCondition, condition)
+			}
+
+			rotatedEvent := false
+			for _, event := range recorder.Events() {
+				rotatedEvent = rotatedEvent || event.Reason == "AggregatorClientCARotated"
+			}
+			if rotatedEvent != test.expectedEvent {
+				t.Errorf("expected an AggregatorClientCARotated event: %v, got %v", test.expectedEvent, rotatedEvent)
+			}
+		})
+	}
+}
@@ -0,0 +1,129 @@
+package assetoverride
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+
+	"github.com/ghodss/yaml"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	corev1listers "k8s.io/client-go/listers/core/v1"
+
+	kubecontrolplanev1 "github.com/openshift/api/kubecontrolplane/v1"
+	"github.com/openshift/library-go/pkg/operator/resource/resourceread"
+
+	"github.com/openshift/cluster-kube-controller-manager-operator/bindata"
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/operatorclient"
+)
+
+const (
+	// EnabledEnvVar set to "true" on the operator deployment reads the assets from ConfigMapName. It is a tech preview
+	// for support to roll out an emergency fix of the operand manifests without waiting for a new release payload.
+	EnabledEnvVar = "TECH_PREVIEW_ASSET_OVERRIDES"
+
+	// ConfigMapName is the configmap of the operator namespace holding the overridden assets, by the keys of
+	// Overridable.
+	ConfigMapName = "kube-controller-manager-asset-overrides"
+)
+
+// Overridable maps the keys of ConfigMapName to the embedded assets they replace.
+var Overridable = map[string]string{
+	"pod.yaml":           "assets/kube-controller-manager/pod.yaml",
+	"defaultconfig.yaml": "assets/config/defaultconfig.yaml",
+}
+
+// EnabledFromEnv tells whether EnabledEnvVar enables the overrides.
+func EnabledFromEnv() bool {
+	enabled, _ := strconv.ParseBool(os.Getenv(EnabledEnvVar))
+	return enabled
+}
+
+// Source returns the assets of the operand, from ConfigMapName when the overrides are enabled and set. A nil Source
+// only returns the embedded assets.
+type Source struct {
+	enabled bool
+	lister  corev1listers.ConfigMapLister
+}
+
+func NewSource(enabled bool, lister corev1listers.ConfigMapLister) *Source {
+	return &Source{enabled: enabled, lister: lister}
+}
+
+// Asset returns the override of the asset, or the embedded asset when it is not overridden. An override that does not
+// parse is an error, the embedded asset is not used instead to not silently revert the fix.
+func (s *Source) Asset(name string) ([]byte, error) {
+	overrides, err := s.overrides()
+	if err != nil {
+		return nil, err
+	}
+	for key, asset := range Overridable {
+		if asset != name {
+			continue
+		}
+		content, ok := overrides[key]
+		if !ok {
+			break
+		}
+		if err := validate(key, []byte(content)); err != nil {
+			return nil, fmt.Errorf("configmap/%s in %q: invalid %s: %v", ConfigMapName, operatorclient.OperatorNamespace, key, err)
+		}
+		return []byte(content), nil
+	}
+	return bindata.Asset(name)
+}
+
+// Overridden returns the sorted keys of the assets that are overridden.
+func (s *Source) Overridden() ([]string, error) {
+	overrides, err := s.overrides()
+	if err != nil {
+		return nil, err
+	}
+	var keys []string
+	for key := range overrides {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys, nil
+}
+
+// overrides returns the overridable keys set in ConfigMapName, none when the overrides are disabled.
+func (s *Source) overrides() (map[string]string, error) {
+	if s == nil || !s.enabled {
+		return nil, nil
+	}
+	configMap, err := s.lister.ConfigMaps(operatorclient.OperatorNamespace).Get(ConfigMapName)
+	if apierrors.IsNotFound(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	overrides := map[string]string{}
+	for key, value := range configMap.Data {
+		if _, ok := Overridable[key]; !ok {
+			return nil, fmt.Errorf("configmap/%s in %q: unknown key %q", ConfigMapName, operatorclient.OperatorNamespace, key)
+		}
+		overrides[key] = value
+	}
+	return overrides, nil
+}
+
+func validate(key string, content []byte) error {
+	switch key {
+	case "pod.yaml":
+		pod, err := resourceread.ReadPodV1(content)
+		if err != nil {
+			return err
+		}
+		if len(pod.Spec.Containers) == 0 {
+			return fmt.Errorf("no containers")
+		}
+	case "defaultconfig.yaml":
+		config := &kubecontrolplanev1.KubeControllerManagerConfig{}
+		if err := yaml.Unmarshal(content, config); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,127 @@
+package assetoverride
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	corev1listers "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/tools/cache"
+
+	"github.com/openshift/cluster-kube-controller-manager-operator/bindata"
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/operatorclient"
+)
+
+const overriddenPod = `apiVersion: v1
+kind: Pod
+metadata:
+  name: kube-controller-manager
+spec:
+  containers:
+  - name: kube-controller-manager
+    image: ${IMAGE}
+`
+
+func TestAsset(t *testing.T) {
+	tests := []struct {
+		name               string
+		enabled            bool
+		data               map[string]string
+		expectedPod        string
+		expectedOverridden []string
+		expectedErr        string
+	}{
+		{
+			name:    "disabled",
+			data:    map[string]string{"pod.yaml": overriddenPod},
+			enabled: false,
+		},
+		{
+			name:    "enabled without configmap",
+			enabled: true,
+		},
+		{
+			name:               "pod overridden",
+			enabled:            true,
+			data:               map[string]string{"pod.yaml": overriddenPod},
+			expectedPod:        overriddenPod,
+			expectedOverridden: []string{"pod.yaml"},
+		},
+		{
+			name:        "pod without containers",
+			enabled:     true,
+			data:        map[string]string{"pod.yaml": "apiVersion: v1\nkind: Pod\n"},
+			expectedErr: "invalid pod.yaml: no containers",
+		},
+		{
+			name:        "unknown key",
+			enabled:     true,
+			data:        map[string]string{"cm.yaml": "{}"},
+			expectedErr: `unknown key "cm.yaml"`,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			indexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc})
+			if test.data != nil {
+				if err := indexer.Add(&corev1.ConfigMap{
+					ObjectMeta: metav1.ObjectMeta{Namespace: operatorclient.OperatorNamespace, Name: ConfigMapName},
+					Data:       test.data,
+				}); err != nil {
+					t.Fatal(err)
+				}
+			}
+			source := NewSource(test.enabled, corev1listers.NewConfigMapLister(indexer))
+
+			pod, err := source.Asset("assets/kube-controller-manager/pod.yaml")
+			if len(test.expectedErr) > 0 {
+				if err == nil || !strings.Contains(err.Error(), test.expectedErr) {
+					t.Fatalf("expected the error %q, got %v", test.expectedErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			expectedPod := test.expectedPod
+			if len(expectedPod) == 0 {
+				expectedPod = string(bindata.MustAsset("assets/kube-controller-manager/pod.yaml"))
+			}
+			if string(pod) != expectedPod {
+				t.Errorf("expected the pod:\n%s\ngot:\n%s", expectedPod, pod)
+			}
+
+			config, err := source.Asset("assets/config/defaultconfig.yaml")
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(config) != string(bindata.MustAsset("assets/config/defaultconfig.yaml")) {
+				t.Errorf("expected the embedded default config, got:\n%s", config)
+			}
+
+			overridden, err := source.Overridden()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(overridden, test.expectedOverridden) {
+				t.Errorf("expected the overridden assets %v, got %v", test.expectedOverridden, overridden)
+			}
+		})
+	}
+}
+
+func TestNilSource(t *testing.T) {
+	var source *Source
+	pod, err := source.Asset("assets/kube-controller-manager/pod.yaml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(pod) != string(bindata.MustAsset("assets/kube-controller-manager/pod.yaml")) {
+		t.Errorf("expected the embedded pod, got:\n%s", pod)
+	}
+	if overridden, err := source.Overridden(); err != nil || len(overridden) != 0 {
+		t.Errorf("expected no overrides, got %v, %v", overridden, err)
+	}
+}
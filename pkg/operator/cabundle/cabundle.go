@@ -0,0 +1,178 @@
+package cabundle
+
+import (
+	"bytes"
+	"crypto/x509"
+	"fmt"
+	"sync"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	corev1listers "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/util/cert"
+
+	"github.com/openshift/library-go/pkg/crypto"
+	"github.com/openshift/library-go/pkg/operator/certrotation"
+	"github.com/openshift/library-go/pkg/operator/events"
+	"github.com/openshift/library-go/pkg/operator/resourcesynccontroller"
+)
+
+// Input is a PEM encoded CA bundle that is combined into a destination bundle. Key names the input in errors and
+// identifies it across combinations.
+type Input struct {
+	Key     string
+	Content string
+}
+
+// Registry combines CA bundles the way resourcesynccontroller.CombineCABundleConfigMaps does, but keeps the parsed
+// certificates of every input and the combined bundle of every destination. An input is only parsed again once its
+// content changes and a bundle is only rebuilt once one of its inputs changes or one of its certificates expires.
+// A change of a bundle is reported with a single CABundleChanged event.
+type Registry struct {
+	lock    sync.Mutex
+	now     func() time.Time
+	bundles map[string]*bundle
+}
+
+type bundle struct {
+	// keys are the keys of the non empty inputs, in the order they were combined
+	keys   []string
+	inputs map[string]*parsedInput
+	// expiry is the earliest expiry of the certificates of the bundle, it has to be rebuilt by then
+	expiry       time.Time
+	fingerprints map[string]bool
+	content      []byte
+}
+
+type parsedInput struct {
+	content      string
+	certificates []*x509.Certificate
+}
+
+func NewRegistry() *Registry {
+	return &Registry{
+		now:     time.Now,
+		bundles: map[string]*bundle{},
+	}
+}
+
+// CombineConfigMaps is the cached equivalent of resourcesynccontroller.CombineCABundleConfigMaps.
+func (r *Registry) CombineConfigMaps(recorder events.Recorder, destination resourcesynccontroller.ResourceLocation, lister corev1listers.ConfigMapLister, additionalAnnotations certrotation.AdditionalAnnotations, inputConfigMaps ...resourcesynccontroller.ResourceLocation) (*corev1.ConfigMap, error) {
+	inputs := []Input{}
+	for _, location := range inputConfigMaps {
+		inputConfigMap, err := lister.ConfigMaps(location.Namespace).Get(location.Name)
+		if apierrors.IsNotFound(err) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		inputs = append(inputs, Input{
+			Key:     fmt.Sprintf("configmap/%s in %q", location.Name, location.Namespace),
+			Content: inputConfigMap.Data["ca-bundle.crt"],
+		})
+	}
+
+	caBytes, err := r.Combine(recorder, destination, inputs...)
+	if err != nil {
+		return nil, err
+	}
+	return &corev1.ConfigMap{
+		ObjectMeta: certrotation.NewTLSArtifactObjectMeta(
+			destination.Name,
+			destination.Namespace,
+			additionalAnnotations,
+		),
+		Data: map[string]string{
+			"ca-bundle.crt": string(caBytes),
+		},
+	}, nil
+}
+
+// Combine returns the PEM encoded unexpired certificates of the inputs, without duplicates and in the order of the
+// inputs. Empty inputs are skipped.
+func (r *Registry) Combine(recorder events.Recorder, destination resourcesynccontroller.ResourceLocation, inputs ...Input) ([]byte, error) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	destinationKey := destination.Namespace + "/" + destination.Name
+	previous := r.bundles[destinationKey]
+	now := r.now()
+
+	changed := previous == nil
+	current := &bundle{inputs: map[string]*parsedInput{}}
+	for _, input := range inputs {
+		if len(input.Content) == 0 {
+			continue
+		}
+		current.keys = append(current.keys, input.Key)
+		if previous != nil {
+			if parsed, ok := previous.inputs[input.Key]; ok && parsed.content == input.Content {
+				current.inputs[input.Key] = parsed
+				continue
+			}
+		}
+		certificates, err := cert.ParseCertsPEM([]byte(input.Content))
+		if err != nil {
+			return nil, fmt.Errorf("%s is malformed: %v", input.Key, err)
+		}
+		current.inputs[input.Key] = &parsedInput{content: input.Content, certificates: certificates}
+		changed = true
+	}
+	if !changed && !keysEqual(previous.keys, current.keys) {
+		changed = true
+	}
+	if !changed && (previous.expiry.IsZero() || now.Before(previous.expiry)) {
+		return previous.content, nil
+	}
+
+	current.fingerprints = map[string]bool{}
+	finalCertificates := []*x509.Certificate{}
+	for _, key := range current.keys {
+		for _, certificate := range current.inputs[key].certificates {
+			if !certificate.NotAfter.After(now) || current.fingerprints[string(certificate.Raw)] {
+				continue
+			}
+			current.fingerprints[string(certificate.Raw)] = true
+			finalCertificates = append(finalCertificates, certificate)
+			if current.expiry.IsZero() || certificate.NotAfter.Before(current.expiry) {
+				current.expiry = certificate.NotAfter
+			}
+		}
+	}
+	caBytes, err := crypto.EncodeCertificates(finalCertificates...)
+	if err != nil {
+		return nil, err
+	}
+	current.content = caBytes
+	r.bundles[destinationKey] = current
+
+	if previous != nil && !bytes.Equal(previous.content, current.content) {
+		added, removed := 0, 0
+		for fingerprint := range current.fingerprints {
+			if !previous.fingerprints[fingerprint] {
+				added++
+			}
+		}
+		for fingerprint := range previous.fingerprints {
+			if !current.fingerprints[fingerprint] {
+				removed++
+			}
+		}
+		recorder.Eventf("CABundleChanged", "The CA bundle configmap/%s -n %s changed: %d certificates added, %d removed, %d in total", destination.Name, destination.Namespace, added, removed, len(finalCertificates))
+	}
+	return caBytes, nil
+}
+
+func keysEqual(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,137 @@
+package cabundle
+
+import (
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	corev1listers "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/tools/cache"
+
+	"github.com/openshift/library-go/pkg/crypto"
+	"github.com/openshift/library-go/pkg/operator/certrotation"
+	"github.com/openshift/library-go/pkg/operator/events"
+	"github.com/openshift/library-go/pkg/operator/resourcesynccontroller"
+)
+
+func newCA(t *testing.T, name string, lifetime time.Duration) []byte {
+	t.Helper()
+	config, err := crypto.MakeSelfSignedCAConfigForDuration(name, lifetime)
+	if err != nil {
+		t.Fatal(err)
+	}
+	certPEM, _, err := config.GetPEMBytes()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return certPEM
+}
+
+func TestCombineConfigMaps(t *testing.T) {
+	first := newCA(t, "first", time.Hour)
+	second := newCA(t, "second", 2*time.Hour)
+	third := newCA(t, "third", time.Hour)
+
+	indexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc})
+	for _, cm := range []*corev1.ConfigMap{
+		{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "a"},
+			Data:       map[string]string{"ca-bundle.crt": string(first) + string(second)},
+		},
+		{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "b"},
+			Data:       map[string]string{"ca-bundle.crt": string(second)},
+		},
+		{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "empty"},
+		},
+	} {
+		if err := indexer.Add(cm); err != nil {
+			t.Fatal(err)
+		}
+	}
+	lister := corev1listers.NewConfigMapLister(indexer)
+	destination := resourcesynccontroller.ResourceLocation{Namespace: "ns", Name: "combined"}
+	annotations := certrotation.AdditionalAnnotations{JiraComponent: "kube-controller-manager"}
+	inputs := []resourcesynccontroller.ResourceLocation{
+		{Namespace: "ns", Name: "a"},
+		{Namespace: "ns", Name: "missing"},
+		{Namespace: "ns", Name: "empty"},
+		{Namespace: "ns", Name: "b"},
+	}
+
+	registry := NewRegistry()
+	recorder := events.NewInMemoryRecorder("test")
+	combine := func() *corev1.ConfigMap {
+		t.Helper()
+		required, err := registry.CombineConfigMaps(recorder, destination, lister, annotations, inputs...)
+		if err != nil {
+			t.Fatal(err)
+		}
+		expected, err := resourcesynccontroller.CombineCABundleConfigMaps(destination, lister, annotations, inputs...)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if required.Data["ca-bundle.crt"] != expected.Data["ca-bundle.crt"] {
+			t.Fatalf("expected the bundle of CombineCABundleConfigMaps, got:\n%s", required.Data["ca-bundle.crt"])
+		}
+		return required
+	}
+
+	required := combine()
+	if expected := string(first) + string(second); required.Data["ca-bundle.crt"] != expected {
+		t.Errorf("expected the certificates once and in order, got:\n%s", required.Data["ca-bundle.crt"])
+	}
+	parsed := registry.bundles["ns/combined"].inputs[`configmap/a in "ns"`]
+
+	combine()
+	if registry.bundles["ns/combined"].inputs[`configmap/a in "ns"`] != parsed {
+		t.Errorf("expected an unchanged input not to be parsed again")
+	}
+	if events := recorder.Events(); len(events) != 0 {
+		t.Errorf("expected no events without a change, got %v", events)
+	}
+
+	if err := indexer.Update(&corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "b"},
+		Data:       map[string]string{"ca-bundle.crt": string(third)},
+	}); err != nil {
+		t.Fatal(err)
+	}
+	required = combine()
+	if expected := string(first) + string(second) + string(third); required.Data["ca-bundle.crt"] != expected {
+		t.Errorf("expected the new certificate to be appended, got:\n%s", required.Data["ca-bundle.crt"])
+	}
+	if registry.bundles["ns/combined"].inputs[`configmap/a in "ns"`] != parsed {
+		t.Errorf("expected an unchanged input not to be parsed again")
+	}
+	if events := recorder.Events(); len(events) != 1 || events[0].Reason != "CABundleChanged" {
+		t.Errorf("expected a single CABundleChanged event, got %v", events)
+	}
+
+	// the certificates of the first input have expired by now
+	registry.now = func() time.Time { return time.Now().Add(90 * time.Minute) }
+	content, err := registry.Combine(recorder, destination,
+		Input{Key: `configmap/a in "ns"`, Content: string(first) + string(second)},
+		Input{Key: `configmap/b in "ns"`, Content: string(third)},
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(content) != string(second) {
+		t.Errorf("expected the expired certificates to be dropped, got:\n%s", content)
+	}
+	if events := recorder.Events(); len(events) != 2 {
+		t.Errorf("expected a second CABundleChanged event, got %v", events)
+	}
+}
+
+func TestCombineMalformed(t *testing.T) {
+	_, err := NewRegistry().Combine(events.NewInMemoryRecorder("test"), resourcesynccontroller.ResourceLocation{Namespace: "ns", Name: "combined"},
+		Input{Key: `configmap/a in "ns"`, Content: "not a certificate"},
+	)
+	if err == nil || err.Error() != `configmap/a in "ns" is malformed: data does not contain any valid RSA or ECDSA certificates` {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
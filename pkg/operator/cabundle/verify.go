@@ -0,0 +1,60 @@
+package cabundle
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"time"
+)
+
+// serverVerificationTimeout bounds the TLS handshake with the server whose certificate is verified.
+const serverVerificationTimeout = 10 * time.Second
+
+// UnreachableError is returned by VerifyServerCertificate when the server could not be reached, which says nothing
+// about the CA bundle.
+type UnreachableError struct {
+	Server string
+	Err    error
+}
+
+func (e *UnreachableError) Error() string {
+	return fmt.Sprintf("could not reach %s: %v", e.Server, e.Err)
+}
+
+func (e *UnreachableError) Unwrap() error {
+	return e.Err
+}
+
+// VerifyServerCertificate checks that the server presents a certificate the CA bundle verifies. A server that cannot
+// be reached yields an UnreachableError.
+func VerifyServerCertificate(server string, caBundle []byte) error {
+	serverURL, err := url.Parse(server)
+	if err != nil {
+		return fmt.Errorf("invalid server %q: %v", server, err)
+	}
+	roots := x509.NewCertPool()
+	if !roots.AppendCertsFromPEM(caBundle) {
+		return fmt.Errorf("the CA bundle holds no certificate")
+	}
+	address := serverURL.Host
+	if len(serverURL.Port()) == 0 {
+		address = net.JoinHostPort(serverURL.Hostname(), "443")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), serverVerificationTimeout)
+	defer cancel()
+	dialer := &tls.Dialer{Config: &tls.Config{RootCAs: roots, ServerName: serverURL.Hostname()}}
+	conn, err := dialer.DialContext(ctx, "tcp", address)
+	var verificationErr *tls.CertificateVerificationError
+	switch {
+	case errors.As(err, &verificationErr):
+		return fmt.Errorf("%s presents a certificate the CA bundle does not verify: %v", server, verificationErr.Err)
+	case err != nil:
+		return &UnreachableError{Server: server, Err: err}
+	}
+	return conn.Close()
+}
@@ -0,0 +1,32 @@
+package cabundle
+
+import (
+	"encoding/pem"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestVerifyServerCertificate(t *testing.T) {
+	server := httptest.NewTLSServer(http.NotFoundHandler())
+	defer server.Close()
+	serverCA := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw})
+
+	if err := VerifyServerCertificate(server.URL, serverCA); err != nil {
+		t.Errorf("expected the server certificate to be verified, got %v", err)
+	}
+
+	err := VerifyServerCertificate(server.URL, newCA(t, "other", time.Hour))
+	var unreachable *UnreachableError
+	if err == nil || errors.As(err, &unreachable) {
+		t.Errorf("expected a verification error, got %v", err)
+	}
+
+	closed := httptest.NewTLSServer(http.NotFoundHandler())
+	closed.Close()
+	if err := VerifyServerCertificate(closed.URL, serverCA); !errors.As(err, &unreachable) {
+		t.Errorf("expected an unreachable error, got %v", err)
+	}
+}
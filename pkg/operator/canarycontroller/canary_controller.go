@@ -0,0 +1,309 @@
+package canarycontroller
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	coordinationv1client "k8s.io/client-go/kubernetes/typed/coordination/v1"
+	corev1client "k8s.io/client-go/kubernetes/typed/core/v1"
+	corev1listers "k8s.io/client-go/listers/core/v1"
+
+	operatorv1 "github.com/openshift/api/operator/v1"
+	"github.com/openshift/library-go/pkg/controller/factory"
+	"github.com/openshift/library-go/pkg/operator/events"
+	"github.com/openshift/library-go/pkg/operator/management"
+	"github.com/openshift/library-go/pkg/operator/resource/resourceapply"
+	"github.com/openshift/library-go/pkg/operator/v1helpers"
+
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/operatorclient"
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/tuning"
+)
+
+const (
+	canaryRolloutProgressingCondition = "CanaryRolloutProgressing"
+	canaryRolloutDegradedCondition    = "CanaryRolloutDegraded"
+
+	// leaderLeaseNamespace and leaderLeaseName are the leader election lease of the kube-controller-manager, its holder
+	// identity starts with the name of the node.
+	leaderLeaseNamespace = "kube-system"
+	leaderLeaseName      = "kube-controller-manager"
+)
+
+// CanaryController holds every new revision at the first node it is installed on, the canary, until the
+// kube-controller-manager there stayed ready for the validation period and the leader election works with it. The
+// installer controller moves on to the other nodes once the canary runs the revision, their installer pods wait for the
+// gate in their rollout barrier. A revision failing to install or to validate on the canary within the timeout aborts
+// the rollout: the installers of the other nodes fail until a new revision is available. The validation is reported in
+// the CanaryRolloutProgressing and CanaryRolloutDegraded conditions.
+type CanaryController struct {
+	operatorClient  v1helpers.StaticPodOperatorClient
+	configMapLister corev1listers.ConfigMapLister
+	podLister       corev1listers.PodLister
+	configMapClient corev1client.ConfigMapsGetter
+	leaseClient     coordinationv1client.LeasesGetter
+	now             func() time.Time
+}
+
+func NewCanaryController(
+	operatorClient v1helpers.StaticPodOperatorClient,
+	kubeInformersForNamespaces v1helpers.KubeInformersForNamespaces,
+	kubeClient kubernetes.Interface,
+	eventRecorder events.Recorder,
+) factory.Controller {
+	c := &CanaryController{
+		operatorClient:  operatorClient,
+		configMapLister: kubeInformersForNamespaces.ConfigMapLister(),
+		podLister:       kubeInformersForNamespaces.InformersFor(operatorclient.TargetNamespace).Core().V1().Pods().Lister(),
+		configMapClient: kubeClient.CoreV1(),
+		leaseClient:     kubeClient.CoordinationV1(),
+		now:             time.Now,
+	}
+
+	// the leader lease is renewed every few seconds, it is read on the resync instead of being watched
+	return factory.New().WithFilteredEventsInformers(
+		factory.NamesFilter(tuning.ConfigMapName),
+		kubeInformersForNamespaces.InformersFor(operatorclient.OperatorNamespace).Core().V1().ConfigMaps().Informer(),
+	).WithFilteredEventsInformers(
+		factory.NamesFilter(GateConfigMapName),
+		kubeInformersForNamespaces.InformersFor(operatorclient.TargetNamespace).Core().V1().ConfigMaps().Informer(),
+	).WithInformers(
+		operatorClient.Informer(),
+		kubeInformersForNamespaces.InformersFor(operatorclient.TargetNamespace).Core().V1().Pods().Informer(),
+	).ResyncEvery(30*time.Second).WithSync(c.sync).ToController("CanaryController", eventRecorder)
+}
+
+func (c *CanaryController) sync(ctx context.Context, syncCtx factory.SyncContext) error {
+	operatorSpec, operatorStatus, _, err := c.operatorClient.GetStaticPodOperatorState()
+	if err != nil {
+		return err
+	}
+	if !management.IsOperatorManaged(operatorSpec.ManagementState) {
+		return nil
+	}
+	tuningConfig, err := tuning.Get(c.configMapLister)
+	if err != nil {
+		return err
+	}
+
+	gate, err := c.currentGate()
+	if err != nil {
+		return err
+	}
+	if !tuningConfig.CanaryRollout.Enabled {
+		if gate != nil {
+			err := c.configMapClient.ConfigMaps(operatorclient.TargetNamespace).Delete(ctx, GateConfigMapName, metav1.DeleteOptions{})
+			if err != nil && !apierrors.IsNotFound(err) {
+				return err
+			}
+			syncCtx.Recorder().Eventf("CanaryRolloutDisabled", "Revisions roll out to all nodes without a canary")
+		}
+		return c.updateConditions(ctx, false, nil)
+	}
+
+	now := c.now()
+	next := nextGate(gate, operatorStatus, now)
+	if next != nil && next.Phase == ValidatingPhase {
+		if next, err = c.validate(ctx, next, tuningConfig.CanaryRollout, operatorStatus, now); err != nil {
+			return err
+		}
+	}
+	if next != nil && (gate == nil || !reflect.DeepEqual(gate.data(), next.data())) {
+		if _, _, err := resourceapply.ApplyConfigMap(ctx, c.configMapClient, syncCtx.Recorder(), next.configMap()); err != nil {
+			return err
+		}
+		recordTransition(syncCtx.Recorder(), gate, next)
+	}
+	return c.updateConditions(ctx, true, next)
+}
+
+// currentGate returns the gate stored in the GateConfigMapName configmap, nil when there is none.
+func (c *CanaryController) currentGate() (*Gate, error) {
+	configMap, err := c.configMapLister.ConfigMaps(operatorclient.TargetNamespace).Get(GateConfigMapName)
+	if apierrors.IsNotFound(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	gate, err := GateFromConfigMap(configMap)
+	if err != nil {
+		// a broken gate is replaced by the one of the latest revision
+		return nil, nil
+	}
+	return gate, nil
+}
+
+// nextGate returns the gate of the latest available revision, which holds the first node the installer controller
+// targets with it. The current gate is kept while the installer controller has not started the revision on a node yet,
+// the revision is not held when no node ran a revision before or all nodes already run it.
+func nextGate(gate *Gate, status *operatorv1.StaticPodOperatorStatus, now time.Time) *Gate {
+	latest := status.LatestAvailableRevision
+	if latest == 0 || (gate != nil && gate.Revision == latest) {
+		return gate
+	}
+
+	bootstrapped, rolledOut := false, true
+	for _, nodeStatus := range status.NodeStatuses {
+		bootstrapped = bootstrapped || nodeStatus.CurrentRevision > 0
+		rolledOut = rolledOut && nodeStatus.CurrentRevision == latest
+	}
+	switch {
+	case !bootstrapped:
+		return &Gate{Revision: latest, Phase: PassedPhase, Message: "The initial revision is not held at a canary node"}
+	case rolledOut:
+		return &Gate{Revision: latest, Phase: PassedPhase, Message: fmt.Sprintf("Revision %d runs on all nodes", latest)}
+	}
+
+	for _, nodeStatus := range status.NodeStatuses {
+		if nodeStatus.TargetRevision == latest || nodeStatus.CurrentRevision == latest || nodeStatus.LastFailedRevision == latest {
+			return &Gate{Revision: latest, Node: nodeStatus.NodeName, Phase: ValidatingPhase, Started: now, Message: "Installing the revision"}
+		}
+	}
+	return gate
+}
+
+// validate returns the gate with the result of the validation of the canary node so far.
+func (c *CanaryController) validate(ctx context.Context, gate *Gate, config tuning.CanaryRolloutConfig, status *operatorv1.StaticPodOperatorStatus, now time.Time) (*Gate, error) {
+	next := *gate
+
+	var nodeStatus *operatorv1.NodeStatus
+	for i := range status.NodeStatuses {
+		if status.NodeStatuses[i].NodeName == gate.Node {
+			nodeStatus = &status.NodeStatuses[i]
+		}
+	}
+	if nodeStatus == nil {
+		next.Phase = FailedPhase
+		next.Message = fmt.Sprintf("Canary node %s is no longer a control plane node", gate.Node)
+		return &next, nil
+	}
+	if nodeStatus.LastFailedRevision == gate.Revision {
+		next.Phase = FailedPhase
+		next.Message = fmt.Sprintf("Revision %d failed on canary node %s: %s", gate.Revision, gate.Node, strings.Join(nodeStatus.LastFailedRevisionErrors, "; "))
+		return &next, nil
+	}
+
+	reason, err := c.unhealthyReason(ctx, gate, nodeStatus, config.ValidationPeriodOrDefault(), now)
+	if err != nil {
+		return nil, err
+	}
+	switch {
+	case len(reason) == 0:
+		next.Phase = PassedPhase
+		next.Message = fmt.Sprintf("Revision %d passed the validation on canary node %s", gate.Revision, gate.Node)
+	case now.Sub(gate.Started) > config.TimeoutOrDefault():
+		next.Phase = FailedPhase
+		next.Message = fmt.Sprintf("Revision %d did not pass the validation on canary node %s within %s: %s", gate.Revision, gate.Node, config.TimeoutOrDefault(), reason)
+	default:
+		next.Message = reason
+	}
+	return &next, nil
+}
+
+// unhealthyReason returns why the canary has not passed the validation yet, empty when it passed. The
+// kube-controller-manager of the canary has to run the revision and be ready for the validation period, and the leader
+// lease has to be held by it or renewed by the leader on another node since it became ready. Only one
+// kube-controller-manager leads at a time, the lease of a healthy leader is not taken over by the canary.
+func (c *CanaryController) unhealthyReason(ctx context.Context, gate *Gate, nodeStatus *operatorv1.NodeStatus, validationPeriod time.Duration, now time.Time) (string, error) {
+	if nodeStatus.CurrentRevision != gate.Revision {
+		return "Installing the revision", nil
+	}
+	podName := "kube-controller-manager-" + gate.Node
+	pod, err := c.podLister.Pods(operatorclient.TargetNamespace).Get(podName)
+	if apierrors.IsNotFound(err) {
+		return fmt.Sprintf("pod/%s is not running", podName), nil
+	}
+	if err != nil {
+		return "", err
+	}
+	if revision := pod.Labels["revision"]; revision != strconv.Itoa(int(gate.Revision)) {
+		return fmt.Sprintf("pod/%s runs revision %s", podName, revision), nil
+	}
+	readySince, ready := readySince(pod)
+	if !ready {
+		return fmt.Sprintf("pod/%s is not ready", podName), nil
+	}
+	if readyFor := now.Sub(readySince); readyFor < validationPeriod {
+		return fmt.Sprintf("pod/%s is ready for %s of %s", podName, readyFor.Truncate(time.Second), validationPeriod), nil
+	}
+
+	lease, err := c.leaseClient.Leases(leaderLeaseNamespace).Get(ctx, leaderLeaseName, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		return fmt.Sprintf("lease/%s -n %s is not held", leaderLeaseName, leaderLeaseNamespace), nil
+	}
+	if err != nil {
+		return "", err
+	}
+	if lease.Spec.HolderIdentity == nil || len(*lease.Spec.HolderIdentity) == 0 {
+		return fmt.Sprintf("lease/%s -n %s is not held", leaderLeaseName, leaderLeaseNamespace), nil
+	}
+	if strings.HasPrefix(*lease.Spec.HolderIdentity, gate.Node+"_") {
+		return "", nil
+	}
+	if lease.Spec.RenewTime == nil || lease.Spec.RenewTime.Time.Before(readySince) {
+		return fmt.Sprintf("lease/%s -n %s was not renewed since pod/%s became ready", leaderLeaseName, leaderLeaseNamespace, podName), nil
+	}
+	return "", nil
+}
+
+// readySince returns when the pod became ready, and whether it is.
+func readySince(pod *corev1.Pod) (time.Time, bool) {
+	for _, condition := range pod.Status.Conditions {
+		if condition.Type == corev1.PodReady {
+			return condition.LastTransitionTime.Time, condition.Status == corev1.ConditionTrue
+		}
+	}
+	return time.Time{}, false
+}
+
+func recordTransition(recorder events.Recorder, previous, next *Gate) {
+	if previous != nil && previous.Revision == next.Revision && previous.Phase == next.Phase {
+		return
+	}
+	switch next.Phase {
+	case ValidatingPhase:
+		recorder.Eventf("CanaryRolloutStarted", "Revision %d is held at canary node %s until it passes the validation there", next.Revision, next.Node)
+	case PassedPhase:
+		recorder.Eventf("CanaryRolloutPassed", "%s", next.Message)
+	case FailedPhase:
+		recorder.Warningf("CanaryRolloutAborted", "%s, the other nodes keep their revision", next.Message)
+	}
+}
+
+func (c *CanaryController) updateConditions(ctx context.Context, enabled bool, gate *Gate) error {
+	progressing := operatorv1.OperatorCondition{Type: canaryRolloutProgressingCondition, Status: operatorv1.ConditionFalse}
+	degraded := operatorv1.OperatorCondition{Type: canaryRolloutDegradedCondition, Status: operatorv1.ConditionFalse, Reason: "AsExpected"}
+	switch {
+	case !enabled:
+		progressing.Reason = "Disabled"
+	case gate == nil:
+		progressing.Reason = "NoRevision"
+	case gate.Phase == ValidatingPhase:
+		progressing.Status = operatorv1.ConditionTrue
+		progressing.Reason = "Validating"
+		progressing.Message = fmt.Sprintf("Revision %d is held at canary node %s since %s: %s", gate.Revision, gate.Node, gate.Started.UTC().Format(time.RFC3339), gate.Message)
+	case gate.Phase == PassedPhase:
+		progressing.Reason = "Passed"
+		progressing.Message = gate.Message
+	case gate.Phase == FailedPhase:
+		progressing.Reason = "Aborted"
+		progressing.Message = gate.Message
+		degraded.Status = operatorv1.ConditionTrue
+		degraded.Reason = "CanaryFailed"
+		degraded.Message = fmt.Sprintf("The rollout of revision %d was aborted, the other nodes keep their revision until a new one is available: %s", gate.Revision, gate.Message)
+	}
+
+	_, _, err := v1helpers.UpdateStaticPodStatus(ctx, c.operatorClient,
+		v1helpers.UpdateStaticPodConditionFn(progressing),
+		v1helpers.UpdateStaticPodConditionFn(degraded),
+	)
+	return err
+}
@@ -0,0 +1,236 @@
+package canarycontroller
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	coordinationv1 "k8s.io/api/coordination/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+	corev1listers "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/utils/ptr"
+
+	operatorv1 "github.com/openshift/api/operator/v1"
+	"github.com/openshift/library-go/pkg/controller/factory"
+	"github.com/openshift/library-go/pkg/operator/events"
+	"github.com/openshift/library-go/pkg/operator/v1helpers"
+
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/operatorclient"
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/tuning"
+)
+
+func TestSync(t *testing.T) {
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	enabled := "canaryRollout:\n  enabled: true"
+	validating := &Gate{Revision: 4, Node: "master-0", Phase: ValidatingPhase, Started: now.Add(-5 * time.Minute), Message: "Installing the revision"}
+	canaryPod := func(revision string, readySince time.Time) *corev1.Pod {
+		return &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Namespace: operatorclient.TargetNamespace, Name: "kube-controller-manager-master-0", Labels: map[string]string{"revision": revision}},
+			Status: corev1.PodStatus{Conditions: []corev1.PodCondition{{
+				Type:               corev1.PodReady,
+				Status:             corev1.ConditionTrue,
+				LastTransitionTime: metav1.NewTime(readySince),
+			}}},
+		}
+	}
+	leaderLease := func(holder string, renewed time.Time) *coordinationv1.Lease {
+		return &coordinationv1.Lease{
+			ObjectMeta: metav1.ObjectMeta{Namespace: leaderLeaseNamespace, Name: leaderLeaseName},
+			Spec: coordinationv1.LeaseSpec{
+				HolderIdentity: ptr.To(holder),
+				RenewTime:      &metav1.MicroTime{Time: renewed},
+			},
+		}
+	}
+
+	tests := []struct {
+		name                string
+		tuningConfig        string
+		gate                *Gate
+		nodeStatuses        []operatorv1.NodeStatus
+		pod                 *corev1.Pod
+		lease               *coordinationv1.Lease
+		expectedPhase       Phase
+		expectedNode        string
+		expectedProgressing operatorv1.ConditionStatus
+		expectedDegraded    operatorv1.ConditionStatus
+	}{
+		{
+			name:                "disabled removes the gate",
+			gate:                validating,
+			nodeStatuses:        []operatorv1.NodeStatus{{NodeName: "master-0", CurrentRevision: 3, TargetRevision: 4}, {NodeName: "master-1", CurrentRevision: 3}},
+			expectedProgressing: operatorv1.ConditionFalse,
+			expectedDegraded:    operatorv1.ConditionFalse,
+		},
+		{
+			name:                "first node targeted is the canary",
+			tuningConfig:        enabled,
+			nodeStatuses:        []operatorv1.NodeStatus{{NodeName: "master-1", CurrentRevision: 3}, {NodeName: "master-0", CurrentRevision: 3, TargetRevision: 4}},
+			expectedPhase:       ValidatingPhase,
+			expectedNode:        "master-0",
+			expectedProgressing: operatorv1.ConditionTrue,
+			expectedDegraded:    operatorv1.ConditionFalse,
+		},
+		{
+			name:                "initial rollout is not held",
+			tuningConfig:        enabled,
+			nodeStatuses:        []operatorv1.NodeStatus{{NodeName: "master-0", TargetRevision: 4}, {NodeName: "master-1"}},
+			expectedPhase:       PassedPhase,
+			expectedProgressing: operatorv1.ConditionFalse,
+			expectedDegraded:    operatorv1.ConditionFalse,
+		},
+		{
+			name:                "canary not ready for the validation period",
+			tuningConfig:        enabled,
+			gate:                validating,
+			nodeStatuses:        []operatorv1.NodeStatus{{NodeName: "master-0", CurrentRevision: 4}, {NodeName: "master-1", CurrentRevision: 3}},
+			pod:                 canaryPod("4", now.Add(-time.Minute)),
+			lease:               leaderLease("master-1_uid", now),
+			expectedPhase:       ValidatingPhase,
+			expectedNode:        "master-0",
+			expectedProgressing: operatorv1.ConditionTrue,
+			expectedDegraded:    operatorv1.ConditionFalse,
+		},
+		{
+			name:                "leader elsewhere renews the lease",
+			tuningConfig:        enabled,
+			gate:                validating,
+			nodeStatuses:        []operatorv1.NodeStatus{{NodeName: "master-0", CurrentRevision: 4}, {NodeName: "master-1", CurrentRevision: 3}},
+			pod:                 canaryPod("4", now.Add(-3*time.Minute)),
+			lease:               leaderLease("master-1_uid", now),
+			expectedPhase:       PassedPhase,
+			expectedNode:        "master-0",
+			expectedProgressing: operatorv1.ConditionFalse,
+			expectedDegraded:    operatorv1.ConditionFalse,
+		},
+		{
+			name:                "canary took the leader lease",
+			tuningConfig:        enabled,
+			gate:                validating,
+			nodeStatuses:        []operatorv1.NodeStatus{{NodeName: "master-0", CurrentRevision: 4}, {NodeName: "master-1", CurrentRevision: 3}},
+			pod:                 canaryPod("4", now.Add(-3*time.Minute)),
+			lease:               leaderLease("master-0_uid", now.Add(-4*time.Minute)),
+			expectedPhase:       PassedPhase,
+			expectedNode:        "master-0",
+			expectedProgressing: operatorv1.ConditionFalse,
+			expectedDegraded:    operatorv1.ConditionFalse,
+		},
+		{
+			name:                "stale leader lease",
+			tuningConfig:        enabled,
+			gate:                validating,
+			nodeStatuses:        []operatorv1.NodeStatus{{NodeName: "master-0", CurrentRevision: 4}, {NodeName: "master-1", CurrentRevision: 3}},
+			pod:                 canaryPod("4", now.Add(-3*time.Minute)),
+			lease:               leaderLease("master-1_uid", now.Add(-4*time.Minute)),
+			expectedPhase:       ValidatingPhase,
+			expectedNode:        "master-0",
+			expectedProgressing: operatorv1.ConditionTrue,
+			expectedDegraded:    operatorv1.ConditionFalse,
+		},
+		{
+			name:         "failed on the canary",
+			tuningConfig: enabled,
+			gate:         validating,
+			nodeStatuses: []operatorv1.NodeStatus{
+				{NodeName: "master-0", CurrentRevision: 3, LastFailedRevision: 4, LastFailedRevisionErrors: []string{"static pod failed to start"}},
+				{NodeName: "master-1", CurrentRevision: 3},
+			},
+			expectedPhase:       FailedPhase,
+			expectedNode:        "master-0",
+			expectedProgressing: operatorv1.ConditionFalse,
+			expectedDegraded:    operatorv1.ConditionTrue,
+		},
+		{
+			name:                "timed out",
+			tuningConfig:        enabled,
+			gate:                &Gate{Revision: 4, Node: "master-0", Phase: ValidatingPhase, Started: now.Add(-time.Hour)},
+			nodeStatuses:        []operatorv1.NodeStatus{{NodeName: "master-0", CurrentRevision: 4}, {NodeName: "master-1", CurrentRevision: 3}},
+			pod:                 canaryPod("3", now.Add(-time.Hour)),
+			expectedPhase:       FailedPhase,
+			expectedNode:        "master-0",
+			expectedProgressing: operatorv1.ConditionFalse,
+			expectedDegraded:    operatorv1.ConditionTrue,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			configMapIndexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{})
+			podIndexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{})
+			var objects []runtime.Object
+			if len(test.tuningConfig) > 0 {
+				if err := configMapIndexer.Add(&corev1.ConfigMap{
+					ObjectMeta: metav1.ObjectMeta{Namespace: operatorclient.OperatorNamespace, Name: tuning.ConfigMapName},
+					Data:       map[string]string{tuning.ConfigKey: test.tuningConfig},
+				}); err != nil {
+					t.Fatal(err)
+				}
+			}
+			if test.gate != nil {
+				if err := configMapIndexer.Add(test.gate.configMap()); err != nil {
+					t.Fatal(err)
+				}
+				objects = append(objects, test.gate.configMap())
+			}
+			if test.pod != nil {
+				if err := podIndexer.Add(test.pod); err != nil {
+					t.Fatal(err)
+				}
+			}
+			if test.lease != nil {
+				objects = append(objects, test.lease)
+			}
+			kubeClient := fake.NewSimpleClientset(objects...)
+
+			operatorClient := v1helpers.NewFakeStaticPodOperatorClient(
+				&operatorv1.StaticPodOperatorSpec{OperatorSpec: operatorv1.OperatorSpec{ManagementState: operatorv1.Managed}},
+				&operatorv1.StaticPodOperatorStatus{LatestAvailableRevision: 4, NodeStatuses: test.nodeStatuses},
+				nil,
+				nil,
+			)
+			c := &CanaryController{
+				operatorClient:  operatorClient,
+				configMapLister: corev1listers.NewConfigMapLister(configMapIndexer),
+				podLister:       corev1listers.NewPodLister(podIndexer),
+				configMapClient: kubeClient.CoreV1(),
+				leaseClient:     kubeClient.CoordinationV1(),
+				now:             func() time.Time { return now },
+			}
+			if err := c.sync(context.TODO(), factory.NewSyncContext("test", events.NewInMemoryRecorder("test"))); err != nil {
+				t.Fatal(err)
+			}
+
+			configMap, err := kubeClient.CoreV1().ConfigMaps(operatorclient.TargetNamespace).Get(context.TODO(), GateConfigMapName, metav1.GetOptions{})
+			switch {
+			case len(test.expectedPhase) == 0 && !apierrors.IsNotFound(err):
+				t.Errorf("expected no gate, got %v", err)
+			case len(test.expectedPhase) > 0 && err != nil:
+				t.Fatal(err)
+			case len(test.expectedPhase) > 0:
+				gate, err := GateFromConfigMap(configMap)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if gate.Phase != test.expectedPhase || gate.Node != test.expectedNode {
+					t.Errorf("expected phase %s on node %q, got %s on node %q: %s", test.expectedPhase, test.expectedNode, gate.Phase, gate.Node, gate.Message)
+				}
+			}
+
+			_, status, _, err := operatorClient.GetStaticPodOperatorState()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if condition := v1helpers.FindOperatorCondition(status.Conditions, canaryRolloutProgressingCondition); condition == nil || condition.Status != test.expectedProgressing {
+				t.Errorf("expected %s %s, got %v", canaryRolloutProgressingCondition, test.expectedProgressing, condition)
+			}
+			if condition := v1helpers.FindOperatorCondition(status.Conditions, canaryRolloutDegradedCondition); condition == nil || condition.Status != test.expectedDegraded {
+				t.Errorf("expected %s %s, got %v", canaryRolloutDegradedCondition, test.expectedDegraded, condition)
+			}
+		})
+	}
+}
@@ -0,0 +1,93 @@
+package canarycontroller
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/operatorclient"
+)
+
+// GateConfigMapName is the configmap of the target namespace holding the gate of the revision rolling out. The rollout
+// barrier of the installer pods reads it, it only exists while the canary rollout is enabled.
+const GateConfigMapName = "canary-rollout"
+
+// Phase is the state of the validation of a revision on its canary node.
+type Phase string
+
+const (
+	// ValidatingPhase holds the other nodes until the revision proved healthy on the canary node.
+	ValidatingPhase Phase = "Validating"
+	// PassedPhase lets the other nodes install the revision.
+	PassedPhase Phase = "Passed"
+	// FailedPhase aborts the rollout, the installers of the other nodes fail until a new revision is available.
+	FailedPhase Phase = "Failed"
+)
+
+const (
+	revisionKey = "revision"
+	nodeKey     = "node"
+	phaseKey    = "phase"
+	startedKey  = "started"
+	messageKey  = "message"
+)
+
+// Gate is the canary of a revision and the state of its validation.
+type Gate struct {
+	Revision int32
+	// Node is the canary node, empty when the revision was not held, e.g. on the initial rollout.
+	Node    string
+	Phase   Phase
+	Started time.Time
+	Message string
+}
+
+// GateFromConfigMap parses the gate stored in the GateConfigMapName configmap.
+func GateFromConfigMap(configMap *corev1.ConfigMap) (*Gate, error) {
+	revision, err := strconv.ParseInt(configMap.Data[revisionKey], 10, 32)
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s in configmap/%s -n %s: %v", revisionKey, configMap.Name, configMap.Namespace, err)
+	}
+	gate := &Gate{
+		Revision: int32(revision),
+		Node:     configMap.Data[nodeKey],
+		Phase:    Phase(configMap.Data[phaseKey]),
+		Message:  configMap.Data[messageKey],
+	}
+	switch gate.Phase {
+	case ValidatingPhase, PassedPhase, FailedPhase:
+	default:
+		return nil, fmt.Errorf("unknown %s %q in configmap/%s -n %s", phaseKey, gate.Phase, configMap.Name, configMap.Namespace)
+	}
+	if started := configMap.Data[startedKey]; len(started) > 0 {
+		if gate.Started, err = time.Parse(time.RFC3339, started); err != nil {
+			return nil, fmt.Errorf("invalid %s in configmap/%s -n %s: %v", startedKey, configMap.Name, configMap.Namespace, err)
+		}
+	}
+	return gate, nil
+}
+
+func (g *Gate) data() map[string]string {
+	data := map[string]string{
+		revisionKey: strconv.Itoa(int(g.Revision)),
+		phaseKey:    string(g.Phase),
+		messageKey:  g.Message,
+	}
+	if len(g.Node) > 0 {
+		data[nodeKey] = g.Node
+	}
+	if !g.Started.IsZero() {
+		data[startedKey] = g.Started.UTC().Format(time.RFC3339)
+	}
+	return data
+}
+
+func (g *Gate) configMap() *corev1.ConfigMap {
+	return &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Namespace: operatorclient.TargetNamespace, Name: GateConfigMapName},
+		Data:       g.data(),
+	}
+}
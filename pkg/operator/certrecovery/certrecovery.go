@@ -0,0 +1,106 @@
+package certrecovery
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	corev1listers "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/util/cert"
+
+	operatorv1 "github.com/openshift/api/operator/v1"
+	"github.com/openshift/library-go/pkg/operator/v1helpers"
+
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/operatorclient"
+)
+
+const (
+	// ConditionType is reported while the operator is in the cert recovery mode.
+	ConditionType = "CertRecoveryMode"
+
+	// TrustRecheckInterval replaces the interval a rotated csr-signer is checked for the trust of the kube-apiserver
+	// at. The kube-apiserver picks up its new client CA quickly while it recovers.
+	TrustRecheckInterval = 10 * time.Second
+	// SATokenSignerPropagation replaces the time a new service account token signer is published to the
+	// kube-apiserver before it is promoted.
+	SATokenSignerPropagation = 30 * time.Second
+)
+
+// expiringSecrets are the certificates an expiry of which the cluster has to recover from: the csr-signer the
+// kubelet and client certificates are issued with, and the client certificate of the kube-controller-manager.
+var expiringSecrets = []struct{ namespace, name string }{
+	{operatorclient.OperatorNamespace, "csr-signer"},
+	{operatorclient.TargetNamespace, "csr-signer"},
+	{operatorclient.TargetNamespace, "kube-controller-manager-client-cert-key"},
+}
+
+// Detector tells whether the operator is in the cert recovery mode, in which the csr-signer and the service account
+// trust are regenerated before anything else and with shorter propagation waits. The mode is on while one of the
+// expiringSecrets is expired, e.g. after a cluster was shut down for longer than the validity of its certificates,
+// or when the operator is started with --cert-recovery.
+type Detector struct {
+	forced       bool
+	secretLister corev1listers.SecretLister
+}
+
+func NewDetector(forced bool, kubeInformersForNamespaces v1helpers.KubeInformersForNamespaces) *Detector {
+	return &Detector{forced: forced, secretLister: kubeInformersForNamespaces.SecretLister()}
+}
+
+// Active returns whether the cert recovery mode is on and why. A nil detector is never active.
+func (d *Detector) Active(now time.Time) (bool, string) {
+	if d == nil {
+		return false, ""
+	}
+	if d.forced {
+		return true, "The operator was started with --cert-recovery"
+	}
+
+	var expired []string
+	for _, location := range expiringSecrets {
+		secret, err := d.secretLister.Secrets(location.namespace).Get(location.name)
+		if apierrors.IsNotFound(err) {
+			continue
+		}
+		if err != nil {
+			// the detection is best effort, the other syncs report the lister errors
+			continue
+		}
+		if notAfter, ok := expiry(secret); ok && now.After(notAfter) {
+			expired = append(expired, fmt.Sprintf("secret/%s -n %s expired at %s", location.name, location.namespace, notAfter.UTC().Format(time.RFC3339)))
+		}
+	}
+	if len(expired) == 0 {
+		return false, ""
+	}
+	return true, strings.Join(expired, ", ")
+}
+
+// Condition reports the cert recovery mode.
+func (d *Detector) Condition(now time.Time) operatorv1.OperatorCondition {
+	active, reason := d.Active(now)
+	if !active {
+		return operatorv1.OperatorCondition{Type: ConditionType, Status: operatorv1.ConditionFalse, Reason: "AsExpected"}
+	}
+	conditionReason := "CertificatesExpired"
+	if d.forced {
+		conditionReason = "Forced"
+	}
+	return operatorv1.OperatorCondition{
+		Type:    ConditionType,
+		Status:  operatorv1.ConditionTrue,
+		Reason:  conditionReason,
+		Message: fmt.Sprintf("The csr-signer and the service account trust are regenerated first: %s", reason),
+	}
+}
+
+// expiry returns the end of the validity of the first certificate of the secret.
+func expiry(secret *corev1.Secret) (time.Time, bool) {
+	certs, err := cert.ParseCertsPEM(secret.Data[corev1.TLSCertKey])
+	if err != nil {
+		return time.Time{}, false
+	}
+	return certs[0].NotAfter, true
+}
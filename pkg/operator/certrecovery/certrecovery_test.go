@@ -0,0 +1,70 @@
+package certrecovery
+
+import (
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	corev1listers "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/tools/cache"
+
+	operatorv1 "github.com/openshift/api/operator/v1"
+	"github.com/openshift/library-go/pkg/crypto"
+
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/operatorclient"
+)
+
+func TestDetector(t *testing.T) {
+	caConfig, err := crypto.MakeSelfSignedCAConfigForDuration("csr-signer", time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	certPEM, keyPEM, err := caConfig.GetPEMBytes()
+	if err != nil {
+		t.Fatal(err)
+	}
+	now := time.Now()
+
+	tests := []struct {
+		name           string
+		forced         bool
+		now            time.Time
+		secrets        []string
+		expectedStatus operatorv1.ConditionStatus
+		expectedReason string
+	}{
+		{name: "no certificates", now: now, expectedStatus: operatorv1.ConditionFalse, expectedReason: "AsExpected"},
+		{name: "valid certificates", now: now, secrets: []string{"csr-signer", "kube-controller-manager-client-cert-key"}, expectedStatus: operatorv1.ConditionFalse, expectedReason: "AsExpected"},
+		{name: "expired csr-signer", now: now.Add(2 * time.Hour), secrets: []string{"csr-signer"}, expectedStatus: operatorv1.ConditionTrue, expectedReason: "CertificatesExpired"},
+		{name: "expired client certificate", now: now.Add(2 * time.Hour), secrets: []string{"kube-controller-manager-client-cert-key"}, expectedStatus: operatorv1.ConditionTrue, expectedReason: "CertificatesExpired"},
+		{name: "forced", forced: true, now: now, expectedStatus: operatorv1.ConditionTrue, expectedReason: "Forced"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			indexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{})
+			for _, name := range test.secrets {
+				if err := indexer.Add(&corev1.Secret{
+					ObjectMeta: metav1.ObjectMeta{Namespace: operatorclient.TargetNamespace, Name: name},
+					Data:       map[string][]byte{"tls.crt": certPEM, "tls.key": keyPEM},
+				}); err != nil {
+					t.Fatal(err)
+				}
+			}
+			d := &Detector{forced: test.forced, secretLister: corev1listers.NewSecretLister(indexer)}
+
+			condition := d.Condition(test.now)
+			if condition.Status != test.expectedStatus || condition.Reason != test.expectedReason {
+				t.Errorf("expected %s/%s, got %#v", test.expectedStatus, test.expectedReason, condition)
+			}
+			if active, _ := d.Active(test.now); active != (test.expectedStatus == operatorv1.ConditionTrue) {
+				t.Errorf("expected active %v, got %v", !active, active)
+			}
+		})
+	}
+
+	var nilDetector *Detector
+	if active, _ := nilDetector.Active(now); active {
+		t.Errorf("expected a nil detector to be inactive")
+	}
+}
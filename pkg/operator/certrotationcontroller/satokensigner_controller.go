@@ -14,9 +14,14 @@ import (
 	"k8s.io/apimachinery/pkg/util/sets"
 	"k8s.io/client-go/kubernetes"
 	corev1client "k8s.io/client-go/kubernetes/typed/core/v1"
+	corev1listers "k8s.io/client-go/listers/core/v1"
 
 	operatorv1 "github.com/openshift/api/operator/v1"
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/certrecovery"
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/fipscontroller"
 	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/operatorclient"
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/targetconfigcontroller"
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/tuning"
 	"github.com/openshift/library-go/pkg/controller/factory"
 	"github.com/openshift/library-go/pkg/operator/encryption/crypto"
 	"github.com/openshift/library-go/pkg/operator/events"
@@ -26,30 +31,49 @@ import (
 
 const (
 	saTokenReadyTimeAnnotation = "kube-controller-manager.openshift.io/ready-to-use"
+
+	// saTokenSignerSyncScheduledCondition reports the sync scheduled for when a new signer can be promoted.
+	saTokenSignerSyncScheduledCondition = "SATokenSignerSyncScheduled"
+
+	// defaultSATokenSignerPropagation is how long a new signer is published to the kube-apiserver before it is promoted.
+	defaultSATokenSignerPropagation = 5 * time.Minute
+	// defaultSATokenSignerPadding is added to the promotion time before the controller syncs again.
+	defaultSATokenSignerPadding = 10 * time.Second
 )
 
 type SATokenSignerController struct {
 	operatorClient  v1helpers.StaticPodOperatorClient
 	secretClient    corev1client.SecretsGetter
 	configMapClient corev1client.ConfigMapsGetter
+	// applyClient writes the signer and its public keys with server-side apply, it reads the live objects because the
+	// informers strip the managed fields
+	applyClient     corev1client.CoreV1Interface
 	endpointClient  corev1client.EndpointsGetter
 	podClient       corev1client.PodsGetter
+	configMapLister corev1listers.ConfigMapLister
+	// certRecovery shortens the propagation of a new signer in the cert recovery mode
+	certRecovery *certrecovery.Detector
 
 	confirmedBootstrapNodeGone bool
+	nextScheduledSync          time.Time
 }
 
 func NewSATokenSignerController(
 	operatorClient v1helpers.StaticPodOperatorClient,
 	kubeInformersForNamespaces v1helpers.KubeInformersForNamespaces,
 	kubeClient kubernetes.Interface,
+	certRecovery *certrecovery.Detector,
 	eventRecorder events.Recorder,
 ) factory.Controller {
 	c := &SATokenSignerController{
 		operatorClient:  operatorClient,
 		secretClient:    v1helpers.CachedSecretGetter(kubeClient.CoreV1(), kubeInformersForNamespaces),
 		configMapClient: v1helpers.CachedConfigMapGetter(kubeClient.CoreV1(), kubeInformersForNamespaces),
+		applyClient:     kubeClient.CoreV1(),
 		endpointClient:  kubeClient.CoreV1(),
 		podClient:       kubeClient.CoreV1(),
+		configMapLister: kubeInformersForNamespaces.ConfigMapLister(),
+		certRecovery:    certRecovery,
 	}
 
 	return factory.New().WithInformers(
@@ -72,13 +96,63 @@ func (c *SATokenSignerController) sync(ctx context.Context, syncCtx factory.Sync
 		condition.Reason = "Error"
 		condition.Message = syncErr.Error()
 	}
-	if _, _, updateErr := v1helpers.UpdateStatus(ctx, c.operatorClient, v1helpers.UpdateConditionFn(condition)); updateErr != nil {
+	scheduledCondition := syncScheduledCondition(c.nextScheduledSync, time.Now())
+	if _, _, updateErr := v1helpers.UpdateStatus(ctx, c.operatorClient, v1helpers.UpdateConditionFn(condition), v1helpers.UpdateConditionFn(scheduledCondition)); updateErr != nil {
 		return updateErr
 	}
 
 	return syncErr
 }
 
+// checkUserSpecifiedSigner refuses the service account token signer provided by the installer when the cluster is in
+// FIPS mode and the key is not approved.
+func (c *SATokenSignerController) checkUserSpecifiedSigner(ctx context.Context) error {
+	enabled, err := fipscontroller.IsFIPSEnabled(c.configMapLister)
+	if err != nil || !enabled {
+		return err
+	}
+	secret, err := c.secretClient.Secrets(operatorclient.GlobalUserSpecifiedConfigNamespace).Get(ctx, operatorclient.UserSpecifiedConfigSecretName, metav1.GetOptions{})
+	if errors.IsNotFound(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if err := fipscontroller.CheckPrivateKeyPEM(secret.Data["service-account.key"]); err != nil {
+		return fmt.Errorf("refusing secret/%s -n %s in FIPS mode: %v", secret.Name, secret.Namespace, err)
+	}
+	return nil
+}
+
+// syncScheduledCondition reports the sync scheduled for the promotion of a new signer until it is due.
+func syncScheduledCondition(nextScheduledSync, now time.Time) operatorv1.OperatorCondition {
+	if !nextScheduledSync.After(now) {
+		return operatorv1.OperatorCondition{
+			Type:   saTokenSignerSyncScheduledCondition,
+			Status: operatorv1.ConditionFalse,
+		}
+	}
+	return operatorv1.OperatorCondition{
+		Type:    saTokenSignerSyncScheduledCondition,
+		Status:  operatorv1.ConditionTrue,
+		Reason:  "SignerPropagating",
+		Message: fmt.Sprintf("nextScheduledSync %s, the new service account token signer is promoted once the kube-apiserver trusts it", nextScheduledSync.UTC().Format(time.RFC3339)),
+	}
+}
+
+// signerDelays returns how long a new signer propagates and the padding of the sync after it, the tuning config
+// shortens them for test clusters.
+func signerDelays(tuningConfig *tuning.Config) (time.Duration, time.Duration) {
+	propagation, padding := defaultSATokenSignerPropagation, defaultSATokenSignerPadding
+	if delay := tuningConfig.RequeueDelays.SATokenSignerPropagation; delay != nil {
+		propagation = delay.Duration
+	}
+	if delay := tuningConfig.RequeueDelays.Padding; delay != nil {
+		padding = delay.Duration
+	}
+	return propagation, padding
+}
+
 type unexpectedAddressesError struct {
 	message string
 }
@@ -151,8 +225,11 @@ func (c *SATokenSignerController) syncWorker(ctx context.Context, syncCtx factor
 			return err
 		}
 		// at this point we have not-found condition, sync the original
+		if err := c.checkUserSpecifiedSigner(ctx); err != nil {
+			return err
+		}
 		_, _, err = resourceapply.SyncSecret(ctx, c.secretClient, syncCtx.Recorder(),
-			operatorclient.GlobalUserSpecifiedConfigNamespace, "initial-service-account-private-key",
+			operatorclient.GlobalUserSpecifiedConfigNamespace, operatorclient.UserSpecifiedConfigSecretName,
 			operatorclient.TargetNamespace, "service-account-private-key", []metav1.OwnerReference{})
 		return err
 	}
@@ -172,6 +249,15 @@ func (c *SATokenSignerController) syncWorker(ctx context.Context, syncCtx factor
 	}
 
 	if needNewSATokenSigningKey {
+		tuningConfig, err := tuning.Get(c.configMapLister)
+		if err != nil {
+			return err
+		}
+		propagation, padding := signerDelays(tuningConfig)
+		if active, _ := c.certRecovery.Active(time.Now()); active && propagation > certrecovery.SATokenSignerPropagation {
+			propagation = certrecovery.SATokenSignerPropagation
+		}
+
 		pubKeyPEM, privKeyPEM, err := crypto.GenerateRSAKeyPair()
 		if err != nil {
 			return err
@@ -180,7 +266,7 @@ func (c *SATokenSignerController) syncWorker(ctx context.Context, syncCtx factor
 		saTokenSigner = &corev1.Secret{
 			ObjectMeta: metav1.ObjectMeta{
 				Namespace: operatorclient.OperatorNamespace, Name: "next-service-account-private-key",
-				Annotations: map[string]string{saTokenReadyTimeAnnotation: time.Now().Add(5 * time.Minute).Format(time.RFC3339)},
+				Annotations: map[string]string{saTokenReadyTimeAnnotation: time.Now().Add(propagation).Format(time.RFC3339)},
 			},
 			Data: map[string][]byte{
 				"service-account.key": privKeyPEM,
@@ -188,12 +274,13 @@ func (c *SATokenSignerController) syncWorker(ctx context.Context, syncCtx factor
 			},
 		}
 
-		saTokenSigner, _, err = resourceapply.ApplySecret(ctx, c.secretClient, syncCtx.Recorder(), saTokenSigner)
+		saTokenSigner, _, err = targetconfigcontroller.ApplySecret(ctx, c.applyClient, syncCtx.Recorder(), saTokenSigner)
 		if err != nil {
 			return err
 		}
 		// requeue for after we should have recovered
-		syncCtx.Queue().AddAfter(syncCtx.QueueKey(), 5*time.Minute+10*time.Second)
+		syncCtx.Queue().AddAfter(syncCtx.QueueKey(), propagation+padding)
+		c.nextScheduledSync = time.Now().Add(propagation + padding)
 	}
 
 	saTokenSigningCerts, err := c.configMapClient.ConfigMaps(operatorclient.GlobalMachineSpecifiedConfigNamespace).Get(ctx, "sa-token-signing-certs", metav1.GetOptions{})
@@ -215,7 +302,7 @@ func (c *SATokenSignerController) syncWorker(ctx context.Context, syncCtx factor
 	}
 	if !hasThisPublicKey {
 		saTokenSigningCerts.Data[fmt.Sprintf("service-account-%03d.pub", len(saTokenSigningCerts.Data)+1)] = currPublicKey
-		saTokenSigningCerts, _, err = resourceapply.ApplyConfigMap(ctx, c.configMapClient, syncCtx.Recorder(), saTokenSigningCerts)
+		saTokenSigningCerts, _, err = targetconfigcontroller.ApplyConfigMap(ctx, c.applyClient, syncCtx.Recorder(), saTokenSigningCerts)
 		if err != nil {
 			return err
 		}
@@ -0,0 +1,176 @@
+package clientcertexpirycontroller
+
+import (
+	"context"
+	"crypto/x509"
+	"fmt"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	corev1client "k8s.io/client-go/kubernetes/typed/core/v1"
+	corev1listers "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/util/cert"
+
+	operatorv1 "github.com/openshift/api/operator/v1"
+	"github.com/openshift/library-go/pkg/controller/factory"
+	"github.com/openshift/library-go/pkg/operator/events"
+	"github.com/openshift/library-go/pkg/operator/resource/resourceapply"
+	"github.com/openshift/library-go/pkg/operator/v1helpers"
+
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/operatorclient"
+)
+
+const (
+	// ClientCertSecretName is the client certificate of the kubeconfig of the kube-controller-manager. The
+	// kube-apiserver-operator rotates it, the cert-syncer copies it to the nodes without a new revision.
+	ClientCertSecretName = "kube-controller-manager-client-cert-key"
+
+	// ConfigMapName records the client certificate the latest revision was rolled out with. It is revisioned, an
+	// update rolls out a new revision, which restarts the operand with the current certificate.
+	ConfigMapName = "client-cert-expiry"
+
+	expiryApproachingCondition = "ClientCertExpiryApproaching"
+
+	// expiryApproachingFraction of the lifetime of the rolled out certificate passes before a newer certificate is
+	// rolled out, days before the rolled out one expires.
+	expiryApproachingFraction = 0.8
+)
+
+// ClientCertExpiryController makes sure the kube-controller-manager does not keep authenticating with an expiring
+// client certificate. The certificate files are replaced on rotation, but the operand is not guaranteed to reload
+// them, so a new revision is rolled out once the certificate of the latest rollout approaches its expiry and a newer
+// one is available. The rotation itself is up to the kube-apiserver-operator, a certificate approaching its expiry
+// without a newer one is reported in the ClientCertExpiryApproaching condition, as is a rollout held back while the
+// operator is Unmanaged.
+type ClientCertExpiryController struct {
+	operatorClient  v1helpers.StaticPodOperatorClient
+	secretLister    corev1listers.SecretLister
+	configMapLister corev1listers.ConfigMapLister
+	configMapClient corev1client.ConfigMapsGetter
+	now             func() time.Time
+}
+
+func NewClientCertExpiryController(
+	operatorClient v1helpers.StaticPodOperatorClient,
+	kubeInformersForNamespaces v1helpers.KubeInformersForNamespaces,
+	kubeClient kubernetes.Interface,
+	eventRecorder events.Recorder,
+) factory.Controller {
+	c := &ClientCertExpiryController{
+		operatorClient:  operatorClient,
+		secretLister:    kubeInformersForNamespaces.SecretLister(),
+		configMapLister: kubeInformersForNamespaces.ConfigMapLister(),
+		configMapClient: kubeClient.CoreV1(),
+		now:             time.Now,
+	}
+
+	return factory.New().WithFilteredEventsInformers(
+		factory.NamesFilter(ClientCertSecretName, ConfigMapName),
+		kubeInformersForNamespaces.InformersFor(operatorclient.TargetNamespace).Core().V1().Secrets().Informer(),
+		kubeInformersForNamespaces.InformersFor(operatorclient.TargetNamespace).Core().V1().ConfigMaps().Informer(),
+	).ResyncEvery(time.Hour).WithSync(c.sync).ToController("ClientCertExpiryController", eventRecorder)
+}
+
+func (c *ClientCertExpiryController) sync(ctx context.Context, syncCtx factory.SyncContext) error {
+	operatorSpec, _, _, err := c.operatorClient.GetStaticPodOperatorState()
+	if err != nil {
+		return err
+	}
+	secret, err := c.secretLister.Secrets(operatorclient.TargetNamespace).Get(ClientCertSecretName)
+	if apierrors.IsNotFound(err) {
+		// the resource sync controller copies it once the kube-apiserver-operator issued it
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	certs, err := cert.ParseCertsPEM(secret.Data[corev1.TLSCertKey])
+	if err != nil {
+		return fmt.Errorf("secret/%s -n %s: %v", ClientCertSecretName, operatorclient.TargetNamespace, err)
+	}
+	current := certs[0]
+
+	rolledOut, err := c.configMapLister.ConfigMaps(operatorclient.TargetNamespace).Get(ConfigMapName)
+	if err != nil && !apierrors.IsNotFound(err) {
+		return err
+	}
+	now := c.now()
+	condition := operatorv1.OperatorCondition{
+		Type:   expiryApproachingCondition,
+		Status: operatorv1.ConditionFalse,
+	}
+	// rolloutReason is set when the current certificate has to be rolled out
+	var rolloutReason string
+	switch {
+	case rolledOut == nil:
+		rolloutReason = "no client certificate was recorded for the latest revision"
+	case rolledOut.Data["serialNumber"] == current.SerialNumber.String():
+		if expiryApproaching(current.NotBefore, current.NotAfter, now) {
+			condition.Status = operatorv1.ConditionTrue
+			condition.Reason = "ClientCertificateNotRotated"
+			condition.Message = fmt.Sprintf("the client certificate in secret/%s -n %s expires at %s and has not been rotated", ClientCertSecretName, operatorclient.TargetNamespace, current.NotAfter.UTC().Format(time.RFC3339))
+		}
+	default:
+		notBefore, notAfter, err := recordedValidity(rolledOut)
+		if err != nil {
+			// an unreadable record is replaced by the current certificate
+			rolloutReason = err.Error()
+			break
+		}
+		if expiryApproaching(notBefore, notAfter, now) {
+			rolloutReason = fmt.Sprintf("the rolled out client certificate expires at %s", notAfter.UTC().Format(time.RFC3339))
+		}
+	}
+
+	switch {
+	case len(rolloutReason) == 0:
+	case operatorSpec.ManagementState == operatorv1.Unmanaged:
+		// a new revision changes the operand, the expiry is only reported
+		condition.Status = operatorv1.ConditionTrue
+		condition.Reason = "Unmanaged"
+		condition.Message = fmt.Sprintf("the client certificate %s is not rolled out while the operator is Unmanaged: %s", current.SerialNumber, rolloutReason)
+	default:
+		if err := c.rollOut(ctx, syncCtx.Recorder(), current, rolloutReason); err != nil {
+			return err
+		}
+	}
+
+	_, _, err = v1helpers.UpdateStaticPodStatus(ctx, c.operatorClient, v1helpers.UpdateStaticPodConditionFn(condition))
+	return err
+}
+
+// rollOut records the certificate in ConfigMapName, which rolls out a new revision.
+func (c *ClientCertExpiryController) rollOut(ctx context.Context, recorder events.Recorder, certificate *x509.Certificate, reason string) error {
+	_, _, err := resourceapply.ApplyConfigMap(ctx, c.configMapClient, recorder, &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Namespace: operatorclient.TargetNamespace, Name: ConfigMapName},
+		Data: map[string]string{
+			"serialNumber": certificate.SerialNumber.String(),
+			"notBefore":    certificate.NotBefore.UTC().Format(time.RFC3339),
+			"notAfter":     certificate.NotAfter.UTC().Format(time.RFC3339),
+		},
+	})
+	if err != nil {
+		return err
+	}
+	recorder.Eventf("ClientCertificateRollout", "Rolling out the client certificate %s expiring at %s: %s", certificate.SerialNumber, certificate.NotAfter.UTC().Format(time.RFC3339), reason)
+	return nil
+}
+
+func expiryApproaching(notBefore, notAfter, now time.Time) bool {
+	return !now.Before(notBefore.Add(time.Duration(float64(notAfter.Sub(notBefore)) * expiryApproachingFraction)))
+}
+
+func recordedValidity(configMap *corev1.ConfigMap) (time.Time, time.Time, error) {
+	notBefore, err := time.Parse(time.RFC3339, configMap.Data["notBefore"])
+	if err != nil {
+		return time.Time{}, time.Time{}, fmt.Errorf("invalid notBefore of configmap/%s: %v", ConfigMapName, err)
+	}
+	notAfter, err := time.Parse(time.RFC3339, configMap.Data["notAfter"])
+	if err != nil {
+		return time.Time{}, time.Time{}, fmt.Errorf("invalid notAfter of configmap/%s: %v", ConfigMapName, err)
+	}
+	return notBefore, notAfter, nil
+}
@@ -0,0 +1,159 @@
+package clientcertexpirycontroller
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+	corev1listers "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/util/cert"
+
+	operatorv1 "github.com/openshift/api/operator/v1"
+	"github.com/openshift/library-go/pkg/controller/factory"
+	"github.com/openshift/library-go/pkg/operator/events"
+	"github.com/openshift/library-go/pkg/operator/v1helpers"
+
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/operatorclient"
+)
+
+func newCertificate(t *testing.T, serial int64, notBefore time.Time, lifetime time.Duration) []byte {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(serial),
+		Subject:      pkix.Name{CommonName: "system:kube-controller-manager"},
+		NotBefore:    notBefore,
+		NotAfter:     notBefore.Add(lifetime),
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: cert.CertificateBlockType, Bytes: der})
+}
+
+func TestSync(t *testing.T) {
+	now := time.Date(2026, 1, 31, 0, 0, 0, 0, time.UTC)
+	lifetime := 30 * 24 * time.Hour
+	// issued 25 days ago, past 80% of its lifetime
+	old := newCertificate(t, 1, now.Add(-25*24*time.Hour), lifetime)
+	rotated := newCertificate(t, 2, now.Add(-10*24*time.Hour), lifetime)
+	record := func(serial string, notBefore time.Time) *corev1.ConfigMap {
+		return &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Namespace: operatorclient.TargetNamespace, Name: ConfigMapName},
+			Data: map[string]string{
+				"serialNumber": serial,
+				"notBefore":    notBefore.Format(time.RFC3339),
+				"notAfter":     notBefore.Add(lifetime).Format(time.RFC3339),
+			},
+		}
+	}
+
+	tests := []struct {
+		name              string
+		managementState   operatorv1.ManagementState
+		certificate       []byte
+		recorded          *corev1.ConfigMap
+		expectedSerial    string
+		expectedCondition operatorv1.ConditionStatus
+	}{
+		{
+			name:              "nothing recorded",
+			certificate:       rotated,
+			expectedSerial:    "2",
+			expectedCondition: operatorv1.ConditionFalse,
+		},
+		{
+			name:              "rolled out certificate expiring with a newer one",
+			certificate:       rotated,
+			recorded:          record("1", now.Add(-25*24*time.Hour)),
+			expectedSerial:    "2",
+			expectedCondition: operatorv1.ConditionFalse,
+		},
+		{
+			name:              "rolled out certificate valid with a newer one",
+			certificate:       rotated,
+			recorded:          record("1", now.Add(-15*24*time.Hour)),
+			expectedSerial:    "1",
+			expectedCondition: operatorv1.ConditionFalse,
+		},
+		{
+			name:              "rolled out certificate expiring with a newer one while unmanaged",
+			managementState:   operatorv1.Unmanaged,
+			certificate:       rotated,
+			recorded:          record("1", now.Add(-25*24*time.Hour)),
+			expectedSerial:    "1",
+			expectedCondition: operatorv1.ConditionTrue,
+		},
+		{
+			name:              "rolled out certificate expiring without a newer one",
+			certificate:       old,
+			recorded:          record("1", now.Add(-25*24*time.Hour)),
+			expectedSerial:    "1",
+			expectedCondition: operatorv1.ConditionTrue,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			secretIndexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc})
+			if err := secretIndexer.Add(&corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{Namespace: operatorclient.TargetNamespace, Name: ClientCertSecretName},
+				Data:       map[string][]byte{corev1.TLSCertKey: test.certificate},
+			}); err != nil {
+				t.Fatal(err)
+			}
+			configMapIndexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc})
+			kubeClient := fake.NewSimpleClientset()
+			if test.recorded != nil {
+				if err := configMapIndexer.Add(test.recorded); err != nil {
+					t.Fatal(err)
+				}
+				kubeClient = fake.NewSimpleClientset(test.recorded)
+			}
+			operatorClient := v1helpers.NewFakeStaticPodOperatorClient(
+				&operatorv1.StaticPodOperatorSpec{OperatorSpec: operatorv1.OperatorSpec{ManagementState: test.managementState}},
+				&operatorv1.StaticPodOperatorStatus{},
+				nil, nil,
+			)
+
+			c := &ClientCertExpiryController{
+				operatorClient:  operatorClient,
+				secretLister:    corev1listers.NewSecretLister(secretIndexer),
+				configMapLister: corev1listers.NewConfigMapLister(configMapIndexer),
+				configMapClient: kubeClient.CoreV1(),
+				now:             func() time.Time { return now },
+			}
+			if err := c.sync(context.TODO(), factory.NewSyncContext("test", events.NewInMemoryRecorder("test"))); err != nil {
+				t.Fatal(err)
+			}
+
+			recorded, err := kubeClient.CoreV1().ConfigMaps(operatorclient.TargetNamespace).Get(context.TODO(), ConfigMapName, metav1.GetOptions{})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if serial := recorded.Data["serialNumber"]; serial != test.expectedSerial {
+				t.Errorf("expected the rolled out certificate %s, got %s", test.expectedSerial, serial)
+			}
+			_, status, _, _ := operatorClient.GetStaticPodOperatorState()
+			condition := v1helpers.FindOperatorCondition(status.Conditions, expiryApproachingCondition)
+			if condition == nil || condition.Status != test.expectedCondition {
+				t.Errorf("expected %s to be %s, got %v", expiryApproachingCondition, test.expectedCondition, condition)
+			}
+		})
+	}
+}
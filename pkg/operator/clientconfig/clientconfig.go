@@ -0,0 +1,77 @@
+package clientconfig
+
+import (
+	"fmt"
+	"net/http"
+	"runtime"
+
+	"k8s.io/client-go/rest"
+	"k8s.io/component-base/metrics"
+	"k8s.io/component-base/metrics/legacyregistry"
+
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/version"
+)
+
+const (
+	// QPS and Burst leave headroom for the bursts of writes at a new revision and during upgrades, the client-go
+	// defaults (5 and 10) made the operator throttle itself well before the kube-apiserver's API Priority and
+	// Fairness did. The client side waits are reported in rest_client_rate_limiter_duration_seconds.
+	QPS   = 50
+	Burst = 100
+
+	userAgentName = "cluster-kube-controller-manager-operator"
+
+	// the API Priority and Fairness response headers naming the flow schema and priority level of a request
+	flowSchemaUIDHeader    = "X-Kubernetes-PF-FlowSchema-UID"
+	priorityLevelUIDHeader = "X-Kubernetes-PF-PriorityLevel-UID"
+)
+
+var throttledRequests = metrics.NewCounterVec(
+	&metrics.CounterOpts{
+		Name:           "kube_controller_manager_operator_client_throttled_requests_total",
+		Help:           "Number of requests of the operator rejected by the kube-apiserver's API Priority and Fairness, by flow schema and priority level.",
+		StabilityLevel: metrics.ALPHA,
+	},
+	[]string{"flow_schema_uid", "priority_level_uid"},
+)
+
+func init() {
+	legacyregistry.MustRegister(throttledRequests)
+}
+
+// ForOperator returns a copy of the config for the operator's own clients. Besides the rate limits and the user
+// agent, it counts the requests the kube-apiserver throttles. Those are retried by client-go after the Retry-After
+// delay the priority level returns, so they only show up as sync latency otherwise.
+func ForOperator(config *rest.Config) *rest.Config {
+	config = rest.CopyConfig(config)
+	config.QPS = QPS
+	config.Burst = Burst
+	config.UserAgent = UserAgent()
+	config.Wrap(func(rt http.RoundTripper) http.RoundTripper {
+		return &throttlingRoundTripper{delegate: rt}
+	})
+	return config
+}
+
+// UserAgent identifies the operator and its version, so its flows are easy to tell apart in the API Priority and
+// Fairness metrics and in the audit log.
+func UserAgent() string {
+	gitVersion := version.Get().GitVersion
+	if len(gitVersion) == 0 {
+		gitVersion = "unknown"
+	}
+	return fmt.Sprintf("%s/%s (%s/%s)", userAgentName, gitVersion, runtime.GOOS, runtime.GOARCH)
+}
+
+// throttlingRoundTripper counts the requests rejected with 429 Too Many Requests.
+type throttlingRoundTripper struct {
+	delegate http.RoundTripper
+}
+
+func (rt *throttlingRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	resp, err := rt.delegate.RoundTrip(req)
+	if err == nil && resp.StatusCode == http.StatusTooManyRequests {
+		throttledRequests.WithLabelValues(resp.Header.Get(flowSchemaUIDHeader), resp.Header.Get(priorityLevelUIDHeader)).Inc()
+	}
+	return resp, err
+}
@@ -0,0 +1,57 @@
+package clientconfig
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"k8s.io/client-go/rest"
+	"k8s.io/component-base/metrics/testutil"
+)
+
+func TestForOperator(t *testing.T) {
+	original := &rest.Config{Host: "https://localhost:6443", QPS: 5, Burst: 10}
+	config := ForOperator(original)
+
+	if original.QPS != 5 || original.Burst != 10 || len(original.UserAgent) > 0 {
+		t.Errorf("expected the original config to be left alone, got %+v", original)
+	}
+	if config.QPS != QPS || config.Burst != Burst {
+		t.Errorf("expected QPS %v and burst %v, got %v and %v", QPS, Burst, config.QPS, config.Burst)
+	}
+	if !strings.HasPrefix(config.UserAgent, userAgentName+"/") {
+		t.Errorf("unexpected user agent %q", config.UserAgent)
+	}
+}
+
+func TestThrottlingRoundTripper(t *testing.T) {
+	throttledRequests.Reset()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set(flowSchemaUIDHeader, "fs")
+		w.Header().Set(priorityLevelUIDHeader, "pl")
+		if r.URL.Path == "/throttled" {
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	client := &http.Client{Transport: &throttlingRoundTripper{delegate: http.DefaultTransport}}
+	for _, path := range []string{"/ok", "/throttled", "/throttled"} {
+		resp, err := client.Get(server.URL + path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+	}
+
+	count, err := testutil.GetCounterMetricValue(throttledRequests.WithLabelValues("fs", "pl"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if count != 2 {
+		t.Errorf("expected 2 throttled requests, got %v", count)
+	}
+}
@@ -0,0 +1,73 @@
+// Package conditiontest checks the lastTransitionTime of the operator conditions in tests. A transition time that
+// moves without a status change resets the age of the ClusterOperator conditions, and the alerts on them fire late
+// or not at all.
+package conditiontest
+
+import (
+	"fmt"
+	"testing"
+
+	operatorv1 "github.com/openshift/api/operator/v1"
+	"github.com/openshift/library-go/pkg/operator/v1helpers"
+)
+
+// TransitionTimeRegressions compares the conditions before and after an update. It reports the conditions whose
+// lastTransitionTime changed while their status did not, and the ones whose status changed while their
+// lastTransitionTime did not move forward.
+func TransitionTimeRegressions(before, after []operatorv1.OperatorCondition) []error {
+	var errs []error
+	for _, condition := range after {
+		previous := v1helpers.FindOperatorCondition(before, condition.Type)
+		if previous == nil {
+			if condition.LastTransitionTime.IsZero() {
+				errs = append(errs, fmt.Errorf("%s was added without a lastTransitionTime", condition.Type))
+			}
+			continue
+		}
+		switch {
+		case previous.Status == condition.Status && !previous.LastTransitionTime.Equal(&condition.LastTransitionTime):
+			errs = append(errs, fmt.Errorf("%s stayed %s but its lastTransitionTime moved from %s to %s", condition.Type, condition.Status, previous.LastTransitionTime, condition.LastTransitionTime))
+		case previous.Status != condition.Status && !condition.LastTransitionTime.After(previous.LastTransitionTime.Time):
+			errs = append(errs, fmt.Errorf("%s changed from %s to %s but its lastTransitionTime stayed at %s", condition.Type, previous.Status, condition.Status, condition.LastTransitionTime))
+		}
+	}
+	return errs
+}
+
+// StatusRecorder snapshots the conditions of a static pod operator client, and fails the test for every transition
+// time regression between two snapshots.
+type StatusRecorder struct {
+	client   v1helpers.StaticPodOperatorClient
+	previous []operatorv1.OperatorCondition
+}
+
+// NewStatusRecorder takes the first snapshot of the conditions of the client.
+func NewStatusRecorder(t testing.TB, client v1helpers.StaticPodOperatorClient) *StatusRecorder {
+	t.Helper()
+	r := &StatusRecorder{client: client}
+	r.previous = r.conditions(t)
+	return r
+}
+
+// Check compares the current conditions with the last snapshot and takes a new one.
+func (r *StatusRecorder) Check(t testing.TB) {
+	t.Helper()
+	current := r.conditions(t)
+	for _, err := range TransitionTimeRegressions(r.previous, current) {
+		t.Error(err)
+	}
+	r.previous = current
+}
+
+func (r *StatusRecorder) conditions(t testing.TB) []operatorv1.OperatorCondition {
+	t.Helper()
+	_, status, _, err := r.client.GetStaticPodOperatorState()
+	if err != nil {
+		t.Fatal(err)
+	}
+	conditions := make([]operatorv1.OperatorCondition, len(status.Conditions))
+	for i := range status.Conditions {
+		status.Conditions[i].DeepCopyInto(&conditions[i])
+	}
+	return conditions
+}
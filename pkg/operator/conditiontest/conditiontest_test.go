@@ -0,0 +1,38 @@
+package conditiontest
+
+import (
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	operatorv1 "github.com/openshift/api/operator/v1"
+)
+
+func TestTransitionTimeRegressions(t *testing.T) {
+	earlier := metav1.NewTime(time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC))
+	later := metav1.NewTime(earlier.Add(time.Minute))
+	degraded := func(status operatorv1.ConditionStatus, transition metav1.Time) []operatorv1.OperatorCondition {
+		return []operatorv1.OperatorCondition{{Type: "TestDegraded", Status: status, LastTransitionTime: transition}}
+	}
+
+	tests := []struct {
+		name          string
+		before, after []operatorv1.OperatorCondition
+		expected      int
+	}{
+		{name: "unchanged", before: degraded(operatorv1.ConditionFalse, earlier), after: degraded(operatorv1.ConditionFalse, earlier)},
+		{name: "transition", before: degraded(operatorv1.ConditionFalse, earlier), after: degraded(operatorv1.ConditionTrue, later)},
+		{name: "added", after: degraded(operatorv1.ConditionFalse, earlier)},
+		{name: "added without a transition time", after: degraded(operatorv1.ConditionFalse, metav1.Time{}), expected: 1},
+		{name: "moved without a status change", before: degraded(operatorv1.ConditionFalse, earlier), after: degraded(operatorv1.ConditionFalse, later), expected: 1},
+		{name: "status change without a transition", before: degraded(operatorv1.ConditionFalse, earlier), after: degraded(operatorv1.ConditionTrue, earlier), expected: 1},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if errs := TransitionTimeRegressions(test.before, test.after); len(errs) != test.expected {
+				t.Errorf("expected %d regressions, got %v", test.expected, errs)
+			}
+		})
+	}
+}
@@ -0,0 +1,189 @@
+package configfingerprintcontroller
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/ghodss/yaml"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	corev1client "k8s.io/client-go/kubernetes/typed/core/v1"
+	corev1listers "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/util/cert"
+
+	"github.com/openshift/library-go/pkg/controller/factory"
+	"github.com/openshift/library-go/pkg/operator/events"
+	"github.com/openshift/library-go/pkg/operator/resource/resourceapply"
+	"github.com/openshift/library-go/pkg/operator/v1helpers"
+
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/operatorclient"
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/targetconfigcontroller"
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/tuning"
+)
+
+const (
+	// fingerprintConfigMapName is collected by the insights-operator.
+	fingerprintConfigMapName = "kube-controller-manager-config-fingerprint"
+	fingerprintKey           = "fingerprint.json"
+)
+
+// certSecrets are the secrets of the TargetNamespace whose certificate ages are part of the fingerprint.
+var certSecrets = []string{
+	"csr-signer",
+	"kube-controller-manager-client-cert-key",
+	"metrics-serving-cert",
+	"serving-cert",
+}
+
+// ConfigFingerprint is an anonymized summary of the kube-controller-manager configuration. It holds no value that
+// identifies the cluster: the flags are only hashed, and the profiles and certificate ages are the same on many
+// clusters. Support correlates incidents with the configuration patterns in it.
+type ConfigFingerprint struct {
+	// FlagSetHash hashes the names and values of the flags the kube-controller-manager is started with, clusters with
+	// the same hash run the same flags.
+	FlagSetHash string `json:"flagSetHash"`
+	// FlagNames are the names of the flags, sorted.
+	FlagNames []string `json:"flagNames"`
+	// UnsupportedConfigOverrides tells whether spec.unsupportedConfigOverrides are set.
+	UnsupportedConfigOverrides bool `json:"unsupportedConfigOverrides"`
+	// Profiles are the selections of the tuning config.
+	Profiles Profiles `json:"profiles"`
+	// CertificateAges are the ages of the certificates in whole hours, by secret.
+	CertificateAges map[string]int64 `json:"certificateAges"`
+}
+
+// Profiles are the selections of the tuning config that shape the behaviour of the operand.
+type Profiles struct {
+	ProbeProfile        tuning.ProbeProfile    `json:"probeProfile,omitempty"`
+	WorkloadProfile     tuning.WorkloadProfile `json:"workloadProfile,omitempty"`
+	LoggingFormat       tuning.LoggingFormat   `json:"loggingFormat,omitempty"`
+	FastFailover        bool                   `json:"fastFailover"`
+	DisabledControllers []string               `json:"disabledControllers,omitempty"`
+}
+
+// ConfigFingerprintController publishes the ConfigFingerprint in the fingerprintConfigMapName configmap for the
+// insights-operator to collect.
+type ConfigFingerprintController struct {
+	operatorClient  v1helpers.StaticPodOperatorClient
+	configMapClient corev1client.ConfigMapsGetter
+	configMapLister corev1listers.ConfigMapLister
+	secretLister    corev1listers.SecretLister
+	now             func() time.Time
+}
+
+func NewConfigFingerprintController(
+	operatorClient v1helpers.StaticPodOperatorClient,
+	kubeInformersForNamespaces v1helpers.KubeInformersForNamespaces,
+	kubeClient kubernetes.Interface,
+	eventRecorder events.Recorder,
+) factory.Controller {
+	c := &ConfigFingerprintController{
+		operatorClient:  operatorClient,
+		configMapClient: v1helpers.CachedConfigMapGetter(kubeClient.CoreV1(), kubeInformersForNamespaces),
+		configMapLister: kubeInformersForNamespaces.ConfigMapLister(),
+		secretLister:    kubeInformersForNamespaces.SecretLister(),
+		now:             time.Now,
+	}
+
+	// the certificate ages only change by the hour, the resync keeps them current
+	return factory.New().WithInformers(
+		operatorClient.Informer(),
+		kubeInformersForNamespaces.InformersFor(operatorclient.TargetNamespace).Core().V1().ConfigMaps().Informer(),
+		kubeInformersForNamespaces.InformersFor(operatorclient.OperatorNamespace).Core().V1().ConfigMaps().Informer(),
+	).ResyncEvery(time.Hour).WithSync(c.sync).ToController("ConfigFingerprintController", eventRecorder)
+}
+
+func (c *ConfigFingerprintController) sync(ctx context.Context, syncCtx factory.SyncContext) error {
+	operatorSpec, _, _, err := c.operatorClient.GetStaticPodOperatorState()
+	if err != nil {
+		return err
+	}
+
+	fingerprint := &ConfigFingerprint{
+		UnsupportedConfigOverrides: len(operatorSpec.UnsupportedConfigOverrides.Raw) > 0 && string(operatorSpec.UnsupportedConfigOverrides.Raw) != "null",
+		CertificateAges:            map[string]int64{},
+	}
+
+	configMap, err := c.configMapLister.ConfigMaps(operatorclient.TargetNamespace).Get("config")
+	switch {
+	case apierrors.IsNotFound(err):
+	case err != nil:
+		return err
+	default:
+		config := map[string]interface{}{}
+		if err := yaml.Unmarshal([]byte(configMap.Data["config.yaml"]), &config); err != nil {
+			return fmt.Errorf("failed to unmarshal the kube-controller-manager config: %v", err)
+		}
+		fingerprint.FlagSetHash, fingerprint.FlagNames = flagSetFingerprint(targetconfigcontroller.GetKubeControllerManagerArgs(config))
+	}
+
+	tuningConfig, err := tuning.Get(c.configMapLister)
+	if err != nil {
+		return err
+	}
+	fingerprint.Profiles = Profiles{
+		ProbeProfile:        tuningConfig.ProbeProfile,
+		WorkloadProfile:     tuningConfig.WorkloadProfile,
+		LoggingFormat:       tuningConfig.LoggingFormat,
+		FastFailover:        tuningConfig.FastFailover,
+		DisabledControllers: tuningConfig.DisabledControllers,
+	}
+
+	now := c.now()
+	for _, name := range certSecrets {
+		secret, err := c.secretLister.Secrets(operatorclient.TargetNamespace).Get(name)
+		if apierrors.IsNotFound(err) {
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		if age, ok := certificateAge(secret, now); ok {
+			fingerprint.CertificateAges[name] = int64(age / time.Hour)
+		}
+	}
+
+	fingerprintBytes, err := json.MarshalIndent(fingerprint, "", "  ")
+	if err != nil {
+		return err
+	}
+	_, _, err = resourceapply.ApplyConfigMap(ctx, c.configMapClient, syncCtx.Recorder(), &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Namespace: operatorclient.OperatorNamespace, Name: fingerprintConfigMapName},
+		Data:       map[string]string{fingerprintKey: string(fingerprintBytes)},
+	})
+	return err
+}
+
+// flagSetFingerprint returns the hash of the flags and their sorted names. The flags are sorted first, the order of
+// the extended arguments is random.
+func flagSetFingerprint(args []string) (string, []string) {
+	sorted := append([]string{}, args...)
+	sort.Strings(sorted)
+
+	names := []string{}
+	seen := map[string]bool{}
+	for _, arg := range sorted {
+		name := strings.SplitN(strings.TrimPrefix(arg, "--"), "=", 2)[0]
+		if !seen[name] {
+			seen[name] = true
+			names = append(names, name)
+		}
+	}
+	return fmt.Sprintf("%x", sha256.Sum256([]byte(strings.Join(sorted, "\n")))), names
+}
+
+// certificateAge returns the age of the leaf certificate of the tls.crt of the secret.
+func certificateAge(secret *corev1.Secret, now time.Time) (time.Duration, bool) {
+	certs, err := cert.ParseCertsPEM(secret.Data[corev1.TLSCertKey])
+	if err != nil || len(certs) == 0 {
+		return 0, false
+	}
+	return now.Sub(certs[0].NotBefore), true
+}
@@ -0,0 +1,52 @@
+package configfingerprintcontroller
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/openshift/library-go/pkg/crypto"
+)
+
+func TestFlagSetFingerprint(t *testing.T) {
+	hash, names := flagSetFingerprint([]string{"--controllers=*", "--cluster-name=secret-infra-id", "--controllers=-ttl"})
+	reorderedHash, _ := flagSetFingerprint([]string{"--controllers=-ttl", "--controllers=*", "--cluster-name=secret-infra-id"})
+	otherHash, _ := flagSetFingerprint([]string{"--controllers=*", "--cluster-name=other-infra-id", "--controllers=-ttl"})
+
+	if hash != reorderedHash {
+		t.Errorf("expected the hash to not depend on the flag order")
+	}
+	if hash == otherHash {
+		t.Errorf("expected the hash to depend on the flag values")
+	}
+	if expected := []string{"cluster-name", "controllers"}; !reflect.DeepEqual(expected, names) {
+		t.Errorf("expected flag names %v, got %v", expected, names)
+	}
+}
+
+func TestCertificateAge(t *testing.T) {
+	caConfig, err := crypto.MakeSelfSignedCAConfigForDuration("csr-signer", time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	certPEM, _, err := caConfig.GetPEMBytes()
+	if err != nil {
+		t.Fatal(err)
+	}
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "csr-signer"},
+		Data:       map[string][]byte{corev1.TLSCertKey: certPEM},
+	}
+
+	now := caConfig.Certs[0].NotBefore.Add(50 * time.Hour)
+	age, ok := certificateAge(secret, now)
+	if !ok || age != 50*time.Hour {
+		t.Errorf("expected an age of 50h, got %s %v", age, ok)
+	}
+	if _, ok := certificateAge(&corev1.Secret{}, now); ok {
+		t.Errorf("expected no age without a certificate")
+	}
+}
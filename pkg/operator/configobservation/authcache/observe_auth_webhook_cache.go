@@ -0,0 +1,69 @@
+package authcache
+
+import (
+	"reflect"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/openshift/library-go/pkg/operator/configobserver"
+	"github.com/openshift/library-go/pkg/operator/events"
+
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/configobservation"
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/tuning"
+)
+
+// cacheArguments are the extended arguments set from the authWebhookCache section of the tuning configmap.
+var cacheArguments = []string{
+	"authentication-token-webhook-cache-ttl",
+	"authorization-webhook-cache-authorized-ttl",
+	"authorization-webhook-cache-unauthorized-ttl",
+}
+
+// ObserveAuthWebhookCache fills in the cache TTLs of the delegated authentication and authorization of the secure port
+// set in the authWebhookCache section of the tuning configmap. The values are validated when the tuning configmap is
+// parsed.
+func ObserveAuthWebhookCache(genericListers configobserver.Listers, recorder events.Recorder, existingConfig map[string]interface{}) (map[string]interface{}, []error) {
+	listers := genericListers.(configobservation.Listers)
+	errs := []error{}
+
+	previouslyObservedConfig := map[string]interface{}{}
+	for _, argument := range cacheArguments {
+		path := []string{"extendedArguments", argument}
+		if value, _, _ := unstructured.NestedStringSlice(existingConfig, path...); len(value) > 0 {
+			if err := unstructured.SetNestedStringSlice(previouslyObservedConfig, value, path...); err != nil {
+				errs = append(errs, err)
+			}
+		}
+	}
+
+	tuningConfig, err := tuning.Get(listers.ConfigMapLister())
+	if err != nil {
+		return previouslyObservedConfig, append(errs, err)
+	}
+
+	arguments := map[string]string{}
+	if ttl := tuningConfig.AuthWebhookCache.TokenTTL; ttl != nil {
+		arguments["authentication-token-webhook-cache-ttl"] = ttl.Duration.String()
+	}
+	if ttl := tuningConfig.AuthWebhookCache.AuthorizedTTL; ttl != nil {
+		arguments["authorization-webhook-cache-authorized-ttl"] = ttl.Duration.String()
+	}
+	if ttl := tuningConfig.AuthWebhookCache.UnauthorizedTTL; ttl != nil {
+		arguments["authorization-webhook-cache-unauthorized-ttl"] = ttl.Duration.String()
+	}
+
+	observedConfig := map[string]interface{}{}
+	for argument, value := range arguments {
+		if err := unstructured.SetNestedStringSlice(observedConfig, []string{value}, "extendedArguments", argument); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	if len(errs) > 0 {
+		return previouslyObservedConfig, errs
+	}
+
+	if !reflect.DeepEqual(previouslyObservedConfig, observedConfig) {
+		recorder.Eventf("ObserveAuthWebhookCache", "Authentication and authorization cache TTLs changed to %v", arguments)
+	}
+	return observedConfig, errs
+}
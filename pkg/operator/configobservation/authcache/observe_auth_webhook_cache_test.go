@@ -0,0 +1,101 @@
+package authcache
+
+import (
+	"reflect"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	corev1listers "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/tools/cache"
+
+	"github.com/openshift/library-go/pkg/operator/events"
+
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/configobservation"
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/operatorclient"
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/tuning"
+)
+
+func TestObserveAuthWebhookCache(t *testing.T) {
+	cacheConfig := map[string]interface{}{
+		"extendedArguments": map[string]interface{}{
+			"authentication-token-webhook-cache-ttl":       []interface{}{"2s"},
+			"authorization-webhook-cache-authorized-ttl":   []interface{}{"5s"},
+			"authorization-webhook-cache-unauthorized-ttl": []interface{}{"0s"},
+		},
+	}
+
+	tests := []struct {
+		name          string
+		tuningConfig  string
+		input         map[string]interface{}
+		expected      map[string]interface{}
+		expectedError bool
+	}{
+		{
+			name:     "no tuning configmap",
+			input:    map[string]interface{}{},
+			expected: map[string]interface{}{},
+		},
+		{
+			name:         "all set, the unauthorized requests are not cached",
+			tuningConfig: "authWebhookCache:\n  tokenTTL: 2s\n  authorizedTTL: 5s\n  unauthorizedTTL: 0s",
+			input:        map[string]interface{}{},
+			expected:     cacheConfig,
+		},
+		{
+			name:         "only the token",
+			tuningConfig: "authWebhookCache:\n  tokenTTL: 1s",
+			input:        cacheConfig,
+			expected: map[string]interface{}{
+				"extendedArguments": map[string]interface{}{
+					"authentication-token-webhook-cache-ttl": []interface{}{"1s"},
+				},
+			},
+		},
+		{
+			name:     "removed",
+			input:    cacheConfig,
+			expected: map[string]interface{}{},
+		},
+		{
+			name:          "too long TTL keeps the previous config",
+			tuningConfig:  "authWebhookCache:\n  authorizedTTL: 1h",
+			input:         cacheConfig,
+			expected:      cacheConfig,
+			expectedError: true,
+		},
+		{
+			name:          "negative TTL keeps the previous config",
+			tuningConfig:  "authWebhookCache:\n  tokenTTL: -1s",
+			input:         cacheConfig,
+			expected:      cacheConfig,
+			expectedError: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			indexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{})
+			if len(test.tuningConfig) > 0 {
+				if err := indexer.Add(&corev1.ConfigMap{
+					ObjectMeta: metav1.ObjectMeta{Namespace: operatorclient.OperatorNamespace, Name: tuning.ConfigMapName},
+					Data:       map[string]string{tuning.ConfigKey: test.tuningConfig},
+				}); err != nil {
+					t.Fatal(err)
+				}
+			}
+			listers := configobservation.Listers{
+				ConfigMapLister_: corev1listers.NewConfigMapLister(indexer),
+			}
+
+			result, errs := ObserveAuthWebhookCache(listers, events.NewInMemoryRecorder("authcache"), test.input)
+			if test.expectedError != (len(errs) > 0) {
+				t.Fatalf("expected error %v, got %v", test.expectedError, errs)
+			}
+			if !reflect.DeepEqual(test.expected, result) {
+				t.Errorf("expected %v, got %v", test.expected, result)
+			}
+		})
+	}
+}
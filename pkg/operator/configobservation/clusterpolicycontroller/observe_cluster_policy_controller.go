@@ -0,0 +1,72 @@
+package clusterpolicycontroller
+
+import (
+	"reflect"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/openshift/library-go/pkg/operator/configobserver"
+	"github.com/openshift/library-go/pkg/operator/events"
+
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/configobservation"
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/tuning"
+)
+
+var (
+	resourceQuotaConcurrentSyncsPath = []string{"resourceQuota", "concurrentSyncs"}
+	resourceQuotaSyncPeriodPath      = []string{"resourceQuota", "syncPeriod"}
+	resourceQuotaMinResyncPeriodPath = []string{"resourceQuota", "minResyncPeriod"}
+)
+
+// ObserveClusterPolicyControllerConfig fills in the cluster-policy-controller settings selected in the tuning
+// configmap. They are only kept in the cluster-policy-controller config, the kube-controller-manager config prunes
+// them away.
+func ObserveClusterPolicyControllerConfig(genericListers configobserver.Listers, recorder events.Recorder, existingConfig map[string]interface{}) (map[string]interface{}, []error) {
+	listers := genericListers.(configobservation.Listers)
+	errs := []error{}
+
+	previouslyObservedConfig := map[string]interface{}{}
+	for _, path := range [][]string{resourceQuotaConcurrentSyncsPath, resourceQuotaSyncPeriodPath, resourceQuotaMinResyncPeriodPath} {
+		value, found, err := unstructured.NestedFieldCopy(existingConfig, path...)
+		if err != nil {
+			errs = append(errs, err)
+		}
+		if !found {
+			continue
+		}
+		if err := unstructured.SetNestedField(previouslyObservedConfig, value, path...); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	tuningConfig, err := tuning.Get(listers.ConfigMapLister())
+	if err != nil {
+		return previouslyObservedConfig, append(errs, err)
+	}
+
+	observedConfig := map[string]interface{}{}
+	cpcConfig := tuningConfig.ClusterPolicyController
+	if cpcConfig.ResourceQuotaConcurrentSyncs > 0 {
+		if err := unstructured.SetNestedField(observedConfig, int64(cpcConfig.ResourceQuotaConcurrentSyncs), resourceQuotaConcurrentSyncsPath...); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	if cpcConfig.ResourceQuotaSyncPeriod != nil {
+		if err := unstructured.SetNestedField(observedConfig, cpcConfig.ResourceQuotaSyncPeriod.Duration.String(), resourceQuotaSyncPeriodPath...); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	if cpcConfig.ResourceQuotaMinResyncPeriod != nil {
+		if err := unstructured.SetNestedField(observedConfig, cpcConfig.ResourceQuotaMinResyncPeriod.Duration.String(), resourceQuotaMinResyncPeriodPath...); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	if len(errs) > 0 {
+		return previouslyObservedConfig, errs
+	}
+
+	if !reflect.DeepEqual(previouslyObservedConfig, observedConfig) {
+		recorder.Eventf("ObserveClusterPolicyControllerConfig", "cluster-policy-controller resource quota config changed to %v", observedConfig["resourceQuota"])
+	}
+	return observedConfig, errs
+}
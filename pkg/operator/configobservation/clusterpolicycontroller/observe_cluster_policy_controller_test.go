@@ -0,0 +1,88 @@
+package clusterpolicycontroller
+
+import (
+	"reflect"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	corev1listers "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/tools/cache"
+
+	"github.com/openshift/library-go/pkg/operator/events"
+
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/configobservation"
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/operatorclient"
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/tuning"
+)
+
+func TestObserveClusterPolicyControllerConfig(t *testing.T) {
+	quotaConfig := map[string]interface{}{
+		"resourceQuota": map[string]interface{}{
+			"concurrentSyncs": int64(10),
+			"syncPeriod":      "10m0s",
+			"minResyncPeriod": "5m0s",
+		},
+	}
+
+	tests := []struct {
+		name          string
+		tuningConfig  string
+		input         map[string]interface{}
+		expected      map[string]interface{}
+		expectedError bool
+	}{
+		{
+			name:     "no tuning configmap",
+			input:    map[string]interface{}{},
+			expected: map[string]interface{}{},
+		},
+		{
+			name: "resource quota",
+			tuningConfig: `clusterPolicyController:
+  resourceQuotaConcurrentSyncs: 10
+  resourceQuotaSyncPeriod: 10m
+  resourceQuotaMinResyncPeriod: 5m`,
+			input:    map[string]interface{}{},
+			expected: quotaConfig,
+		},
+		{
+			name:     "removed settings",
+			input:    quotaConfig,
+			expected: map[string]interface{}{},
+		},
+		{
+			name: "invalid period keeps the previous config",
+			tuningConfig: `clusterPolicyController:
+  resourceQuotaSyncPeriod: 0s`,
+			input:         quotaConfig,
+			expected:      quotaConfig,
+			expectedError: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			indexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{})
+			if len(test.tuningConfig) > 0 {
+				if err := indexer.Add(&corev1.ConfigMap{
+					ObjectMeta: metav1.ObjectMeta{Namespace: operatorclient.OperatorNamespace, Name: tuning.ConfigMapName},
+					Data:       map[string]string{tuning.ConfigKey: test.tuningConfig},
+				}); err != nil {
+					t.Fatal(err)
+				}
+			}
+			listers := configobservation.Listers{
+				ConfigMapLister_: corev1listers.NewConfigMapLister(indexer),
+			}
+
+			result, errs := ObserveClusterPolicyControllerConfig(listers, events.NewInMemoryRecorder("clusterpolicycontroller"), test.input)
+			if test.expectedError != (len(errs) > 0) {
+				t.Fatalf("expected error %v, got %v", test.expectedError, errs)
+			}
+			if !reflect.DeepEqual(test.expected, result) {
+				t.Errorf("expected %v, got %v", test.expected, result)
+			}
+		})
+	}
+}
@@ -0,0 +1,56 @@
+package clusterpolicycontroller
+
+import (
+	"reflect"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/openshift/library-go/pkg/operator/configobserver"
+	"github.com/openshift/library-go/pkg/operator/events"
+
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/configobservation"
+)
+
+var internalRegistryHostnamePath = []string{"dockerPullSecret", "internalRegistryHostname"}
+
+// ObserveInternalRegistryHostname fills in the hostname of the internal image registry published in
+// images.config.openshift.io/cluster, so the cluster-policy-controller recognizes the image references to the
+// internal registry without an unsupportedConfigOverrides workaround. The kube-controller-manager config prunes it
+// away.
+func ObserveInternalRegistryHostname(genericListers configobserver.Listers, recorder events.Recorder, existingConfig map[string]interface{}) (map[string]interface{}, []error) {
+	listers := genericListers.(configobservation.Listers)
+	errs := []error{}
+
+	previouslyObservedConfig := map[string]interface{}{}
+	if value, _, _ := unstructured.NestedString(existingConfig, internalRegistryHostnamePath...); len(value) > 0 {
+		if err := unstructured.SetNestedField(previouslyObservedConfig, value, internalRegistryHostnamePath...); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	hostname := ""
+	imageConfig, err := listers.ImageConfigLister.Get("cluster")
+	switch {
+	case errors.IsNotFound(err):
+	case err != nil:
+		return previouslyObservedConfig, append(errs, err)
+	default:
+		hostname = imageConfig.Status.InternalRegistryHostname
+	}
+
+	observedConfig := map[string]interface{}{}
+	if len(hostname) > 0 {
+		if err := unstructured.SetNestedField(observedConfig, hostname, internalRegistryHostnamePath...); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	if len(errs) > 0 {
+		return previouslyObservedConfig, errs
+	}
+
+	if !reflect.DeepEqual(previouslyObservedConfig, observedConfig) {
+		recorder.Eventf("ObserveInternalRegistryHostname", "Internal registry hostname changed to %q", hostname)
+	}
+	return observedConfig, errs
+}
@@ -0,0 +1,73 @@
+package clusterpolicycontroller
+
+import (
+	"reflect"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/cache"
+
+	configv1 "github.com/openshift/api/config/v1"
+	configlistersv1 "github.com/openshift/client-go/config/listers/config/v1"
+	"github.com/openshift/library-go/pkg/operator/events"
+
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/configobservation"
+)
+
+func TestObserveInternalRegistryHostname(t *testing.T) {
+	registryConfig := map[string]interface{}{
+		"dockerPullSecret": map[string]interface{}{
+			"internalRegistryHostname": "image-registry.openshift-image-registry.svc:5000",
+		},
+	}
+
+	tests := []struct {
+		name        string
+		imageConfig *configv1.Image
+		input       map[string]interface{}
+		expected    map[string]interface{}
+	}{
+		{
+			name:     "no image config",
+			input:    registryConfig,
+			expected: map[string]interface{}{},
+		},
+		{
+			name: "internal registry",
+			imageConfig: &configv1.Image{
+				ObjectMeta: metav1.ObjectMeta{Name: "cluster"},
+				Status:     configv1.ImageStatus{InternalRegistryHostname: "image-registry.openshift-image-registry.svc:5000"},
+			},
+			input:    map[string]interface{}{},
+			expected: registryConfig,
+		},
+		{
+			name:        "registry removed",
+			imageConfig: &configv1.Image{ObjectMeta: metav1.ObjectMeta{Name: "cluster"}},
+			input:       registryConfig,
+			expected:    map[string]interface{}{},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			indexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{})
+			if test.imageConfig != nil {
+				if err := indexer.Add(test.imageConfig); err != nil {
+					t.Fatal(err)
+				}
+			}
+			listers := configobservation.Listers{
+				ImageConfigLister: configlistersv1.NewImageLister(indexer),
+			}
+
+			result, errs := ObserveInternalRegistryHostname(listers, events.NewInMemoryRecorder("clusterpolicycontroller"), test.input)
+			if len(errs) > 0 {
+				t.Fatal(errs)
+			}
+			if !reflect.DeepEqual(test.expected, result) {
+				t.Errorf("expected %v, got %v", test.expected, result)
+			}
+		})
+	}
+}
@@ -19,11 +19,23 @@ import (
 	"github.com/openshift/library-go/pkg/operator/v1helpers"
 
 	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/configobservation"
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/configobservation/authcache"
 	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/configobservation/cloud"
 	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/configobservation/clustername"
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/configobservation/clusterpolicycontroller"
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/configobservation/controllers"
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/configobservation/csrsigner"
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/configobservation/failover"
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/configobservation/garbagecollector"
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/configobservation/logging"
 	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/configobservation/network"
 	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/configobservation/node"
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/configobservation/profiling"
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/configobservation/secureport"
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/configobservation/serviceaccount"
 	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/configobservation/serviceca"
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/configobservation/storage"
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/configobservation/workload"
 	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/operatorclient"
 )
 
@@ -45,17 +57,12 @@ func NewConfigObserver(
 	kubeInformersForNamespaces v1helpers.KubeInformersForNamespaces,
 	resourceSyncer resourcesynccontroller.ResourceSyncer,
 	featureGateAccessor featuregates.FeatureGateAccess,
+	securePort int32,
 	eventRecorder events.Recorder,
 ) (*ConfigObserver, error) {
 
-	interestingNamespaces := []string{
-		operatorclient.GlobalUserSpecifiedConfigNamespace,
-		operatorclient.GlobalMachineSpecifiedConfigNamespace,
-		operatorclient.TargetNamespace,
-		operatorclient.OperatorNamespace,
-	}
 	configMapPreRunCacheSynced := []cache.InformerSynced{}
-	for _, ns := range interestingNamespaces {
+	for _, ns := range operatorclient.ConfigNamespaces {
 		configMapPreRunCacheSynced = append(configMapPreRunCacheSynced, kubeInformersForNamespaces.InformersFor(ns).Core().V1().ConfigMaps().Informer().HasSynced)
 	}
 
@@ -66,8 +73,9 @@ func NewConfigObserver(
 		configinformers.Config().V1().Networks().Informer(),
 		configinformers.Config().V1().Nodes().Informer(),
 		configinformers.Config().V1().Proxies().Informer(),
+		configinformers.Config().V1().Images().Informer(),
 	}
-	for _, ns := range interestingNamespaces {
+	for _, ns := range operatorclient.ConfigNamespaces {
 		informers = append(informers, kubeInformersForNamespaces.InformersFor(ns).Core().V1().ConfigMaps().Informer())
 	}
 
@@ -98,6 +106,9 @@ func NewConfigObserver(
 				NodeLister_:           configinformers.Config().V1().Nodes().Lister(),
 				ProxyLister_:          configinformers.Config().V1().Proxies().Lister(),
 				APIServerLister_:      configinformers.Config().V1().APIServers().Lister(),
+				ImageConfigLister:     configinformers.Config().V1().Images().Lister(),
+				// nodes are not watched, their status changes too often. The node count is picked up on resync.
+				KubeNodeLister: kubeInformersForNamespaces.InformersFor("").Core().V1().Nodes().Lister(),
 
 				ResourceSync:     resourceSyncer,
 				ConfigMapLister_: kubeInformersForNamespaces.ConfigMapLister(),
@@ -106,12 +117,14 @@ func NewConfigObserver(
 
 					kubeInformersForNamespaces.InformersFor(operatorclient.GlobalUserSpecifiedConfigNamespace).Core().V1().ConfigMaps().Informer().HasSynced,
 					kubeInformersForNamespaces.InformersFor(operatorclient.TargetNamespace).Core().V1().ConfigMaps().Informer().HasSynced,
+					kubeInformersForNamespaces.InformersFor("").Core().V1().Nodes().Informer().HasSynced,
 
 					configinformers.Config().V1().FeatureGates().Informer().HasSynced,
 					configinformers.Config().V1().Infrastructures().Informer().HasSynced,
 					configinformers.Config().V1().Networks().Informer().HasSynced,
 					configinformers.Config().V1().Nodes().Informer().HasSynced,
 					configinformers.Config().V1().Proxies().Informer().HasSynced,
+					configinformers.Config().V1().Images().Informer().HasSynced,
 				),
 			},
 			informers,
@@ -139,6 +152,7 @@ func NewConfigObserver(
 			),
 			network.ObserveClusterCIDRs,
 			network.ObserveServiceClusterIPRanges,
+			network.ObserveNodeCIDRAllocation,
 			nodeobserver.NewLatencyProfileObserver(
 				node.LatencyConfigs,
 				[]nodeobserver.ShouldSuppressConfigUpdatesFunc{
@@ -152,9 +166,22 @@ func NewConfigObserver(
 			),
 			proxy.NewProxyObserveFunc([]string{"targetconfigcontroller", "proxy"}),
 			serviceca.ObserveServiceCA,
+			serviceaccount.ObserveRootCAFile,
 			clustername.ObserveInfraID,
 			libgoapiserver.ObserveTLSSecurityProfile,
 			cloud.NewObserveCloudVolumePluginFunc(),
+			workload.ObserveWorkloadProfile,
+			logging.ObserveLoggingFormat,
+			profiling.ObserveProfiling,
+			clusterpolicycontroller.ObserveClusterPolicyControllerConfig,
+			clusterpolicycontroller.ObserveInternalRegistryHostname,
+			failover.ObserveFastFailover,
+			storage.ObserveStorageTuning,
+			garbagecollector.ObserveGarbageCollectorTuning,
+			csrsigner.ObserveCSRSigner,
+			authcache.ObserveAuthWebhookCache,
+			controllers.ObserveDisabledControllers,
+			secureport.NewObserveSecurePortFunc(securePort),
 		),
 	}
 
@@ -0,0 +1,80 @@
+package controllers
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/util/sets"
+
+	"github.com/openshift/library-go/pkg/operator/configobserver"
+	"github.com/openshift/library-go/pkg/operator/events"
+
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/configobservation"
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/tuning"
+)
+
+// defaultControllers is the controllers argument of the default config, the observed argument replaces it as a whole.
+var defaultControllers = []string{"*", "-ttl", "-bootstrapsigner", "-tokencleaner"}
+
+var controllersPath = []string{"extendedArguments", "controllers"}
+
+// ObserveDisabledControllers fills in the controllers extended argument when the tuning configmap disables some of the
+// kube-controller-manager controllers, and warns about what the cluster loses while they are off.
+func ObserveDisabledControllers(genericListers configobserver.Listers, recorder events.Recorder, existingConfig map[string]interface{}) (map[string]interface{}, []error) {
+	listers := genericListers.(configobservation.Listers)
+	errs := []error{}
+
+	previouslyObservedConfig := map[string]interface{}{}
+	if value, _, _ := unstructured.NestedStringSlice(existingConfig, controllersPath...); len(value) > 0 {
+		if err := unstructured.SetNestedStringSlice(previouslyObservedConfig, value, controllersPath...); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	tuningConfig, err := tuning.Get(listers.ConfigMapLister())
+	if err != nil {
+		return previouslyObservedConfig, append(errs, err)
+	}
+
+	observedConfig := map[string]interface{}{}
+	if len(tuningConfig.DisabledControllers) > 0 {
+		if err := unstructured.SetNestedStringSlice(observedConfig, controllersArgument(tuningConfig.DisabledControllers), controllersPath...); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	if len(errs) > 0 {
+		return previouslyObservedConfig, errs
+	}
+
+	if !reflect.DeepEqual(previouslyObservedConfig, observedConfig) {
+		if len(tuningConfig.DisabledControllers) == 0 {
+			recorder.Eventf("ObserveDisabledControllers", "All the default kube-controller-manager controllers are enabled")
+		} else {
+			disabled := sets.List(sets.New[string](tuningConfig.DisabledControllers...))
+			warnings := make([]string, 0, len(disabled))
+			for _, controller := range disabled {
+				warnings = append(warnings, fmt.Sprintf("%s: %s", controller, tuning.DisableableControllers[controller]))
+			}
+			recorder.Warningf("ControllersDisabled", "Disabled kube-controller-manager controllers %s: %s", strings.Join(disabled, ", "), strings.Join(warnings, "; "))
+		}
+	}
+	return observedConfig, errs
+}
+
+// controllersArgument returns the default controllers with the given ones turned off as well.
+func controllersArgument(disabled []string) []string {
+	argument := append([]string{}, defaultControllers...)
+	existing := sets.New[string](defaultControllers...)
+	extra := []string{}
+	for _, controller := range disabled {
+		if !existing.Has("-" + controller) {
+			extra = append(extra, "-"+controller)
+			existing.Insert("-" + controller)
+		}
+	}
+	sort.Strings(extra)
+	return append(argument, extra...)
+}
@@ -0,0 +1,109 @@
+package controllers
+
+import (
+	"reflect"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	corev1listers "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/tools/cache"
+
+	"github.com/openshift/library-go/pkg/operator/events"
+
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/configobservation"
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/operatorclient"
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/tuning"
+)
+
+func TestObserveDisabledControllers(t *testing.T) {
+	controllersConfig := func(controllers ...interface{}) map[string]interface{} {
+		return map[string]interface{}{
+			"extendedArguments": map[string]interface{}{
+				"controllers": controllers,
+			},
+		}
+	}
+
+	tests := []struct {
+		name            string
+		tuningConfig    string
+		input           map[string]interface{}
+		expected        map[string]interface{}
+		expectedError   bool
+		expectedWarning bool
+	}{
+		{
+			name:     "no tuning configmap",
+			input:    map[string]interface{}{},
+			expected: map[string]interface{}{},
+		},
+		{
+			name:            "nodeipam disabled",
+			tuningConfig:    "disabledControllers: [nodeipam]",
+			input:           map[string]interface{}{},
+			expected:        controllersConfig("*", "-ttl", "-bootstrapsigner", "-tokencleaner", "-nodeipam"),
+			expectedWarning: true,
+		},
+		{
+			name:            "controllers disabled by default are not repeated",
+			tuningConfig:    "disabledControllers: [ttl, route, nodeipam]",
+			input:           map[string]interface{}{},
+			expected:        controllersConfig("*", "-ttl", "-bootstrapsigner", "-tokencleaner", "-nodeipam", "-route"),
+			expectedWarning: true,
+		},
+		{
+			name:         "unchanged",
+			tuningConfig: "disabledControllers: [nodeipam]",
+			input:        controllersConfig("*", "-ttl", "-bootstrapsigner", "-tokencleaner", "-nodeipam"),
+			expected:     controllersConfig("*", "-ttl", "-bootstrapsigner", "-tokencleaner", "-nodeipam"),
+		},
+		{
+			name:     "re-enabled",
+			input:    controllersConfig("*", "-ttl", "-bootstrapsigner", "-tokencleaner", "-nodeipam"),
+			expected: map[string]interface{}{},
+		},
+		{
+			name:          "controller outside the allow-list keeps the previous config",
+			tuningConfig:  "disabledControllers: [serviceaccount-token]",
+			input:         controllersConfig("*", "-ttl", "-bootstrapsigner", "-tokencleaner", "-nodeipam"),
+			expected:      controllersConfig("*", "-ttl", "-bootstrapsigner", "-tokencleaner", "-nodeipam"),
+			expectedError: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			indexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{})
+			if len(test.tuningConfig) > 0 {
+				if err := indexer.Add(&corev1.ConfigMap{
+					ObjectMeta: metav1.ObjectMeta{Namespace: operatorclient.OperatorNamespace, Name: tuning.ConfigMapName},
+					Data:       map[string]string{tuning.ConfigKey: test.tuningConfig},
+				}); err != nil {
+					t.Fatal(err)
+				}
+			}
+			listers := configobservation.Listers{
+				ConfigMapLister_: corev1listers.NewConfigMapLister(indexer),
+			}
+
+			recorder := events.NewInMemoryRecorder("controllers")
+			result, errs := ObserveDisabledControllers(listers, recorder, test.input)
+			if test.expectedError != (len(errs) > 0) {
+				t.Fatalf("expected error %v, got %v", test.expectedError, errs)
+			}
+			if !reflect.DeepEqual(test.expected, result) {
+				t.Errorf("expected %v, got %v", test.expected, result)
+			}
+			warned := false
+			for _, event := range recorder.Events() {
+				if event.Type == corev1.EventTypeWarning && event.Reason == "ControllersDisabled" {
+					warned = true
+				}
+			}
+			if warned != test.expectedWarning {
+				t.Errorf("expected warning %v, got %v", test.expectedWarning, recorder.Events())
+			}
+		})
+	}
+}
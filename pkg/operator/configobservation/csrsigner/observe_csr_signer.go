@@ -0,0 +1,85 @@
+package csrsigner
+
+import (
+	"reflect"
+	"time"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/klog/v2"
+
+	"github.com/openshift/library-go/pkg/operator/configobserver"
+	"github.com/openshift/library-go/pkg/operator/events"
+
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/configobservation"
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/csrsignerprovider"
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/targetconfigcontroller"
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/tuning"
+)
+
+var (
+	certFilePath = []string{"extendedArguments", "cluster-signing-cert-file"}
+	keyFilePath  = []string{"extendedArguments", "cluster-signing-key-file"}
+)
+
+// ObserveCSRSigner points the kube-controller-manager at the signer files of the external provider of the csrSigner
+// section of the tuning configmap. The switch waits until the client CA bundle of the kube-apiserver trusts the
+// certificate of the provider, the certificates issued before would be rejected. Without a provider the files of the
+// csr-signer of the operator in the default config are used.
+func ObserveCSRSigner(genericListers configobserver.Listers, recorder events.Recorder, existingConfig map[string]interface{}) (map[string]interface{}, []error) {
+	listers := genericListers.(configobservation.Listers)
+	errs := []error{}
+
+	previouslyObservedConfig := map[string]interface{}{}
+	for _, path := range [][]string{certFilePath, keyFilePath} {
+		if value, _, _ := unstructured.NestedStringSlice(existingConfig, path...); len(value) > 0 {
+			if err := unstructured.SetNestedStringSlice(previouslyObservedConfig, value, path...); err != nil {
+				errs = append(errs, err)
+			}
+		}
+	}
+
+	tuningConfig, err := tuning.Get(listers.ConfigMapLister())
+	if err != nil {
+		return previouslyObservedConfig, append(errs, err)
+	}
+
+	observedConfig := map[string]interface{}{}
+	provider := csrsignerprovider.New(tuningConfig.CSRSigner)
+	if provider != nil {
+		certificate, err := provider.Certificate(listers.ConfigMapLister())
+		if err != nil {
+			return previouslyObservedConfig, append(errs, err)
+		}
+		certFile, keyFile := provider.Files()
+		if value, _, _ := unstructured.NestedStringSlice(previouslyObservedConfig, keyFilePath...); len(value) == 0 || value[0] != keyFile {
+			trusted, err := targetconfigcontroller.IsTrustedByKubeAPIServer(listers.ConfigMapLister(), certificate, time.Now())
+			if err != nil {
+				return previouslyObservedConfig, append(errs, err)
+			}
+			if !trusted {
+				// the target config controller publishes the certificate, the configmap informer requeues once the
+				// kube-apiserver trusts it
+				klog.V(2).Infof("Waiting for the kube-apiserver to trust the csr-signer of provider %s", provider.Name())
+				return previouslyObservedConfig, errs
+			}
+		}
+		if err := unstructured.SetNestedStringSlice(observedConfig, []string{certFile}, certFilePath...); err != nil {
+			errs = append(errs, err)
+		}
+		if err := unstructured.SetNestedStringSlice(observedConfig, []string{keyFile}, keyFilePath...); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	if len(errs) > 0 {
+		return previouslyObservedConfig, errs
+	}
+
+	if !reflect.DeepEqual(previouslyObservedConfig, observedConfig) {
+		if provider != nil {
+			recorder.Eventf("ObserveCSRSigner", "The kube-controller-manager signs with the csr-signer of provider %s", provider.Name())
+		} else {
+			recorder.Eventf("ObserveCSRSigner", "The kube-controller-manager signs with the csr-signer of the operator")
+		}
+	}
+	return observedConfig, errs
+}
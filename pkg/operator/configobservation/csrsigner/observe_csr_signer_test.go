@@ -0,0 +1,140 @@
+package csrsigner
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	corev1listers "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/tools/cache"
+
+	"github.com/openshift/library-go/pkg/crypto"
+	"github.com/openshift/library-go/pkg/operator/events"
+
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/configobservation"
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/operatorclient"
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/tuning"
+)
+
+func TestObserveCSRSigner(t *testing.T) {
+	signerConfig, err := crypto.MakeSelfSignedCAConfigForDuration("external-signer", time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	signerCert, _, err := signerConfig.GetPEMBytes()
+	if err != nil {
+		t.Fatal(err)
+	}
+	otherConfig, err := crypto.MakeSelfSignedCAConfigForDuration("other", time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	otherCert, _, err := otherConfig.GetPEMBytes()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	providerConfig := map[string]interface{}{
+		"extendedArguments": map[string]interface{}{
+			"cluster-signing-cert-file": []interface{}{"/etc/kubernetes/csr-signer-provider/tls.crt"},
+			"cluster-signing-key-file":  []interface{}{"/etc/kubernetes/csr-signer-provider/tls.key"},
+		},
+	}
+	hostPathTuning := "csrSigner: {provider: HostPath, hostPath: /var/run/csr-signer, certificateConfigMap: external-signer}"
+
+	tests := []struct {
+		name          string
+		tuningConfig  string
+		certificate   []byte
+		clientCA      []byte
+		input         map[string]interface{}
+		expected      map[string]interface{}
+		expectedError bool
+	}{
+		{
+			name:     "no provider",
+			input:    map[string]interface{}{},
+			expected: map[string]interface{}{},
+		},
+		{
+			name:         "trusted provider",
+			tuningConfig: hostPathTuning,
+			certificate:  signerCert,
+			clientCA:     signerCert,
+			input:        map[string]interface{}{},
+			expected:     providerConfig,
+		},
+		{
+			name:         "provider not trusted yet",
+			tuningConfig: hostPathTuning,
+			certificate:  signerCert,
+			clientCA:     otherCert,
+			input:        map[string]interface{}{},
+			expected:     map[string]interface{}{},
+		},
+		{
+			name:         "the trust is only checked for the switch",
+			tuningConfig: hostPathTuning,
+			certificate:  signerCert,
+			clientCA:     otherCert,
+			input:        providerConfig,
+			expected:     providerConfig,
+		},
+		{
+			name:          "missing certificate configmap",
+			tuningConfig:  hostPathTuning,
+			clientCA:      signerCert,
+			input:         map[string]interface{}{},
+			expected:      map[string]interface{}{},
+			expectedError: true,
+		},
+		{
+			name:     "provider removed",
+			input:    providerConfig,
+			expected: map[string]interface{}{},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			indexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{})
+			configMaps := []*corev1.ConfigMap{}
+			if len(test.tuningConfig) > 0 {
+				configMaps = append(configMaps, &corev1.ConfigMap{
+					ObjectMeta: metav1.ObjectMeta{Namespace: operatorclient.OperatorNamespace, Name: tuning.ConfigMapName},
+					Data:       map[string]string{tuning.ConfigKey: test.tuningConfig},
+				})
+			}
+			if test.certificate != nil {
+				configMaps = append(configMaps, &corev1.ConfigMap{
+					ObjectMeta: metav1.ObjectMeta{Namespace: operatorclient.GlobalUserSpecifiedConfigNamespace, Name: "external-signer"},
+					Data:       map[string]string{"tls.crt": string(test.certificate)},
+				})
+			}
+			if test.clientCA != nil {
+				configMaps = append(configMaps, &corev1.ConfigMap{
+					ObjectMeta: metav1.ObjectMeta{Namespace: operatorclient.GlobalMachineSpecifiedConfigNamespace, Name: "kube-apiserver-client-ca"},
+					Data:       map[string]string{"ca-bundle.crt": string(test.clientCA)},
+				})
+			}
+			for _, configMap := range configMaps {
+				if err := indexer.Add(configMap); err != nil {
+					t.Fatal(err)
+				}
+			}
+			listers := configobservation.Listers{
+				ConfigMapLister_: corev1listers.NewConfigMapLister(indexer),
+			}
+
+			result, errs := ObserveCSRSigner(listers, events.NewInMemoryRecorder("csrsigner"), test.input)
+			if test.expectedError != (len(errs) > 0) {
+				t.Fatalf("expected error %v, got %v", test.expectedError, errs)
+			}
+			if !reflect.DeepEqual(test.expected, result) {
+				t.Errorf("expected %v, got %v", test.expected, result)
+			}
+		})
+	}
+}
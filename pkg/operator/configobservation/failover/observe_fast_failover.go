@@ -0,0 +1,75 @@
+package failover
+
+import (
+	"fmt"
+	"reflect"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	configv1 "github.com/openshift/api/config/v1"
+	"github.com/openshift/library-go/pkg/operator/configobserver"
+	"github.com/openshift/library-go/pkg/operator/events"
+
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/configobservation"
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/tuning"
+)
+
+// fastFailoverArguments are the extended arguments set by the fast failover option. The lease still outlasts the
+// renew deadline by a few retries, and the node monitor period stays well below the node-monitor-grace-period.
+var fastFailoverArguments = map[string]string{
+	// default 15s
+	"leader-elect-lease-duration": "10s",
+	// default 12s, see the default config
+	"leader-elect-renew-deadline": "8s",
+	// default 3s, see the default config
+	"leader-elect-retry-period": "2s",
+	// default 5s
+	"node-monitor-period": "2s",
+}
+
+// ObserveFastFailover fills in the leader election and node monitor extended arguments when fast failover is
+// selected in the tuning configmap. It is refused on clusters that declared a slow worker latency profile.
+func ObserveFastFailover(genericListers configobserver.Listers, recorder events.Recorder, existingConfig map[string]interface{}) (map[string]interface{}, []error) {
+	listers := genericListers.(configobservation.Listers)
+	errs := []error{}
+
+	previouslyObservedConfig := map[string]interface{}{}
+	for argument := range fastFailoverArguments {
+		path := []string{"extendedArguments", argument}
+		if value, _, _ := unstructured.NestedStringSlice(existingConfig, path...); len(value) > 0 {
+			if err := unstructured.SetNestedStringSlice(previouslyObservedConfig, value, path...); err != nil {
+				errs = append(errs, err)
+			}
+		}
+	}
+
+	tuningConfig, err := tuning.Get(listers.ConfigMapLister())
+	if err != nil {
+		return previouslyObservedConfig, append(errs, err)
+	}
+
+	observedConfig := map[string]interface{}{}
+	if tuningConfig.FastFailover {
+		nodeConfig, err := listers.NodeLister().Get("cluster")
+		if err != nil && !apierrors.IsNotFound(err) {
+			return previouslyObservedConfig, append(errs, err)
+		}
+		if nodeConfig != nil && len(nodeConfig.Spec.WorkerLatencyProfile) > 0 && nodeConfig.Spec.WorkerLatencyProfile != configv1.DefaultUpdateDefaultReaction {
+			return previouslyObservedConfig, append(errs, fmt.Errorf("fastFailover cannot be combined with the %s worker latency profile of nodes.config.openshift.io/cluster", nodeConfig.Spec.WorkerLatencyProfile))
+		}
+		for argument, value := range fastFailoverArguments {
+			if err := unstructured.SetNestedStringSlice(observedConfig, []string{value}, "extendedArguments", argument); err != nil {
+				errs = append(errs, err)
+			}
+		}
+	}
+	if len(errs) > 0 {
+		return previouslyObservedConfig, errs
+	}
+
+	if !reflect.DeepEqual(previouslyObservedConfig, observedConfig) {
+		recorder.Eventf("ObserveFastFailover", "Fast failover changed to %t", tuningConfig.FastFailover)
+	}
+	return observedConfig, errs
+}
@@ -0,0 +1,113 @@
+package failover
+
+import (
+	"reflect"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	corev1listers "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/tools/cache"
+
+	configv1 "github.com/openshift/api/config/v1"
+	configlistersv1 "github.com/openshift/client-go/config/listers/config/v1"
+	"github.com/openshift/library-go/pkg/operator/events"
+
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/configobservation"
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/operatorclient"
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/tuning"
+)
+
+func TestObserveFastFailover(t *testing.T) {
+	fastConfig := map[string]interface{}{
+		"extendedArguments": map[string]interface{}{
+			"leader-elect-lease-duration": []interface{}{"10s"},
+			"leader-elect-renew-deadline": []interface{}{"8s"},
+			"leader-elect-retry-period":   []interface{}{"2s"},
+			"node-monitor-period":         []interface{}{"2s"},
+		},
+	}
+
+	tests := []struct {
+		name                 string
+		tuningConfig         string
+		workerLatencyProfile configv1.WorkerLatencyProfileType
+		input                map[string]interface{}
+		expected             map[string]interface{}
+		expectedError        bool
+	}{
+		{
+			name:     "no tuning configmap",
+			input:    map[string]interface{}{},
+			expected: map[string]interface{}{},
+		},
+		{
+			name:         "fast failover",
+			tuningConfig: "fastFailover: true",
+			input:        map[string]interface{}{},
+			expected:     fastConfig,
+		},
+		{
+			name:                 "fast failover with the default worker latency profile",
+			tuningConfig:         "fastFailover: true",
+			workerLatencyProfile: configv1.DefaultUpdateDefaultReaction,
+			input:                map[string]interface{}{},
+			expected:             fastConfig,
+		},
+		{
+			name:     "disabled",
+			input:    fastConfig,
+			expected: map[string]interface{}{},
+		},
+		{
+			name:                 "slow worker latency profile keeps the previous config",
+			tuningConfig:         "fastFailover: true",
+			workerLatencyProfile: configv1.MediumUpdateAverageReaction,
+			input:                map[string]interface{}{},
+			expected:             map[string]interface{}{},
+			expectedError:        true,
+		},
+		{
+			name:          "slow storage keeps the previous config",
+			tuningConfig:  "fastFailover: true\nprobeProfile: SlowStorage",
+			input:         fastConfig,
+			expected:      fastConfig,
+			expectedError: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			configMapIndexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{})
+			if len(test.tuningConfig) > 0 {
+				if err := configMapIndexer.Add(&corev1.ConfigMap{
+					ObjectMeta: metav1.ObjectMeta{Namespace: operatorclient.OperatorNamespace, Name: tuning.ConfigMapName},
+					Data:       map[string]string{tuning.ConfigKey: test.tuningConfig},
+				}); err != nil {
+					t.Fatal(err)
+				}
+			}
+			nodeIndexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{})
+			if len(test.workerLatencyProfile) > 0 {
+				if err := nodeIndexer.Add(&configv1.Node{
+					ObjectMeta: metav1.ObjectMeta{Name: "cluster"},
+					Spec:       configv1.NodeSpec{WorkerLatencyProfile: test.workerLatencyProfile},
+				}); err != nil {
+					t.Fatal(err)
+				}
+			}
+			listers := configobservation.Listers{
+				ConfigMapLister_: corev1listers.NewConfigMapLister(configMapIndexer),
+				NodeLister_:      configlistersv1.NewNodeLister(nodeIndexer),
+			}
+
+			result, errs := ObserveFastFailover(listers, events.NewInMemoryRecorder("failover"), test.input)
+			if test.expectedError != (len(errs) > 0) {
+				t.Fatalf("expected error %v, got %v", test.expectedError, errs)
+			}
+			if !reflect.DeepEqual(test.expected, result) {
+				t.Errorf("expected %v, got %v", test.expected, result)
+			}
+		})
+	}
+}
@@ -0,0 +1,129 @@
+package garbagecollector
+
+import (
+	"strconv"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/openshift/library-go/pkg/operator/configobserver"
+	"github.com/openshift/library-go/pkg/operator/events"
+
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/configobservation"
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/objectcountcontroller"
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/tuning"
+)
+
+var concurrentSyncsPath = []string{"extendedArguments", "concurrent-gc-syncs"}
+
+// profileConcurrentSyncs are the garbage collector workers of the workload profiles that change them.
+var profileConcurrentSyncs = map[tuning.WorkloadProfile]int32{
+	// garbage collection of the objects left behind by the deleted test namespaces (default 20)
+	tuning.CIWorkloadProfile: 50,
+}
+
+// scalingSteps are the garbage collector workers AutoScale sets from the number of objects stored in the cluster. A
+// worker processes the dependents of one owner at a time, the default of 20 falls behind from about a million objects.
+var scalingSteps = []struct {
+	objects int64
+	syncs   int32
+}{
+	{objects: 0, syncs: 20},
+	{objects: 1000000, syncs: 40},
+	{objects: 2500000, syncs: 60},
+	{objects: 5000000, syncs: 80},
+	{objects: 10000000, syncs: 100},
+}
+
+// scaleDownMargin is how far below the object count of its step the count has to drop before AutoScale removes
+// workers again, so that a count around a step does not roll out a new revision on every measurement.
+const scaleDownMargin = 0.9
+
+// ObserveGarbageCollectorTuning fills in the garbage collector workers, from the garbageCollector section of the tuning
+// configmap, from the object count of the cluster with AutoScale, or from the workload profile.
+func ObserveGarbageCollectorTuning(genericListers configobserver.Listers, recorder events.Recorder, existingConfig map[string]interface{}) (map[string]interface{}, []error) {
+	listers := genericListers.(configobservation.Listers)
+	errs := []error{}
+
+	previouslyObservedConfig := map[string]interface{}{}
+	var previousSyncs int32
+	if value, _, _ := unstructured.NestedStringSlice(existingConfig, concurrentSyncsPath...); len(value) > 0 {
+		if err := unstructured.SetNestedStringSlice(previouslyObservedConfig, value, concurrentSyncsPath...); err != nil {
+			errs = append(errs, err)
+		}
+		if syncs, err := strconv.ParseInt(value[0], 10, 32); err == nil {
+			previousSyncs = int32(syncs)
+		}
+	}
+
+	tuningConfig, err := tuning.Get(listers.ConfigMapLister())
+	if err != nil {
+		return previouslyObservedConfig, append(errs, err)
+	}
+
+	syncs := profileConcurrentSyncs[tuningConfig.WorkloadProfile]
+	reason := "the workload profile"
+	var objects int64
+	switch {
+	case tuningConfig.GarbageCollector.ConcurrentSyncs > 0:
+		syncs = tuningConfig.GarbageCollector.ConcurrentSyncs
+		reason = "the tuning configmap"
+	case tuningConfig.GarbageCollector.AutoScale:
+		var counted bool
+		objects, counted, err = objectcountcontroller.Get(listers.ConfigMapLister())
+		if err != nil {
+			return previouslyObservedConfig, append(errs, err)
+		}
+		if !counted {
+			// the objects were not counted yet, keep the workers until they are
+			return previouslyObservedConfig, errs
+		}
+		syncs = scaledConcurrentSyncs(objects, previousSyncs, syncs, tuningConfig.GarbageCollector.MaxConcurrentSyncsOrDefault())
+	}
+
+	observedConfig := map[string]interface{}{}
+	if syncs > 0 {
+		if err := unstructured.SetNestedStringSlice(observedConfig, []string{strconv.Itoa(int(syncs))}, concurrentSyncsPath...); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	if len(errs) > 0 {
+		return previouslyObservedConfig, errs
+	}
+
+	switch {
+	case syncs == previousSyncs:
+	case syncs == 0:
+		recorder.Eventf("ObserveGarbageCollectorTuning", "Garbage collector workers reset to the kube-controller-manager default")
+	case tuningConfig.GarbageCollector.AutoScale:
+		recorder.Eventf("ObserveGarbageCollectorTuning", "Garbage collector workers changed to %d for %d objects", syncs, objects)
+	default:
+		recorder.Eventf("ObserveGarbageCollectorTuning", "Garbage collector workers changed to %d by %s", syncs, reason)
+	}
+	return observedConfig, errs
+}
+
+// scaledConcurrentSyncs returns the workers of the step of the object count, between the workers of the workload
+// profile and the maximum. The previous workers are kept while the count stays within scaleDownMargin of their step.
+func scaledConcurrentSyncs(objects int64, previous, minimum, maximum int32) int32 {
+	syncs := stepConcurrentSyncs(objects)
+	if syncs < previous && stepConcurrentSyncs(int64(float64(objects)/scaleDownMargin)) >= previous {
+		syncs = previous
+	}
+	if syncs < minimum {
+		syncs = minimum
+	}
+	if syncs > maximum {
+		syncs = maximum
+	}
+	return syncs
+}
+
+func stepConcurrentSyncs(objects int64) int32 {
+	var syncs int32
+	for _, step := range scalingSteps {
+		if objects >= step.objects {
+			syncs = step.syncs
+		}
+	}
+	return syncs
+}
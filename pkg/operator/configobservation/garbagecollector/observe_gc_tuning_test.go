@@ -0,0 +1,152 @@
+package garbagecollector
+
+import (
+	"reflect"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	corev1listers "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/tools/cache"
+
+	"github.com/openshift/library-go/pkg/operator/events"
+
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/configobservation"
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/objectcountcontroller"
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/operatorclient"
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/tuning"
+)
+
+func TestObserveGarbageCollectorTuning(t *testing.T) {
+	syncsConfig := func(syncs string) map[string]interface{} {
+		return map[string]interface{}{
+			"extendedArguments": map[string]interface{}{
+				"concurrent-gc-syncs": []interface{}{syncs},
+			},
+		}
+	}
+
+	tests := []struct {
+		name          string
+		tuningConfig  string
+		objects       string
+		input         map[string]interface{}
+		expected      map[string]interface{}
+		expectedError bool
+	}{
+		{
+			name:     "no tuning configmap",
+			input:    map[string]interface{}{},
+			expected: map[string]interface{}{},
+		},
+		{
+			name:         "ci profile",
+			tuningConfig: "workloadProfile: CI",
+			input:        map[string]interface{}{},
+			expected:     syncsConfig("50"),
+		},
+		{
+			name:         "set",
+			tuningConfig: "workloadProfile: CI\ngarbageCollector:\n  concurrentSyncs: 30",
+			input:        syncsConfig("50"),
+			expected:     syncsConfig("30"),
+		},
+		{
+			name:         "removed",
+			tuningConfig: "workloadProfile: Default",
+			input:        syncsConfig("30"),
+			expected:     map[string]interface{}{},
+		},
+		{
+			name:         "scaled",
+			tuningConfig: "garbageCollector:\n  autoScale: true",
+			objects:      "3000000",
+			input:        map[string]interface{}{},
+			expected:     syncsConfig("60"),
+		},
+		{
+			name:         "scaled up to the maximum",
+			tuningConfig: "garbageCollector:\n  autoScale: true\n  maxConcurrentSyncs: 50",
+			objects:      "30000000",
+			input:        syncsConfig("40"),
+			expected:     syncsConfig("50"),
+		},
+		{
+			name:         "not counted yet keeps the previous config",
+			tuningConfig: "garbageCollector:\n  autoScale: true",
+			input:        syncsConfig("40"),
+			expected:     syncsConfig("40"),
+		},
+		{
+			name:          "too many syncs keep the previous config",
+			tuningConfig:  "garbageCollector:\n  concurrentSyncs: 500",
+			input:         syncsConfig("40"),
+			expected:      syncsConfig("40"),
+			expectedError: true,
+		},
+		{
+			name:          "syncs with scaling keep the previous config",
+			tuningConfig:  "garbageCollector:\n  autoScale: true\n  concurrentSyncs: 40",
+			input:         syncsConfig("40"),
+			expected:      syncsConfig("40"),
+			expectedError: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			configMapIndexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{})
+			if len(test.tuningConfig) > 0 {
+				if err := configMapIndexer.Add(&corev1.ConfigMap{
+					ObjectMeta: metav1.ObjectMeta{Namespace: operatorclient.OperatorNamespace, Name: tuning.ConfigMapName},
+					Data:       map[string]string{tuning.ConfigKey: test.tuningConfig},
+				}); err != nil {
+					t.Fatal(err)
+				}
+			}
+			if len(test.objects) > 0 {
+				if err := configMapIndexer.Add(&corev1.ConfigMap{
+					ObjectMeta: metav1.ObjectMeta{Namespace: operatorclient.OperatorNamespace, Name: objectcountcontroller.ConfigMapName},
+					Data:       map[string]string{objectcountcontroller.ObjectsKey: test.objects},
+				}); err != nil {
+					t.Fatal(err)
+				}
+			}
+			listers := configobservation.Listers{
+				ConfigMapLister_: corev1listers.NewConfigMapLister(configMapIndexer),
+			}
+
+			result, errs := ObserveGarbageCollectorTuning(listers, events.NewInMemoryRecorder("gc"), test.input)
+			if test.expectedError != (len(errs) > 0) {
+				t.Fatalf("expected error %v, got %v", test.expectedError, errs)
+			}
+			if !reflect.DeepEqual(test.expected, result) {
+				t.Errorf("expected %v, got %v", test.expected, result)
+			}
+		})
+	}
+}
+
+func TestScaledConcurrentSyncs(t *testing.T) {
+	tests := []struct {
+		name     string
+		objects  int64
+		previous int32
+		minimum  int32
+		expected int32
+	}{
+		{name: "small cluster", objects: 200000, expected: 20},
+		{name: "first step", objects: 1000000, previous: 20, expected: 40},
+		{name: "just below the step keeps the workers", objects: 950000, previous: 40, expected: 40},
+		{name: "well below the step removes workers", objects: 850000, previous: 40, expected: 20},
+		{name: "profile minimum", objects: 1200000, minimum: 50, expected: 50},
+		{name: "largest step", objects: 50000000, expected: 100},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if actual := scaledConcurrentSyncs(test.objects, test.previous, test.minimum, tuning.MaxConcurrentGCSyncs); actual != test.expected {
+				t.Errorf("expected %d, got %d", test.expected, actual)
+			}
+		})
+	}
+}
@@ -19,6 +19,9 @@ type Listers struct {
 	ProxyLister_          configlistersv1.ProxyLister
 	ConfigMapLister_      corev1listers.ConfigMapLister
 	APIServerLister_      configlistersv1.APIServerLister
+	ImageConfigLister     configlistersv1.ImageLister
+	// KubeNodeLister counts the nodes the node CIDRs are sized for
+	KubeNodeLister corev1listers.NodeLister
 
 	ResourceSync       resourcesynccontroller.ResourceSyncer
 	PreRunCachesSynced []cache.InformerSynced
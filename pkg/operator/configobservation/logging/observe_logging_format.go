@@ -0,0 +1,53 @@
+package logging
+
+import (
+	"reflect"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/openshift/library-go/pkg/operator/configobserver"
+	"github.com/openshift/library-go/pkg/operator/events"
+
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/configobservation"
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/tuning"
+)
+
+var loggingFormatPath = []string{"extendedArguments", "logging-format"}
+
+// ObserveLoggingFormat fills in the logging-format extended argument for the format selected in the tuning
+// configmap. The text format is the kube-controller-manager default and is left unset.
+func ObserveLoggingFormat(genericListers configobserver.Listers, recorder events.Recorder, existingConfig map[string]interface{}) (map[string]interface{}, []error) {
+	listers := genericListers.(configobservation.Listers)
+	errs := []error{}
+
+	previouslyObservedConfig := map[string]interface{}{}
+	if value, _, _ := unstructured.NestedStringSlice(existingConfig, loggingFormatPath...); len(value) > 0 {
+		if err := unstructured.SetNestedStringSlice(previouslyObservedConfig, value, loggingFormatPath...); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	tuningConfig, err := tuning.Get(listers.ConfigMapLister())
+	if err != nil {
+		return previouslyObservedConfig, append(errs, err)
+	}
+
+	observedConfig := map[string]interface{}{}
+	if tuningConfig.LoggingFormat == tuning.JSONLoggingFormat {
+		if err := unstructured.SetNestedStringSlice(observedConfig, []string{string(tuning.JSONLoggingFormat)}, loggingFormatPath...); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	if len(errs) > 0 {
+		return previouslyObservedConfig, errs
+	}
+
+	if !reflect.DeepEqual(previouslyObservedConfig, observedConfig) {
+		format := tuningConfig.LoggingFormat
+		if len(format) == 0 {
+			format = tuning.TextLoggingFormat
+		}
+		recorder.Eventf("ObserveLoggingFormat", "Logging format changed to %q", format)
+	}
+	return observedConfig, errs
+}
@@ -0,0 +1,83 @@
+package logging
+
+import (
+	"reflect"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	corev1listers "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/tools/cache"
+
+	"github.com/openshift/library-go/pkg/operator/events"
+
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/configobservation"
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/operatorclient"
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/tuning"
+)
+
+func TestObserveLoggingFormat(t *testing.T) {
+	jsonConfig := map[string]interface{}{
+		"extendedArguments": map[string]interface{}{
+			"logging-format": []interface{}{"json"},
+		},
+	}
+
+	tests := []struct {
+		name          string
+		tuningConfig  string
+		input         map[string]interface{}
+		expected      map[string]interface{}
+		expectedError bool
+	}{
+		{
+			name:     "no tuning configmap",
+			input:    map[string]interface{}{},
+			expected: map[string]interface{}{},
+		},
+		{
+			name:         "text",
+			tuningConfig: "loggingFormat: text",
+			input:        jsonConfig,
+			expected:     map[string]interface{}{},
+		},
+		{
+			name:         "json",
+			tuningConfig: "loggingFormat: json",
+			input:        map[string]interface{}{},
+			expected:     jsonConfig,
+		},
+		{
+			name:          "invalid format keeps the previous config",
+			tuningConfig:  "loggingFormat: xml",
+			input:         jsonConfig,
+			expected:      jsonConfig,
+			expectedError: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			indexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{})
+			if len(test.tuningConfig) > 0 {
+				if err := indexer.Add(&corev1.ConfigMap{
+					ObjectMeta: metav1.ObjectMeta{Namespace: operatorclient.OperatorNamespace, Name: tuning.ConfigMapName},
+					Data:       map[string]string{tuning.ConfigKey: test.tuningConfig},
+				}); err != nil {
+					t.Fatal(err)
+				}
+			}
+			listers := configobservation.Listers{
+				ConfigMapLister_: corev1listers.NewConfigMapLister(indexer),
+			}
+
+			result, errs := ObserveLoggingFormat(listers, events.NewInMemoryRecorder("logging"), test.input)
+			if test.expectedError != (len(errs) > 0) {
+				t.Fatalf("expected error %v, got %v", test.expectedError, errs)
+			}
+			if !reflect.DeepEqual(test.expected, result) {
+				t.Errorf("expected %v, got %v", test.expected, result)
+			}
+		})
+	}
+}
@@ -1,15 +1,26 @@
 package network
 
 import (
+	"fmt"
+	"math"
+	"math/bits"
+	"net"
+	"reflect"
+	"strconv"
 	"strings"
 
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/sets"
 
+	configv1 "github.com/openshift/api/config/v1"
+	operatorv1 "github.com/openshift/api/operator/v1"
 	"github.com/openshift/library-go/pkg/operator/configobserver"
 	"github.com/openshift/library-go/pkg/operator/configobserver/network"
 	"github.com/openshift/library-go/pkg/operator/events"
 
 	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/configobservation"
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/tuning"
 )
 
 func ObserveClusterCIDRs(genericListers configobserver.Listers, recorder events.Recorder, existingConfig map[string]interface{}) (map[string]interface{}, []error) {
@@ -71,3 +82,207 @@ func ObserveServiceClusterIPRanges(genericListers configobserver.Listers, record
 
 	return observedConfig, errs
 }
+
+// selfAllocatingNetworkTypes assign the pod subnets of the nodes themselves and ignore node.spec.podCIDR. The other
+// network plugins, e.g. Calico, Cilium or Flannel in their default IPAM modes, rely on the kube-controller-manager to
+// allocate a pod CIDR to every node.
+var selfAllocatingNetworkTypes = sets.New[string](
+	string(operatorv1.NetworkTypeOpenShiftSDN),
+	string(operatorv1.NetworkTypeOVNKubernetes),
+	"Kuryr",
+)
+
+var nodeCIDRArguments = []string{
+	"allocate-node-cidrs",
+	"configure-cloud-routes",
+	"node-cidr-mask-size",
+	"node-cidr-mask-size-ipv4",
+	"node-cidr-mask-size-ipv6",
+}
+
+// defaultNodeCIDRMaskSizes are the node CIDR sizes of the kube-controller-manager, by IP family, used for the cluster
+// networks without a hostPrefix unless the projected nodes need smaller node CIDRs.
+var defaultNodeCIDRMaskSizes = map[string]int{"ipv4": 24, "ipv6": 64}
+
+// ObserveNodeCIDRAllocation enables the node CIDR allocation for the network plugins that need it, sized after the
+// hostPrefix of the cluster networks. A cluster network without a hostPrefix gets node CIDRs small enough for the
+// projected node count, the larger of the current node count and the expectedNodeCount of the tuning config. A cluster
+// network that cannot cover the projected nodes is reported as an error, the node CIDRs are observed nevertheless.
+// Routes to the node CIDRs are left to the network plugin and the cloud-controller-manager, the
+// kube-controller-manager never configures them.
+func ObserveNodeCIDRAllocation(genericListers configobserver.Listers, recorder events.Recorder, existingConfig map[string]interface{}) (map[string]interface{}, []error) {
+	listers := genericListers.(configobservation.Listers)
+
+	var errs []error
+	previouslyObservedConfig := map[string]interface{}{}
+	for _, argument := range nodeCIDRArguments {
+		path := []string{"extendedArguments", argument}
+		if value, _, _ := unstructured.NestedStringSlice(existingConfig, path...); len(value) > 0 {
+			if err := unstructured.SetNestedStringSlice(previouslyObservedConfig, value, path...); err != nil {
+				errs = append(errs, err)
+			}
+		}
+	}
+
+	networkConfig, err := listers.NetworkLister.Get("cluster")
+	if err != nil {
+		return previouslyObservedConfig, append(errs, err)
+	}
+	networkType := networkConfig.Status.NetworkType
+	if len(networkType) == 0 {
+		return previouslyObservedConfig, append(errs, fmt.Errorf("network.config.openshift.io/cluster: status.networkType is not reported yet"))
+	}
+
+	arguments := map[string]string{
+		"allocate-node-cidrs":    "false",
+		"configure-cloud-routes": "false",
+	}
+	var capacityErrs []error
+	if !selfAllocatingNetworkTypes.Has(networkType) {
+		projectedNodes, err := projectedNodeCount(listers)
+		if err != nil {
+			return previouslyObservedConfig, append(errs, err)
+		}
+		arguments["allocate-node-cidrs"] = "true"
+		maskSizes, err := nodeCIDRMaskSizes(networkConfig.Status.ClusterNetwork, projectedNodes)
+		if err != nil {
+			return previouslyObservedConfig, append(errs, err)
+		}
+		for argument, value := range maskSizes {
+			arguments[argument] = value
+		}
+		capacityErrs = nodeCIDRCapacityErrors(networkConfig.Status.ClusterNetwork, maskSizes, projectedNodes)
+	}
+
+	observedConfig := map[string]interface{}{}
+	for argument, value := range arguments {
+		if err := unstructured.SetNestedStringSlice(observedConfig, []string{value}, "extendedArguments", argument); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	if len(errs) > 0 {
+		return previouslyObservedConfig, errs
+	}
+
+	if !reflect.DeepEqual(previouslyObservedConfig, observedConfig) {
+		recorder.Eventf("ObserveNodeCIDRAllocation", "Node CIDR allocation set to %s for the %s network type", arguments["allocate-node-cidrs"], networkType)
+	}
+	return observedConfig, capacityErrs
+}
+
+// projectedNodeCount returns the number of nodes the node CIDRs must cover.
+func projectedNodeCount(listers configobservation.Listers) (int, error) {
+	tuningConfig, err := tuning.Get(listers.ConfigMapLister())
+	if err != nil {
+		return 0, err
+	}
+	nodes, err := listers.KubeNodeLister.List(labels.Everything())
+	if err != nil {
+		return 0, err
+	}
+	if expected := int(tuningConfig.ExpectedNodeCount); expected > len(nodes) {
+		return expected, nil
+	}
+	return len(nodes), nil
+}
+
+// firstClusterNetworks returns the first cluster network of each IP family, the ones the kube-controller-manager
+// allocates the node CIDRs from.
+func firstClusterNetworks(clusterNetworks []configv1.ClusterNetworkEntry) (map[string]configv1.ClusterNetworkEntry, map[string]*net.IPNet, error) {
+	entries := map[string]configv1.ClusterNetworkEntry{}
+	cidrs := map[string]*net.IPNet{}
+	for _, clusterNetwork := range clusterNetworks {
+		ip, cidr, err := net.ParseCIDR(clusterNetwork.CIDR)
+		if err != nil {
+			return nil, nil, fmt.Errorf("network.config.openshift.io/cluster: invalid cluster network %q: %v", clusterNetwork.CIDR, err)
+		}
+		family := "ipv6"
+		if ip.To4() != nil {
+			family = "ipv4"
+		}
+		if _, ok := entries[family]; !ok {
+			entries[family] = clusterNetwork
+			cidrs[family] = cidr
+		}
+	}
+	return entries, cidrs, nil
+}
+
+// nodeCIDRMaskSizes returns the node CIDR mask size arguments for the first cluster network of each IP family, its
+// hostPrefix or, without one, the default size shrunk until the projected nodes fit. A single stack cluster uses
+// node-cidr-mask-size, a dual stack one needs the per family arguments.
+func nodeCIDRMaskSizes(clusterNetworks []configv1.ClusterNetworkEntry, projectedNodes int) (map[string]string, error) {
+	if len(clusterNetworks) == 0 {
+		return nil, fmt.Errorf("network.config.openshift.io/cluster: status.clusterNetwork is not reported yet")
+	}
+	entries, cidrs, err := firstClusterNetworks(clusterNetworks)
+	if err != nil {
+		return nil, err
+	}
+	maskSizes := map[string]string{}
+	for family, clusterNetwork := range entries {
+		prefixLength, addressBits := cidrs[family].Mask.Size()
+		maskSize := int(clusterNetwork.HostPrefix)
+		if maskSize == 0 {
+			maskSize = defaultNodeCIDRMaskSizes[family]
+			if fitting := prefixLength + bitsFor(projectedNodes); fitting > maskSize {
+				// leave at least two addresses per node
+				maskSize = min(fitting, addressBits-2)
+			}
+		}
+		if maskSize < prefixLength || maskSize > addressBits {
+			return nil, fmt.Errorf("network.config.openshift.io/cluster: hostPrefix %d does not fit in cluster network %q", maskSize, clusterNetwork.CIDR)
+		}
+		maskSizes["node-cidr-mask-size-"+family] = strconv.Itoa(maskSize)
+	}
+	if len(maskSizes) == 1 {
+		for _, maskSize := range maskSizes {
+			return map[string]string{"node-cidr-mask-size": maskSize}, nil
+		}
+	}
+	return maskSizes, nil
+}
+
+// nodeCIDRCapacityErrors reports the cluster networks whose node CIDRs run out before the projected node count.
+func nodeCIDRCapacityErrors(clusterNetworks []configv1.ClusterNetworkEntry, maskSizes map[string]string, projectedNodes int) []error {
+	entries, cidrs, err := firstClusterNetworks(clusterNetworks)
+	if err != nil {
+		return []error{err}
+	}
+	var errs []error
+	for _, family := range []string{"ipv4", "ipv6"} {
+		if _, ok := entries[family]; !ok {
+			continue
+		}
+		maskSize, ok := maskSizes["node-cidr-mask-size-"+family]
+		if !ok {
+			maskSize = maskSizes["node-cidr-mask-size"]
+		}
+		size, err := strconv.Atoi(maskSize)
+		if err != nil {
+			return []error{err}
+		}
+		prefixLength, _ := cidrs[family].Mask.Size()
+		if capacity := nodeCIDRCapacity(prefixLength, size); capacity < projectedNodes {
+			errs = append(errs, fmt.Errorf("network.config.openshift.io/cluster: cluster network %q has room for %d node CIDRs of /%d, %d nodes are projected", entries[family].CIDR, capacity, size, projectedNodes))
+		}
+	}
+	return errs
+}
+
+// nodeCIDRCapacity is the number of node CIDRs of the mask size in a cluster network of the prefix length, capped at
+// the largest int.
+func nodeCIDRCapacity(prefixLength, maskSize int) int {
+	if maskSize-prefixLength >= bits.UintSize-1 {
+		return math.MaxInt
+	}
+	return 1 << (maskSize - prefixLength)
+}
+
+// bitsFor returns the number of bits needed to number n node CIDRs.
+func bitsFor(n int) int {
+	if n <= 1 {
+		return 0
+	}
+	return bits.Len(uint(n - 1))
+}
@@ -1,10 +1,13 @@
 package network
 
 import (
+	"fmt"
 	"reflect"
 	"testing"
 
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	corev1listers "k8s.io/client-go/listers/core/v1"
 	"k8s.io/client-go/tools/cache"
 
 	configv1 "github.com/openshift/api/config/v1"
@@ -14,6 +17,8 @@ import (
 	"github.com/ghodss/yaml"
 
 	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/configobservation"
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/operatorclient"
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/tuning"
 )
 
 func TestObserveClusterCIDRs(t *testing.T) {
@@ -117,6 +122,155 @@ func TestObserveServiceClusterIPRanges(t *testing.T) {
 	}
 }
 
+func TestObserveNodeCIDRAllocation(t *testing.T) {
+	disabled := map[string]interface{}{
+		"extendedArguments": map[string]interface{}{
+			"allocate-node-cidrs":    []interface{}{"false"},
+			"configure-cloud-routes": []interface{}{"false"},
+		},
+	}
+	tests := []struct {
+		name          string
+		status        configv1.NetworkStatus
+		nodes         int
+		tuning        string
+		input         map[string]interface{}
+		expected      map[string]interface{}
+		expectedError bool
+	}{
+		{
+			name:     "OVNKubernetes",
+			status:   configv1.NetworkStatus{NetworkType: "OVNKubernetes", ClusterNetwork: []configv1.ClusterNetworkEntry{{CIDR: "10.128.0.0/14", HostPrefix: 23}}},
+			input:    map[string]interface{}{},
+			expected: disabled,
+		},
+		{
+			name:   "third party plugin",
+			status: configv1.NetworkStatus{NetworkType: "Calico", ClusterNetwork: []configv1.ClusterNetworkEntry{{CIDR: "10.128.0.0/14", HostPrefix: 23}}},
+			input:  disabled,
+			expected: map[string]interface{}{
+				"extendedArguments": map[string]interface{}{
+					"allocate-node-cidrs":    []interface{}{"true"},
+					"configure-cloud-routes": []interface{}{"false"},
+					"node-cidr-mask-size":    []interface{}{"23"},
+				},
+			},
+		},
+		{
+			name: "third party plugin, dual stack",
+			status: configv1.NetworkStatus{NetworkType: "Cilium", ClusterNetwork: []configv1.ClusterNetworkEntry{
+				{CIDR: "10.128.0.0/14", HostPrefix: 23}, {CIDR: "10.132.0.0/14", HostPrefix: 24}, {CIDR: "fd01::/48", HostPrefix: 64},
+			}},
+			input: map[string]interface{}{},
+			expected: map[string]interface{}{
+				"extendedArguments": map[string]interface{}{
+					"allocate-node-cidrs":      []interface{}{"true"},
+					"configure-cloud-routes":   []interface{}{"false"},
+					"node-cidr-mask-size-ipv4": []interface{}{"23"},
+					"node-cidr-mask-size-ipv6": []interface{}{"64"},
+				},
+			},
+		},
+		{
+			name:   "third party plugin without hostPrefix",
+			status: configv1.NetworkStatus{NetworkType: "Calico", ClusterNetwork: []configv1.ClusterNetworkEntry{{CIDR: "10.128.0.0/16"}}},
+			nodes:  3,
+			input:  map[string]interface{}{},
+			expected: map[string]interface{}{
+				"extendedArguments": map[string]interface{}{
+					"allocate-node-cidrs":    []interface{}{"true"},
+					"configure-cloud-routes": []interface{}{"false"},
+					"node-cidr-mask-size":    []interface{}{"24"},
+				},
+			},
+		},
+		{
+			name:   "third party plugin without hostPrefix sized for the expected nodes",
+			status: configv1.NetworkStatus{NetworkType: "Calico", ClusterNetwork: []configv1.ClusterNetworkEntry{{CIDR: "10.128.0.0/16"}}},
+			nodes:  3,
+			tuning: "expectedNodeCount: 1000",
+			input:  map[string]interface{}{},
+			expected: map[string]interface{}{
+				"extendedArguments": map[string]interface{}{
+					"allocate-node-cidrs":    []interface{}{"true"},
+					"configure-cloud-routes": []interface{}{"false"},
+					"node-cidr-mask-size":    []interface{}{"26"},
+				},
+			},
+		},
+		{
+			name:   "hostPrefix too large for the nodes",
+			status: configv1.NetworkStatus{NetworkType: "Calico", ClusterNetwork: []configv1.ClusterNetworkEntry{{CIDR: "10.128.0.0/20", HostPrefix: 23}}},
+			nodes:  9,
+			input:  map[string]interface{}{},
+			expected: map[string]interface{}{
+				"extendedArguments": map[string]interface{}{
+					"allocate-node-cidrs":    []interface{}{"true"},
+					"configure-cloud-routes": []interface{}{"false"},
+					"node-cidr-mask-size":    []interface{}{"23"},
+				},
+			},
+			expectedError: true,
+		},
+		{
+			name:          "hostPrefix shorter than the cluster network keeps the previous config",
+			status:        configv1.NetworkStatus{NetworkType: "Calico", ClusterNetwork: []configv1.ClusterNetworkEntry{{CIDR: "10.128.0.0/14", HostPrefix: 12}}},
+			input:         disabled,
+			expected:      disabled,
+			expectedError: true,
+		},
+		{
+			name:          "network type not reported keeps the previous config",
+			status:        configv1.NetworkStatus{},
+			input:         disabled,
+			expected:      disabled,
+			expectedError: true,
+		},
+		{
+			name:          "third party plugin without cluster networks keeps the previous config",
+			status:        configv1.NetworkStatus{NetworkType: "Calico"},
+			input:         disabled,
+			expected:      disabled,
+			expectedError: true,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			indexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{})
+			if err := indexer.Add(&configv1.Network{ObjectMeta: metav1.ObjectMeta{Name: "cluster"}, Status: test.status}); err != nil {
+				t.Fatal(err.Error())
+			}
+			nodeIndexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{})
+			for i := 0; i < test.nodes; i++ {
+				if err := nodeIndexer.Add(&corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: fmt.Sprintf("node-%d", i)}}); err != nil {
+					t.Fatal(err)
+				}
+			}
+			configMapIndexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc})
+			if len(test.tuning) > 0 {
+				if err := configMapIndexer.Add(&corev1.ConfigMap{
+					ObjectMeta: metav1.ObjectMeta{Namespace: operatorclient.OperatorNamespace, Name: tuning.ConfigMapName},
+					Data:       map[string]string{tuning.ConfigKey: test.tuning},
+				}); err != nil {
+					t.Fatal(err)
+				}
+			}
+			listers := configobservation.Listers{
+				NetworkLister:    configlistersv1.NewNetworkLister(indexer),
+				KubeNodeLister:   corev1listers.NewNodeLister(nodeIndexer),
+				ConfigMapLister_: corev1listers.NewConfigMapLister(configMapIndexer),
+			}
+			result, errs := ObserveNodeCIDRAllocation(listers, events.NewInMemoryRecorder("network"), test.input)
+			if test.expectedError != (len(errs) > 0) {
+				t.Fatalf("expected error %v, got %v", test.expectedError, errs)
+			}
+			if !reflect.DeepEqual(test.expected, result) {
+				t.Errorf("\n===== observed config expected:\n%v\n===== observed config actual:\n%v", toYAML(test.expected), toYAML(result))
+			}
+		})
+	}
+}
+
 func toYAML(o interface{}) string {
 	b, e := yaml.Marshal(o)
 	if e != nil {
@@ -0,0 +1,60 @@
+package profiling
+
+import (
+	"reflect"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/openshift/library-go/pkg/operator/configobserver"
+	"github.com/openshift/library-go/pkg/operator/events"
+
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/configobservation"
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/tuning"
+)
+
+var (
+	profilingPath           = []string{"extendedArguments", "profiling"}
+	contentionProfilingPath = []string{"extendedArguments", "contention-profiling"}
+)
+
+// ObserveProfiling fills in the profiling and contention-profiling extended arguments for the profiling section of
+// the tuning configmap. The kube-controller-manager defaults, profiling on and contention profiling off, are left
+// unset.
+func ObserveProfiling(genericListers configobserver.Listers, recorder events.Recorder, existingConfig map[string]interface{}) (map[string]interface{}, []error) {
+	listers := genericListers.(configobservation.Listers)
+	errs := []error{}
+
+	previouslyObservedConfig := map[string]interface{}{}
+	for _, path := range [][]string{profilingPath, contentionProfilingPath} {
+		if value, _, _ := unstructured.NestedStringSlice(existingConfig, path...); len(value) > 0 {
+			if err := unstructured.SetNestedStringSlice(previouslyObservedConfig, value, path...); err != nil {
+				errs = append(errs, err)
+			}
+		}
+	}
+
+	tuningConfig, err := tuning.Get(listers.ConfigMapLister())
+	if err != nil {
+		return previouslyObservedConfig, append(errs, err)
+	}
+
+	observedConfig := map[string]interface{}{}
+	if tuningConfig.Profiling.Disabled {
+		if err := unstructured.SetNestedStringSlice(observedConfig, []string{"false"}, profilingPath...); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	if tuningConfig.Profiling.Contention {
+		if err := unstructured.SetNestedStringSlice(observedConfig, []string{"true"}, contentionProfilingPath...); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	if len(errs) > 0 {
+		return previouslyObservedConfig, errs
+	}
+
+	if !reflect.DeepEqual(previouslyObservedConfig, observedConfig) {
+		recorder.Eventf("ObserveProfiling", "Profiling changed to disabled=%t, contention=%t", tuningConfig.Profiling.Disabled, tuningConfig.Profiling.Contention)
+	}
+	return observedConfig, errs
+}
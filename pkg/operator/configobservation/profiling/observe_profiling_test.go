@@ -0,0 +1,94 @@
+package profiling
+
+import (
+	"reflect"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	corev1listers "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/tools/cache"
+
+	"github.com/openshift/library-go/pkg/operator/events"
+
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/configobservation"
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/operatorclient"
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/tuning"
+)
+
+func TestObserveProfiling(t *testing.T) {
+	disabledConfig := map[string]interface{}{
+		"extendedArguments": map[string]interface{}{
+			"profiling": []interface{}{"false"},
+		},
+	}
+	contentionConfig := map[string]interface{}{
+		"extendedArguments": map[string]interface{}{
+			"contention-profiling": []interface{}{"true"},
+		},
+	}
+
+	tests := []struct {
+		name          string
+		tuningConfig  string
+		input         map[string]interface{}
+		expected      map[string]interface{}
+		expectedError bool
+	}{
+		{
+			name:     "no tuning configmap",
+			input:    map[string]interface{}{},
+			expected: map[string]interface{}{},
+		},
+		{
+			name:         "disabled",
+			tuningConfig: "profiling: {disabled: true}",
+			input:        map[string]interface{}{},
+			expected:     disabledConfig,
+		},
+		{
+			name:         "contention",
+			tuningConfig: "profiling: {contention: true}",
+			input:        disabledConfig,
+			expected:     contentionConfig,
+		},
+		{
+			name:         "defaults",
+			tuningConfig: "profiling: {}",
+			input:        contentionConfig,
+			expected:     map[string]interface{}{},
+		},
+		{
+			name:          "contention without profiling keeps the previous config",
+			tuningConfig:  "profiling: {disabled: true, contention: true}",
+			input:         disabledConfig,
+			expected:      disabledConfig,
+			expectedError: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			indexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{})
+			if len(test.tuningConfig) > 0 {
+				if err := indexer.Add(&corev1.ConfigMap{
+					ObjectMeta: metav1.ObjectMeta{Namespace: operatorclient.OperatorNamespace, Name: tuning.ConfigMapName},
+					Data:       map[string]string{tuning.ConfigKey: test.tuningConfig},
+				}); err != nil {
+					t.Fatal(err)
+				}
+			}
+			listers := configobservation.Listers{
+				ConfigMapLister_: corev1listers.NewConfigMapLister(indexer),
+			}
+
+			result, errs := ObserveProfiling(listers, events.NewInMemoryRecorder("profiling"), test.input)
+			if test.expectedError != (len(errs) > 0) {
+				t.Fatalf("expected error %v, got %v", test.expectedError, errs)
+			}
+			if !reflect.DeepEqual(test.expected, result) {
+				t.Errorf("expected %v, got %v", test.expected, result)
+			}
+		})
+	}
+}
@@ -0,0 +1,192 @@
+package schema
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/ghodss/yaml"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	kubecontrolplanev1 "github.com/openshift/api/kubecontrolplane/v1"
+	openshiftcontrolplanev1 "github.com/openshift/api/openshiftcontrolplane/v1"
+	"github.com/openshift/library-go/pkg/operator/resource/resourcemerge"
+)
+
+const (
+	// ConfigMapName is the configmap in the operator namespace the schema is published in.
+	ConfigMapName = "kube-controller-manager-observed-config-schema"
+	// ConfigKey is the key of ConfigMapName holding the JSON schema.
+	ConfigKey = "schema.json"
+)
+
+// Target is a config rendered from the observed config. Each target keeps the paths of the observed config its
+// config type knows, MergePrunedConfigMap prunes the rest.
+type Target string
+
+const (
+	// KubeControllerManagerTarget is the config configmap of the kube-controller-manager container.
+	KubeControllerManagerTarget Target = "kube-controller-manager"
+	// ClusterPolicyControllerTarget is the cluster-policy-controller-config configmap.
+	ClusterPolicyControllerTarget Target = "cluster-policy-controller"
+)
+
+// targetTypes are the config types the targets are pruned to.
+var targetTypes = []struct {
+	target Target
+	config runtime.Object
+}{
+	{target: KubeControllerManagerTarget, config: &kubecontrolplanev1.KubeControllerManagerConfig{}},
+	{target: ClusterPolicyControllerTarget, config: &openshiftcontrolplanev1.OpenShiftControllerManagerConfig{}},
+}
+
+// ObservedPath is a path of the observed config an observer may set.
+type ObservedPath struct {
+	// Path is the path of the value in the observed config.
+	Path []string
+	// Type is the JSON schema type of the value.
+	Type string
+	// Observer names the config observer setting the path.
+	Observer string
+	// Description says what the value is observed from.
+	Description string
+	// Example is a valid value, it is used to find out which targets keep the path.
+	Example interface{}
+}
+
+// ObservedPaths are all the paths the config observers of the operator may set. A new observer must add its paths
+// here, the published schema is the only documentation of the observed config outside of the code.
+var ObservedPaths = []ObservedPath{
+	extendedArgument("cloud-provider", "cloudprovider.NewCloudProviderObserver", "The cloud provider of infrastructure/cluster."),
+	extendedArgument("cloud-config", "cloudprovider.NewCloudProviderObserver", "The path of the cloud config synced from openshift-config-managed/kube-cloud-config."),
+	extendedArgument("feature-gates", "featuregates.NewObserveFeatureFlagsFunc", "The feature gates of featuregate/cluster without the OpenShift only ones."),
+	{Path: []string{"featureGates"}, Type: "array", Observer: "featuregates.NewObserveFeatureFlagsFunc", Description: "The feature gates of featuregate/cluster.", Example: []interface{}{"ExampleGate=true"}},
+	extendedArgument("cluster-cidr", "network.ObserveClusterCIDRs", "The cluster networks of network/cluster."),
+	extendedArgument("service-cluster-ip-range", "network.ObserveServiceClusterIPRanges", "The service networks of network/cluster."),
+	extendedArgument("allocate-node-cidrs", "network.ObserveNodeCIDRAllocation", "Whether node CIDRs are allocated, after the network type of network/cluster."),
+	extendedArgument("configure-cloud-routes", "network.ObserveNodeCIDRAllocation", "Whether cloud routes are configured for the node CIDRs."),
+	extendedArgument("node-cidr-mask-size", "network.ObserveNodeCIDRAllocation", "The node CIDR size of a single stack cluster network."),
+	extendedArgument("node-cidr-mask-size-ipv4", "network.ObserveNodeCIDRAllocation", "The IPv4 node CIDR size of a dual stack cluster network."),
+	extendedArgument("node-cidr-mask-size-ipv6", "network.ObserveNodeCIDRAllocation", "The IPv6 node CIDR size of a dual stack cluster network."),
+	extendedArgument("node-monitor-grace-period", "nodeobserver.NewLatencyProfileObserver", "The node monitor grace period of the worker latency profile of node/cluster."),
+	{Path: []string{"targetconfigcontroller", "proxy"}, Type: "object", Observer: "proxy.NewProxyObserveFunc", Description: "The proxy environment variables of proxy/cluster, read by the operator itself.", Example: map[string]interface{}{"HTTPS_PROXY": "https://proxy.example.com"}},
+	{Path: []string{"serviceServingCert", "certFile"}, Type: "string", Observer: "serviceca.ObserveServiceCA", Description: "The path of the service CA bundle once the service-ca configmap exists.", Example: "/etc/kubernetes/static-pod-resources/configmaps/service-ca/ca-bundle.crt"},
+	extendedArgument("root-ca-file", "serviceaccount.ObserveRootCAFile", "The path of the service account root CA of the tuning configmap once the serviceaccount-root-ca configmap exists."),
+	extendedArgument("cluster-name", "clustername.ObserveInfraID", "The infrastructure name of infrastructure/cluster."),
+	{Path: []string{"servingInfo", "minTLSVersion"}, Type: "string", Observer: "apiserver.ObserveTLSSecurityProfile", Description: "The minimum TLS version of the TLS security profile of apiserver/cluster.", Example: "VersionTLS12"},
+	{Path: []string{"servingInfo", "cipherSuites"}, Type: "array", Observer: "apiserver.ObserveTLSSecurityProfile", Description: "The cipher suites of the TLS security profile of apiserver/cluster.", Example: []interface{}{"TLS_AES_128_GCM_SHA256"}},
+	extendedArgument("external-cloud-volume-plugin", "cloud.NewObserveCloudVolumePluginFunc", "The in-tree volume plugin of a cloud provider that moved out of tree."),
+	extendedArgument("concurrent-namespace-syncs", "workload.ObserveWorkloadProfile", "The namespace worker count of the workload profile of the tuning configmap."),
+	extendedArgument("concurrent-job-syncs", "workload.ObserveWorkloadProfile", "The job worker count of the workload profile of the tuning configmap."),
+	extendedArgument("terminated-pod-gc-threshold", "workload.ObserveWorkloadProfile", "The terminated pod threshold of the workload profile of the tuning configmap."),
+	extendedArgument("min-resync-period", "workload.ObserveWorkloadProfile", "The informer resync period of the workload profile of the tuning configmap."),
+	extendedArgument("logging-format", "logging.ObserveLoggingFormat", "The log format of the tuning configmap."),
+	extendedArgument("profiling", "profiling.ObserveProfiling", "Whether the profiling endpoints are served, after the profiling section of the tuning configmap."),
+	extendedArgument("contention-profiling", "profiling.ObserveProfiling", "Whether contention profiling is enabled, after the profiling section of the tuning configmap."),
+	{Path: []string{"resourceQuota", "concurrentSyncs"}, Type: "integer", Observer: "clusterpolicycontroller.ObserveClusterPolicyControllerConfig", Description: "The cluster resource quota worker count of the tuning configmap.", Example: int64(10)},
+	{Path: []string{"resourceQuota", "syncPeriod"}, Type: "string", Observer: "clusterpolicycontroller.ObserveClusterPolicyControllerConfig", Description: "The cluster resource quota sync period of the tuning configmap.", Example: "10m0s"},
+	{Path: []string{"resourceQuota", "minResyncPeriod"}, Type: "string", Observer: "clusterpolicycontroller.ObserveClusterPolicyControllerConfig", Description: "The cluster resource quota informer resync period of the tuning configmap.", Example: "5m0s"},
+	{Path: []string{"dockerPullSecret", "internalRegistryHostname"}, Type: "string", Observer: "clusterpolicycontroller.ObserveInternalRegistryHostname", Description: "The internal registry hostname of image.config/cluster.", Example: "image-registry.openshift-image-registry.svc:5000"},
+	extendedArgument("leader-elect-lease-duration", "failover.ObserveFastFailover", "The leader election lease of the fast failover option of the tuning configmap."),
+	extendedArgument("leader-elect-renew-deadline", "failover.ObserveFastFailover", "The leader election renew deadline of the fast failover option of the tuning configmap."),
+	extendedArgument("leader-elect-retry-period", "failover.ObserveFastFailover", "The leader election retry period of the fast failover option of the tuning configmap."),
+	extendedArgument("node-monitor-period", "failover.ObserveFastFailover", "The node monitor period of the fast failover option of the tuning configmap."),
+	extendedArgument("pv-binder-sync-period", "storage.ObserveStorageTuning", "The persistent volume binder sync period of the storage section of the tuning configmap."),
+	extendedArgument("attach-detach-reconcile-sync-period", "storage.ObserveStorageTuning", "The attach/detach reconcile period of the storage section of the tuning configmap."),
+	extendedArgument("concurrent-ephemeralvolume-syncs", "storage.ObserveStorageTuning", "The ephemeral volume worker count of the storage section of the tuning configmap."),
+	extendedArgument("concurrent-gc-syncs", "garbagecollector.ObserveGarbageCollectorTuning", "The garbage collector worker count of the garbageCollector section of the tuning configmap, scaled to the object count with autoScale, or of the workload profile."),
+	extendedArgument("authentication-token-webhook-cache-ttl", "authcache.ObserveAuthWebhookCache", "The token review cache TTL of the authWebhookCache section of the tuning configmap."),
+	extendedArgument("authorization-webhook-cache-authorized-ttl", "authcache.ObserveAuthWebhookCache", "The allowed subject access review cache TTL of the authWebhookCache section of the tuning configmap."),
+	extendedArgument("authorization-webhook-cache-unauthorized-ttl", "authcache.ObserveAuthWebhookCache", "The denied subject access review cache TTL of the authWebhookCache section of the tuning configmap."),
+	extendedArgument("cluster-signing-cert-file", "csrsigner.ObserveCSRSigner", "The signer certificate file of the external csr-signer provider of the tuning configmap once the kube-apiserver trusts it."),
+	extendedArgument("cluster-signing-key-file", "csrsigner.ObserveCSRSigner", "The signer key file of the external csr-signer provider of the tuning configmap once the kube-apiserver trusts it."),
+	extendedArgument("controllers", "controllers.ObserveDisabledControllers", "The enabled controllers without the ones disabled in the tuning configmap."),
+	extendedArgument("secure-port", "secureport.NewObserveSecurePortFunc", "The secure port of the tuning configmap the operator was started with."),
+}
+
+// extendedArgument is an observed kube-controller-manager flag, which like all the flags is a list of values.
+func extendedArgument(name, observer, description string) ObservedPath {
+	return ObservedPath{
+		Path:        []string{"extendedArguments", name},
+		Type:        "array",
+		Observer:    observer,
+		Description: description,
+		Example:     []interface{}{"example"},
+	}
+}
+
+// Targets returns the rendered configs that keep the path after the observed config is pruned to their config type.
+func Targets(path ObservedPath) ([]Target, error) {
+	observedConfig := map[string]interface{}{}
+	if err := unstructured.SetNestedField(observedConfig, runtime.DeepCopyJSONValue(path.Example), path.Path...); err != nil {
+		return nil, err
+	}
+	observedConfigJSON, err := json.Marshal(observedConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	var targets []Target
+	for _, targetType := range targetTypes {
+		pruned, err := resourcemerge.MergePrunedProcessConfig(targetType.config, nil, []byte("{}"), observedConfigJSON)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %v", strings.Join(path.Path, "."), err)
+		}
+		prunedConfig := map[string]interface{}{}
+		if err := yaml.Unmarshal(pruned, &prunedConfig); err != nil {
+			return nil, err
+		}
+		if _, found, _ := unstructured.NestedFieldNoCopy(prunedConfig, path.Path...); found {
+			targets = append(targets, targetType.target)
+		}
+	}
+	return targets, nil
+}
+
+// JSONSchema returns the JSON schema of the observed config. Every leaf names its observer in x-observer and the
+// rendered configs keeping it in x-rendered-into, a path no config keeps is only read by the operator.
+func JSONSchema() ([]byte, error) {
+	root := newObjectSchema()
+	for _, path := range ObservedPaths {
+		targets, err := Targets(path)
+		if err != nil {
+			return nil, err
+		}
+		renderedInto := []interface{}{}
+		for _, target := range targets {
+			renderedInto = append(renderedInto, string(target))
+		}
+
+		parent := root
+		for _, element := range path.Path[:len(path.Path)-1] {
+			properties := parent["properties"].(map[string]interface{})
+			child, ok := properties[element].(map[string]interface{})
+			if !ok {
+				child = newObjectSchema()
+				properties[element] = child
+			}
+			parent = child
+		}
+		leaf := map[string]interface{}{
+			"type":            path.Type,
+			"description":     path.Description,
+			"x-observer":      path.Observer,
+			"x-rendered-into": renderedInto,
+		}
+		if path.Type == "array" {
+			leaf["items"] = map[string]interface{}{"type": "string"}
+		}
+		parent["properties"].(map[string]interface{})[path.Path[len(path.Path)-1]] = leaf
+	}
+	root["$schema"] = "https://json-schema.org/draft/2020-12/schema"
+	root["title"] = "kube-controller-manager operator observed config"
+	return json.MarshalIndent(root, "", "  ")
+}
+
+func newObjectSchema() map[string]interface{} {
+	return map[string]interface{}{
+		"type":       "object",
+		"properties": map[string]interface{}{},
+	}
+}
@@ -0,0 +1,61 @@
+package schema
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestTargets(t *testing.T) {
+	expected := map[string][]Target{
+		"extendedArguments.cluster-cidr":            {KubeControllerManagerTarget},
+		"featureGates":                              {ClusterPolicyControllerTarget},
+		"servingInfo.minTLSVersion":                 {ClusterPolicyControllerTarget},
+		"resourceQuota.concurrentSyncs":             {ClusterPolicyControllerTarget},
+		"dockerPullSecret.internalRegistryHostname": {ClusterPolicyControllerTarget},
+		"targetconfigcontroller.proxy":              nil,
+	}
+
+	seen := map[string]bool{}
+	for _, path := range ObservedPaths {
+		key := strings.Join(path.Path, ".")
+		if seen[key] {
+			t.Errorf("%s is listed twice", key)
+		}
+		seen[key] = true
+
+		targets, err := Targets(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if want, ok := expected[key]; ok && !reflect.DeepEqual(want, targets) {
+			t.Errorf("%s: expected targets %v, got %v", key, want, targets)
+		}
+	}
+	for key := range expected {
+		if !seen[key] {
+			t.Errorf("%s is not listed", key)
+		}
+	}
+}
+
+func TestJSONSchema(t *testing.T) {
+	raw, err := JSONSchema()
+	if err != nil {
+		t.Fatal(err)
+	}
+	schema := map[string]interface{}{}
+	if err := json.Unmarshal(raw, &schema); err != nil {
+		t.Fatal(err)
+	}
+
+	extendedArguments := schema["properties"].(map[string]interface{})["extendedArguments"].(map[string]interface{})
+	clusterCIDR := extendedArguments["properties"].(map[string]interface{})["cluster-cidr"].(map[string]interface{})
+	if clusterCIDR["type"] != "array" || clusterCIDR["x-observer"] != "network.ObserveClusterCIDRs" {
+		t.Errorf("unexpected cluster-cidr schema %v", clusterCIDR)
+	}
+	if renderedInto := clusterCIDR["x-rendered-into"]; !reflect.DeepEqual(renderedInto, []interface{}{"kube-controller-manager"}) {
+		t.Errorf("unexpected cluster-cidr targets %v", renderedInto)
+	}
+}
@@ -0,0 +1,43 @@
+package secureport
+
+import (
+	"reflect"
+	"strconv"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/openshift/library-go/pkg/operator/configobserver"
+	"github.com/openshift/library-go/pkg/operator/events"
+
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/tuning"
+)
+
+var securePortPath = []string{"extendedArguments", "secure-port"}
+
+// NewObserveSecurePortFunc sets the secure-port extended argument to the secure port the operator was started with,
+// unless it is the tuning.DefaultSecurePort of the default config. The port is not read from the tuning config on
+// every sync, the services and the guard pods the operator manages only pick a new port up on its restart.
+func NewObserveSecurePortFunc(securePort int32) configobserver.ObserveConfigFunc {
+	return func(_ configobserver.Listers, recorder events.Recorder, existingConfig map[string]interface{}) (map[string]interface{}, []error) {
+		errs := []error{}
+
+		previouslyObservedConfig := map[string]interface{}{}
+		if value, _, _ := unstructured.NestedStringSlice(existingConfig, securePortPath...); len(value) > 0 {
+			if err := unstructured.SetNestedStringSlice(previouslyObservedConfig, value, securePortPath...); err != nil {
+				errs = append(errs, err)
+			}
+		}
+
+		observedConfig := map[string]interface{}{}
+		if securePort != tuning.DefaultSecurePort {
+			if err := unstructured.SetNestedStringSlice(observedConfig, []string{strconv.Itoa(int(securePort))}, securePortPath...); err != nil {
+				return previouslyObservedConfig, append(errs, err)
+			}
+		}
+
+		if !reflect.DeepEqual(previouslyObservedConfig, observedConfig) {
+			recorder.Eventf("ObserveSecurePort", "Secure port changed to %d", securePort)
+		}
+		return observedConfig, errs
+	}
+}
@@ -0,0 +1,57 @@
+package secureport
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/openshift/library-go/pkg/operator/events"
+
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/configobservation"
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/tuning"
+)
+
+func TestObserveSecurePort(t *testing.T) {
+	movedConfig := map[string]interface{}{
+		"extendedArguments": map[string]interface{}{
+			"secure-port": []interface{}{"10258"},
+		},
+	}
+
+	tests := []struct {
+		name       string
+		securePort int32
+		input      map[string]interface{}
+		expected   map[string]interface{}
+	}{
+		{
+			name:       "default port",
+			securePort: tuning.DefaultSecurePort,
+			input:      map[string]interface{}{},
+			expected:   map[string]interface{}{},
+		},
+		{
+			name:       "moved port",
+			securePort: 10258,
+			input:      map[string]interface{}{},
+			expected:   movedConfig,
+		},
+		{
+			name:       "moved back to the default port",
+			securePort: tuning.DefaultSecurePort,
+			input:      movedConfig,
+			expected:   map[string]interface{}{},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			result, errs := NewObserveSecurePortFunc(test.securePort)(configobservation.Listers{}, events.NewInMemoryRecorder("secureport"), test.input)
+			if len(errs) > 0 {
+				t.Fatal(errs)
+			}
+			if !reflect.DeepEqual(test.expected, result) {
+				t.Errorf("expected %v, got %v", test.expected, result)
+			}
+		})
+	}
+}
@@ -0,0 +1,74 @@
+package serviceaccount
+
+import (
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/openshift/library-go/pkg/operator/configobserver"
+	"github.com/openshift/library-go/pkg/operator/events"
+
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/configobservation"
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/operatorclient"
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/tuning"
+)
+
+const (
+	rootCAConfigMapName = "serviceaccount-root-ca"
+	rootCABundleKey     = "ca-bundle.crt"
+	rootCAFilePath      = "/etc/kubernetes/static-pod-resources/configmaps/serviceaccount-root-ca/ca-bundle.crt"
+)
+
+// ObserveRootCAFile points the root-ca-file extended argument at the serviceaccount-root-ca configmap when the tuning
+// configmap names a service account root CA. The configmap is only written once its bundle was checked against the
+// kube-apiserver, until then the default serviceaccount-ca bundle stays in place.
+func ObserveRootCAFile(genericListers configobserver.Listers, recorder events.Recorder, existingConfig map[string]interface{}) (map[string]interface{}, []error) {
+	listers := genericListers.(configobservation.Listers)
+	errs := []error{}
+	prevObservedConfig := map[string]interface{}{}
+
+	rootCAFilePathKey := []string{"extendedArguments", "root-ca-file"}
+	currentRootCAFilePath, _, err := unstructured.NestedStringSlice(existingConfig, rootCAFilePathKey...)
+	if err != nil {
+		errs = append(errs, err)
+	}
+	if len(currentRootCAFilePath) > 0 {
+		if err := unstructured.SetNestedStringSlice(prevObservedConfig, currentRootCAFilePath, rootCAFilePathKey...); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	tuningConfig, err := tuning.Get(listers.ConfigMapLister())
+	if err != nil {
+		return prevObservedConfig, append(errs, err)
+	}
+
+	observedConfig := map[string]interface{}{}
+	if len(tuningConfig.ServiceAccountRootCA) == 0 {
+		return reportRootCAFileChange(recorder, prevObservedConfig, observedConfig), errs
+	}
+	ca, err := listers.ConfigMapLister().ConfigMaps(operatorclient.TargetNamespace).Get(rootCAConfigMapName)
+	if errors.IsNotFound(err) {
+		// the root CA has not been verified and copied yet
+		return reportRootCAFileChange(recorder, prevObservedConfig, observedConfig), errs
+	}
+	if err != nil {
+		return prevObservedConfig, append(errs, err)
+	}
+	if len(ca.Data[rootCABundleKey]) == 0 {
+		return reportRootCAFileChange(recorder, prevObservedConfig, observedConfig), errs
+	}
+	if err := unstructured.SetNestedStringSlice(observedConfig, []string{rootCAFilePath}, rootCAFilePathKey...); err != nil {
+		recorder.Warningf("ObserveRootCAFile", "Failed setting root-ca-file: %v", err)
+		return prevObservedConfig, append(errs, err)
+	}
+	return reportRootCAFileChange(recorder, prevObservedConfig, observedConfig), errs
+}
+
+func reportRootCAFileChange(recorder events.Recorder, prevObservedConfig, observedConfig map[string]interface{}) map[string]interface{} {
+	if !equality.Semantic.DeepEqual(prevObservedConfig, observedConfig) {
+		rootCAFile, _, _ := unstructured.NestedStringSlice(observedConfig, "extendedArguments", "root-ca-file")
+		recorder.Eventf("ObserveRootCAFile", "root-ca-file changed to %q", rootCAFile)
+	}
+	return observedConfig
+}
@@ -0,0 +1,109 @@
+package serviceaccount
+
+import (
+	"reflect"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	corev1listers "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/tools/cache"
+
+	"github.com/openshift/library-go/pkg/operator/events"
+
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/configobservation"
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/operatorclient"
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/tuning"
+)
+
+func TestObserveRootCAFile(t *testing.T) {
+	rootCAConfig := map[string]interface{}{
+		"extendedArguments": map[string]interface{}{
+			"root-ca-file": []interface{}{rootCAFilePath},
+		},
+	}
+
+	tests := []struct {
+		name          string
+		tuningConfig  string
+		rootCA        *corev1.ConfigMap
+		input         map[string]interface{}
+		expected      map[string]interface{}
+		expectedError bool
+	}{
+		{
+			name:     "no tuning configmap",
+			input:    map[string]interface{}{},
+			expected: map[string]interface{}{},
+		},
+		{
+			name:         "root CA not copied yet",
+			tuningConfig: "serviceAccountRootCA: proxy-ca",
+			input:        map[string]interface{}{},
+			expected:     map[string]interface{}{},
+		},
+		{
+			name:         "empty root CA",
+			tuningConfig: "serviceAccountRootCA: proxy-ca",
+			rootCA:       &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Namespace: operatorclient.TargetNamespace, Name: rootCAConfigMapName}},
+			input:        map[string]interface{}{},
+			expected:     map[string]interface{}{},
+		},
+		{
+			name:         "root CA",
+			tuningConfig: "serviceAccountRootCA: proxy-ca",
+			rootCA: &corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{Namespace: operatorclient.TargetNamespace, Name: rootCAConfigMapName},
+				Data:       map[string]string{rootCABundleKey: "bundle"},
+			},
+			input:    map[string]interface{}{},
+			expected: rootCAConfig,
+		},
+		{
+			name: "unset with a leftover copy",
+			rootCA: &corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{Namespace: operatorclient.TargetNamespace, Name: rootCAConfigMapName},
+				Data:       map[string]string{rootCABundleKey: "bundle"},
+			},
+			input:    rootCAConfig,
+			expected: map[string]interface{}{},
+		},
+		{
+			name:          "invalid tuning configmap keeps the previous config",
+			tuningConfig:  "serviceAccountRootCA: Invalid_Name",
+			input:         rootCAConfig,
+			expected:      rootCAConfig,
+			expectedError: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			configMapIndexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{})
+			if len(test.tuningConfig) > 0 {
+				if err := configMapIndexer.Add(&corev1.ConfigMap{
+					ObjectMeta: metav1.ObjectMeta{Namespace: operatorclient.OperatorNamespace, Name: tuning.ConfigMapName},
+					Data:       map[string]string{tuning.ConfigKey: test.tuningConfig},
+				}); err != nil {
+					t.Fatal(err)
+				}
+			}
+			if test.rootCA != nil {
+				if err := configMapIndexer.Add(test.rootCA); err != nil {
+					t.Fatal(err)
+				}
+			}
+			listers := configobservation.Listers{
+				ConfigMapLister_: corev1listers.NewConfigMapLister(configMapIndexer),
+			}
+
+			result, errs := ObserveRootCAFile(listers, events.NewInMemoryRecorder("serviceaccount"), test.input)
+			if test.expectedError != (len(errs) > 0) {
+				t.Fatalf("expected error %v, got %v", test.expectedError, errs)
+			}
+			if !reflect.DeepEqual(test.expected, result) {
+				t.Errorf("expected %v, got %v", test.expected, result)
+			}
+		})
+	}
+}
@@ -0,0 +1,69 @@
+package storage
+
+import (
+	"reflect"
+	"strconv"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/openshift/library-go/pkg/operator/configobserver"
+	"github.com/openshift/library-go/pkg/operator/events"
+
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/configobservation"
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/tuning"
+)
+
+// storageArguments are the extended arguments set from the storage section of the tuning configmap.
+var storageArguments = []string{
+	"pv-binder-sync-period",
+	"attach-detach-reconcile-sync-period",
+	"concurrent-ephemeralvolume-syncs",
+}
+
+// ObserveStorageTuning fills in the sync periods of the volume controllers set in the storage section of the tuning
+// configmap. The values are validated when the tuning configmap is parsed.
+func ObserveStorageTuning(genericListers configobserver.Listers, recorder events.Recorder, existingConfig map[string]interface{}) (map[string]interface{}, []error) {
+	listers := genericListers.(configobservation.Listers)
+	errs := []error{}
+
+	previouslyObservedConfig := map[string]interface{}{}
+	for _, argument := range storageArguments {
+		path := []string{"extendedArguments", argument}
+		if value, _, _ := unstructured.NestedStringSlice(existingConfig, path...); len(value) > 0 {
+			if err := unstructured.SetNestedStringSlice(previouslyObservedConfig, value, path...); err != nil {
+				errs = append(errs, err)
+			}
+		}
+	}
+
+	tuningConfig, err := tuning.Get(listers.ConfigMapLister())
+	if err != nil {
+		return previouslyObservedConfig, append(errs, err)
+	}
+
+	arguments := map[string]string{}
+	if period := tuningConfig.Storage.PVBinderSyncPeriod; period != nil {
+		arguments["pv-binder-sync-period"] = period.Duration.String()
+	}
+	if period := tuningConfig.Storage.AttachDetachReconcileSyncPeriod; period != nil {
+		arguments["attach-detach-reconcile-sync-period"] = period.Duration.String()
+	}
+	if syncs := tuningConfig.Storage.ConcurrentEphemeralVolumeSyncs; syncs > 0 {
+		arguments["concurrent-ephemeralvolume-syncs"] = strconv.Itoa(int(syncs))
+	}
+
+	observedConfig := map[string]interface{}{}
+	for argument, value := range arguments {
+		if err := unstructured.SetNestedStringSlice(observedConfig, []string{value}, "extendedArguments", argument); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	if len(errs) > 0 {
+		return previouslyObservedConfig, errs
+	}
+
+	if !reflect.DeepEqual(previouslyObservedConfig, observedConfig) {
+		recorder.Eventf("ObserveStorageTuning", "Volume controller settings changed to %v", arguments)
+	}
+	return observedConfig, errs
+}
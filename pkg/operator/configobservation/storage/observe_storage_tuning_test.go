@@ -0,0 +1,108 @@
+package storage
+
+import (
+	"reflect"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	corev1listers "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/tools/cache"
+
+	"github.com/openshift/library-go/pkg/operator/events"
+
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/configobservation"
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/operatorclient"
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/tuning"
+)
+
+func TestObserveStorageTuning(t *testing.T) {
+	storageConfig := map[string]interface{}{
+		"extendedArguments": map[string]interface{}{
+			"pv-binder-sync-period":               []interface{}{"1m0s"},
+			"attach-detach-reconcile-sync-period": []interface{}{"2m0s"},
+			"concurrent-ephemeralvolume-syncs":    []interface{}{"10"},
+		},
+	}
+
+	tests := []struct {
+		name          string
+		tuningConfig  string
+		input         map[string]interface{}
+		expected      map[string]interface{}
+		expectedError bool
+	}{
+		{
+			name:     "no tuning configmap",
+			input:    map[string]interface{}{},
+			expected: map[string]interface{}{},
+		},
+		{
+			name:         "all set",
+			tuningConfig: "storage:\n  pvBinderSyncPeriod: 1m\n  attachDetachReconcileSyncPeriod: 2m\n  concurrentEphemeralVolumeSyncs: 10",
+			input:        map[string]interface{}{},
+			expected:     storageConfig,
+		},
+		{
+			name:         "only the binder",
+			tuningConfig: "storage:\n  pvBinderSyncPeriod: 30s",
+			input:        storageConfig,
+			expected: map[string]interface{}{
+				"extendedArguments": map[string]interface{}{
+					"pv-binder-sync-period": []interface{}{"30s"},
+				},
+			},
+		},
+		{
+			name:     "removed",
+			input:    storageConfig,
+			expected: map[string]interface{}{},
+		},
+		{
+			name:          "too short binder period keeps the previous config",
+			tuningConfig:  "storage:\n  pvBinderSyncPeriod: 1s",
+			input:         storageConfig,
+			expected:      storageConfig,
+			expectedError: true,
+		},
+		{
+			name:          "too long reconcile period keeps the previous config",
+			tuningConfig:  "storage:\n  attachDetachReconcileSyncPeriod: 1h",
+			input:         storageConfig,
+			expected:      storageConfig,
+			expectedError: true,
+		},
+		{
+			name:          "too many ephemeral volume syncs keep the previous config",
+			tuningConfig:  "storage:\n  concurrentEphemeralVolumeSyncs: 100",
+			input:         storageConfig,
+			expected:      storageConfig,
+			expectedError: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			configMapIndexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{})
+			if len(test.tuningConfig) > 0 {
+				if err := configMapIndexer.Add(&corev1.ConfigMap{
+					ObjectMeta: metav1.ObjectMeta{Namespace: operatorclient.OperatorNamespace, Name: tuning.ConfigMapName},
+					Data:       map[string]string{tuning.ConfigKey: test.tuningConfig},
+				}); err != nil {
+					t.Fatal(err)
+				}
+			}
+			listers := configobservation.Listers{
+				ConfigMapLister_: corev1listers.NewConfigMapLister(configMapIndexer),
+			}
+
+			result, errs := ObserveStorageTuning(listers, events.NewInMemoryRecorder("storage"), test.input)
+			if test.expectedError != (len(errs) > 0) {
+				t.Fatalf("expected error %v, got %v", test.expectedError, errs)
+			}
+			if !reflect.DeepEqual(test.expected, result) {
+				t.Errorf("expected %v, got %v", test.expected, result)
+			}
+		})
+	}
+}
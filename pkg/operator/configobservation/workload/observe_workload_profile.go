@@ -0,0 +1,72 @@
+package workload
+
+import (
+	"reflect"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/openshift/library-go/pkg/operator/configobserver"
+	"github.com/openshift/library-go/pkg/operator/events"
+
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/configobservation"
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/tuning"
+)
+
+// profileArguments are the extended arguments set by each workload profile. Arguments not listed for a profile
+// keep the kube-controller-manager defaults. The garbage collector workers of the profiles are set by
+// garbagecollector.ObserveGarbageCollectorTuning, which also scales them.
+var profileArguments = map[tuning.WorkloadProfile]map[string]string{
+	tuning.CIWorkloadProfile: {
+		// namespace deletion is the bottleneck when thousands of test namespaces are torn down (default 10)
+		"concurrent-namespace-syncs": "50",
+		// test jobs are short lived and numerous (default 5)
+		"concurrent-job-syncs": "20",
+		// collect terminated test pods long before they pile up (default 12500)
+		"terminated-pod-gc-threshold": "1000",
+		// resync the shared informers more often so missed events are recovered quicker (default 12h)
+		"min-resync-period": "2h",
+	},
+}
+
+// ObserveWorkloadProfile fills in the controller concurrency and resync extended arguments for the workload
+// profile selected in the tuning configmap.
+func ObserveWorkloadProfile(genericListers configobserver.Listers, recorder events.Recorder, existingConfig map[string]interface{}) (map[string]interface{}, []error) {
+	listers := genericListers.(configobservation.Listers)
+	errs := []error{}
+
+	previouslyObservedConfig := map[string]interface{}{}
+	for _, arguments := range profileArguments {
+		for argument := range arguments {
+			path := []string{"extendedArguments", argument}
+			if value, _, _ := unstructured.NestedStringSlice(existingConfig, path...); len(value) > 0 {
+				if err := unstructured.SetNestedStringSlice(previouslyObservedConfig, value, path...); err != nil {
+					errs = append(errs, err)
+				}
+			}
+		}
+	}
+
+	tuningConfig, err := tuning.Get(listers.ConfigMapLister())
+	if err != nil {
+		return previouslyObservedConfig, append(errs, err)
+	}
+
+	observedConfig := map[string]interface{}{}
+	for argument, value := range profileArguments[tuningConfig.WorkloadProfile] {
+		if err := unstructured.SetNestedStringSlice(observedConfig, []string{value}, "extendedArguments", argument); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	if len(errs) > 0 {
+		return previouslyObservedConfig, errs
+	}
+
+	if !reflect.DeepEqual(previouslyObservedConfig, observedConfig) {
+		profile := tuningConfig.WorkloadProfile
+		if len(profile) == 0 {
+			profile = tuning.DefaultWorkloadProfile
+		}
+		recorder.Eventf("ObserveWorkloadProfile", "Controller worker counts changed to match the %q workload profile", profile)
+	}
+	return observedConfig, errs
+}
@@ -0,0 +1,86 @@
+package workload
+
+import (
+	"reflect"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	corev1listers "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/tools/cache"
+
+	"github.com/openshift/library-go/pkg/operator/events"
+
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/configobservation"
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/operatorclient"
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/tuning"
+)
+
+func TestObserveWorkloadProfile(t *testing.T) {
+	ciConfig := map[string]interface{}{
+		"extendedArguments": map[string]interface{}{
+			"concurrent-namespace-syncs":  []interface{}{"50"},
+			"concurrent-job-syncs":        []interface{}{"20"},
+			"terminated-pod-gc-threshold": []interface{}{"1000"},
+			"min-resync-period":           []interface{}{"2h"},
+		},
+	}
+
+	tests := []struct {
+		name          string
+		tuningConfig  string
+		input         map[string]interface{}
+		expected      map[string]interface{}
+		expectedError bool
+	}{
+		{
+			name:     "no tuning configmap",
+			input:    map[string]interface{}{},
+			expected: map[string]interface{}{},
+		},
+		{
+			name:         "default profile",
+			tuningConfig: "workloadProfile: Default",
+			input:        ciConfig,
+			expected:     map[string]interface{}{},
+		},
+		{
+			name:         "ci profile",
+			tuningConfig: "workloadProfile: CI",
+			input:        map[string]interface{}{},
+			expected:     ciConfig,
+		},
+		{
+			name:          "invalid profile keeps the previous config",
+			tuningConfig:  "workloadProfile: Fast",
+			input:         ciConfig,
+			expected:      ciConfig,
+			expectedError: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			indexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{})
+			if len(test.tuningConfig) > 0 {
+				if err := indexer.Add(&corev1.ConfigMap{
+					ObjectMeta: metav1.ObjectMeta{Namespace: operatorclient.OperatorNamespace, Name: tuning.ConfigMapName},
+					Data:       map[string]string{tuning.ConfigKey: test.tuningConfig},
+				}); err != nil {
+					t.Fatal(err)
+				}
+			}
+			listers := configobservation.Listers{
+				ConfigMapLister_: corev1listers.NewConfigMapLister(indexer),
+			}
+
+			result, errs := ObserveWorkloadProfile(listers, events.NewInMemoryRecorder("workload"), test.input)
+			if test.expectedError != (len(errs) > 0) {
+				t.Fatalf("expected error %v, got %v", test.expectedError, errs)
+			}
+			if !reflect.DeepEqual(test.expected, result) {
+				t.Errorf("expected %v, got %v", test.expected, result)
+			}
+		})
+	}
+}
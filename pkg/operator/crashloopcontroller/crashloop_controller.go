@@ -0,0 +1,202 @@
+package crashloopcontroller
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
+	corev1listers "k8s.io/client-go/listers/core/v1"
+
+	operatorv1 "github.com/openshift/api/operator/v1"
+	"github.com/openshift/library-go/pkg/controller/factory"
+	"github.com/openshift/library-go/pkg/operator/events"
+	"github.com/openshift/library-go/pkg/operator/v1helpers"
+
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/operatorclient"
+)
+
+const (
+	crashLoopingCondition = "KubeControllerManagerCrashLooping"
+
+	// RestartWindow is how far back the restarts of a container are counted.
+	RestartWindow = 15 * time.Minute
+	// RestartThreshold is the number of restarts within the RestartWindow that make a container crashlooping.
+	RestartThreshold = 3
+
+	// maxTerminationMessage caps the termination message of a container quoted in the condition.
+	maxTerminationMessage = 256
+)
+
+// restartSample is the restart count of a container seen at a sync.
+type restartSample struct {
+	at           time.Time
+	restartCount int32
+}
+
+// containerKey identifies a container of a pod, a new pod on the same node starts counting from scratch.
+type containerKey struct {
+	podUID    types.UID
+	container string
+}
+
+// CrashLoopController counts the restarts of the containers of the kube-controller-manager static pods and reports
+// the ones restarting RestartThreshold times within the RestartWindow, or waiting in CrashLoopBackOff, in the
+// KubeControllerManagerCrashLooping condition. The node statuses only follow the revisions, a crashlooping operand
+// on its current revision goes unnoticed otherwise.
+type CrashLoopController struct {
+	operatorClient v1helpers.StaticPodOperatorClient
+	podLister      corev1listers.PodLister
+	now            func() time.Time
+
+	// samples are the restart counts seen within the RestartWindow, oldest first. The oldest sample is the baseline
+	// the restarts are counted from. They only live as long as the operator process, restarts before the operator
+	// started are not counted.
+	samples map[containerKey][]restartSample
+}
+
+func NewCrashLoopController(
+	operatorClient v1helpers.StaticPodOperatorClient,
+	kubeInformersForNamespaces v1helpers.KubeInformersForNamespaces,
+	eventRecorder events.Recorder,
+) factory.Controller {
+	c := &CrashLoopController{
+		operatorClient: operatorClient,
+		podLister:      kubeInformersForNamespaces.InformersFor(operatorclient.TargetNamespace).Core().V1().Pods().Lister(),
+		now:            time.Now,
+		samples:        map[containerKey][]restartSample{},
+	}
+
+	return factory.New().WithInformers(
+		kubeInformersForNamespaces.InformersFor(operatorclient.TargetNamespace).Core().V1().Pods().Informer(),
+	).ResyncEvery(time.Minute).WithSync(c.sync).ToController("CrashLoopController", eventRecorder)
+}
+
+func (c *CrashLoopController) sync(ctx context.Context, syncCtx factory.SyncContext) error {
+	pods, err := c.podLister.Pods(operatorclient.TargetNamespace).List(labels.SelectorFromSet(labels.Set{"app": "kube-controller-manager"}))
+	if err != nil {
+		return err
+	}
+	condition := crashLoopingConditionFor(c.crashLoops(pods, c.now()))
+	_, _, err = v1helpers.UpdateStaticPodStatus(ctx, c.operatorClient, v1helpers.UpdateStaticPodConditionFn(condition))
+	return err
+}
+
+// crashLoop is a crashlooping container.
+type crashLoop struct {
+	nodeName  string
+	container string
+	// restarts within the RestartWindow
+	restarts int32
+	// backOff is set when the container waits in CrashLoopBackOff
+	backOff bool
+	// lastTermination describes the last time the container terminated
+	lastTermination string
+}
+
+// crashLoops records the restart counts of the containers of the pods and returns the crashlooping ones, sorted by
+// node and container. Containers of pods that are gone are forgotten.
+func (c *CrashLoopController) crashLoops(pods []*corev1.Pod, now time.Time) []crashLoop {
+	seen := map[containerKey]bool{}
+	var loops []crashLoop
+	for _, pod := range pods {
+		for _, status := range pod.Status.ContainerStatuses {
+			key := containerKey{podUID: pod.UID, container: status.Name}
+			seen[key] = true
+			c.samples[key] = addSample(c.samples[key], restartSample{at: now, restartCount: status.RestartCount}, now)
+			restarts := status.RestartCount - c.samples[key][0].restartCount
+
+			backOff := status.State.Waiting != nil && status.State.Waiting.Reason == "CrashLoopBackOff"
+			if !backOff && restarts < RestartThreshold {
+				continue
+			}
+			loops = append(loops, crashLoop{
+				nodeName:        pod.Spec.NodeName,
+				container:       status.Name,
+				restarts:        restarts,
+				backOff:         backOff,
+				lastTermination: lastTermination(status),
+			})
+		}
+	}
+	for key := range c.samples {
+		if !seen[key] {
+			delete(c.samples, key)
+		}
+	}
+
+	sort.Slice(loops, func(i, j int) bool {
+		if loops[i].nodeName != loops[j].nodeName {
+			return loops[i].nodeName < loops[j].nodeName
+		}
+		return loops[i].container < loops[j].container
+	})
+	return loops
+}
+
+// addSample appends the sample and drops the samples older than the RestartWindow, except for the newest of them,
+// which stays the baseline of the window.
+func addSample(samples []restartSample, sample restartSample, now time.Time) []restartSample {
+	if len(samples) > 0 && sample.restartCount < samples[len(samples)-1].restartCount {
+		// the count went back, the container status was recreated
+		samples = nil
+	}
+	samples = append(samples, sample)
+	for len(samples) > 1 && now.Sub(samples[1].at) >= RestartWindow {
+		samples = samples[1:]
+	}
+	return samples
+}
+
+// lastTermination describes the last termination of the container, or returns an empty string when it did not
+// terminate yet.
+func lastTermination(status corev1.ContainerStatus) string {
+	terminated := status.LastTerminationState.Terminated
+	if terminated == nil {
+		return ""
+	}
+	reason := terminated.Reason
+	if len(reason) == 0 {
+		reason = "unknown reason"
+	}
+	description := fmt.Sprintf("%s (exit code %d)", reason, terminated.ExitCode)
+	if message := strings.TrimSpace(terminated.Message); len(message) > 0 {
+		if len(message) > maxTerminationMessage {
+			message = message[len(message)-maxTerminationMessage:]
+		}
+		description += ": " + message
+	}
+	return description
+}
+
+// crashLoopingConditionFor reports the crashlooping containers and their nodes.
+func crashLoopingConditionFor(loops []crashLoop) operatorv1.OperatorCondition {
+	if len(loops) == 0 {
+		return operatorv1.OperatorCondition{
+			Type:   crashLoopingCondition,
+			Status: operatorv1.ConditionFalse,
+		}
+	}
+
+	var lines []string
+	for _, loop := range loops {
+		line := fmt.Sprintf("node %s: container %s restarted %d times in the last %s", loop.nodeName, loop.container, loop.restarts, RestartWindow)
+		if loop.backOff {
+			line += ", waiting in CrashLoopBackOff"
+		}
+		if len(loop.lastTermination) > 0 {
+			line += ", last terminated with " + loop.lastTermination
+		}
+		lines = append(lines, line)
+	}
+	return operatorv1.OperatorCondition{
+		Type:    crashLoopingCondition,
+		Status:  operatorv1.ConditionTrue,
+		Reason:  "ContainerRestarts",
+		Message: strings.Join(lines, "\n"),
+	}
+}
@@ -0,0 +1,80 @@
+package crashloopcontroller
+
+import (
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	operatorv1 "github.com/openshift/api/operator/v1"
+)
+
+func kcmPod(uid types.UID, nodeName string, restartCount int32, waitingReason string) *corev1.Pod {
+	status := corev1.ContainerStatus{
+		Name:         "kube-controller-manager",
+		RestartCount: restartCount,
+		LastTerminationState: corev1.ContainerState{
+			Terminated: &corev1.ContainerStateTerminated{Reason: "Error", ExitCode: 255, Message: "failed to load config\n"},
+		},
+	}
+	if len(waitingReason) > 0 {
+		status.State.Waiting = &corev1.ContainerStateWaiting{Reason: waitingReason}
+	}
+	return &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "kube-controller-manager-" + nodeName, UID: uid},
+		Spec:       corev1.PodSpec{NodeName: nodeName},
+		Status:     corev1.PodStatus{ContainerStatuses: []corev1.ContainerStatus{status}},
+	}
+}
+
+func TestCrashLoops(t *testing.T) {
+	start := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	c := &CrashLoopController{samples: map[containerKey][]restartSample{}}
+
+	// restarts before the first sync are not counted
+	if loops := c.crashLoops([]*corev1.Pod{kcmPod("a", "master-0", 10, "")}, start); len(loops) != 0 {
+		t.Fatalf("expected no crashloop at the first sync, got %v", loops)
+	}
+	if loops := c.crashLoops([]*corev1.Pod{kcmPod("a", "master-0", 12, "")}, start.Add(5*time.Minute)); len(loops) != 0 {
+		t.Fatalf("expected no crashloop below the threshold, got %v", loops)
+	}
+	loops := c.crashLoops([]*corev1.Pod{kcmPod("a", "master-0", 13, "")}, start.Add(10*time.Minute))
+	if len(loops) != 1 || loops[0].nodeName != "master-0" || loops[0].restarts != 3 {
+		t.Fatalf("expected 3 restarts on master-0, got %v", loops)
+	}
+	if expected := "Error (exit code 255): failed to load config"; loops[0].lastTermination != expected {
+		t.Errorf("expected last termination %q, got %q", expected, loops[0].lastTermination)
+	}
+
+	// the restarts age out of the window
+	if loops := c.crashLoops([]*corev1.Pod{kcmPod("a", "master-0", 13, "")}, start.Add(30*time.Minute)); len(loops) != 0 {
+		t.Fatalf("expected the restarts to age out, got %v", loops)
+	}
+
+	// a new pod starts from scratch, CrashLoopBackOff is reported at once
+	loops = c.crashLoops([]*corev1.Pod{kcmPod("b", "master-0", 1, "CrashLoopBackOff")}, start.Add(31*time.Minute))
+	if len(loops) != 1 || !loops[0].backOff || loops[0].restarts != 0 {
+		t.Fatalf("expected a backoff without counted restarts, got %v", loops)
+	}
+	if _, ok := c.samples[containerKey{podUID: "a", container: "kube-controller-manager"}]; ok {
+		t.Errorf("expected the samples of the deleted pod to be forgotten")
+	}
+}
+
+func TestCrashLoopingConditionFor(t *testing.T) {
+	if condition := crashLoopingConditionFor(nil); condition.Status != operatorv1.ConditionFalse {
+		t.Errorf("expected False without crashloops, got %v", condition)
+	}
+
+	condition := crashLoopingConditionFor([]crashLoop{
+		{nodeName: "master-0", container: "kube-controller-manager", restarts: 4, backOff: true, lastTermination: "Error (exit code 1)"},
+		{nodeName: "master-2", container: "cluster-policy-controller", restarts: 3},
+	})
+	expected := "node master-0: container kube-controller-manager restarted 4 times in the last 15m0s, waiting in CrashLoopBackOff, last terminated with Error (exit code 1)\n" +
+		"node master-2: container cluster-policy-controller restarted 3 times in the last 15m0s"
+	if condition.Status != operatorv1.ConditionTrue || condition.Message != expected {
+		t.Errorf("expected True %q, got %s %q", expected, condition.Status, condition.Message)
+	}
+}
@@ -0,0 +1,262 @@
+package csrselftestcontroller
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"fmt"
+	"time"
+
+	certificatesv1 "k8s.io/api/certificates/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/kubernetes"
+	certificatesv1client "k8s.io/client-go/kubernetes/typed/certificates/v1"
+	corev1listers "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/util/cert"
+	"k8s.io/utils/ptr"
+
+	operatorv1 "github.com/openshift/api/operator/v1"
+	"github.com/openshift/library-go/pkg/controller/factory"
+	"github.com/openshift/library-go/pkg/operator/events"
+	"github.com/openshift/library-go/pkg/operator/v1helpers"
+
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/operatorclient"
+)
+
+const (
+	// SelfTestAnnotation on the kubecontrollermanager/cluster resource runs the CSR self-test again whenever its
+	// value changes, e.g. set to the current time.
+	SelfTestAnnotation = "kubecontrollermanager.operator.openshift.io/csr-self-test"
+
+	csrSelfTestCondition = "CSRSelfTestDegraded"
+
+	// selfTestNodeName is the node the self-test CSR is made for. No node by that name exists, the certificate is
+	// only kept in memory and expires after the shortest lifetime the signer allows.
+	selfTestNodeName = "kube-controller-manager-operator-self-test"
+	selfTestLabel    = "kubecontrollermanager.operator.openshift.io/csr-self-test"
+
+	// SelfTestTimeout is how long the self-test waits for the kube-controller-manager to sign its CSR.
+	SelfTestTimeout = 2 * time.Minute
+)
+
+// CSRSelfTestController requests a kubelet-serving certificate the way a joining node does, approves it and checks
+// that the kube-controller-manager signs it with a certificate csr-controller-ca verifies. It runs when the operator
+// starts, after every csr-signer rotation and whenever the SelfTestAnnotation changes, and reports the result in
+// the CSRSelfTestDegraded condition. A broken CSR path otherwise only shows up when nodes fail to join.
+type CSRSelfTestController struct {
+	operatorClient  v1helpers.StaticPodOperatorClient
+	operatorLister  cache.GenericLister
+	csrClient       certificatesv1client.CertificateSigningRequestInterface
+	secretLister    corev1listers.SecretLister
+	configMapLister corev1listers.ConfigMapLister
+	timeout         time.Duration
+	now             func() time.Time
+
+	// lastTrigger is the trigger of the last self-test
+	lastTrigger *selfTestTrigger
+}
+
+// selfTestTrigger is what a self-test ran for, a change of any field runs it again.
+type selfTestTrigger struct {
+	annotation string
+	signerCert []byte
+}
+
+func NewCSRSelfTestController(
+	operatorClient v1helpers.StaticPodOperatorClient,
+	operatorLister cache.GenericLister,
+	kubeInformersForNamespaces v1helpers.KubeInformersForNamespaces,
+	kubeClient kubernetes.Interface,
+	eventRecorder events.Recorder,
+) factory.Controller {
+	c := &CSRSelfTestController{
+		operatorClient:  operatorClient,
+		operatorLister:  operatorLister,
+		csrClient:       kubeClient.CertificatesV1().CertificateSigningRequests(),
+		secretLister:    kubeInformersForNamespaces.SecretLister(),
+		configMapLister: kubeInformersForNamespaces.ConfigMapLister(),
+		timeout:         SelfTestTimeout,
+		now:             time.Now,
+	}
+
+	return factory.New().WithInformers(
+		operatorClient.Informer(),
+		kubeInformersForNamespaces.InformersFor(operatorclient.OperatorNamespace).Core().V1().ConfigMaps().Informer(),
+	).WithFilteredEventsInformers(
+		factory.NamesFilter("csr-signer"),
+		kubeInformersForNamespaces.InformersFor(operatorclient.TargetNamespace).Core().V1().Secrets().Informer(),
+	).ResyncEvery(10*time.Minute).WithSync(c.sync).ToController("CSRSelfTestController", eventRecorder)
+}
+
+func (c *CSRSelfTestController) sync(ctx context.Context, syncCtx factory.SyncContext) error {
+	trigger, err := c.currentTrigger()
+	if err != nil {
+		return err
+	}
+	if trigger == nil {
+		// without a csr-signer nothing can be signed, which the target config controller already reports
+		return nil
+	}
+	if c.lastTrigger != nil && c.lastTrigger.annotation == trigger.annotation && bytes.Equal(c.lastTrigger.signerCert, trigger.signerCert) {
+		return nil
+	}
+
+	condition := operatorv1.OperatorCondition{
+		Type:    csrSelfTestCondition,
+		Status:  operatorv1.ConditionFalse,
+		Reason:  "AsExpected",
+		Message: fmt.Sprintf("A %s CSR was signed and verified by csr-controller-ca at %s", certificatesv1.KubeletServingSignerName, c.now().UTC().Format(time.RFC3339)),
+	}
+	if testErr := c.selfTest(ctx); testErr != nil {
+		syncCtx.Recorder().Warningf("CSRSelfTestFailed", "The CSR self-test failed: %v", testErr)
+		condition.Status = operatorv1.ConditionTrue
+		condition.Reason = "SelfTestFailed"
+		condition.Message = fmt.Sprintf("A %s CSR was not signed and verified: %v", certificatesv1.KubeletServingSignerName, testErr)
+	}
+	if _, _, err := v1helpers.UpdateStaticPodStatus(ctx, c.operatorClient, v1helpers.UpdateStaticPodConditionFn(condition)); err != nil {
+		return err
+	}
+	c.lastTrigger = trigger
+	return nil
+}
+
+// currentTrigger returns the annotation value and the csr-signer certificate of the target namespace, or nil without
+// a csr-signer.
+func (c *CSRSelfTestController) currentTrigger() (*selfTestTrigger, error) {
+	signer, err := c.secretLister.Secrets(operatorclient.TargetNamespace).Get("csr-signer")
+	if apierrors.IsNotFound(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	uncastOperator, err := c.operatorLister.Get("cluster")
+	if err != nil {
+		return nil, err
+	}
+	operator, err := meta.Accessor(uncastOperator)
+	if err != nil {
+		return nil, err
+	}
+	return &selfTestTrigger{
+		annotation: operator.GetAnnotations()[SelfTestAnnotation],
+		signerCert: signer.Data[corev1.TLSCertKey],
+	}, nil
+}
+
+// selfTest creates, approves and waits for a kubelet-serving CSR, then verifies its certificate. The CSR is deleted
+// in any case.
+func (c *CSRSelfTestController) selfTest(ctx context.Context) error {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		return err
+	}
+	request, err := x509.CreateCertificateRequest(rand.Reader, &x509.CertificateRequest{
+		Subject:  pkix.Name{CommonName: "system:node:" + selfTestNodeName, Organization: []string{"system:nodes"}},
+		DNSNames: []string{selfTestNodeName},
+	}, key)
+	if err != nil {
+		return err
+	}
+
+	csr, err := c.csrClient.Create(ctx, &certificatesv1.CertificateSigningRequest{
+		ObjectMeta: metav1.ObjectMeta{
+			GenerateName: selfTestNodeName + "-",
+			Labels:       map[string]string{selfTestLabel: "true"},
+		},
+		Spec: certificatesv1.CertificateSigningRequestSpec{
+			Request:    pem.EncodeToMemory(&pem.Block{Type: cert.CertificateRequestBlockType, Bytes: request}),
+			SignerName: certificatesv1.KubeletServingSignerName,
+			// the shortest lifetime the signer allows
+			ExpirationSeconds: ptr.To[int32](600),
+			Usages: []certificatesv1.KeyUsage{
+				certificatesv1.UsageDigitalSignature,
+				certificatesv1.UsageKeyEncipherment,
+				certificatesv1.UsageServerAuth,
+			},
+		},
+	}, metav1.CreateOptions{})
+	if err != nil {
+		return fmt.Errorf("failed to create the CSR: %v", err)
+	}
+	defer func() {
+		// the context may be done already, the CSR must not be left behind
+		deleteCtx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		defer cancel()
+		if err := c.csrClient.Delete(deleteCtx, csr.Name, metav1.DeleteOptions{}); err != nil && !apierrors.IsNotFound(err) {
+			utilruntime.HandleError(fmt.Errorf("failed to delete the self-test CSR %s: %v", csr.Name, err))
+		}
+	}()
+
+	csr.Status.Conditions = append(csr.Status.Conditions, certificatesv1.CertificateSigningRequestCondition{
+		Type:    certificatesv1.CertificateApproved,
+		Status:  corev1.ConditionTrue,
+		Reason:  "KubeControllerManagerOperatorSelfTest",
+		Message: "Approved by the CSR self-test of the kube-controller-manager operator",
+	})
+	if _, err := c.csrClient.UpdateApproval(ctx, csr.Name, csr, metav1.UpdateOptions{}); err != nil {
+		return fmt.Errorf("failed to approve the CSR: %v", err)
+	}
+
+	var certificate []byte
+	err = wait.PollUntilContextTimeout(ctx, time.Second, c.timeout, true, func(ctx context.Context) (bool, error) {
+		current, err := c.csrClient.Get(ctx, csr.Name, metav1.GetOptions{})
+		if err != nil {
+			return false, err
+		}
+		for _, condition := range current.Status.Conditions {
+			if condition.Type == certificatesv1.CertificateDenied || condition.Type == certificatesv1.CertificateFailed {
+				return false, fmt.Errorf("the CSR is %s: %s", condition.Type, condition.Message)
+			}
+		}
+		certificate = current.Status.Certificate
+		return len(certificate) > 0, nil
+	})
+	if err != nil {
+		return fmt.Errorf("the CSR was not signed within %s: %v", c.timeout, err)
+	}
+	return c.verify(certificate, key)
+}
+
+// verify checks that the certificate is for the self-test key and that csr-controller-ca verifies it for serving.
+func (c *CSRSelfTestController) verify(certificatePEM []byte, key *rsa.PrivateKey) error {
+	certs, err := cert.ParseCertsPEM(certificatePEM)
+	if err != nil {
+		return fmt.Errorf("failed to parse the certificate: %v", err)
+	}
+	if !key.PublicKey.Equal(certs[0].PublicKey) {
+		return fmt.Errorf("the certificate is not for the key of the CSR")
+	}
+
+	caBundle, err := c.configMapLister.ConfigMaps(operatorclient.OperatorNamespace).Get("csr-controller-ca")
+	if err != nil {
+		return fmt.Errorf("failed to get csr-controller-ca: %v", err)
+	}
+	roots := x509.NewCertPool()
+	if !roots.AppendCertsFromPEM([]byte(caBundle.Data["ca-bundle.crt"])) {
+		return fmt.Errorf("csr-controller-ca holds no certificate")
+	}
+	intermediates := x509.NewCertPool()
+	for _, intermediate := range certs[1:] {
+		intermediates.AddCert(intermediate)
+	}
+	if _, err := certs[0].Verify(x509.VerifyOptions{
+		Roots:         roots,
+		Intermediates: intermediates,
+		CurrentTime:   c.now(),
+		KeyUsages:     []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+	}); err != nil {
+		return fmt.Errorf("csr-controller-ca does not verify the certificate: %v", err)
+	}
+	return nil
+}
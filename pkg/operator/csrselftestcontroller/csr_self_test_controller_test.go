@@ -0,0 +1,202 @@
+package csrselftestcontroller
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/pem"
+	"math/big"
+	"strings"
+	"testing"
+	"time"
+
+	certificatesv1 "k8s.io/api/certificates/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+	corev1listers "k8s.io/client-go/listers/core/v1"
+	clienttesting "k8s.io/client-go/testing"
+	"k8s.io/client-go/tools/cache"
+
+	operatorv1 "github.com/openshift/api/operator/v1"
+	"github.com/openshift/library-go/pkg/controller/factory"
+	"github.com/openshift/library-go/pkg/crypto"
+	"github.com/openshift/library-go/pkg/operator/events"
+	"github.com/openshift/library-go/pkg/operator/v1helpers"
+
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/operatorclient"
+)
+
+func TestSync(t *testing.T) {
+	signerConfig, err := crypto.MakeSelfSignedCAConfigForDuration("csr-signer", time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	otherConfig, err := crypto.MakeSelfSignedCAConfigForDuration("other", time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	signerPEM, _, err := signerConfig.GetPEMBytes()
+	if err != nil {
+		t.Fatal(err)
+	}
+	otherPEM, _, err := otherConfig.GetPEMBytes()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name           string
+		caBundle       []byte
+		deny           bool
+		expectedStatus operatorv1.ConditionStatus
+		expectedReason string
+	}{
+		{
+			name:           "signed and verified",
+			caBundle:       signerPEM,
+			expectedStatus: operatorv1.ConditionFalse,
+			expectedReason: "AsExpected",
+		},
+		{
+			name:           "not verified by csr-controller-ca",
+			caBundle:       otherPEM,
+			expectedStatus: operatorv1.ConditionTrue,
+			expectedReason: "SelfTestFailed",
+		},
+		{
+			name:           "denied",
+			caBundle:       signerPEM,
+			deny:           true,
+			expectedStatus: operatorv1.ConditionTrue,
+			expectedReason: "SelfTestFailed",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			kubeClient := fake.NewSimpleClientset()
+			kubeClient.PrependReactor("create", "certificatesigningrequests", func(action clienttesting.Action) (bool, runtime.Object, error) {
+				csr := action.(clienttesting.CreateAction).GetObject().(*certificatesv1.CertificateSigningRequest)
+				csr.Name = csr.GenerateName + "test"
+				return false, nil, nil
+			})
+			kubeClient.PrependReactor("update", "certificatesigningrequests", func(action clienttesting.Action) (bool, runtime.Object, error) {
+				if action.GetSubresource() != "approval" {
+					return false, nil, nil
+				}
+				csr := action.(clienttesting.UpdateAction).GetObject().(*certificatesv1.CertificateSigningRequest)
+				if test.deny {
+					csr.Status.Conditions = []certificatesv1.CertificateSigningRequestCondition{{Type: certificatesv1.CertificateDenied, Status: corev1.ConditionTrue, Message: "denied by test"}}
+					return false, nil, nil
+				}
+				csr.Status.Certificate = sign(t, signerConfig, csr.Spec.Request)
+				return false, nil, nil
+			})
+
+			operatorIndexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{})
+			if err := operatorIndexer.Add(&operatorv1.KubeControllerManager{ObjectMeta: metav1.ObjectMeta{Name: "cluster"}}); err != nil {
+				t.Fatal(err)
+			}
+			secretIndexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc})
+			if err := secretIndexer.Add(&corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{Namespace: operatorclient.TargetNamespace, Name: "csr-signer"},
+				Data:       map[string][]byte{corev1.TLSCertKey: signerPEM},
+			}); err != nil {
+				t.Fatal(err)
+			}
+			configMapIndexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc})
+			if err := configMapIndexer.Add(&corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{Namespace: operatorclient.OperatorNamespace, Name: "csr-controller-ca"},
+				Data:       map[string]string{"ca-bundle.crt": string(test.caBundle)},
+			}); err != nil {
+				t.Fatal(err)
+			}
+
+			operatorClient := v1helpers.NewFakeStaticPodOperatorClient(&operatorv1.StaticPodOperatorSpec{}, &operatorv1.StaticPodOperatorStatus{}, nil, nil)
+			c := &CSRSelfTestController{
+				operatorClient:  operatorClient,
+				operatorLister:  cache.NewGenericLister(operatorIndexer, operatorv1.GroupVersion.WithResource("kubecontrollermanagers").GroupResource()),
+				csrClient:       kubeClient.CertificatesV1().CertificateSigningRequests(),
+				secretLister:    corev1listers.NewSecretLister(secretIndexer),
+				configMapLister: corev1listers.NewConfigMapLister(configMapIndexer),
+				timeout:         5 * time.Second,
+				now:             time.Now,
+			}
+			syncCtx := factory.NewSyncContext("test", events.NewInMemoryRecorder("test"))
+			if err := c.sync(context.TODO(), syncCtx); err != nil {
+				t.Fatal(err)
+			}
+
+			_, status, _, err := operatorClient.GetStaticPodOperatorState()
+			if err != nil {
+				t.Fatal(err)
+			}
+			condition := v1helpers.FindOperatorCondition(status.Conditions, csrSelfTestCondition)
+			if condition == nil {
+				t.Fatalf("missing %s condition", csrSelfTestCondition)
+			}
+			if condition.Status != test.expectedStatus || condition.Reason != test.expectedReason {
+				t.Errorf("expected %s/%s, got %s/%s: %s", test.expectedStatus, test.expectedReason, condition.Status, condition.Reason, condition.Message)
+			}
+			csrs, err := kubeClient.CertificatesV1().CertificateSigningRequests().List(context.TODO(), metav1.ListOptions{})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(csrs.Items) != 0 {
+				t.Errorf("expected the self-test CSR to be deleted, got %d CSRs", len(csrs.Items))
+			}
+
+			// an unchanged trigger does not run the self-test again
+			kubeClient.ClearActions()
+			if err := c.sync(context.TODO(), syncCtx); err != nil {
+				t.Fatal(err)
+			}
+			if actions := kubeClient.Actions(); len(actions) != 0 {
+				t.Errorf("expected no self-test for an unchanged trigger, got %v", actions)
+			}
+
+			// a new annotation value does
+			if err := operatorIndexer.Update(&operatorv1.KubeControllerManager{ObjectMeta: metav1.ObjectMeta{
+				Name:        "cluster",
+				Annotations: map[string]string{SelfTestAnnotation: "again"},
+			}}); err != nil {
+				t.Fatal(err)
+			}
+			if err := c.sync(context.TODO(), syncCtx); err != nil {
+				t.Fatal(err)
+			}
+			if len(kubeClient.Actions()) == 0 {
+				t.Errorf("expected a self-test for a new annotation value")
+			}
+		})
+	}
+}
+
+func sign(t *testing.T, ca *crypto.TLSCertificateConfig, requestPEM []byte) []byte {
+	block, _ := pem.Decode(requestPEM)
+	request, err := x509.ParseCertificateRequest(block.Bytes)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(request.Subject.CommonName, "system:node:") {
+		t.Errorf("unexpected subject %q", request.Subject.CommonName)
+	}
+	serial, err := rand.Int(rand.Reader, big.NewInt(1<<62))
+	if err != nil {
+		t.Fatal(err)
+	}
+	der, err := x509.CreateCertificate(rand.Reader, &x509.Certificate{
+		SerialNumber: serial,
+		Subject:      request.Subject,
+		DNSNames:     request.DNSNames,
+		NotBefore:    time.Now().Add(-time.Minute),
+		NotAfter:     time.Now().Add(10 * time.Minute),
+		KeyUsage:     x509.KeyUsageDigitalSignature | x509.KeyUsageKeyEncipherment,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+	}, ca.Certs[0], request.PublicKey, ca.Key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+}
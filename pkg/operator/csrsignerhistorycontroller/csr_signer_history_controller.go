@@ -0,0 +1,187 @@
+package csrsignerhistorycontroller
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	corev1client "k8s.io/client-go/kubernetes/typed/core/v1"
+	corev1listers "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/util/cert"
+	"k8s.io/klog/v2"
+
+	"github.com/openshift/library-go/pkg/controller/factory"
+	"github.com/openshift/library-go/pkg/operator/events"
+	"github.com/openshift/library-go/pkg/operator/resource/resourceapply"
+	"github.com/openshift/library-go/pkg/operator/v1helpers"
+
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/operatorclient"
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/targetconfigcontroller"
+)
+
+const (
+	// historyConfigMapName holds the CSRSignerHistory.
+	historyConfigMapName = "csr-signer-history"
+	historyKey           = "history.json"
+
+	// MaxReplacements is the number of replacements kept in the history, the oldest are dropped first. The signer is
+	// rotated about monthly, it covers a few years.
+	MaxReplacements = 50
+
+	// unknownReason is recorded when the csr-signer lacks the targetconfigcontroller.CSRSignerReplaceReasonAnnotation,
+	// e.g. for a signer replaced before the reasons were recorded.
+	unknownReason = "Unknown"
+)
+
+// CSRSignerHistory lists the replacements of the csr-signer of the target namespace, the signer the
+// kube-controller-manager issues the kubelet and client certificates with.
+type CSRSignerHistory struct {
+	// Replacements are sorted by time, oldest first.
+	Replacements []Replacement `json:"replacements"`
+}
+
+// Replacement is a csr-signer replacing the previous one.
+type Replacement struct {
+	// Time is when the replacement was observed.
+	Time metav1.Time `json:"time"`
+	// Reason is why the signer was replaced: Created, PreviousExpired, Rotated or Unknown.
+	Reason string `json:"reason"`
+	// Old is the replaced signer, unset for the first replacement recorded.
+	Old *Certificate `json:"old,omitempty"`
+	New Certificate  `json:"new"`
+}
+
+// Certificate identifies the certificate of a signer, never its key.
+type Certificate struct {
+	// SHA256Fingerprint is the hex encoded SHA-256 of the DER of the certificate.
+	SHA256Fingerprint string      `json:"sha256Fingerprint"`
+	Subject           string      `json:"subject"`
+	NotBefore         metav1.Time `json:"notBefore"`
+	NotAfter          metav1.Time `json:"notAfter"`
+}
+
+// CSRSignerHistoryController records every replacement of the csr-signer of the target namespace in the
+// historyConfigMapName configmap of the operator namespace and in a CSRSignerReplaced event, for the audits of the
+// signer. The replacements were only in the logs of the operator and the recovery controller before.
+type CSRSignerHistoryController struct {
+	secretLister    corev1listers.SecretLister
+	configMapLister corev1listers.ConfigMapLister
+	configMapClient corev1client.ConfigMapsGetter
+	now             func() time.Time
+}
+
+func NewCSRSignerHistoryController(
+	kubeInformersForNamespaces v1helpers.KubeInformersForNamespaces,
+	kubeClient kubernetes.Interface,
+	eventRecorder events.Recorder,
+) factory.Controller {
+	c := &CSRSignerHistoryController{
+		secretLister:    kubeInformersForNamespaces.SecretLister(),
+		configMapLister: kubeInformersForNamespaces.ConfigMapLister(),
+		configMapClient: v1helpers.CachedConfigMapGetter(kubeClient.CoreV1(), kubeInformersForNamespaces),
+		now:             time.Now,
+	}
+
+	return factory.New().WithFilteredEventsInformers(
+		factory.NamesFilter("csr-signer"),
+		kubeInformersForNamespaces.InformersFor(operatorclient.TargetNamespace).Core().V1().Secrets().Informer(),
+	).WithFilteredEventsInformers(
+		factory.NamesFilter(historyConfigMapName),
+		kubeInformersForNamespaces.InformersFor(operatorclient.OperatorNamespace).Core().V1().ConfigMaps().Informer(),
+	).ResyncEvery(10*time.Minute).WithSync(c.sync).ToController("CSRSignerHistoryController", eventRecorder)
+}
+
+func (c *CSRSignerHistoryController) sync(ctx context.Context, syncCtx factory.SyncContext) error {
+	signer, err := c.secretLister.Secrets(operatorclient.TargetNamespace).Get("csr-signer")
+	if apierrors.IsNotFound(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	current, err := signerCertificate(signer)
+	if err != nil {
+		return fmt.Errorf("secret/csr-signer -n %s: %v", operatorclient.TargetNamespace, err)
+	}
+
+	history := &CSRSignerHistory{}
+	configMap, err := c.configMapLister.ConfigMaps(operatorclient.OperatorNamespace).Get(historyConfigMapName)
+	switch {
+	case apierrors.IsNotFound(err):
+	case err != nil:
+		return err
+	default:
+		if err := json.Unmarshal([]byte(configMap.Data[historyKey]), history); err != nil {
+			// the history cannot be recovered, it starts over with the current signer
+			klog.Warningf("Ignoring the invalid %s/%s: %v", operatorclient.OperatorNamespace, historyConfigMapName, err)
+			history = &CSRSignerHistory{}
+		}
+	}
+
+	var previous *Certificate
+	if len(history.Replacements) > 0 {
+		last := history.Replacements[len(history.Replacements)-1].New
+		if last.SHA256Fingerprint == current.SHA256Fingerprint {
+			return nil
+		}
+		previous = &last
+	}
+	replacement := Replacement{
+		Time:   metav1.NewTime(c.now()),
+		Reason: signer.Annotations[targetconfigcontroller.CSRSignerReplaceReasonAnnotation],
+		Old:    previous,
+		New:    *current,
+	}
+	if len(replacement.Reason) == 0 {
+		replacement.Reason = unknownReason
+	}
+	history.Replacements = append(history.Replacements, replacement)
+	if extra := len(history.Replacements) - MaxReplacements; extra > 0 {
+		history.Replacements = history.Replacements[extra:]
+	}
+
+	historyBytes, err := json.MarshalIndent(history, "", "  ")
+	if err != nil {
+		return err
+	}
+	if _, _, err := resourceapply.ApplyConfigMap(ctx, c.configMapClient, syncCtx.Recorder(), &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Namespace: operatorclient.OperatorNamespace, Name: historyConfigMapName},
+		Data:       map[string]string{historyKey: string(historyBytes)},
+	}); err != nil {
+		return err
+	}
+	syncCtx.Recorder().Eventf("CSRSignerReplaced", "%s", replacementMessage(replacement))
+	return nil
+}
+
+// signerCertificate returns the first certificate of the tls.crt of the signer, the one it signs with.
+func signerCertificate(signer *corev1.Secret) (*Certificate, error) {
+	certs, err := cert.ParseCertsPEM(signer.Data["tls.crt"])
+	if err != nil {
+		return nil, err
+	}
+	fingerprint := sha256.Sum256(certs[0].Raw)
+	return &Certificate{
+		SHA256Fingerprint: hex.EncodeToString(fingerprint[:]),
+		Subject:           certs[0].Subject.String(),
+		NotBefore:         metav1.NewTime(certs[0].NotBefore),
+		NotAfter:          metav1.NewTime(certs[0].NotAfter),
+	}, nil
+}
+
+func replacementMessage(replacement Replacement) string {
+	message := fmt.Sprintf("reason=%s new.sha256=%s new.notBefore=%s new.notAfter=%s", replacement.Reason, replacement.New.SHA256Fingerprint,
+		replacement.New.NotBefore.UTC().Format(time.RFC3339), replacement.New.NotAfter.UTC().Format(time.RFC3339))
+	if old := replacement.Old; old != nil {
+		message += fmt.Sprintf(" old.sha256=%s old.notBefore=%s old.notAfter=%s", old.SHA256Fingerprint,
+			old.NotBefore.UTC().Format(time.RFC3339), old.NotAfter.UTC().Format(time.RFC3339))
+	}
+	return "The csr-signer was replaced: " + message
+}
@@ -0,0 +1,112 @@
+package csrsignerhistorycontroller
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+	corev1listers "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/tools/cache"
+
+	"github.com/openshift/library-go/pkg/controller/factory"
+	"github.com/openshift/library-go/pkg/crypto"
+	"github.com/openshift/library-go/pkg/operator/events"
+
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/operatorclient"
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/targetconfigcontroller"
+)
+
+func signerSecret(t *testing.T, name, reason string) *corev1.Secret {
+	signerConfig, err := crypto.MakeSelfSignedCAConfigForDuration(name, time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	certPEM, keyPEM, err := signerConfig.GetPEMBytes()
+	if err != nil {
+		t.Fatal(err)
+	}
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Namespace: operatorclient.TargetNamespace, Name: "csr-signer"},
+		Data:       map[string][]byte{"tls.crt": certPEM, "tls.key": keyPEM},
+	}
+	if len(reason) > 0 {
+		secret.Annotations = map[string]string{targetconfigcontroller.CSRSignerReplaceReasonAnnotation: reason}
+	}
+	return secret
+}
+
+func TestSync(t *testing.T) {
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	secretIndexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{})
+	configMapIndexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{})
+	kubeClient := fake.NewSimpleClientset()
+	recorder := events.NewInMemoryRecorder("test")
+	c := &CSRSignerHistoryController{
+		secretLister:    corev1listers.NewSecretLister(secretIndexer),
+		configMapLister: corev1listers.NewConfigMapLister(configMapIndexer),
+		configMapClient: kubeClient.CoreV1(),
+		now:             func() time.Time { return now },
+	}
+	sync := func(signer *corev1.Secret) *CSRSignerHistory {
+		if err := secretIndexer.Update(signer); err != nil {
+			t.Fatal(err)
+		}
+		if err := c.sync(context.TODO(), factory.NewSyncContext("test", recorder)); err != nil {
+			t.Fatal(err)
+		}
+		configMap, err := kubeClient.CoreV1().ConfigMaps(operatorclient.OperatorNamespace).Get(context.TODO(), historyConfigMapName, metav1.GetOptions{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := configMapIndexer.Update(configMap); err != nil {
+			t.Fatal(err)
+		}
+		history := &CSRSignerHistory{}
+		if err := json.Unmarshal([]byte(configMap.Data[historyKey]), history); err != nil {
+			t.Fatal(err)
+		}
+		return history
+	}
+
+	first := signerSecret(t, "signer-1", "")
+	history := sync(first)
+	if len(history.Replacements) != 1 || history.Replacements[0].Reason != unknownReason || history.Replacements[0].Old != nil {
+		t.Fatalf("expected the current signer to be recorded with an unknown reason, got %+v", history.Replacements)
+	}
+
+	// the same signer is not recorded again
+	if history := sync(first); len(history.Replacements) != 1 {
+		t.Fatalf("expected one replacement, got %+v", history.Replacements)
+	}
+
+	second := signerSecret(t, "signer-2", targetconfigcontroller.CSRSignerRotated)
+	history = sync(second)
+	if len(history.Replacements) != 2 {
+		t.Fatalf("expected two replacements, got %+v", history.Replacements)
+	}
+	replacement := history.Replacements[1]
+	if replacement.Reason != targetconfigcontroller.CSRSignerRotated || replacement.New.Subject != "CN=signer-2" {
+		t.Errorf("unexpected replacement %+v", replacement)
+	}
+	if replacement.Old == nil || *replacement.Old != history.Replacements[0].New {
+		t.Errorf("expected the first signer as the old one, got %+v", replacement.Old)
+	}
+
+	var replacedEvents int
+	for _, event := range recorder.Events() {
+		if event.Reason == "CSRSignerReplaced" {
+			replacedEvents++
+			if !strings.Contains(event.Message, "new.sha256=") {
+				t.Errorf("expected the fingerprint in the event, got %q", event.Message)
+			}
+		}
+	}
+	if replacedEvents != 2 {
+		t.Errorf("expected two CSRSignerReplaced events, got %d", replacedEvents)
+	}
+}
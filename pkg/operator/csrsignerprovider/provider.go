@@ -0,0 +1,103 @@
+package csrsignerprovider
+
+import (
+	"fmt"
+	"path"
+
+	corev1 "k8s.io/api/core/v1"
+	corev1listers "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/util/cert"
+
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/operatorclient"
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/tuning"
+)
+
+// CertificateKey is the key of the certificate configmap of a provider holding the PEM certificate of the signer
+// followed by its intermediates.
+const CertificateKey = "tls.crt"
+
+// Provider is an external source of the csr-signer of the kube-controller-manager. The operator never holds the key
+// of the signer, it only publishes the certificate in the CA bundles and points the kube-controller-manager at the
+// files the provider makes available in its container.
+type Provider interface {
+	// Name identifies the provider in conditions and events.
+	Name() string
+	// Certificate returns the PEM certificate of the signer followed by its intermediates.
+	Certificate(lister corev1listers.ConfigMapLister) ([]byte, error)
+	// Files returns the paths the kube-controller-manager reads the certificate and the key of the signer from.
+	Files() (certFile, keyFile string)
+	// Mount makes the Files available in the kube-controller-manager container of the pod.
+	Mount(pod *corev1.Pod)
+}
+
+// New returns the provider of the csrSigner section of the tuning configmap, nil when the csr-signer of the operator
+// is used.
+func New(config tuning.CSRSignerConfig) Provider {
+	switch config.Provider {
+	case tuning.HostPathCSRSignerProvider:
+		return &hostPathProvider{hostPath: config.HostPath, certificateConfigMap: config.CertificateConfigMap}
+	default:
+		return nil
+	}
+}
+
+const (
+	hostPathVolumeName = "csr-signer-provider"
+	// hostPathMountPath is outside of the resource dirs the installer and the cert-syncer write to.
+	hostPathMountPath = "/etc/kubernetes/csr-signer-provider"
+)
+
+// hostPathProvider reads the signer from a directory of the control plane hosts that an agent of the HSM or the KMS
+// keeps populated.
+type hostPathProvider struct {
+	hostPath             string
+	certificateConfigMap string
+}
+
+func (p *hostPathProvider) Name() string {
+	return fmt.Sprintf("%s %s", tuning.HostPathCSRSignerProvider, p.hostPath)
+}
+
+func (p *hostPathProvider) Certificate(lister corev1listers.ConfigMapLister) ([]byte, error) {
+	return certificateFromConfigMap(lister, p.certificateConfigMap)
+}
+
+func (p *hostPathProvider) Files() (string, string) {
+	return path.Join(hostPathMountPath, corev1.TLSCertKey), path.Join(hostPathMountPath, corev1.TLSPrivateKeyKey)
+}
+
+func (p *hostPathProvider) Mount(pod *corev1.Pod) {
+	// the pod fails to start rather than start without a signer when the agent did not create the directory
+	hostPathType := corev1.HostPathDirectory
+	pod.Spec.Volumes = append(pod.Spec.Volumes, corev1.Volume{
+		Name: hostPathVolumeName,
+		VolumeSource: corev1.VolumeSource{
+			HostPath: &corev1.HostPathVolumeSource{Path: p.hostPath, Type: &hostPathType},
+		},
+	})
+	for i := range pod.Spec.Containers {
+		container := &pod.Spec.Containers[i]
+		if container.Name != "kube-controller-manager" {
+			continue
+		}
+		container.VolumeMounts = append(container.VolumeMounts, corev1.VolumeMount{
+			Name:      hostPathVolumeName,
+			MountPath: hostPathMountPath,
+			ReadOnly:  true,
+		})
+	}
+}
+
+// certificateFromConfigMap returns the CertificateKey of the configmap of the openshift-config namespace once it
+// holds certificates.
+func certificateFromConfigMap(lister corev1listers.ConfigMapLister, name string) ([]byte, error) {
+	configMap, err := lister.ConfigMaps(operatorclient.GlobalUserSpecifiedConfigNamespace).Get(name)
+	if err != nil {
+		return nil, err
+	}
+	certificate := []byte(configMap.Data[CertificateKey])
+	if _, err := cert.ParseCertsPEM(certificate); err != nil {
+		return nil, fmt.Errorf("invalid configmap/%s[%s] -n %s: %v", name, CertificateKey, operatorclient.GlobalUserSpecifiedConfigNamespace, err)
+	}
+	return certificate, nil
+}
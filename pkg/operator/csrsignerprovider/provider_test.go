@@ -0,0 +1,71 @@
+package csrsignerprovider
+
+import (
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	corev1listers "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/tools/cache"
+
+	"github.com/openshift/library-go/pkg/crypto"
+
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/operatorclient"
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/tuning"
+)
+
+func TestHostPathProvider(t *testing.T) {
+	if New(tuning.CSRSignerConfig{}) != nil {
+		t.Fatalf("expected no provider without a csrSigner section")
+	}
+	provider := New(tuning.CSRSignerConfig{Provider: tuning.HostPathCSRSignerProvider, HostPath: "/var/run/csr-signer", CertificateConfigMap: "external-signer"})
+
+	signerConfig, err := crypto.MakeSelfSignedCAConfigForDuration("external-signer", time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	signerCert, _, err := signerConfig.GetPEMBytes()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, test := range []struct {
+		name          string
+		data          map[string]string
+		expectedError bool
+	}{
+		{name: "certificate", data: map[string]string{CertificateKey: string(signerCert)}},
+		{name: "no certificate", data: map[string]string{}, expectedError: true},
+		{name: "garbage", data: map[string]string{CertificateKey: "garbage"}, expectedError: true},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			indexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{})
+			if err := indexer.Add(&corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{Namespace: operatorclient.GlobalUserSpecifiedConfigNamespace, Name: "external-signer"},
+				Data:       test.data,
+			}); err != nil {
+				t.Fatal(err)
+			}
+			certificate, err := provider.Certificate(corev1listers.NewConfigMapLister(indexer))
+			if test.expectedError != (err != nil) {
+				t.Fatalf("expected error %v, got %v", test.expectedError, err)
+			}
+			if err == nil && string(certificate) != string(signerCert) {
+				t.Errorf("unexpected certificate %q", certificate)
+			}
+		})
+	}
+
+	pod := &corev1.Pod{Spec: corev1.PodSpec{Containers: []corev1.Container{{Name: "kube-controller-manager"}, {Name: "cluster-policy-controller"}}}}
+	provider.Mount(pod)
+	if len(pod.Spec.Volumes) != 1 || pod.Spec.Volumes[0].HostPath == nil || pod.Spec.Volumes[0].HostPath.Path != "/var/run/csr-signer" {
+		t.Fatalf("unexpected volumes %v", pod.Spec.Volumes)
+	}
+	if len(pod.Spec.Containers[0].VolumeMounts) != 1 || len(pod.Spec.Containers[1].VolumeMounts) != 0 {
+		t.Fatalf("expected the signer to be mounted into the kube-controller-manager container only")
+	}
+	certFile, keyFile := provider.Files()
+	if mountPath := pod.Spec.Containers[0].VolumeMounts[0].MountPath; certFile != mountPath+"/tls.crt" || keyFile != mountPath+"/tls.key" {
+		t.Errorf("files %s and %s are not in the mount %s", certFile, keyFile, mountPath)
+	}
+}
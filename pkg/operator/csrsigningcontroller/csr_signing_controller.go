@@ -0,0 +1,185 @@
+package csrsigningcontroller
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	certificatesv1 "k8s.io/api/certificates/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/sets"
+	certificatesv1listers "k8s.io/client-go/listers/certificates/v1"
+	corev1listers "k8s.io/client-go/listers/core/v1"
+	"k8s.io/component-base/metrics"
+	"k8s.io/component-base/metrics/legacyregistry"
+
+	operatorv1 "github.com/openshift/api/operator/v1"
+	"github.com/openshift/library-go/pkg/controller/factory"
+	"github.com/openshift/library-go/pkg/operator/events"
+	"github.com/openshift/library-go/pkg/operator/v1helpers"
+
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/operatorclient"
+)
+
+const (
+	csrSigningDegradedCondition = "CSRSigningDegraded"
+
+	// SigningThreshold is how long an approved CSR may wait for its certificate before the signing is reported as
+	// degraded. The kube-controller-manager signs approved CSRs within seconds.
+	SigningThreshold = 10 * time.Minute
+)
+
+// signerNames are the signers of the kube-controller-manager, backed by the csr-signer.
+var signerNames = sets.New[string](
+	certificatesv1.KubeAPIServerClientSignerName,
+	certificatesv1.KubeAPIServerClientKubeletSignerName,
+	certificatesv1.KubeletServingSignerName,
+)
+
+var (
+	signedCSRs = metrics.NewCounter(
+		&metrics.CounterOpts{
+			Name:           "kube_controller_manager_operator_csrs_signed_total",
+			Help:           "Number of CSRs of the kube-controller-manager signers observed to get their certificate.",
+			StabilityLevel: metrics.ALPHA,
+		},
+	)
+	awaitingSignatureCSRs = metrics.NewGauge(
+		&metrics.GaugeOpts{
+			Name:           "kube_controller_manager_operator_csrs_awaiting_signature",
+			Help:           "Number of approved CSRs of the kube-controller-manager signers that have no certificate yet.",
+			StabilityLevel: metrics.ALPHA,
+		},
+	)
+)
+
+func init() {
+	legacyregistry.MustRegister(signedCSRs, awaitingSignatureCSRs)
+}
+
+// CSRSigningController tracks how fast the kube-controller-manager signs the approved CSRs. A misconfigured
+// csr-signer otherwise only shows up as nodes that never join during a scale-up.
+type CSRSigningController struct {
+	operatorClient v1helpers.StaticPodOperatorClient
+	csrLister      certificatesv1listers.CertificateSigningRequestLister
+	secretLister   corev1listers.SecretLister
+	now            func() time.Time
+
+	// signed holds the CSRs already counted as signed, nil until the first sync
+	signed sets.Set[types.UID]
+}
+
+func NewCSRSigningController(
+	operatorClient v1helpers.StaticPodOperatorClient,
+	kubeInformersForNamespaces v1helpers.KubeInformersForNamespaces,
+	eventRecorder events.Recorder,
+) factory.Controller {
+	csrInformer := kubeInformersForNamespaces.InformersFor("").Certificates().V1().CertificateSigningRequests()
+	c := &CSRSigningController{
+		operatorClient: operatorClient,
+		csrLister:      csrInformer.Lister(),
+		secretLister:   kubeInformersForNamespaces.SecretLister(),
+		now:            time.Now,
+	}
+
+	return factory.New().WithInformers(
+		csrInformer.Informer(),
+	).WithFilteredEventsInformers(
+		factory.NamesFilter("csr-signer"),
+		kubeInformersForNamespaces.InformersFor(operatorclient.TargetNamespace).Core().V1().Secrets().Informer(),
+	).ResyncEvery(time.Minute).WithSync(c.sync).ToController("CSRSigningController", eventRecorder)
+}
+
+func (c *CSRSigningController) sync(ctx context.Context, syncCtx factory.SyncContext) error {
+	csrs, err := c.csrLister.List(labels.Everything())
+	if err != nil {
+		return err
+	}
+
+	now := c.now()
+	signed := sets.New[types.UID]()
+	var awaiting []*certificatesv1.CertificateSigningRequest
+	var stalled []string
+	for _, csr := range csrs {
+		if !signerNames.Has(csr.Spec.SignerName) {
+			continue
+		}
+		if len(csr.Status.Certificate) > 0 {
+			signed.Insert(csr.UID)
+			continue
+		}
+		if !isApproved(csr) {
+			continue
+		}
+		awaiting = append(awaiting, csr)
+		if approvedAt := approvalTime(csr); !approvedAt.IsZero() && now.Sub(approvedAt) >= SigningThreshold {
+			stalled = append(stalled, csr.Name)
+		}
+	}
+	// the CSRs signed before the operator started are not counted
+	if c.signed != nil {
+		signedCSRs.Add(float64(signed.Difference(c.signed).Len()))
+	}
+	c.signed = signed
+	awaitingSignatureCSRs.Set(float64(len(awaiting)))
+
+	condition := operatorv1.OperatorCondition{
+		Type:   csrSigningDegradedCondition,
+		Status: operatorv1.ConditionFalse,
+	}
+	if len(stalled) > 0 {
+		sort.Strings(stalled)
+		// without a csr-signer nothing can be signed, which the target config controller already reports
+		_, err := c.secretLister.Secrets(operatorclient.TargetNamespace).Get("csr-signer")
+		switch {
+		case apierrors.IsNotFound(err):
+		case err != nil:
+			return err
+		default:
+			condition.Status = operatorv1.ConditionTrue
+			condition.Reason = "ApprovedCSRsNotSigned"
+			condition.Message = fmt.Sprintf("%d approved CSRs have waited more than %s for their certificate, check the csr-signer and the kube-controller-manager logs: %s", len(stalled), SigningThreshold, truncatedList(stalled, 5))
+		}
+	}
+	_, _, err = v1helpers.UpdateStaticPodStatus(ctx, c.operatorClient, v1helpers.UpdateStaticPodConditionFn(condition))
+	return err
+}
+
+func isApproved(csr *certificatesv1.CertificateSigningRequest) bool {
+	approved := false
+	for _, condition := range csr.Status.Conditions {
+		switch condition.Type {
+		case certificatesv1.CertificateDenied, certificatesv1.CertificateFailed:
+			if condition.Status == corev1.ConditionTrue {
+				return false
+			}
+		case certificatesv1.CertificateApproved:
+			approved = approved || condition.Status == corev1.ConditionTrue
+		}
+	}
+	return approved
+}
+
+func approvalTime(csr *certificatesv1.CertificateSigningRequest) time.Time {
+	for _, condition := range csr.Status.Conditions {
+		if condition.Type == certificatesv1.CertificateApproved && condition.Status == corev1.ConditionTrue {
+			if !condition.LastTransitionTime.IsZero() {
+				return condition.LastTransitionTime.Time
+			}
+			return condition.LastUpdateTime.Time
+		}
+	}
+	return time.Time{}
+}
+
+func truncatedList(names []string, max int) string {
+	if len(names) <= max {
+		return strings.Join(names, ", ")
+	}
+	return fmt.Sprintf("%s and %d more", strings.Join(names[:max], ", "), len(names)-max)
+}
@@ -0,0 +1,163 @@
+package csrsigningcontroller
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	certificatesv1 "k8s.io/api/certificates/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	certificatesv1listers "k8s.io/client-go/listers/certificates/v1"
+	corev1listers "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/component-base/metrics/testutil"
+
+	operatorv1 "github.com/openshift/api/operator/v1"
+	"github.com/openshift/library-go/pkg/controller/factory"
+	"github.com/openshift/library-go/pkg/operator/events"
+	"github.com/openshift/library-go/pkg/operator/v1helpers"
+
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/operatorclient"
+)
+
+func TestSync(t *testing.T) {
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	csr := func(name string, signerName string, approvedAgo time.Duration, certificate bool) *certificatesv1.CertificateSigningRequest {
+		csr := &certificatesv1.CertificateSigningRequest{
+			ObjectMeta: metav1.ObjectMeta{Name: name, UID: types.UID(name)},
+			Spec:       certificatesv1.CertificateSigningRequestSpec{SignerName: signerName},
+		}
+		if approvedAgo > 0 {
+			csr.Status.Conditions = []certificatesv1.CertificateSigningRequestCondition{{
+				Type:               certificatesv1.CertificateApproved,
+				Status:             corev1.ConditionTrue,
+				LastTransitionTime: metav1.NewTime(now.Add(-approvedAgo)),
+			}}
+		}
+		if certificate {
+			csr.Status.Certificate = []byte("cert")
+		}
+		return csr
+	}
+
+	tests := []struct {
+		name             string
+		csrs             []*certificatesv1.CertificateSigningRequest
+		csrSigner        bool
+		expectedStatus   operatorv1.ConditionStatus
+		expectedAwaiting float64
+	}{
+		{
+			name:           "no csrs",
+			csrSigner:      true,
+			expectedStatus: operatorv1.ConditionFalse,
+		},
+		{
+			name: "recently approved",
+			csrs: []*certificatesv1.CertificateSigningRequest{
+				csr("recent", certificatesv1.KubeAPIServerClientKubeletSignerName, time.Minute, false),
+				csr("signed", certificatesv1.KubeletServingSignerName, time.Hour, true),
+				csr("pending-approval", certificatesv1.KubeletServingSignerName, 0, false),
+			},
+			csrSigner:        true,
+			expectedStatus:   operatorv1.ConditionFalse,
+			expectedAwaiting: 1,
+		},
+		{
+			name: "approved long ago",
+			csrs: []*certificatesv1.CertificateSigningRequest{
+				csr("stalled", certificatesv1.KubeAPIServerClientKubeletSignerName, time.Hour, false),
+				csr("other-signer", "example.com/signer", time.Hour, false),
+			},
+			csrSigner:        true,
+			expectedStatus:   operatorv1.ConditionTrue,
+			expectedAwaiting: 1,
+		},
+		{
+			name: "no csr-signer",
+			csrs: []*certificatesv1.CertificateSigningRequest{
+				csr("stalled", certificatesv1.KubeAPIServerClientKubeletSignerName, time.Hour, false),
+			},
+			expectedStatus:   operatorv1.ConditionFalse,
+			expectedAwaiting: 1,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			csrIndexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{})
+			for _, csr := range test.csrs {
+				if err := csrIndexer.Add(csr); err != nil {
+					t.Fatal(err)
+				}
+			}
+			secretIndexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc})
+			if test.csrSigner {
+				if err := secretIndexer.Add(&corev1.Secret{ObjectMeta: metav1.ObjectMeta{Namespace: operatorclient.TargetNamespace, Name: "csr-signer"}}); err != nil {
+					t.Fatal(err)
+				}
+			}
+			operatorClient := v1helpers.NewFakeStaticPodOperatorClient(&operatorv1.StaticPodOperatorSpec{}, &operatorv1.StaticPodOperatorStatus{}, nil, nil)
+			c := &CSRSigningController{
+				operatorClient: operatorClient,
+				csrLister:      certificatesv1listers.NewCertificateSigningRequestLister(csrIndexer),
+				secretLister:   corev1listers.NewSecretLister(secretIndexer),
+				now:            func() time.Time { return now },
+			}
+
+			if err := c.sync(context.TODO(), factory.NewSyncContext("test", events.NewInMemoryRecorder("test"))); err != nil {
+				t.Fatal(err)
+			}
+			_, status, _, _ := operatorClient.GetStaticPodOperatorState()
+			condition := v1helpers.FindOperatorCondition(status.Conditions, csrSigningDegradedCondition)
+			if condition == nil || condition.Status != test.expectedStatus {
+				t.Errorf("expected %s to be %s, got %#v", csrSigningDegradedCondition, test.expectedStatus, condition)
+			}
+			if awaiting, err := testutil.GetGaugeMetricValue(awaitingSignatureCSRs); err != nil || awaiting != test.expectedAwaiting {
+				t.Errorf("expected %v CSRs awaiting signature, got %v (%v)", test.expectedAwaiting, awaiting, err)
+			}
+		})
+	}
+}
+
+func TestSignedCSRsCounter(t *testing.T) {
+	csrIndexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{})
+	signed := func(name string) *certificatesv1.CertificateSigningRequest {
+		return &certificatesv1.CertificateSigningRequest{
+			ObjectMeta: metav1.ObjectMeta{Name: name, UID: types.UID(name)},
+			Spec:       certificatesv1.CertificateSigningRequestSpec{SignerName: certificatesv1.KubeletServingSignerName},
+			Status:     certificatesv1.CertificateSigningRequestStatus{Certificate: []byte("cert")},
+		}
+	}
+	if err := csrIndexer.Add(signed("before-start")); err != nil {
+		t.Fatal(err)
+	}
+	c := &CSRSigningController{
+		operatorClient: v1helpers.NewFakeStaticPodOperatorClient(&operatorv1.StaticPodOperatorSpec{}, &operatorv1.StaticPodOperatorStatus{}, nil, nil),
+		csrLister:      certificatesv1listers.NewCertificateSigningRequestLister(csrIndexer),
+		secretLister:   corev1listers.NewSecretLister(cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc})),
+		now:            time.Now,
+	}
+	syncCtx := factory.NewSyncContext("test", events.NewInMemoryRecorder("test"))
+
+	initial, err := testutil.GetCounterMetricValue(signedCSRs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := c.sync(context.TODO(), syncCtx); err != nil {
+		t.Fatal(err)
+	}
+	if err := csrIndexer.Add(signed("after-start")); err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 2; i++ {
+		if err := c.sync(context.TODO(), syncCtx); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if count, err := testutil.GetCounterMetricValue(signedCSRs); err != nil || count-initial != 1 {
+		t.Errorf("expected one CSR counted as signed, got %v (%v)", count-initial, err)
+	}
+}
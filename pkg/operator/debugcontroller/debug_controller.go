@@ -0,0 +1,253 @@
+package debugcontroller
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/pprof"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	dto "github.com/prometheus/client_model/go"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	corev1client "k8s.io/client-go/kubernetes/typed/core/v1"
+	"k8s.io/component-base/metrics/legacyregistry"
+	"k8s.io/klog/v2"
+	"k8s.io/utils/ptr"
+
+	operatorv1 "github.com/openshift/api/operator/v1"
+	"github.com/openshift/library-go/pkg/controller/factory"
+	"github.com/openshift/library-go/pkg/operator/events"
+	"github.com/openshift/library-go/pkg/operator/loglevel"
+	"github.com/openshift/library-go/pkg/operator/resource/resourceapply"
+	"github.com/openshift/library-go/pkg/operator/v1helpers"
+
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/operatorclient"
+)
+
+const (
+	// debugConfigMapName holds the last controller state snapshot while debugging is enabled.
+	debugConfigMapName = "kube-controller-manager-operator-debug"
+	// debugListenAddress is only reachable from within the operator pod.
+	debugListenAddress = "localhost:6060"
+	// debugStateTimestampAnnotation is the time the state in the debugConfigMapName configmap was collected. It is only
+	// written with a change of the state, a resync of an unchanged state writes nothing.
+	debugStateTimestampAnnotation = "kubecontrollermanager.operator.openshift.io/state-timestamp"
+)
+
+// DebugController serves pprof and a controller state dump on debugListenAddress and mirrors the state dump into
+// the debugConfigMapName configmap, but only while the operator log level is Debug or higher.
+type DebugController struct {
+	operatorClient  v1helpers.StaticPodOperatorClient
+	configMapClient corev1client.ConfigMapsGetter
+	gatherer        interface {
+		Gather() ([]*dto.MetricFamily, error)
+	}
+	informerResyncPeriods map[string]time.Duration
+
+	serverLock sync.Mutex
+	server     *http.Server
+}
+
+// ControllerState is a point in time view of the operator controllers.
+type ControllerState struct {
+	// Timestamp is the time the state was collected. It is left out of the configmap, see
+	// debugStateTimestampAnnotation.
+	Timestamp *metav1.Time `json:"timestamp,omitempty"`
+	// OperatorLogLevel is the operator log level that enabled debugging.
+	OperatorLogLevel operatorv1.LogLevel `json:"operatorLogLevel"`
+	// SyncErrors maps the degraded conditions reported by the controllers to their messages.
+	SyncErrors map[string]string `json:"syncErrors,omitempty"`
+	// QueueDepths maps the controller work queue names to the number of items waiting in them.
+	QueueDepths map[string]int64 `json:"queueDepths,omitempty"`
+	// InformerResyncPeriods are the effective resync periods of the informers, after the jitter.
+	InformerResyncPeriods []InformerResyncPeriod `json:"informerResyncPeriods,omitempty"`
+}
+
+// InformerResyncPeriod is the resync period of the informers of a namespace.
+type InformerResyncPeriod struct {
+	// Namespace is empty for the cluster-scoped informers.
+	Namespace string          `json:"namespace"`
+	Period    metav1.Duration `json:"period"`
+}
+
+func NewDebugController(
+	operatorClient v1helpers.StaticPodOperatorClient,
+	kubeInformersForNamespaces v1helpers.KubeInformersForNamespaces,
+	kubeClient kubernetes.Interface,
+	informerResyncPeriods map[string]time.Duration,
+	eventRecorder events.Recorder,
+) factory.Controller {
+	c := &DebugController{
+		operatorClient:        operatorClient,
+		configMapClient:       v1helpers.CachedConfigMapGetter(kubeClient.CoreV1(), kubeInformersForNamespaces),
+		gatherer:              legacyregistry.DefaultGatherer,
+		informerResyncPeriods: informerResyncPeriods,
+	}
+
+	return factory.New().WithInformers(
+		operatorClient.Informer(),
+		kubeInformersForNamespaces.InformersFor(operatorclient.OperatorNamespace).Core().V1().ConfigMaps().Informer(),
+	).ResyncEvery(time.Minute).WithSync(c.sync).ToController("DebugController", eventRecorder)
+}
+
+func (c *DebugController) sync(ctx context.Context, syncCtx factory.SyncContext) error {
+	operatorSpec, _, _, err := c.operatorClient.GetStaticPodOperatorState()
+	if err != nil {
+		return err
+	}
+
+	if !isDebugEnabled(operatorSpec.OperatorLogLevel) {
+		c.stopServer()
+		if _, err := c.configMapClient.ConfigMaps(operatorclient.OperatorNamespace).Get(ctx, debugConfigMapName, metav1.GetOptions{}); apierrors.IsNotFound(err) {
+			return nil
+		}
+		err := c.configMapClient.ConfigMaps(operatorclient.OperatorNamespace).Delete(ctx, debugConfigMapName, metav1.DeleteOptions{})
+		if apierrors.IsNotFound(err) {
+			return nil
+		}
+		return err
+	}
+
+	c.startServer(ctx)
+
+	state, err := c.collectState()
+	if err != nil {
+		return err
+	}
+	timestamp := state.Timestamp
+	state.Timestamp = nil
+	stateBytes, err := json.MarshalIndent(state, "", "  ")
+	if err != nil {
+		return err
+	}
+	existing, err := c.configMapClient.ConfigMaps(operatorclient.OperatorNamespace).Get(ctx, debugConfigMapName, metav1.GetOptions{})
+	if err != nil && !apierrors.IsNotFound(err) {
+		return err
+	}
+	if err == nil && existing.Data["state.json"] == string(stateBytes) {
+		return nil
+	}
+	_, _, err = resourceapply.ApplyConfigMap(ctx, c.configMapClient, syncCtx.Recorder(), &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace:   operatorclient.OperatorNamespace,
+			Name:        debugConfigMapName,
+			Annotations: map[string]string{debugStateTimestampAnnotation: timestamp.UTC().Format(time.RFC3339)},
+		},
+		Data: map[string]string{"state.json": string(stateBytes)},
+	})
+	return err
+}
+
+// isDebugEnabled returns true when the operator log level is at least Debug.
+func isDebugEnabled(logLevel operatorv1.LogLevel) bool {
+	return loglevel.LogLevelToVerbosity(logLevel) >= loglevel.LogLevelToVerbosity(operatorv1.Debug)
+}
+
+func (c *DebugController) collectState() (*ControllerState, error) {
+	operatorSpec, operatorStatus, _, err := c.operatorClient.GetStaticPodOperatorState()
+	if err != nil {
+		return nil, err
+	}
+
+	state := &ControllerState{
+		Timestamp:        ptr.To(metav1.Now()),
+		OperatorLogLevel: operatorSpec.OperatorLogLevel,
+		SyncErrors:       map[string]string{},
+		QueueDepths:      map[string]int64{},
+	}
+	for _, condition := range operatorStatus.Conditions {
+		if strings.HasSuffix(condition.Type, operatorv1.OperatorStatusTypeDegraded) && condition.Status == operatorv1.ConditionTrue {
+			state.SyncErrors[condition.Type] = condition.Message
+		}
+	}
+
+	for namespace, period := range c.informerResyncPeriods {
+		state.InformerResyncPeriods = append(state.InformerResyncPeriods, InformerResyncPeriod{Namespace: namespace, Period: metav1.Duration{Duration: period}})
+	}
+	sort.Slice(state.InformerResyncPeriods, func(i, j int) bool {
+		return state.InformerResyncPeriods[i].Namespace < state.InformerResyncPeriods[j].Namespace
+	})
+
+	metricFamilies, err := c.gatherer.Gather()
+	if err != nil {
+		return nil, err
+	}
+	for _, metricFamily := range metricFamilies {
+		if metricFamily.GetName() != "workqueue_depth" {
+			continue
+		}
+		for _, metric := range metricFamily.GetMetric() {
+			for _, label := range metric.GetLabel() {
+				if label.GetName() == "name" {
+					state.QueueDepths[label.GetValue()] = int64(metric.GetGauge().GetValue())
+				}
+			}
+		}
+	}
+
+	return state, nil
+}
+
+func (c *DebugController) startServer(ctx context.Context) {
+	c.serverLock.Lock()
+	defer c.serverLock.Unlock()
+	if c.server != nil {
+		return
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/debug/pprof/", pprof.Index)
+	mux.HandleFunc("/debug/pprof/cmdline", pprof.Cmdline)
+	mux.HandleFunc("/debug/pprof/profile", pprof.Profile)
+	mux.HandleFunc("/debug/pprof/symbol", pprof.Symbol)
+	mux.HandleFunc("/debug/pprof/trace", pprof.Trace)
+	mux.HandleFunc("/debug/controllers", c.serveState)
+
+	server := &http.Server{Addr: debugListenAddress, Handler: mux}
+	c.server = server
+	go func() {
+		klog.Infof("Serving debug endpoints on %s", debugListenAddress)
+		if err := server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			klog.Errorf("Failed to serve debug endpoints: %v", err)
+		}
+	}()
+	go func() {
+		<-ctx.Done()
+		c.stopServer()
+	}()
+}
+
+func (c *DebugController) stopServer() {
+	c.serverLock.Lock()
+	defer c.serverLock.Unlock()
+	if c.server == nil {
+		return
+	}
+	klog.Infof("Stopping debug endpoints on %s", debugListenAddress)
+	if err := c.server.Close(); err != nil {
+		klog.Warningf("Failed to stop debug endpoints: %v", err)
+	}
+	c.server = nil
+}
+
+func (c *DebugController) serveState(w http.ResponseWriter, _ *http.Request) {
+	state, err := c.collectState()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(state); err != nil {
+		klog.Warningf("Failed to write controller state: %v", err)
+	}
+}
@@ -0,0 +1,153 @@
+package debugcontroller
+
+import (
+	"context"
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	dto "github.com/prometheus/client_model/go"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+	"k8s.io/utils/ptr"
+
+	operatorv1 "github.com/openshift/api/operator/v1"
+	"github.com/openshift/library-go/pkg/controller/factory"
+	"github.com/openshift/library-go/pkg/operator/events"
+	"github.com/openshift/library-go/pkg/operator/v1helpers"
+
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/operatorclient"
+)
+
+type fakeGatherer []*dto.MetricFamily
+
+func (f fakeGatherer) Gather() ([]*dto.MetricFamily, error) {
+	return f, nil
+}
+
+func TestIsDebugEnabled(t *testing.T) {
+	for logLevel, expected := range map[operatorv1.LogLevel]bool{
+		"":                  false,
+		operatorv1.Normal:   false,
+		operatorv1.Debug:    true,
+		operatorv1.Trace:    true,
+		operatorv1.TraceAll: true,
+	} {
+		if actual := isDebugEnabled(logLevel); actual != expected {
+			t.Errorf("unexpected result for %q: expected %v, got %v", logLevel, expected, actual)
+		}
+	}
+}
+
+func TestCollectState(t *testing.T) {
+	operatorClient := v1helpers.NewFakeStaticPodOperatorClient(
+		&operatorv1.StaticPodOperatorSpec{OperatorSpec: operatorv1.OperatorSpec{OperatorLogLevel: operatorv1.Debug}},
+		&operatorv1.StaticPodOperatorStatus{OperatorStatus: operatorv1.OperatorStatus{Conditions: []operatorv1.OperatorCondition{
+			{Type: "TargetConfigControllerDegraded", Status: operatorv1.ConditionTrue, Message: "broken"},
+			{Type: "SATokenSignerDegraded", Status: operatorv1.ConditionFalse},
+			{Type: "StaticPodsAvailable", Status: operatorv1.ConditionTrue, Message: "not an error"},
+		}}},
+		nil,
+		nil,
+	)
+	c := &DebugController{
+		operatorClient: operatorClient,
+		gatherer: fakeGatherer{
+			{
+				Name: ptr.To("workqueue_depth"),
+				Metric: []*dto.Metric{
+					{Label: []*dto.LabelPair{{Name: ptr.To("name"), Value: ptr.To("TargetConfigController")}}, Gauge: &dto.Gauge{Value: ptr.To(3.0)}},
+				},
+			},
+			{
+				Name: ptr.To("workqueue_adds_total"),
+				Metric: []*dto.Metric{
+					{Label: []*dto.LabelPair{{Name: ptr.To("name"), Value: ptr.To("TargetConfigController")}}, Counter: &dto.Counter{Value: ptr.To(42.0)}},
+				},
+			},
+		},
+		informerResyncPeriods: map[string]time.Duration{"openshift-config": 11 * time.Minute, "": 10 * time.Minute},
+	}
+
+	state, err := c.collectState()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expected := map[string]string{"TargetConfigControllerDegraded": "broken"}; !reflect.DeepEqual(expected, state.SyncErrors) {
+		t.Errorf("unexpected sync errors: %v", state.SyncErrors)
+	}
+	if expected := map[string]int64{"TargetConfigController": 3}; !reflect.DeepEqual(expected, state.QueueDepths) {
+		t.Errorf("unexpected queue depths: %v", state.QueueDepths)
+	}
+	expectedPeriods := []InformerResyncPeriod{
+		{Period: metav1.Duration{Duration: 10 * time.Minute}},
+		{Namespace: "openshift-config", Period: metav1.Duration{Duration: 11 * time.Minute}},
+	}
+	if !reflect.DeepEqual(expectedPeriods, state.InformerResyncPeriods) {
+		t.Errorf("unexpected informer resync periods: %v", state.InformerResyncPeriods)
+	}
+	if state.OperatorLogLevel != operatorv1.Debug {
+		t.Errorf("unexpected log level: %v", state.OperatorLogLevel)
+	}
+}
+
+func TestSyncWritesChangedState(t *testing.T) {
+	status := &operatorv1.StaticPodOperatorStatus{}
+	operatorClient := v1helpers.NewFakeStaticPodOperatorClient(
+		&operatorv1.StaticPodOperatorSpec{OperatorSpec: operatorv1.OperatorSpec{OperatorLogLevel: operatorv1.Debug}},
+		status,
+		nil,
+		nil,
+	)
+	kubeClient := fake.NewSimpleClientset()
+	c := &DebugController{
+		operatorClient:  operatorClient,
+		configMapClient: kubeClient.CoreV1(),
+		gatherer:        fakeGatherer{},
+		// the debug endpoints are not served by the test
+		server: &http.Server{},
+	}
+	syncCtx := factory.NewSyncContext("test", events.NewInMemoryRecorder("debug"))
+	writes := func() int {
+		count := 0
+		for _, action := range kubeClient.Actions() {
+			if action.GetVerb() == "create" || action.GetVerb() == "update" {
+				count++
+			}
+		}
+		return count
+	}
+
+	for i := 0; i < 2; i++ {
+		if err := c.sync(context.TODO(), syncCtx); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if writes() != 1 {
+		t.Errorf("expected the unchanged state to be written once, got %d writes", writes())
+	}
+	configMap, err := kubeClient.CoreV1().ConfigMaps(operatorclient.OperatorNamespace).Get(context.TODO(), debugConfigMapName, metav1.GetOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(configMap.Annotations[debugStateTimestampAnnotation]) == 0 || strings.Contains(configMap.Data["state.json"], "timestamp") {
+		t.Errorf("expected the timestamp in the annotation only, got %v and %s", configMap.Annotations, configMap.Data["state.json"])
+	}
+
+	_, _, resourceVersion, err := operatorClient.GetStaticPodOperatorState()
+	if err != nil {
+		t.Fatal(err)
+	}
+	status.Conditions = []operatorv1.OperatorCondition{{Type: "TargetConfigControllerDegraded", Status: operatorv1.ConditionTrue, Message: "broken"}}
+	if _, err := operatorClient.UpdateStaticPodOperatorStatus(context.TODO(), resourceVersion, status); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.sync(context.TODO(), syncCtx); err != nil {
+		t.Fatal(err)
+	}
+	if writes() != 2 {
+		t.Errorf("expected the changed state to be written, got %d writes", writes())
+	}
+}
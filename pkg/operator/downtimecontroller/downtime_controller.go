@@ -0,0 +1,209 @@
+package downtimecontroller
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	corev1client "k8s.io/client-go/kubernetes/typed/core/v1"
+	corev1listers "k8s.io/client-go/listers/core/v1"
+	"k8s.io/component-base/metrics"
+	"k8s.io/component-base/metrics/legacyregistry"
+	"k8s.io/klog/v2"
+
+	operatorv1 "github.com/openshift/api/operator/v1"
+	"github.com/openshift/library-go/pkg/controller/factory"
+	"github.com/openshift/library-go/pkg/operator/events"
+	"github.com/openshift/library-go/pkg/operator/resource/resourceapply"
+	"github.com/openshift/library-go/pkg/operator/v1helpers"
+
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/operatorclient"
+)
+
+const (
+	// windowsConfigMapName holds the planned downtime windows of the kube-controller-manager on the nodes.
+	windowsConfigMapName = "kube-controller-manager-downtime-windows"
+	windowsKey           = "windows.json"
+
+	rolloutInProgressCondition = "RolloutInProgress"
+
+	// ExpectedNodeDowntime is how long a window is planned for when the install of a revision on a node starts.
+	ExpectedNodeDowntime = 10 * time.Minute
+	// Grace keeps a window open after the node runs its new revision, until the scrapes of the restarted
+	// kube-controller-manager succeed again and the alerts on them resolve. A slow install extends its window by it.
+	Grace = 5 * time.Minute
+)
+
+var plannedDowntime = metrics.NewGaugeVec(
+	&metrics.GaugeOpts{
+		Name:           "kube_controller_manager_operator_planned_downtime",
+		Help:           "1 while a revision rollout restarts the kube-controller-manager on the node.",
+		StabilityLevel: metrics.ALPHA,
+	},
+	[]string{"node"},
+)
+
+func init() {
+	legacyregistry.MustRegister(plannedDowntime)
+}
+
+// Window is the time the kube-controller-manager on a node is expected to be down for the rollout of a revision.
+type Window struct {
+	NodeName string      `json:"nodeName"`
+	Revision int32       `json:"revision"`
+	Start    metav1.Time `json:"start"`
+	End      metav1.Time `json:"end"`
+}
+
+// DowntimeController publishes the planned downtime windows of the kube-controller-manager during revision rollouts in
+// the windowsConfigMapName configmap and the kube_controller_manager_operator_planned_downtime metric, which the
+// KubeControllerManagerPlannedDowntime alert fires on, for Alertmanager to inhibit the TargetDown alerts of the restarts
+// a rollout causes. The rollout itself is reported in the RolloutInProgress condition.
+type DowntimeController struct {
+	operatorClient  v1helpers.StaticPodOperatorClient
+	configMapClient corev1client.ConfigMapsGetter
+	configMapLister corev1listers.ConfigMapLister
+	now             func() time.Time
+}
+
+func NewDowntimeController(
+	operatorClient v1helpers.StaticPodOperatorClient,
+	kubeInformersForNamespaces v1helpers.KubeInformersForNamespaces,
+	kubeClient kubernetes.Interface,
+	eventRecorder events.Recorder,
+) factory.Controller {
+	c := &DowntimeController{
+		operatorClient:  operatorClient,
+		configMapClient: v1helpers.CachedConfigMapGetter(kubeClient.CoreV1(), kubeInformersForNamespaces),
+		configMapLister: kubeInformersForNamespaces.ConfigMapLister(),
+		now:             time.Now,
+	}
+
+	return factory.New().WithFilteredEventsInformers(
+		factory.NamesFilter(windowsConfigMapName),
+		kubeInformersForNamespaces.InformersFor(operatorclient.OperatorNamespace).Core().V1().ConfigMaps().Informer(),
+	).WithInformers(
+		operatorClient.Informer(),
+	).ResyncEvery(time.Minute).WithSync(c.sync).ToController("DowntimeController", eventRecorder)
+}
+
+func (c *DowntimeController) sync(ctx context.Context, syncCtx factory.SyncContext) error {
+	_, operatorStatus, _, err := c.operatorClient.GetStaticPodOperatorState()
+	if err != nil {
+		return err
+	}
+
+	var previous []Window
+	configMap, err := c.configMapLister.ConfigMaps(operatorclient.OperatorNamespace).Get(windowsConfigMapName)
+	switch {
+	case apierrors.IsNotFound(err):
+	case err != nil:
+		return err
+	default:
+		if err := json.Unmarshal([]byte(configMap.Data[windowsKey]), &previous); err != nil {
+			// the windows in progress start over
+			klog.Warningf("Ignoring the invalid %s/%s: %v", operatorclient.OperatorNamespace, windowsConfigMapName, err)
+			previous = nil
+		}
+	}
+
+	now := c.now()
+	windows := updateWindows(previous, operatorStatus, now)
+	windowsBytes, err := json.MarshalIndent(windows, "", "  ")
+	if err != nil {
+		return err
+	}
+	if _, _, err := resourceapply.ApplyConfigMap(ctx, c.configMapClient, syncCtx.Recorder(), &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Namespace: operatorclient.OperatorNamespace, Name: windowsConfigMapName},
+		Data:       map[string]string{windowsKey: string(windowsBytes)},
+	}); err != nil {
+		return err
+	}
+
+	plannedDowntime.Reset()
+	for _, window := range windows {
+		plannedDowntime.WithLabelValues(window.NodeName).Set(1)
+	}
+
+	_, _, err = v1helpers.UpdateStaticPodStatus(ctx, c.operatorClient, v1helpers.UpdateStaticPodConditionFn(rolloutInProgressConditionFor(operatorStatus, windows)))
+	return err
+}
+
+// updateWindows opens a window for every node starting the install of a revision and closes it the Grace after the
+// node runs the revision. Closed windows are dropped.
+func updateWindows(previous []Window, operatorStatus *operatorv1.StaticPodOperatorStatus, now time.Time) []Window {
+	previousWindows := map[string]Window{}
+	for _, window := range previous {
+		previousWindows[window.NodeName] = window
+	}
+
+	windows := []Window{}
+	for _, nodeStatus := range operatorStatus.NodeStatuses {
+		window, open := previousWindows[nodeStatus.NodeName]
+		installing := nodeStatus.TargetRevision != 0 && nodeStatus.TargetRevision != nodeStatus.CurrentRevision
+		switch {
+		case installing && (!open || window.Revision != nodeStatus.TargetRevision):
+			window = Window{
+				NodeName: nodeStatus.NodeName,
+				Revision: nodeStatus.TargetRevision,
+				Start:    metav1.NewTime(now),
+				End:      metav1.NewTime(now.Add(ExpectedNodeDowntime)),
+			}
+		case installing:
+			if window.End.Time.Before(now.Add(Grace)) {
+				window.End = metav1.NewTime(now.Add(Grace))
+			}
+		case !open:
+			continue
+		default:
+			if window.End.Time.After(now.Add(Grace)) {
+				window.End = metav1.NewTime(now.Add(Grace))
+			}
+			if !now.Before(window.End.Time) {
+				continue
+			}
+		}
+		windows = append(windows, window)
+	}
+	sort.Slice(windows, func(i, j int) bool { return windows[i].NodeName < windows[j].NodeName })
+	return windows
+}
+
+// rolloutInProgressConditionFor reports a rollout while a node is not on the latest revision yet, with the open
+// windows.
+func rolloutInProgressConditionFor(operatorStatus *operatorv1.StaticPodOperatorStatus, windows []Window) operatorv1.OperatorCondition {
+	condition := operatorv1.OperatorCondition{
+		Type:   rolloutInProgressCondition,
+		Status: operatorv1.ConditionFalse,
+		Reason: "AllNodesAtLatestRevision",
+	}
+
+	var pending []string
+	for _, nodeStatus := range operatorStatus.NodeStatuses {
+		if nodeStatus.CurrentRevision != operatorStatus.LatestAvailableRevision {
+			pending = append(pending, nodeStatus.NodeName)
+		}
+	}
+	if len(pending) == 0 {
+		return condition
+	}
+
+	var planned []string
+	for _, window := range windows {
+		planned = append(planned, fmt.Sprintf("%s until %s", window.NodeName, window.End.UTC().Format(time.RFC3339)))
+	}
+	condition.Status = operatorv1.ConditionTrue
+	condition.Reason = "RollingOut"
+	condition.Message = fmt.Sprintf("Rolling out revision %d to %s", operatorStatus.LatestAvailableRevision, strings.Join(pending, ", "))
+	if len(planned) > 0 {
+		condition.Message += fmt.Sprintf(", planned downtime on %s", strings.Join(planned, ", "))
+	}
+	return condition
+}
@@ -0,0 +1,127 @@
+package downtimecontroller
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+	corev1listers "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/tools/cache"
+
+	operatorv1 "github.com/openshift/api/operator/v1"
+	"github.com/openshift/library-go/pkg/controller/factory"
+	"github.com/openshift/library-go/pkg/operator/events"
+	"github.com/openshift/library-go/pkg/operator/v1helpers"
+
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/operatorclient"
+)
+
+func TestUpdateWindows(t *testing.T) {
+	start := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	status := func(nodeStatuses ...operatorv1.NodeStatus) *operatorv1.StaticPodOperatorStatus {
+		return &operatorv1.StaticPodOperatorStatus{LatestAvailableRevision: 4, NodeStatuses: nodeStatuses}
+	}
+
+	// the install on master-0 starts
+	windows := updateWindows(nil, status(
+		operatorv1.NodeStatus{NodeName: "master-0", CurrentRevision: 3, TargetRevision: 4},
+		operatorv1.NodeStatus{NodeName: "master-1", CurrentRevision: 3},
+	), start)
+	if len(windows) != 1 || windows[0].NodeName != "master-0" || windows[0].Revision != 4 || !windows[0].End.Time.Equal(start.Add(ExpectedNodeDowntime)) {
+		t.Fatalf("expected a window of master-0 for revision 4, got %v", windows)
+	}
+
+	// a slow install extends the window
+	now := start.Add(ExpectedNodeDowntime)
+	windows = updateWindows(windows, status(
+		operatorv1.NodeStatus{NodeName: "master-0", CurrentRevision: 3, TargetRevision: 4},
+		operatorv1.NodeStatus{NodeName: "master-1", CurrentRevision: 3},
+	), now)
+	if len(windows) != 1 || !windows[0].Start.Time.Equal(start) || !windows[0].End.Time.Equal(now.Add(Grace)) {
+		t.Fatalf("expected the window of master-0 to be extended, got %v", windows)
+	}
+
+	// master-0 runs the revision and master-1 starts
+	now = now.Add(time.Minute)
+	windows = updateWindows(windows, status(
+		operatorv1.NodeStatus{NodeName: "master-0", CurrentRevision: 4},
+		operatorv1.NodeStatus{NodeName: "master-1", CurrentRevision: 3, TargetRevision: 4},
+	), now)
+	if len(windows) != 2 || windows[0].NodeName != "master-0" || windows[1].NodeName != "master-1" {
+		t.Fatalf("expected windows of master-0 and master-1, got %v", windows)
+	}
+	master0End := windows[0].End.Time
+
+	// the window of master-0 closes the grace after it got the revision
+	now = master0End
+	windows = updateWindows(windows, status(
+		operatorv1.NodeStatus{NodeName: "master-0", CurrentRevision: 4},
+		operatorv1.NodeStatus{NodeName: "master-1", CurrentRevision: 3, TargetRevision: 4},
+	), now)
+	if len(windows) != 1 || windows[0].NodeName != "master-1" {
+		t.Fatalf("expected the window of master-1 only, got %v", windows)
+	}
+
+	// the window of master-1 closes early when it got the revision quickly
+	now = now.Add(time.Minute)
+	windows = updateWindows(windows, status(
+		operatorv1.NodeStatus{NodeName: "master-0", CurrentRevision: 4},
+		operatorv1.NodeStatus{NodeName: "master-1", CurrentRevision: 4},
+	), now)
+	if len(windows) != 1 || !windows[0].End.Time.Equal(now.Add(Grace)) {
+		t.Fatalf("expected the window of master-1 to close after the grace, got %v", windows)
+	}
+}
+
+func TestSync(t *testing.T) {
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	operatorClient := v1helpers.NewFakeStaticPodOperatorClient(
+		&operatorv1.StaticPodOperatorSpec{OperatorSpec: operatorv1.OperatorSpec{ManagementState: operatorv1.Managed}},
+		&operatorv1.StaticPodOperatorStatus{
+			LatestAvailableRevision: 4,
+			NodeStatuses: []operatorv1.NodeStatus{
+				{NodeName: "master-0", CurrentRevision: 3, TargetRevision: 4},
+				{NodeName: "master-1", CurrentRevision: 3},
+			},
+		},
+		nil,
+		nil,
+	)
+	kubeClient := fake.NewSimpleClientset()
+	c := &DowntimeController{
+		operatorClient:  operatorClient,
+		configMapClient: kubeClient.CoreV1(),
+		configMapLister: corev1listers.NewConfigMapLister(cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{})),
+		now:             func() time.Time { return now },
+	}
+	if err := c.sync(context.TODO(), factory.NewSyncContext("test", events.NewInMemoryRecorder("test"))); err != nil {
+		t.Fatal(err)
+	}
+
+	configMap, err := kubeClient.CoreV1().ConfigMaps(operatorclient.OperatorNamespace).Get(context.TODO(), windowsConfigMapName, metav1.GetOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var windows []Window
+	if err := json.Unmarshal([]byte(configMap.Data[windowsKey]), &windows); err != nil {
+		t.Fatal(err)
+	}
+	if len(windows) != 1 || windows[0].NodeName != "master-0" {
+		t.Errorf("expected a window of master-0, got %v", windows)
+	}
+
+	_, status, _, err := operatorClient.GetStaticPodOperatorState()
+	if err != nil {
+		t.Fatal(err)
+	}
+	condition := v1helpers.FindOperatorCondition(status.Conditions, rolloutInProgressCondition)
+	if condition == nil || condition.Status != operatorv1.ConditionTrue {
+		t.Fatalf("expected %s to be true, got %v", rolloutInProgressCondition, condition)
+	}
+	if expected := "Rolling out revision 4 to master-0, master-1, planned downtime on master-0 until 2024-01-01T12:10:00Z"; condition.Message != expected {
+		t.Errorf("expected message %q, got %q", expected, condition.Message)
+	}
+}
@@ -0,0 +1,214 @@
+package fipscontroller
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"crypto/x509"
+	"fmt"
+	"time"
+
+	"github.com/ghodss/yaml"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	corev1listers "k8s.io/client-go/listers/core/v1"
+	certutil "k8s.io/client-go/util/cert"
+	"k8s.io/client-go/util/keyutil"
+
+	operatorv1 "github.com/openshift/api/operator/v1"
+	"github.com/openshift/library-go/pkg/controller/factory"
+	"github.com/openshift/library-go/pkg/operator/events"
+	"github.com/openshift/library-go/pkg/operator/v1helpers"
+
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/operatorclient"
+)
+
+const (
+	// fipsKeysDegradedCondition is reported while a signer of a FIPS cluster uses a key or signature that is not
+	// approved.
+	fipsKeysDegradedCondition = "FIPSKeysDegraded"
+
+	// MinRSAKeyBits is the smallest RSA modulus approved for signatures.
+	MinRSAKeyBits = 2048
+
+	// installConfigNamespace and installConfigName hold the install-config the cluster was installed with, which
+	// records whether it runs in FIPS mode.
+	installConfigNamespace = "kube-system"
+	installConfigName      = "cluster-config-v1"
+)
+
+// approvedCurves are the ECDSA curves approved for signatures.
+var approvedCurves = map[elliptic.Curve]bool{
+	elliptic.P256(): true,
+	elliptic.P384(): true,
+	elliptic.P521(): true,
+}
+
+// unapprovedSignatureAlgorithms are the certificate signature algorithms a FIPS cluster must not use.
+var unapprovedSignatureAlgorithms = map[x509.SignatureAlgorithm]bool{
+	x509.MD2WithRSA:    true,
+	x509.MD5WithRSA:    true,
+	x509.SHA1WithRSA:   true,
+	x509.DSAWithSHA1:   true,
+	x509.DSAWithSHA256: true,
+	x509.ECDSAWithSHA1: true,
+	x509.PureEd25519:   true,
+}
+
+// signerKey is a secret key holding a signer the operator generates or a user provides.
+type signerKey struct {
+	namespace, name, key string
+	certificate          bool
+}
+
+// signerKeys are checked on FIPS clusters. The keys the operator generates are approved already, checking them
+// catches a signer copied in from elsewhere.
+var signerKeys = []signerKey{
+	{namespace: operatorclient.OperatorNamespace, name: "csr-signer-signer", key: "tls.crt", certificate: true},
+	{namespace: operatorclient.OperatorNamespace, name: "csr-signer", key: "tls.crt", certificate: true},
+	{namespace: operatorclient.TargetNamespace, name: "csr-signer", key: "tls.crt", certificate: true},
+	{namespace: operatorclient.GlobalUserSpecifiedConfigNamespace, name: operatorclient.UserSpecifiedConfigSecretName, key: "service-account.key"},
+	{namespace: operatorclient.OperatorNamespace, name: "next-service-account-private-key", key: "service-account.key"},
+	{namespace: operatorclient.TargetNamespace, name: "service-account-private-key", key: "service-account.key"},
+}
+
+// FIPSController reports the signers of a FIPS cluster whose keys or signatures are not approved in the
+// FIPSKeysDegraded condition.
+type FIPSController struct {
+	operatorClient  v1helpers.StaticPodOperatorClient
+	configMapLister corev1listers.ConfigMapLister
+	secretLister    corev1listers.SecretLister
+}
+
+func NewFIPSController(
+	operatorClient v1helpers.StaticPodOperatorClient,
+	kubeInformersForNamespaces v1helpers.KubeInformersForNamespaces,
+	eventRecorder events.Recorder,
+) factory.Controller {
+	c := &FIPSController{
+		operatorClient:  operatorClient,
+		configMapLister: kubeInformersForNamespaces.ConfigMapLister(),
+		secretLister:    kubeInformersForNamespaces.SecretLister(),
+	}
+
+	return factory.New().WithInformers(
+		operatorClient.Informer(),
+		kubeInformersForNamespaces.InformersFor(installConfigNamespace).Core().V1().ConfigMaps().Informer(),
+		kubeInformersForNamespaces.InformersFor(operatorclient.GlobalUserSpecifiedConfigNamespace).Core().V1().Secrets().Informer(),
+		kubeInformersForNamespaces.InformersFor(operatorclient.OperatorNamespace).Core().V1().Secrets().Informer(),
+		kubeInformersForNamespaces.InformersFor(operatorclient.TargetNamespace).Core().V1().Secrets().Informer(),
+	).ResyncEvery(time.Hour).WithSync(c.sync).ToController("FIPSController", eventRecorder)
+}
+
+func (c *FIPSController) sync(ctx context.Context, syncCtx factory.SyncContext) error {
+	condition := operatorv1.OperatorCondition{
+		Type:   fipsKeysDegradedCondition,
+		Status: operatorv1.ConditionFalse,
+	}
+	enabled, err := IsFIPSEnabled(c.configMapLister)
+	if err != nil {
+		return err
+	}
+	if enabled {
+		var errs []error
+		for _, signer := range signerKeys {
+			if err := c.checkSignerKey(signer); err != nil {
+				errs = append(errs, fmt.Errorf("secret/%s -n %s: %v", signer.name, signer.namespace, err))
+			}
+		}
+		if len(errs) > 0 {
+			condition.Status = operatorv1.ConditionTrue
+			condition.Reason = "UnapprovedKeys"
+			condition.Message = v1helpers.NewMultiLineAggregate(errs).Error()
+		}
+	}
+	_, _, err = v1helpers.UpdateStaticPodStatus(ctx, c.operatorClient, v1helpers.UpdateStaticPodConditionFn(condition))
+	return err
+}
+
+func (c *FIPSController) checkSignerKey(signer signerKey) error {
+	secret, err := c.secretLister.Secrets(signer.namespace).Get(signer.name)
+	if apierrors.IsNotFound(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	data := secret.Data[signer.key]
+	if len(data) == 0 {
+		return nil
+	}
+	if signer.certificate {
+		return CheckCertificatesPEM(data)
+	}
+	return CheckPrivateKeyPEM(data)
+}
+
+// IsFIPSEnabled returns whether the cluster was installed in FIPS mode, as recorded in its install-config.
+func IsFIPSEnabled(configMapLister corev1listers.ConfigMapLister) (bool, error) {
+	configMap, err := configMapLister.ConfigMaps(installConfigNamespace).Get(installConfigName)
+	if apierrors.IsNotFound(err) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	installConfig := struct {
+		FIPS bool `json:"fips"`
+	}{}
+	if err := yaml.Unmarshal([]byte(configMap.Data["install-config"]), &installConfig); err != nil {
+		return false, fmt.Errorf("failed to parse the install-config of configmap/%s -n %s: %v", installConfigName, installConfigNamespace, err)
+	}
+	return installConfig.FIPS, nil
+}
+
+// CheckCertificatesPEM returns an error when a certificate of the PEM bundle has a key or signature that is not
+// approved.
+func CheckCertificatesPEM(data []byte) error {
+	certificates, err := certutil.ParseCertsPEM(data)
+	if err != nil {
+		return err
+	}
+	for _, certificate := range certificates {
+		if unapprovedSignatureAlgorithms[certificate.SignatureAlgorithm] {
+			return fmt.Errorf("certificate %q is signed with %s", certificate.Subject.CommonName, certificate.SignatureAlgorithm)
+		}
+		if err := CheckPublicKey(certificate.PublicKey); err != nil {
+			return fmt.Errorf("certificate %q: %v", certificate.Subject.CommonName, err)
+		}
+	}
+	return nil
+}
+
+// CheckPrivateKeyPEM returns an error when a PEM private key is not approved.
+func CheckPrivateKeyPEM(data []byte) error {
+	key, err := keyutil.ParsePrivateKeyPEM(data)
+	if err != nil {
+		return err
+	}
+	signer, ok := key.(crypto.Signer)
+	if !ok {
+		return fmt.Errorf("unsupported private key type %T", key)
+	}
+	return CheckPublicKey(signer.Public())
+}
+
+// CheckPublicKey returns an error unless the key is an RSA key of at least MinRSAKeyBits bits or an ECDSA key on
+// an approved curve.
+func CheckPublicKey(key crypto.PublicKey) error {
+	switch key := key.(type) {
+	case *rsa.PublicKey:
+		if bits := key.N.BitLen(); bits < MinRSAKeyBits {
+			return fmt.Errorf("RSA key of %d bits, at least %d are required", bits, MinRSAKeyBits)
+		}
+		return nil
+	case *ecdsa.PublicKey:
+		if !approvedCurves[key.Curve] {
+			return fmt.Errorf("ECDSA key on the unapproved curve %s", key.Curve.Params().Name)
+		}
+		return nil
+	default:
+		return fmt.Errorf("unapproved key type %T", key)
+	}
+}
@@ -0,0 +1,174 @@
+package fipscontroller
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"strings"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	corev1listers "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/util/keyutil"
+
+	operatorv1 "github.com/openshift/api/operator/v1"
+	"github.com/openshift/library-go/pkg/controller/factory"
+	"github.com/openshift/library-go/pkg/operator/events"
+	"github.com/openshift/library-go/pkg/operator/v1helpers"
+
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/operatorclient"
+)
+
+func rsaKey(t *testing.T, bits int) *rsa.PrivateKey {
+	key, err := rsa.GenerateKey(rand.Reader, bits)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return key
+}
+
+func selfSignedCertPEM(t *testing.T, key *rsa.PrivateKey) []byte {
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "signer"},
+		NotBefore:    time.Now(),
+		NotAfter:     time.Now().Add(time.Hour),
+		IsCA:         true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+}
+
+func TestCheckPublicKey(t *testing.T) {
+	p224, err := ecdsa.GenerateKey(elliptic.P224(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	p256, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	edKey, _, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name          string
+		key           interface{}
+		expectedError string
+	}{
+		{name: "RSA 2048", key: &rsaKey(t, 2048).PublicKey},
+		{name: "RSA 1024", key: &rsaKey(t, 1024).PublicKey, expectedError: "RSA key of 1024 bits"},
+		{name: "ECDSA P-256", key: &p256.PublicKey},
+		{name: "ECDSA P-224", key: &p224.PublicKey, expectedError: "unapproved curve P-224"},
+		{name: "Ed25519", key: edKey, expectedError: "unapproved key type"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			err := CheckPublicKey(test.key)
+			if len(test.expectedError) == 0 && err != nil || len(test.expectedError) > 0 && (err == nil || !strings.Contains(err.Error(), test.expectedError)) {
+				t.Errorf("expected error %q, got %v", test.expectedError, err)
+			}
+		})
+	}
+}
+
+func TestSync(t *testing.T) {
+	weakKey := rsaKey(t, 1024)
+	weakKeyPEM, err := keyutil.MarshalPrivateKeyToPEM(weakKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	installConfig := func(fips bool) *corev1.ConfigMap {
+		data := "fips: false\n"
+		if fips {
+			data = "fips: true\n"
+		}
+		return &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Namespace: installConfigNamespace, Name: installConfigName},
+			Data:       map[string]string{"install-config": data},
+		}
+	}
+
+	tests := []struct {
+		name            string
+		installConfig   *corev1.ConfigMap
+		secrets         []*corev1.Secret
+		expectedStatus  operatorv1.ConditionStatus
+		expectedMessage string
+	}{
+		{
+			name:          "weak keys are ignored without FIPS",
+			installConfig: installConfig(false),
+			secrets: []*corev1.Secret{
+				{ObjectMeta: metav1.ObjectMeta{Namespace: operatorclient.TargetNamespace, Name: "csr-signer"}, Data: map[string][]byte{"tls.crt": selfSignedCertPEM(t, weakKey)}},
+			},
+			expectedStatus: operatorv1.ConditionFalse,
+		},
+		{
+			name:          "approved keys",
+			installConfig: installConfig(true),
+			secrets: []*corev1.Secret{
+				{ObjectMeta: metav1.ObjectMeta{Namespace: operatorclient.TargetNamespace, Name: "csr-signer"}, Data: map[string][]byte{"tls.crt": selfSignedCertPEM(t, rsaKey(t, 2048))}},
+			},
+			expectedStatus: operatorv1.ConditionFalse,
+		},
+		{
+			name:          "weak keys in FIPS mode",
+			installConfig: installConfig(true),
+			secrets: []*corev1.Secret{
+				{ObjectMeta: metav1.ObjectMeta{Namespace: operatorclient.TargetNamespace, Name: "csr-signer"}, Data: map[string][]byte{"tls.crt": selfSignedCertPEM(t, weakKey)}},
+				{ObjectMeta: metav1.ObjectMeta{Namespace: operatorclient.GlobalUserSpecifiedConfigNamespace, Name: operatorclient.UserSpecifiedConfigSecretName}, Data: map[string][]byte{"service-account.key": weakKeyPEM}},
+			},
+			expectedStatus: operatorv1.ConditionTrue,
+			expectedMessage: "secret/csr-signer -n openshift-kube-controller-manager: certificate \"signer\": RSA key of 1024 bits, at least 2048 are required\n" +
+				"secret/initial-service-account-private-key -n openshift-config: RSA key of 1024 bits, at least 2048 are required",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			configMapIndexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc})
+			if err := configMapIndexer.Add(test.installConfig); err != nil {
+				t.Fatal(err)
+			}
+			secretIndexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc})
+			for _, secret := range test.secrets {
+				if err := secretIndexer.Add(secret); err != nil {
+					t.Fatal(err)
+				}
+			}
+			operatorClient := v1helpers.NewFakeStaticPodOperatorClient(&operatorv1.StaticPodOperatorSpec{}, &operatorv1.StaticPodOperatorStatus{}, nil, nil)
+			c := &FIPSController{
+				operatorClient:  operatorClient,
+				configMapLister: corev1listers.NewConfigMapLister(configMapIndexer),
+				secretLister:    corev1listers.NewSecretLister(secretIndexer),
+			}
+
+			if err := c.sync(context.TODO(), factory.NewSyncContext("FIPSController", events.NewInMemoryRecorder("fips"))); err != nil {
+				t.Fatal(err)
+			}
+			_, status, _, err := operatorClient.GetStaticPodOperatorState()
+			if err != nil {
+				t.Fatal(err)
+			}
+			condition := v1helpers.FindOperatorCondition(status.Conditions, fipsKeysDegradedCondition)
+			if condition == nil || condition.Status != test.expectedStatus || condition.Message != test.expectedMessage {
+				t.Errorf("expected %s %q, got %#v", test.expectedStatus, test.expectedMessage, condition)
+			}
+		})
+	}
+}
@@ -0,0 +1,104 @@
+package flagdiff
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/ghodss/yaml"
+)
+
+// Change is a flag of the kube-controller-manager that was added, removed or changed. A flag given more than once,
+// as the extendedArguments allow, has several values.
+type Change struct {
+	Flag string `json:"flag"`
+	// Old is unset for an added flag.
+	Old []string `json:"old,omitempty"`
+	// New is unset for a removed flag.
+	New []string `json:"new,omitempty"`
+}
+
+func (c Change) String() string {
+	switch {
+	case c.Old == nil:
+		return fmt.Sprintf("added --%s=%s", c.Flag, strings.Join(c.New, ","))
+	case c.New == nil:
+		return fmt.Sprintf("removed --%s=%s", c.Flag, strings.Join(c.Old, ","))
+	default:
+		return fmt.Sprintf("changed --%s from %s to %s", c.Flag, strings.Join(c.Old, ","), strings.Join(c.New, ","))
+	}
+}
+
+// Diff returns the changes between two sets of flags, sorted by flag.
+func Diff(previous, current map[string][]string) []Change {
+	flags := map[string]bool{}
+	for flag := range previous {
+		flags[flag] = true
+	}
+	for flag := range current {
+		flags[flag] = true
+	}
+	var changes []Change
+	for flag := range flags {
+		previousValues, previousFound := previous[flag]
+		currentValues, currentFound := current[flag]
+		if previousFound == currentFound && reflect.DeepEqual(previousValues, currentValues) {
+			continue
+		}
+		change := Change{Flag: flag}
+		if previousFound {
+			change.Old = nonNil(previousValues)
+		}
+		if currentFound {
+			change.New = nonNil(currentValues)
+		}
+		changes = append(changes, change)
+	}
+	sort.Slice(changes, func(i, j int) bool { return changes[i].Flag < changes[j].Flag })
+	return changes
+}
+
+// nonNil keeps a flag set without a value apart from a missing one.
+func nonNil(values []string) []string {
+	if values == nil {
+		return []string{}
+	}
+	return values
+}
+
+// Describe joins the changes into a single human readable line.
+func Describe(changes []Change) string {
+	descriptions := make([]string, 0, len(changes))
+	for _, change := range changes {
+		descriptions = append(descriptions, change.String())
+	}
+	return strings.Join(descriptions, "; ")
+}
+
+// ExtendedArguments returns the extendedArguments of a rendered kube-controller-manager config file. An empty file
+// has none.
+func ExtendedArguments(configYAML string) (map[string][]string, error) {
+	config := struct {
+		ExtendedArguments map[string][]string `json:"extendedArguments"`
+	}{}
+	if len(configYAML) == 0 {
+		return map[string][]string{}, nil
+	}
+	if err := yaml.Unmarshal([]byte(configYAML), &config); err != nil {
+		return nil, err
+	}
+	if config.ExtendedArguments == nil {
+		return map[string][]string{}, nil
+	}
+	return config.ExtendedArguments, nil
+}
+
+// SingleValued converts flags with a single value each, as parsed from a command line, for Diff.
+func SingleValued(flags map[string]string) map[string][]string {
+	converted := make(map[string][]string, len(flags))
+	for flag, value := range flags {
+		converted[flag] = []string{value}
+	}
+	return converted
+}
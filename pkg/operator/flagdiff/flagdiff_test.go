@@ -0,0 +1,46 @@
+package flagdiff
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestDiff(t *testing.T) {
+	previous := map[string][]string{
+		"cluster-name":        {"a"},
+		"controllers":         {"*", "-ttl"},
+		"feature-gates":       {"A=true"},
+		"enable-dynamic-prov": {},
+	}
+	current := map[string][]string{
+		"cluster-name":        {"a"},
+		"controllers":         {"*", "-ttl", "-bootstrapsigner"},
+		"secure-port":         {"10258"},
+		"enable-dynamic-prov": {},
+	}
+	changes := Diff(previous, current)
+	expected := []Change{
+		{Flag: "controllers", Old: []string{"*", "-ttl"}, New: []string{"*", "-ttl", "-bootstrapsigner"}},
+		{Flag: "feature-gates", Old: []string{"A=true"}},
+		{Flag: "secure-port", New: []string{"10258"}},
+	}
+	if !reflect.DeepEqual(changes, expected) {
+		t.Fatalf("expected %v, got %v", expected, changes)
+	}
+	if description := Describe(changes); description != "changed --controllers from *,-ttl to *,-ttl,-bootstrapsigner; removed --feature-gates=A=true; added --secure-port=10258" {
+		t.Errorf("unexpected description %q", description)
+	}
+}
+
+func TestExtendedArguments(t *testing.T) {
+	arguments, err := ExtendedArguments(`{"apiVersion":"kubecontrolplane.config.openshift.io/v1","extendedArguments":{"cluster-name":["a"]}}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(arguments, map[string][]string{"cluster-name": {"a"}}) {
+		t.Errorf("unexpected arguments %v", arguments)
+	}
+	if arguments, err := ExtendedArguments(""); err != nil || len(arguments) != 0 {
+		t.Errorf("expected no arguments for an empty config, got %v, %v", arguments, err)
+	}
+}
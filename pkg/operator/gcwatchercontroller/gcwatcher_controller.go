@@ -84,11 +84,6 @@ func NewGarbageCollectorWatcherController(
 			syncContext.Queue().Add(factory.DefaultQueueKey)
 		}
 	}
-	serviceCAHandler := func(obj interface{}) {
-		if metaObj, ok := obj.(metav1.Object); ok && metaObj.GetName() == "service-ca" {
-			syncContext.Queue().Add(factory.DefaultQueueKey)
-		}
-	}
 
 	configInformers.Config().V1().ClusterOperators().Informer().AddEventHandlerWithResyncPeriod(
 		// we are only interested in adds, deletes, and partial updates of monitoring object
@@ -116,13 +111,11 @@ func NewGarbageCollectorWatcherController(
 		0,
 	)
 	kubeInformersForNamespaces.InformersFor(operatorclient.GlobalMachineSpecifiedConfigNamespace).Core().V1().ConfigMaps().Informer().AddEventHandlerWithResyncPeriod(
-		cache.ResourceEventHandlerFuncs{
-			AddFunc: serviceCAHandler,
-			UpdateFunc: func(oldObj, newObj interface{}) {
-				serviceCAHandler(newObj)
-			},
-			DeleteFunc: serviceCAHandler,
-		},
+		operatorclient.NamespaceEventHandler([]string{operatorclient.GlobalMachineSpecifiedConfigNamespace}, func(obj metav1.Object) {
+			if obj.GetName() == "service-ca" {
+				syncContext.Queue().Add(factory.DefaultQueueKey)
+			}
+		}),
 		0,
 	)
 
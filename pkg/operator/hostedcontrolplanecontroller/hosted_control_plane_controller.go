@@ -0,0 +1,320 @@
+package hostedcontrolplanecontroller
+
+import (
+	"context"
+	"crypto/sha256"
+	"fmt"
+	"path"
+	"sort"
+	"time"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/client-go/kubernetes"
+	corev1listers "k8s.io/client-go/listers/core/v1"
+
+	configv1 "github.com/openshift/api/config/v1"
+	operatorv1 "github.com/openshift/api/operator/v1"
+	configv1informers "github.com/openshift/client-go/config/informers/externalversions/config/v1"
+	configv1listers "github.com/openshift/client-go/config/listers/config/v1"
+	"github.com/openshift/library-go/pkg/controller/factory"
+	"github.com/openshift/library-go/pkg/operator/events"
+	"github.com/openshift/library-go/pkg/operator/management"
+	"github.com/openshift/library-go/pkg/operator/resource/resourceapply"
+	"github.com/openshift/library-go/pkg/operator/resource/resourcemerge"
+	"github.com/openshift/library-go/pkg/operator/resource/resourceread"
+	"github.com/openshift/library-go/pkg/operator/v1helpers"
+
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/operatorclient"
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/tuning"
+)
+
+const (
+	// resourcesHashAnnotation on the pod template rolls out the deployment when the copied resources change.
+	// The certificates are left out, the kubelet refreshes the projected volume and the operand reloads them.
+	resourcesHashAnnotation = "kubecontrollermanager.operator.openshift.io/resources-hash"
+
+	resourceDirVolume = "resource-dir"
+	certDirVolume     = "cert-dir"
+)
+
+// staticPodOnlyContainers only make sense on a control plane node. The kubelet keeps the projected certs up to date
+// instead of the cert-syncer, and the recovery controller regenerates certs through the local apiserver endpoint.
+var staticPodOnlyContainers = sets.New[string](
+	"kube-controller-manager-cert-syncer",
+	"kube-controller-manager-recovery-controller",
+)
+
+// Resource is a configmap or secret in the target namespace the operand reads.
+type Resource struct {
+	Name     string
+	Optional bool
+}
+
+// Resources lists the inputs of the operand, mirroring the revisioned resources and the unrevisioned certs of the
+// static pod installer.
+type Resources struct {
+	ConfigMaps     []Resource
+	Secrets        []Resource
+	CertConfigMaps []Resource
+	CertSecrets    []Resource
+}
+
+// HostedControlPlaneController runs the kube-controller-manager as a deployment in the hosted control plane namespace
+// when the control plane topology is External, since there are no control plane nodes to run static pods on. It reuses
+// the pod manifest rendered by the target config controller, so config observation and cert management are shared
+// with the static pod mode, and only replaces the host paths with projected copies of the operand inputs.
+type HostedControlPlaneController struct {
+	operatorClient       v1helpers.StaticPodOperatorClient
+	kubeClient           kubernetes.Interface
+	configMapLister      corev1listers.ConfigMapLister
+	secretLister         corev1listers.SecretLister
+	infrastructureLister configv1listers.InfrastructureLister
+	resources            Resources
+}
+
+func NewHostedControlPlaneController(
+	operatorClient v1helpers.StaticPodOperatorClient,
+	kubeInformersForNamespaces v1helpers.KubeInformersForNamespaces,
+	kubeClient kubernetes.Interface,
+	infrastructureInformer configv1informers.InfrastructureInformer,
+	resources Resources,
+	eventRecorder events.Recorder,
+) factory.Controller {
+	c := &HostedControlPlaneController{
+		operatorClient:       operatorClient,
+		kubeClient:           kubeClient,
+		configMapLister:      kubeInformersForNamespaces.ConfigMapLister(),
+		secretLister:         kubeInformersForNamespaces.SecretLister(),
+		infrastructureLister: infrastructureInformer.Lister(),
+		resources:            resources,
+	}
+
+	return factory.New().WithInformers(
+		operatorClient.Informer(),
+		infrastructureInformer.Informer(),
+		// the operand inputs and the rendered pod
+		kubeInformersForNamespaces.InformersFor(operatorclient.TargetNamespace).Core().V1().ConfigMaps().Informer(),
+		kubeInformersForNamespaces.InformersFor(operatorclient.TargetNamespace).Core().V1().Secrets().Informer(),
+		// the tuning config names the hosted control plane namespace
+		kubeInformersForNamespaces.InformersFor(operatorclient.OperatorNamespace).Core().V1().ConfigMaps().Informer(),
+	).ResyncEvery(time.Minute).WithSync(c.sync).ToController("HostedControlPlaneController", eventRecorder)
+}
+
+func (c *HostedControlPlaneController) sync(ctx context.Context, syncCtx factory.SyncContext) error {
+	operatorSpec, operatorStatus, _, err := c.operatorClient.GetStaticPodOperatorState()
+	if err != nil {
+		return err
+	}
+	if !management.IsOperatorManaged(operatorSpec.ManagementState) {
+		return nil
+	}
+
+	infrastructure, err := c.infrastructureLister.Get("cluster")
+	if err != nil {
+		return err
+	}
+	if infrastructure.Status.ControlPlaneTopology != configv1.ExternalTopologyMode {
+		return c.updateStatus(ctx, nil, nil)
+	}
+
+	deployment, syncErr := c.syncDeployment(ctx, syncCtx.Recorder(), operatorStatus)
+	if err := c.updateStatus(ctx, deployment, syncErr); err != nil {
+		return err
+	}
+	return syncErr
+}
+
+func (c *HostedControlPlaneController) syncDeployment(ctx context.Context, recorder events.Recorder, operatorStatus *operatorv1.StaticPodOperatorStatus) (*appsv1.Deployment, error) {
+	tuningConfig, err := tuning.Get(c.configMapLister)
+	if err != nil {
+		return nil, err
+	}
+	namespace := tuningConfig.HostedControlPlaneNamespace
+	if len(namespace) == 0 {
+		return nil, fmt.Errorf("hostedControlPlaneNamespace must be set in %s/%s for the %s control plane topology", operatorclient.OperatorNamespace, tuning.ConfigMapName, configv1.ExternalTopologyMode)
+	}
+
+	podConfigMap, err := c.configMapLister.ConfigMaps(operatorclient.TargetNamespace).Get("kube-controller-manager-pod")
+	if err != nil {
+		return nil, err
+	}
+	pod, err := resourceread.ReadPodV1([]byte(podConfigMap.Data["pod.yaml"]))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse %s/%s: %v", podConfigMap.Namespace, podConfigMap.Name, err)
+	}
+
+	resourceDir, err := c.copyResources(ctx, recorder, namespace, c.resources.ConfigMaps, c.resources.Secrets)
+	if err != nil {
+		return nil, err
+	}
+	certDir, err := c.copyResources(ctx, recorder, namespace, c.resources.CertConfigMaps, c.resources.CertSecrets)
+	if err != nil {
+		return nil, err
+	}
+
+	required := renderDeployment(pod, namespace, resourceDir, certDir)
+	deployment, _, err := resourceapply.ApplyDeployment(ctx, c.kubeClient.AppsV1(), recorder, required, resourcemerge.ExpectedDeploymentGeneration(required, operatorStatus.Generations))
+	return deployment, err
+}
+
+// projectedDir is the content of a projected volume laid out like the static pod resource directories.
+type projectedDir struct {
+	sources []corev1.VolumeProjection
+	hash    string
+}
+
+// copyResources copies the configmaps and secrets from the target namespace into the hosted control plane namespace
+// and returns a projected volume that mounts them at configmaps/<name>/<key> and secrets/<name>/<key>.
+func (c *HostedControlPlaneController) copyResources(ctx context.Context, recorder events.Recorder, namespace string, configMaps, secrets []Resource) (projectedDir, error) {
+	dir := projectedDir{}
+	hash := sha256.New()
+
+	for _, resource := range configMaps {
+		configMap, err := c.configMapLister.ConfigMaps(operatorclient.TargetNamespace).Get(resource.Name)
+		if apierrors.IsNotFound(err) && resource.Optional {
+			continue
+		}
+		if err != nil {
+			return dir, err
+		}
+		copied := &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: configMap.Name},
+			Data:       configMap.Data,
+			BinaryData: configMap.BinaryData,
+		}
+		if _, _, err := resourceapply.ApplyConfigMap(ctx, c.kubeClient.CoreV1(), recorder, copied); err != nil {
+			return dir, err
+		}
+
+		var items []corev1.KeyToPath
+		for _, key := range sortedKeys(configMap.Data, configMap.BinaryData) {
+			items = append(items, corev1.KeyToPath{Key: key, Path: path.Join("configmaps", configMap.Name, key)})
+			fmt.Fprintf(hash, "configmap/%s/%s=%s%s\n", configMap.Name, key, configMap.Data[key], configMap.BinaryData[key])
+		}
+		dir.sources = append(dir.sources, corev1.VolumeProjection{ConfigMap: &corev1.ConfigMapProjection{
+			LocalObjectReference: corev1.LocalObjectReference{Name: configMap.Name},
+			Items:                items,
+		}})
+	}
+
+	for _, resource := range secrets {
+		secret, err := c.secretLister.Secrets(operatorclient.TargetNamespace).Get(resource.Name)
+		if apierrors.IsNotFound(err) && resource.Optional {
+			continue
+		}
+		if err != nil {
+			return dir, err
+		}
+		copied := &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: secret.Name},
+			Type:       secret.Type,
+			Data:       secret.Data,
+		}
+		if _, _, err := resourceapply.ApplySecret(ctx, c.kubeClient.CoreV1(), recorder, copied); err != nil {
+			return dir, err
+		}
+
+		var items []corev1.KeyToPath
+		for _, key := range sortedKeys(nil, secret.Data) {
+			items = append(items, corev1.KeyToPath{Key: key, Path: path.Join("secrets", secret.Name, key)})
+			fmt.Fprintf(hash, "secret/%s/%s=%s\n", secret.Name, key, secret.Data[key])
+		}
+		dir.sources = append(dir.sources, corev1.VolumeProjection{Secret: &corev1.SecretProjection{
+			LocalObjectReference: corev1.LocalObjectReference{Name: secret.Name},
+			Items:                items,
+		}})
+	}
+
+	dir.hash = fmt.Sprintf("%x", hash.Sum(nil))
+	return dir, nil
+}
+
+// renderDeployment turns the static pod manifest into a deployment in the given namespace. The host paths are
+// replaced with the projected resource and cert directories, and the pod leaves the host network since it no
+// longer runs on a control plane node.
+func renderDeployment(pod *corev1.Pod, namespace string, resourceDir, certDir projectedDir) *appsv1.Deployment {
+	podSpec := pod.Spec.DeepCopy()
+	podSpec.HostNetwork = false
+	podSpec.NodeName = ""
+	podSpec.Containers = nil
+	for _, container := range pod.Spec.Containers {
+		if !staticPodOnlyContainers.Has(container.Name) {
+			podSpec.Containers = append(podSpec.Containers, container)
+		}
+	}
+	for i, volume := range podSpec.Volumes {
+		switch volume.Name {
+		case resourceDirVolume:
+			podSpec.Volumes[i].VolumeSource = corev1.VolumeSource{Projected: &corev1.ProjectedVolumeSource{Sources: resourceDir.sources}}
+		case certDirVolume:
+			podSpec.Volumes[i].VolumeSource = corev1.VolumeSource{Projected: &corev1.ProjectedVolumeSource{Sources: certDir.sources}}
+		}
+	}
+
+	labels := map[string]string{}
+	for key, value := range pod.Labels {
+		// revisions only exist for static pods
+		if key != "revision" {
+			labels[key] = value
+		}
+	}
+	annotations := map[string]string{resourcesHashAnnotation: resourceDir.hash}
+	for key, value := range pod.Annotations {
+		annotations[key] = value
+	}
+
+	// two replicas behind leader election, like the control plane of a highly available cluster
+	replicas := int32(2)
+	return &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: namespace,
+			Name:      pod.Name,
+			Labels:    labels,
+		},
+		Spec: appsv1.DeploymentSpec{
+			Replicas: &replicas,
+			Selector: &metav1.LabelSelector{MatchLabels: labels},
+			Template: corev1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{Labels: labels, Annotations: annotations},
+				Spec:       *podSpec,
+			},
+		},
+	}
+}
+
+func (c *HostedControlPlaneController) updateStatus(ctx context.Context, deployment *appsv1.Deployment, syncErr error) error {
+	condition := operatorv1.OperatorCondition{
+		Type:   "HostedControlPlaneControllerDegraded",
+		Status: operatorv1.ConditionFalse,
+	}
+	if syncErr != nil {
+		condition.Status = operatorv1.ConditionTrue
+		condition.Reason = "SynchronizationError"
+		condition.Message = syncErr.Error()
+	}
+	updateFuncs := []v1helpers.UpdateStaticPodStatusFunc{v1helpers.UpdateStaticPodConditionFn(condition)}
+	if deployment != nil {
+		updateFuncs = append(updateFuncs, func(status *operatorv1.StaticPodOperatorStatus) error {
+			resourcemerge.SetDeploymentGeneration(&status.Generations, deployment)
+			return nil
+		})
+	}
+	_, _, err := v1helpers.UpdateStaticPodStatus(ctx, c.operatorClient, updateFuncs...)
+	return err
+}
+
+func sortedKeys(data map[string]string, binaryData map[string][]byte) []string {
+	keys := make([]string, 0, len(data)+len(binaryData))
+	for key := range data {
+		keys = append(keys, key)
+	}
+	for key := range binaryData {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,127 @@
+package hostedcontrolplanecontroller
+
+import (
+	"context"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+	corev1listers "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/tools/cache"
+
+	"github.com/openshift/library-go/pkg/operator/events"
+	"github.com/openshift/library-go/pkg/operator/resource/resourceread"
+
+	"github.com/openshift/cluster-kube-controller-manager-operator/bindata"
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/operatorclient"
+)
+
+func TestRenderDeployment(t *testing.T) {
+	configMapIndexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc})
+	secretIndexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc})
+	configMapIndexer.Add(&corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Namespace: operatorclient.TargetNamespace, Name: "config"},
+		Data:       map[string]string{"config.yaml": "{}"},
+	})
+	configMapIndexer.Add(&corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Namespace: operatorclient.TargetNamespace, Name: "client-ca"},
+		Data:       map[string]string{"ca-bundle.crt": "ca"},
+	})
+	secretIndexer.Add(&corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Namespace: operatorclient.TargetNamespace, Name: "csr-signer"},
+		Data:       map[string][]byte{"tls.key": []byte("key"), "tls.crt": []byte("crt")},
+	})
+
+	kubeClient := fake.NewSimpleClientset()
+	c := &HostedControlPlaneController{
+		kubeClient:      kubeClient,
+		configMapLister: corev1listers.NewConfigMapLister(configMapIndexer),
+		secretLister:    corev1listers.NewSecretLister(secretIndexer),
+	}
+	recorder := events.NewInMemoryRecorder("test")
+
+	resourceDir, err := c.copyResources(context.TODO(), recorder, "hcp", []Resource{{Name: "config"}, {Name: "cloud-config", Optional: true}}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	certDir, err := c.copyResources(context.TODO(), recorder, "hcp", []Resource{{Name: "client-ca"}}, []Resource{{Name: "csr-signer"}, {Name: "metrics-serving-cert", Optional: true}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := c.copyResources(context.TODO(), recorder, "hcp", []Resource{{Name: "missing"}}, nil); err == nil {
+		t.Errorf("expected an error for a missing required configmap")
+	}
+
+	if _, err := kubeClient.CoreV1().ConfigMaps("hcp").Get(context.TODO(), "config", metav1.GetOptions{}); err != nil {
+		t.Errorf("expected the config configmap to be copied: %v", err)
+	}
+	if _, err := kubeClient.CoreV1().Secrets("hcp").Get(context.TODO(), "csr-signer", metav1.GetOptions{}); err != nil {
+		t.Errorf("expected the csr-signer secret to be copied: %v", err)
+	}
+
+	pod := resourceread.ReadPodV1OrDie(bindata.MustAsset("assets/kube-controller-manager/pod.yaml"))
+	deployment := renderDeployment(pod, "hcp", resourceDir, certDir)
+
+	if deployment.Namespace != "hcp" {
+		t.Errorf("expected namespace hcp, got %q", deployment.Namespace)
+	}
+	if _, ok := deployment.Spec.Template.Labels["revision"]; ok {
+		t.Errorf("expected no revision label, got %v", deployment.Spec.Template.Labels)
+	}
+	if deployment.Spec.Template.Spec.HostNetwork {
+		t.Errorf("expected the deployment not to use the host network")
+	}
+	if deployment.Spec.Template.Annotations[resourcesHashAnnotation] != resourceDir.hash {
+		t.Errorf("expected the resources hash annotation to be %q, got %q", resourceDir.hash, deployment.Spec.Template.Annotations[resourcesHashAnnotation])
+	}
+	for _, container := range deployment.Spec.Template.Spec.Containers {
+		if staticPodOnlyContainers.Has(container.Name) {
+			t.Errorf("unexpected container %q", container.Name)
+		}
+	}
+
+	for _, volume := range deployment.Spec.Template.Spec.Volumes {
+		if volume.HostPath != nil {
+			t.Errorf("unexpected host path volume %q", volume.Name)
+		}
+		if volume.Name != certDirVolume {
+			continue
+		}
+		var paths []string
+		for _, source := range volume.Projected.Sources {
+			if source.ConfigMap != nil {
+				for _, item := range source.ConfigMap.Items {
+					paths = append(paths, item.Path)
+				}
+			}
+			if source.Secret != nil {
+				for _, item := range source.Secret.Items {
+					paths = append(paths, item.Path)
+				}
+			}
+		}
+		expected := []string{"configmaps/client-ca/ca-bundle.crt", "secrets/csr-signer/tls.crt", "secrets/csr-signer/tls.key"}
+		if len(paths) != len(expected) {
+			t.Fatalf("expected cert paths %v, got %v", expected, paths)
+		}
+		for i := range expected {
+			if paths[i] != expected[i] {
+				t.Errorf("expected cert paths %v, got %v", expected, paths)
+			}
+		}
+	}
+
+	// the certs rotate without a rollout
+	secretIndexer.Update(&corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Namespace: operatorclient.TargetNamespace, Name: "csr-signer"},
+		Data:       map[string][]byte{"tls.key": []byte("new-key"), "tls.crt": []byte("new-crt")},
+	})
+	rotatedResourceDir, err := c.copyResources(context.TODO(), recorder, "hcp", []Resource{{Name: "config"}}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rotatedResourceDir.hash != resourceDir.hash {
+		t.Errorf("expected the resources hash not to change on cert rotation")
+	}
+}
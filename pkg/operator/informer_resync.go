@@ -0,0 +1,41 @@
+package operator
+
+import (
+	"time"
+
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/tuning"
+)
+
+const (
+	// defaultInformerResyncPeriod is the resync period of library-go's informers for namespaces.
+	defaultInformerResyncPeriod = 10 * time.Minute
+	// defaultInformerResyncJitterFactor keeps the operators restarted together by an upgrade from resyncing in
+	// lockstep.
+	defaultInformerResyncJitterFactor = 0.1
+)
+
+// informerResyncPeriods returns the resync period of the informers of every namespace, the empty namespace being
+// the cluster-scoped informers. Every period is jittered on its own.
+func informerResyncPeriods(config tuning.InformerResyncConfig, namespaces []string, jitter func(time.Duration, float64) time.Duration) map[string]time.Duration {
+	period := defaultInformerResyncPeriod
+	if config.Period != nil {
+		period = config.Period.Duration
+	}
+	jitterFactor := defaultInformerResyncJitterFactor
+	if config.JitterFactor != nil {
+		jitterFactor = *config.JitterFactor
+	}
+
+	periods := map[string]time.Duration{}
+	for _, namespace := range namespaces {
+		namespacePeriod := period
+		if override, ok := config.NamespacePeriods[namespace]; ok {
+			namespacePeriod = override.Duration
+		}
+		periods[namespace] = namespacePeriod
+		if jitterFactor > 0 {
+			periods[namespace] = jitter(namespacePeriod, jitterFactor)
+		}
+	}
+	return periods
+}
@@ -0,0 +1,54 @@
+package operator
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/tuning"
+)
+
+func TestInformerResyncPeriods(t *testing.T) {
+	// the maximal jitter makes the factor visible in the periods
+	maxJitter := func(duration time.Duration, maxFactor float64) time.Duration {
+		return duration + time.Duration(maxFactor*float64(duration))
+	}
+	namespaces := []string{"", "openshift-config", "openshift-kube-controller-manager"}
+
+	tests := []struct {
+		name     string
+		config   tuning.InformerResyncConfig
+		expected map[string]time.Duration
+	}{
+		{
+			name:     "defaults",
+			expected: map[string]time.Duration{"": 11 * time.Minute, "openshift-config": 11 * time.Minute, "openshift-kube-controller-manager": 11 * time.Minute},
+		},
+		{
+			name: "namespace period and no jitter",
+			config: tuning.InformerResyncConfig{
+				Period:           &metav1.Duration{Duration: 30 * time.Minute},
+				NamespacePeriods: map[string]metav1.Duration{"openshift-kube-controller-manager": {Duration: 5 * time.Minute}},
+				JitterFactor:     ptr.To(0.0),
+			},
+			expected: map[string]time.Duration{"": 30 * time.Minute, "openshift-config": 30 * time.Minute, "openshift-kube-controller-manager": 5 * time.Minute},
+		},
+		{
+			name: "jitter factor",
+			config: tuning.InformerResyncConfig{
+				JitterFactor: ptr.To(0.5),
+			},
+			expected: map[string]time.Duration{"": 15 * time.Minute, "openshift-config": 15 * time.Minute, "openshift-kube-controller-manager": 15 * time.Minute},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if actual := informerResyncPeriods(test.config, namespaces, maxJitter); !reflect.DeepEqual(actual, test.expected) {
+				t.Errorf("expected %v, got %v", test.expected, actual)
+			}
+		})
+	}
+}
@@ -0,0 +1,177 @@
+package installerlogcontroller
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/kubernetes"
+	corev1client "k8s.io/client-go/kubernetes/typed/core/v1"
+	corev1listers "k8s.io/client-go/listers/core/v1"
+
+	operatorv1 "github.com/openshift/api/operator/v1"
+	"github.com/openshift/library-go/pkg/controller/factory"
+	"github.com/openshift/library-go/pkg/operator/events"
+	"github.com/openshift/library-go/pkg/operator/v1helpers"
+
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/operatorclient"
+)
+
+const (
+	// installerFailedReason is the LastFailedReason the installer controller sets when an installer pod failed.
+	installerFailedReason = "InstallerFailed"
+
+	// excerptPrefix starts the error holding the log excerpt of a failed installer pod, which marks the failure as
+	// handled.
+	excerptPrefix = "log excerpt of "
+
+	// tailLines is the number of log lines fetched from a failed container.
+	tailLines int64 = 20
+	// maxExcerpt caps the log excerpt of a container, the NodeInstallerDegraded message holds the excerpts of all
+	// the failing nodes.
+	maxExcerpt = 1024
+)
+
+// InstallerLogController adds the tail of the logs of the failed containers of a failed installer pod to the
+// lastFailedRevisionErrors of its node, which the installer controller reports in the NodeInstallerDegraded condition,
+// and to an InstallerPodLogExcerpt event. The termination message of the installer pod only covers the installer
+// container and is often just the last error.
+type InstallerLogController struct {
+	operatorClient v1helpers.StaticPodOperatorClient
+	podLister      corev1listers.PodLister
+	podsGetter     corev1client.PodsGetter
+}
+
+func NewInstallerLogController(
+	operatorClient v1helpers.StaticPodOperatorClient,
+	kubeInformersForNamespaces v1helpers.KubeInformersForNamespaces,
+	kubeClient kubernetes.Interface,
+	eventRecorder events.Recorder,
+) factory.Controller {
+	c := &InstallerLogController{
+		operatorClient: operatorClient,
+		podLister:      kubeInformersForNamespaces.InformersFor(operatorclient.TargetNamespace).Core().V1().Pods().Lister(),
+		podsGetter:     kubeClient.CoreV1(),
+	}
+
+	return factory.New().WithInformers(
+		operatorClient.Informer(),
+		kubeInformersForNamespaces.InformersFor(operatorclient.TargetNamespace).Core().V1().Pods().Informer(),
+	).ResyncEvery(time.Minute).WithSync(c.sync).ToController("InstallerLogController", eventRecorder)
+}
+
+func (c *InstallerLogController) sync(ctx context.Context, syncCtx factory.SyncContext) error {
+	_, operatorStatus, _, err := c.operatorClient.GetStaticPodOperatorState()
+	if err != nil {
+		return err
+	}
+	pods, err := c.podLister.Pods(operatorclient.TargetNamespace).List(labels.SelectorFromSet(labels.Set{"app": "installer"}))
+	if err != nil {
+		return err
+	}
+
+	excerpts := map[string]string{}
+	for _, nodeStatus := range operatorStatus.NodeStatuses {
+		if !needsExcerpt(nodeStatus) {
+			continue
+		}
+		pod := failedInstallerPod(pods, nodeStatus.NodeName, nodeStatus.LastFailedRevision)
+		if pod == nil {
+			// the pod is gone or its failure not observed yet
+			continue
+		}
+		excerpt, err := c.excerpt(ctx, pod)
+		if err != nil {
+			return err
+		}
+		excerpts[nodeStatus.NodeName] = excerpt
+		syncCtx.Recorder().Warningf("InstallerPodLogExcerpt", "Installer pod %s failed to install revision %d on node %s:\n%s", pod.Name, nodeStatus.LastFailedRevision, nodeStatus.NodeName, excerpt)
+	}
+	if len(excerpts) == 0 {
+		return nil
+	}
+
+	_, _, err = v1helpers.UpdateStaticPodStatus(ctx, c.operatorClient, func(newStatus *operatorv1.StaticPodOperatorStatus) error {
+		for i := range newStatus.NodeStatuses {
+			nodeStatus := &newStatus.NodeStatuses[i]
+			// a retry may have reset the errors in the meantime, its pod gets its own excerpt
+			if excerpt, ok := excerpts[nodeStatus.NodeName]; ok && needsExcerpt(*nodeStatus) {
+				nodeStatus.LastFailedRevisionErrors = append(nodeStatus.LastFailedRevisionErrors, excerpt)
+			}
+		}
+		return nil
+	})
+	return err
+}
+
+// needsExcerpt returns whether the installer of the target revision of the node failed and its errors lack the log
+// excerpt.
+func needsExcerpt(nodeStatus operatorv1.NodeStatus) bool {
+	if nodeStatus.LastFailedReason != installerFailedReason || nodeStatus.LastFailedRevision == 0 || nodeStatus.LastFailedRevision != nodeStatus.TargetRevision {
+		return false
+	}
+	for _, failure := range nodeStatus.LastFailedRevisionErrors {
+		if strings.HasPrefix(failure, excerptPrefix) {
+			return false
+		}
+	}
+	return true
+}
+
+// failedInstallerPod returns the newest failed installer pod of the revision on the node, nil when there is none.
+func failedInstallerPod(pods []*corev1.Pod, nodeName string, revision int32) *corev1.Pod {
+	prefix := fmt.Sprintf("installer-%d-", revision)
+	var failed *corev1.Pod
+	for _, pod := range pods {
+		if pod.Spec.NodeName != nodeName || !strings.HasPrefix(pod.Name, prefix) || pod.Status.Phase != corev1.PodFailed {
+			continue
+		}
+		if failed == nil || failed.CreationTimestamp.Before(&pod.CreationTimestamp) {
+			failed = pod
+		}
+	}
+	return failed
+}
+
+// excerpt returns the truncated tails of the logs of the containers of the pod that exited with an error, the init
+// containers first.
+func (c *InstallerLogController) excerpt(ctx context.Context, pod *corev1.Pod) (string, error) {
+	var failedContainers []string
+	for _, statuses := range [][]corev1.ContainerStatus{pod.Status.InitContainerStatuses, pod.Status.ContainerStatuses} {
+		for _, status := range statuses {
+			if terminated := status.State.Terminated; terminated != nil && terminated.ExitCode != 0 {
+				failedContainers = append(failedContainers, status.Name)
+			}
+		}
+	}
+
+	lines := []string{fmt.Sprintf("%spod %s:", excerptPrefix, pod.Name)}
+	if len(failedContainers) == 0 {
+		return strings.Join(append(lines, "no container exited with an error"), "\n"), nil
+	}
+	for _, container := range failedContainers {
+		tail := tailLines
+		logs, err := c.podsGetter.Pods(pod.Namespace).GetLogs(pod.Name, &corev1.PodLogOptions{Container: container, TailLines: &tail}).DoRaw(ctx)
+		if err != nil {
+			return "", fmt.Errorf("failed to get the logs of container %s of pod %s: %v", container, pod.Name, err)
+		}
+		lines = append(lines, fmt.Sprintf("%s: %s", container, truncate(strings.TrimSpace(string(logs)))))
+	}
+	return strings.Join(lines, "\n"), nil
+}
+
+// truncate keeps the end of the logs, where the error is.
+func truncate(logs string) string {
+	if len(logs) <= maxExcerpt {
+		return logs
+	}
+	logs = logs[len(logs)-maxExcerpt:]
+	if i := strings.Index(logs, "\n"); i >= 0 {
+		// start at a full line
+		logs = logs[i+1:]
+	}
+	return "..." + logs
+}
@@ -0,0 +1,133 @@
+package installerlogcontroller
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+	corev1listers "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/tools/cache"
+
+	operatorv1 "github.com/openshift/api/operator/v1"
+	"github.com/openshift/library-go/pkg/controller/factory"
+	"github.com/openshift/library-go/pkg/operator/events"
+	"github.com/openshift/library-go/pkg/operator/v1helpers"
+
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/operatorclient"
+)
+
+func installerPod(name, nodeName string, phase corev1.PodPhase, created time.Time, failedContainers ...string) *corev1.Pod {
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace:         operatorclient.TargetNamespace,
+			Name:              name,
+			Labels:            map[string]string{"app": "installer"},
+			CreationTimestamp: metav1.NewTime(created),
+		},
+		Spec:   corev1.PodSpec{NodeName: nodeName},
+		Status: corev1.PodStatus{Phase: phase},
+	}
+	pod.Status.InitContainerStatuses = []corev1.ContainerStatus{{
+		Name:  "wait-for-rollout-barrier",
+		State: corev1.ContainerState{Terminated: &corev1.ContainerStateTerminated{ExitCode: 0}},
+	}}
+	for _, container := range failedContainers {
+		pod.Status.ContainerStatuses = append(pod.Status.ContainerStatuses, corev1.ContainerStatus{
+			Name:  container,
+			State: corev1.ContainerState{Terminated: &corev1.ContainerStateTerminated{ExitCode: 1}},
+		})
+	}
+	return pod
+}
+
+func TestSync(t *testing.T) {
+	created := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	pods := []*corev1.Pod{
+		installerPod("installer-3-master-0", "master-0", corev1.PodFailed, created, "installer"),
+		installerPod("installer-3-retry-1-master-0", "master-0", corev1.PodFailed, created.Add(time.Minute), "installer"),
+		installerPod("installer-3-master-1", "master-1", corev1.PodSucceeded, created),
+	}
+	indexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{})
+	for _, pod := range pods {
+		if err := indexer.Add(pod); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	operatorClient := v1helpers.NewFakeStaticPodOperatorClient(
+		&operatorv1.StaticPodOperatorSpec{OperatorSpec: operatorv1.OperatorSpec{ManagementState: operatorv1.Managed}},
+		&operatorv1.StaticPodOperatorStatus{
+			LatestAvailableRevision: 3,
+			NodeStatuses: []operatorv1.NodeStatus{
+				{
+					NodeName:                 "master-0",
+					CurrentRevision:          2,
+					TargetRevision:           3,
+					LastFailedRevision:       3,
+					LastFailedReason:         installerFailedReason,
+					LastFailedCount:          2,
+					LastFailedRevisionErrors: []string{"installer: failed to copy"},
+				},
+				{NodeName: "master-1", CurrentRevision: 3},
+			},
+		},
+		nil,
+		nil,
+	)
+	c := &InstallerLogController{
+		operatorClient: operatorClient,
+		podLister:      corev1listers.NewPodLister(indexer),
+		podsGetter:     fake.NewSimpleClientset().CoreV1(),
+	}
+	recorder := events.NewInMemoryRecorder("test")
+
+	for i := 0; i < 2; i++ {
+		if err := c.sync(context.TODO(), factory.NewSyncContext("test", recorder)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	_, status, _, err := operatorClient.GetStaticPodOperatorState()
+	if err != nil {
+		t.Fatal(err)
+	}
+	errors := status.NodeStatuses[0].LastFailedRevisionErrors
+	if len(errors) != 2 {
+		t.Fatalf("expected the excerpt to be added once, got %q", errors)
+	}
+	// the fake clientset returns "fake logs"
+	if expected := "log excerpt of pod installer-3-retry-1-master-0:\ninstaller: fake logs"; errors[1] != expected {
+		t.Errorf("expected excerpt %q, got %q", expected, errors[1])
+	}
+	if len(status.NodeStatuses[1].LastFailedRevisionErrors) != 0 {
+		t.Errorf("expected no excerpt for master-1, got %q", status.NodeStatuses[1].LastFailedRevisionErrors)
+	}
+
+	var excerptEvents int
+	for _, event := range recorder.Events() {
+		if event.Reason == "InstallerPodLogExcerpt" {
+			excerptEvents++
+		}
+	}
+	if excerptEvents != 1 {
+		t.Errorf("expected one InstallerPodLogExcerpt event, got %d", excerptEvents)
+	}
+}
+
+func TestTruncate(t *testing.T) {
+	if truncate("short") != "short" {
+		t.Errorf("expected short logs to be kept")
+	}
+	logs := strings.Repeat("earlier line\n", 200) + "E0101 failed to copy the secrets"
+	truncated := truncate(logs)
+	if len(truncated) > maxExcerpt+3 {
+		t.Errorf("expected at most %d bytes, got %d", maxExcerpt+3, len(truncated))
+	}
+	if !strings.HasPrefix(truncated, "...earlier line\n") || !strings.HasSuffix(truncated, "failed to copy the secrets") {
+		t.Errorf("expected the end of the logs from a full line, got %q", truncated)
+	}
+}
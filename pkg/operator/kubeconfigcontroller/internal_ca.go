@@ -0,0 +1,99 @@
+package kubeconfigcontroller
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	corev1listers "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/tools/clientcmd"
+	"k8s.io/klog/v2"
+
+	"github.com/openshift/library-go/pkg/operator/certrotation"
+	"github.com/openshift/library-go/pkg/operator/events"
+	"github.com/openshift/library-go/pkg/operator/resourcesynccontroller"
+
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/cabundle"
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/operatorclient"
+)
+
+const (
+	// internalCAConfigMapName is the revisioned CA bundle the kubeconfig trusts when the tuning configmap names an
+	// internal API server CA.
+	internalCAConfigMapName = "controller-manager-kubeconfig-ca"
+	internalCAPath          = "/etc/kubernetes/static-pod-resources/configmaps/" + internalCAConfigMapName + "/ca-bundle.crt"
+)
+
+// internalCABundle combines the kube-apiserver serving CAs the kubeconfig trusts by default with the custom CA of
+// the internal API load balancer.
+func internalCABundle(lister corev1listers.ConfigMapLister, customCA string) (*corev1.ConfigMap, error) {
+	custom, err := lister.ConfigMaps(operatorclient.GlobalUserSpecifiedConfigNamespace).Get(customCA)
+	if err != nil {
+		return nil, fmt.Errorf("internalAPIServerCA: %v", err)
+	}
+	if len(custom.Data["ca-bundle.crt"]) == 0 {
+		return nil, fmt.Errorf("internalAPIServerCA: configmap/%s in %q has no ca-bundle.crt", customCA, operatorclient.GlobalUserSpecifiedConfigNamespace)
+	}
+	return resourcesynccontroller.CombineCABundleConfigMaps(
+		resourcesynccontroller.ResourceLocation{Namespace: operatorclient.TargetNamespace, Name: internalCAConfigMapName},
+		lister,
+		certrotation.AdditionalAnnotations{
+			JiraComponent: "kube-controller-manager",
+		},
+		resourcesynccontroller.ResourceLocation{Namespace: operatorclient.GlobalMachineSpecifiedConfigNamespace, Name: "kube-apiserver-server-ca"},
+		resourcesynccontroller.ResourceLocation{Namespace: operatorclient.GlobalUserSpecifiedConfigNamespace, Name: customCA},
+	)
+}
+
+// withCertificateAuthority points the clusters of the kubeconfig in the configmap at another CA file.
+func withCertificateAuthority(cm *corev1.ConfigMap, path string) error {
+	kubeconfig, err := clientcmd.Load([]byte(cm.Data[kubeconfigKey]))
+	if err != nil {
+		return fmt.Errorf("failed to parse %s/%s: %v", cm.Namespace, cm.Name, err)
+	}
+	for _, cluster := range kubeconfig.Clusters {
+		cluster.CertificateAuthority = path
+	}
+	kubeconfigBytes, err := clientcmd.Write(*kubeconfig)
+	if err != nil {
+		return err
+	}
+	cm.Data[kubeconfigKey] = string(kubeconfigBytes)
+	return nil
+}
+
+// verifyServerCertificate checks that the server presents a certificate the CA bundle verifies before a kubeconfig
+// trusting the bundle rolls out, a kube-controller-manager that cannot reach the kube-apiserver would otherwise
+// only fail once it restarts. A server the operator cannot reach is not held against the bundle, the internal API
+// URL may only resolve on the control plane hosts.
+func verifyServerCertificate(server string, caBundle []byte, recorder events.Recorder) error {
+	err := cabundle.VerifyServerCertificate(server, caBundle)
+	var unreachable *cabundle.UnreachableError
+	if errors.As(err, &unreachable) {
+		klog.Warningf("Could not verify the certificate of %s: %v", server, unreachable.Err)
+		recorder.Warningf("InternalAPIServerUnverified", "Could not verify the certificate of %s against the internal API server CA: %v", server, unreachable.Err)
+		return nil
+	}
+	return err
+}
+
+// deleteInternalCABundle removes the CA bundle once the kubeconfig no longer trusts it.
+func (c *KubeconfigController) deleteInternalCABundle(ctx context.Context, recorder events.Recorder) error {
+	if _, err := c.configMapLister.ConfigMaps(operatorclient.TargetNamespace).Get(internalCAConfigMapName); apierrors.IsNotFound(err) {
+		return nil
+	} else if err != nil {
+		return err
+	}
+	err := c.configMapClient.ConfigMaps(operatorclient.TargetNamespace).Delete(ctx, internalCAConfigMapName, metav1.DeleteOptions{})
+	if apierrors.IsNotFound(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	recorder.Eventf("ConfigMapDeleted", "Deleted configmap/%s -n %s, the internal API server CA is no longer configured", internalCAConfigMapName, operatorclient.TargetNamespace)
+	return nil
+}
@@ -0,0 +1,197 @@
+package kubeconfigcontroller
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/client-go/kubernetes"
+	corev1client "k8s.io/client-go/kubernetes/typed/core/v1"
+	corev1listers "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/tools/clientcmd"
+
+	operatorv1 "github.com/openshift/api/operator/v1"
+	configv1informers "github.com/openshift/client-go/config/informers/externalversions/config/v1"
+	configv1listers "github.com/openshift/client-go/config/listers/config/v1"
+	"github.com/openshift/library-go/pkg/controller/factory"
+	"github.com/openshift/library-go/pkg/operator/events"
+	"github.com/openshift/library-go/pkg/operator/management"
+	"github.com/openshift/library-go/pkg/operator/resource/resourceread"
+	"github.com/openshift/library-go/pkg/operator/v1helpers"
+
+	"github.com/openshift/cluster-kube-controller-manager-operator/bindata"
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/operatorclient"
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/targetconfigcontroller"
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/tuning"
+)
+
+const (
+	kubeconfigConfigMapName = "controller-manager-kubeconfig"
+	kubeconfigKey           = "kubeconfig"
+)
+
+// KubeconfigController keeps the controller-manager-kubeconfig configmap pointed at the current
+// infrastructure.status.apiServerInternalURL. The configmap is revisioned, so any change to the
+// URL (for instance an api VIP migration) rolls out a new revision of the operand. When the tuning
+// configmap names an internal API server CA, the kubeconfig trusts it once the server is verified with it.
+type KubeconfigController struct {
+	operatorClient       v1helpers.StaticPodOperatorClient
+	configMapClient      corev1client.ConfigMapsGetter
+	configMapLister      corev1listers.ConfigMapLister
+	infrastructureLister configv1listers.InfrastructureLister
+	// verifyServer checks the server certificate against the CA bundle of the internal API server CA
+	verifyServer func(server string, caBundle []byte, recorder events.Recorder) error
+}
+
+func NewKubeconfigController(
+	operatorClient v1helpers.StaticPodOperatorClient,
+	kubeInformersForNamespaces v1helpers.KubeInformersForNamespaces,
+	kubeClient kubernetes.Interface,
+	infrastructureInformer configv1informers.InfrastructureInformer,
+	eventRecorder events.Recorder,
+) factory.Controller {
+	c := &KubeconfigController{
+		operatorClient:       operatorClient,
+		configMapClient:      kubeClient.CoreV1(),
+		configMapLister:      kubeInformersForNamespaces.ConfigMapLister(),
+		infrastructureLister: infrastructureInformer.Lister(),
+		verifyServer:         verifyServerCertificate,
+	}
+
+	return factory.New().WithInformers(
+		operatorClient.Informer(),
+		// the apiServerInternalURL lives in the infrastructure status
+		infrastructureInformer.Informer(),
+		// this is for watching our output in case someone changes it
+		kubeInformersForNamespaces.InformersFor(operatorclient.TargetNamespace).Core().V1().ConfigMaps().Informer(),
+		// the tuning configmap names the internal API server CA
+		kubeInformersForNamespaces.InformersFor(operatorclient.OperatorNamespace).Core().V1().ConfigMaps().Informer(),
+		kubeInformersForNamespaces.InformersFor(operatorclient.GlobalUserSpecifiedConfigNamespace).Core().V1().ConfigMaps().Informer(),
+		kubeInformersForNamespaces.InformersFor(operatorclient.GlobalMachineSpecifiedConfigNamespace).Core().V1().ConfigMaps().Informer(),
+	).ResyncEvery(time.Minute).WithSync(c.sync).ToController("KubeconfigController", eventRecorder)
+}
+
+func (c *KubeconfigController) sync(ctx context.Context, syncCtx factory.SyncContext) error {
+	operatorSpec, _, _, err := c.operatorClient.GetStaticPodOperatorState()
+	if err != nil {
+		return err
+	}
+	if !management.IsOperatorManaged(operatorSpec.ManagementState) {
+		return nil
+	}
+
+	syncErr := c.syncKubeconfig(ctx, syncCtx.Recorder())
+
+	condition := operatorv1.OperatorCondition{
+		Type:   "KubeconfigControllerDegraded",
+		Status: operatorv1.ConditionFalse,
+	}
+	if syncErr != nil {
+		condition.Status = operatorv1.ConditionTrue
+		condition.Reason = "SynchronizationError"
+		condition.Message = syncErr.Error()
+	}
+	if _, _, updateErr := v1helpers.UpdateStaticPodStatus(ctx, c.operatorClient, v1helpers.UpdateStaticPodConditionFn(condition)); updateErr != nil {
+		return updateErr
+	}
+	return syncErr
+}
+
+func (c *KubeconfigController) syncKubeconfig(ctx context.Context, recorder events.Recorder) error {
+	previousServer := ""
+	existing, err := c.configMapLister.ConfigMaps(operatorclient.TargetNamespace).Get(kubeconfigConfigMapName)
+	switch {
+	case apierrors.IsNotFound(err):
+	case err != nil:
+		return err
+	default:
+		// an unparseable kubeconfig is simply replaced
+		previousServer, _ = serverFromKubeconfig(existing)
+	}
+
+	tuningConfig, err := tuning.Get(c.configMapLister)
+	if err != nil {
+		return err
+	}
+
+	required, err := RenderControllerManagerKubeconfig(c.infrastructureLister)
+	if err != nil {
+		return err
+	}
+	requiredServer, err := serverFromKubeconfig(required)
+	if err != nil {
+		return err
+	}
+
+	// the CA bundle is applied before the kubeconfig trusting it, so that no revision has the kubeconfig without it
+	if len(tuningConfig.InternalAPIServerCA) > 0 {
+		caBundle, err := internalCABundle(c.configMapLister, tuningConfig.InternalAPIServerCA)
+		if err != nil {
+			return err
+		}
+		if err := c.verifyServer(requiredServer, []byte(caBundle.Data["ca-bundle.crt"]), recorder); err != nil {
+			return fmt.Errorf("not rolling out a kubeconfig trusting configmap/%s in %q: %v", tuningConfig.InternalAPIServerCA, operatorclient.GlobalUserSpecifiedConfigNamespace, err)
+		}
+		if _, _, err := targetconfigcontroller.ApplyConfigMap(ctx, c.configMapClient, recorder, caBundle); err != nil {
+			return err
+		}
+		if err := withCertificateAuthority(required, internalCAPath); err != nil {
+			return err
+		}
+	}
+
+	if len(previousServer) > 0 && previousServer != requiredServer {
+		recorder.Eventf("APIServerInternalURLChanged", "The internal API server URL changed from %q to %q, a new revision of %s/%s will be rolled out", previousServer, requiredServer, operatorclient.TargetNamespace, kubeconfigConfigMapName)
+	}
+
+	if _, _, err := targetconfigcontroller.ApplyConfigMap(ctx, c.configMapClient, recorder, required); err != nil {
+		return err
+	}
+	if len(tuningConfig.InternalAPIServerCA) == 0 {
+		return c.deleteInternalCABundle(ctx, recorder)
+	}
+	return nil
+}
+
+// RenderControllerManagerKubeconfig returns the controller-manager-kubeconfig configmap pointing at the apiServerInternalURL
+// of the cluster infrastructure.
+func RenderControllerManagerKubeconfig(infrastructureLister configv1listers.InfrastructureLister) (*corev1.ConfigMap, error) {
+	cmString := string(bindata.MustAsset("assets/kube-controller-manager/kubeconfig-cm.yaml"))
+
+	infrastructure, err := infrastructureLister.Get("cluster")
+	if err != nil {
+		return nil, err
+	}
+	apiServerInternalURL := infrastructure.Status.APIServerInternalURL
+	if len(apiServerInternalURL) == 0 {
+		return nil, fmt.Errorf("infrastucture/cluster: missing APIServerInternalURL")
+	}
+
+	for pattern, value := range map[string]string{
+		"$LB_INT_URL": apiServerInternalURL,
+	} {
+		cmString = strings.ReplaceAll(cmString, pattern, value)
+	}
+
+	return resourceread.ReadConfigMapV1OrDie([]byte(cmString)), nil
+}
+
+// serverFromKubeconfig returns the server of the current context of the kubeconfig stored in the configmap.
+func serverFromKubeconfig(cm *corev1.ConfigMap) (string, error) {
+	kubeconfig, err := clientcmd.Load([]byte(cm.Data[kubeconfigKey]))
+	if err != nil {
+		return "", fmt.Errorf("failed to parse %s/%s: %v", cm.Namespace, cm.Name, err)
+	}
+	currentContext, ok := kubeconfig.Contexts[kubeconfig.CurrentContext]
+	if !ok {
+		return "", fmt.Errorf("%s/%s: missing current context %q", cm.Namespace, cm.Name, kubeconfig.CurrentContext)
+	}
+	cluster, ok := kubeconfig.Clusters[currentContext.Cluster]
+	if !ok {
+		return "", fmt.Errorf("%s/%s: missing cluster %q", cm.Namespace, cm.Name, currentContext.Cluster)
+	}
+	return cluster.Server, nil
+}
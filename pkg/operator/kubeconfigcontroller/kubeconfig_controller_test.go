@@ -0,0 +1,279 @@
+package kubeconfigcontroller
+
+import (
+	"context"
+	"encoding/pem"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+	corev1listers "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/tools/clientcmd"
+
+	configv1 "github.com/openshift/api/config/v1"
+	configv1listers "github.com/openshift/client-go/config/listers/config/v1"
+	"github.com/openshift/library-go/pkg/crypto"
+	"github.com/openshift/library-go/pkg/operator/events"
+
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/operatorclient"
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/tuning"
+)
+
+func TestSyncKubeconfig(t *testing.T) {
+	newInfrastructureLister := func(t *testing.T, url string) configv1listers.InfrastructureLister {
+		indexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{})
+		if err := indexer.Add(&configv1.Infrastructure{
+			ObjectMeta: metav1.ObjectMeta{Name: "cluster"},
+			Status:     configv1.InfrastructureStatus{APIServerInternalURL: url},
+		}); err != nil {
+			t.Fatal(err)
+		}
+		return configv1listers.NewInfrastructureLister(indexer)
+	}
+
+	tests := []struct {
+		name          string
+		existingURL   string
+		url           string
+		expectedEvent bool
+		expectedError bool
+	}{
+		{
+			name: "initial render",
+			url:  "https://api-int.example.com:6443",
+		},
+		{
+			name:        "unchanged url",
+			existingURL: "https://api-int.example.com:6443",
+			url:         "https://api-int.example.com:6443",
+		},
+		{
+			name:          "api vip migration",
+			existingURL:   "https://api-int.example.com:6443",
+			url:           "https://api-int.new.example.com:6443",
+			expectedEvent: true,
+		},
+		{
+			name:          "missing url",
+			expectedError: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			indexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{})
+			kubeClient := fake.NewSimpleClientset()
+			if len(test.existingURL) > 0 {
+				existing, err := RenderControllerManagerKubeconfig(newInfrastructureLister(t, test.existingURL))
+				if err != nil {
+					t.Fatal(err)
+				}
+				if err := indexer.Add(existing); err != nil {
+					t.Fatal(err)
+				}
+				kubeClient = fake.NewSimpleClientset(existing)
+			}
+
+			recorder := events.NewInMemoryRecorder("kubeconfig-controller")
+			c := &KubeconfigController{
+				configMapClient:      kubeClient.CoreV1(),
+				configMapLister:      corev1listers.NewConfigMapLister(indexer),
+				infrastructureLister: newInfrastructureLister(t, test.url),
+			}
+
+			err := c.syncKubeconfig(context.Background(), recorder)
+			if test.expectedError != (err != nil) {
+				t.Fatalf("expected error %v, got %v", test.expectedError, err)
+			}
+			if test.expectedError {
+				return
+			}
+
+			actual, err := kubeClient.CoreV1().ConfigMaps(operatorclient.TargetNamespace).Get(context.Background(), kubeconfigConfigMapName, metav1.GetOptions{})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if server, err := serverFromKubeconfig(actual); err != nil || server != test.url {
+				t.Errorf("expected server %q, got %q (%v)", test.url, server, err)
+			}
+
+			changeEvent := false
+			for _, event := range recorder.Events() {
+				if event.Reason == "APIServerInternalURLChanged" {
+					changeEvent = true
+				}
+			}
+			if changeEvent != test.expectedEvent {
+				t.Errorf("expected APIServerInternalURLChanged event %v, got %v: %v", test.expectedEvent, changeEvent, recorder.Events())
+			}
+		})
+	}
+}
+
+func TestServerFromKubeconfig(t *testing.T) {
+	cm := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Namespace: operatorclient.TargetNamespace, Name: kubeconfigConfigMapName},
+		Data:       map[string]string{kubeconfigKey: "not a kubeconfig"},
+	}
+	if _, err := serverFromKubeconfig(cm); err == nil {
+		t.Errorf("expected an error for an invalid kubeconfig")
+	}
+}
+
+func TestSyncKubeconfigInternalAPIServerCA(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer server.Close()
+	serverCA := string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw}))
+	otherCA, err := crypto.MakeSelfSignedCAConfigForDuration("other", time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	otherCAPEM, _, err := otherCA.GetPEMBytes()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name              string
+		tuningConfig      string
+		customCA          string
+		existingCABundle  bool
+		expectedError     bool
+		expectedCABundle  bool
+		expectedAuthority string
+	}{
+		{
+			name:              "no internal API server CA",
+			expectedAuthority: "/etc/kubernetes/static-pod-resources/configmaps/serviceaccount-ca/ca-bundle.crt",
+		},
+		{
+			name:              "internal API server CA removed",
+			existingCABundle:  true,
+			expectedAuthority: "/etc/kubernetes/static-pod-resources/configmaps/serviceaccount-ca/ca-bundle.crt",
+		},
+		{
+			name:              "verified internal API server CA",
+			tuningConfig:      "internalAPIServerCA: lb-ca",
+			customCA:          serverCA,
+			expectedCABundle:  true,
+			expectedAuthority: internalCAPath,
+		},
+		{
+			name:          "internal API server CA not verifying the server",
+			tuningConfig:  "internalAPIServerCA: lb-ca",
+			customCA:      string(otherCAPEM),
+			expectedError: true,
+		},
+		{
+			name:          "missing internal API server CA",
+			tuningConfig:  "internalAPIServerCA: lb-ca",
+			expectedError: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			indexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{})
+			var objects []runtime.Object
+			if len(test.tuningConfig) > 0 {
+				objects = append(objects, &corev1.ConfigMap{
+					ObjectMeta: metav1.ObjectMeta{Namespace: operatorclient.OperatorNamespace, Name: tuning.ConfigMapName},
+					Data:       map[string]string{tuning.ConfigKey: test.tuningConfig},
+				})
+			}
+			if len(test.customCA) > 0 {
+				objects = append(objects, &corev1.ConfigMap{
+					ObjectMeta: metav1.ObjectMeta{Namespace: operatorclient.GlobalUserSpecifiedConfigNamespace, Name: "lb-ca"},
+					Data:       map[string]string{"ca-bundle.crt": test.customCA},
+				})
+			}
+			if test.existingCABundle {
+				objects = append(objects, &corev1.ConfigMap{
+					ObjectMeta: metav1.ObjectMeta{Namespace: operatorclient.TargetNamespace, Name: internalCAConfigMapName},
+					Data:       map[string]string{"ca-bundle.crt": serverCA},
+				})
+			}
+			for _, obj := range objects {
+				if err := indexer.Add(obj); err != nil {
+					t.Fatal(err)
+				}
+			}
+			kubeClient := fake.NewSimpleClientset(objects...)
+
+			infrastructureIndexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{})
+			if err := infrastructureIndexer.Add(&configv1.Infrastructure{
+				ObjectMeta: metav1.ObjectMeta{Name: "cluster"},
+				Status:     configv1.InfrastructureStatus{APIServerInternalURL: server.URL},
+			}); err != nil {
+				t.Fatal(err)
+			}
+			c := &KubeconfigController{
+				configMapClient:      kubeClient.CoreV1(),
+				configMapLister:      corev1listers.NewConfigMapLister(indexer),
+				infrastructureLister: configv1listers.NewInfrastructureLister(infrastructureIndexer),
+				verifyServer:         verifyServerCertificate,
+			}
+
+			err := c.syncKubeconfig(context.Background(), events.NewInMemoryRecorder("kubeconfig-controller"))
+			if test.expectedError != (err != nil) {
+				t.Fatalf("expected error %v, got %v", test.expectedError, err)
+			}
+			_, err = kubeClient.CoreV1().ConfigMaps(operatorclient.TargetNamespace).Get(context.Background(), internalCAConfigMapName, metav1.GetOptions{})
+			if test.expectedCABundle != (err == nil) {
+				t.Errorf("expected the CA bundle %v, got %v", test.expectedCABundle, err)
+			}
+			kubeconfigConfigMap, err := kubeClient.CoreV1().ConfigMaps(operatorclient.TargetNamespace).Get(context.Background(), kubeconfigConfigMapName, metav1.GetOptions{})
+			if test.expectedError {
+				if !apierrors.IsNotFound(err) {
+					t.Errorf("expected no kubeconfig to be rolled out, got %v", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			kubeconfig, err := clientcmd.Load([]byte(kubeconfigConfigMap.Data[kubeconfigKey]))
+			if err != nil {
+				t.Fatal(err)
+			}
+			for name, cluster := range kubeconfig.Clusters {
+				if cluster.CertificateAuthority != test.expectedAuthority {
+					t.Errorf("expected cluster %s to trust %s, got %s", name, test.expectedAuthority, cluster.CertificateAuthority)
+				}
+			}
+		})
+	}
+}
+
+func TestVerifyServerCertificateUnreachable(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	address := listener.Addr().String()
+	listener.Close()
+
+	caConfig, err := crypto.MakeSelfSignedCAConfigForDuration("ca", time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	caPEM, _, err := caConfig.GetPEMBytes()
+	if err != nil {
+		t.Fatal(err)
+	}
+	recorder := events.NewInMemoryRecorder("kubeconfig-controller")
+	if err := verifyServerCertificate("https://"+address, caPEM, recorder); err != nil {
+		t.Errorf("expected an unreachable server not to block the kubeconfig, got %v", err)
+	}
+	if len(recorder.Events()) != 1 || recorder.Events()[0].Reason != "InternalAPIServerUnverified" {
+		t.Errorf("expected an InternalAPIServerUnverified event, got %v", recorder.Events())
+	}
+}
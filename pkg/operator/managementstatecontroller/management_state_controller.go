@@ -0,0 +1,163 @@
+package managementstatecontroller
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	corev1client "k8s.io/client-go/kubernetes/typed/core/v1"
+	corev1listers "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/tools/cache"
+
+	operatorv1 "github.com/openshift/api/operator/v1"
+	"github.com/openshift/library-go/pkg/controller/factory"
+	"github.com/openshift/library-go/pkg/operator/events"
+	"github.com/openshift/library-go/pkg/operator/resource/resourceapply"
+	"github.com/openshift/library-go/pkg/operator/status"
+	"github.com/openshift/library-go/pkg/operator/v1helpers"
+
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/operatorclient"
+)
+
+const (
+	// CleanupOnRemovalAnnotation on the kubecontrollermanager/cluster resource makes the Removed management state also
+	// delete the configmaps the operator renders into the target namespace.
+	CleanupOnRemovalAnnotation = "kubecontrollermanager.operator.openshift.io/cleanup-on-removal"
+
+	removedCondition = "ManagementStateRemoved"
+)
+
+// ManagementStateController reports the Removed and Unmanaged management states. The other controllers only stop
+// reconciling when the operator is Removed; this controller tells the admin so and, when asked to, cleans up after
+// them. The static pods keep running, removing them would leave the cluster without a controller manager. While the
+// operator is Unmanaged it keeps the versions and the node revisions current for monitoring.
+type ManagementStateController struct {
+	operatorClient  v1helpers.StaticPodOperatorClient
+	operatorLister  cache.GenericLister
+	configMapClient corev1client.ConfigMapsGetter
+	configMapLister corev1listers.ConfigMapLister
+	// renderedConfigMaps are the configmaps the operator renders into the target namespace from its own inputs. They
+	// are recreated as soon as the operator is Managed again. The secrets hold signing keys and certificates that
+	// cannot be recreated without disrupting the cluster, so they are never deleted.
+	renderedConfigMaps []string
+	podLister          corev1listers.PodLister
+	versionRecorder    status.VersionGetter
+}
+
+func NewManagementStateController(
+	operatorClient v1helpers.StaticPodOperatorClient,
+	operatorLister cache.GenericLister,
+	kubeInformersForNamespaces v1helpers.KubeInformersForNamespaces,
+	kubeClient kubernetes.Interface,
+	renderedConfigMaps []string,
+	versionRecorder status.VersionGetter,
+	eventRecorder events.Recorder,
+) factory.Controller {
+	c := &ManagementStateController{
+		operatorClient:     operatorClient,
+		operatorLister:     operatorLister,
+		configMapClient:    kubeClient.CoreV1(),
+		configMapLister:    kubeInformersForNamespaces.ConfigMapLister(),
+		renderedConfigMaps: renderedConfigMaps,
+		podLister:          kubeInformersForNamespaces.InformersFor(operatorclient.TargetNamespace).Core().V1().Pods().Lister(),
+		versionRecorder:    versionRecorder,
+	}
+
+	return factory.New().WithInformers(
+		operatorClient.Informer(),
+		kubeInformersForNamespaces.InformersFor(operatorclient.TargetNamespace).Core().V1().ConfigMaps().Informer(),
+		kubeInformersForNamespaces.InformersFor(operatorclient.TargetNamespace).Core().V1().Pods().Informer(),
+	).ResyncEvery(time.Minute).WithSync(c.sync).ToController("ManagementStateController", eventRecorder)
+}
+
+func (c *ManagementStateController) sync(ctx context.Context, syncCtx factory.SyncContext) error {
+	operatorSpec, _, _, err := c.operatorClient.GetStaticPodOperatorState()
+	if err != nil {
+		return err
+	}
+
+	if operatorSpec.ManagementState == operatorv1.Unmanaged {
+		return c.syncUnmanaged(ctx, syncCtx)
+	}
+	if operatorSpec.ManagementState != operatorv1.Removed {
+		return c.updateCondition(ctx, operatorv1.OperatorCondition{
+			Type:   removedCondition,
+			Status: operatorv1.ConditionFalse,
+		})
+	}
+
+	cleanup, err := c.isCleanupRequested()
+	if err != nil {
+		return err
+	}
+	if !cleanup {
+		return c.updateCondition(ctx, operatorv1.OperatorCondition{
+			Type:    removedCondition,
+			Status:  operatorv1.ConditionTrue,
+			Reason:  "Removed",
+			Message: fmt.Sprintf("The operator stopped reconciling the kube-controller-manager, the running static pods are left in place. Set the %s annotation to also delete the configmaps rendered in %s.", CleanupOnRemovalAnnotation, operatorclient.TargetNamespace),
+		})
+	}
+
+	cleanupErr := c.deleteRenderedConfigMaps(ctx, syncCtx.Recorder())
+	condition := operatorv1.OperatorCondition{
+		Type:    removedCondition,
+		Status:  operatorv1.ConditionTrue,
+		Reason:  "Removed",
+		Message: fmt.Sprintf("The operator stopped reconciling the kube-controller-manager and deleted the configmaps rendered in %s, the running static pods are left in place.", operatorclient.TargetNamespace),
+	}
+	if cleanupErr != nil {
+		condition.Reason = "CleanupFailed"
+		condition.Message = cleanupErr.Error()
+	}
+	if err := c.updateCondition(ctx, condition); err != nil {
+		return err
+	}
+	return cleanupErr
+}
+
+// isCleanupRequested returns whether the operator resource asks for the rendered configmaps to be deleted.
+func (c *ManagementStateController) isCleanupRequested() (bool, error) {
+	uncastOperator, err := c.operatorLister.Get("cluster")
+	if err != nil {
+		return false, err
+	}
+	operator, err := meta.Accessor(uncastOperator)
+	if err != nil {
+		return false, err
+	}
+	return operator.GetAnnotations()[CleanupOnRemovalAnnotation] == "true", nil
+}
+
+// deleteRenderedConfigMaps deletes the renderedConfigMaps that still exist.
+func (c *ManagementStateController) deleteRenderedConfigMaps(ctx context.Context, recorder events.Recorder) error {
+	var errs []error
+	for _, name := range c.renderedConfigMaps {
+		if _, err := c.configMapLister.ConfigMaps(operatorclient.TargetNamespace).Get(name); apierrors.IsNotFound(err) {
+			continue
+		} else if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		if _, _, err := resourceapply.DeleteConfigMap(ctx, c.configMapClient, recorder, &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Namespace: operatorclient.TargetNamespace, Name: name},
+		}); err != nil {
+			errs = append(errs, fmt.Errorf("%q: %v", "configmap/"+name, err))
+		}
+	}
+	return v1helpers.NewMultiLineAggregate(errs)
+}
+
+// updateCondition sets the Removed condition of a management state other than Unmanaged.
+func (c *ManagementStateController) updateCondition(ctx context.Context, condition operatorv1.OperatorCondition) error {
+	_, _, err := v1helpers.UpdateStaticPodStatus(ctx, c.operatorClient,
+		v1helpers.UpdateStaticPodConditionFn(condition),
+		v1helpers.UpdateStaticPodConditionFn(operatorv1.OperatorCondition{Type: unmanagedCondition, Status: operatorv1.ConditionFalse}),
+	)
+	return err
+}
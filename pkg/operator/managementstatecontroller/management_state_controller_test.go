@@ -0,0 +1,193 @@
+package managementstatecontroller
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+	corev1listers "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/tools/cache"
+
+	operatorv1 "github.com/openshift/api/operator/v1"
+	"github.com/openshift/library-go/pkg/controller/factory"
+	"github.com/openshift/library-go/pkg/operator/events"
+	"github.com/openshift/library-go/pkg/operator/status"
+	"github.com/openshift/library-go/pkg/operator/v1helpers"
+
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/operatorclient"
+)
+
+type fakeOperatorLister struct {
+	operator *unstructured.Unstructured
+}
+
+func (l fakeOperatorLister) List(_ labels.Selector) ([]runtime.Object, error) {
+	return []runtime.Object{l.operator}, nil
+}
+
+func (l fakeOperatorLister) Get(_ string) (runtime.Object, error) {
+	return l.operator, nil
+}
+
+func (l fakeOperatorLister) ByNamespace(_ string) cache.GenericNamespaceLister {
+	return nil
+}
+
+func TestSync(t *testing.T) {
+	tests := []struct {
+		name              string
+		managementState   operatorv1.ManagementState
+		annotations       map[string]string
+		expectedStatus    operatorv1.ConditionStatus
+		expectedConfigMap bool
+	}{
+		{
+			name:              "managed",
+			managementState:   operatorv1.Managed,
+			expectedStatus:    operatorv1.ConditionFalse,
+			expectedConfigMap: true,
+		},
+		{
+			name:              "removed",
+			managementState:   operatorv1.Removed,
+			expectedStatus:    operatorv1.ConditionTrue,
+			expectedConfigMap: true,
+		},
+		{
+			name:            "removed with cleanup",
+			managementState: operatorv1.Removed,
+			annotations:     map[string]string{CleanupOnRemovalAnnotation: "true"},
+			expectedStatus:  operatorv1.ConditionTrue,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			configMap := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Namespace: operatorclient.TargetNamespace, Name: "kube-controller-manager-pod"}}
+			indexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{})
+			if err := indexer.Add(configMap); err != nil {
+				t.Fatal(err)
+			}
+			kubeClient := fake.NewSimpleClientset(configMap)
+
+			operator := &unstructured.Unstructured{}
+			operator.SetName("cluster")
+			operator.SetAnnotations(test.annotations)
+
+			operatorClient := v1helpers.NewFakeStaticPodOperatorClient(
+				&operatorv1.StaticPodOperatorSpec{OperatorSpec: operatorv1.OperatorSpec{ManagementState: test.managementState}},
+				&operatorv1.StaticPodOperatorStatus{},
+				nil,
+				nil,
+			)
+			c := &ManagementStateController{
+				operatorClient:     operatorClient,
+				operatorLister:     fakeOperatorLister{operator: operator},
+				configMapClient:    kubeClient.CoreV1(),
+				configMapLister:    corev1listers.NewConfigMapLister(indexer),
+				renderedConfigMaps: []string{"kube-controller-manager-pod"},
+			}
+
+			if err := c.sync(context.TODO(), factory.NewSyncContext("ManagementStateController", events.NewInMemoryRecorder("management-state"))); err != nil {
+				t.Fatal(err)
+			}
+
+			_, status, _, err := operatorClient.GetStaticPodOperatorState()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if condition := v1helpers.FindOperatorCondition(status.Conditions, removedCondition); condition == nil || condition.Status != test.expectedStatus {
+				t.Errorf("expected %s=%s, got %#v", removedCondition, test.expectedStatus, condition)
+			}
+
+			_, err = kubeClient.CoreV1().ConfigMaps(operatorclient.TargetNamespace).Get(context.TODO(), configMap.Name, metav1.GetOptions{})
+			if exists := !apierrors.IsNotFound(err); exists != test.expectedConfigMap {
+				t.Errorf("expected configmap to exist %v, got %v", test.expectedConfigMap, exists)
+			}
+		})
+	}
+}
+
+func TestSyncUnmanaged(t *testing.T) {
+	t.Setenv("IMAGE", "quay.io/openshift/kube-controller-manager:v2")
+	t.Setenv("OPERAND_IMAGE_VERSION", "1.29.1")
+	t.Setenv("OPERATOR_IMAGE_VERSION", "4.16.1")
+
+	pod := func(nodeName, revision string, ready corev1.ConditionStatus) *corev1.Pod {
+		return &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Namespace: operatorclient.TargetNamespace, Name: "kube-controller-manager-" + nodeName, Labels: map[string]string{"revision": revision}},
+			Spec:       corev1.PodSpec{Containers: []corev1.Container{{Name: "kube-controller-manager", Image: "quay.io/openshift/kube-controller-manager:v2"}}},
+			Status: corev1.PodStatus{
+				Phase:      corev1.PodRunning,
+				Conditions: []corev1.PodCondition{{Type: corev1.PodReady, Status: ready}},
+			},
+		}
+	}
+	podIndexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc})
+	for _, pod := range []*corev1.Pod{pod("master-0", "4", corev1.ConditionTrue), pod("master-1", "5", corev1.ConditionFalse)} {
+		if err := podIndexer.Add(pod); err != nil {
+			t.Fatal(err)
+		}
+	}
+	configMap := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Namespace: operatorclient.TargetNamespace, Name: "kube-controller-manager-pod"}}
+	kubeClient := fake.NewSimpleClientset(configMap)
+
+	operator := &unstructured.Unstructured{}
+	operator.SetName("cluster")
+	operator.SetAnnotations(map[string]string{CleanupOnRemovalAnnotation: "true"})
+	operatorClient := v1helpers.NewFakeStaticPodOperatorClient(
+		&operatorv1.StaticPodOperatorSpec{OperatorSpec: operatorv1.OperatorSpec{ManagementState: operatorv1.Unmanaged}},
+		&operatorv1.StaticPodOperatorStatus{NodeStatuses: []operatorv1.NodeStatus{
+			{NodeName: "master-0", CurrentRevision: 3},
+			{NodeName: "master-1", CurrentRevision: 4},
+			{NodeName: "master-2", CurrentRevision: 4},
+		}},
+		nil,
+		nil,
+	)
+	versionRecorder := status.NewVersionGetter()
+	c := &ManagementStateController{
+		operatorClient:  operatorClient,
+		operatorLister:  fakeOperatorLister{operator: operator},
+		configMapClient: kubeClient.CoreV1(),
+		configMapLister: corev1listers.NewConfigMapLister(cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{})),
+		podLister:       corev1listers.NewPodLister(podIndexer),
+		versionRecorder: versionRecorder,
+	}
+	recorder := events.NewInMemoryRecorder("management-state")
+	if err := c.sync(context.TODO(), factory.NewSyncContext("ManagementStateController", recorder)); err != nil {
+		t.Fatal(err)
+	}
+
+	_, operatorStatus, _, err := operatorClient.GetStaticPodOperatorState()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var revisions []int32
+	for _, nodeStatus := range operatorStatus.NodeStatuses {
+		revisions = append(revisions, nodeStatus.CurrentRevision)
+	}
+	if expected := []int32{4, 4, 4}; !reflect.DeepEqual(revisions, expected) {
+		t.Errorf("expected the current revisions %v, got %v", expected, revisions)
+	}
+	condition := v1helpers.FindOperatorCondition(operatorStatus.Conditions, unmanagedCondition)
+	if condition == nil || condition.Status != operatorv1.ConditionTrue || !strings.HasPrefix(condition.Message, "UnmanagedSince "+condition.LastTransitionTime.UTC().Format(time.RFC3339)) {
+		t.Errorf("unexpected %s condition %#v", unmanagedCondition, condition)
+	}
+	if expected := map[string]string{"kube-controller-manager": "1.29.1", "operator": "4.16.1"}; !reflect.DeepEqual(versionRecorder.GetVersions(), expected) {
+		t.Errorf("expected the versions %v, got %v", expected, versionRecorder.GetVersions())
+	}
+	// the cleanup annotation only applies to Removed
+	if _, err := kubeClient.CoreV1().ConfigMaps(operatorclient.TargetNamespace).Get(context.TODO(), configMap.Name, metav1.GetOptions{}); err != nil {
+		t.Errorf("expected the rendered configmap to be kept, got %v", err)
+	}
+}
@@ -0,0 +1,125 @@
+package managementstatecontroller
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/util/sets"
+
+	operatorv1 "github.com/openshift/api/operator/v1"
+	"github.com/openshift/library-go/pkg/controller/factory"
+	"github.com/openshift/library-go/pkg/operator/staticpod/startupmonitor/annotations"
+	"github.com/openshift/library-go/pkg/operator/status"
+	"github.com/openshift/library-go/pkg/operator/v1helpers"
+
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/operatorclient"
+)
+
+const (
+	// unmanagedCondition reports the Unmanaged management state. The time the operator became Unmanaged is its
+	// lastTransitionTime and is repeated in its message as UnmanagedSince.
+	unmanagedCondition = "ManagementStateUnmanaged"
+
+	operandName   = "kube-controller-manager"
+	staticPodName = "kube-controller-manager"
+	revisionLabel = "revision"
+)
+
+// syncUnmanaged keeps the status monitoring relies on current while the operator is Unmanaged. The installer and
+// static pod state controllers stop with the other controllers, so the node revisions and the versions are read from
+// the running static pods here. No resource is changed.
+func (c *ManagementStateController) syncUnmanaged(ctx context.Context, syncCtx factory.SyncContext) error {
+	_, operatorStatus, _, err := c.operatorClient.GetStaticPodOperatorState()
+	if err != nil {
+		return err
+	}
+
+	revisions := map[string]int32{}
+	images := sets.New[string]()
+	for _, nodeStatus := range operatorStatus.NodeStatuses {
+		pod, err := c.podLister.Pods(operatorclient.TargetNamespace).Get(mirrorPodName(nodeStatus.NodeName))
+		if apierrors.IsNotFound(err) {
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		if revision, ok := readyRevision(pod); ok {
+			revisions[nodeStatus.NodeName] = revision
+		}
+		for _, container := range pod.Spec.Containers {
+			if container.Name == operandName {
+				images.Insert(container.Image)
+			}
+		}
+	}
+	// the versions are only reported once all the pods run the operand image of this operator
+	if images.Len() == 1 && images.Has(status.ImageForOperandFromEnv()) {
+		c.versionRecorder.SetVersion(operandName, status.VersionForOperandFromEnv())
+		c.versionRecorder.SetVersion("operator", status.VersionForOperatorFromEnv())
+	}
+
+	var changed []string
+	_, _, err = v1helpers.UpdateStaticPodStatus(ctx, c.operatorClient, func(newStatus *operatorv1.StaticPodOperatorStatus) error {
+		changed = nil
+		for i := range newStatus.NodeStatuses {
+			nodeStatus := &newStatus.NodeStatuses[i]
+			revision, ok := revisions[nodeStatus.NodeName]
+			if !ok || revision == nodeStatus.CurrentRevision {
+				continue
+			}
+			changed = append(changed, fmt.Sprintf("node %q from revision %d to %d", nodeStatus.NodeName, nodeStatus.CurrentRevision, revision))
+			nodeStatus.CurrentRevision = revision
+		}
+
+		v1helpers.SetOperatorCondition(&newStatus.Conditions, operatorv1.OperatorCondition{
+			Type:   unmanagedCondition,
+			Status: operatorv1.ConditionTrue,
+			Reason: "Unmanaged",
+		})
+		condition := v1helpers.FindOperatorCondition(newStatus.Conditions, unmanagedCondition)
+		condition.Message = fmt.Sprintf("UnmanagedSince %s: the operator does not change any resource. The versions, the node revisions and the certificate expiry are still reported.", condition.LastTransitionTime.UTC().Format(time.RFC3339))
+		v1helpers.SetOperatorCondition(&newStatus.Conditions, operatorv1.OperatorCondition{
+			Type:   removedCondition,
+			Status: operatorv1.ConditionFalse,
+		})
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	for _, change := range changed {
+		syncCtx.Recorder().Eventf("NodeCurrentRevisionChanged", "Updated %s while the operator is Unmanaged", change)
+	}
+	return nil
+}
+
+// readyRevision returns the revision of a ready static pod. A fallback pod runs an older revision than it is
+// labeled with and is skipped, like a pod that is not ready yet.
+func readyRevision(pod *corev1.Pod) (int32, bool) {
+	if _, ok := pod.Annotations[annotations.FallbackForRevision]; ok {
+		return 0, false
+	}
+	if pod.Status.Phase != corev1.PodRunning {
+		return 0, false
+	}
+	ready := false
+	for _, condition := range pod.Status.Conditions {
+		if condition.Type == corev1.PodReady {
+			ready = condition.Status == corev1.ConditionTrue
+		}
+	}
+	revision, err := strconv.ParseInt(pod.Labels[revisionLabel], 10, 32)
+	if !ready || err != nil {
+		return 0, false
+	}
+	return int32(revision), true
+}
+
+func mirrorPodName(nodeName string) string {
+	return staticPodName + "-" + nodeName
+}
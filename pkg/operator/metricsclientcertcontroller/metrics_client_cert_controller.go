@@ -0,0 +1,245 @@
+package metricsclientcertcontroller
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"fmt"
+	"time"
+
+	certificatesv1 "k8s.io/api/certificates/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/kubernetes"
+	certificatesv1client "k8s.io/client-go/kubernetes/typed/certificates/v1"
+	corev1client "k8s.io/client-go/kubernetes/typed/core/v1"
+	corev1listers "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/util/cert"
+	"k8s.io/client-go/util/keyutil"
+	"k8s.io/klog/v2"
+	"k8s.io/utils/ptr"
+
+	operatorv1 "github.com/openshift/api/operator/v1"
+	"github.com/openshift/library-go/pkg/controller/factory"
+	"github.com/openshift/library-go/pkg/operator/events"
+	"github.com/openshift/library-go/pkg/operator/v1helpers"
+
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/operatorclient"
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/targetconfigcontroller"
+)
+
+const (
+	// SecretName is the client certificate prometheus scrapes the kube-controller-manager with, see the
+	// kube-controller-manager service monitor.
+	SecretName = "metrics-client-cert"
+
+	// ClientUser is the user of the client certificate, the metrics reader cluster role is bound to it.
+	ClientUser = "system:openshift:kube-controller-manager-metrics-client"
+
+	metricsClientCertCondition = "MetricsClientCertControllerDegraded"
+	metricsClientCertLabel     = "kubecontrollermanager.operator.openshift.io/metrics-client-cert"
+
+	// certificateLifetime is requested from the signer, which caps it at the cluster-signing-duration of 30 days.
+	certificateLifetime = 30 * 24 * time.Hour
+	// refreshFraction of the lifetime of the certificate passes before it is rotated, leaving prometheus days to
+	// reload the new one.
+	refreshFraction = 0.8
+
+	// SigningTimeout is how long the controller waits for the kube-controller-manager to sign its CSR.
+	SigningTimeout = 2 * time.Minute
+)
+
+// MetricsClientCertController keeps a client certificate of the kube-apiserver-client signer in SecretName and rotates
+// it before it expires. The kube-controller-manager authenticates it against its client CA without the token review
+// the delegated authentication of a bearer token needs, and authorizes ClientUser through the metrics reader
+// cluster role.
+type MetricsClientCertController struct {
+	operatorClient v1helpers.StaticPodOperatorClient
+	csrClient      certificatesv1client.CertificateSigningRequestInterface
+	secretClient   corev1client.SecretsGetter
+	secretLister   corev1listers.SecretLister
+	timeout        time.Duration
+	now            func() time.Time
+}
+
+func NewMetricsClientCertController(
+	operatorClient v1helpers.StaticPodOperatorClient,
+	kubeInformersForNamespaces v1helpers.KubeInformersForNamespaces,
+	kubeClient kubernetes.Interface,
+	eventRecorder events.Recorder,
+) factory.Controller {
+	c := &MetricsClientCertController{
+		operatorClient: operatorClient,
+		csrClient:      kubeClient.CertificatesV1().CertificateSigningRequests(),
+		secretClient:   kubeClient.CoreV1(),
+		secretLister:   kubeInformersForNamespaces.SecretLister(),
+		timeout:        SigningTimeout,
+		now:            time.Now,
+	}
+
+	return factory.New().WithFilteredEventsInformers(
+		factory.NamesFilter(SecretName),
+		kubeInformersForNamespaces.InformersFor(operatorclient.TargetNamespace).Core().V1().Secrets().Informer(),
+	).ResyncEvery(time.Hour).WithSync(c.sync).ToController("MetricsClientCertController", eventRecorder)
+}
+
+func (c *MetricsClientCertController) sync(ctx context.Context, syncCtx factory.SyncContext) error {
+	condition := operatorv1.OperatorCondition{
+		Type:   metricsClientCertCondition,
+		Status: operatorv1.ConditionFalse,
+	}
+	syncErr := c.ensureClientCert(ctx, syncCtx.Recorder())
+	if syncErr != nil {
+		condition.Status = operatorv1.ConditionTrue
+		condition.Reason = "RotationFailed"
+		condition.Message = syncErr.Error()
+	}
+	if _, _, err := v1helpers.UpdateStaticPodStatus(ctx, c.operatorClient, v1helpers.UpdateStaticPodConditionFn(condition)); err != nil {
+		return err
+	}
+	return syncErr
+}
+
+func (c *MetricsClientCertController) ensureClientCert(ctx context.Context, recorder events.Recorder) error {
+	secret, err := c.secretLister.Secrets(operatorclient.TargetNamespace).Get(SecretName)
+	if err != nil && !apierrors.IsNotFound(err) {
+		return err
+	}
+	reason := "the certificate is missing"
+	if secret != nil {
+		reason = rotationReason(secret, c.now())
+	}
+	if len(reason) == 0 {
+		return nil
+	}
+	klog.V(2).Infof("Requesting a new metrics client certificate: %s", reason)
+
+	certPEM, keyPEM, err := c.requestCertificate(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to request a metrics client certificate: %v", err)
+	}
+	_, _, err = targetconfigcontroller.ApplySecret(ctx, c.secretClient, recorder, &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Namespace: operatorclient.TargetNamespace, Name: SecretName},
+		Type:       corev1.SecretTypeTLS,
+		Data: map[string][]byte{
+			corev1.TLSCertKey:       certPEM,
+			corev1.TLSPrivateKeyKey: keyPEM,
+		},
+	})
+	if err != nil {
+		return err
+	}
+	recorder.Eventf("MetricsClientCertRotated", "Rotated the metrics client certificate in secret/%s -n %s: %s", SecretName, operatorclient.TargetNamespace, reason)
+	return nil
+}
+
+// rotationReason tells why the certificate of the secret needs to be replaced, or returns an empty string when it is
+// still good.
+func rotationReason(secret *corev1.Secret, now time.Time) string {
+	if len(secret.Data[corev1.TLSPrivateKeyKey]) == 0 {
+		return "the key is missing"
+	}
+	certs, err := cert.ParseCertsPEM(secret.Data[corev1.TLSCertKey])
+	if err != nil {
+		return fmt.Sprintf("the certificate is malformed: %v", err)
+	}
+	if certs[0].Subject.CommonName != ClientUser {
+		return fmt.Sprintf("the certificate is for %q", certs[0].Subject.CommonName)
+	}
+	lifetime := certs[0].NotAfter.Sub(certs[0].NotBefore)
+	refresh := certs[0].NotBefore.Add(time.Duration(float64(lifetime) * refreshFraction))
+	if now.After(refresh) {
+		return fmt.Sprintf("the certificate expires at %s", certs[0].NotAfter.UTC().Format(time.RFC3339))
+	}
+	return ""
+}
+
+// requestCertificate creates, approves and waits for a kube-apiserver-client CSR for ClientUser. The CSR is deleted
+// in any case.
+func (c *MetricsClientCertController) requestCertificate(ctx context.Context) ([]byte, []byte, error) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return nil, nil, err
+	}
+	request, err := x509.CreateCertificateRequest(rand.Reader, &x509.CertificateRequest{
+		Subject: pkix.Name{CommonName: ClientUser},
+	}, key)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	csr, err := c.csrClient.Create(ctx, &certificatesv1.CertificateSigningRequest{
+		ObjectMeta: metav1.ObjectMeta{
+			GenerateName: "kube-controller-manager-metrics-client-",
+			Labels:       map[string]string{metricsClientCertLabel: "true"},
+		},
+		Spec: certificatesv1.CertificateSigningRequestSpec{
+			Request:           pem.EncodeToMemory(&pem.Block{Type: cert.CertificateRequestBlockType, Bytes: request}),
+			SignerName:        certificatesv1.KubeAPIServerClientSignerName,
+			ExpirationSeconds: ptr.To(int32(certificateLifetime / time.Second)),
+			Usages: []certificatesv1.KeyUsage{
+				certificatesv1.UsageDigitalSignature,
+				certificatesv1.UsageClientAuth,
+			},
+		},
+	}, metav1.CreateOptions{})
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to create the CSR: %v", err)
+	}
+	defer func() {
+		// the context may be done already, the CSR must not be left behind
+		deleteCtx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		defer cancel()
+		if err := c.csrClient.Delete(deleteCtx, csr.Name, metav1.DeleteOptions{}); err != nil && !apierrors.IsNotFound(err) {
+			utilruntime.HandleError(fmt.Errorf("failed to delete the metrics client CSR %s: %v", csr.Name, err))
+		}
+	}()
+
+	csr.Status.Conditions = append(csr.Status.Conditions, certificatesv1.CertificateSigningRequestCondition{
+		Type:    certificatesv1.CertificateApproved,
+		Status:  corev1.ConditionTrue,
+		Reason:  "KubeControllerManagerOperatorMetricsClient",
+		Message: "Approved by the kube-controller-manager operator for scraping the kube-controller-manager metrics",
+	})
+	if _, err := c.csrClient.UpdateApproval(ctx, csr.Name, csr, metav1.UpdateOptions{}); err != nil {
+		return nil, nil, fmt.Errorf("failed to approve the CSR: %v", err)
+	}
+
+	var certificate []byte
+	err = wait.PollUntilContextTimeout(ctx, time.Second, c.timeout, true, func(ctx context.Context) (bool, error) {
+		current, err := c.csrClient.Get(ctx, csr.Name, metav1.GetOptions{})
+		if err != nil {
+			return false, err
+		}
+		for _, condition := range current.Status.Conditions {
+			if condition.Type == certificatesv1.CertificateDenied || condition.Type == certificatesv1.CertificateFailed {
+				return false, fmt.Errorf("the CSR is %s: %s", condition.Type, condition.Message)
+			}
+		}
+		certificate = current.Status.Certificate
+		return len(certificate) > 0, nil
+	})
+	if err != nil {
+		return nil, nil, fmt.Errorf("the CSR was not signed within %s: %v", c.timeout, err)
+	}
+
+	certs, err := cert.ParseCertsPEM(certificate)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to parse the certificate: %v", err)
+	}
+	if !key.PublicKey.Equal(certs[0].PublicKey) {
+		return nil, nil, fmt.Errorf("the certificate is not for the key of the CSR")
+	}
+	keyPEM, err := keyutil.MarshalPrivateKeyToPEM(key)
+	if err != nil {
+		return nil, nil, err
+	}
+	return certificate, keyPEM, nil
+}
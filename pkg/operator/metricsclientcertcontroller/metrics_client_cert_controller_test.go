@@ -0,0 +1,207 @@
+package metricsclientcertcontroller
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"testing"
+	"time"
+
+	certificatesv1 "k8s.io/api/certificates/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+	corev1listers "k8s.io/client-go/listers/core/v1"
+	clienttesting "k8s.io/client-go/testing"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/util/cert"
+	"k8s.io/client-go/util/keyutil"
+
+	operatorv1 "github.com/openshift/api/operator/v1"
+	"github.com/openshift/library-go/pkg/controller/factory"
+	"github.com/openshift/library-go/pkg/crypto"
+	"github.com/openshift/library-go/pkg/operator/events"
+	"github.com/openshift/library-go/pkg/operator/v1helpers"
+
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/operatorclient"
+)
+
+func TestSync(t *testing.T) {
+	signerConfig, err := crypto.MakeSelfSignedCAConfigForDuration("csr-signer", time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	now := time.Now()
+
+	tests := []struct {
+		name           string
+		existing       *corev1.Secret
+		deny           bool
+		expectRotation bool
+		expectedStatus operatorv1.ConditionStatus
+	}{
+		{
+			name:           "missing",
+			expectRotation: true,
+			expectedStatus: operatorv1.ConditionFalse,
+		},
+		{
+			name:           "valid",
+			existing:       newSecret(t, signerConfig, ClientUser, now.Add(-time.Hour), now.Add(10*time.Hour)),
+			expectedStatus: operatorv1.ConditionFalse,
+		},
+		{
+			name:           "expiring",
+			existing:       newSecret(t, signerConfig, ClientUser, now.Add(-9*time.Hour), now.Add(time.Hour)),
+			expectRotation: true,
+			expectedStatus: operatorv1.ConditionFalse,
+		},
+		{
+			name:           "other user",
+			existing:       newSecret(t, signerConfig, "other", now.Add(-time.Hour), now.Add(10*time.Hour)),
+			expectRotation: true,
+			expectedStatus: operatorv1.ConditionFalse,
+		},
+		{
+			name:           "denied",
+			deny:           true,
+			expectedStatus: operatorv1.ConditionTrue,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			kubeClient := fake.NewSimpleClientset()
+			kubeClient.PrependReactor("create", "certificatesigningrequests", func(action clienttesting.Action) (bool, runtime.Object, error) {
+				csr := action.(clienttesting.CreateAction).GetObject().(*certificatesv1.CertificateSigningRequest)
+				csr.Name = csr.GenerateName + "test"
+				return false, nil, nil
+			})
+			kubeClient.PrependReactor("update", "certificatesigningrequests", func(action clienttesting.Action) (bool, runtime.Object, error) {
+				if action.GetSubresource() != "approval" {
+					return false, nil, nil
+				}
+				csr := action.(clienttesting.UpdateAction).GetObject().(*certificatesv1.CertificateSigningRequest)
+				if csr.Spec.SignerName != certificatesv1.KubeAPIServerClientSignerName {
+					t.Errorf("unexpected signer %q", csr.Spec.SignerName)
+				}
+				if test.deny {
+					csr.Status.Conditions = []certificatesv1.CertificateSigningRequestCondition{{Type: certificatesv1.CertificateDenied, Status: corev1.ConditionTrue, Message: "denied by test"}}
+					return false, nil, nil
+				}
+				csr.Status.Certificate = sign(t, signerConfig, csr.Spec.Request)
+				return false, nil, nil
+			})
+			secretIndexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc})
+			if test.existing != nil {
+				if err := secretIndexer.Add(test.existing); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			operatorClient := v1helpers.NewFakeStaticPodOperatorClient(&operatorv1.StaticPodOperatorSpec{}, &operatorv1.StaticPodOperatorStatus{}, nil, nil)
+			c := &MetricsClientCertController{
+				operatorClient: operatorClient,
+				csrClient:      kubeClient.CertificatesV1().CertificateSigningRequests(),
+				secretClient:   kubeClient.CoreV1(),
+				secretLister:   corev1listers.NewSecretLister(secretIndexer),
+				timeout:        5 * time.Second,
+				now:            func() time.Time { return now },
+			}
+			err := c.sync(context.TODO(), factory.NewSyncContext("test", events.NewInMemoryRecorder("test")))
+			if (test.expectedStatus == operatorv1.ConditionTrue) != (err != nil) {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			_, status, _, err := operatorClient.GetStaticPodOperatorState()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if condition := v1helpers.FindOperatorCondition(status.Conditions, metricsClientCertCondition); condition == nil || condition.Status != test.expectedStatus {
+				t.Errorf("expected %s=%s, got %v", metricsClientCertCondition, test.expectedStatus, condition)
+			}
+
+			secret, err := kubeClient.CoreV1().Secrets(operatorclient.TargetNamespace).Get(context.TODO(), SecretName, metav1.GetOptions{})
+			if !test.expectRotation {
+				if err == nil {
+					t.Errorf("expected no new certificate")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if reason := rotationReason(secret, now); len(reason) > 0 {
+				t.Errorf("expected a good certificate, got: %s", reason)
+			}
+			csrs, err := kubeClient.CertificatesV1().CertificateSigningRequests().List(context.TODO(), metav1.ListOptions{})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(csrs.Items) != 0 {
+				t.Errorf("expected the CSR to be deleted, got %d CSRs", len(csrs.Items))
+			}
+		})
+	}
+}
+
+func newSecret(t *testing.T, ca *crypto.TLSCertificateConfig, user string, notBefore, notAfter time.Time) *corev1.Secret {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	der, err := x509.CreateCertificate(rand.Reader, &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: user},
+		NotBefore:    notBefore,
+		NotAfter:     notAfter,
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	}, ca.Certs[0], &key.PublicKey, ca.Key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyPEM, err := keyutil.MarshalPrivateKeyToPEM(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Namespace: operatorclient.TargetNamespace, Name: SecretName},
+		Data: map[string][]byte{
+			corev1.TLSCertKey:       pem.EncodeToMemory(&pem.Block{Type: cert.CertificateBlockType, Bytes: der}),
+			corev1.TLSPrivateKeyKey: keyPEM,
+		},
+	}
+}
+
+func sign(t *testing.T, ca *crypto.TLSCertificateConfig, requestPEM []byte) []byte {
+	block, _ := pem.Decode(requestPEM)
+	request, err := x509.ParseCertificateRequest(block.Bytes)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if request.Subject.CommonName != ClientUser {
+		t.Errorf("unexpected subject %q", request.Subject.CommonName)
+	}
+	serial, err := rand.Int(rand.Reader, big.NewInt(1<<62))
+	if err != nil {
+		t.Fatal(err)
+	}
+	der, err := x509.CreateCertificate(rand.Reader, &x509.Certificate{
+		SerialNumber: serial,
+		Subject:      request.Subject,
+		NotBefore:    time.Now().Add(-time.Minute),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	}, ca.Certs[0], request.PublicKey, ca.Key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: cert.CertificateBlockType, Bytes: der})
+}
@@ -0,0 +1,156 @@
+package objectcountcontroller
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/prometheus/common/expfmt"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	corev1client "k8s.io/client-go/kubernetes/typed/core/v1"
+	corev1listers "k8s.io/client-go/listers/core/v1"
+
+	"github.com/openshift/library-go/pkg/controller/factory"
+	"github.com/openshift/library-go/pkg/operator/events"
+	"github.com/openshift/library-go/pkg/operator/resource/resourceapply"
+	"github.com/openshift/library-go/pkg/operator/v1helpers"
+
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/operatorclient"
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/tuning"
+)
+
+const (
+	// ConfigMapName is the configmap in the operator namespace the object count is published in.
+	ConfigMapName = "kube-controller-manager-object-count"
+	// ObjectsKey is the key of ConfigMapName holding the number of objects.
+	ObjectsKey = "objects"
+	// MeasuredAtKey is the key of ConfigMapName holding when the objects were counted, in RFC 3339.
+	MeasuredAtKey = "measuredAt"
+
+	// storageObjectsMetric is the number of objects the kube-apiserver stores, by resource.
+	storageObjectsMetric = "apiserver_storage_objects"
+
+	// measurePeriod is how often the objects are counted, the metrics of the kube-apiserver are large.
+	measurePeriod = 10 * time.Minute
+)
+
+// ObjectCountController counts the objects stored in the cluster from the metrics of the kube-apiserver and publishes
+// the count in the ConfigMapName configmap, which the garbage collector observer sizes the garbage collector workers
+// by. The objects are only counted while the autoScale of the garbageCollector section of the tuning configmap is
+// set, the configmap is deleted otherwise.
+type ObjectCountController struct {
+	configMapClient corev1client.ConfigMapsGetter
+	configMapLister corev1listers.ConfigMapLister
+	fetchMetrics    func(ctx context.Context) ([]byte, error)
+	now             func() time.Time
+}
+
+func NewObjectCountController(
+	kubeInformersForNamespaces v1helpers.KubeInformersForNamespaces,
+	kubeClient kubernetes.Interface,
+	eventRecorder events.Recorder,
+) factory.Controller {
+	c := &ObjectCountController{
+		configMapClient: kubeClient.CoreV1(),
+		configMapLister: kubeInformersForNamespaces.ConfigMapLister(),
+		fetchMetrics: func(ctx context.Context) ([]byte, error) {
+			return kubeClient.Discovery().RESTClient().Get().AbsPath("/metrics").DoRaw(ctx)
+		},
+		now: time.Now,
+	}
+
+	return factory.New().WithFilteredEventsInformers(
+		factory.NamesFilter(tuning.ConfigMapName),
+		kubeInformersForNamespaces.InformersFor(operatorclient.OperatorNamespace).Core().V1().ConfigMaps().Informer(),
+	).ResyncEvery(measurePeriod).WithSync(c.sync).ToController("ObjectCountController", eventRecorder)
+}
+
+func (c *ObjectCountController) sync(ctx context.Context, syncCtx factory.SyncContext) error {
+	tuningConfig, err := tuning.Get(c.configMapLister)
+	if err != nil {
+		return err
+	}
+	existing, err := c.configMapLister.ConfigMaps(operatorclient.OperatorNamespace).Get(ConfigMapName)
+	if err != nil && !apierrors.IsNotFound(err) {
+		return err
+	}
+	if !tuningConfig.GarbageCollector.AutoScale {
+		if existing == nil {
+			return nil
+		}
+		// a stale count must not size the workers once the scaling is turned on again
+		_, _, err := resourceapply.DeleteConfigMap(ctx, c.configMapClient, syncCtx.Recorder(), existing)
+		return err
+	}
+	if measuredAt, ok := measurementTime(existing); ok && c.now().Sub(measuredAt) < measurePeriod/2 {
+		// another change of the tuning configmap, the count is recent enough
+		return nil
+	}
+
+	metrics, err := c.fetchMetrics(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to fetch the metrics of the kube-apiserver: %v", err)
+	}
+	objects, err := countObjects(metrics)
+	if err != nil {
+		return err
+	}
+	_, _, err = resourceapply.ApplyConfigMap(ctx, c.configMapClient, syncCtx.Recorder(), &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Namespace: operatorclient.OperatorNamespace, Name: ConfigMapName},
+		Data: map[string]string{
+			ObjectsKey:    strconv.FormatInt(objects, 10),
+			MeasuredAtKey: c.now().UTC().Format(time.RFC3339),
+		},
+	})
+	return err
+}
+
+func measurementTime(configMap *corev1.ConfigMap) (time.Time, bool) {
+	if configMap == nil {
+		return time.Time{}, false
+	}
+	measuredAt, err := time.Parse(time.RFC3339, configMap.Data[MeasuredAtKey])
+	return measuredAt, err == nil
+}
+
+// countObjects sums the storageObjectsMetric of all the resources in the metrics of the kube-apiserver. Resources
+// the kube-apiserver failed to count report -1 and are skipped.
+func countObjects(metrics []byte) (int64, error) {
+	parser := expfmt.TextParser{}
+	families, err := parser.TextToMetricFamilies(bytes.NewReader(metrics))
+	if err != nil {
+		return 0, fmt.Errorf("failed to parse the metrics of the kube-apiserver: %v", err)
+	}
+	family, ok := families[storageObjectsMetric]
+	if !ok {
+		return 0, fmt.Errorf("the kube-apiserver does not report %s", storageObjectsMetric)
+	}
+	var objects int64
+	for _, metric := range family.GetMetric() {
+		if value := metric.GetGauge().GetValue(); value > 0 {
+			objects += int64(value)
+		}
+	}
+	return objects, nil
+}
+
+// Get returns the number of objects published in the ConfigMapName configmap, and false while none was counted.
+func Get(lister corev1listers.ConfigMapLister) (int64, bool, error) {
+	configMap, err := lister.ConfigMaps(operatorclient.OperatorNamespace).Get(ConfigMapName)
+	if apierrors.IsNotFound(err) {
+		return 0, false, nil
+	}
+	if err != nil {
+		return 0, false, err
+	}
+	objects, err := strconv.ParseInt(configMap.Data[ObjectsKey], 10, 64)
+	if err != nil {
+		return 0, false, fmt.Errorf("invalid %s/%s[%s]: %v", operatorclient.OperatorNamespace, ConfigMapName, ObjectsKey, err)
+	}
+	return objects, true, nil
+}
@@ -0,0 +1,137 @@
+package objectcountcontroller
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+	corev1listers "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/tools/cache"
+
+	"github.com/openshift/library-go/pkg/controller/factory"
+	"github.com/openshift/library-go/pkg/operator/events"
+
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/operatorclient"
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/tuning"
+)
+
+const apiserverMetrics = `# HELP apiserver_storage_objects [STABLE] Number of stored objects at the time of last check split by kind.
+# TYPE apiserver_storage_objects gauge
+apiserver_storage_objects{resource="configmaps"} 1200
+apiserver_storage_objects{resource="pods"} 800
+apiserver_storage_objects{resource="events"} -1
+# HELP apiserver_request_total Counter of apiserver requests.
+# TYPE apiserver_request_total counter
+apiserver_request_total{code="200",verb="GET"} 42
+`
+
+func TestCountObjects(t *testing.T) {
+	objects, err := countObjects([]byte(apiserverMetrics))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if objects != 2000 {
+		t.Errorf("expected 2000 objects, got %d", objects)
+	}
+	if _, err := countObjects([]byte("# TYPE apiserver_request_total counter\napiserver_request_total 1\n")); err == nil {
+		t.Errorf("expected an error without %s", storageObjectsMetric)
+	}
+}
+
+func TestSync(t *testing.T) {
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	countConfigMap := func(objects string, measuredAt time.Time) *corev1.ConfigMap {
+		return &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Namespace: operatorclient.OperatorNamespace, Name: ConfigMapName},
+			Data:       map[string]string{ObjectsKey: objects, MeasuredAtKey: measuredAt.Format(time.RFC3339)},
+		}
+	}
+
+	tests := []struct {
+		name            string
+		tuningConfig    string
+		existing        *corev1.ConfigMap
+		expectedObjects string
+		expectedFetch   bool
+	}{
+		{
+			name:     "scaling off deletes the count",
+			existing: countConfigMap("10", now.Add(-time.Hour)),
+		},
+		{
+			name:            "counted",
+			tuningConfig:    "garbageCollector:\n  autoScale: true",
+			expectedObjects: "2000",
+			expectedFetch:   true,
+		},
+		{
+			name:            "outdated count",
+			tuningConfig:    "garbageCollector:\n  autoScale: true",
+			existing:        countConfigMap("10", now.Add(-measurePeriod)),
+			expectedObjects: "2000",
+			expectedFetch:   true,
+		},
+		{
+			name:            "recent count",
+			tuningConfig:    "garbageCollector:\n  autoScale: true",
+			existing:        countConfigMap("10", now.Add(-time.Minute)),
+			expectedObjects: "10",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			indexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc})
+			var objects []runtime.Object
+			if len(test.tuningConfig) > 0 {
+				objects = append(objects, &corev1.ConfigMap{
+					ObjectMeta: metav1.ObjectMeta{Namespace: operatorclient.OperatorNamespace, Name: tuning.ConfigMapName},
+					Data:       map[string]string{tuning.ConfigKey: test.tuningConfig},
+				})
+			}
+			if test.existing != nil {
+				objects = append(objects, test.existing)
+			}
+			for _, object := range objects {
+				if err := indexer.Add(object); err != nil {
+					t.Fatal(err)
+				}
+			}
+			kubeClient := fake.NewSimpleClientset(objects...)
+			fetched := false
+			c := &ObjectCountController{
+				configMapClient: kubeClient.CoreV1(),
+				configMapLister: corev1listers.NewConfigMapLister(indexer),
+				fetchMetrics: func(ctx context.Context) ([]byte, error) {
+					fetched = true
+					return []byte(apiserverMetrics), nil
+				},
+				now: func() time.Time { return now },
+			}
+			if err := c.sync(context.TODO(), factory.NewSyncContext("test", events.NewInMemoryRecorder("test"))); err != nil {
+				t.Fatal(err)
+			}
+
+			if fetched != test.expectedFetch {
+				t.Errorf("expected the metrics fetched %v, got %v", test.expectedFetch, fetched)
+			}
+			configMap, err := kubeClient.CoreV1().ConfigMaps(operatorclient.OperatorNamespace).Get(context.TODO(), ConfigMapName, metav1.GetOptions{})
+			if len(test.expectedObjects) == 0 {
+				if !apierrors.IsNotFound(err) {
+					t.Errorf("expected no count, got %v, %v", configMap, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if configMap.Data[ObjectsKey] != test.expectedObjects {
+				t.Errorf("expected %s objects, got %s", test.expectedObjects, configMap.Data[ObjectsKey])
+			}
+		})
+	}
+}
@@ -0,0 +1,42 @@
+package operatorclient
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/client-go/tools/cache"
+)
+
+// NamespaceEventHandler calls handle with every object added, updated or deleted in one of the namespaces, with the
+// new object on update. All namespaces are handled when none is given. The final state of an object whose deletion
+// was missed is unwrapped, so handle always gets the object itself.
+func NamespaceEventHandler(namespaces []string, handle func(obj metav1.Object)) cache.ResourceEventHandler {
+	interesting := sets.New(namespaces...)
+	return cache.FilteringResourceEventHandler{
+		FilterFunc: func(obj interface{}) bool {
+			metaObj, ok := objectMeta(obj)
+			return ok && (interesting.Len() == 0 || interesting.Has(metaObj.GetNamespace()))
+		},
+		Handler: cache.ResourceEventHandlerFuncs{
+			AddFunc: func(obj interface{}) {
+				metaObj, _ := objectMeta(obj)
+				handle(metaObj)
+			},
+			UpdateFunc: func(_, newObj interface{}) {
+				metaObj, _ := objectMeta(newObj)
+				handle(metaObj)
+			},
+			DeleteFunc: func(obj interface{}) {
+				metaObj, _ := objectMeta(obj)
+				handle(metaObj)
+			},
+		},
+	}
+}
+
+func objectMeta(obj interface{}) (metav1.Object, bool) {
+	if tombstone, ok := obj.(cache.DeletedFinalStateUnknown); ok {
+		obj = tombstone.Obj
+	}
+	metaObj, ok := obj.(metav1.Object)
+	return metaObj, ok
+}
@@ -0,0 +1,46 @@
+package operatorclient
+
+import (
+	"reflect"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/cache"
+)
+
+func TestNamespaceEventHandler(t *testing.T) {
+	configMap := func(namespace, name string) *corev1.ConfigMap {
+		return &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: name}}
+	}
+
+	var handled []string
+	handler := NamespaceEventHandler([]string{TargetNamespace, OperatorNamespace}, func(obj metav1.Object) {
+		handled = append(handled, obj.GetNamespace()+"/"+obj.GetName())
+	})
+	handler.OnAdd(configMap(TargetNamespace, "added"), false)
+	handler.OnAdd(configMap("kube-system", "ignored"), false)
+	handler.OnUpdate(configMap(OperatorNamespace, "old"), configMap(OperatorNamespace, "updated"))
+	handler.OnDelete(configMap(TargetNamespace, "deleted"))
+	handler.OnDelete(cache.DeletedFinalStateUnknown{Key: TargetNamespace + "/missed", Obj: configMap(TargetNamespace, "missed")})
+	handler.OnDelete(cache.DeletedFinalStateUnknown{Key: "kube-system/missed", Obj: configMap("kube-system", "missed")})
+	handler.OnDelete("not an object")
+
+	expected := []string{
+		TargetNamespace + "/added",
+		OperatorNamespace + "/updated",
+		TargetNamespace + "/deleted",
+		TargetNamespace + "/missed",
+	}
+	if !reflect.DeepEqual(handled, expected) {
+		t.Errorf("expected %v, got %v", expected, handled)
+	}
+
+	handled = nil
+	NamespaceEventHandler(nil, func(obj metav1.Object) {
+		handled = append(handled, obj.GetNamespace()+"/"+obj.GetName())
+	}).OnAdd(configMap("kube-system", "any"), false)
+	if expected := []string{"kube-system/any"}; !reflect.DeepEqual(handled, expected) {
+		t.Errorf("expected every namespace to be handled, got %v", handled)
+	}
+}
@@ -0,0 +1,192 @@
+package operatorclient
+
+import (
+	"fmt"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/client-go/informers"
+	coreinformers "k8s.io/client-go/informers/core/v1"
+	"k8s.io/client-go/kubernetes"
+	corev1listers "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/klog/v2"
+
+	"github.com/openshift/library-go/pkg/operator/v1helpers"
+)
+
+// UserSpecifiedConfigSecretName is the only secret the shared informer of GlobalUserSpecifiedConfigNamespace caches.
+// The namespace holds the pull secrets, identity provider secrets and serving certs of the whole cluster, which are
+// not worth caching, the secrets named by the tuning extraMounts are watched by informers of their own. The
+// configmaps there are cached in full, their names are chosen by the admin in the infrastructure and proxy config and
+// by the CSR CA bundle label.
+const UserSpecifiedConfigSecretName = "initial-service-account-private-key"
+
+// MachineSpecifiedConfigSecretName is the only secret the shared informer of GlobalMachineSpecifiedConfigNamespace
+// caches, the source of the client certificate copied by the resource sync controller. The configmaps there are
+// stripped by TransformCachedObject instead, several of them are read.
+//
+// The secrets of OperatorNamespace are not filtered: the cert rotation controllers read several of them and a field
+// selector matches a single name.
+const MachineSpecifiedConfigSecretName = "kube-controller-manager-client-cert-key"
+
+// FilterSecretsByName makes the shared secret informer of the namespace only list and watch the named secret.
+// It must be called before the secret informer of the namespace is requested by any controller, later requests
+// then share the filtered informer.
+func FilterSecretsByName(kubeInformersForNamespaces v1helpers.KubeInformersForNamespaces, namespace, name string) {
+	kubeInformersForNamespaces.InformersFor(namespace).InformerFor(&corev1.Secret{}, func(client kubernetes.Interface, resyncPeriod time.Duration) cache.SharedIndexInformer {
+		return coreinformers.NewFilteredSecretInformer(client, namespace, resyncPeriod, cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc}, func(options *metav1.ListOptions) {
+			options.FieldSelector = fields.OneTermEqualSelector("metadata.name", name).String()
+		})
+	})
+}
+
+// NewKubeInformersForNamespaces is v1helpers.NewKubeInformersForNamespaces with a resync period per namespace. The
+// namespaces missing from resyncPeriods use defaultResyncPeriod. The cached objects are stripped by
+// TransformCachedObject.
+func NewKubeInformersForNamespaces(kubeClient kubernetes.Interface, defaultResyncPeriod time.Duration, resyncPeriods map[string]time.Duration, namespaces ...string) v1helpers.KubeInformersForNamespaces {
+	ret := kubeInformersForNamespaces{}
+	for _, namespace := range namespaces {
+		resyncPeriod, ok := resyncPeriods[namespace]
+		if !ok {
+			resyncPeriod = defaultResyncPeriod
+		}
+		if len(namespace) == 0 {
+			ret[""] = informers.NewSharedInformerFactoryWithOptions(kubeClient, resyncPeriod, informers.WithTransform(TransformCachedObject))
+			continue
+		}
+		ret[namespace] = informers.NewSharedInformerFactoryWithOptions(kubeClient, resyncPeriod, informers.WithNamespace(namespace), informers.WithTransform(TransformCachedObject))
+	}
+	return ret
+}
+
+type kubeInformersForNamespaces map[string]informers.SharedInformerFactory
+
+func (i kubeInformersForNamespaces) Start(stopCh <-chan struct{}) {
+	for _, informer := range i {
+		informer.Start(stopCh)
+	}
+}
+
+func (i kubeInformersForNamespaces) Namespaces() sets.String {
+	return sets.StringKeySet(i)
+}
+
+func (i kubeInformersForNamespaces) InformersFor(namespace string) informers.SharedInformerFactory {
+	return i[namespace]
+}
+
+func (i kubeInformersForNamespaces) ConfigMapLister() corev1listers.ConfigMapLister {
+	return configMapLister(i)
+}
+
+func (i kubeInformersForNamespaces) SecretLister() corev1listers.SecretLister {
+	return secretLister(i)
+}
+
+func (i kubeInformersForNamespaces) PodLister() corev1listers.PodLister {
+	return podLister(i)
+}
+
+// factoryFor returns the informer factory of a namespace, a missing one is a coding error.
+func (i kubeInformersForNamespaces) factoryFor(namespace string) informers.SharedInformerFactory {
+	informer, ok := i[namespace]
+	if !ok {
+		panic(fmt.Sprintf("namespace %q is missing", namespace))
+	}
+	return informer
+}
+
+func (i kubeInformersForNamespaces) globalFactory() (informers.SharedInformerFactory, error) {
+	globalInformer, ok := i[""]
+	if !ok {
+		return nil, fmt.Errorf("combinedLister does not support cross namespace list")
+	}
+	return globalInformer, nil
+}
+
+type configMapLister kubeInformersForNamespaces
+
+func (l configMapLister) List(selector labels.Selector) ([]*corev1.ConfigMap, error) {
+	globalInformer, err := kubeInformersForNamespaces(l).globalFactory()
+	if err != nil {
+		return nil, err
+	}
+	return globalInformer.Core().V1().ConfigMaps().Lister().List(selector)
+}
+
+func (l configMapLister) ConfigMaps(namespace string) corev1listers.ConfigMapNamespaceLister {
+	lister := kubeInformersForNamespaces(l).factoryFor(namespace).Core().V1().ConfigMaps().Lister().ConfigMaps(namespace)
+	if namespace == GlobalMachineSpecifiedConfigNamespace {
+		return cachedDataConfigMapLister{ConfigMapNamespaceLister: lister}
+	}
+	return lister
+}
+
+// cachedDataConfigMapLister fails the reads of the configmaps of GlobalMachineSpecifiedConfigNamespace whose data
+// TransformCachedObject strips, a reader would otherwise see them empty.
+type cachedDataConfigMapLister struct {
+	corev1listers.ConfigMapNamespaceLister
+}
+
+func (l cachedDataConfigMapLister) Get(name string) (*corev1.ConfigMap, error) {
+	if !CachedDataConfigMaps.Has(name) {
+		return nil, strippedDataError("configmap", name, "CachedDataConfigMaps")
+	}
+	return l.ConfigMapNamespaceLister.Get(name)
+}
+
+type secretLister kubeInformersForNamespaces
+
+func (l secretLister) List(selector labels.Selector) ([]*corev1.Secret, error) {
+	globalInformer, err := kubeInformersForNamespaces(l).globalFactory()
+	if err != nil {
+		return nil, err
+	}
+	return globalInformer.Core().V1().Secrets().Lister().List(selector)
+}
+
+func (l secretLister) Secrets(namespace string) corev1listers.SecretNamespaceLister {
+	lister := kubeInformersForNamespaces(l).factoryFor(namespace).Core().V1().Secrets().Lister().Secrets(namespace)
+	if namespace == GlobalMachineSpecifiedConfigNamespace {
+		return cachedDataSecretLister{SecretNamespaceLister: lister}
+	}
+	return lister
+}
+
+// cachedDataSecretLister is cachedDataConfigMapLister for secrets.
+type cachedDataSecretLister struct {
+	corev1listers.SecretNamespaceLister
+}
+
+func (l cachedDataSecretLister) Get(name string) (*corev1.Secret, error) {
+	if !CachedDataSecrets.Has(name) {
+		return nil, strippedDataError("secret", name, "CachedDataSecrets")
+	}
+	return l.SecretNamespaceLister.Get(name)
+}
+
+// strippedDataError is logged as well, it is a coding error that only shows on a cluster.
+func strippedDataError(kind, name, allowlist string) error {
+	err := fmt.Errorf("%s/%s in %q is cached without its data, add it to operatorclient.%s", kind, name, GlobalMachineSpecifiedConfigNamespace, allowlist)
+	klog.Errorf("Read of a stripped object: %v", err)
+	return err
+}
+
+type podLister kubeInformersForNamespaces
+
+func (l podLister) List(selector labels.Selector) ([]*corev1.Pod, error) {
+	globalInformer, err := kubeInformersForNamespaces(l).globalFactory()
+	if err != nil {
+		return nil, err
+	}
+	return globalInformer.Core().V1().Pods().Lister().List(selector)
+}
+
+func (l podLister) Pods(namespace string) corev1listers.PodNamespaceLister {
+	return kubeInformersForNamespaces(l).factoryFor(namespace).Core().V1().Pods().Lister().Pods(namespace)
+}
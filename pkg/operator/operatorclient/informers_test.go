@@ -0,0 +1,77 @@
+package operatorclient
+
+import (
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/client-go/kubernetes/fake"
+	clienttesting "k8s.io/client-go/testing"
+
+	"github.com/openshift/library-go/pkg/operator/v1helpers"
+)
+
+func TestFilterSecretsByName(t *testing.T) {
+	kubeClient := fake.NewSimpleClientset()
+	var fieldSelectors []string
+	kubeClient.PrependReactor("list", "secrets", func(action clienttesting.Action) (bool, runtime.Object, error) {
+		fieldSelectors = append(fieldSelectors, action.(clienttesting.ListActionImpl).GetListRestrictions().Fields.String())
+		return false, nil, nil
+	})
+
+	namespaces := []string{GlobalUserSpecifiedConfigNamespace, GlobalMachineSpecifiedConfigNamespace, TargetNamespace}
+	kubeInformersForNamespaces := v1helpers.NewKubeInformersForNamespaces(kubeClient, namespaces...)
+	FilterSecretsByName(kubeInformersForNamespaces, GlobalUserSpecifiedConfigNamespace, UserSpecifiedConfigSecretName)
+	FilterSecretsByName(kubeInformersForNamespaces, GlobalMachineSpecifiedConfigNamespace, MachineSpecifiedConfigSecretName)
+
+	// the informers requested by the controllers are the filtered ones
+	for _, namespace := range namespaces {
+		kubeInformersForNamespaces.InformersFor(namespace).Core().V1().Secrets().Informer()
+	}
+
+	stopCh := make(chan struct{})
+	defer close(stopCh)
+	kubeInformersForNamespaces.Start(stopCh)
+	for _, namespace := range namespaces {
+		kubeInformersForNamespaces.InformersFor(namespace).WaitForCacheSync(stopCh)
+	}
+
+	expected := map[string]bool{"metadata.name=" + UserSpecifiedConfigSecretName: true, "metadata.name=" + MachineSpecifiedConfigSecretName: true, "": true}
+	if len(fieldSelectors) != len(expected) {
+		t.Fatalf("expected field selectors %v, got %v", expected, fieldSelectors)
+	}
+	for _, fieldSelector := range fieldSelectors {
+		if !expected[fieldSelector] {
+			t.Errorf("unexpected field selector %q", fieldSelector)
+		}
+	}
+}
+
+func TestNewKubeInformersForNamespaces(t *testing.T) {
+	kubeClient := fake.NewSimpleClientset(&corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Namespace: TargetNamespace, Name: "config"}})
+	kubeInformersForNamespaces := NewKubeInformersForNamespaces(kubeClient, 10*time.Minute, map[string]time.Duration{TargetNamespace: time.Minute}, "", TargetNamespace)
+	kubeInformersForNamespaces.InformersFor("").Core().V1().ConfigMaps().Informer()
+	kubeInformersForNamespaces.InformersFor(TargetNamespace).Core().V1().ConfigMaps().Informer()
+
+	stopCh := make(chan struct{})
+	defer close(stopCh)
+	kubeInformersForNamespaces.Start(stopCh)
+	for _, namespace := range []string{"", TargetNamespace} {
+		kubeInformersForNamespaces.InformersFor(namespace).WaitForCacheSync(stopCh)
+	}
+
+	if _, err := kubeInformersForNamespaces.ConfigMapLister().ConfigMaps(TargetNamespace).Get("config"); err != nil {
+		t.Errorf("expected the configmap of the namespace: %v", err)
+	}
+	configMaps, err := kubeInformersForNamespaces.ConfigMapLister().List(labels.Everything())
+	if err != nil || len(configMaps) != 1 {
+		t.Errorf("expected the configmap in the cluster-scoped list, got %v: %v", configMaps, err)
+	}
+	if !kubeInformersForNamespaces.Namespaces().Equal(sets.NewString("", TargetNamespace)) {
+		t.Errorf("unexpected namespaces %v", kubeInformersForNamespaces.Namespaces().List())
+	}
+}
@@ -6,3 +6,22 @@ const (
 	OperatorNamespace                     = "openshift-kube-controller-manager-operator"
 	TargetNamespace                       = "openshift-kube-controller-manager"
 )
+
+// ConfigNamespaces hold the configmaps and secrets the operand is rendered from and into. The controllers reacting
+// to any change of their inputs watch all of them.
+var ConfigNamespaces = []string{
+	GlobalUserSpecifiedConfigNamespace,
+	GlobalMachineSpecifiedConfigNamespace,
+	OperatorNamespace,
+	TargetNamespace,
+}
+
+// InformerNamespaces are the namespaces the operator caches, the empty one being the cluster-scoped resources. A
+// namespace the operator starts to read from, e.g. for the metrics or logging stack, is added here and gets its
+// informers and resync period with the others.
+var InformerNamespaces = append([]string{""}, append(ConfigNamespaces,
+	// the install-config for the FIPS check
+	"kube-system",
+	// the static resources of the infra controllers
+	"openshift-infra",
+)...)
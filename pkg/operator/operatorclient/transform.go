@@ -0,0 +1,59 @@
+package operatorclient
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/util/sets"
+)
+
+// CachedDataConfigMaps and CachedDataSecrets are the configmaps and secrets of GlobalMachineSpecifiedConfigNamespace
+// whose data is cached. The namespace is shared by the whole cluster and holds large contents the operator never
+// reads, like the console dashboards, the other configmaps and secrets there are cached with their metadata only.
+// A controller reading another configmap or secret of the namespace through a lister must add it here, the
+// ConfigMapLister and SecretLister of NewKubeInformersForNamespaces fail the reads of the others.
+var (
+	CachedDataConfigMaps = sets.NewString(
+		"csr-controller-ca",
+		"default-ingress-cert",
+		"kube-apiserver-aggregator-client-ca",
+		"kube-apiserver-client-ca",
+		"kube-apiserver-server-ca",
+		// the cloud provider config observer
+		"kube-cloud-config",
+		"sa-token-signing-certs",
+		"service-ca",
+	)
+	CachedDataSecrets = sets.NewString(
+		MachineSpecifiedConfigSecretName,
+	)
+)
+
+// TransformCachedObject strips what the controllers do not read from the objects cached by the informers: the
+// managed fields of every object, and the data of the configmaps and secrets of GlobalMachineSpecifiedConfigNamespace
+// missing from CachedDataConfigMaps and CachedDataSecrets. The resource versions are kept, the objects still trigger
+// the event handlers on every change.
+func TransformCachedObject(obj interface{}) (interface{}, error) {
+	accessor, err := meta.Accessor(obj)
+	if err != nil {
+		// the tombstones hold objects that were transformed already
+		return obj, nil
+	}
+	accessor.SetManagedFields(nil)
+
+	if accessor.GetNamespace() != GlobalMachineSpecifiedConfigNamespace {
+		return obj, nil
+	}
+	switch obj := obj.(type) {
+	case *corev1.ConfigMap:
+		if !CachedDataConfigMaps.Has(obj.Name) {
+			obj.Data = nil
+			obj.BinaryData = nil
+		}
+	case *corev1.Secret:
+		if !CachedDataSecrets.Has(obj.Name) {
+			obj.Data = nil
+			obj.StringData = nil
+		}
+	}
+	return obj, nil
+}
@@ -0,0 +1,87 @@
+package operatorclient
+
+import (
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/tools/cache"
+)
+
+func TestTransformCachedObject(t *testing.T) {
+	managedFields := []metav1.ManagedFieldsEntry{{Manager: "kube-apiserver"}}
+	configMap := func(namespace, name string) *corev1.ConfigMap {
+		return &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: name, ResourceVersion: "1", ManagedFields: managedFields},
+			Data:       map[string]string{"ca-bundle.crt": "bundle"},
+		}
+	}
+	kubeClient := fake.NewSimpleClientset(
+		configMap(GlobalMachineSpecifiedConfigNamespace, "kube-apiserver-server-ca"),
+		configMap(GlobalMachineSpecifiedConfigNamespace, "grafana-dashboard-etcd"),
+		configMap(TargetNamespace, "config"),
+		&corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Namespace: GlobalMachineSpecifiedConfigNamespace, Name: "router-certs"},
+			Data:       map[string][]byte{"tls.key": []byte("key")},
+		},
+	)
+	kubeInformersForNamespaces := NewKubeInformersForNamespaces(kubeClient, 0, nil, GlobalMachineSpecifiedConfigNamespace, TargetNamespace)
+	for _, namespace := range []string{GlobalMachineSpecifiedConfigNamespace, TargetNamespace} {
+		kubeInformersForNamespaces.InformersFor(namespace).Core().V1().ConfigMaps().Informer()
+		kubeInformersForNamespaces.InformersFor(namespace).Core().V1().Secrets().Informer()
+	}
+	stopCh := make(chan struct{})
+	defer close(stopCh)
+	kubeInformersForNamespaces.Start(stopCh)
+	for _, namespace := range []string{GlobalMachineSpecifiedConfigNamespace, TargetNamespace} {
+		kubeInformersForNamespaces.InformersFor(namespace).WaitForCacheSync(stopCh)
+	}
+
+	for _, test := range []struct {
+		namespace, name string
+		expectData      bool
+	}{
+		{namespace: GlobalMachineSpecifiedConfigNamespace, name: "kube-apiserver-server-ca", expectData: true},
+		{namespace: GlobalMachineSpecifiedConfigNamespace, name: "grafana-dashboard-etcd"},
+		{namespace: TargetNamespace, name: "config", expectData: true},
+	} {
+		cached, err := kubeInformersForNamespaces.InformersFor(test.namespace).Core().V1().ConfigMaps().Lister().ConfigMaps(test.namespace).Get(test.name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if hasData := len(cached.Data) > 0; hasData != test.expectData {
+			t.Errorf("configmap/%s -n %s: expected data cached %v, got %v", test.name, test.namespace, test.expectData, cached.Data)
+		}
+		if len(cached.ManagedFields) > 0 || cached.ResourceVersion != "1" {
+			t.Errorf("configmap/%s -n %s: expected the managed fields stripped and the resource version kept, got %+v", test.name, test.namespace, cached.ObjectMeta)
+		}
+	}
+	secret, err := kubeInformersForNamespaces.InformersFor(GlobalMachineSpecifiedConfigNamespace).Core().V1().Secrets().Lister().Secrets(GlobalMachineSpecifiedConfigNamespace).Get("router-certs")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(secret.Data) > 0 {
+		t.Errorf("expected the data of secret/router-certs stripped, got %v", secret.Data)
+	}
+
+	// the combined listers fail the reads of the stripped objects instead of returning them empty
+	if _, err := kubeInformersForNamespaces.ConfigMapLister().ConfigMaps(GlobalMachineSpecifiedConfigNamespace).Get("kube-apiserver-server-ca"); err != nil {
+		t.Errorf("expected configmap/kube-apiserver-server-ca to be read: %v", err)
+	}
+	if _, err := kubeInformersForNamespaces.ConfigMapLister().ConfigMaps(GlobalMachineSpecifiedConfigNamespace).Get("grafana-dashboard-etcd"); err == nil {
+		t.Errorf("expected the read of configmap/grafana-dashboard-etcd to fail")
+	}
+	if _, err := kubeInformersForNamespaces.SecretLister().Secrets(GlobalMachineSpecifiedConfigNamespace).Get("router-certs"); err == nil {
+		t.Errorf("expected the read of secret/router-certs to fail")
+	}
+	if _, err := kubeInformersForNamespaces.ConfigMapLister().ConfigMaps(TargetNamespace).Get("config"); err != nil {
+		t.Errorf("expected configmap/config of the target namespace to be read: %v", err)
+	}
+
+	// tombstones are passed through
+	tombstone := cache.DeletedFinalStateUnknown{Key: "ns/name"}
+	if obj, err := TransformCachedObject(tombstone); err != nil || obj != tombstone {
+		t.Errorf("expected the tombstone unchanged, got %v: %v", obj, err)
+	}
+}
@@ -0,0 +1,640 @@
+package operatorruntime
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	configv1 "github.com/openshift/api/config/v1"
+	"github.com/openshift/cluster-kube-controller-manager-operator/bindata"
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/aggregatorclientcacontroller"
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/assetoverride"
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/canarycontroller"
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/certrecovery"
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/certrotationcontroller"
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/clientcertexpirycontroller"
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/configfingerprintcontroller"
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/configobservation/configobservercontroller"
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/configobservation/node"
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/crashloopcontroller"
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/csrselftestcontroller"
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/csrsignerhistorycontroller"
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/csrsigningcontroller"
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/debugcontroller"
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/downtimecontroller"
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/fipscontroller"
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/gcwatchercontroller"
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/hostedcontrolplanecontroller"
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/installerlogcontroller"
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/kubeconfigcontroller"
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/managementstatecontroller"
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/metricsclientcertcontroller"
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/objectcountcontroller"
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/operatorclient"
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/operatorschedulingcontroller"
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/ownershipcontroller"
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/podjanitorcontroller"
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/rbacdriftcontroller"
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/recoverytokencontroller"
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/resourcesynccontroller"
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/revisionhistorycontroller"
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/rollbackcontroller"
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/rolloutbarrier"
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/rolloutordercontroller"
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/rolloutstatuscontroller"
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/targetconfigcontroller"
+	"github.com/openshift/library-go/pkg/operator/certrotation"
+	"github.com/openshift/library-go/pkg/operator/latencyprofilecontroller"
+	"github.com/openshift/library-go/pkg/operator/resource/resourceapply"
+	libgoresourcesynccontroller "github.com/openshift/library-go/pkg/operator/resourcesynccontroller"
+	"github.com/openshift/library-go/pkg/operator/staticpod"
+	"github.com/openshift/library-go/pkg/operator/staticpod/controller/common"
+	"github.com/openshift/library-go/pkg/operator/staticresourcecontroller"
+	"github.com/openshift/library-go/pkg/operator/status"
+	"github.com/openshift/library-go/pkg/operator/v1helpers"
+
+	policyv1 "k8s.io/api/policy/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/klog/v2"
+	"k8s.io/utils/ptr"
+)
+
+// Controller is a controller of the operator built by NewControllers.
+type Controller struct {
+	Name string
+
+	runner  runner
+	workers int
+}
+
+// Run runs the controller until the context is done. The informers of the Options must be started.
+func (c Controller) Run(ctx context.Context) {
+	c.runner.Run(ctx, c.workers)
+}
+
+// Start runs the controllers in the background until the context is done.
+func Start(ctx context.Context, controllers []Controller) {
+	for _, controller := range controllers {
+		go controller.Run(ctx)
+	}
+}
+
+// ControllerNames are the names of the controllers of the operator, the ones Options.Controllers selects from.
+func ControllerNames() []string {
+	var names []string
+	for _, constructor := range (&builder{}).constructors() {
+		names = append(names, constructor.name)
+	}
+	return names
+}
+
+// NewControllers builds the controllers selected by the options. The controllers register their informers with the
+// informer factories of the options, which must be started after.
+func NewControllers(ctx context.Context, opts Options) ([]Controller, error) {
+	b := &builder{ctx: ctx, opts: opts}
+	constructors := b.constructors()
+
+	selected := sets.New[string](opts.Controllers...)
+	known := sets.New[string]()
+	for _, constructor := range constructors {
+		known.Insert(constructor.name)
+	}
+	if unknown := selected.Difference(known); unknown.Len() > 0 {
+		return nil, fmt.Errorf("unknown controllers %s, the controllers are %s", strings.Join(sets.List(unknown), ", "), strings.Join(ControllerNames(), ", "))
+	}
+
+	var controllers []Controller
+	for _, constructor := range constructors {
+		if selected.Len() > 0 && !selected.Has(constructor.name) {
+			continue
+		}
+		controller, err := constructor.new()
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", constructor.name, err)
+		}
+		workers := constructor.workers
+		if workers == 0 {
+			workers = 1
+		}
+		controllers = append(controllers, Controller{Name: constructor.name, runner: controller, workers: workers})
+	}
+	return controllers, nil
+}
+
+// runner is implemented by the controllers of library-go.
+type runner interface {
+	Run(ctx context.Context, workers int)
+}
+
+// managerRunner runs the controllers of a staticpod builder, which are started as a whole.
+type managerRunner struct {
+	manager interface{ Start(ctx context.Context) }
+}
+
+func (r managerRunner) Run(ctx context.Context, _ int) {
+	r.manager.Start(ctx)
+}
+
+type constructor struct {
+	name string
+	new  func() (runner, error)
+	// workers defaults to 1.
+	workers int
+}
+
+// builder builds the controllers, and once the state some of them share.
+type builder struct {
+	ctx  context.Context
+	opts Options
+
+	resourceSyncController *libgoresourcesynccontroller.ResourceSyncController
+	versionRecorder        status.VersionGetter
+	certRecoveryDetector   *certrecovery.Detector
+}
+
+// resourceSyncer is shared by the ResourceSyncController and the ConfigObserver, whose observers sync resources.
+func (b *builder) resourceSyncer() (*libgoresourcesynccontroller.ResourceSyncController, error) {
+	if b.resourceSyncController != nil {
+		return b.resourceSyncController, nil
+	}
+	resourceSyncController, err := resourcesynccontroller.NewResourceSyncController(
+		b.opts.OperatorClient,
+		b.opts.KubeInformersForNamespaces,
+		v1helpers.CachedSecretGetter(b.opts.KubeClient.CoreV1(), b.opts.KubeInformersForNamespaces),
+		v1helpers.CachedConfigMapGetter(b.opts.KubeClient.CoreV1(), b.opts.KubeInformersForNamespaces),
+		b.opts.EventRecorder,
+	)
+	if err != nil {
+		return nil, err
+	}
+	b.resourceSyncController = resourceSyncController
+	return resourceSyncController, nil
+}
+
+// versionGetter starts from the versions of the clusteroperator, so they don't change until we sync.
+func (b *builder) versionGetter() (status.VersionGetter, error) {
+	if b.versionRecorder != nil {
+		return b.versionRecorder, nil
+	}
+	versionRecorder := status.NewVersionGetter()
+	clusterOperator, err := b.opts.ConfigClient.ConfigV1().ClusterOperators().Get(b.ctx, "kube-controller-manager", metav1.GetOptions{})
+	if err != nil && !errors.IsNotFound(err) {
+		return nil, err
+	}
+	for _, version := range clusterOperator.Status.Versions {
+		versionRecorder.SetVersion(version.Name, version.Version)
+	}
+	versionRecorder.SetVersion("raw-internal", status.VersionForOperatorFromEnv())
+	b.versionRecorder = versionRecorder
+	return versionRecorder, nil
+}
+
+// certRecovery is shared by the TargetConfigController and the SATokenSignerController, which both recover the trust.
+func (b *builder) certRecovery() *certrecovery.Detector {
+	if b.certRecoveryDetector == nil {
+		b.certRecoveryDetector = certrecovery.NewDetector(b.opts.ForceCertRecovery, b.opts.KubeInformersForNamespaces)
+	}
+	return b.certRecoveryDetector
+}
+
+func (b *builder) constructors() []constructor {
+	opts := b.opts
+	return []constructor{
+		{name: "ResourceSyncController", new: func() (runner, error) {
+			return b.resourceSyncer()
+		}},
+		{name: "ConfigObserver", new: func() (runner, error) {
+			resourceSyncController, err := b.resourceSyncer()
+			if err != nil {
+				return nil, err
+			}
+			return configobservercontroller.NewConfigObserver(
+				opts.OperatorClient,
+				opts.ConfigInformers,
+				opts.KubeInformersForNamespaces,
+				resourceSyncController,
+				opts.FeatureGateAccessor,
+				opts.SecurePort,
+				opts.EventRecorder,
+			)
+		}},
+		{name: "KubeControllerManagerStaticResources", new: func() (runner, error) {
+			return staticresourcecontroller.NewStaticResourceController(
+				"KubeControllerManagerStaticResources",
+				securePortAssets(opts.SecurePort),
+				[]string{
+					"assets/kube-controller-manager/ns.yaml",
+					"assets/kube-controller-manager/namespace-openshift-infra.yaml",
+					"assets/kube-controller-manager/svc.yaml",
+					"assets/kube-controller-manager/metrics-svc.yaml",
+					"assets/kube-controller-manager/sa.yaml",
+					"assets/kube-controller-manager/recycler-sa.yaml",
+					"assets/kube-controller-manager/localhost-recovery-sa.yaml",
+					"assets/kube-controller-manager/localhost-recovery-token.yaml",
+				},
+				(&resourceapply.ClientHolder{}).WithKubernetes(opts.KubeClient),
+				opts.OperatorClient,
+				opts.EventRecorder,
+			).WithConditionalResources(
+				bindata.Asset,
+				[]string{
+					"assets/kube-controller-manager/vsphere/legacy-cloud-provider-sa.yaml",
+					"assets/kube-controller-manager/vsphere/legacy-cloud-provider-role.yaml",
+					"assets/kube-controller-manager/vsphere/legacy-cloud-provider-binding.yaml",
+				},
+				func() bool {
+					isVSphere, precheckSucceeded, err := newPlatformMatcherFn(configv1.VSpherePlatformType, opts.ConfigInformers.Config().V1().Infrastructures())()
+					if err != nil {
+						klog.Errorf("PlatformType check failed: %v", err)
+						return false
+					}
+					if !precheckSucceeded {
+						klog.V(4).Infof("PlatformType precheck did not succeed, skipping")
+						return false
+					}
+					// create only if platform type is vsphere
+					return isVSphere
+				},
+				nil,
+			).WithConditionalResources(
+				bindata.Asset,
+				[]string{
+					"assets/kube-controller-manager/gce/cloud-provider-role.yaml",
+					"assets/kube-controller-manager/gce/cloud-provider-binding.yaml",
+				},
+				func() bool {
+					// We do not want to apply these resources, so must return false here
+					return false
+				},
+				func() bool {
+					// The resources above are required for the 4.14 -> 4.15 upgrade path.
+					// They are not required from 4.16, so can re removed.
+					return true
+				},
+			).AddKubeInformers(opts.KubeInformersForNamespaces), nil
+		}},
+		// the roles and bindings of the operand are restored on every edit, see the RBACDriftController
+		{name: "RBACDriftController", new: func() (runner, error) {
+			return rbacdriftcontroller.NewRBACDriftController(
+				opts.OperatorClient,
+				opts.KubeInformersForNamespaces,
+				opts.KubeClient,
+				opts.EventRecorder,
+			), nil
+		}},
+		// the managed configmaps and secrets are labeled and annotated for backup and GitOps tooling
+		{name: "OwnershipController", new: func() (runner, error) {
+			return ownershipcontroller.NewOwnershipController(
+				opts.OperatorClient,
+				managedResourceOwners,
+				opts.KubeInformersForNamespaces,
+				opts.KubeClient,
+				opts.EventRecorder,
+			), nil
+		}},
+		// the installer controller rolls out to the nodes in the order of the node statuses
+		{name: "RolloutOrderController", new: func() (runner, error) {
+			return rolloutordercontroller.NewRolloutOrderController(
+				opts.OperatorClient,
+				opts.KubeInformersForNamespaces,
+				opts.EventRecorder,
+			), nil
+		}},
+		// a revision marked bad is rolled back by restoring the rendered resources of a previous one
+		{name: "RollbackController", new: func() (runner, error) {
+			return rollbackcontroller.NewRollbackController(
+				opts.OperatorClient,
+				opts.OperatorLister,
+				opts.KubeInformersForNamespaces,
+				opts.KubeClient,
+				opts.EventRecorder,
+			), nil
+		}},
+		// the garbage collector observer scales the garbage collector workers to the object count
+		{name: "ObjectCountController", new: func() (runner, error) {
+			return objectcountcontroller.NewObjectCountController(
+				opts.KubeInformersForNamespaces,
+				opts.KubeClient,
+				opts.EventRecorder,
+			), nil
+		}},
+		// the monitoring stack is optional, so the rules are only created once its CRDs are available
+		{name: "KubeControllerManagerMonitoringResources", new: func() (runner, error) {
+			return staticresourcecontroller.NewStaticResourceController(
+				"KubeControllerManagerMonitoringResources",
+				bindata.Asset,
+				[]string{
+					"assets/kube-controller-manager/monitoring/servicemonitor.yaml",
+					"assets/kube-controller-manager/monitoring/workqueue-recording-rules.yaml",
+				},
+				(&resourceapply.ClientHolder{}).WithKubernetes(opts.KubeClient).WithDynamicClient(opts.DynamicClient),
+				opts.OperatorClient,
+				opts.EventRecorder,
+			).WithIgnoreNotFoundOnCreate().AddKubeInformers(opts.KubeInformersForNamespaces), nil
+		}},
+		{name: "TargetConfigController", workers: targetconfigcontroller.Workers, new: func() (runner, error) {
+			return targetconfigcontroller.NewTargetConfigController(
+				opts.Images.KubeControllerManager,
+				opts.Images.Operator,
+				opts.Images.ClusterPolicyController,
+				opts.Images.Tools,
+				opts.KubeInformersForNamespaces,
+				opts.OperatorClient,
+				opts.OperatorLister,
+				opts.KubeClient,
+				opts.DynamicClient,
+				assetoverride.NewSource(opts.AssetOverridesEnabled, opts.KubeInformersForNamespaces.ConfigMapLister()),
+				b.certRecovery(),
+				opts.EventRecorder,
+			), nil
+		}},
+		{name: "KubeconfigController", new: func() (runner, error) {
+			return kubeconfigcontroller.NewKubeconfigController(
+				opts.OperatorClient,
+				opts.KubeInformersForNamespaces,
+				opts.KubeClient,
+				opts.ConfigInformers.Config().V1().Infrastructures(),
+				opts.EventRecorder,
+			), nil
+		}},
+		// the revision, installer, pruning, node and guard controllers of library-go
+		{name: "StaticPodControllers", new: func() (runner, error) {
+			versionRecorder, err := b.versionGetter()
+			if err != nil {
+				return nil, err
+			}
+			staticPodControllers, err := staticpod.NewBuilder(opts.OperatorClient, opts.KubeClient, opts.KubeInformersForNamespaces, opts.ConfigInformers).
+				WithEvents(opts.EventRecorder).
+				// the installs wait for the etcd and kube-apiserver rollouts on the same node
+				WithCustomInstaller(
+					[]string{"cluster-kube-controller-manager-operator", "installer"},
+					rolloutbarrier.InstallerPodMutationFunc([]string{"cluster-kube-controller-manager-operator", "rollout-barrier"}, rolloutbarrier.DefaultTimeout),
+				).
+				WithPruning([]string{"cluster-kube-controller-manager-operator", "prune"}, "kube-controller-manager-pod").
+				WithRevisionedResources(operatorclient.TargetNamespace, "kube-controller-manager", deploymentConfigMaps, deploymentSecrets).
+				WithUnrevisionedCerts("kube-controller-manager-certs", CertConfigMaps, CertSecrets).
+				WithVersioning("kube-controller-manager", versionRecorder).
+				WithPodDisruptionBudgetGuard(
+					"openshift-kube-controller-manager-operator",
+					"kube-controller-manager-operator",
+					strconv.Itoa(int(opts.SecurePort)),
+					"healthz",
+					ptr.To(policyv1.AlwaysAllow),
+					func() (bool, bool, error) {
+						isSNO, precheckSucceeded, err := common.NewIsSingleNodePlatformFn(opts.ConfigInformers.Config().V1().Infrastructures())()
+						// create only when not a single node topology
+						return !isSNO, precheckSucceeded, err
+					},
+				).
+				WithOperandPodLabelSelector(labels.Set{"app": "kube-controller-manager"}.AsSelector()).
+				ToControllers()
+			if err != nil {
+				return nil, err
+			}
+			return managerRunner{manager: staticPodControllers}, nil
+		}},
+		{name: "ClusterOperatorStatusController", new: func() (runner, error) {
+			versionRecorder, err := b.versionGetter()
+			if err != nil {
+				return nil, err
+			}
+			return status.NewClusterOperatorStatusController(
+				"kube-controller-manager",
+				[]configv1.ObjectReference{
+					{Group: "operator.openshift.io", Resource: "kubecontrollermanagers", Name: "cluster"},
+					{Resource: "namespaces", Name: "openshift-config"},
+					{Resource: "namespaces", Name: "openshift-config-managed"},
+					{Resource: "namespaces", Name: operatorclient.TargetNamespace},
+					{Resource: "namespaces", Name: "openshift-kube-controller-manager-operator"},
+					{Resource: "namespaces", Name: "kube-system"},
+					// TODO move to a more appropriate operator. One that creates and approves these.
+					{Group: "certificates.k8s.io", Resource: "certificatesigningrequests"},
+					// TODO move to a more appropriate operator. One that creates and manages these.
+					{Resource: "nodes"},
+					{Group: "config.openshift.io", Resource: "nodes", Name: "cluster"},
+				},
+				opts.ConfigClient.ConfigV1(),
+				opts.ConfigInformers.Config().V1().ClusterOperators(),
+				opts.OperatorClient,
+				versionRecorder,
+				opts.EventRecorder,
+			), nil
+		}},
+		{name: "CertRotationController", new: func() (runner, error) {
+			certRotationScale, err := certrotation.GetCertRotationScale(b.ctx, opts.KubeClient, operatorclient.GlobalUserSpecifiedConfigNamespace)
+			if err != nil {
+				return nil, err
+			}
+			return certrotationcontroller.NewCertRotationController(
+				v1helpers.CachedSecretGetter(opts.KubeClient.CoreV1(), opts.KubeInformersForNamespaces),
+				v1helpers.CachedConfigMapGetter(opts.KubeClient.CoreV1(), opts.KubeInformersForNamespaces),
+				opts.OperatorClient,
+				opts.KubeInformersForNamespaces,
+				opts.EventRecorder,
+				// this is weird, but when we turn down rotation in CI, we go fast enough that kubelets and kas are racing to observe the new signer before the signer is used.
+				// we need to establish some kind of delay or back pressure to prevent the rollout.  This ensures we don't trigger kas restart
+				// during e2e tests for now.
+				certRotationScale*8,
+			)
+		}},
+		{name: "SATokenSignerController", new: func() (runner, error) {
+			return certrotationcontroller.NewSATokenSignerController(opts.OperatorClient, opts.KubeInformersForNamespaces, opts.KubeClient, b.certRecovery(), opts.EventRecorder), nil
+		}},
+		{name: "LatencyProfileController", new: func() (runner, error) {
+			latencyProfileRejectionChecker, err := latencyprofilecontroller.NewInstallerProfileRejectionChecker(
+				opts.KubeInformersForNamespaces.ConfigMapLister().ConfigMaps(operatorclient.TargetNamespace),
+				node.LatencyConfigs,
+				node.LatencyProfileRejectionScenarios,
+			)
+			if err != nil {
+				return nil, err
+			}
+			return latencyprofilecontroller.NewLatencyProfileController(
+				opts.OperatorClient,
+				operatorclient.TargetNamespace,
+				latencyProfileRejectionChecker,
+				latencyprofilecontroller.NewInstallerRevisionConfigMatcher(
+					opts.KubeInformersForNamespaces.ConfigMapLister().ConfigMaps(operatorclient.TargetNamespace),
+					node.LatencyConfigs,
+				),
+				opts.ConfigInformers.Config().V1().Nodes(),
+				opts.KubeInformersForNamespaces,
+				opts.EventRecorder,
+			), nil
+		}},
+		{name: "GarbageCollectorWatcherController", new: func() (runner, error) {
+			return gcwatchercontroller.NewGarbageCollectorWatcherController(opts.OperatorClient, opts.KubeInformersForNamespaces, opts.ConfigInformers, opts.KubeClient, opts.EventRecorder, []string{
+				"GarbageCollectorSyncFailed",
+			}), nil
+		}},
+		{name: "DebugController", new: func() (runner, error) {
+			return debugcontroller.NewDebugController(opts.OperatorClient, opts.KubeInformersForNamespaces, opts.KubeClient, opts.InformerResyncPeriods, opts.EventRecorder), nil
+		}},
+		{name: "ManagementStateController", new: func() (runner, error) {
+			versionRecorder, err := b.versionGetter()
+			if err != nil {
+				return nil, err
+			}
+			return managementstatecontroller.NewManagementStateController(
+				opts.OperatorClient,
+				opts.OperatorLister,
+				opts.KubeInformersForNamespaces,
+				opts.KubeClient,
+				renderedConfigMaps(),
+				versionRecorder,
+				opts.EventRecorder,
+			), nil
+		}},
+		{name: "PodJanitorController", new: func() (runner, error) {
+			return podjanitorcontroller.NewPodJanitorController(
+				opts.OperatorClient,
+				opts.KubeInformersForNamespaces,
+				opts.KubeClient,
+				opts.EventRecorder,
+			), nil
+		}},
+		{name: "OperatorSchedulingController", new: func() (runner, error) {
+			return operatorschedulingcontroller.NewOperatorSchedulingController(
+				opts.KubeInformersForNamespaces,
+				opts.KubeClient,
+				opts.EventRecorder,
+			), nil
+		}},
+		{name: "CSRSigningController", new: func() (runner, error) {
+			return csrsigningcontroller.NewCSRSigningController(
+				opts.OperatorClient,
+				opts.KubeInformersForNamespaces,
+				opts.EventRecorder,
+			), nil
+		}},
+		// records the replacements of the csr-signer for the audits
+		{name: "CSRSignerHistoryController", new: func() (runner, error) {
+			return csrsignerhistorycontroller.NewCSRSignerHistoryController(
+				opts.KubeInformersForNamespaces,
+				opts.KubeClient,
+				opts.EventRecorder,
+			), nil
+		}},
+		{name: "CSRSelfTestController", new: func() (runner, error) {
+			return csrselftestcontroller.NewCSRSelfTestController(
+				opts.OperatorClient,
+				opts.OperatorLister,
+				opts.KubeInformersForNamespaces,
+				opts.KubeClient,
+				opts.EventRecorder,
+			), nil
+		}},
+		{name: "MetricsClientCertController", new: func() (runner, error) {
+			return metricsclientcertcontroller.NewMetricsClientCertController(
+				opts.OperatorClient,
+				opts.KubeInformersForNamespaces,
+				opts.KubeClient,
+				opts.EventRecorder,
+			), nil
+		}},
+		{name: "AggregatorClientCAController", new: func() (runner, error) {
+			return aggregatorclientcacontroller.NewAggregatorClientCAController(
+				opts.OperatorClient,
+				opts.KubeInformersForNamespaces,
+				opts.KubeClient,
+				opts.EventRecorder,
+			), nil
+		}},
+		{name: "ClientCertExpiryController", new: func() (runner, error) {
+			return clientcertexpirycontroller.NewClientCertExpiryController(
+				opts.OperatorClient,
+				opts.KubeInformersForNamespaces,
+				opts.KubeClient,
+				opts.EventRecorder,
+			), nil
+		}},
+		{name: "FIPSController", new: func() (runner, error) {
+			return fipscontroller.NewFIPSController(
+				opts.OperatorClient,
+				opts.KubeInformersForNamespaces,
+				opts.EventRecorder,
+			), nil
+		}},
+		{name: "RolloutStatusController", new: func() (runner, error) {
+			return rolloutstatuscontroller.NewRolloutStatusController(
+				opts.OperatorClient,
+				opts.KubeInformersForNamespaces,
+				opts.KubeClient,
+				opts.EventRecorder,
+			), nil
+		}},
+		// publishes the planned downtime of the operand during rollouts for the alerting
+		{name: "DowntimeController", new: func() (runner, error) {
+			return downtimecontroller.NewDowntimeController(
+				opts.OperatorClient,
+				opts.KubeInformersForNamespaces,
+				opts.KubeClient,
+				opts.EventRecorder,
+			), nil
+		}},
+		{name: "RevisionHistoryController", new: func() (runner, error) {
+			return revisionhistorycontroller.NewRevisionHistoryController(
+				deploymentConfigMaps,
+				deploymentSecrets,
+				opts.KubeInformersForNamespaces,
+				opts.KubeClient,
+				opts.EventRecorder,
+			), nil
+		}},
+		{name: "ConfigFingerprintController", new: func() (runner, error) {
+			return configfingerprintcontroller.NewConfigFingerprintController(
+				opts.OperatorClient,
+				opts.KubeInformersForNamespaces,
+				opts.KubeClient,
+				opts.EventRecorder,
+			), nil
+		}},
+		{name: "CrashLoopController", new: func() (runner, error) {
+			return crashloopcontroller.NewCrashLoopController(
+				opts.OperatorClient,
+				opts.KubeInformersForNamespaces,
+				opts.EventRecorder,
+			), nil
+		}},
+		// quotes the logs of failed installer pods in NodeInstallerDegraded
+		{name: "InstallerLogController", new: func() (runner, error) {
+			return installerlogcontroller.NewInstallerLogController(
+				opts.OperatorClient,
+				opts.KubeInformersForNamespaces,
+				opts.KubeClient,
+				opts.EventRecorder,
+			), nil
+		}},
+		// holds new revisions at a canary node until they proved healthy there
+		{name: "CanaryController", new: func() (runner, error) {
+			return canarycontroller.NewCanaryController(
+				opts.OperatorClient,
+				opts.KubeInformersForNamespaces,
+				opts.KubeClient,
+				opts.EventRecorder,
+			), nil
+		}},
+		{name: "RecoveryTokenController", new: func() (runner, error) {
+			return recoverytokencontroller.NewRecoveryTokenController(
+				opts.OperatorClient,
+				opts.KubeInformersForNamespaces,
+				opts.KubeConfigHost,
+				opts.EventRecorder,
+			), nil
+		}},
+		{name: "HostedControlPlaneController", new: func() (runner, error) {
+			return hostedcontrolplanecontroller.NewHostedControlPlaneController(
+				opts.OperatorClient,
+				opts.KubeInformersForNamespaces,
+				opts.KubeClient,
+				opts.ConfigInformers.Config().V1().Infrastructures(),
+				hostedControlPlaneResources(),
+				opts.EventRecorder,
+			), nil
+		}},
+	}
+}
@@ -0,0 +1,60 @@
+package operatorruntime
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/client-go/kubernetes/fake"
+
+	operatorv1 "github.com/openshift/api/operator/v1"
+	"github.com/openshift/library-go/pkg/operator/events"
+	"github.com/openshift/library-go/pkg/operator/v1helpers"
+
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/operatorclient"
+)
+
+func TestControllerNames(t *testing.T) {
+	names := ControllerNames()
+	if unique := sets.New[string](names...); unique.Len() != len(names) {
+		t.Errorf("expected unique controller names, got %v", names)
+	}
+	if !sets.New[string](names...).HasAll("TargetConfigController", "ConfigObserver", "StaticPodControllers", "CertRotationController") {
+		t.Errorf("expected the target config, config observer, static pod and cert rotation controllers, got %v", names)
+	}
+}
+
+func TestNewControllers(t *testing.T) {
+	kubeClient := fake.NewSimpleClientset()
+	opts := Options{
+		KubeClient: kubeClient,
+		OperatorClient: v1helpers.NewFakeStaticPodOperatorClient(
+			&operatorv1.StaticPodOperatorSpec{OperatorSpec: operatorv1.OperatorSpec{ManagementState: operatorv1.Managed}},
+			&operatorv1.StaticPodOperatorStatus{},
+			nil,
+			nil,
+		),
+		KubeInformersForNamespaces: v1helpers.NewKubeInformersForNamespaces(kubeClient, operatorclient.InformerNamespaces...),
+		EventRecorder:              events.NewInMemoryRecorder("test"),
+	}
+
+	opts.Controllers = []string{"CSRSignerHistoryController", "FIPSController"}
+	controllers, err := NewControllers(context.TODO(), opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var names []string
+	for _, controller := range controllers {
+		names = append(names, controller.Name)
+	}
+	// the controllers are built in the order of ControllerNames
+	if strings.Join(names, ",") != "CSRSignerHistoryController,FIPSController" {
+		t.Errorf("expected the selected controllers only, got %v", names)
+	}
+
+	opts.Controllers = []string{"FIPSController", "NoSuchController"}
+	if _, err := NewControllers(context.TODO(), opts); err == nil || !strings.Contains(err.Error(), "NoSuchController") {
+		t.Errorf("expected an error naming the unknown controller, got %v", err)
+	}
+}
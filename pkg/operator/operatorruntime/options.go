@@ -0,0 +1,68 @@
+package operatorruntime
+
+import (
+	"os"
+	"time"
+
+	configv1client "github.com/openshift/client-go/config/clientset/versioned"
+	configinformers "github.com/openshift/client-go/config/informers/externalversions"
+	"github.com/openshift/library-go/pkg/operator/configobserver/featuregates"
+	"github.com/openshift/library-go/pkg/operator/events"
+	"github.com/openshift/library-go/pkg/operator/v1helpers"
+
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/cache"
+)
+
+// Options are the clients, informers and settings the controllers of the operator are built from. The operator binary
+// fills them from its controller context, an embedder like HyperShift or a test harness from its own clients.
+type Options struct {
+	// KubeClient is used for the core resources, the operator uses a protobuf client.
+	KubeClient    kubernetes.Interface
+	ConfigClient  configv1client.Interface
+	DynamicClient dynamic.Interface
+	// KubeConfigHost is the URL of the kube-apiserver written into the localhost recovery kubeconfig.
+	KubeConfigHost string
+
+	// OperatorClient and OperatorLister are for the kubecontrollermanagers.operator.openshift.io/cluster resource.
+	OperatorClient             v1helpers.StaticPodOperatorClient
+	OperatorLister             cache.GenericLister
+	KubeInformersForNamespaces v1helpers.KubeInformersForNamespaces
+	ConfigInformers            configinformers.SharedInformerFactory
+	// FeatureGateAccessor must have observed the initial feature gates before the controllers are built.
+	FeatureGateAccessor featuregates.FeatureGateAccess
+	EventRecorder       events.Recorder
+
+	// Images are the images of the operand and of the pods the operator runs.
+	Images Images
+	// AssetOverridesEnabled lets the TargetConfigController render from the asset overrides of the operator namespace.
+	AssetOverridesEnabled bool
+	// SecurePort is the secure port of the kube-controller-manager, see tuning.Config.SecurePortOrDefault.
+	SecurePort int32
+	// InformerResyncPeriods are the resync periods by namespace reported by the DebugController.
+	InformerResyncPeriods map[string]time.Duration
+	// ForceCertRecovery keeps the operator in the cert recovery mode, see certrecovery.Detector.
+	ForceCertRecovery bool
+
+	// Controllers are the names of the controllers to build, see ControllerNames. All of them are built when empty.
+	Controllers []string
+}
+
+// Images are the images the operator renders into the operand and its own pods.
+type Images struct {
+	KubeControllerManager   string
+	Operator                string
+	ClusterPolicyController string
+	Tools                   string
+}
+
+// ImagesFromEnv returns the images set in the environment of the operator.
+func ImagesFromEnv() Images {
+	return Images{
+		KubeControllerManager:   os.Getenv("IMAGE"),
+		Operator:                os.Getenv("OPERATOR_IMAGE"),
+		ClusterPolicyController: os.Getenv("CLUSTER_POLICY_CONTROLLER_IMAGE"),
+		Tools:                   os.Getenv("TOOLS_IMAGE"),
+	}
+}
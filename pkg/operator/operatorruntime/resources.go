@@ -0,0 +1,202 @@
+package operatorruntime
+
+import (
+	"bytes"
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/util/sets"
+
+	configv1 "github.com/openshift/api/config/v1"
+	configinformersv1 "github.com/openshift/client-go/config/informers/externalversions/config/v1"
+	"github.com/openshift/cluster-kube-controller-manager-operator/bindata"
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/canarycontroller"
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/hostedcontrolplanecontroller"
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/operatorclient"
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/ownershipcontroller"
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/tuning"
+	"github.com/openshift/library-go/pkg/operator/resource/resourceapply"
+	"github.com/openshift/library-go/pkg/operator/staticpod/controller/installer"
+	"github.com/openshift/library-go/pkg/operator/staticpod/controller/revision"
+)
+
+// deploymentConfigMaps is a list of configmaps that are directly copied for the current values.  A different actor/controller modifies these.
+// the first element should be the configmap that contains the static pod manifest
+var deploymentConfigMaps = []revision.RevisionResource{
+	{Name: "kube-controller-manager-pod"},
+
+	{Name: "config"},
+	{Name: "cluster-policy-controller-config"},
+	{Name: "controller-manager-kubeconfig"},
+	// the CA bundle of the kubeconfig when the tuning configmap names an internal API server CA
+	{Name: "controller-manager-kubeconfig-ca", Optional: true},
+	{Name: "cloud-config", Optional: true},
+	{Name: "kube-controller-cert-syncer-kubeconfig"},
+	{Name: "serviceaccount-ca"},
+	{Name: "serviceaccount-root-ca", Optional: true},
+	{Name: "service-ca"},
+	{Name: "recycler-config"},
+	{Name: "extra-mounts", Optional: true},
+	// the kube-controller-manager flags when the tuning configmap selects the flags file
+	{Name: "kube-controller-manager-flags", Optional: true},
+	// the client certificate of the kubeconfig a revision was rolled out with, see the ClientCertExpiryController
+	{Name: "client-cert-expiry", Optional: true},
+}
+
+// deploymentSecrets is a list of secrets that are directly copied for the current values.  A different actor/controller modifies these.
+var deploymentSecrets = []revision.RevisionResource{
+	{Name: "service-account-private-key"},
+
+	// this cert is created by the service-ca controller, which doesn't come up until after we are available. this piece of config must be optional.
+	{Name: "serving-cert", Optional: true},
+
+	// this needs to be revisioned as certsyncer's kubeconfig isn't wired to be live reloaded, nor will be autorecovery
+	{Name: "localhost-recovery-client-token"},
+
+	// the secret sources of the tuning extraMounts
+	{Name: "extra-mounts", Optional: true},
+}
+
+var CertConfigMaps = []installer.UnrevisionedResource{
+	{Name: "aggregator-client-ca"},
+	{Name: "client-ca"},
+
+	// this is a copy of trusted-ca-bundle CM but with key modified to "tls-ca-bundle.pem" so that we can mount it the way we need
+	{Name: "trusted-ca-bundle", Optional: true},
+}
+
+var CertSecrets = []installer.UnrevisionedResource{
+	{Name: "kube-controller-manager-client-cert-key"},
+	{Name: "csr-signer"},
+
+	// this cert is created by the service-ca controller for the metrics service and is selected through SNI.
+	// It is not revisioned, so its rotation doesn't restart the operand and interrupt the scraping.
+	{Name: "metrics-serving-cert", Optional: true},
+}
+
+// ManagedResources lists the configmaps and secrets of the target namespace the operand is started from, the revisioned
+// ones by the name of their current copy.
+func ManagedResources() (configMaps, secrets []installer.UnrevisionedResource) {
+	for _, configMap := range deploymentConfigMaps {
+		configMaps = append(configMaps, installer.UnrevisionedResource{Name: configMap.Name, Optional: configMap.Optional})
+	}
+	configMaps = append(configMaps, CertConfigMaps...)
+	for _, secret := range deploymentSecrets {
+		secrets = append(secrets, installer.UnrevisionedResource{Name: secret.Name, Optional: secret.Optional})
+	}
+	secrets = append(secrets, CertSecrets...)
+	return configMaps, secrets
+}
+
+const (
+	operatorResource = "kubecontrollermanager.operator.openshift.io/cluster"
+	tuningConfigMap  = "configmap/" + operatorclient.OperatorNamespace + "/" + tuning.ConfigMapName
+)
+
+// managedResourceOwners are the configmaps and secrets of the target namespace the operator writes, with the controller
+// writing them and their inputs. The serving certificates are written by the service-ca operator and are not listed.
+var managedResourceOwners = []ownershipcontroller.Resource{
+	{Kind: ownershipcontroller.ConfigMap, Name: "kube-controller-manager-pod", Controller: "TargetConfigController", Sources: []string{operatorResource, tuningConfigMap}},
+	{Kind: ownershipcontroller.ConfigMap, Name: "config", Controller: "TargetConfigController", Sources: []string{operatorResource, tuningConfigMap}},
+	{Kind: ownershipcontroller.ConfigMap, Name: "cluster-policy-controller-config", Controller: "TargetConfigController", Sources: []string{operatorResource, tuningConfigMap}},
+	{Kind: ownershipcontroller.ConfigMap, Name: "kube-controller-manager-flags", Controller: "TargetConfigController", Sources: []string{operatorResource, tuningConfigMap}},
+	{Kind: ownershipcontroller.ConfigMap, Name: "recycler-config", Controller: "TargetConfigController", Sources: []string{operatorResource}},
+	{Kind: ownershipcontroller.ConfigMap, Name: "extra-mounts", Controller: "TargetConfigController", Sources: []string{tuningConfigMap}},
+	{Kind: ownershipcontroller.ConfigMap, Name: "kube-controller-cert-syncer-kubeconfig", Controller: "TargetConfigController", Sources: []string{operatorResource}},
+	{Kind: ownershipcontroller.ConfigMap, Name: "serviceaccount-ca", Controller: "TargetConfigController", Sources: []string{
+		"configmap/" + operatorclient.GlobalMachineSpecifiedConfigNamespace + "/kube-apiserver-server-ca",
+		"configmap/" + operatorclient.GlobalMachineSpecifiedConfigNamespace + "/default-ingress-cert",
+		tuningConfigMap,
+	}},
+	{Kind: ownershipcontroller.ConfigMap, Name: "serviceaccount-root-ca", Controller: "TargetConfigController", Sources: []string{tuningConfigMap}},
+	{Kind: ownershipcontroller.ConfigMap, Name: "trusted-ca-bundle", Controller: "TargetConfigController", Sources: []string{"proxy.config.openshift.io/cluster"}},
+	{Kind: ownershipcontroller.ConfigMap, Name: "controller-manager-kubeconfig", Controller: "KubeconfigController", Sources: []string{"infrastructure.config.openshift.io/cluster"}},
+	{Kind: ownershipcontroller.ConfigMap, Name: "controller-manager-kubeconfig-ca", Controller: "KubeconfigController", Sources: []string{
+		"configmap/" + operatorclient.GlobalMachineSpecifiedConfigNamespace + "/kube-apiserver-server-ca",
+		tuningConfigMap,
+	}},
+	{Kind: ownershipcontroller.ConfigMap, Name: "cloud-config", Controller: "ConfigObserver", Sources: []string{"infrastructure.config.openshift.io/cluster"}},
+	{Kind: ownershipcontroller.ConfigMap, Name: "service-ca", Controller: "ResourceSyncController", Sources: []string{"configmap/" + operatorclient.GlobalMachineSpecifiedConfigNamespace + "/service-ca"}},
+	{Kind: ownershipcontroller.ConfigMap, Name: "client-ca", Controller: "ResourceSyncController", Sources: []string{"configmap/" + operatorclient.GlobalMachineSpecifiedConfigNamespace + "/kube-apiserver-client-ca"}},
+	{Kind: ownershipcontroller.ConfigMap, Name: "aggregator-client-ca", Controller: "AggregatorClientCAController", Sources: []string{"configmap/" + operatorclient.GlobalMachineSpecifiedConfigNamespace + "/kube-apiserver-aggregator-client-ca"}},
+	{Kind: ownershipcontroller.ConfigMap, Name: canarycontroller.GateConfigMapName, Controller: "CanaryController", Sources: []string{operatorResource, tuningConfigMap}},
+	{Kind: ownershipcontroller.ConfigMap, Name: "client-cert-expiry", Controller: "ClientCertExpiryController", Sources: []string{"secret/" + operatorclient.TargetNamespace + "/kube-controller-manager-client-cert-key"}},
+
+	{Kind: ownershipcontroller.Secret, Name: "service-account-private-key", Controller: "SATokenSignerController", Sources: []string{"secret/" + operatorclient.OperatorNamespace + "/next-service-account-private-key"}},
+	{Kind: ownershipcontroller.Secret, Name: "localhost-recovery-client-token", Controller: "RecoveryTokenController", Sources: []string{"serviceaccount/" + operatorclient.TargetNamespace + "/localhost-recovery-client"}},
+	{Kind: ownershipcontroller.Secret, Name: "extra-mounts", Controller: "TargetConfigController", Sources: []string{tuningConfigMap}},
+	{Kind: ownershipcontroller.Secret, Name: "kube-controller-manager-client-cert-key", Controller: "ResourceSyncController", Sources: []string{"secret/" + operatorclient.GlobalMachineSpecifiedConfigNamespace + "/kube-controller-manager-client-cert-key"}},
+	{Kind: ownershipcontroller.Secret, Name: "csr-signer", Controller: "TargetConfigController", Sources: []string{"secret/" + operatorclient.OperatorNamespace + "/csr-signer"}},
+}
+
+// copyingControllers write copies of resources managed by other operators rather than render them.
+var copyingControllers = sets.New("ResourceSyncController", "AggregatorClientCAController", "ConfigObserver")
+
+// renderedConfigMaps are the configmaps of managedResourceOwners the operator renders from its own inputs, which the
+// ManagementStateController deletes on removal. The copies are left to their sources.
+func renderedConfigMaps() []string {
+	var names []string
+	for _, resource := range managedResourceOwners {
+		if resource.Kind == ownershipcontroller.ConfigMap && !copyingControllers.Has(resource.Controller) {
+			names = append(names, resource.Name)
+		}
+	}
+	return names
+}
+
+// hostedControlPlaneResources lists the same operand inputs as the static pod installer, minus the pod manifest
+// that is rendered into the deployment itself.
+func hostedControlPlaneResources() hostedcontrolplanecontroller.Resources {
+	resources := hostedcontrolplanecontroller.Resources{}
+	for _, configMap := range deploymentConfigMaps {
+		if configMap.Name == "kube-controller-manager-pod" {
+			continue
+		}
+		resources.ConfigMaps = append(resources.ConfigMaps, hostedcontrolplanecontroller.Resource{Name: configMap.Name, Optional: configMap.Optional})
+	}
+	for _, secret := range deploymentSecrets {
+		resources.Secrets = append(resources.Secrets, hostedcontrolplanecontroller.Resource{Name: secret.Name, Optional: secret.Optional})
+	}
+	for _, configMap := range CertConfigMaps {
+		resources.CertConfigMaps = append(resources.CertConfigMaps, hostedcontrolplanecontroller.Resource{Name: configMap.Name, Optional: configMap.Optional})
+	}
+	for _, secret := range CertSecrets {
+		resources.CertSecrets = append(resources.CertSecrets, hostedcontrolplanecontroller.Resource{Name: secret.Name, Optional: secret.Optional})
+	}
+	return resources
+}
+
+// securePortAssets points the target port of the services of the kube-controller-manager at its secure port.
+func securePortAssets(securePort int32) resourceapply.AssetFunc {
+	return func(name string) ([]byte, error) {
+		asset, err := bindata.Asset(name)
+		if err != nil {
+			return nil, err
+		}
+		switch name {
+		case "assets/kube-controller-manager/svc.yaml", "assets/kube-controller-manager/metrics-svc.yaml":
+			return bytes.ReplaceAll(asset, []byte(fmt.Sprintf("targetPort: %d", tuning.DefaultSecurePort)), []byte(fmt.Sprintf("targetPort: %d", securePort))), nil
+		}
+		return asset, nil
+	}
+}
+
+// newPlatformMatcherFn returns a function that checks if the cluster PlatformType matches with the passed one.
+// In case if err is nil, precheckSucceeded signifies whether the `matched` is valid.
+// If precheckSucceeded is false, the `matched` return value does not reflect if the cluster platform type matches on not.
+func newPlatformMatcherFn(platform configv1.PlatformType, infraInformer configinformersv1.InfrastructureInformer) func() (matched, preconditionFulfilled bool, err error) {
+	return func() (matched, precheckSucceeded bool, err error) {
+		if !infraInformer.Informer().HasSynced() {
+			// Do not return transient error
+			return false, false, nil
+		}
+		infraData, err := infraInformer.Lister().Get("cluster")
+		if err != nil {
+			return false, true, fmt.Errorf("Unable to list infrastructures.config.openshift.io/cluster object, unable to determine platform type")
+		}
+		if infraData.Status.PlatformStatus.Type == "" {
+			return false, true, fmt.Errorf("PlatformType was not set, unable to determine platform type")
+		}
+
+		return infraData.Status.PlatformStatus.Type == platform, true, nil
+	}
+}
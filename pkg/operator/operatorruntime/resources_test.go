@@ -0,0 +1,247 @@
+package operatorruntime
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"io/fs"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/util/sets"
+
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/operatorclient"
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/tuning"
+)
+
+func TestRenderedConfigMaps(t *testing.T) {
+	names := sets.New(renderedConfigMaps()...)
+	// every configmap rendered by the TargetConfigController is cleaned up on removal, the copies are not
+	for _, name := range []string{"kube-controller-manager-pod", "kube-controller-manager-flags", "kube-controller-cert-syncer-kubeconfig", "controller-manager-kubeconfig", "serviceaccount-ca"} {
+		if !names.Has(name) {
+			t.Errorf("expected configmap/%s to be deleted on removal, got %v", name, sets.List(names))
+		}
+	}
+	for _, name := range []string{"client-ca", "aggregator-client-ca", "service-ca", "cloud-config"} {
+		if names.Has(name) {
+			t.Errorf("expected the copy configmap/%s to be kept on removal", name)
+		}
+	}
+}
+
+func TestHostedControlPlaneResources(t *testing.T) {
+	names := sets.New[string]()
+	for _, configMap := range hostedControlPlaneResources().ConfigMaps {
+		names.Insert(configMap.Name)
+	}
+	// the pod manifest is rendered into the deployment, the other inputs are copied
+	if names.Has("kube-controller-manager-pod") {
+		t.Errorf("expected the pod manifest to be left out, got %v", sets.List(names))
+	}
+	for _, name := range []string{"config", "serviceaccount-ca", "recycler-config"} {
+		if !names.Has(name) {
+			t.Errorf("expected configmap/%s to be copied, got %v", name, sets.List(names))
+		}
+	}
+}
+
+// TestCachedDataAllowlist checks that the configmaps and secrets of openshift-config-managed the controllers read are
+// cached with their data. The reads are found in the sources of pkg/operator: the Get calls of the listers and clients
+// of the namespace, the ResourceLocations of the namespace and the sources of the ownership table.
+func TestCachedDataAllowlist(t *testing.T) {
+	// the names read from variables, by the expression reading them
+	variableReads := map[string][]string{
+		"source.Name": nil,
+	}
+	for _, source := range tuning.ServiceAccountCABundleSources {
+		variableReads["source.Name"] = append(variableReads["source.Name"], source.Name)
+	}
+
+	configMaps, secrets, variables := machineSpecifiedConfigReads(t, "..")
+	for expression, positions := range variables {
+		names, ok := variableReads[expression]
+		if !ok {
+			t.Errorf("%s: cannot tell the names %s reads from openshift-config-managed, add them to variableReads", strings.Join(positions, ", "), expression)
+		}
+		configMaps.Insert(names...)
+	}
+	if configMaps.Len() == 0 || secrets.Len() == 0 {
+		t.Fatalf("expected reads of openshift-config-managed, found configmaps %v and secrets %v", sets.List(configMaps), sets.List(secrets))
+	}
+	for _, name := range sets.List(configMaps) {
+		if !operatorclient.CachedDataConfigMaps.Has(name) {
+			t.Errorf("configmap/%s of openshift-config-managed is read but missing from operatorclient.CachedDataConfigMaps", name)
+		}
+	}
+	for _, name := range sets.List(secrets) {
+		if !operatorclient.CachedDataSecrets.Has(name) {
+			t.Errorf("secret/%s of openshift-config-managed is read but missing from operatorclient.CachedDataSecrets", name)
+		}
+	}
+}
+
+// machineSpecifiedConfigReads returns the names of the configmaps and secrets of GlobalMachineSpecifiedConfigNamespace
+// read by the sources under root, and the positions of the reads of names that are not string constants, by the
+// expression of the name.
+func machineSpecifiedConfigReads(t *testing.T, root string) (sets.Set[string], sets.Set[string], map[string][]string) {
+	fileSet := token.NewFileSet()
+	var files []*ast.File
+	err := filepath.WalkDir(root, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil || entry.IsDir() || !strings.HasSuffix(path, ".go") || strings.HasSuffix(path, "_test.go") {
+			return err
+		}
+		file, err := parser.ParseFile(fileSet, path, nil, 0)
+		if err != nil {
+			return err
+		}
+		files = append(files, file)
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// the string constants, by package and name
+	constants := map[string]string{}
+	for _, file := range files {
+		for _, decl := range file.Decls {
+			genDecl, ok := decl.(*ast.GenDecl)
+			if !ok || genDecl.Tok != token.CONST {
+				continue
+			}
+			for _, spec := range genDecl.Specs {
+				valueSpec := spec.(*ast.ValueSpec)
+				for i, name := range valueSpec.Names {
+					if i >= len(valueSpec.Values) {
+						continue
+					}
+					if value, ok := stringLiteral(valueSpec.Values[i]); ok {
+						constants[file.Name.Name+"."+name.Name] = value
+					}
+				}
+			}
+		}
+	}
+
+	configMaps, secrets, variables := sets.New[string](), sets.New[string](), map[string][]string{}
+	for _, file := range files {
+		pkg := file.Name.Name
+		read := func(secret bool, expr ast.Expr) {
+			name, ok := stringLiteral(expr)
+			switch expr := expr.(type) {
+			case *ast.Ident:
+				name, ok = constants[pkg+"."+expr.Name]
+			case *ast.SelectorExpr:
+				if x, isIdent := expr.X.(*ast.Ident); isIdent {
+					name, ok = constants[x.Name+"."+expr.Sel.Name]
+				}
+			}
+			switch {
+			case !ok:
+				variables[types.ExprString(expr)] = append(variables[types.ExprString(expr)], fileSet.Position(expr.Pos()).String())
+			case secret:
+				secrets.Insert(name)
+			default:
+				configMaps.Insert(name)
+			}
+		}
+
+		var stack []ast.Node
+		ast.Inspect(file, func(node ast.Node) bool {
+			if node == nil {
+				stack = stack[:len(stack)-1]
+				return true
+			}
+			stack = append(stack, node)
+
+			switch node := node.(type) {
+			case *ast.CallExpr:
+				// <lister or client>.ConfigMaps(namespace).Get([ctx, ]name[, options])
+				get, ok := node.Fun.(*ast.SelectorExpr)
+				if !ok || get.Sel.Name != "Get" || len(node.Args) == 0 {
+					return true
+				}
+				namespaced, ok := get.X.(*ast.CallExpr)
+				if !ok || len(namespaced.Args) != 1 || !isMachineSpecifiedConfigNamespace(namespaced.Args[0]) {
+					return true
+				}
+				resource, ok := namespaced.Fun.(*ast.SelectorExpr)
+				if !ok || (resource.Sel.Name != "ConfigMaps" && resource.Sel.Name != "Secrets") {
+					return true
+				}
+				name := node.Args[0]
+				if len(node.Args) == 3 {
+					name = node.Args[1]
+				}
+				read(resource.Sel.Name == "Secrets", name)
+			case *ast.CompositeLit:
+				// ResourceLocation{Namespace: namespace, Name: name}, of a secret when passed to a *Secret* call
+				if !strings.HasSuffix(types.ExprString(node.Type), "ResourceLocation") {
+					return true
+				}
+				var namespace, name ast.Expr
+				for _, elt := range node.Elts {
+					if field, ok := elt.(*ast.KeyValueExpr); ok {
+						switch types.ExprString(field.Key) {
+						case "Namespace":
+							namespace = field.Value
+						case "Name":
+							name = field.Value
+						}
+					}
+				}
+				if namespace == nil || name == nil || !isMachineSpecifiedConfigNamespace(namespace) {
+					return true
+				}
+				secret := false
+				for i := len(stack) - 1; i >= 0; i-- {
+					if call, ok := stack[i].(*ast.CallExpr); ok {
+						secret = strings.Contains(types.ExprString(call.Fun), "Secret")
+						break
+					}
+				}
+				read(secret, name)
+			case *ast.BinaryExpr:
+				// "configmap/" + namespace + "/name" of the ownership table
+				prefix, ok := node.X.(*ast.BinaryExpr)
+				if !ok || !isMachineSpecifiedConfigNamespace(prefix.Y) {
+					return true
+				}
+				kind, ok := stringLiteral(prefix.X)
+				if !ok {
+					return true
+				}
+				name, ok := stringLiteral(node.Y)
+				if !ok || !strings.HasPrefix(name, "/") {
+					return true
+				}
+				read(kind == "secret/", &ast.BasicLit{Kind: token.STRING, Value: strconv.Quote(strings.TrimPrefix(name, "/"))})
+			}
+			return true
+		})
+	}
+	return configMaps, secrets, variables
+}
+
+func isMachineSpecifiedConfigNamespace(expr ast.Expr) bool {
+	switch expr := expr.(type) {
+	case *ast.Ident:
+		return expr.Name == "GlobalMachineSpecifiedConfigNamespace"
+	case *ast.SelectorExpr:
+		return expr.Sel.Name == "GlobalMachineSpecifiedConfigNamespace"
+	}
+	value, ok := stringLiteral(expr)
+	return ok && value == operatorclient.GlobalMachineSpecifiedConfigNamespace
+}
+
+func stringLiteral(expr ast.Expr) (string, bool) {
+	literal, ok := expr.(*ast.BasicLit)
+	if !ok || literal.Kind != token.STRING {
+		return "", false
+	}
+	value, err := strconv.Unquote(literal.Value)
+	return value, err == nil
+}
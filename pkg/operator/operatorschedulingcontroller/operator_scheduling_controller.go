@@ -0,0 +1,170 @@
+package operatorschedulingcontroller
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	appsv1client "k8s.io/client-go/kubernetes/typed/apps/v1"
+	appsv1listers "k8s.io/client-go/listers/apps/v1"
+	corev1listers "k8s.io/client-go/listers/core/v1"
+
+	"github.com/openshift/library-go/pkg/controller/factory"
+	"github.com/openshift/library-go/pkg/operator/events"
+	"github.com/openshift/library-go/pkg/operator/v1helpers"
+
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/operatorclient"
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/tuning"
+)
+
+const (
+	// OperatorDeploymentName is the deployment of the operator, installed by the cluster-version-operator.
+	OperatorDeploymentName = "kube-controller-manager-operator"
+
+	// AppliedSchedulingAnnotation records the node selector and tolerations the operator added to its deployment, so
+	// that they are removed again once they are dropped from the tuning config. The entries of the manifest are never
+	// touched.
+	AppliedSchedulingAnnotation = "kubecontrollermanager.operator.openshift.io/applied-scheduling"
+)
+
+// OperatorSchedulingController adds the node selector and tolerations of the tuning config to the deployment of the
+// operator. The cluster-version-operator only ensures the entries of the manifest are present, the added ones are
+// kept. The installer pods need neither: they are bound to the node they install the operand on and tolerate every
+// taint.
+type OperatorSchedulingController struct {
+	deploymentClient appsv1client.DeploymentsGetter
+	deploymentLister appsv1listers.DeploymentLister
+	configMapLister  corev1listers.ConfigMapLister
+}
+
+func NewOperatorSchedulingController(
+	kubeInformersForNamespaces v1helpers.KubeInformersForNamespaces,
+	kubeClient kubernetes.Interface,
+	eventRecorder events.Recorder,
+) factory.Controller {
+	deployments := kubeInformersForNamespaces.InformersFor(operatorclient.OperatorNamespace).Apps().V1().Deployments()
+	c := &OperatorSchedulingController{
+		deploymentClient: kubeClient.AppsV1(),
+		deploymentLister: deployments.Lister(),
+		configMapLister:  kubeInformersForNamespaces.ConfigMapLister(),
+	}
+
+	return factory.New().WithInformers(
+		deployments.Informer(),
+		// the tuning config holds the scheduling
+		kubeInformersForNamespaces.InformersFor(operatorclient.OperatorNamespace).Core().V1().ConfigMaps().Informer(),
+	).ResyncEvery(10*time.Minute).WithSync(c.sync).ToController("OperatorSchedulingController", eventRecorder)
+}
+
+func (c *OperatorSchedulingController) sync(ctx context.Context, syncCtx factory.SyncContext) error {
+	tuningConfig, err := tuning.Get(c.configMapLister)
+	if err != nil {
+		return err
+	}
+	deployment, err := c.deploymentLister.Deployments(operatorclient.OperatorNamespace).Get(OperatorDeploymentName)
+	if apierrors.IsNotFound(err) {
+		// the operator runs outside of its deployment, e.g. while it is debugged locally
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	required, changed, err := applyScheduling(deployment, tuningConfig.Scheduling)
+	if err != nil || !changed {
+		return err
+	}
+	if _, err := c.deploymentClient.Deployments(required.Namespace).Update(ctx, required, metav1.UpdateOptions{}); err != nil {
+		return err
+	}
+	syncCtx.Recorder().Eventf("OperatorSchedulingChanged", "Changed the scheduling of deployment/%s: node selector %v, %d tolerations", required.Name, required.Spec.Template.Spec.NodeSelector, len(required.Spec.Template.Spec.Tolerations))
+	return nil
+}
+
+// applyScheduling returns a copy of the deployment with the entries added before replaced by the ones of the
+// scheduling. Entries the manifest sets already are left alone, a node selector key of the manifest is not overridden.
+func applyScheduling(deployment *appsv1.Deployment, scheduling tuning.SchedulingConfig) (*appsv1.Deployment, bool, error) {
+	previous := tuning.SchedulingConfig{}
+	if value, ok := deployment.Annotations[AppliedSchedulingAnnotation]; ok {
+		if err := json.Unmarshal([]byte(value), &previous); err != nil {
+			return nil, false, fmt.Errorf("invalid %s annotation of deployment/%s: %v", AppliedSchedulingAnnotation, deployment.Name, err)
+		}
+	}
+
+	required := deployment.DeepCopy()
+	podSpec := &required.Spec.Template.Spec
+	for key, value := range previous.NodeSelector {
+		if podSpec.NodeSelector[key] == value {
+			delete(podSpec.NodeSelector, key)
+		}
+	}
+	podSpec.Tolerations = removeTolerations(podSpec.Tolerations, previous.Tolerations)
+
+	added := tuning.SchedulingConfig{}
+	for key, value := range scheduling.NodeSelector {
+		if _, ok := podSpec.NodeSelector[key]; ok {
+			continue
+		}
+		if podSpec.NodeSelector == nil {
+			podSpec.NodeSelector = map[string]string{}
+		}
+		podSpec.NodeSelector[key] = value
+		if added.NodeSelector == nil {
+			added.NodeSelector = map[string]string{}
+		}
+		added.NodeSelector[key] = value
+	}
+	for _, toleration := range scheduling.Tolerations {
+		if !hasToleration(podSpec.Tolerations, toleration) {
+			podSpec.Tolerations = append(podSpec.Tolerations, toleration)
+			added.Tolerations = append(added.Tolerations, toleration)
+		}
+	}
+
+	if len(added.NodeSelector) == 0 && len(added.Tolerations) == 0 {
+		delete(required.Annotations, AppliedSchedulingAnnotation)
+	} else {
+		value, err := json.Marshal(added)
+		if err != nil {
+			return nil, false, err
+		}
+		if required.Annotations == nil {
+			required.Annotations = map[string]string{}
+		}
+		required.Annotations[AppliedSchedulingAnnotation] = string(value)
+	}
+
+	changed := !equality.Semantic.DeepEqual(deployment.Spec.Template.Spec.NodeSelector, podSpec.NodeSelector) ||
+		!equality.Semantic.DeepEqual(deployment.Spec.Template.Spec.Tolerations, podSpec.Tolerations) ||
+		deployment.Annotations[AppliedSchedulingAnnotation] != required.Annotations[AppliedSchedulingAnnotation]
+	return required, changed, nil
+}
+
+func hasToleration(tolerations []corev1.Toleration, toleration corev1.Toleration) bool {
+	for i := range tolerations {
+		if equality.Semantic.DeepEqual(tolerations[i], toleration) {
+			return true
+		}
+	}
+	return false
+}
+
+func removeTolerations(tolerations, removed []corev1.Toleration) []corev1.Toleration {
+	if len(removed) == 0 {
+		return tolerations
+	}
+	kept := []corev1.Toleration{}
+	for _, toleration := range tolerations {
+		if !hasToleration(removed, toleration) {
+			kept = append(kept, toleration)
+		}
+	}
+	return kept
+}
@@ -0,0 +1,128 @@
+package operatorschedulingcontroller
+
+import (
+	"context"
+	"testing"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+	appsv1listers "k8s.io/client-go/listers/apps/v1"
+	corev1listers "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/tools/cache"
+
+	"github.com/openshift/library-go/pkg/controller/factory"
+	"github.com/openshift/library-go/pkg/operator/events"
+
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/operatorclient"
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/tuning"
+)
+
+var (
+	masterToleration = corev1.Toleration{Key: "node-role.kubernetes.io/master", Operator: corev1.TolerationOpExists, Effect: corev1.TaintEffectNoSchedule}
+	infraToleration  = corev1.Toleration{Key: "node-role.kubernetes.io/infra", Operator: corev1.TolerationOpExists, Effect: corev1.TaintEffectNoSchedule}
+)
+
+func newDeployment() *appsv1.Deployment {
+	return &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{Namespace: operatorclient.OperatorNamespace, Name: OperatorDeploymentName},
+		Spec: appsv1.DeploymentSpec{
+			Template: corev1.PodTemplateSpec{
+				Spec: corev1.PodSpec{
+					NodeSelector: map[string]string{"node-role.kubernetes.io/master": ""},
+					Tolerations:  []corev1.Toleration{masterToleration},
+				},
+			},
+		},
+	}
+}
+
+func TestApplyScheduling(t *testing.T) {
+	scheduling := tuning.SchedulingConfig{
+		NodeSelector: map[string]string{
+			"node-role.kubernetes.io/infra":  "",
+			"node-role.kubernetes.io/master": "ignored",
+		},
+		Tolerations: []corev1.Toleration{masterToleration, infraToleration},
+	}
+
+	applied, changed, err := applyScheduling(newDeployment(), scheduling)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !changed {
+		t.Fatalf("expected the deployment to change")
+	}
+	podSpec := applied.Spec.Template.Spec
+	if expected := map[string]string{"node-role.kubernetes.io/master": "", "node-role.kubernetes.io/infra": ""}; !equality.Semantic.DeepEqual(podSpec.NodeSelector, expected) {
+		t.Errorf("expected the node selector %v, got %v", expected, podSpec.NodeSelector)
+	}
+	if expected := []corev1.Toleration{masterToleration, infraToleration}; !equality.Semantic.DeepEqual(podSpec.Tolerations, expected) {
+		t.Errorf("expected the tolerations %v, got %v", expected, podSpec.Tolerations)
+	}
+
+	if _, changed, err := applyScheduling(applied, scheduling); err != nil || changed {
+		t.Errorf("expected no change on the second sync, got changed=%v err=%v", changed, err)
+	}
+
+	// dropping the scheduling removes what was added, the entries of the manifest stay
+	removed, changed, err := applyScheduling(applied, tuning.SchedulingConfig{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !changed {
+		t.Fatalf("expected the deployment to change")
+	}
+	if expected := newDeployment(); !equality.Semantic.DeepEqual(removed.Spec, expected.Spec) {
+		t.Errorf("expected the manifest scheduling, got %v and %v", removed.Spec.Template.Spec.NodeSelector, removed.Spec.Template.Spec.Tolerations)
+	}
+	if _, ok := removed.Annotations[AppliedSchedulingAnnotation]; ok {
+		t.Errorf("expected the %s annotation to be removed", AppliedSchedulingAnnotation)
+	}
+}
+
+func TestSync(t *testing.T) {
+	deployment := newDeployment()
+	configMapIndexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc})
+	if err := configMapIndexer.Add(&corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Namespace: operatorclient.OperatorNamespace, Name: tuning.ConfigMapName},
+		Data: map[string]string{tuning.ConfigKey: `scheduling:
+  nodeSelector:
+    node-role.kubernetes.io/infra: ""
+  tolerations:
+  - key: node-role.kubernetes.io/infra
+    operator: Exists
+    effect: NoSchedule
+`},
+	}); err != nil {
+		t.Fatal(err)
+	}
+	deploymentIndexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc})
+	if err := deploymentIndexer.Add(deployment); err != nil {
+		t.Fatal(err)
+	}
+	kubeClient := fake.NewSimpleClientset(deployment)
+
+	c := &OperatorSchedulingController{
+		deploymentClient: kubeClient.AppsV1(),
+		deploymentLister: appsv1listers.NewDeploymentLister(deploymentIndexer),
+		configMapLister:  corev1listers.NewConfigMapLister(configMapIndexer),
+	}
+	syncCtx := factory.NewSyncContext("test", events.NewInMemoryRecorder("test"))
+	if err := c.sync(context.TODO(), syncCtx); err != nil {
+		t.Fatal(err)
+	}
+
+	updated, err := kubeClient.AppsV1().Deployments(operatorclient.OperatorNamespace).Get(context.TODO(), OperatorDeploymentName, metav1.GetOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := updated.Spec.Template.Spec.NodeSelector["node-role.kubernetes.io/infra"]; !ok {
+		t.Errorf("expected the infra node selector, got %v", updated.Spec.Template.Spec.NodeSelector)
+	}
+	if expected := []corev1.Toleration{masterToleration, infraToleration}; !equality.Semantic.DeepEqual(updated.Spec.Template.Spec.Tolerations, expected) {
+		t.Errorf("expected the tolerations %v, got %v", expected, updated.Spec.Template.Spec.Tolerations)
+	}
+}
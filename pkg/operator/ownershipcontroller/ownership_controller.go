@@ -0,0 +1,261 @@
+package ownershipcontroller
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"sort"
+	"strings"
+	"time"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+	"k8s.io/client-go/kubernetes"
+	corev1client "k8s.io/client-go/kubernetes/typed/core/v1"
+	corev1listers "k8s.io/client-go/listers/core/v1"
+
+	"github.com/openshift/library-go/pkg/controller/factory"
+	"github.com/openshift/library-go/pkg/operator/events"
+	"github.com/openshift/library-go/pkg/operator/management"
+	"github.com/openshift/library-go/pkg/operator/v1helpers"
+
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/operatorclient"
+)
+
+const (
+	// ManagedLabel marks the configmaps and secrets of the target namespace the operator owns, so that backup and
+	// GitOps tooling can select them.
+	ManagedLabel = "kubecontrollermanager.operator.openshift.io/managed"
+
+	// ManagedByAnnotation names the controller of the operator that writes the resource.
+	ManagedByAnnotation = "kubecontrollermanager.operator.openshift.io/managed-by"
+
+	// SourceInputsAnnotation lists the resources the content is rendered or copied from, comma separated.
+	SourceInputsAnnotation = "kubecontrollermanager.operator.openshift.io/source-inputs"
+
+	// LastSyncHashAnnotation is the hash of the data of the resource when it was last stamped.
+	LastSyncHashAnnotation = "kubecontrollermanager.operator.openshift.io/last-sync-hash"
+)
+
+// Kinds of the managed resources.
+const (
+	ConfigMap = "ConfigMap"
+	Secret    = "Secret"
+)
+
+// Resource is a configmap or secret of the target namespace the operator owns.
+type Resource struct {
+	Kind       string
+	Name       string
+	Controller string
+	// Sources are the inputs of the content, as kind/name for cluster scoped and kind/namespace/name for namespaced
+	// resources.
+	Sources []string
+}
+
+// OwnershipController stamps the ownership label and annotations on the managed resources. The controllers writing
+// the resources keep the metadata they don't set themselves, so the stamps survive their updates and are only
+// refreshed when the data changes.
+type OwnershipController struct {
+	operatorClient  v1helpers.OperatorClient
+	resources       []Resource
+	configMapClient corev1client.ConfigMapsGetter
+	secretClient    corev1client.SecretsGetter
+	configMapLister corev1listers.ConfigMapLister
+	secretLister    corev1listers.SecretLister
+}
+
+func NewOwnershipController(
+	operatorClient v1helpers.OperatorClient,
+	resources []Resource,
+	kubeInformersForNamespaces v1helpers.KubeInformersForNamespaces,
+	kubeClient kubernetes.Interface,
+	eventRecorder events.Recorder,
+) factory.Controller {
+	c := &OwnershipController{
+		operatorClient:  operatorClient,
+		resources:       resources,
+		configMapClient: kubeClient.CoreV1(),
+		secretClient:    kubeClient.CoreV1(),
+		configMapLister: kubeInformersForNamespaces.ConfigMapLister(),
+		secretLister:    kubeInformersForNamespaces.SecretLister(),
+	}
+
+	var names []string
+	for _, resource := range resources {
+		names = append(names, resource.Name)
+	}
+	return factory.New().WithFilteredEventsInformers(
+		factory.NamesFilter(names...),
+		kubeInformersForNamespaces.InformersFor(operatorclient.TargetNamespace).Core().V1().ConfigMaps().Informer(),
+		kubeInformersForNamespaces.InformersFor(operatorclient.TargetNamespace).Core().V1().Secrets().Informer(),
+	).WithInformers(
+		operatorClient.Informer(),
+	).ResyncEvery(10*time.Minute).WithSync(c.sync).ToController("OwnershipController", eventRecorder)
+}
+
+func (c *OwnershipController) sync(ctx context.Context, syncCtx factory.SyncContext) error {
+	operatorSpec, _, _, err := c.operatorClient.GetOperatorState()
+	if err != nil {
+		return err
+	}
+	if !management.IsOperatorManaged(operatorSpec.ManagementState) {
+		return nil
+	}
+
+	var errs []error
+	for _, resource := range c.resources {
+		switch resource.Kind {
+		case ConfigMap:
+			errs = append(errs, c.stampConfigMap(ctx, resource))
+		case Secret:
+			errs = append(errs, c.stampSecret(ctx, resource))
+		}
+	}
+	return utilerrors.NewAggregate(errs)
+}
+
+func (c *OwnershipController) stampConfigMap(ctx context.Context, resource Resource) error {
+	configMap, err := c.configMapLister.ConfigMaps(operatorclient.TargetNamespace).Get(resource.Name)
+	if apierrors.IsNotFound(err) {
+		// optional, or not written yet
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	hash, err := dataHash(configMap.Data, configMap.BinaryData)
+	if err != nil {
+		return err
+	}
+	required := configMap.DeepCopy()
+	if !stamp(&required.ObjectMeta, resource, hash) {
+		return nil
+	}
+	_, err = c.configMapClient.ConfigMaps(required.Namespace).Update(ctx, required, metav1.UpdateOptions{})
+	return err
+}
+
+func (c *OwnershipController) stampSecret(ctx context.Context, resource Resource) error {
+	secret, err := c.secretLister.Secrets(operatorclient.TargetNamespace).Get(resource.Name)
+	if apierrors.IsNotFound(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	hash, err := dataHash(secret.Data)
+	if err != nil {
+		return err
+	}
+	required := secret.DeepCopy()
+	if !stamp(&required.ObjectMeta, resource, hash) {
+		return nil
+	}
+	_, err = c.secretClient.Secrets(required.Namespace).Update(ctx, required, metav1.UpdateOptions{})
+	return err
+}
+
+// stamp sets the ownership label and annotations of the resource and returns whether they changed.
+func stamp(meta *metav1.ObjectMeta, resource Resource, hash string) bool {
+	required := map[string]string{
+		ManagedByAnnotation:    resource.Controller,
+		SourceInputsAnnotation: strings.Join(resource.Sources, ","),
+		LastSyncHashAnnotation: hash,
+	}
+	changed := meta.Labels[ManagedLabel] != "true"
+	if meta.Labels == nil {
+		meta.Labels = map[string]string{}
+	}
+	meta.Labels[ManagedLabel] = "true"
+	if meta.Annotations == nil {
+		meta.Annotations = map[string]string{}
+	}
+	for key, value := range required {
+		if current, ok := meta.Annotations[key]; !ok || current != value {
+			meta.Annotations[key] = value
+			changed = true
+		}
+	}
+	return changed
+}
+
+// dataHash hashes the JSON of the data, the keys of the maps are serialized in order.
+func dataHash(data ...interface{}) (string, error) {
+	content, err := json.Marshal(data)
+	if err != nil {
+		return "", err
+	}
+	hash := sha256.Sum256(content)
+	return hex.EncodeToString(hash[:]), nil
+}
+
+// Owned is a stamped resource as listed by List.
+type Owned struct {
+	Kind         string   `json:"kind"`
+	Namespace    string   `json:"namespace"`
+	Name         string   `json:"name"`
+	ManagedBy    string   `json:"managedBy"`
+	SourceInputs []string `json:"sourceInputs,omitempty"`
+	LastSyncHash string   `json:"lastSyncHash"`
+}
+
+// List returns the configmaps and secrets of the target namespace stamped with the ManagedLabel, sorted by kind and
+// name. The revisioned copies carry the stamps of the resource they were copied from, they are left out.
+func List(ctx context.Context, client corev1client.CoreV1Interface) ([]Owned, error) {
+	options := metav1.ListOptions{LabelSelector: ManagedLabel + "=true"}
+	var owned []Owned
+	configMaps, err := client.ConfigMaps(operatorclient.TargetNamespace).List(ctx, options)
+	if err != nil {
+		return nil, err
+	}
+	for i := range configMaps.Items {
+		if isRevisionCopy(&configMaps.Items[i].ObjectMeta) {
+			continue
+		}
+		owned = append(owned, ownedFor(ConfigMap, &configMaps.Items[i].ObjectMeta))
+	}
+	secrets, err := client.Secrets(operatorclient.TargetNamespace).List(ctx, options)
+	if err != nil {
+		return nil, err
+	}
+	for i := range secrets.Items {
+		if isRevisionCopy(&secrets.Items[i].ObjectMeta) {
+			continue
+		}
+		owned = append(owned, ownedFor(Secret, &secrets.Items[i].ObjectMeta))
+	}
+	sort.Slice(owned, func(i, j int) bool {
+		if owned[i].Kind != owned[j].Kind {
+			return owned[i].Kind < owned[j].Kind
+		}
+		return owned[i].Name < owned[j].Name
+	})
+	return owned, nil
+}
+
+// isRevisionCopy returns whether the resource is the copy of a revision, owned by its revision status configmap.
+func isRevisionCopy(meta *metav1.ObjectMeta) bool {
+	for _, owner := range meta.OwnerReferences {
+		if owner.Kind == "ConfigMap" && strings.HasPrefix(owner.Name, "revision-status-") {
+			return true
+		}
+	}
+	return false
+}
+
+func ownedFor(kind string, meta *metav1.ObjectMeta) Owned {
+	owned := Owned{
+		Kind:         kind,
+		Namespace:    meta.Namespace,
+		Name:         meta.Name,
+		ManagedBy:    meta.Annotations[ManagedByAnnotation],
+		LastSyncHash: meta.Annotations[LastSyncHashAnnotation],
+	}
+	if sources := meta.Annotations[SourceInputsAnnotation]; len(sources) > 0 {
+		owned.SourceInputs = strings.Split(sources, ",")
+	}
+	return owned
+}
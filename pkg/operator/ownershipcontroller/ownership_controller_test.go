@@ -0,0 +1,70 @@
+package ownershipcontroller
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/operatorclient"
+)
+
+func TestStamp(t *testing.T) {
+	resource := Resource{
+		Kind:       ConfigMap,
+		Name:       "config",
+		Controller: "TargetConfigController",
+		Sources:    []string{"kubecontrollermanager.operator.openshift.io/cluster", "configmap/ns/tuning"},
+	}
+	meta := &metav1.ObjectMeta{Annotations: map[string]string{"other": "kept"}}
+
+	if !stamp(meta, resource, "a") {
+		t.Fatal("expected the first stamp to change the metadata")
+	}
+	if meta.Labels[ManagedLabel] != "true" || meta.Annotations["other"] != "kept" {
+		t.Errorf("unexpected metadata %v", meta)
+	}
+	if meta.Annotations[SourceInputsAnnotation] != "kubecontrollermanager.operator.openshift.io/cluster,configmap/ns/tuning" {
+		t.Errorf("unexpected sources %q", meta.Annotations[SourceInputsAnnotation])
+	}
+	if stamp(meta, resource, "a") {
+		t.Error("expected the same stamp not to change the metadata")
+	}
+	if !stamp(meta, resource, "b") || meta.Annotations[LastSyncHashAnnotation] != "b" {
+		t.Error("expected a new hash to change the metadata")
+	}
+}
+
+func TestList(t *testing.T) {
+	stamped := func(name string, owners ...metav1.OwnerReference) metav1.ObjectMeta {
+		meta := metav1.ObjectMeta{Namespace: operatorclient.TargetNamespace, Name: name, OwnerReferences: owners}
+		stamp(&meta, Resource{Controller: "TargetConfigController", Sources: []string{"proxy.config.openshift.io/cluster"}}, "hash")
+		return meta
+	}
+	client := fake.NewSimpleClientset(
+		&corev1.ConfigMap{ObjectMeta: stamped("trusted-ca-bundle")},
+		&corev1.ConfigMap{ObjectMeta: stamped("config")},
+		&corev1.ConfigMap{ObjectMeta: stamped("config-3", metav1.OwnerReference{Kind: "ConfigMap", Name: "revision-status-3"})},
+		&corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Namespace: operatorclient.TargetNamespace, Name: "unmanaged"}},
+		&corev1.Secret{ObjectMeta: stamped("csr-signer")},
+	)
+
+	owned, err := List(context.TODO(), client.CoreV1())
+	if err != nil {
+		t.Fatal(err)
+	}
+	var names []string
+	for _, o := range owned {
+		names = append(names, o.Kind+"/"+o.Name)
+	}
+	expected := []string{"ConfigMap/config", "ConfigMap/trusted-ca-bundle", "Secret/csr-signer"}
+	if !reflect.DeepEqual(names, expected) {
+		t.Errorf("expected %v, got %v", expected, names)
+	}
+	if owned[0].ManagedBy != "TargetConfigController" || !reflect.DeepEqual(owned[0].SourceInputs, []string{"proxy.config.openshift.io/cluster"}) || owned[0].LastSyncHash != "hash" {
+		t.Errorf("unexpected entry %+v", owned[0])
+	}
+}
@@ -0,0 +1,165 @@
+package podjanitorcontroller
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/selection"
+	"k8s.io/client-go/kubernetes"
+	corev1client "k8s.io/client-go/kubernetes/typed/core/v1"
+	corev1listers "k8s.io/client-go/listers/core/v1"
+
+	operatorv1 "github.com/openshift/api/operator/v1"
+	"github.com/openshift/library-go/pkg/controller/factory"
+	"github.com/openshift/library-go/pkg/operator/events"
+	"github.com/openshift/library-go/pkg/operator/management"
+	"github.com/openshift/library-go/pkg/operator/v1helpers"
+
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/operatorclient"
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/tuning"
+)
+
+// DefaultCompletedPodRetention is how long completed installer and pruner pods are kept when the tuning config
+// does not say otherwise.
+const DefaultCompletedPodRetention = 24 * time.Hour
+
+// podNamePrefixes maps the app label of the installer and pruner pods to the prefix of their names, which is
+// followed by the revision they were created for.
+var podNamePrefixes = map[string]string{
+	"installer": "installer-",
+	"pruner":    "revision-pruner-",
+}
+
+// PodJanitorController deletes the completed installer and pruner pods of revisions all the nodes have moved past.
+// They are only garbage collected together with their revision, so on long lived clusters that keep many revisions
+// they pile up into thousands. The pods of the revisions the nodes are on or rolling to are left to the installer
+// controller, which reads their status.
+type PodJanitorController struct {
+	operatorClient  v1helpers.StaticPodOperatorClient
+	podClient       corev1client.PodsGetter
+	podLister       corev1listers.PodLister
+	configMapLister corev1listers.ConfigMapLister
+	now             func() time.Time
+}
+
+func NewPodJanitorController(
+	operatorClient v1helpers.StaticPodOperatorClient,
+	kubeInformersForNamespaces v1helpers.KubeInformersForNamespaces,
+	kubeClient kubernetes.Interface,
+	eventRecorder events.Recorder,
+) factory.Controller {
+	c := &PodJanitorController{
+		operatorClient:  operatorClient,
+		podClient:       kubeClient.CoreV1(),
+		podLister:       kubeInformersForNamespaces.InformersFor(operatorclient.TargetNamespace).Core().V1().Pods().Lister(),
+		configMapLister: kubeInformersForNamespaces.ConfigMapLister(),
+		now:             time.Now,
+	}
+
+	return factory.New().WithInformers(
+		operatorClient.Informer(),
+		kubeInformersForNamespaces.InformersFor(operatorclient.TargetNamespace).Core().V1().Pods().Informer(),
+		// the tuning config sets the retention
+		kubeInformersForNamespaces.InformersFor(operatorclient.OperatorNamespace).Core().V1().ConfigMaps().Informer(),
+	).ResyncEvery(time.Hour).WithSync(c.sync).ToController("PodJanitorController", eventRecorder)
+}
+
+func (c *PodJanitorController) sync(ctx context.Context, syncCtx factory.SyncContext) error {
+	operatorSpec, operatorStatus, _, err := c.operatorClient.GetStaticPodOperatorState()
+	if err != nil {
+		return err
+	}
+	if !management.IsOperatorManaged(operatorSpec.ManagementState) {
+		return nil
+	}
+
+	tuningConfig, err := tuning.Get(c.configMapLister)
+	if err != nil {
+		return err
+	}
+	retention := DefaultCompletedPodRetention
+	if tuningConfig.CompletedPodRetention != nil {
+		retention = tuningConfig.CompletedPodRetention.Duration
+	}
+
+	selector, err := labels.NewRequirement("app", selection.In, []string{"installer", "pruner"})
+	if err != nil {
+		return err
+	}
+	pods, err := c.podLister.Pods(operatorclient.TargetNamespace).List(labels.NewSelector().Add(*selector))
+	if err != nil {
+		return err
+	}
+
+	var errs []error
+	deleted := 0
+	for _, pod := range expiredPods(pods, operatorStatus.NodeStatuses, retention, c.now()) {
+		err := c.podClient.Pods(pod.Namespace).Delete(ctx, pod.Name, metav1.DeleteOptions{Preconditions: &metav1.Preconditions{UID: &pod.UID}})
+		if apierrors.IsNotFound(err) {
+			continue
+		}
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%q: %v", "pod/"+pod.Name, err))
+			continue
+		}
+		deleted++
+	}
+	if deleted > 0 {
+		syncCtx.Recorder().Eventf("CompletedPodsDeleted", "Deleted %d completed installer and pruner pods older than %s", deleted, retention)
+	}
+	return v1helpers.NewMultiLineAggregate(errs)
+}
+
+// expiredPods returns the completed pods older than the retention whose revision is below the current revision
+// of every node.
+func expiredPods(pods []*corev1.Pod, nodeStatuses []operatorv1.NodeStatus, retention time.Duration, now time.Time) []*corev1.Pod {
+	if len(nodeStatuses) == 0 {
+		return nil
+	}
+	oldestRevision := nodeStatuses[0].CurrentRevision
+	for _, nodeStatus := range nodeStatuses {
+		if nodeStatus.CurrentRevision < oldestRevision {
+			oldestRevision = nodeStatus.CurrentRevision
+		}
+		if nodeStatus.TargetRevision > 0 && nodeStatus.TargetRevision < oldestRevision {
+			oldestRevision = nodeStatus.TargetRevision
+		}
+	}
+
+	var expired []*corev1.Pod
+	for _, pod := range pods {
+		if pod.Status.Phase != corev1.PodSucceeded && pod.Status.Phase != corev1.PodFailed {
+			continue
+		}
+		if now.Sub(pod.CreationTimestamp.Time) < retention {
+			continue
+		}
+		revision, ok := podRevision(pod)
+		if !ok || revision >= oldestRevision {
+			continue
+		}
+		expired = append(expired, pod)
+	}
+	return expired
+}
+
+// podRevision parses the revision out of the name of an installer or pruner pod.
+func podRevision(pod *corev1.Pod) (int32, bool) {
+	prefix, ok := podNamePrefixes[pod.Labels["app"]]
+	if !ok || !strings.HasPrefix(pod.Name, prefix) {
+		return 0, false
+	}
+	revision, _, _ := strings.Cut(strings.TrimPrefix(pod.Name, prefix), "-")
+	parsed, err := strconv.ParseInt(revision, 10, 32)
+	if err != nil {
+		return 0, false
+	}
+	return int32(parsed), true
+}
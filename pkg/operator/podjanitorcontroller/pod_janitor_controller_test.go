@@ -0,0 +1,130 @@
+package podjanitorcontroller
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/client-go/kubernetes/fake"
+	corev1listers "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/tools/cache"
+
+	operatorv1 "github.com/openshift/api/operator/v1"
+	"github.com/openshift/library-go/pkg/controller/factory"
+	"github.com/openshift/library-go/pkg/operator/events"
+	"github.com/openshift/library-go/pkg/operator/v1helpers"
+
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/operatorclient"
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/tuning"
+)
+
+func TestSync(t *testing.T) {
+	now := time.Now()
+	pod := func(name, app string, phase corev1.PodPhase, age time.Duration) *corev1.Pod {
+		return &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace:         operatorclient.TargetNamespace,
+				Name:              name,
+				Labels:            map[string]string{"app": app},
+				CreationTimestamp: metav1.NewTime(now.Add(-age)),
+			},
+			Status: corev1.PodStatus{Phase: phase},
+		}
+	}
+	pods := []*corev1.Pod{
+		pod("installer-3-master-0", "installer", corev1.PodSucceeded, 48*time.Hour),
+		pod("installer-3-retry-1-master-1", "installer", corev1.PodFailed, 48*time.Hour),
+		pod("revision-pruner-3-master-0", "pruner", corev1.PodSucceeded, 48*time.Hour),
+		// recent
+		pod("installer-3-master-2", "installer", corev1.PodSucceeded, time.Hour),
+		// the revision of master-2
+		pod("installer-4-master-2", "installer", corev1.PodSucceeded, 48*time.Hour),
+		// the revision of master-1
+		pod("installer-5-master-1", "installer", corev1.PodSucceeded, 48*time.Hour),
+		// still running
+		pod("installer-2-master-0", "installer", corev1.PodRunning, 48*time.Hour),
+		// not an installer pod
+		pod("kube-controller-manager-master-0", "kube-controller-manager", corev1.PodSucceeded, 48*time.Hour),
+	}
+
+	tests := []struct {
+		name            string
+		tuningConfig    string
+		expectedDeleted sets.Set[string]
+	}{
+		{
+			name:            "default retention",
+			expectedDeleted: sets.New("installer-3-master-0", "installer-3-retry-1-master-1", "revision-pruner-3-master-0"),
+		},
+		{
+			name:            "short retention",
+			tuningConfig:    "completedPodRetention: 30m",
+			expectedDeleted: sets.New("installer-3-master-0", "installer-3-retry-1-master-1", "revision-pruner-3-master-0", "installer-3-master-2"),
+		},
+		{
+			name:            "long retention",
+			tuningConfig:    "completedPodRetention: 72h",
+			expectedDeleted: sets.New[string](),
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			podIndexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc})
+			kubeClient := fake.NewSimpleClientset()
+			for _, pod := range pods {
+				if err := podIndexer.Add(pod); err != nil {
+					t.Fatal(err)
+				}
+				if _, err := kubeClient.CoreV1().Pods(pod.Namespace).Create(context.TODO(), pod, metav1.CreateOptions{}); err != nil {
+					t.Fatal(err)
+				}
+			}
+			configMapIndexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc})
+			if len(test.tuningConfig) > 0 {
+				if err := configMapIndexer.Add(&corev1.ConfigMap{
+					ObjectMeta: metav1.ObjectMeta{Namespace: operatorclient.OperatorNamespace, Name: tuning.ConfigMapName},
+					Data:       map[string]string{tuning.ConfigKey: test.tuningConfig},
+				}); err != nil {
+					t.Fatal(err)
+				}
+			}
+			c := &PodJanitorController{
+				operatorClient: v1helpers.NewFakeStaticPodOperatorClient(
+					&operatorv1.StaticPodOperatorSpec{OperatorSpec: operatorv1.OperatorSpec{ManagementState: operatorv1.Managed}},
+					&operatorv1.StaticPodOperatorStatus{NodeStatuses: []operatorv1.NodeStatus{
+						{NodeName: "master-0", CurrentRevision: 5},
+						{NodeName: "master-1", CurrentRevision: 5},
+						{NodeName: "master-2", CurrentRevision: 4, TargetRevision: 5},
+					}},
+					nil,
+					nil,
+				),
+				podClient:       kubeClient.CoreV1(),
+				podLister:       corev1listers.NewPodLister(podIndexer),
+				configMapLister: corev1listers.NewConfigMapLister(configMapIndexer),
+				now:             func() time.Time { return now },
+			}
+
+			recorder := events.NewInMemoryRecorder("test")
+			if err := c.sync(context.TODO(), factory.NewSyncContext("test", recorder)); err != nil {
+				t.Fatal(err)
+			}
+
+			deleted := sets.New[string]()
+			for _, action := range kubeClient.Actions() {
+				if action.GetVerb() == "delete" {
+					deleted.Insert(action.(interface{ GetName() string }).GetName())
+				}
+			}
+			if !deleted.Equal(test.expectedDeleted) {
+				t.Errorf("expected %v to be deleted, got %v", sets.List(test.expectedDeleted), sets.List(deleted))
+			}
+			if test.expectedDeleted.Len() > 0 && len(recorder.Events()) != 1 {
+				t.Errorf("expected one event, got %v", recorder.Events())
+			}
+		})
+	}
+}
@@ -0,0 +1,237 @@
+package rbacdriftcontroller
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+	"k8s.io/client-go/kubernetes"
+	rbacv1client "k8s.io/client-go/kubernetes/typed/rbac/v1"
+	rbacv1listers "k8s.io/client-go/listers/rbac/v1"
+
+	operatorv1 "github.com/openshift/api/operator/v1"
+	"github.com/openshift/library-go/pkg/controller/factory"
+	"github.com/openshift/library-go/pkg/operator/events"
+	"github.com/openshift/library-go/pkg/operator/management"
+	"github.com/openshift/library-go/pkg/operator/resource/resourceapply"
+	"github.com/openshift/library-go/pkg/operator/resource/resourceread"
+	"github.com/openshift/library-go/pkg/operator/v1helpers"
+
+	"github.com/openshift/cluster-kube-controller-manager-operator/bindata"
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/operatorclient"
+)
+
+const (
+	rbacDriftCondition = "RBACDrift"
+
+	// driftReportDuration is how long a restored edit is reported in the RBACDrift condition. The edit is undone
+	// within seconds, the condition is what tells the admin it happened.
+	driftReportDuration = time.Hour
+)
+
+// The roles and bindings the kube-controller-manager and the cluster-policy-controller need, beyond the ones the
+// kube-apiserver bootstraps.
+var (
+	clusterRoles = []string{
+		"assets/kube-controller-manager/namespace-security-allocation-controller-clusterrole.yaml",
+		"assets/kube-controller-manager/podsecurity-admission-label-syncer-controller-clusterrole.yaml",
+		"assets/kube-controller-manager/podsecurity-admission-label-privileged-namespaces-syncer-controller-clusterrole.yaml",
+		"assets/kube-controller-manager/csr_approver_clusterrole.yaml",
+		"assets/kube-controller-manager/metrics-client-clusterrole.yaml",
+	}
+	clusterRoleBindings = []string{
+		"assets/kube-controller-manager/namespace-security-allocation-controller-clusterrolebinding.yaml",
+		"assets/kube-controller-manager/podsecurity-admission-label-syncer-controller-clusterrolebinding.yaml",
+		"assets/kube-controller-manager/podsecurity-admission-label-privileged-namespaces-syncer-controller-clusterrolebinding.yaml",
+		"assets/kube-controller-manager/localhost-recovery-client-crb.yaml",
+		"assets/kube-controller-manager/csr_approver_clusterrolebinding.yaml",
+		"assets/kube-controller-manager/metrics-client-clusterrolebinding.yaml",
+	}
+	roles = []string{
+		"assets/kube-controller-manager/leader-election-cluster-policy-controller-role.yaml",
+	}
+	roleBindings = []string{
+		"assets/kube-controller-manager/leader-election-rolebinding.yaml",
+		"assets/kube-controller-manager/leader-election-cluster-policy-controller-rolebinding.yaml",
+	}
+
+	// roleNamespaces are the namespaces of the roles and role bindings
+	roleNamespaces = []string{"kube-system", operatorclient.TargetNamespace}
+)
+
+// RBACDriftController keeps the roles and bindings of the operand as they are in the assets. An edit or a deletion
+// of one of them is undone and reported in the RBACDrift condition for driftReportDuration, so that an admin taking
+// away permissions of the kube-controller-manager finds out why they come back.
+type RBACDriftController struct {
+	operatorClient           v1helpers.OperatorClient
+	rbacClient               rbacv1client.RbacV1Interface
+	clusterRoleLister        rbacv1listers.ClusterRoleLister
+	clusterRoleBindingLister rbacv1listers.ClusterRoleBindingLister
+	roleListers              map[string]rbacv1listers.RoleNamespaceLister
+	roleBindingListers       map[string]rbacv1listers.RoleBindingNamespaceLister
+	now                      func() time.Time
+
+	// drifted are the times the objects, by kind and name, were last restored
+	drifted map[string]time.Time
+}
+
+func NewRBACDriftController(
+	operatorClient v1helpers.OperatorClient,
+	kubeInformersForNamespaces v1helpers.KubeInformersForNamespaces,
+	kubeClient kubernetes.Interface,
+	eventRecorder events.Recorder,
+) factory.Controller {
+	c := &RBACDriftController{
+		operatorClient:           operatorClient,
+		rbacClient:               kubeClient.RbacV1(),
+		clusterRoleLister:        kubeInformersForNamespaces.InformersFor("").Rbac().V1().ClusterRoles().Lister(),
+		clusterRoleBindingLister: kubeInformersForNamespaces.InformersFor("").Rbac().V1().ClusterRoleBindings().Lister(),
+		roleListers:              map[string]rbacv1listers.RoleNamespaceLister{},
+		roleBindingListers:       map[string]rbacv1listers.RoleBindingNamespaceLister{},
+		now:                      time.Now,
+		drifted:                  map[string]time.Time{},
+	}
+
+	var names []string
+	for _, asset := range clusterRoles {
+		names = append(names, resourceread.ReadClusterRoleV1OrDie(bindata.MustAsset(asset)).Name)
+	}
+	for _, asset := range clusterRoleBindings {
+		names = append(names, resourceread.ReadClusterRoleBindingV1OrDie(bindata.MustAsset(asset)).Name)
+	}
+	for _, asset := range roles {
+		names = append(names, resourceread.ReadRoleV1OrDie(bindata.MustAsset(asset)).Name)
+	}
+	for _, asset := range roleBindings {
+		names = append(names, resourceread.ReadRoleBindingV1OrDie(bindata.MustAsset(asset)).Name)
+	}
+	informers := []factory.Informer{
+		kubeInformersForNamespaces.InformersFor("").Rbac().V1().ClusterRoles().Informer(),
+		kubeInformersForNamespaces.InformersFor("").Rbac().V1().ClusterRoleBindings().Informer(),
+	}
+	for _, namespace := range roleNamespaces {
+		rbacInformers := kubeInformersForNamespaces.InformersFor(namespace).Rbac().V1()
+		c.roleListers[namespace] = rbacInformers.Roles().Lister().Roles(namespace)
+		c.roleBindingListers[namespace] = rbacInformers.RoleBindings().Lister().RoleBindings(namespace)
+		informers = append(informers, rbacInformers.Roles().Informer(), rbacInformers.RoleBindings().Informer())
+	}
+
+	return factory.New().WithFilteredEventsInformers(
+		factory.NamesFilter(names...),
+		informers...,
+	).WithInformers(
+		operatorClient.Informer(),
+	).ResyncEvery(time.Minute).WithSync(c.sync).ToController("RBACDriftController", eventRecorder)
+}
+
+func (c *RBACDriftController) sync(ctx context.Context, syncCtx factory.SyncContext) error {
+	operatorSpec, _, _, err := c.operatorClient.GetOperatorState()
+	if err != nil {
+		return err
+	}
+	if !management.IsOperatorManaged(operatorSpec.ManagementState) {
+		return nil
+	}
+
+	var errs []error
+	for _, asset := range clusterRoles {
+		required := resourceread.ReadClusterRoleV1OrDie(bindata.MustAsset(asset))
+		_, err := c.clusterRoleLister.Get(required.Name)
+		existed, err := objectExisted(err)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		_, changed, err := resourceapply.ApplyClusterRole(ctx, c.rbacClient, syncCtx.Recorder(), required)
+		c.recordDrift(syncCtx.Recorder(), "clusterrole/"+required.Name, existed && changed, err)
+		errs = append(errs, err)
+	}
+	for _, asset := range clusterRoleBindings {
+		required := resourceread.ReadClusterRoleBindingV1OrDie(bindata.MustAsset(asset))
+		_, err := c.clusterRoleBindingLister.Get(required.Name)
+		existed, err := objectExisted(err)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		_, changed, err := resourceapply.ApplyClusterRoleBinding(ctx, c.rbacClient, syncCtx.Recorder(), required)
+		c.recordDrift(syncCtx.Recorder(), "clusterrolebinding/"+required.Name, existed && changed, err)
+		errs = append(errs, err)
+	}
+	for _, asset := range roles {
+		required := resourceread.ReadRoleV1OrDie(bindata.MustAsset(asset))
+		_, err := c.roleListers[required.Namespace].Get(required.Name)
+		existed, err := objectExisted(err)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		_, changed, err := resourceapply.ApplyRole(ctx, c.rbacClient, syncCtx.Recorder(), required)
+		c.recordDrift(syncCtx.Recorder(), fmt.Sprintf("role/%s -n %s", required.Name, required.Namespace), existed && changed, err)
+		errs = append(errs, err)
+	}
+	for _, asset := range roleBindings {
+		required := resourceread.ReadRoleBindingV1OrDie(bindata.MustAsset(asset))
+		_, err := c.roleBindingListers[required.Namespace].Get(required.Name)
+		existed, err := objectExisted(err)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		_, changed, err := resourceapply.ApplyRoleBinding(ctx, c.rbacClient, syncCtx.Recorder(), required)
+		c.recordDrift(syncCtx.Recorder(), fmt.Sprintf("rolebinding/%s -n %s", required.Name, required.Namespace), existed && changed, err)
+		errs = append(errs, err)
+	}
+
+	if _, _, err := v1helpers.UpdateStatus(ctx, c.operatorClient, v1helpers.UpdateConditionFn(c.driftCondition())); err != nil {
+		errs = append(errs, err)
+	}
+	return utilerrors.NewAggregate(errs)
+}
+
+// objectExisted tells whether the lister found the object, a deletion after it was cached is drift as well.
+func objectExisted(err error) (bool, error) {
+	if apierrors.IsNotFound(err) {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+// recordDrift remembers an object that differed from its asset and was restored.
+func (c *RBACDriftController) recordDrift(recorder events.Recorder, object string, drifted bool, err error) {
+	if !drifted || err != nil {
+		return
+	}
+	c.drifted[object] = c.now()
+	recorder.Warningf("RBACDriftRestored", "Restored %s, which differed from the one the kube-controller-manager needs", object)
+}
+
+// driftCondition reports the objects restored within driftReportDuration and forgets the older ones.
+func (c *RBACDriftController) driftCondition() operatorv1.OperatorCondition {
+	now := c.now()
+	var restored []string
+	for object, at := range c.drifted {
+		if now.Sub(at) >= driftReportDuration {
+			delete(c.drifted, object)
+			continue
+		}
+		restored = append(restored, fmt.Sprintf("%s at %s", object, at.UTC().Format(time.RFC3339)))
+	}
+	if len(restored) == 0 {
+		return operatorv1.OperatorCondition{
+			Type:   rbacDriftCondition,
+			Status: operatorv1.ConditionFalse,
+		}
+	}
+	sort.Strings(restored)
+	return operatorv1.OperatorCondition{
+		Type:    rbacDriftCondition,
+		Status:  operatorv1.ConditionTrue,
+		Reason:  "Restored",
+		Message: fmt.Sprintf("restored edited or deleted %s", strings.Join(restored, ", ")),
+	}
+}
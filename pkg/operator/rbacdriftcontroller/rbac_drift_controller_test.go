@@ -0,0 +1,98 @@
+package rbacdriftcontroller
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	rbacv1 "k8s.io/api/rbac/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+	rbacv1listers "k8s.io/client-go/listers/rbac/v1"
+	"k8s.io/client-go/tools/cache"
+
+	operatorv1 "github.com/openshift/api/operator/v1"
+	"github.com/openshift/library-go/pkg/controller/factory"
+	"github.com/openshift/library-go/pkg/operator/events"
+	"github.com/openshift/library-go/pkg/operator/resource/resourceread"
+	"github.com/openshift/library-go/pkg/operator/v1helpers"
+
+	"github.com/openshift/cluster-kube-controller-manager-operator/bindata"
+)
+
+func TestSync(t *testing.T) {
+	required := resourceread.ReadClusterRoleBindingV1OrDie(bindata.MustAsset("assets/kube-controller-manager/csr_approver_clusterrolebinding.yaml"))
+	edited := required.DeepCopy()
+	edited.Subjects = []rbacv1.Subject{{Kind: "ServiceAccount", Name: "other", Namespace: "other"}}
+
+	kubeClient := fake.NewSimpleClientset(edited)
+	indexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc})
+	if err := indexer.Add(edited); err != nil {
+		t.Fatal(err)
+	}
+	emptyIndexer := func() cache.Indexer {
+		return cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc})
+	}
+	operatorClient := v1helpers.NewFakeStaticPodOperatorClient(&operatorv1.StaticPodOperatorSpec{OperatorSpec: operatorv1.OperatorSpec{ManagementState: operatorv1.Managed}}, &operatorv1.StaticPodOperatorStatus{}, nil, nil)
+	now := time.Now()
+	c := &RBACDriftController{
+		operatorClient:           operatorClient,
+		rbacClient:               kubeClient.RbacV1(),
+		clusterRoleLister:        rbacv1listers.NewClusterRoleLister(emptyIndexer()),
+		clusterRoleBindingLister: rbacv1listers.NewClusterRoleBindingLister(indexer),
+		roleListers:              map[string]rbacv1listers.RoleNamespaceLister{},
+		roleBindingListers:       map[string]rbacv1listers.RoleBindingNamespaceLister{},
+		now:                      func() time.Time { return now },
+		drifted:                  map[string]time.Time{},
+	}
+	for _, namespace := range roleNamespaces {
+		c.roleListers[namespace] = rbacv1listers.NewRoleLister(emptyIndexer()).Roles(namespace)
+		c.roleBindingListers[namespace] = rbacv1listers.NewRoleBindingLister(emptyIndexer()).RoleBindings(namespace)
+	}
+	recorder := events.NewInMemoryRecorder("test")
+	if err := c.sync(context.TODO(), factory.NewSyncContext("test", recorder)); err != nil {
+		t.Fatal(err)
+	}
+
+	restored, err := kubeClient.RbacV1().ClusterRoleBindings().Get(context.TODO(), required.Name, metav1.GetOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !equality.Semantic.DeepEqual(restored.Subjects, required.Subjects) {
+		t.Errorf("expected the subjects %v to be restored, got %v", required.Subjects, restored.Subjects)
+	}
+	// the missing objects are created without being reported
+	if _, err := kubeClient.RbacV1().ClusterRoles().Get(context.TODO(), "system:openshift:controller:cluster-csr-approver-controller", metav1.GetOptions{}); err != nil {
+		t.Errorf("expected the cluster role to be created: %v", err)
+	}
+	var driftEvents int
+	for _, event := range recorder.Events() {
+		if event.Reason == "RBACDriftRestored" {
+			driftEvents++
+		}
+	}
+	if driftEvents != 1 {
+		t.Errorf("expected a single RBACDriftRestored event, got %d", driftEvents)
+	}
+
+	condition := func() *operatorv1.OperatorCondition {
+		_, status, _, _ := operatorClient.GetStaticPodOperatorState()
+		return v1helpers.FindOperatorCondition(status.Conditions, rbacDriftCondition)
+	}
+	if cond := condition(); cond == nil || cond.Status != operatorv1.ConditionTrue {
+		t.Fatalf("expected %s to be True, got %v", rbacDriftCondition, cond)
+	}
+
+	// the restored edit is forgotten after driftReportDuration
+	now = now.Add(driftReportDuration)
+	if err := indexer.Update(restored); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.sync(context.TODO(), factory.NewSyncContext("test", recorder)); err != nil {
+		t.Fatal(err)
+	}
+	if cond := condition(); cond == nil || cond.Status != operatorv1.ConditionFalse {
+		t.Errorf("expected %s to be False, got %v", rbacDriftCondition, cond)
+	}
+}
@@ -0,0 +1,131 @@
+package recoverytokencontroller
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	authenticationv1 "k8s.io/api/authentication/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apiserver/pkg/authentication/serviceaccount"
+	"k8s.io/client-go/kubernetes"
+	corev1listers "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/rest"
+
+	operatorv1 "github.com/openshift/api/operator/v1"
+	"github.com/openshift/library-go/pkg/controller/factory"
+	"github.com/openshift/library-go/pkg/operator/events"
+	"github.com/openshift/library-go/pkg/operator/management"
+	"github.com/openshift/library-go/pkg/operator/v1helpers"
+
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/operatorclient"
+)
+
+const (
+	tokenSecretName    = "localhost-recovery-client-token"
+	serviceAccountName = "localhost-recovery-client"
+
+	degradedCondition = "LocalhostRecoveryTokenDegraded"
+)
+
+// RecoveryTokenController periodically authenticates with the localhost recovery token, the way the cert-syncer and
+// the recovery controller of the operand do when the kube-apiserver is only reachable on localhost. A token that was
+// revoked or whose service account was recreated would otherwise only be noticed in the outage it is meant for.
+type RecoveryTokenController struct {
+	operatorClient v1helpers.StaticPodOperatorClient
+	secretLister   corev1listers.SecretLister
+	host           string
+	// authenticate returns the user the kube-apiserver authenticates the config as
+	authenticate func(ctx context.Context, config *rest.Config) (string, error)
+}
+
+// NewRecoveryTokenController returns a controller that tests the token against the kube-apiserver at host, the
+// operator cannot reach the localhost endpoint of the control plane nodes.
+func NewRecoveryTokenController(
+	operatorClient v1helpers.StaticPodOperatorClient,
+	kubeInformersForNamespaces v1helpers.KubeInformersForNamespaces,
+	host string,
+	eventRecorder events.Recorder,
+) factory.Controller {
+	c := &RecoveryTokenController{
+		operatorClient: operatorClient,
+		secretLister:   kubeInformersForNamespaces.SecretLister(),
+		host:           host,
+		authenticate:   authenticate,
+	}
+
+	return factory.New().WithInformers(
+		operatorClient.Informer(),
+	).WithFilteredEventsInformers(
+		factory.NamesFilter(tokenSecretName),
+		kubeInformersForNamespaces.InformersFor(operatorclient.TargetNamespace).Core().V1().Secrets().Informer(),
+	).ResyncEvery(10*time.Minute).WithSync(c.sync).ToController("RecoveryTokenController", eventRecorder)
+}
+
+func (c *RecoveryTokenController) sync(ctx context.Context, syncCtx factory.SyncContext) error {
+	operatorSpec, _, _, err := c.operatorClient.GetStaticPodOperatorState()
+	if err != nil {
+		return err
+	}
+	if !management.IsOperatorManaged(operatorSpec.ManagementState) {
+		return nil
+	}
+
+	token, err := c.secretLister.Secrets(operatorclient.TargetNamespace).Get(tokenSecretName)
+	if apierrors.IsNotFound(err) {
+		// the target config controller reports the missing token
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if len(token.Data["token"]) == 0 || len(token.Data["ca.crt"]) == 0 {
+		// the token is not populated yet, the target config controller reports it as well
+		return nil
+	}
+
+	config := &rest.Config{
+		Host:            c.host,
+		BearerToken:     string(token.Data["token"]),
+		TLSClientConfig: rest.TLSClientConfig{CAData: token.Data["ca.crt"]},
+		Timeout:         30 * time.Second,
+	}
+	user, err := c.authenticate(ctx, config)
+
+	condition := operatorv1.OperatorCondition{
+		Type:   degradedCondition,
+		Status: operatorv1.ConditionFalse,
+	}
+	expectedUser := serviceaccount.MakeUsername(operatorclient.TargetNamespace, serviceAccountName)
+	switch {
+	case apierrors.IsUnauthorized(err):
+		condition.Status = operatorv1.ConditionTrue
+		condition.Reason = "TokenRejected"
+		condition.Message = fmt.Sprintf("The kube-apiserver rejected the token in secret/%s -n %s: %v", tokenSecretName, operatorclient.TargetNamespace, err)
+	case err != nil:
+		// the test could not reach a verdict, retry without changing the condition
+		return fmt.Errorf("failed to authenticate with the token in secret/%s -n %s: %v", tokenSecretName, operatorclient.TargetNamespace, err)
+	case user != expectedUser:
+		condition.Status = operatorv1.ConditionTrue
+		condition.Reason = "UnexpectedUser"
+		condition.Message = fmt.Sprintf("The token in secret/%s -n %s authenticates as %q instead of %q", tokenSecretName, operatorclient.TargetNamespace, user, expectedUser)
+	}
+
+	if _, _, err := v1helpers.UpdateStaticPodStatus(ctx, c.operatorClient, v1helpers.UpdateStaticPodConditionFn(condition)); err != nil {
+		return err
+	}
+	return nil
+}
+
+func authenticate(ctx context.Context, config *rest.Config) (string, error) {
+	client, err := kubernetes.NewForConfig(config)
+	if err != nil {
+		return "", err
+	}
+	review, err := client.AuthenticationV1().SelfSubjectReviews().Create(ctx, &authenticationv1.SelfSubjectReview{}, metav1.CreateOptions{})
+	if err != nil {
+		return "", err
+	}
+	return review.Status.UserInfo.Username, nil
+}
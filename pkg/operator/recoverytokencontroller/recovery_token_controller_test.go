@@ -0,0 +1,135 @@
+package recoverytokencontroller
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	corev1listers "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/cache"
+
+	operatorv1 "github.com/openshift/api/operator/v1"
+	"github.com/openshift/library-go/pkg/controller/factory"
+	"github.com/openshift/library-go/pkg/operator/events"
+	"github.com/openshift/library-go/pkg/operator/v1helpers"
+
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/operatorclient"
+)
+
+func TestSync(t *testing.T) {
+	validToken := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Namespace: operatorclient.TargetNamespace, Name: tokenSecretName},
+		Data:       map[string][]byte{"token": []byte("token"), "ca.crt": []byte("ca")},
+	}
+	expectedUser := "system:serviceaccount:openshift-kube-controller-manager:localhost-recovery-client"
+
+	tests := []struct {
+		name            string
+		token           *corev1.Secret
+		user            string
+		authErr         error
+		expectedStatus  operatorv1.ConditionStatus
+		expectedReason  string
+		expectedError   bool
+		expectedAttempt bool
+	}{
+		{
+			name:            "authenticated",
+			token:           validToken,
+			user:            expectedUser,
+			expectedStatus:  operatorv1.ConditionFalse,
+			expectedAttempt: true,
+		},
+		{
+			name:            "rejected",
+			token:           validToken,
+			authErr:         apierrors.NewUnauthorized("Unauthorized"),
+			expectedStatus:  operatorv1.ConditionTrue,
+			expectedReason:  "TokenRejected",
+			expectedAttempt: true,
+		},
+		{
+			name:            "unexpected user",
+			token:           validToken,
+			user:            "system:anonymous",
+			expectedStatus:  operatorv1.ConditionTrue,
+			expectedReason:  "UnexpectedUser",
+			expectedAttempt: true,
+		},
+		{
+			name:            "unreachable",
+			token:           validToken,
+			authErr:         fmt.Errorf("connection refused"),
+			expectedError:   true,
+			expectedAttempt: true,
+		},
+		{
+			name: "missing token",
+		},
+		{
+			name: "token not populated",
+			token: &corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{Namespace: operatorclient.TargetNamespace, Name: tokenSecretName},
+			},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			indexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{})
+			if test.token != nil {
+				if err := indexer.Add(test.token); err != nil {
+					t.Fatal(err)
+				}
+			}
+			operatorClient := v1helpers.NewFakeStaticPodOperatorClient(
+				&operatorv1.StaticPodOperatorSpec{OperatorSpec: operatorv1.OperatorSpec{ManagementState: operatorv1.Managed}},
+				&operatorv1.StaticPodOperatorStatus{},
+				nil,
+				nil,
+			)
+			attempted := false
+			c := &RecoveryTokenController{
+				operatorClient: operatorClient,
+				secretLister:   corev1listers.NewSecretLister(indexer),
+				host:           "https://api.example.com:6443",
+				authenticate: func(ctx context.Context, config *rest.Config) (string, error) {
+					attempted = true
+					if config.BearerToken != "token" || string(config.CAData) != "ca" {
+						t.Errorf("unexpected config %+v", config)
+					}
+					return test.user, test.authErr
+				},
+			}
+
+			err := c.sync(context.TODO(), factory.NewSyncContext("test", events.NewInMemoryRecorder("test")))
+			if test.expectedError != (err != nil) {
+				t.Fatalf("expected error %v, got %v", test.expectedError, err)
+			}
+			if attempted != test.expectedAttempt {
+				t.Errorf("expected an authentication attempt %v, got %v", test.expectedAttempt, attempted)
+			}
+
+			_, status, _, err := operatorClient.GetStaticPodOperatorState()
+			if err != nil {
+				t.Fatal(err)
+			}
+			condition := v1helpers.FindOperatorCondition(status.Conditions, degradedCondition)
+			if len(test.expectedStatus) == 0 {
+				if condition != nil {
+					t.Errorf("expected no condition, got %v", condition)
+				}
+				return
+			}
+			if condition == nil {
+				t.Fatalf("expected the %s condition", degradedCondition)
+			}
+			if condition.Status != test.expectedStatus || condition.Reason != test.expectedReason {
+				t.Errorf("expected %s/%s, got %s/%s: %s", test.expectedStatus, test.expectedReason, condition.Status, condition.Reason, condition.Message)
+			}
+		})
+	}
+}
@@ -59,12 +59,7 @@ func NewResourceSyncController(
 	); err != nil {
 		return nil, err
 	}
-	if err := resourceSyncController.SyncConfigMap(
-		resourcesynccontroller.ResourceLocation{Namespace: operatorclient.TargetNamespace, Name: "aggregator-client-ca"},
-		resourcesynccontroller.ResourceLocation{Namespace: operatorclient.GlobalMachineSpecifiedConfigNamespace, Name: "kube-apiserver-aggregator-client-ca"},
-	); err != nil {
-		return nil, err
-	}
+	// the aggregator-client-ca is copied by the AggregatorClientCAController, which keeps it while its source is broken
 
 	return resourceSyncController, nil
 }
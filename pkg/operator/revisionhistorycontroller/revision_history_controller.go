@@ -0,0 +1,432 @@
+package revisionhistorycontroller
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ghodss/yaml"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/kubernetes"
+	corev1client "k8s.io/client-go/kubernetes/typed/core/v1"
+	corev1listers "k8s.io/client-go/listers/core/v1"
+	"k8s.io/klog/v2"
+
+	"github.com/openshift/library-go/pkg/controller/factory"
+	"github.com/openshift/library-go/pkg/operator/events"
+	"github.com/openshift/library-go/pkg/operator/resource/resourceapply"
+	"github.com/openshift/library-go/pkg/operator/resource/resourceread"
+	"github.com/openshift/library-go/pkg/operator/staticpod/controller/revision"
+	"github.com/openshift/library-go/pkg/operator/v1helpers"
+
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/flagdiff"
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/operatorclient"
+)
+
+const (
+	// historyConfigMapName holds the RevisionHistory of the operand.
+	historyConfigMapName = "kube-controller-manager-revision-history"
+	historyKey           = "history.json"
+
+	// MaxRevisions is the number of revisions kept in the history, the oldest are dropped first.
+	MaxRevisions = 50
+
+	revisionStatusPrefix    = "revision-status-"
+	revisionReadyAnnotation = "operator.openshift.io/revision-ready"
+
+	// podConfigMapName is the revisioned configmap with the static pod manifest, its changes are recorded as
+	// changes of the kube-controller-manager flags.
+	podConfigMapName = "kube-controller-manager-pod"
+	// flagsConfigMapName holds the kube-controller-manager flags one per line when the flags file is selected.
+	flagsConfigMapName = "kube-controller-manager-flags"
+)
+
+// configKeys are the keys of the revisioned configmaps holding a config file, their changes are recorded by path.
+var configKeys = map[string]bool{
+	"config.yaml": true,
+}
+
+// RevisionHistory lists the revisions of the operand with what changed in each of them.
+type RevisionHistory struct {
+	// Revisions are sorted by revision, oldest first.
+	Revisions []RevisionRecord `json:"revisions"`
+}
+
+// RevisionRecord is what changed in a revision compared to the revision before it. Only the names of the changed
+// keys and config paths are recorded, never their values, secrets are among the revisioned resources. The flags are
+// recorded with their values, they are on the command line of the operand anyway.
+type RevisionRecord struct {
+	Revision     int32       `json:"revision"`
+	CreationTime metav1.Time `json:"creationTime"`
+	// Reason is the reason the revision controller created the revision for.
+	Reason string `json:"reason,omitempty"`
+	// Changes are the revisioned resources that changed, sorted by resource. They are unknown when the resources of
+	// the previous revision were already pruned.
+	Changes []ResourceChange `json:"changes,omitempty"`
+	// PreviousRevisionPruned is set when the resources of the previous revision were gone.
+	PreviousRevisionPruned bool `json:"previousRevisionPruned,omitempty"`
+}
+
+// ResourceChange lists what changed in a revisioned configmap or secret.
+type ResourceChange struct {
+	// Resource is configmap/<name> or secret/<name>, without the revision suffix.
+	Resource string `json:"resource"`
+	// Keys are the data keys that were added, removed or changed.
+	Keys []string `json:"keys,omitempty"`
+	// Paths are the dotted paths changed in the config files of the resource.
+	Paths []string `json:"paths,omitempty"`
+	// Flags are the kube-controller-manager flags that were added, removed or changed.
+	Flags []string `json:"flags,omitempty"`
+	// FlagChanges are the changes of the Flags and of the extendedArguments of the config file, with their old and
+	// new values.
+	FlagChanges []flagdiff.Change `json:"flagChanges,omitempty"`
+}
+
+// RevisionHistoryController records in the historyConfigMapName configmap what changed in every new revision of the
+// operand. The revision-status configmaps only name the changed resources and the revisioned copies are pruned, so
+// the reason a revision restarted the control plane is otherwise lost soon after.
+type RevisionHistoryController struct {
+	configMaps      []revision.RevisionResource
+	secrets         []revision.RevisionResource
+	configMapClient corev1client.ConfigMapsGetter
+	configMapLister corev1listers.ConfigMapLister
+	secretLister    corev1listers.SecretLister
+}
+
+func NewRevisionHistoryController(
+	configMaps []revision.RevisionResource,
+	secrets []revision.RevisionResource,
+	kubeInformersForNamespaces v1helpers.KubeInformersForNamespaces,
+	kubeClient kubernetes.Interface,
+	eventRecorder events.Recorder,
+) factory.Controller {
+	c := &RevisionHistoryController{
+		configMaps:      configMaps,
+		secrets:         secrets,
+		configMapClient: v1helpers.CachedConfigMapGetter(kubeClient.CoreV1(), kubeInformersForNamespaces),
+		configMapLister: kubeInformersForNamespaces.ConfigMapLister(),
+		secretLister:    kubeInformersForNamespaces.SecretLister(),
+	}
+
+	return factory.New().WithInformers(
+		kubeInformersForNamespaces.InformersFor(operatorclient.OperatorNamespace).Core().V1().ConfigMaps().Informer(),
+		kubeInformersForNamespaces.InformersFor(operatorclient.TargetNamespace).Core().V1().Secrets().Informer(),
+	).WithFilteredEventsInformers(
+		func(obj interface{}) bool {
+			configMap, ok := obj.(*corev1.ConfigMap)
+			return ok && strings.HasPrefix(configMap.Name, revisionStatusPrefix)
+		},
+		kubeInformersForNamespaces.InformersFor(operatorclient.TargetNamespace).Core().V1().ConfigMaps().Informer(),
+	).ResyncEvery(10*time.Minute).WithSync(c.sync).ToController("RevisionHistoryController", eventRecorder)
+}
+
+func (c *RevisionHistoryController) sync(ctx context.Context, syncCtx factory.SyncContext) error {
+	history := &RevisionHistory{}
+	configMap, err := c.configMapLister.ConfigMaps(operatorclient.OperatorNamespace).Get(historyConfigMapName)
+	switch {
+	case apierrors.IsNotFound(err):
+	case err != nil:
+		return err
+	default:
+		if err := json.Unmarshal([]byte(configMap.Data[historyKey]), history); err != nil {
+			klog.Warningf("Ignoring the invalid %s/%s: %v", operatorclient.OperatorNamespace, historyConfigMapName, err)
+			history = &RevisionHistory{}
+		}
+	}
+
+	var lastRecorded int32
+	if len(history.Revisions) > 0 {
+		lastRecorded = history.Revisions[len(history.Revisions)-1].Revision
+	}
+	statuses, err := c.revisionStatuses(lastRecorded)
+	if err != nil {
+		return err
+	}
+	if len(statuses) == 0 {
+		return nil
+	}
+
+	for _, status := range statuses {
+		record, err := c.recordRevision(status)
+		if err != nil {
+			return err
+		}
+		history.Revisions = append(history.Revisions, *record)
+	}
+	if extra := len(history.Revisions) - MaxRevisions; extra > 0 {
+		history.Revisions = history.Revisions[extra:]
+	}
+
+	historyBytes, err := json.MarshalIndent(history, "", "  ")
+	if err != nil {
+		return err
+	}
+	_, _, err = resourceapply.ApplyConfigMap(ctx, c.configMapClient, syncCtx.Recorder(), &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Namespace: operatorclient.OperatorNamespace, Name: historyConfigMapName},
+		Data:       map[string]string{historyKey: string(historyBytes)},
+	})
+	return err
+}
+
+// revisionStatus is a revision-status configmap of a revision whose resources were all copied.
+type revisionStatus struct {
+	revision  int32
+	configMap *corev1.ConfigMap
+}
+
+// revisionStatuses returns the ready revisions after lastRecorded, oldest first. Revisions after one that is not
+// ready yet are left for a later sync, so that the history stays sorted.
+func (c *RevisionHistoryController) revisionStatuses(lastRecorded int32) ([]revisionStatus, error) {
+	configMaps, err := c.configMapLister.ConfigMaps(operatorclient.TargetNamespace).List(labels.Everything())
+	if err != nil {
+		return nil, err
+	}
+	var statuses []revisionStatus
+	for _, configMap := range configMaps {
+		if !strings.HasPrefix(configMap.Name, revisionStatusPrefix) {
+			continue
+		}
+		revision, err := strconv.ParseInt(strings.TrimPrefix(configMap.Name, revisionStatusPrefix), 10, 32)
+		if err != nil || int32(revision) <= lastRecorded {
+			continue
+		}
+		statuses = append(statuses, revisionStatus{revision: int32(revision), configMap: configMap})
+	}
+	sort.Slice(statuses, func(i, j int) bool { return statuses[i].revision < statuses[j].revision })
+	for i, status := range statuses {
+		if status.configMap.Annotations[revisionReadyAnnotation] != "true" {
+			return statuses[:i], nil
+		}
+	}
+	return statuses, nil
+}
+
+// recordRevision compares the revisioned resources of a revision with the ones of the revision before it.
+func (c *RevisionHistoryController) recordRevision(status revisionStatus) (*RevisionRecord, error) {
+	record := &RevisionRecord{
+		Revision:     status.revision,
+		CreationTime: status.configMap.CreationTimestamp,
+		Reason:       status.configMap.Data["reason"],
+	}
+	if status.revision <= 1 {
+		return record, nil
+	}
+	if _, err := c.configMapLister.ConfigMaps(operatorclient.TargetNamespace).Get(revisionStatusPrefix + strconv.Itoa(int(status.revision-1))); apierrors.IsNotFound(err) {
+		record.PreviousRevisionPruned = true
+		return record, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	for _, resource := range c.configMaps {
+		current, err := c.configMapData(resource.Name, status.revision)
+		if err != nil {
+			return nil, err
+		}
+		previous, err := c.configMapData(resource.Name, status.revision-1)
+		if err != nil {
+			return nil, err
+		}
+		if change := configMapChange(resource.Name, previous, current); change != nil {
+			record.Changes = append(record.Changes, *change)
+		}
+	}
+	for _, resource := range c.secrets {
+		current, err := c.secretData(resource.Name, status.revision)
+		if err != nil {
+			return nil, err
+		}
+		previous, err := c.secretData(resource.Name, status.revision-1)
+		if err != nil {
+			return nil, err
+		}
+		if keys := changedSecretKeys(previous, current); len(keys) > 0 {
+			record.Changes = append(record.Changes, ResourceChange{Resource: "secret/" + resource.Name, Keys: keys})
+		}
+	}
+	sort.Slice(record.Changes, func(i, j int) bool { return record.Changes[i].Resource < record.Changes[j].Resource })
+	return record, nil
+}
+
+func (c *RevisionHistoryController) configMapData(name string, revision int32) (map[string]string, error) {
+	configMap, err := c.configMapLister.ConfigMaps(operatorclient.TargetNamespace).Get(revisionedName(name, revision))
+	if apierrors.IsNotFound(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return configMap.Data, nil
+}
+
+func (c *RevisionHistoryController) secretData(name string, revision int32) (map[string][]byte, error) {
+	secret, err := c.secretLister.Secrets(operatorclient.TargetNamespace).Get(revisionedName(name, revision))
+	if apierrors.IsNotFound(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return secret.Data, nil
+}
+
+func revisionedName(name string, revision int32) string {
+	return fmt.Sprintf("%s-%d", name, revision)
+}
+
+// configMapChange returns what changed in a revisioned configmap, or nil when it did not change.
+func configMapChange(name string, previous, current map[string]string) *ResourceChange {
+	change := &ResourceChange{Resource: "configmap/" + name}
+	for _, key := range unionKeys(previous, current) {
+		previousValue, previousFound := previous[key]
+		currentValue, currentFound := current[key]
+		if previousFound == currentFound && previousValue == currentValue {
+			continue
+		}
+		change.Keys = append(change.Keys, key)
+		switch {
+		case name == podConfigMapName && key == "pod.yaml":
+			previousFlags, currentFlags := podFlags(previousValue), podFlags(currentValue)
+			change.Flags = append(change.Flags, changedFlags(previousFlags, currentFlags)...)
+			change.FlagChanges = append(change.FlagChanges, flagdiff.Diff(flagdiff.SingleValued(previousFlags), flagdiff.SingleValued(currentFlags))...)
+		case name == flagsConfigMapName && key == "flags":
+			previousFlags, currentFlags := parseFlags(previousValue), parseFlags(currentValue)
+			change.Flags = append(change.Flags, changedFlags(previousFlags, currentFlags)...)
+			change.FlagChanges = append(change.FlagChanges, flagdiff.Diff(flagdiff.SingleValued(previousFlags), flagdiff.SingleValued(currentFlags))...)
+		case configKeys[key]:
+			change.Paths = append(change.Paths, changedConfigPaths(previousValue, currentValue)...)
+			change.FlagChanges = append(change.FlagChanges, extendedArgumentChanges(previousValue, currentValue)...)
+		}
+	}
+	if len(change.Keys) == 0 {
+		return nil
+	}
+	return change
+}
+
+func changedSecretKeys(previous, current map[string][]byte) []string {
+	var keys []string
+	for _, key := range unionKeys(previous, current) {
+		previousValue, previousFound := previous[key]
+		currentValue, currentFound := current[key]
+		if previousFound != currentFound || !bytes.Equal(previousValue, currentValue) {
+			keys = append(keys, key)
+		}
+	}
+	return keys
+}
+
+func unionKeys[V any](maps ...map[string]V) []string {
+	keys := map[string]bool{}
+	for _, m := range maps {
+		for key := range m {
+			keys[key] = true
+		}
+	}
+	sorted := make([]string, 0, len(keys))
+	for key := range keys {
+		sorted = append(sorted, key)
+	}
+	sort.Strings(sorted)
+	return sorted
+}
+
+// changedConfigPaths returns the dotted paths of the leaves that differ between two config files. A file that
+// does not parse is reported as a whole.
+func changedConfigPaths(previous, current string) []string {
+	var previousConfig, currentConfig interface{}
+	if err := yaml.Unmarshal([]byte(previous), &previousConfig); err != nil {
+		return []string{"<unparsable>"}
+	}
+	if err := yaml.Unmarshal([]byte(current), &currentConfig); err != nil {
+		return []string{"<unparsable>"}
+	}
+	var paths []string
+	walkChanges("", previousConfig, currentConfig, &paths)
+	return paths
+}
+
+func walkChanges(path string, previous, current interface{}, paths *[]string) {
+	previousMap, previousIsMap := previous.(map[string]interface{})
+	currentMap, currentIsMap := current.(map[string]interface{})
+	if !previousIsMap || !currentIsMap {
+		if !reflect.DeepEqual(previous, current) {
+			*paths = append(*paths, path)
+		}
+		return
+	}
+	for _, key := range unionKeys(previousMap, currentMap) {
+		childPath := key
+		if len(path) > 0 {
+			childPath = path + "." + key
+		}
+		walkChanges(childPath, previousMap[key], currentMap[key], paths)
+	}
+}
+
+// extendedArgumentChanges returns the changes of the extendedArguments between two config files. A file that does not
+// parse is already reported in the paths.
+func extendedArgumentChanges(previous, current string) []flagdiff.Change {
+	previousArguments, err := flagdiff.ExtendedArguments(previous)
+	if err != nil {
+		return nil
+	}
+	currentArguments, err := flagdiff.ExtendedArguments(current)
+	if err != nil {
+		return nil
+	}
+	return flagdiff.Diff(previousArguments, currentArguments)
+}
+
+// changedFlags returns the names of the flags that differ between two sets of flags.
+func changedFlags(previousFlags, currentFlags map[string]string) []string {
+	var flags []string
+	for _, flag := range unionKeys(previousFlags, currentFlags) {
+		previousValue, previousFound := previousFlags[flag]
+		currentValue, currentFound := currentFlags[flag]
+		if previousFound != currentFound || previousValue != currentValue {
+			flags = append(flags, flag)
+		}
+	}
+	return flags
+}
+
+// podFlags returns the kube-controller-manager flags of a pod manifest, read from the script the container is
+// started with.
+func podFlags(podYAML string) map[string]string {
+	if len(podYAML) == 0 {
+		return map[string]string{}
+	}
+	pod, err := resourceread.ReadPodV1([]byte(podYAML))
+	if err != nil {
+		return map[string]string{}
+	}
+	for _, container := range pod.Spec.Containers {
+		if container.Name == "kube-controller-manager" {
+			return parseFlags(strings.Join(container.Args, " "))
+		}
+	}
+	return map[string]string{}
+}
+
+// parseFlags returns the values of the --name=value fields of a script or flags file by name.
+func parseFlags(content string) map[string]string {
+	flags := map[string]string{}
+	for _, field := range strings.Fields(content) {
+		if !strings.HasPrefix(field, "--") {
+			continue
+		}
+		name, value, _ := strings.Cut(strings.TrimPrefix(field, "--"), "=")
+		flags[name] = value
+	}
+	return flags
+}
@@ -0,0 +1,149 @@
+package revisionhistorycontroller
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+	corev1listers "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/tools/cache"
+
+	"github.com/openshift/library-go/pkg/controller/factory"
+	"github.com/openshift/library-go/pkg/operator/events"
+	"github.com/openshift/library-go/pkg/operator/staticpod/controller/revision"
+
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/flagdiff"
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/operatorclient"
+)
+
+const podTemplate = `apiVersion: v1
+kind: Pod
+metadata:
+  name: kube-controller-manager
+spec:
+  containers:
+  - name: kube-controller-manager
+    args:
+    - exec hyperkube kube-controller-manager --kubeconfig=/kubeconfig %s
+`
+
+func TestSync(t *testing.T) {
+	revisionStatus := func(revision int32, ready bool) *corev1.ConfigMap {
+		return &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace:   operatorclient.TargetNamespace,
+				Name:        fmt.Sprintf("revision-status-%d", revision),
+				Annotations: map[string]string{revisionReadyAnnotation: fmt.Sprintf("%t", ready)},
+			},
+			Data: map[string]string{"revision": fmt.Sprintf("%d", revision), "reason": fmt.Sprintf("reason %d", revision)},
+		}
+	}
+	configMap := func(name string, data map[string]string) *corev1.ConfigMap {
+		return &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Namespace: operatorclient.TargetNamespace, Name: name}, Data: data}
+	}
+	secret := func(name string, data map[string][]byte) *corev1.Secret {
+		return &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Namespace: operatorclient.TargetNamespace, Name: name}, Data: data}
+	}
+
+	configMapIndexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc})
+	secretIndexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc})
+	for _, obj := range []runtime.Object{
+		revisionStatus(2, true),
+		revisionStatus(3, true),
+		revisionStatus(4, false),
+		revisionStatus(5, true),
+		configMap("config-2", map[string]string{"config.yaml": `{"extendedArguments":{"cluster-name":["a"]},"kind":"KubeControllerManagerConfig"}`}),
+		configMap("config-3", map[string]string{"config.yaml": `{"extendedArguments":{"cluster-name":["b"],"secure-port":["10258"]},"kind":"KubeControllerManagerConfig"}`}),
+		configMap("kube-controller-manager-pod-2", map[string]string{"pod.yaml": fmt.Sprintf(podTemplate, "--v=2 --cluster-name=a"), "version": "1"}),
+		configMap("kube-controller-manager-pod-3", map[string]string{"pod.yaml": fmt.Sprintf(podTemplate, "--v=2 --cluster-name=b --secure-port=10258"), "version": "1"}),
+		configMap("service-ca-2", map[string]string{"ca-bundle.crt": "same"}),
+		configMap("service-ca-3", map[string]string{"ca-bundle.crt": "same"}),
+		secret("serving-cert-2", map[string][]byte{"tls.crt": []byte("old"), "tls.key": []byte("key")}),
+		secret("serving-cert-3", map[string][]byte{"tls.crt": []byte("new"), "tls.key": []byte("key")}),
+	} {
+		var err error
+		if s, ok := obj.(*corev1.Secret); ok {
+			err = secretIndexer.Add(s)
+		} else {
+			err = configMapIndexer.Add(obj)
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	kubeClient := fake.NewSimpleClientset()
+	c := &RevisionHistoryController{
+		configMaps:      []revision.RevisionResource{{Name: "kube-controller-manager-pod"}, {Name: "config"}, {Name: "service-ca"}},
+		secrets:         []revision.RevisionResource{{Name: "serving-cert", Optional: true}},
+		configMapClient: kubeClient.CoreV1(),
+		configMapLister: corev1listers.NewConfigMapLister(configMapIndexer),
+		secretLister:    corev1listers.NewSecretLister(secretIndexer),
+	}
+	if err := c.sync(context.TODO(), factory.NewSyncContext("test", events.NewInMemoryRecorder("test"))); err != nil {
+		t.Fatal(err)
+	}
+
+	historyConfigMap, err := kubeClient.CoreV1().ConfigMaps(operatorclient.OperatorNamespace).Get(context.TODO(), historyConfigMapName, metav1.GetOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	history := &RevisionHistory{}
+	if err := json.Unmarshal([]byte(historyConfigMap.Data[historyKey]), history); err != nil {
+		t.Fatal(err)
+	}
+	// revision 5 waits for revision 4 to be ready
+	expected := []RevisionRecord{
+		{Revision: 2, Reason: "reason 2", PreviousRevisionPruned: true},
+		{
+			Revision: 3,
+			Reason:   "reason 3",
+			Changes: []ResourceChange{
+				{
+					Resource: "configmap/config", Keys: []string{"config.yaml"}, Paths: []string{"extendedArguments.cluster-name", "extendedArguments.secure-port"},
+					FlagChanges: []flagdiff.Change{{Flag: "cluster-name", Old: []string{"a"}, New: []string{"b"}}, {Flag: "secure-port", New: []string{"10258"}}},
+				},
+				{
+					Resource: "configmap/kube-controller-manager-pod", Keys: []string{"pod.yaml"}, Flags: []string{"cluster-name", "secure-port"},
+					FlagChanges: []flagdiff.Change{{Flag: "cluster-name", Old: []string{"a"}, New: []string{"b"}}, {Flag: "secure-port", New: []string{"10258"}}},
+				},
+				{Resource: "secret/serving-cert", Keys: []string{"tls.crt"}},
+			},
+		},
+	}
+	if !reflect.DeepEqual(expected, history.Revisions) {
+		t.Errorf("expected %+v, got %+v", expected, history.Revisions)
+	}
+
+	// the recorded revisions are kept and the ready ones after them are appended
+	if err := configMapIndexer.Add(historyConfigMap); err != nil {
+		t.Fatal(err)
+	}
+	if err := configMapIndexer.Update(revisionStatus(4, true)); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.sync(context.TODO(), factory.NewSyncContext("test", events.NewInMemoryRecorder("test"))); err != nil {
+		t.Fatal(err)
+	}
+	historyConfigMap, err = kubeClient.CoreV1().ConfigMaps(operatorclient.OperatorNamespace).Get(context.TODO(), historyConfigMapName, metav1.GetOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	history = &RevisionHistory{}
+	if err := json.Unmarshal([]byte(historyConfigMap.Data[historyKey]), history); err != nil {
+		t.Fatal(err)
+	}
+	var revisions []int32
+	for _, record := range history.Revisions {
+		revisions = append(revisions, record.Revision)
+	}
+	if !reflect.DeepEqual([]int32{2, 3, 4, 5}, revisions) {
+		t.Errorf("expected revisions 2 to 5, got %v", revisions)
+	}
+}
@@ -0,0 +1,277 @@
+package rollbackcontroller
+
+import (
+	"context"
+	"crypto/x509"
+	"fmt"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/client-go/kubernetes"
+	corev1client "k8s.io/client-go/kubernetes/typed/core/v1"
+	corev1listers "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/util/cert"
+
+	operatorv1 "github.com/openshift/api/operator/v1"
+	"github.com/openshift/library-go/pkg/controller/factory"
+	"github.com/openshift/library-go/pkg/operator/events"
+	"github.com/openshift/library-go/pkg/operator/management"
+	"github.com/openshift/library-go/pkg/operator/resource/resourceapply"
+	"github.com/openshift/library-go/pkg/operator/v1helpers"
+
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/operatorclient"
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/targetconfigcontroller"
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/version"
+)
+
+const (
+	revisionRollbackDegradedCondition = "RevisionRollbackDegraded"
+
+	revisionStatusPrefix = "revision-status-"
+	podConfigMapName     = "kube-controller-manager-pod"
+
+	// minCertificateValidity is how long the restored certificates have to stay valid, a rollback must not restore a
+	// CA bundle that expires before the revision rolled out.
+	minCertificateValidity = time.Hour
+)
+
+// RollbackController restores the resources of the revision named by the targetconfigcontroller.RollbackAnnotation,
+// which the revision controller then rolls out to the nodes as a new revision. A revision is only restored when its
+// copies were not pruned yet, it was rendered by the running version of the operator and its certificates are valid.
+// The restored resources are kept until the annotation is removed.
+type RollbackController struct {
+	operatorClient  v1helpers.StaticPodOperatorClient
+	operatorLister  cache.GenericLister
+	configMapClient corev1client.ConfigMapsGetter
+	secretClient    corev1client.SecretsGetter
+	configMapLister corev1listers.ConfigMapLister
+	secretLister    corev1listers.SecretLister
+	operatorVersion string
+	now             func() time.Time
+}
+
+func NewRollbackController(
+	operatorClient v1helpers.StaticPodOperatorClient,
+	operatorLister cache.GenericLister,
+	kubeInformersForNamespaces v1helpers.KubeInformersForNamespaces,
+	kubeClient kubernetes.Interface,
+	eventRecorder events.Recorder,
+) factory.Controller {
+	c := &RollbackController{
+		operatorClient:  operatorClient,
+		operatorLister:  operatorLister,
+		configMapClient: kubeClient.CoreV1(),
+		secretClient:    kubeClient.CoreV1(),
+		configMapLister: kubeInformersForNamespaces.ConfigMapLister(),
+		secretLister:    kubeInformersForNamespaces.SecretLister(),
+		operatorVersion: version.Get().String(),
+		now:             time.Now,
+	}
+
+	return factory.New().WithInformers(
+		// the annotation is set on the operator resource
+		operatorClient.Informer(),
+		kubeInformersForNamespaces.InformersFor(operatorclient.TargetNamespace).Core().V1().ConfigMaps().Informer(),
+		kubeInformersForNamespaces.InformersFor(operatorclient.TargetNamespace).Core().V1().Secrets().Informer(),
+	).ResyncEvery(time.Minute).WithSync(c.sync).ToController("RollbackController", eventRecorder)
+}
+
+func (c *RollbackController) sync(ctx context.Context, syncCtx factory.SyncContext) error {
+	operatorSpec, operatorStatus, _, err := c.operatorClient.GetStaticPodOperatorState()
+	if err != nil {
+		return err
+	}
+	if !management.IsOperatorManaged(operatorSpec.ManagementState) {
+		return nil
+	}
+
+	rollbackCondition := operatorv1.OperatorCondition{Type: targetconfigcontroller.RevisionRollbackCondition, Status: operatorv1.ConditionFalse, Reason: "NotRequested"}
+	degradedCondition := operatorv1.OperatorCondition{Type: revisionRollbackDegradedCondition, Status: operatorv1.ConditionFalse, Reason: "AsExpected"}
+	revision, err := targetconfigcontroller.RollbackRevision(c.operatorLister)
+	switch {
+	case err != nil && apierrors.IsNotFound(err):
+		return nil
+	case err != nil:
+		degradedCondition.Status = operatorv1.ConditionTrue
+		degradedCondition.Reason = "InvalidAnnotation"
+		degradedCondition.Message = err.Error()
+	case revision > 0:
+		problems := c.checkRevision(revision, operatorStatus.LatestAvailableRevision)
+		if len(problems) > 0 {
+			degradedCondition.Status = operatorv1.ConditionTrue
+			degradedCondition.Reason = "RollbackRejected"
+			degradedCondition.Message = fmt.Sprintf("Revision %d is not restored:\n%s", revision, strings.Join(problems, "\n"))
+			break
+		}
+		restored, err := c.restore(ctx, syncCtx.Recorder(), revision)
+		if err != nil {
+			return err
+		}
+		if len(restored) > 0 {
+			syncCtx.Recorder().Eventf("RevisionRolledBack", "Restored %s of revision %d, a new revision rolls them out", strings.Join(restored, ", "), revision)
+		}
+		rollbackCondition.Status = operatorv1.ConditionTrue
+		rollbackCondition.Reason = "RolledBack"
+		rollbackCondition.Message = fmt.Sprintf("The rendered resources of revision %d are restored and not updated from the inputs until the %s annotation is removed", revision, targetconfigcontroller.RollbackAnnotation)
+	}
+
+	_, _, err = v1helpers.UpdateStaticPodStatus(ctx, c.operatorClient,
+		v1helpers.UpdateStaticPodConditionFn(rollbackCondition),
+		v1helpers.UpdateStaticPodConditionFn(degradedCondition),
+	)
+	return err
+}
+
+// checkRevision returns why the revision cannot be restored, nothing when it can.
+func (c *RollbackController) checkRevision(revision, latestAvailableRevision int32) []string {
+	if revision > latestAvailableRevision {
+		return []string{fmt.Sprintf("the latest available revision is %d", latestAvailableRevision)}
+	}
+	if _, err := c.configMapLister.ConfigMaps(operatorclient.TargetNamespace).Get(fmt.Sprintf("%s%d", revisionStatusPrefix, revision)); err != nil {
+		return []string{fmt.Sprintf("the revision is not available: %v", err)}
+	}
+
+	var problems []string
+	pod, err := c.configMapLister.ConfigMaps(operatorclient.TargetNamespace).Get(revisionedName(podConfigMapName, revision))
+	if err != nil {
+		// the copies of the revision were pruned
+		return []string{fmt.Sprintf("configmap/%s: %v", revisionedName(podConfigMapName, revision), err)}
+	}
+	if pod.Data["version"] != c.operatorVersion {
+		problems = append(problems, fmt.Sprintf("the revision was rendered by operator version %q, not by the running %q", pod.Data["version"], c.operatorVersion))
+	}
+
+	now := c.now()
+	for _, name := range targetconfigcontroller.RollbackConfigMaps {
+		configMap, err := c.configMapLister.ConfigMaps(operatorclient.TargetNamespace).Get(revisionedName(name, revision))
+		if apierrors.IsNotFound(err) {
+			continue
+		}
+		if err != nil {
+			return append(problems, err.Error())
+		}
+		for key, value := range configMap.Data {
+			if problem := certificateProblem([]byte(value), now); len(problem) > 0 {
+				problems = append(problems, fmt.Sprintf("configmap/%s[%s]: %s", configMap.Name, key, problem))
+			}
+		}
+	}
+	for _, name := range targetconfigcontroller.RollbackSecrets {
+		secret, err := c.secretLister.Secrets(operatorclient.TargetNamespace).Get(revisionedName(name, revision))
+		if apierrors.IsNotFound(err) {
+			continue
+		}
+		if err != nil {
+			return append(problems, err.Error())
+		}
+		for key, value := range secret.Data {
+			if problem := certificateProblem(value, now); len(problem) > 0 {
+				problems = append(problems, fmt.Sprintf("secret/%s[%s]: %s", secret.Name, key, problem))
+			}
+		}
+	}
+	return problems
+}
+
+// certificateProblem returns why the PEM certificates of a value cannot be restored: when all of them expire within
+// minCertificateValidity. Values without certificates have none.
+func certificateProblem(value []byte, now time.Time) string {
+	if !strings.Contains(string(value), "-----BEGIN CERTIFICATE-----") {
+		return ""
+	}
+	certificates, err := cert.ParseCertsPEM(value)
+	if err != nil {
+		return fmt.Sprintf("malformed certificates: %v", err)
+	}
+	var latest *x509.Certificate
+	for _, certificate := range certificates {
+		if latest == nil || certificate.NotAfter.After(latest.NotAfter) {
+			latest = certificate
+		}
+	}
+	if latest.NotAfter.Before(now.Add(minCertificateValidity)) {
+		return fmt.Sprintf("all the certificates expire by %s", latest.NotAfter.UTC().Format(time.RFC3339))
+	}
+	return ""
+}
+
+// restore writes the content of the copies of the revision to the current resources and deletes the current
+// resources the revision did not have. It returns the resources it changed.
+func (c *RollbackController) restore(ctx context.Context, recorder events.Recorder, revision int32) ([]string, error) {
+	var restored []string
+	for _, name := range targetconfigcontroller.RollbackConfigMaps {
+		source, err := c.configMapLister.ConfigMaps(operatorclient.TargetNamespace).Get(revisionedName(name, revision))
+		if err != nil && !apierrors.IsNotFound(err) {
+			return nil, err
+		}
+		existing, err := c.configMapLister.ConfigMaps(operatorclient.TargetNamespace).Get(name)
+		if err != nil && !apierrors.IsNotFound(err) {
+			return nil, err
+		}
+		if source == nil {
+			if existing == nil {
+				continue
+			}
+			if _, _, err := resourceapply.DeleteConfigMap(ctx, c.configMapClient, recorder, existing); err != nil {
+				return nil, err
+			}
+			restored = append(restored, "configmap/"+name)
+			continue
+		}
+		required := &corev1.ConfigMap{}
+		required.Namespace = operatorclient.TargetNamespace
+		required.Name = name
+		required.Data = source.Data
+		required.BinaryData = source.BinaryData
+		if existing != nil && equality.Semantic.DeepEqual(existing.Data, required.Data) && equality.Semantic.DeepEqual(existing.BinaryData, required.BinaryData) {
+			continue
+		}
+		if existing != nil {
+			existing = existing.DeepCopy()
+		}
+		if _, _, err := targetconfigcontroller.RestoreConfigMap(ctx, c.configMapClient, recorder, required, existing); err != nil {
+			return nil, err
+		}
+		restored = append(restored, "configmap/"+name)
+	}
+
+	for _, name := range targetconfigcontroller.RollbackSecrets {
+		source, err := c.secretLister.Secrets(operatorclient.TargetNamespace).Get(revisionedName(name, revision))
+		if err != nil && !apierrors.IsNotFound(err) {
+			return nil, err
+		}
+		existing, err := c.secretLister.Secrets(operatorclient.TargetNamespace).Get(name)
+		if err != nil && !apierrors.IsNotFound(err) {
+			return nil, err
+		}
+		if source == nil {
+			if existing == nil {
+				continue
+			}
+			if _, _, err := resourceapply.DeleteSecret(ctx, c.secretClient, recorder, existing); err != nil {
+				return nil, err
+			}
+			restored = append(restored, "secret/"+name)
+			continue
+		}
+		if existing != nil && equality.Semantic.DeepEqual(existing.Data, source.Data) {
+			continue
+		}
+		required := &corev1.Secret{Type: source.Type, Data: source.Data}
+		required.Namespace = operatorclient.TargetNamespace
+		required.Name = name
+		if _, _, err := targetconfigcontroller.ApplySecret(ctx, c.secretClient, recorder, required); err != nil {
+			return nil, err
+		}
+		restored = append(restored, "secret/"+name)
+	}
+	return restored, nil
+}
+
+func revisionedName(name string, revision int32) string {
+	return fmt.Sprintf("%s-%d", name, revision)
+}
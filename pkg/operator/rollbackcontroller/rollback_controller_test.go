@@ -0,0 +1,223 @@
+package rollbackcontroller
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+	corev1listers "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/tools/cache"
+
+	operatorv1 "github.com/openshift/api/operator/v1"
+	"github.com/openshift/library-go/pkg/controller/factory"
+	"github.com/openshift/library-go/pkg/crypto"
+	"github.com/openshift/library-go/pkg/operator/events"
+	"github.com/openshift/library-go/pkg/operator/v1helpers"
+
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/operatorclient"
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/targetconfigcontroller"
+)
+
+type fakeOperatorLister struct {
+	operator *unstructured.Unstructured
+}
+
+func (l fakeOperatorLister) List(_ labels.Selector) ([]runtime.Object, error) {
+	return []runtime.Object{l.operator}, nil
+}
+
+func (l fakeOperatorLister) Get(_ string) (runtime.Object, error) {
+	return l.operator, nil
+}
+
+func (l fakeOperatorLister) ByNamespace(_ string) cache.GenericNamespaceLister {
+	return nil
+}
+
+func TestSync(t *testing.T) {
+	caConfig, err := crypto.MakeSelfSignedCAConfigForDuration("service-account-ca", 2*time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	caPEM, _, err := caConfig.GetPEMBytes()
+	if err != nil {
+		t.Fatal(err)
+	}
+	rotatedCAConfig, err := crypto.MakeSelfSignedCAConfigForDuration("kube-apiserver-server-ca", 24*time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rotatedCAPEM, _, err := rotatedCAConfig.GetPEMBytes()
+	if err != nil {
+		t.Fatal(err)
+	}
+	configMap := func(name string, data map[string]string) *corev1.ConfigMap {
+		return &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Namespace: operatorclient.TargetNamespace, Name: name}, Data: data}
+	}
+
+	tests := []struct {
+		name             string
+		annotation       string
+		version          string
+		now              time.Time
+		expectedReason   string
+		expectedDegraded string
+		expectedMessage  string
+		expectedRestored bool
+	}{
+		{
+			name:             "not requested",
+			version:          "v1",
+			now:              time.Now(),
+			expectedReason:   "NotRequested",
+			expectedDegraded: "AsExpected",
+		},
+		{
+			name:             "restored",
+			annotation:       "2",
+			version:          "v1",
+			now:              time.Now(),
+			expectedReason:   "RolledBack",
+			expectedDegraded: "AsExpected",
+			expectedRestored: true,
+		},
+		{
+			name:             "not available",
+			annotation:       "4",
+			version:          "v1",
+			now:              time.Now(),
+			expectedReason:   "NotRequested",
+			expectedDegraded: "RollbackRejected",
+			expectedMessage:  "the latest available revision is 3",
+		},
+		{
+			name:             "other operator version",
+			annotation:       "2",
+			version:          "v2",
+			now:              time.Now(),
+			expectedReason:   "NotRequested",
+			expectedDegraded: "RollbackRejected",
+			expectedMessage:  `rendered by operator version "v1"`,
+		},
+		{
+			name:             "expired certificates",
+			annotation:       "2",
+			version:          "v1",
+			now:              time.Now().Add(90 * time.Minute),
+			expectedReason:   "NotRequested",
+			expectedDegraded: "RollbackRejected",
+			expectedMessage:  "configmap/extra-mounts-2[ca.crt]: all the certificates expire",
+		},
+		{
+			name:             "invalid annotation",
+			annotation:       "previous",
+			version:          "v1",
+			now:              time.Now(),
+			expectedReason:   "NotRequested",
+			expectedDegraded: "InvalidAnnotation",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			objects := []runtime.Object{
+				configMap("revision-status-2", nil),
+				configMap("kube-controller-manager-pod-2", map[string]string{"version": "v1", "pod.yaml": "old pod"}),
+				configMap("config-2", map[string]string{"config.yaml": "old config"}),
+				configMap("extra-mounts-2", map[string]string{"ca.crt": string(caPEM)}),
+				// the current bundle holds a CA rotated after revision 2
+				configMap("serviceaccount-ca-2", map[string]string{"ca-bundle.crt": string(caPEM)}),
+				configMap("serviceaccount-ca", map[string]string{"ca-bundle.crt": string(caPEM) + string(rotatedCAPEM)}),
+				configMap("kube-controller-manager-pod", map[string]string{"version": "v1", "pod.yaml": "new pod"}),
+				configMap("config", map[string]string{"config.yaml": "new config"}),
+				configMap("kube-controller-manager-flags", map[string]string{"flags": "--v=4"}),
+			}
+			indexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc})
+			for _, object := range objects {
+				if err := indexer.Add(object); err != nil {
+					t.Fatal(err)
+				}
+			}
+			kubeClient := fake.NewSimpleClientset(objects...)
+			operator := &unstructured.Unstructured{}
+			operator.SetName("cluster")
+			if len(test.annotation) > 0 {
+				operator.SetAnnotations(map[string]string{targetconfigcontroller.RollbackAnnotation: test.annotation})
+			}
+			operatorClient := v1helpers.NewFakeStaticPodOperatorClient(
+				&operatorv1.StaticPodOperatorSpec{OperatorSpec: operatorv1.OperatorSpec{ManagementState: operatorv1.Managed}},
+				&operatorv1.StaticPodOperatorStatus{LatestAvailableRevision: 3},
+				nil, nil,
+			)
+			c := &RollbackController{
+				operatorClient:  operatorClient,
+				operatorLister:  fakeOperatorLister{operator: operator},
+				configMapClient: kubeClient.CoreV1(),
+				secretClient:    kubeClient.CoreV1(),
+				configMapLister: corev1listers.NewConfigMapLister(indexer),
+				secretLister:    corev1listers.NewSecretLister(cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc})),
+				operatorVersion: test.version,
+				now:             func() time.Time { return test.now },
+			}
+			recorder := events.NewInMemoryRecorder("test")
+			if err := c.sync(context.TODO(), factory.NewSyncContext("test", recorder)); err != nil {
+				t.Fatal(err)
+			}
+
+			_, status, _, _ := operatorClient.GetStaticPodOperatorState()
+			if condition := v1helpers.FindOperatorCondition(status.Conditions, targetconfigcontroller.RevisionRollbackCondition); condition == nil || condition.Reason != test.expectedReason {
+				t.Errorf("expected the reason %s, got %v", test.expectedReason, condition)
+			}
+			degraded := v1helpers.FindOperatorCondition(status.Conditions, revisionRollbackDegradedCondition)
+			if degraded == nil || degraded.Reason != test.expectedDegraded {
+				t.Fatalf("expected the degraded reason %s, got %v", test.expectedDegraded, degraded)
+			}
+			if !strings.Contains(degraded.Message, test.expectedMessage) {
+				t.Errorf("expected %q in the message, got %q", test.expectedMessage, degraded.Message)
+			}
+
+			config, err := kubeClient.CoreV1().ConfigMaps(operatorclient.TargetNamespace).Get(context.TODO(), "config", metav1.GetOptions{})
+			if err != nil {
+				t.Fatal(err)
+			}
+			_, err = kubeClient.CoreV1().ConfigMaps(operatorclient.TargetNamespace).Get(context.TODO(), "kube-controller-manager-flags", metav1.GetOptions{})
+			if test.expectedRestored {
+				if config.Data["config.yaml"] != "old config" {
+					t.Errorf("expected the config of revision 2, got %q", config.Data["config.yaml"])
+				}
+				if !apierrors.IsNotFound(err) {
+					t.Errorf("expected the flags, which revision 2 did not have, to be deleted, got %v", err)
+				}
+				if _, err := kubeClient.CoreV1().ConfigMaps(operatorclient.TargetNamespace).Get(context.TODO(), "extra-mounts", metav1.GetOptions{}); err != nil {
+					t.Errorf("expected the extra-mounts of revision 2 to be created, got %v", err)
+				}
+			} else {
+				if config.Data["config.yaml"] != "new config" || err != nil {
+					t.Errorf("expected the current resources to be kept, got %q, %v", config.Data["config.yaml"], err)
+				}
+			}
+			// the CA bundles are combined from the current CAs, whatever the revision
+			serviceAccountCA, err := kubeClient.CoreV1().ConfigMaps(operatorclient.TargetNamespace).Get(context.TODO(), "serviceaccount-ca", metav1.GetOptions{})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !strings.Contains(serviceAccountCA.Data["ca-bundle.crt"], string(rotatedCAPEM)) {
+				t.Errorf("expected the serviceaccount-ca to keep the rotated CA, got %q", serviceAccountCA.Data["ca-bundle.crt"])
+			}
+			var rolledBack bool
+			for _, event := range recorder.Events() {
+				rolledBack = rolledBack || event.Reason == "RevisionRolledBack"
+			}
+			if rolledBack != test.expectedRestored {
+				t.Errorf("expected a RevisionRolledBack event %v, got %v", test.expectedRestored, recorder.Events())
+			}
+		})
+	}
+}
@@ -0,0 +1,274 @@
+package rolloutbarrier
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	coordinationv1 "k8s.io/api/coordination/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/klog/v2"
+	"k8s.io/utils/ptr"
+
+	operatorv1 "github.com/openshift/api/operator/v1"
+	"github.com/openshift/library-go/pkg/operator/loglevel"
+	"github.com/openshift/library-go/pkg/operator/staticpod/controller/installer"
+
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/canarycontroller"
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/operatorclient"
+)
+
+const (
+	// LeaseNamespace holds the per node leases of the handshake between the control plane operators.
+	LeaseNamespace = "kube-system"
+
+	// Holder is the holder identity of the leases taken by this operator.
+	Holder = "kube-controller-manager"
+
+	// LeaseDuration covers the install of a revision and the restart of the operand on the node. The lease is not
+	// released, it expires.
+	LeaseDuration = 5 * time.Minute
+
+	// DefaultTimeout bounds the wait for the barrier. The rollout goes ahead afterwards, a stuck peer must not block
+	// it, and the installer pod must not stay pending long enough to be reported degraded.
+	DefaultTimeout = 4 * time.Minute
+
+	initContainerName = "wait-for-rollout-barrier"
+	pollInterval      = 5 * time.Second
+)
+
+// peer is a control plane operand whose static pods are rolled out on the same nodes.
+type peer struct {
+	namespace     string
+	operandLabels map[string]string
+}
+
+var peers = []peer{
+	{namespace: "openshift-etcd", operandLabels: map[string]string{"app": "etcd"}},
+	{namespace: "openshift-kube-apiserver", operandLabels: map[string]string{"app": "openshift-kube-apiserver"}},
+}
+
+// LeaseName is the lease of the node an operator holds while it rolls out a revision of its static pod there.
+func LeaseName(nodeName string) string {
+	return "control-plane-rollout-" + nodeName
+}
+
+// InstallerPodMutationFunc adds an init container running the command to the installer pods, so that the install on a
+// node waits for the barrier.
+func InstallerPodMutationFunc(command []string, timeout time.Duration) installer.InstallerPodMutationFunc {
+	return func(pod *corev1.Pod, nodeName string, operatorSpec *operatorv1.StaticPodOperatorSpec, revision int32) error {
+		installerContainer := pod.Spec.Containers[0]
+		pod.Spec.InitContainers = append(pod.Spec.InitContainers, corev1.Container{
+			Name:    initContainerName,
+			Image:   installerContainer.Image,
+			Command: command,
+			Args: []string{
+				fmt.Sprintf("--node-name=%s", nodeName),
+				fmt.Sprintf("--revision=%d", revision),
+				fmt.Sprintf("--timeout=%s", timeout),
+				fmt.Sprintf("-v=%d", loglevel.LogLevelToVerbosity(operatorSpec.LogLevel)),
+			},
+			ImagePullPolicy:          installerContainer.ImagePullPolicy,
+			Env:                      installerContainer.Env,
+			VolumeMounts:             serviceAccountMounts(installerContainer.VolumeMounts),
+			Resources:                installerContainer.Resources,
+			TerminationMessagePolicy: corev1.TerminationMessageFallbackToLogsOnError,
+		})
+		return nil
+	}
+}
+
+// serviceAccountMounts keeps the mount of the token, the init container doesn't need the host paths of the installer.
+func serviceAccountMounts(mounts []corev1.VolumeMount) []corev1.VolumeMount {
+	var kept []corev1.VolumeMount
+	for _, mount := range mounts {
+		if mount.MountPath == "/var/run/secrets/kubernetes.io/serviceaccount" {
+			kept = append(kept, mount)
+		}
+	}
+	return kept
+}
+
+// errCanaryFailed is returned when the canary rollout of the revision was aborted.
+var errCanaryFailed = errors.New("the canary rollout failed")
+
+// Wait blocks until the revision passed the validation on its canary node, no peer rolls out on the node and no other
+// operator holds the lease of the node, then takes the lease. After the timeout it gives up waiting and returns without
+// the lease, unless it still waits for the canary: the install then fails and the installer controller retries it.
+func Wait(ctx context.Context, client kubernetes.Interface, nodeName string, revision int32, timeout time.Duration) error {
+	var lastReason string
+	var waitingForCanary bool
+	err := wait.PollUntilContextTimeout(ctx, pollInterval, timeout, true, func(ctx context.Context) (bool, error) {
+		reason, err := canaryReason(ctx, client, nodeName, revision)
+		if errors.Is(err, errCanaryFailed) {
+			return false, err
+		}
+		waitingForCanary = len(reason) > 0
+		if err == nil && len(reason) == 0 {
+			reason, err = busyReason(ctx, client, nodeName, time.Now())
+		}
+		if err != nil {
+			// the API may be unavailable while a peer rolls out
+			klog.Warningf("Failed to check the rollout barrier of node %s: %v", nodeName, err)
+			return false, nil
+		}
+		if len(reason) > 0 {
+			if reason != lastReason {
+				klog.Infof("Waiting for the rollout barrier of node %s: %s", nodeName, reason)
+				lastReason = reason
+			}
+			return false, nil
+		}
+		if err := acquire(ctx, client, nodeName, time.Now()); err != nil {
+			// another operator took the lease first
+			klog.Warningf("Failed to take lease/%s -n %s: %v", LeaseName(nodeName), LeaseNamespace, err)
+			return false, nil
+		}
+		return true, nil
+	})
+	if wait.Interrupted(err) && waitingForCanary {
+		return fmt.Errorf("revision %d is not installed on node %s after waiting %s: %s", revision, nodeName, timeout, lastReason)
+	}
+	if wait.Interrupted(err) {
+		klog.Warningf("Proceeding with the rollout on node %s after waiting %s for the rollout barrier: %s", nodeName, timeout, lastReason)
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	klog.Infof("Took lease/%s -n %s for %s", LeaseName(nodeName), LeaseNamespace, LeaseDuration)
+	return nil
+}
+
+// canaryReason returns why the revision is held at its canary node, empty when it is not. It returns errCanaryFailed
+// when the revision failed on the canary node.
+func canaryReason(ctx context.Context, client kubernetes.Interface, nodeName string, revision int32) (string, error) {
+	if revision == 0 {
+		return "", nil
+	}
+	configMap, err := client.CoreV1().ConfigMaps(operatorclient.TargetNamespace).Get(ctx, canarycontroller.GateConfigMapName, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		// the canary rollout is disabled
+		return "", nil
+	}
+	if err != nil {
+		return "", err
+	}
+	gate, err := canarycontroller.GateFromConfigMap(configMap)
+	if err != nil {
+		return "", err
+	}
+	switch {
+	case gate.Revision < revision:
+		return fmt.Sprintf("the canary node of revision %d is not chosen yet", revision), nil
+	case gate.Revision > revision, gate.Node == nodeName:
+		return "", nil
+	case gate.Phase == canarycontroller.FailedPhase:
+		return "", fmt.Errorf("%w: %s", errCanaryFailed, gate.Message)
+	case gate.Phase == canarycontroller.ValidatingPhase:
+		return fmt.Sprintf("revision %d is validated on canary node %s: %s", revision, gate.Node, gate.Message), nil
+	}
+	return "", nil
+}
+
+// busyReason returns why a rollout on the node would overlap with one of another control plane operator, empty when
+// it would not.
+func busyReason(ctx context.Context, client kubernetes.Interface, nodeName string, now time.Time) (string, error) {
+	lease, err := client.CoordinationV1().Leases(LeaseNamespace).Get(ctx, LeaseName(nodeName), metav1.GetOptions{})
+	if err != nil && !apierrors.IsNotFound(err) {
+		return "", err
+	}
+	if err == nil && heldByOther(lease, now) {
+		return fmt.Sprintf("lease/%s -n %s is held by %s", lease.Name, LeaseNamespace, *lease.Spec.HolderIdentity), nil
+	}
+
+	var reasons []string
+	for _, peer := range peers {
+		pods, err := client.CoreV1().Pods(peer.namespace).List(ctx, metav1.ListOptions{FieldSelector: "spec.nodeName=" + nodeName})
+		if err != nil {
+			return "", err
+		}
+		for _, pod := range pods.Items {
+			if pod.Spec.NodeName != nodeName {
+				continue
+			}
+			switch {
+			case pod.Labels["app"] == "installer" && (pod.Status.Phase == corev1.PodPending || pod.Status.Phase == corev1.PodRunning):
+				reasons = append(reasons, fmt.Sprintf("pod/%s -n %s is installing", pod.Name, pod.Namespace))
+			case hasLabels(&pod, peer.operandLabels) && !isPodReady(&pod):
+				reasons = append(reasons, fmt.Sprintf("pod/%s -n %s is not ready", pod.Name, pod.Namespace))
+			}
+		}
+	}
+	sort.Strings(reasons)
+	return strings.Join(reasons, ", "), nil
+}
+
+func heldByOther(lease *coordinationv1.Lease, now time.Time) bool {
+	if lease.Spec.HolderIdentity == nil || *lease.Spec.HolderIdentity == "" || *lease.Spec.HolderIdentity == Holder {
+		return false
+	}
+	if lease.Spec.RenewTime == nil || lease.Spec.LeaseDurationSeconds == nil {
+		return false
+	}
+	return now.Before(lease.Spec.RenewTime.Add(time.Duration(*lease.Spec.LeaseDurationSeconds) * time.Second))
+}
+
+// acquire takes the lease of the node. The update fails on a conflict when another operator took it in the meantime.
+func acquire(ctx context.Context, client kubernetes.Interface, nodeName string, now time.Time) error {
+	spec := coordinationv1.LeaseSpec{
+		HolderIdentity:       ptr.To(Holder),
+		LeaseDurationSeconds: ptr.To(int32(LeaseDuration / time.Second)),
+		AcquireTime:          &metav1.MicroTime{Time: now},
+		RenewTime:            &metav1.MicroTime{Time: now},
+	}
+	leases := client.CoordinationV1().Leases(LeaseNamespace)
+	lease, err := leases.Get(ctx, LeaseName(nodeName), metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		_, err := leases.Create(ctx, &coordinationv1.Lease{
+			ObjectMeta: metav1.ObjectMeta{Namespace: LeaseNamespace, Name: LeaseName(nodeName)},
+			Spec:       spec,
+		}, metav1.CreateOptions{})
+		return err
+	}
+	if err != nil {
+		return err
+	}
+	if heldByOther(lease, now) {
+		return fmt.Errorf("held by %s", *lease.Spec.HolderIdentity)
+	}
+	lease = lease.DeepCopy()
+	if lease.Spec.LeaseTransitions == nil {
+		lease.Spec.LeaseTransitions = ptr.To(int32(0))
+	}
+	*lease.Spec.LeaseTransitions++
+	spec.LeaseTransitions = lease.Spec.LeaseTransitions
+	lease.Spec = spec
+	_, err = leases.Update(ctx, lease, metav1.UpdateOptions{})
+	return err
+}
+
+func hasLabels(pod *corev1.Pod, labels map[string]string) bool {
+	for key, value := range labels {
+		if pod.Labels[key] != value {
+			return false
+		}
+	}
+	return true
+}
+
+func isPodReady(pod *corev1.Pod) bool {
+	for _, condition := range pod.Status.Conditions {
+		if condition.Type == corev1.PodReady {
+			return condition.Status == corev1.ConditionTrue
+		}
+	}
+	return false
+}
@@ -0,0 +1,212 @@
+package rolloutbarrier
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	coordinationv1 "k8s.io/api/coordination/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+	"k8s.io/utils/ptr"
+
+	operatorv1 "github.com/openshift/api/operator/v1"
+
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/canarycontroller"
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/operatorclient"
+)
+
+func TestBusyReason(t *testing.T) {
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	pod := func(namespace, name, app, nodeName string, phase corev1.PodPhase, ready bool) *corev1.Pod {
+		status := corev1.ConditionFalse
+		if ready {
+			status = corev1.ConditionTrue
+		}
+		return &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: name, Labels: map[string]string{"app": app}},
+			Spec:       corev1.PodSpec{NodeName: nodeName},
+			Status: corev1.PodStatus{
+				Phase:      phase,
+				Conditions: []corev1.PodCondition{{Type: corev1.PodReady, Status: status}},
+			},
+		}
+	}
+	lease := func(holder string, renewed time.Time) *coordinationv1.Lease {
+		return &coordinationv1.Lease{
+			ObjectMeta: metav1.ObjectMeta{Namespace: LeaseNamespace, Name: LeaseName("master-0")},
+			Spec: coordinationv1.LeaseSpec{
+				HolderIdentity:       ptr.To(holder),
+				LeaseDurationSeconds: ptr.To(int32(300)),
+				RenewTime:            &metav1.MicroTime{Time: renewed},
+			},
+		}
+	}
+	tests := []struct {
+		name     string
+		objects  []runtime.Object
+		expected string
+	}{
+		{
+			name: "settled",
+			objects: []runtime.Object{
+				pod("openshift-etcd", "etcd-master-0", "etcd", "master-0", corev1.PodRunning, true),
+				pod("openshift-etcd", "installer-3-master-0", "installer", "master-0", corev1.PodSucceeded, false),
+				pod("openshift-kube-apiserver", "kube-apiserver-master-0", "openshift-kube-apiserver", "master-0", corev1.PodRunning, true),
+			},
+		},
+		{
+			name: "installing on another node",
+			objects: []runtime.Object{
+				pod("openshift-kube-apiserver", "installer-7-master-1", "installer", "master-1", corev1.PodRunning, true),
+			},
+		},
+		{
+			name: "kube-apiserver installing",
+			objects: []runtime.Object{
+				pod("openshift-kube-apiserver", "installer-7-master-0", "installer", "master-0", corev1.PodRunning, true),
+			},
+			expected: "pod/installer-7-master-0 -n openshift-kube-apiserver is installing",
+		},
+		{
+			name: "etcd restarting",
+			objects: []runtime.Object{
+				pod("openshift-etcd", "etcd-master-0", "etcd", "master-0", corev1.PodRunning, false),
+			},
+			expected: "pod/etcd-master-0 -n openshift-etcd is not ready",
+		},
+		{
+			name:     "lease held by another operator",
+			objects:  []runtime.Object{lease("kube-apiserver", now.Add(-time.Minute))},
+			expected: "lease/control-plane-rollout-master-0 -n kube-system is held by kube-apiserver",
+		},
+		{
+			name:    "lease expired",
+			objects: []runtime.Object{lease("kube-apiserver", now.Add(-10*time.Minute))},
+		},
+		{
+			name:    "lease held by this operator",
+			objects: []runtime.Object{lease(Holder, now.Add(-time.Minute))},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			actual, err := busyReason(context.TODO(), fake.NewSimpleClientset(test.objects...), "master-0", now)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if actual != test.expected {
+				t.Errorf("expected %q, got %q", test.expected, actual)
+			}
+		})
+	}
+}
+
+func TestCanaryReason(t *testing.T) {
+	gate := func(revision, node, phase string) *corev1.ConfigMap {
+		return &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Namespace: operatorclient.TargetNamespace, Name: canarycontroller.GateConfigMapName},
+			Data:       map[string]string{"revision": revision, "node": node, "phase": phase, "message": "pod/kube-controller-manager-master-1 is not ready"},
+		}
+	}
+	tests := []struct {
+		name          string
+		objects       []runtime.Object
+		revision      int32
+		expected      string
+		expectedError bool
+	}{
+		{name: "disabled", revision: 4},
+		{name: "no revision", objects: []runtime.Object{gate("4", "master-1", "Validating")}},
+		{name: "canary", objects: []runtime.Object{gate("4", "master-0", "Validating")}, revision: 4},
+		{
+			name:     "canary not chosen yet",
+			objects:  []runtime.Object{gate("3", "master-1", "Passed")},
+			revision: 4,
+			expected: "the canary node of revision 4 is not chosen yet",
+		},
+		{
+			name:     "validating",
+			objects:  []runtime.Object{gate("4", "master-1", "Validating")},
+			revision: 4,
+			expected: "revision 4 is validated on canary node master-1: pod/kube-controller-manager-master-1 is not ready",
+		},
+		{name: "passed", objects: []runtime.Object{gate("4", "master-1", "Passed")}, revision: 4},
+		{name: "failed", objects: []runtime.Object{gate("4", "master-1", "Failed")}, revision: 4, expectedError: true},
+		{name: "older revision", objects: []runtime.Object{gate("5", "master-1", "Failed")}, revision: 4},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			actual, err := canaryReason(context.TODO(), fake.NewSimpleClientset(test.objects...), "master-0", test.revision)
+			if test.expectedError != errors.Is(err, errCanaryFailed) {
+				t.Fatalf("expected error %v, got %v", test.expectedError, err)
+			}
+			if actual != test.expected {
+				t.Errorf("expected %q, got %q", test.expected, actual)
+			}
+		})
+	}
+}
+
+func TestAcquire(t *testing.T) {
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	client := fake.NewSimpleClientset()
+	if err := acquire(context.TODO(), client, "master-0", now); err != nil {
+		t.Fatal(err)
+	}
+	// the lease of this operator is taken again on the next rollout
+	if err := acquire(context.TODO(), client, "master-0", now.Add(time.Minute)); err != nil {
+		t.Fatal(err)
+	}
+	lease, err := client.CoordinationV1().Leases(LeaseNamespace).Get(context.TODO(), LeaseName("master-0"), metav1.GetOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if *lease.Spec.HolderIdentity != Holder || !lease.Spec.RenewTime.Time.Equal(now.Add(time.Minute)) || *lease.Spec.LeaseTransitions != 1 {
+		t.Errorf("unexpected lease %v", lease.Spec)
+	}
+
+	lease.Spec.HolderIdentity = ptr.To("etcd")
+	if _, err := client.CoordinationV1().Leases(LeaseNamespace).Update(context.TODO(), lease, metav1.UpdateOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	if err := acquire(context.TODO(), client, "master-0", now.Add(2*time.Minute)); err == nil {
+		t.Errorf("expected the lease held by etcd not to be taken")
+	}
+}
+
+func TestInstallerPodMutationFunc(t *testing.T) {
+	pod := &corev1.Pod{
+		Spec: corev1.PodSpec{
+			Containers: []corev1.Container{{
+				Name:  "installer",
+				Image: "operator-image",
+				VolumeMounts: []corev1.VolumeMount{
+					{Name: "kubelet-dir", MountPath: "/etc/kubernetes/"},
+					{Name: "kube-api-access", MountPath: "/var/run/secrets/kubernetes.io/serviceaccount", ReadOnly: true},
+				},
+			}},
+		},
+	}
+	mutate := InstallerPodMutationFunc([]string{"operator", "rollout-barrier"}, 2*time.Minute)
+	if err := mutate(pod, "master-0", &operatorv1.StaticPodOperatorSpec{}, 3); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(pod.Spec.InitContainers) != 1 {
+		t.Fatalf("expected an init container, got %d", len(pod.Spec.InitContainers))
+	}
+	initContainer := pod.Spec.InitContainers[0]
+	if initContainer.Image != "operator-image" {
+		t.Errorf("expected the installer image, got %q", initContainer.Image)
+	}
+	if initContainer.Args[0] != "--node-name=master-0" || initContainer.Args[1] != "--revision=3" || initContainer.Args[2] != "--timeout=2m0s" {
+		t.Errorf("unexpected args %v", initContainer.Args)
+	}
+	if len(initContainer.VolumeMounts) != 1 || initContainer.VolumeMounts[0].Name != "kube-api-access" {
+		t.Errorf("expected only the service account mount, got %v", initContainer.VolumeMounts)
+	}
+}
@@ -0,0 +1,169 @@
+package rolloutordercontroller
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	corev1listers "k8s.io/client-go/listers/core/v1"
+
+	operatorv1 "github.com/openshift/api/operator/v1"
+	"github.com/openshift/library-go/pkg/controller/factory"
+	"github.com/openshift/library-go/pkg/operator/events"
+	"github.com/openshift/library-go/pkg/operator/management"
+	"github.com/openshift/library-go/pkg/operator/v1helpers"
+
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/operatorclient"
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/tuning"
+)
+
+const nodeRolloutOrderCondition = "NodeRolloutOrder"
+
+// RolloutOrderController applies the rolloutNodeOrder of the tuning config to the node statuses. The installer
+// controller starts a rollout with the first node of the node statuses that needs the new revision and continues
+// with the following ones, so their order is the order the nodes receive new revisions in. The order is only changed
+// while no rollout is in progress and is reported in the NodeRolloutOrder condition.
+type RolloutOrderController struct {
+	operatorClient  v1helpers.StaticPodOperatorClient
+	configMapLister corev1listers.ConfigMapLister
+}
+
+func NewRolloutOrderController(
+	operatorClient v1helpers.StaticPodOperatorClient,
+	kubeInformersForNamespaces v1helpers.KubeInformersForNamespaces,
+	eventRecorder events.Recorder,
+) factory.Controller {
+	c := &RolloutOrderController{
+		operatorClient:  operatorClient,
+		configMapLister: kubeInformersForNamespaces.ConfigMapLister(),
+	}
+
+	return factory.New().WithFilteredEventsInformers(
+		factory.NamesFilter(tuning.ConfigMapName),
+		kubeInformersForNamespaces.InformersFor(operatorclient.OperatorNamespace).Core().V1().ConfigMaps().Informer(),
+	).WithInformers(
+		operatorClient.Informer(),
+	).ResyncEvery(time.Minute).WithSync(c.sync).ToController("RolloutOrderController", eventRecorder)
+}
+
+func (c *RolloutOrderController) sync(ctx context.Context, syncCtx factory.SyncContext) error {
+	operatorSpec, _, _, err := c.operatorClient.GetStaticPodOperatorState()
+	if err != nil {
+		return err
+	}
+	if !management.IsOperatorManaged(operatorSpec.ManagementState) {
+		return nil
+	}
+	tuningConfig, err := tuning.Get(c.configMapLister)
+	if err != nil {
+		return err
+	}
+
+	var reordered []string
+	_, _, err = v1helpers.UpdateStaticPodStatus(ctx, c.operatorClient, func(status *operatorv1.StaticPodOperatorStatus) error {
+		// the node statuses are ordered on the latest status, the installer controller may have moved on since
+		reordered = nil
+		ordered := orderNodeStatuses(status.NodeStatuses, tuningConfig.RolloutNodeOrder)
+		if !isRollingOut(status) && !sameOrder(ordered, status.NodeStatuses) {
+			status.NodeStatuses = ordered
+			reordered = nodeNames(ordered)
+		}
+		v1helpers.SetOperatorCondition(&status.Conditions, nodeRolloutOrderConditionFor(status, tuningConfig.RolloutNodeOrder))
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	if len(reordered) > 0 {
+		syncCtx.Recorder().Eventf("NodeRolloutOrderChanged", "Revisions roll out to the nodes in the order %s", strings.Join(reordered, ", "))
+	}
+	return nil
+}
+
+// orderNodeStatuses returns the node statuses with the pinned nodes first, in the pinned order, followed by the other
+// nodes in their current order. Pinned nodes without a node status are skipped.
+func orderNodeStatuses(nodeStatuses []operatorv1.NodeStatus, pinned []string) []operatorv1.NodeStatus {
+	ordered := make([]operatorv1.NodeStatus, 0, len(nodeStatuses))
+	placed := map[string]bool{}
+	for _, name := range pinned {
+		for _, nodeStatus := range nodeStatuses {
+			if nodeStatus.NodeName == name {
+				ordered = append(ordered, nodeStatus)
+				placed[name] = true
+			}
+		}
+	}
+	for _, nodeStatus := range nodeStatuses {
+		if !placed[nodeStatus.NodeName] {
+			ordered = append(ordered, nodeStatus)
+		}
+	}
+	return ordered
+}
+
+// isRollingOut returns whether a node is installing a revision or not at the latest available revision yet. The
+// installer controller walks the node statuses by their index, reordering them then would skip or repeat nodes.
+func isRollingOut(status *operatorv1.StaticPodOperatorStatus) bool {
+	for _, nodeStatus := range status.NodeStatuses {
+		if nodeStatus.TargetRevision != 0 || nodeStatus.CurrentRevision != status.LatestAvailableRevision {
+			return true
+		}
+	}
+	return false
+}
+
+func sameOrder(a, b []operatorv1.NodeStatus) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i].NodeName != b[i].NodeName {
+			return false
+		}
+	}
+	return true
+}
+
+func nodeRolloutOrderConditionFor(status *operatorv1.StaticPodOperatorStatus, pinned []string) operatorv1.OperatorCondition {
+	condition := operatorv1.OperatorCondition{
+		Type:   nodeRolloutOrderCondition,
+		Status: operatorv1.ConditionFalse,
+		Reason: "NotPinned",
+	}
+	if len(pinned) == 0 {
+		return condition
+	}
+
+	condition.Status = operatorv1.ConditionTrue
+	condition.Reason = "Pinned"
+	if !sameOrder(orderNodeStatuses(status.NodeStatuses, pinned), status.NodeStatuses) {
+		condition.Reason = "PendingRollout"
+		condition.Message = fmt.Sprintf("The rollout in progress continues in the order %s, the pinned order applies to the next rollout", strings.Join(nodeNames(status.NodeStatuses), ", "))
+	} else {
+		condition.Message = fmt.Sprintf("Revisions roll out to the nodes in the order %s", strings.Join(nodeNames(status.NodeStatuses), ", "))
+	}
+
+	known := map[string]bool{}
+	for _, nodeStatus := range status.NodeStatuses {
+		known[nodeStatus.NodeName] = true
+	}
+	var unknown []string
+	for _, name := range pinned {
+		if !known[name] {
+			unknown = append(unknown, name)
+		}
+	}
+	if len(unknown) > 0 {
+		condition.Message += fmt.Sprintf("\nThe pinned nodes %s are not control plane nodes", strings.Join(unknown, ", "))
+	}
+	return condition
+}
+
+func nodeNames(nodeStatuses []operatorv1.NodeStatus) []string {
+	var names []string
+	for _, nodeStatus := range nodeStatuses {
+		names = append(names, nodeStatus.NodeName)
+	}
+	return names
+}
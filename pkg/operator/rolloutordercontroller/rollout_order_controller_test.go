@@ -0,0 +1,119 @@
+package rolloutordercontroller
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	corev1listers "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/tools/cache"
+
+	operatorv1 "github.com/openshift/api/operator/v1"
+	"github.com/openshift/library-go/pkg/controller/factory"
+	"github.com/openshift/library-go/pkg/operator/events"
+	"github.com/openshift/library-go/pkg/operator/v1helpers"
+
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/operatorclient"
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/tuning"
+)
+
+func TestOrderNodeStatuses(t *testing.T) {
+	nodeStatuses := []operatorv1.NodeStatus{{NodeName: "master-0"}, {NodeName: "master-1"}, {NodeName: "master-2"}}
+	tests := []struct {
+		name     string
+		pinned   []string
+		expected []string
+	}{
+		{name: "not pinned", expected: []string{"master-0", "master-1", "master-2"}},
+		{name: "canary first", pinned: []string{"master-2"}, expected: []string{"master-2", "master-0", "master-1"}},
+		{name: "fully pinned", pinned: []string{"master-1", "master-2", "master-0"}, expected: []string{"master-1", "master-2", "master-0"}},
+		{name: "unknown node", pinned: []string{"master-3", "master-1"}, expected: []string{"master-1", "master-0", "master-2"}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if actual := nodeNames(orderNodeStatuses(nodeStatuses, test.pinned)); !reflect.DeepEqual(actual, test.expected) {
+				t.Errorf("expected %v, got %v", test.expected, actual)
+			}
+		})
+	}
+}
+
+func TestSync(t *testing.T) {
+	indexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc})
+	if err := indexer.Add(&corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Namespace: operatorclient.OperatorNamespace, Name: tuning.ConfigMapName},
+		Data:       map[string]string{tuning.ConfigKey: "rolloutNodeOrder: [master-2, master-4]"},
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name              string
+		nodeStatuses      []operatorv1.NodeStatus
+		expectedOrder     []string
+		expectedReason    string
+		expectedMessages  []string
+		expectedReordered bool
+	}{
+		{
+			name: "between rollouts",
+			nodeStatuses: []operatorv1.NodeStatus{
+				{NodeName: "master-0", CurrentRevision: 3},
+				{NodeName: "master-1", CurrentRevision: 3},
+				{NodeName: "master-2", CurrentRevision: 3},
+			},
+			expectedOrder:     []string{"master-2", "master-0", "master-1"},
+			expectedReason:    "Pinned",
+			expectedMessages:  []string{"in the order master-2, master-0, master-1", "master-4 are not control plane nodes"},
+			expectedReordered: true,
+		},
+		{
+			name: "rolling out",
+			nodeStatuses: []operatorv1.NodeStatus{
+				{NodeName: "master-0", CurrentRevision: 3, TargetRevision: 4},
+				{NodeName: "master-1", CurrentRevision: 3},
+				{NodeName: "master-2", CurrentRevision: 3},
+			},
+			expectedOrder:    []string{"master-0", "master-1", "master-2"},
+			expectedReason:   "PendingRollout",
+			expectedMessages: []string{"continues in the order master-0, master-1, master-2"},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			operatorClient := v1helpers.NewFakeStaticPodOperatorClient(
+				&operatorv1.StaticPodOperatorSpec{OperatorSpec: operatorv1.OperatorSpec{ManagementState: operatorv1.Managed}},
+				&operatorv1.StaticPodOperatorStatus{LatestAvailableRevision: 3, NodeStatuses: test.nodeStatuses},
+				nil, nil,
+			)
+			c := &RolloutOrderController{
+				operatorClient:  operatorClient,
+				configMapLister: corev1listers.NewConfigMapLister(indexer),
+			}
+			recorder := events.NewInMemoryRecorder("test")
+			if err := c.sync(context.TODO(), factory.NewSyncContext("test", recorder)); err != nil {
+				t.Fatal(err)
+			}
+
+			_, status, _, _ := operatorClient.GetStaticPodOperatorState()
+			if actual := nodeNames(status.NodeStatuses); !reflect.DeepEqual(actual, test.expectedOrder) {
+				t.Errorf("expected the order %v, got %v", test.expectedOrder, actual)
+			}
+			condition := v1helpers.FindOperatorCondition(status.Conditions, nodeRolloutOrderCondition)
+			if condition == nil || condition.Reason != test.expectedReason {
+				t.Fatalf("expected the reason %s, got %v", test.expectedReason, condition)
+			}
+			for _, message := range test.expectedMessages {
+				if !strings.Contains(condition.Message, message) {
+					t.Errorf("expected %q in the message, got %q", message, condition.Message)
+				}
+			}
+			if reordered := len(recorder.Events()) > 0; reordered != test.expectedReordered {
+				t.Errorf("expected a NodeRolloutOrderChanged event %v, got %v", test.expectedReordered, recorder.Events())
+			}
+		})
+	}
+}
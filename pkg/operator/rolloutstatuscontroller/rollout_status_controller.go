@@ -0,0 +1,242 @@
+package rolloutstatuscontroller
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	corev1client "k8s.io/client-go/kubernetes/typed/core/v1"
+	corev1listers "k8s.io/client-go/listers/core/v1"
+	"k8s.io/klog/v2"
+
+	operatorv1 "github.com/openshift/api/operator/v1"
+	"github.com/openshift/library-go/pkg/controller/factory"
+	"github.com/openshift/library-go/pkg/operator/events"
+	"github.com/openshift/library-go/pkg/operator/resource/resourceapply"
+	"github.com/openshift/library-go/pkg/operator/v1helpers"
+
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/operatorclient"
+)
+
+const (
+	// rolloutConfigMapName holds the RolloutStatus of the static pods.
+	rolloutConfigMapName = "kube-controller-manager-rollout-status"
+	rolloutKey           = "rollout.json"
+
+	rolloutStalledCondition = "RolloutStalled"
+
+	// StalledThreshold is how long a rollout may go without any node changing its revision before it is reported
+	// as stalled.
+	StalledThreshold = 30 * time.Minute
+
+	// recentDurations is the number of node rollout durations the ETA is based on.
+	recentDurations = 5
+)
+
+// RolloutStatus is a structured view of the rollout of the static pods to the nodes.
+type RolloutStatus struct {
+	// LatestAvailableRevision is the revision the nodes are rolling to.
+	LatestAvailableRevision int32 `json:"latestAvailableRevision"`
+	// Nodes are the rollout states of the nodes, sorted by name.
+	Nodes []NodeRollout `json:"nodes"`
+	// RecentNodeRolloutDurations are the durations of the last node rollouts, oldest first.
+	RecentNodeRolloutDurations []metav1.Duration `json:"recentNodeRolloutDurations,omitempty"`
+	// EstimatedCompletionTime is when the nodes are expected to be on the LatestAvailableRevision, based on the
+	// average of the RecentNodeRolloutDurations. It is unset when no rollout is in progress or nothing is known
+	// about past rollouts.
+	EstimatedCompletionTime *metav1.Time `json:"estimatedCompletionTime,omitempty"`
+}
+
+// NodeRollout is the rollout state of a node.
+type NodeRollout struct {
+	NodeName        string `json:"nodeName"`
+	CurrentRevision int32  `json:"currentRevision"`
+	TargetRevision  int32  `json:"targetRevision,omitempty"`
+	// LastTransitionTime is the last time the current or target revision of the node changed.
+	LastTransitionTime metav1.Time `json:"lastTransitionTime"`
+	// RolloutStartTime is when the node started rolling to its target revision.
+	RolloutStartTime *metav1.Time `json:"rolloutStartTime,omitempty"`
+}
+
+// RolloutStatusController publishes the RolloutStatus in the rolloutConfigMapName configmap and reports the
+// RolloutStalled condition. The node statuses only tell where each node is, not for how long or how long the
+// remaining nodes will take, which is what an admin waiting on an upgrade asks.
+type RolloutStatusController struct {
+	operatorClient  v1helpers.StaticPodOperatorClient
+	configMapClient corev1client.ConfigMapsGetter
+	configMapLister corev1listers.ConfigMapLister
+	now             func() time.Time
+}
+
+func NewRolloutStatusController(
+	operatorClient v1helpers.StaticPodOperatorClient,
+	kubeInformersForNamespaces v1helpers.KubeInformersForNamespaces,
+	kubeClient kubernetes.Interface,
+	eventRecorder events.Recorder,
+) factory.Controller {
+	c := &RolloutStatusController{
+		operatorClient:  operatorClient,
+		configMapClient: v1helpers.CachedConfigMapGetter(kubeClient.CoreV1(), kubeInformersForNamespaces),
+		configMapLister: kubeInformersForNamespaces.ConfigMapLister(),
+		now:             time.Now,
+	}
+
+	return factory.New().WithInformers(
+		operatorClient.Informer(),
+		kubeInformersForNamespaces.InformersFor(operatorclient.OperatorNamespace).Core().V1().ConfigMaps().Informer(),
+	).ResyncEvery(time.Minute).WithSync(c.sync).ToController("RolloutStatusController", eventRecorder)
+}
+
+func (c *RolloutStatusController) sync(ctx context.Context, syncCtx factory.SyncContext) error {
+	_, operatorStatus, _, err := c.operatorClient.GetStaticPodOperatorState()
+	if err != nil {
+		return err
+	}
+
+	previous := &RolloutStatus{}
+	configMap, err := c.configMapLister.ConfigMaps(operatorclient.OperatorNamespace).Get(rolloutConfigMapName)
+	switch {
+	case apierrors.IsNotFound(err):
+	case err != nil:
+		return err
+	default:
+		if err := json.Unmarshal([]byte(configMap.Data[rolloutKey]), previous); err != nil {
+			// start over, the times of the nodes are only as accurate as the first sync
+			klog.Warningf("Ignoring the invalid %s/%s: %v", operatorclient.OperatorNamespace, rolloutConfigMapName, err)
+			previous = &RolloutStatus{}
+		}
+	}
+
+	now := c.now()
+	rollout := updateRolloutStatus(previous, operatorStatus, now)
+	rolloutBytes, err := json.MarshalIndent(rollout, "", "  ")
+	if err != nil {
+		return err
+	}
+	if _, _, err := resourceapply.ApplyConfigMap(ctx, c.configMapClient, syncCtx.Recorder(), &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Namespace: operatorclient.OperatorNamespace, Name: rolloutConfigMapName},
+		Data:       map[string]string{rolloutKey: string(rolloutBytes)},
+	}); err != nil {
+		return err
+	}
+
+	_, _, err = v1helpers.UpdateStaticPodStatus(ctx, c.operatorClient, v1helpers.UpdateStaticPodConditionFn(rolloutStalledConditionFor(rollout, now)))
+	return err
+}
+
+// updateRolloutStatus carries the times of the previous status forward to the current node statuses.
+func updateRolloutStatus(previous *RolloutStatus, operatorStatus *operatorv1.StaticPodOperatorStatus, now time.Time) *RolloutStatus {
+	previousNodes := map[string]NodeRollout{}
+	for _, node := range previous.Nodes {
+		previousNodes[node.NodeName] = node
+	}
+
+	rollout := &RolloutStatus{
+		LatestAvailableRevision:    operatorStatus.LatestAvailableRevision,
+		RecentNodeRolloutDurations: previous.RecentNodeRolloutDurations,
+	}
+	for _, nodeStatus := range operatorStatus.NodeStatuses {
+		node, known := previousNodes[nodeStatus.NodeName]
+		if !known {
+			rollout.Nodes = append(rollout.Nodes, NodeRollout{
+				NodeName:           nodeStatus.NodeName,
+				CurrentRevision:    nodeStatus.CurrentRevision,
+				TargetRevision:     nodeStatus.TargetRevision,
+				LastTransitionTime: metav1.NewTime(now),
+			})
+			continue
+		}
+
+		if node.CurrentRevision != nodeStatus.CurrentRevision || node.TargetRevision != nodeStatus.TargetRevision {
+			node.LastTransitionTime = metav1.NewTime(now)
+		}
+		if node.CurrentRevision != nodeStatus.CurrentRevision && node.RolloutStartTime != nil {
+			rollout.RecentNodeRolloutDurations = append(rollout.RecentNodeRolloutDurations, metav1.Duration{Duration: now.Sub(node.RolloutStartTime.Time)})
+			node.RolloutStartTime = nil
+		}
+		if nodeStatus.TargetRevision > nodeStatus.CurrentRevision && node.RolloutStartTime == nil {
+			node.RolloutStartTime = &metav1.Time{Time: now}
+		}
+		node.CurrentRevision = nodeStatus.CurrentRevision
+		node.TargetRevision = nodeStatus.TargetRevision
+		rollout.Nodes = append(rollout.Nodes, node)
+	}
+	sort.Slice(rollout.Nodes, func(i, j int) bool { return rollout.Nodes[i].NodeName < rollout.Nodes[j].NodeName })
+	if extra := len(rollout.RecentNodeRolloutDurations) - recentDurations; extra > 0 {
+		rollout.RecentNodeRolloutDurations = rollout.RecentNodeRolloutDurations[extra:]
+	}
+
+	rollout.EstimatedCompletionTime = estimateCompletionTime(rollout)
+	return rollout
+}
+
+// estimateCompletionTime assumes the nodes that are not on the latest revision roll one after the other, each
+// taking the average of the recent node rollouts, starting with the node that is rolling now or else the last
+// node that changed.
+func estimateCompletionTime(rollout *RolloutStatus) *metav1.Time {
+	if len(rollout.RecentNodeRolloutDurations) == 0 {
+		return nil
+	}
+	var total time.Duration
+	for _, duration := range rollout.RecentNodeRolloutDurations {
+		total += duration.Duration
+	}
+	average := total / time.Duration(len(rollout.RecentNodeRolloutDurations))
+
+	remaining := 0
+	var start time.Time
+	for _, node := range rollout.Nodes {
+		if node.CurrentRevision == rollout.LatestAvailableRevision {
+			continue
+		}
+		remaining++
+		if node.RolloutStartTime != nil {
+			start = node.RolloutStartTime.Time
+		}
+	}
+	if remaining == 0 {
+		return nil
+	}
+	if start.IsZero() {
+		for _, node := range rollout.Nodes {
+			if node.LastTransitionTime.After(start) {
+				start = node.LastTransitionTime.Time
+			}
+		}
+	}
+	return &metav1.Time{Time: start.Add(time.Duration(remaining) * average)}
+}
+
+// rolloutStalledConditionFor reports a rollout in which no node changed its revision for the StalledThreshold.
+func rolloutStalledConditionFor(rollout *RolloutStatus, now time.Time) operatorv1.OperatorCondition {
+	condition := operatorv1.OperatorCondition{
+		Type:   rolloutStalledCondition,
+		Status: operatorv1.ConditionFalse,
+	}
+
+	var pending []string
+	var lastTransition time.Time
+	for _, node := range rollout.Nodes {
+		if node.LastTransitionTime.After(lastTransition) {
+			lastTransition = node.LastTransitionTime.Time
+		}
+		if node.CurrentRevision != rollout.LatestAvailableRevision {
+			pending = append(pending, fmt.Sprintf("%s (revision %d)", node.NodeName, node.CurrentRevision))
+		}
+	}
+	if len(pending) == 0 || now.Sub(lastTransition) < StalledThreshold {
+		return condition
+	}
+
+	condition.Status = operatorv1.ConditionTrue
+	condition.Reason = "NoNodeProgress"
+	condition.Message = fmt.Sprintf("No node changed its revision since %s while rolling to revision %d, waiting on %s", lastTransition.UTC().Format(time.RFC3339), rollout.LatestAvailableRevision, strings.Join(pending, ", "))
+	return condition
+}
@@ -0,0 +1,103 @@
+package rolloutstatuscontroller
+
+import (
+	"testing"
+	"time"
+
+	operatorv1 "github.com/openshift/api/operator/v1"
+)
+
+func TestUpdateRolloutStatus(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	statusWith := func(latest int32, nodes ...operatorv1.NodeStatus) *operatorv1.StaticPodOperatorStatus {
+		return &operatorv1.StaticPodOperatorStatus{
+			LatestAvailableRevision: latest,
+			NodeStatuses:            nodes,
+		}
+	}
+
+	// revision 2 rolls out to master-0, then master-1
+	rollout := updateRolloutStatus(&RolloutStatus{}, statusWith(1,
+		operatorv1.NodeStatus{NodeName: "master-1", CurrentRevision: 1},
+		operatorv1.NodeStatus{NodeName: "master-0", CurrentRevision: 1},
+	), start)
+	if rollout.Nodes[0].NodeName != "master-0" {
+		t.Errorf("expected the nodes sorted by name, got %v", rollout.Nodes)
+	}
+	if rollout.EstimatedCompletionTime != nil {
+		t.Errorf("expected no ETA without a rollout, got %v", rollout.EstimatedCompletionTime)
+	}
+
+	rollout = updateRolloutStatus(rollout, statusWith(2,
+		operatorv1.NodeStatus{NodeName: "master-0", CurrentRevision: 1, TargetRevision: 2},
+		operatorv1.NodeStatus{NodeName: "master-1", CurrentRevision: 1},
+	), start.Add(time.Minute))
+	if rollout.Nodes[0].RolloutStartTime == nil || !rollout.Nodes[0].RolloutStartTime.Time.Equal(start.Add(time.Minute)) {
+		t.Errorf("expected master-0 to start rolling, got %v", rollout.Nodes[0])
+	}
+	if rollout.EstimatedCompletionTime != nil {
+		t.Errorf("expected no ETA without past rollouts, got %v", rollout.EstimatedCompletionTime)
+	}
+
+	rollout = updateRolloutStatus(rollout, statusWith(2,
+		operatorv1.NodeStatus{NodeName: "master-0", CurrentRevision: 2},
+		operatorv1.NodeStatus{NodeName: "master-1", CurrentRevision: 1},
+	), start.Add(5*time.Minute))
+	if len(rollout.RecentNodeRolloutDurations) != 1 || rollout.RecentNodeRolloutDurations[0].Duration != 4*time.Minute {
+		t.Fatalf("expected a 4m node rollout, got %v", rollout.RecentNodeRolloutDurations)
+	}
+	if rollout.Nodes[0].RolloutStartTime != nil || !rollout.Nodes[0].LastTransitionTime.Time.Equal(start.Add(5*time.Minute)) {
+		t.Errorf("expected master-0 to finish rolling, got %v", rollout.Nodes[0])
+	}
+	if rollout.EstimatedCompletionTime == nil || !rollout.EstimatedCompletionTime.Time.Equal(start.Add(9*time.Minute)) {
+		t.Errorf("expected the ETA 4m after the last transition, got %v", rollout.EstimatedCompletionTime)
+	}
+
+	// an unchanged node status keeps its times
+	rollout = updateRolloutStatus(rollout, statusWith(2,
+		operatorv1.NodeStatus{NodeName: "master-0", CurrentRevision: 2},
+		operatorv1.NodeStatus{NodeName: "master-1", CurrentRevision: 1, TargetRevision: 2},
+	), start.Add(6*time.Minute))
+	if !rollout.Nodes[0].LastTransitionTime.Time.Equal(start.Add(5 * time.Minute)) {
+		t.Errorf("expected master-0 to keep its transition time, got %v", rollout.Nodes[0])
+	}
+	if rollout.EstimatedCompletionTime == nil || !rollout.EstimatedCompletionTime.Time.Equal(start.Add(10*time.Minute)) {
+		t.Errorf("expected the ETA 4m after master-1 started, got %v", rollout.EstimatedCompletionTime)
+	}
+
+	rollout = updateRolloutStatus(rollout, statusWith(2,
+		operatorv1.NodeStatus{NodeName: "master-0", CurrentRevision: 2},
+		operatorv1.NodeStatus{NodeName: "master-1", CurrentRevision: 2},
+	), start.Add(8*time.Minute))
+	if len(rollout.RecentNodeRolloutDurations) != 2 || rollout.RecentNodeRolloutDurations[1].Duration != 2*time.Minute {
+		t.Errorf("expected a 2m node rollout, got %v", rollout.RecentNodeRolloutDurations)
+	}
+	if rollout.EstimatedCompletionTime != nil {
+		t.Errorf("expected no ETA after the rollout, got %v", rollout.EstimatedCompletionTime)
+	}
+}
+
+func TestRolloutStalledCondition(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	rollout := updateRolloutStatus(&RolloutStatus{}, &operatorv1.StaticPodOperatorStatus{
+		LatestAvailableRevision: 2,
+		NodeStatuses: []operatorv1.NodeStatus{
+			{NodeName: "master-0", CurrentRevision: 2},
+			{NodeName: "master-1", CurrentRevision: 1, TargetRevision: 2},
+		},
+	}, start)
+
+	if condition := rolloutStalledConditionFor(rollout, start.Add(StalledThreshold-time.Second)); condition.Status != operatorv1.ConditionFalse {
+		t.Errorf("expected the rollout not to be stalled yet, got %v", condition)
+	}
+	condition := rolloutStalledConditionFor(rollout, start.Add(StalledThreshold))
+	if condition.Status != operatorv1.ConditionTrue || condition.Reason != "NoNodeProgress" {
+		t.Errorf("expected the rollout to be stalled, got %v", condition)
+	}
+
+	rollout.LatestAvailableRevision = 1
+	rollout.Nodes[0].CurrentRevision = 1
+	if condition := rolloutStalledConditionFor(rollout, start.Add(time.Hour)); condition.Status != operatorv1.ConditionFalse {
+		t.Errorf("expected no stall without a rollout, got %v", condition)
+	}
+}
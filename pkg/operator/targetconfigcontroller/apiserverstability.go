@@ -0,0 +1,165 @@
+package targetconfigcontroller
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
+	corev1client "k8s.io/client-go/kubernetes/typed/core/v1"
+
+	operatorv1 "github.com/openshift/api/operator/v1"
+	"github.com/openshift/library-go/pkg/controller/factory"
+	"github.com/openshift/library-go/pkg/operator/events"
+	"github.com/openshift/library-go/pkg/operator/v1helpers"
+)
+
+const (
+	// waitingForStableAPIServerCondition is reported while a change rolling out a new revision is held back because
+	// the kube-apiserver is rolling out itself.
+	waitingForStableAPIServerCondition = "WaitingForStableAPIServer"
+
+	// apiServerStabilityRecheckInterval is how often a held back change checks the kube-apiserver again.
+	apiServerStabilityRecheckInterval = 30 * time.Second
+
+	apiServerNamespace   = "openshift-kube-apiserver"
+	apiServerPodSelector = "app=openshift-kube-apiserver"
+)
+
+// apiServerInstability returns why the kube-apiserver is not stable from the point of view of the operator, empty when
+// it is. It is unstable while its static pods run different revisions or are not ready, i.e. while it rolls out, and
+// while the kubernetes service lacks the endpoint of one of them. Restarting the kube-controller-manager then makes
+// it elect a leader and fill its caches against a half rolled out kube-apiserver. Without pods, e.g. while the
+// bootstrap kube-apiserver serves, there is no rollout to wait for.
+func apiServerInstability(ctx context.Context, client corev1client.CoreV1Interface) (string, error) {
+	pods, err := client.Pods(apiServerNamespace).List(ctx, metav1.ListOptions{LabelSelector: apiServerPodSelector})
+	if err != nil {
+		return "", err
+	}
+	revisions := sets.New[string]()
+	podIPs := sets.New[string]()
+	var notReady []string
+	for _, pod := range pods.Items {
+		revisions.Insert(pod.Labels["revision"])
+		podIPs.Insert(pod.Status.HostIP)
+		if !isPodReady(&pod) {
+			notReady = append(notReady, pod.Name)
+		}
+	}
+	if revisions.Len() > 1 {
+		return fmt.Sprintf("the kube-apiserver pods run revisions %s", strings.Join(sets.List(revisions), ", ")), nil
+	}
+	if len(notReady) > 0 {
+		sort.Strings(notReady)
+		return fmt.Sprintf("the kube-apiserver pods %s are not ready", strings.Join(notReady, ", ")), nil
+	}
+
+	endpoints, err := client.Endpoints("default").Get(ctx, "kubernetes", metav1.GetOptions{})
+	if err != nil {
+		return "", err
+	}
+	endpointIPs := sets.New[string]()
+	for _, subset := range endpoints.Subsets {
+		for _, address := range subset.Addresses {
+			endpointIPs.Insert(address.IP)
+		}
+	}
+	if missing := podIPs.Difference(endpointIPs); missing.Len() > 0 {
+		return fmt.Sprintf("the kubernetes service has no endpoints for the kube-apiserver on %s", strings.Join(sets.List(missing), ", ")), nil
+	}
+	return "", nil
+}
+
+func isPodReady(pod *corev1.Pod) bool {
+	for _, condition := range pod.Status.Conditions {
+		if condition.Type == corev1.PodReady {
+			return condition.Status == corev1.ConditionTrue
+		}
+	}
+	return false
+}
+
+// quietSyncContext drops the events of a sync probing for changes, the probe writes nothing.
+type quietSyncContext struct {
+	factory.SyncContext
+}
+
+func (c quietSyncContext) Recorder() events.Recorder {
+	return events.NewInMemoryRecorder("probe")
+}
+
+// holdBackForUnstableAPIServer returns whether a sync of one of the restartRequiredInputs is held back because it would
+// roll out a new revision while the kube-apiserver is not stable. The pending changes are found by a dry-run of the
+// syncer, which is only done while the kube-apiserver is unstable. The first revision is never held back, the
+// kube-apiserver may wait for the kube-controller-manager during the install.
+func (c *TargetConfigController) holdBackForUnstableAPIServer(ctx context.Context, syncCtx factory.SyncContext, syncer targetConfigSyncer, operatorSpec *operatorv1.StaticPodOperatorSpec, latestAvailableRevision int32) (bool, error) {
+	if !restartRequired(syncer) || latestAvailableRevision == 0 {
+		return false, nil
+	}
+	instability, err := apiServerInstability(ctx, c.kubeClient.CoreV1())
+	if err != nil {
+		return false, err
+	}
+	var changes []string
+	if len(instability) > 0 {
+		client := newDryRunClient(c.kubeClient.CoreV1())
+		if err := syncer.sync(ctx, quietSyncContext{SyncContext: syncCtx}, client, operatorSpec); err != nil {
+			// the real sync reports the error
+			return false, nil
+		}
+		changes = client.Changes()
+	}
+
+	c.apiServerWaitsLock.Lock()
+	if len(changes) > 0 {
+		c.apiServerWaits[syncer.name] = instability
+	} else {
+		delete(c.apiServerWaits, syncer.name)
+	}
+	condition := waitingForStableAPIServerConditionFor(c.syncers, c.apiServerWaits)
+	c.apiServerWaitsLock.Unlock()
+
+	if _, _, err := v1helpers.UpdateStaticPodStatus(ctx, c.operatorClient, v1helpers.UpdateStaticPodConditionFn(condition)); err != nil {
+		return false, err
+	}
+	if len(changes) == 0 {
+		return false, nil
+	}
+	c.scheduleSync(syncCtx, apiServerStabilityRecheckInterval, "the change is held back until the kube-apiserver is stable")
+	return true, nil
+}
+
+func restartRequired(syncer targetConfigSyncer) bool {
+	for _, name := range restartRequiredInputs {
+		if syncer.resource == "configmap/"+name {
+			return true
+		}
+	}
+	return false
+}
+
+// waitingForStableAPIServerConditionFor lists the held back resources in the order of the syncers.
+func waitingForStableAPIServerConditionFor(syncers []targetConfigSyncer, waits map[string]string) operatorv1.OperatorCondition {
+	var lines []string
+	for _, syncer := range syncers {
+		if instability, ok := waits[syncer.name]; ok {
+			lines = append(lines, fmt.Sprintf("%s: %s", syncer.resource, instability))
+		}
+	}
+	if len(lines) == 0 {
+		return operatorv1.OperatorCondition{
+			Type:   waitingForStableAPIServerCondition,
+			Status: operatorv1.ConditionFalse,
+		}
+	}
+	return operatorv1.OperatorCondition{
+		Type:    waitingForStableAPIServerCondition,
+		Status:  operatorv1.ConditionTrue,
+		Reason:  "APIServerRollingOut",
+		Message: fmt.Sprintf("The changes rolling out a new revision are held back until the kube-apiserver is stable:\n%s", strings.Join(lines, "\n")),
+	}
+}
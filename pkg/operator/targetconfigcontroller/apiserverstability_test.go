@@ -0,0 +1,107 @@
+package targetconfigcontroller
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+
+	operatorv1 "github.com/openshift/api/operator/v1"
+)
+
+func TestAPIServerInstability(t *testing.T) {
+	pod := func(name, revision, hostIP string, ready bool) *corev1.Pod {
+		status := corev1.ConditionFalse
+		if ready {
+			status = corev1.ConditionTrue
+		}
+		return &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Namespace: apiServerNamespace, Name: name, Labels: map[string]string{"app": "openshift-kube-apiserver", "revision": revision}},
+			Status: corev1.PodStatus{
+				HostIP:     hostIP,
+				Conditions: []corev1.PodCondition{{Type: corev1.PodReady, Status: status}},
+			},
+		}
+	}
+	endpoints := func(ips ...string) *corev1.Endpoints {
+		subset := corev1.EndpointSubset{}
+		for _, ip := range ips {
+			subset.Addresses = append(subset.Addresses, corev1.EndpointAddress{IP: ip})
+		}
+		return &corev1.Endpoints{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "kubernetes"},
+			Subsets:    []corev1.EndpointSubset{subset},
+		}
+	}
+	tests := []struct {
+		name     string
+		objects  []runtime.Object
+		expected string
+	}{
+		{
+			name:    "stable",
+			objects: []runtime.Object{pod("a", "3", "10.0.0.1", true), pod("b", "3", "10.0.0.2", true), endpoints("10.0.0.1", "10.0.0.2")},
+		},
+		{
+			name:    "no pods",
+			objects: []runtime.Object{endpoints("10.0.0.9")},
+		},
+		{
+			name:     "rolling out",
+			objects:  []runtime.Object{pod("a", "4", "10.0.0.1", true), pod("b", "3", "10.0.0.2", true), endpoints("10.0.0.1", "10.0.0.2")},
+			expected: "the kube-apiserver pods run revisions 3, 4",
+		},
+		{
+			name:     "not ready",
+			objects:  []runtime.Object{pod("a", "3", "10.0.0.1", false), pod("b", "3", "10.0.0.2", true), endpoints("10.0.0.2")},
+			expected: "the kube-apiserver pods a are not ready",
+		},
+		{
+			name:     "missing endpoint",
+			objects:  []runtime.Object{pod("a", "3", "10.0.0.1", true), pod("b", "3", "10.0.0.2", true), endpoints("10.0.0.1")},
+			expected: "the kubernetes service has no endpoints for the kube-apiserver on 10.0.0.2",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			actual, err := apiServerInstability(context.TODO(), fake.NewSimpleClientset(test.objects...).CoreV1())
+			if err != nil {
+				t.Fatal(err)
+			}
+			if actual != test.expected {
+				t.Errorf("expected %q, got %q", test.expected, actual)
+			}
+		})
+	}
+}
+
+func TestWaitingForStableAPIServerConditionFor(t *testing.T) {
+	syncers := []targetConfigSyncer{
+		{name: "config", resource: "configmap/config"},
+		{name: "pod", resource: "configmap/kube-controller-manager-pod"},
+	}
+
+	if condition := waitingForStableAPIServerConditionFor(syncers, map[string]string{}); condition.Status != operatorv1.ConditionFalse {
+		t.Errorf("expected False without held back changes, got %v", condition)
+	}
+
+	condition := waitingForStableAPIServerConditionFor(syncers, map[string]string{
+		"pod":    "the kube-apiserver pods run revisions 3, 4",
+		"config": "the kube-apiserver pods a are not ready",
+	})
+	if condition.Status != operatorv1.ConditionTrue {
+		t.Fatalf("expected True, got %v", condition)
+	}
+	lines := strings.Split(condition.Message, "\n")[1:]
+	expected := []string{
+		"configmap/config: the kube-apiserver pods a are not ready",
+		"configmap/kube-controller-manager-pod: the kube-apiserver pods run revisions 3, 4",
+	}
+	if strings.Join(lines, "\n") != strings.Join(expected, "\n") {
+		t.Errorf("expected the lines %q, got %q", expected, lines)
+	}
+}
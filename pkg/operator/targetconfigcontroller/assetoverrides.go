@@ -0,0 +1,42 @@
+package targetconfigcontroller
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	operatorv1 "github.com/openshift/api/operator/v1"
+	"github.com/openshift/library-go/pkg/operator/v1helpers"
+
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/assetoverride"
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/operatorclient"
+)
+
+// assetOverridesUpgradeableCondition blocks upgrades while assets of the operand are overridden. An override is an
+// emergency fix for the current release, it would replace the assets of the next release as well.
+const assetOverridesUpgradeableCondition = "AssetOverridesUpgradeable"
+
+// updateAssetOverridesCondition reports the overridden assets.
+func (c *TargetConfigController) updateAssetOverridesCondition(ctx context.Context) error {
+	overridden, err := c.assets.Overridden()
+	if err != nil {
+		return err
+	}
+	_, _, err = v1helpers.UpdateStaticPodStatus(ctx, c.operatorClient, v1helpers.UpdateStaticPodConditionFn(assetOverridesConditionFor(overridden)))
+	return err
+}
+
+func assetOverridesConditionFor(overridden []string) operatorv1.OperatorCondition {
+	if len(overridden) == 0 {
+		return operatorv1.OperatorCondition{
+			Type:   assetOverridesUpgradeableCondition,
+			Status: operatorv1.ConditionTrue,
+		}
+	}
+	return operatorv1.OperatorCondition{
+		Type:    assetOverridesUpgradeableCondition,
+		Status:  operatorv1.ConditionFalse,
+		Reason:  "AssetsOverridden",
+		Message: fmt.Sprintf("configmap/%s in %q overrides %s, remove the overrides before upgrading", assetoverride.ConfigMapName, operatorclient.OperatorNamespace, strings.Join(overridden, ", ")),
+	}
+}
@@ -0,0 +1,34 @@
+package targetconfigcontroller
+
+import (
+	"context"
+
+	operatorv1 "github.com/openshift/api/operator/v1"
+	"github.com/openshift/library-go/pkg/operator/v1helpers"
+)
+
+// certOnlyRolloutDeferredCondition was reported while a change of the serviceaccount-ca bundle was held back until the
+// next revision. The kube-controller-manager reads that bundle on startup only, holding it back left the operand
+// trusting a rotated CA late, so its changes are written right away and the condition is removed. The CA bundles the
+// operand reloads live are unrevisioned certs synced by the cert-syncer, their changes never roll out a revision.
+const certOnlyRolloutDeferredCondition = "CertOnlyRolloutDeferred"
+
+// restartRequiredInputs are the revisioned configmaps holding the flags and the pod spec of the operand. A change of
+// any of them rolls out a new revision.
+var restartRequiredInputs = []string{"kube-controller-manager-pod", "config"}
+
+// removeCertOnlyRolloutDeferredCondition removes the condition an operator of a previous version may have left.
+func (c *TargetConfigController) removeCertOnlyRolloutDeferredCondition(ctx context.Context) error {
+	_, status, _, err := c.operatorClient.GetStaticPodOperatorState()
+	if err != nil {
+		return err
+	}
+	if v1helpers.FindOperatorCondition(status.Conditions, certOnlyRolloutDeferredCondition) == nil {
+		return nil
+	}
+	_, _, err = v1helpers.UpdateStaticPodStatus(ctx, c.operatorClient, func(oldStatus *operatorv1.StaticPodOperatorStatus) error {
+		v1helpers.RemoveOperatorCondition(&oldStatus.Conditions, certOnlyRolloutDeferredCondition)
+		return nil
+	})
+	return err
+}
@@ -0,0 +1,36 @@
+package targetconfigcontroller
+
+import (
+	"context"
+	"testing"
+
+	operatorv1 "github.com/openshift/api/operator/v1"
+	"github.com/openshift/library-go/pkg/operator/v1helpers"
+)
+
+func TestRemoveCertOnlyRolloutDeferredCondition(t *testing.T) {
+	operatorClient := v1helpers.NewFakeStaticPodOperatorClient(
+		&operatorv1.StaticPodOperatorSpec{},
+		&operatorv1.StaticPodOperatorStatus{OperatorStatus: operatorv1.OperatorStatus{Conditions: []operatorv1.OperatorCondition{
+			{Type: certOnlyRolloutDeferredCondition, Status: operatorv1.ConditionTrue, Reason: "CABundleChanged"},
+			{Type: "TargetConfigControllerDegraded", Status: operatorv1.ConditionFalse},
+		}}},
+		nil, nil,
+	)
+	c := &TargetConfigController{operatorClient: operatorClient}
+	for i := 0; i < 2; i++ {
+		if err := c.removeCertOnlyRolloutDeferredCondition(context.TODO()); err != nil {
+			t.Fatal(err)
+		}
+	}
+	_, status, _, err := operatorClient.GetStaticPodOperatorState()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if v1helpers.FindOperatorCondition(status.Conditions, certOnlyRolloutDeferredCondition) != nil {
+		t.Errorf("expected the condition to be removed, got %v", status.Conditions)
+	}
+	if v1helpers.FindOperatorCondition(status.Conditions, "TargetConfigControllerDegraded") == nil {
+		t.Errorf("expected the other conditions to be kept, got %v", status.Conditions)
+	}
+}
@@ -0,0 +1,95 @@
+package targetconfigcontroller
+
+import (
+	"context"
+	"time"
+
+	"k8s.io/apimachinery/pkg/util/sets"
+
+	"github.com/openshift/library-go/pkg/controller/factory"
+	"github.com/openshift/library-go/pkg/operator/v1helpers"
+
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/certrecovery"
+)
+
+// certRecoverySyncers regenerate the csr-signer and the service account trust. In the cert recovery mode they are
+// synced before the other syncers, which wait until each of them synced once, and they are neither skipped as no-op
+// syncs nor held back for an unstable kube-apiserver: the kube-apiserver is unstable because of the expired certs.
+var certRecoverySyncers = sets.NewString("csr-signer", "serviceaccount-ca")
+
+// isCertRecoverySync returns whether the syncer is synced first in the cert recovery mode, and the mode is on.
+func (c *TargetConfigController) isCertRecoverySync(name string, now time.Time) bool {
+	if !certRecoverySyncers.Has(name) {
+		return false
+	}
+	active, _ := c.certRecovery.Active(now)
+	return active
+}
+
+// queueSyncers queues the syncers for a fanned out sync, only the certRecoverySyncers that did not sync yet while the
+// cert recovery mode is on, and reports the mode.
+func (c *TargetConfigController) queueSyncers(ctx context.Context, syncCtx factory.SyncContext, now time.Time) error {
+	active, reason := c.certRecovery.Active(now)
+
+	c.certRecoveryLock.Lock()
+	entered := active && !c.certRecoveryActive
+	if active != c.certRecoveryActive {
+		c.certRecoverySynced = sets.NewString()
+	}
+	c.certRecoveryActive = active
+	pending := certRecoverySyncers.Difference(c.certRecoverySynced)
+	c.certRecoveryLock.Unlock()
+
+	if entered {
+		syncCtx.Recorder().Warningf("CertRecoveryModeEntered", "The csr-signer and the service account trust are regenerated before the other resources: %s", reason)
+	}
+	for _, syncer := range c.syncers {
+		if active && pending.Len() > 0 && !pending.Has(syncer.name) {
+			continue
+		}
+		syncCtx.Queue().Add(syncer.name)
+	}
+	_, _, err := v1helpers.UpdateStaticPodStatus(ctx, c.operatorClient, v1helpers.UpdateStaticPodConditionFn(c.certRecovery.Condition(now)))
+	return err
+}
+
+// waitsForCertRecovery returns whether the syncer waits for the certRecoverySyncers, which did not all sync yet in
+// the cert recovery mode.
+func (c *TargetConfigController) waitsForCertRecovery(name string) bool {
+	if certRecoverySyncers.Has(name) {
+		return false
+	}
+	c.certRecoveryLock.Lock()
+	defer c.certRecoveryLock.Unlock()
+	return c.certRecoveryActive && !c.certRecoverySynced.IsSuperset(certRecoverySyncers)
+}
+
+// recordCertRecoverySync records a successful sync of a certRecoverySyncer and queues the waiting syncers once all of
+// them synced.
+func (c *TargetConfigController) recordCertRecoverySync(syncCtx factory.SyncContext, name string) {
+	c.certRecoveryLock.Lock()
+	if !c.certRecoveryActive || c.certRecoverySynced.Has(name) {
+		c.certRecoveryLock.Unlock()
+		return
+	}
+	c.certRecoverySynced.Insert(name)
+	done := c.certRecoverySynced.IsSuperset(certRecoverySyncers)
+	c.certRecoveryLock.Unlock()
+
+	if !done {
+		return
+	}
+	for _, syncer := range c.syncers {
+		if !certRecoverySyncers.Has(syncer.name) {
+			syncCtx.Queue().Add(syncer.name)
+		}
+	}
+}
+
+// certRecoveryCSRSignerRequeueDelays shortens the trust recheck of a rotated csr-signer in the cert recovery mode.
+func certRecoveryCSRSignerRequeueDelays(delays CSRSignerRequeueDelays) CSRSignerRequeueDelays {
+	if delays.TrustRecheckInterval > certrecovery.TrustRecheckInterval {
+		delays.TrustRecheckInterval = certrecovery.TrustRecheckInterval
+	}
+	return delays
+}
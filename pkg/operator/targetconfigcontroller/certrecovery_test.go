@@ -0,0 +1,94 @@
+package targetconfigcontroller
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/client-go/kubernetes/fake"
+
+	operatorv1 "github.com/openshift/api/operator/v1"
+	"github.com/openshift/library-go/pkg/controller/factory"
+	"github.com/openshift/library-go/pkg/operator/events"
+	"github.com/openshift/library-go/pkg/operator/v1helpers"
+
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/certrecovery"
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/operatorclient"
+)
+
+func TestCertRecoverySyncOrder(t *testing.T) {
+	operatorClient := v1helpers.NewFakeStaticPodOperatorClient(
+		&operatorv1.StaticPodOperatorSpec{},
+		&operatorv1.StaticPodOperatorStatus{},
+		nil,
+		nil,
+	)
+	kubeInformersForNamespaces := v1helpers.NewKubeInformersForNamespaces(fake.NewSimpleClientset(), operatorclient.TargetNamespace, operatorclient.OperatorNamespace)
+	c := &TargetConfigController{
+		operatorClient: operatorClient,
+		certRecovery:   certrecovery.NewDetector(true, kubeInformersForNamespaces),
+	}
+	c.syncers = c.newSyncers()
+
+	syncCtx := factory.NewSyncContext("test", events.NewInMemoryRecorder("target-config-controller"))
+	queued := func() sets.String {
+		keys := sets.NewString()
+		for syncCtx.Queue().Len() > 0 {
+			key, _ := syncCtx.Queue().Get()
+			keys.Insert(key.(string))
+			syncCtx.Queue().Done(key)
+		}
+		return keys
+	}
+
+	if err := c.queueSyncers(context.TODO(), syncCtx, time.Now()); err != nil {
+		t.Fatal(err)
+	}
+	if keys := queued(); !keys.Equal(certRecoverySyncers) {
+		t.Errorf("expected only the cert recovery syncers to be queued, got %v", keys.List())
+	}
+	if !c.waitsForCertRecovery("pod") || c.waitsForCertRecovery("csr-signer") {
+		t.Errorf("expected the pod to wait for the csr-signer")
+	}
+	if !c.isCertRecoverySync("csr-signer", time.Now()) || c.isCertRecoverySync("pod", time.Now()) {
+		t.Errorf("expected only the csr-signer to be a cert recovery sync")
+	}
+
+	c.recordCertRecoverySync(syncCtx, "csr-signer")
+	if keys := queued(); keys.Len() != 0 {
+		t.Errorf("expected nothing to be queued before the service account trust synced, got %v", keys.List())
+	}
+	c.recordCertRecoverySync(syncCtx, "serviceaccount-ca")
+	if keys := queued(); keys.Len() != len(c.syncers)-certRecoverySyncers.Len() || keys.HasAny(certRecoverySyncers.List()...) {
+		t.Errorf("expected the other syncers to be queued, got %v", keys.List())
+	}
+	if c.waitsForCertRecovery("pod") {
+		t.Errorf("expected the pod not to wait anymore")
+	}
+
+	if err := c.queueSyncers(context.TODO(), syncCtx, time.Now()); err != nil {
+		t.Fatal(err)
+	}
+	if keys := queued(); keys.Len() != len(c.syncers) {
+		t.Errorf("expected all the syncers to be queued, got %v", keys.List())
+	}
+	_, status, _, err := operatorClient.GetStaticPodOperatorState()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if condition := v1helpers.FindOperatorCondition(status.Conditions, certrecovery.ConditionType); condition == nil || condition.Status != operatorv1.ConditionTrue {
+		t.Errorf("expected %s to be true, got %#v", certrecovery.ConditionType, condition)
+	}
+}
+
+func TestCertRecoveryCSRSignerRequeueDelays(t *testing.T) {
+	delays := certRecoveryCSRSignerRequeueDelays(DefaultCSRSignerRequeueDelays)
+	if delays.TrustRecheckInterval != certrecovery.TrustRecheckInterval || delays.Padding != DefaultCSRSignerRequeueDelays.Padding {
+		t.Errorf("unexpected delays %#v", delays)
+	}
+	tuned := CSRSignerRequeueDelays{TrustRecheckInterval: time.Second}
+	if delays := certRecoveryCSRSignerRequeueDelays(tuned); delays != tuned {
+		t.Errorf("expected shorter tuned delays to be kept, got %#v", delays)
+	}
+}
@@ -0,0 +1,71 @@
+package targetconfigcontroller
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/client-go/util/cert"
+
+	operatorv1 "github.com/openshift/api/operator/v1"
+
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/operatorclient"
+)
+
+const (
+	clockSkewDetectedCondition = "ClockSkewDetected"
+
+	// ClockSkewTolerance is how far in the future the start of the validity of a managed certificate may be before
+	// it is put down to skewed clocks. The certificates are issued with a start a second in the past, a start in the
+	// future means the clock of the control plane node that issued them runs ahead of the one of the operator.
+	ClockSkewTolerance = 5 * time.Minute
+)
+
+// clockSkewSecrets are the secrets with the certificates the csr-signer rotation times its steps by.
+var clockSkewSecrets = []struct{ namespace, name string }{
+	{operatorclient.OperatorNamespace, "csr-signer-signer"},
+	{operatorclient.OperatorNamespace, "csr-signer"},
+	{operatorclient.TargetNamespace, "csr-signer"},
+}
+
+// clockSkewCondition reports the managed certificates that start beyond the ClockSkewTolerance. A rotated
+// csr-signer is held back until its start, which otherwise looks like a rotation that is stuck for no reason.
+func (c *TargetConfigController) clockSkewCondition(now time.Time) operatorv1.OperatorCondition {
+	var skewed []string
+	for _, location := range clockSkewSecrets {
+		secret, err := c.secretLister.Secrets(location.namespace).Get(location.name)
+		if apierrors.IsNotFound(err) {
+			continue
+		}
+		if err != nil {
+			return operatorv1.OperatorCondition{Type: clockSkewDetectedCondition, Status: operatorv1.ConditionUnknown, Reason: "MissingInput", Message: err.Error()}
+		}
+		certs, err := cert.ParseCertsPEM(secret.Data["tls.crt"])
+		if err != nil {
+			continue
+		}
+		if skew := certs[0].NotBefore.Sub(now); skew > ClockSkewTolerance {
+			skewed = append(skewed, fmt.Sprintf("secret/%s -n %s is valid from %s, %s after the operator clock", location.name, location.namespace, certs[0].NotBefore.UTC().Format(time.RFC3339), skew.Round(time.Second)))
+		}
+	}
+
+	if len(skewed) == 0 {
+		return operatorv1.OperatorCondition{Type: clockSkewDetectedCondition, Status: operatorv1.ConditionFalse, Reason: "AsExpected"}
+	}
+	return operatorv1.OperatorCondition{
+		Type:    clockSkewDetectedCondition,
+		Status:  operatorv1.ConditionTrue,
+		Reason:  "CertificatesNotYetValid",
+		Message: fmt.Sprintf("The clocks of the control plane nodes appear skewed, the csr-signer rotation waits for the certificates to become valid: %s", strings.Join(skewed, "; ")),
+	}
+}
+
+// skewTolerantTime is the time to check the validity of a certificate at: now, or the start of its validity when it
+// is less than the ClockSkewTolerance in the future.
+func skewTolerantTime(notBefore, now time.Time) time.Time {
+	if notBefore.After(now) && notBefore.Sub(now) <= ClockSkewTolerance {
+		return notBefore
+	}
+	return now
+}
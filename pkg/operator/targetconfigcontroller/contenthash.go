@@ -0,0 +1,183 @@
+package targetconfigcontroller
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"sync"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
+	applycorev1 "k8s.io/client-go/applyconfigurations/core/v1"
+	corev1client "k8s.io/client-go/kubernetes/typed/core/v1"
+
+	"github.com/openshift/library-go/pkg/operator/events"
+)
+
+// ContentHashAnnotation holds the hash of the data the operator last wrote to a configmap rendered from its assets.
+// A configmap whose data no longer matches the hash was modified by someone else, typically a GitOps tool that
+// manages the target namespace and fights the operator over its resources.
+const ContentHashAnnotation = "kubecontrollermanager.operator.openshift.io/content-hash"
+
+// contentHashClient stamps the ContentHashAnnotation on the configmaps it writes and emits a ManagedResourceMutated
+// event when it reads one whose data does not match its annotation. The operator does not revert the change, the
+// server-side apply that follows the read fails with a conflict until it is reverted, see FieldManager. A configmap
+// written before the hash was tracked is adopted with its current data.
+// Only the configmap the syncer renders is tracked, the others it reads or writes pass through.
+type contentHashClient struct {
+	corev1client.CoreV1Interface
+	recorder events.Recorder
+	name     string
+	reports  *mutationReports
+}
+
+// mutationReports remembers the mutations already reported across the syncs, the clients are created per sync.
+type mutationReports struct {
+	lock sync.Mutex
+	// hashes are the hashes of the mutated configmaps by name
+	hashes map[string]string
+}
+
+func newMutationReports() *mutationReports {
+	return &mutationReports{hashes: map[string]string{}}
+}
+
+// report returns whether the mutation of the configmap to the hash was not reported yet, and forgets the reported
+// mutation once the configmap matches its annotation again.
+func (r *mutationReports) report(name, hash string, mutated bool) bool {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	if !mutated {
+		delete(r.hashes, name)
+		return false
+	}
+	if r.hashes[name] == hash {
+		return false
+	}
+	r.hashes[name] = hash
+	return true
+}
+
+func newContentHashClient(client corev1client.CoreV1Interface, recorder events.Recorder, name string, reports *mutationReports) *contentHashClient {
+	return &contentHashClient{CoreV1Interface: client, recorder: recorder, name: name, reports: reports}
+}
+
+func (c *contentHashClient) ConfigMaps(namespace string) corev1client.ConfigMapInterface {
+	return &contentHashConfigMaps{ConfigMapInterface: c.CoreV1Interface.ConfigMaps(namespace), recorder: c.recorder, name: c.name, reports: c.reports}
+}
+
+type contentHashConfigMaps struct {
+	corev1client.ConfigMapInterface
+	recorder events.Recorder
+	name     string
+	reports  *mutationReports
+}
+
+func (c *contentHashConfigMaps) Get(ctx context.Context, name string, opts metav1.GetOptions) (*corev1.ConfigMap, error) {
+	configMap, err := c.ConfigMapInterface.Get(ctx, name, opts)
+	if err != nil || name != c.name {
+		return configMap, err
+	}
+	hash, ok := configMap.Annotations[ContentHashAnnotation]
+	if !ok {
+		return c.ConfigMapInterface.Update(ctx, withContentHash(configMap), metav1.UpdateOptions{})
+	}
+	ownedHash := ownedContentHash(configMap)
+	if c.reports.report(configMap.Name, ownedHash, hash != ownedHash) {
+		c.recorder.Warningf("ManagedResourceMutated", "ConfigMap/%s -n %s was modified outside of the operator, it is not updated until the change is reverted", configMap.Name, configMap.Namespace)
+	}
+	return configMap, nil
+}
+
+func (c *contentHashConfigMaps) Create(ctx context.Context, configMap *corev1.ConfigMap, opts metav1.CreateOptions) (*corev1.ConfigMap, error) {
+	if configMap.Name != c.name {
+		return c.ConfigMapInterface.Create(ctx, configMap, opts)
+	}
+	return c.ConfigMapInterface.Create(ctx, withContentHash(configMap), opts)
+}
+
+func (c *contentHashConfigMaps) Update(ctx context.Context, configMap *corev1.ConfigMap, opts metav1.UpdateOptions) (*corev1.ConfigMap, error) {
+	if configMap.Name != c.name {
+		return c.ConfigMapInterface.Update(ctx, configMap, opts)
+	}
+	return c.ConfigMapInterface.Update(ctx, withContentHash(configMap), opts)
+}
+
+func (c *contentHashConfigMaps) Apply(ctx context.Context, configMap *applycorev1.ConfigMapApplyConfiguration, opts metav1.ApplyOptions) (*corev1.ConfigMap, error) {
+	if configMap.Name == nil || *configMap.Name != c.name {
+		return c.ConfigMapInterface.Apply(ctx, configMap, opts)
+	}
+	hash := contentHash(&corev1.ConfigMap{Data: configMap.Data, BinaryData: configMap.BinaryData})
+	return c.ConfigMapInterface.Apply(ctx, configMap.WithAnnotations(map[string]string{ContentHashAnnotation: hash}), opts)
+}
+
+func withContentHash(configMap *corev1.ConfigMap) *corev1.ConfigMap {
+	configMap = configMap.DeepCopy()
+	if configMap.Annotations == nil {
+		configMap.Annotations = map[string]string{}
+	}
+	configMap.Annotations[ContentHashAnnotation] = contentHash(configMap)
+	return configMap
+}
+
+// IsModifiedOutsideOperator returns whether the data the operator owns in a configmap tracked with the
+// ContentHashAnnotation no longer matches its hash, or another manager added keys to it. Configmaps without the
+// annotation are not tracked and never reported.
+func IsModifiedOutsideOperator(configMap *corev1.ConfigMap) bool {
+	hash, ok := configMap.Annotations[ContentHashAnnotation]
+	if !ok {
+		return false
+	}
+	if hash != ownedContentHash(configMap) {
+		return true
+	}
+	if !appliedByFieldManager(configMap.ManagedFields) {
+		return false
+	}
+	keys := sets.KeySet(configMap.Data).Union(sets.KeySet(configMap.BinaryData))
+	return keys.Difference(appliedDataKeys(configMap.ManagedFields)).Len() > 0
+}
+
+// ownedContentHash hashes the data the operator applied to the configmap. The keys of other managers are reported by
+// the apply, see foreignDataKeys, and a key another manager changed is no longer owned by the operator. A configmap the
+// operator did not apply yet is hashed whole.
+func ownedContentHash(configMap *corev1.ConfigMap) string {
+	if !appliedByFieldManager(configMap.ManagedFields) {
+		return contentHash(configMap)
+	}
+	keys := appliedDataKeys(configMap.ManagedFields)
+	owned := &corev1.ConfigMap{}
+	for key, value := range configMap.Data {
+		if keys.Has(key) {
+			if owned.Data == nil {
+				owned.Data = map[string]string{}
+			}
+			owned.Data[key] = value
+		}
+	}
+	for key, value := range configMap.BinaryData {
+		if keys.Has(key) {
+			if owned.BinaryData == nil {
+				owned.BinaryData = map[string][]byte{}
+			}
+			owned.BinaryData[key] = value
+		}
+	}
+	return contentHash(owned)
+}
+
+// contentHash hashes the data of the configmap. The JSON encoding sorts the keys, so the hash is stable.
+func contentHash(configMap *corev1.ConfigMap) string {
+	content, err := json.Marshal(struct {
+		Data       map[string]string `json:"data,omitempty"`
+		BinaryData map[string][]byte `json:"binaryData,omitempty"`
+	}{configMap.Data, configMap.BinaryData})
+	if err != nil {
+		// maps of strings and bytes always encode
+		panic(err)
+	}
+	hash := sha256.Sum256(content)
+	return hex.EncodeToString(hash[:])
+}
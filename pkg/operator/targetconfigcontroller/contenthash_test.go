@@ -0,0 +1,103 @@
+package targetconfigcontroller
+
+import (
+	"context"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+
+	"github.com/openshift/library-go/pkg/operator/events"
+
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/operatorclient"
+)
+
+func TestContentHashClient(t *testing.T) {
+	kubeClient := fake.NewSimpleClientset(&corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Namespace: operatorclient.TargetNamespace, Name: "recycler-config"},
+		Data:       map[string]string{"recycler-pod.yaml": "pod"},
+	})
+	recorder := events.NewInMemoryRecorder("content-hash")
+	reports := newMutationReports()
+	// every sync reads the configmap through a client of its own
+	sync := func() *corev1.ConfigMap {
+		t.Helper()
+		configMap, err := newContentHashClient(kubeClient.CoreV1(), recorder, "recycler-config", reports).ConfigMaps(operatorclient.TargetNamespace).Get(context.TODO(), "recycler-config", metav1.GetOptions{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return configMap
+	}
+	update := func(configMap *corev1.ConfigMap) {
+		t.Helper()
+		if _, err := kubeClient.CoreV1().ConfigMaps(operatorclient.TargetNamespace).Update(context.TODO(), configMap, metav1.UpdateOptions{}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	mutations := func() int {
+		count := 0
+		for _, event := range recorder.Events() {
+			if event.Reason == "ManagedResourceMutated" {
+				count++
+			}
+		}
+		return count
+	}
+
+	// an existing configmap is adopted
+	existing := sync()
+	if existing.Annotations[ContentHashAnnotation] != contentHash(existing) {
+		t.Fatalf("expected the configmap to be adopted, got annotations %v", existing.Annotations)
+	}
+	if mutations() != 0 {
+		t.Errorf("expected no mutation on adoption")
+	}
+
+	// a third party changes the data, which is reported once however many syncs see it
+	existing.Data["recycler-pod.yaml"] = "changed"
+	update(existing)
+	for i := 0; i < 3; i++ {
+		sync()
+	}
+	if mutations() != 1 {
+		t.Errorf("expected a mutation event, got %v", recorder.Events())
+	}
+
+	// the change is reverted and made again
+	existing.Data["recycler-pod.yaml"] = "pod"
+	update(existing)
+	sync()
+	existing.Data["recycler-pod.yaml"] = "changed again"
+	update(existing)
+	sync()
+	if mutations() != 2 {
+		t.Errorf("expected a mutation event for the new change, got %v", recorder.Events())
+	}
+}
+
+func TestOwnedContentHash(t *testing.T) {
+	configMap := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			ManagedFields: []metav1.ManagedFieldsEntry{
+				{Manager: FieldManager, Operation: metav1.ManagedFieldsOperationApply, FieldsV1: &metav1.FieldsV1{Raw: []byte(`{"f:data":{".":{},"f:config.yaml":{}}}`)}},
+				{Manager: "kubectl-edit", Operation: metav1.ManagedFieldsOperationUpdate, FieldsV1: &metav1.FieldsV1{Raw: []byte(`{"f:data":{"f:extra.yaml":{}}}`)}},
+			},
+		},
+		Data: map[string]string{"config.yaml": "config", "extra.yaml": "extra"},
+	}
+	applied := contentHash(&corev1.ConfigMap{Data: map[string]string{"config.yaml": "config"}})
+	if actual := ownedContentHash(configMap); actual != applied {
+		t.Errorf("expected the hash of the applied keys %s, got %s", applied, actual)
+	}
+
+	// the added key is not hashed but still reported
+	configMap.Annotations = map[string]string{ContentHashAnnotation: applied}
+	if !IsModifiedOutsideOperator(configMap) {
+		t.Errorf("expected the key added by another manager to be reported")
+	}
+	delete(configMap.Data, "extra.yaml")
+	if IsModifiedOutsideOperator(configMap) {
+		t.Errorf("expected the configmap to match its hash")
+	}
+}
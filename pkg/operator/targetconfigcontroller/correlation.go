@@ -0,0 +1,71 @@
+package targetconfigcontroller
+
+import (
+	"context"
+	"fmt"
+
+	utilrand "k8s.io/apimachinery/pkg/util/rand"
+	"k8s.io/klog/v2"
+
+	"github.com/openshift/library-go/pkg/controller/factory"
+	"github.com/openshift/library-go/pkg/operator/events"
+)
+
+// syncIDLength is the length of the random ID tagging the events and log lines of a single sync.
+const syncIDLength = 8
+
+// correlatedSyncContext tags the events of a sync with its ID, so that the outputs applied by one pass can be told
+// apart from the ones of the passes the other workers run at the same time.
+type correlatedSyncContext struct {
+	factory.SyncContext
+	syncID string
+}
+
+func (c correlatedSyncContext) Recorder() events.Recorder {
+	return correlatedRecorder{Recorder: c.SyncContext.Recorder(), syncID: c.syncID}
+}
+
+// withSyncID starts a sync with a new ID. The returned context carries a logger with the ID and the synced resource.
+func withSyncID(ctx context.Context, syncCtx factory.SyncContext, resource string) (context.Context, factory.SyncContext) {
+	syncID := utilrand.String(syncIDLength)
+	logger := klog.FromContext(ctx).WithValues("syncID", syncID, "resource", resource)
+	return klog.NewContext(ctx, logger), correlatedSyncContext{SyncContext: syncCtx, syncID: syncID}
+}
+
+// correlatedRecorder appends the sync ID to the event messages.
+type correlatedRecorder struct {
+	events.Recorder
+	syncID string
+}
+
+func (r correlatedRecorder) suffix(message string) string {
+	return fmt.Sprintf("%s [syncID=%s]", message, r.syncID)
+}
+
+func (r correlatedRecorder) Event(reason, message string) {
+	r.Recorder.Event(reason, r.suffix(message))
+}
+
+func (r correlatedRecorder) Eventf(reason, messageFmt string, args ...interface{}) {
+	r.Event(reason, fmt.Sprintf(messageFmt, args...))
+}
+
+func (r correlatedRecorder) Warning(reason, message string) {
+	r.Recorder.Warning(reason, r.suffix(message))
+}
+
+func (r correlatedRecorder) Warningf(reason, messageFmt string, args ...interface{}) {
+	r.Warning(reason, fmt.Sprintf(messageFmt, args...))
+}
+
+func (r correlatedRecorder) ForComponent(componentName string) events.Recorder {
+	return correlatedRecorder{Recorder: r.Recorder.ForComponent(componentName), syncID: r.syncID}
+}
+
+func (r correlatedRecorder) WithComponentSuffix(componentNameSuffix string) events.Recorder {
+	return correlatedRecorder{Recorder: r.Recorder.WithComponentSuffix(componentNameSuffix), syncID: r.syncID}
+}
+
+func (r correlatedRecorder) WithContext(ctx context.Context) events.Recorder {
+	return correlatedRecorder{Recorder: r.Recorder.WithContext(ctx), syncID: r.syncID}
+}
@@ -0,0 +1,40 @@
+package targetconfigcontroller
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/openshift/library-go/pkg/controller/factory"
+	"github.com/openshift/library-go/pkg/operator/events"
+)
+
+func TestWithSyncID(t *testing.T) {
+	recorder := events.NewInMemoryRecorder("target-config-controller")
+	syncCtx := factory.NewSyncContext("test", recorder)
+
+	_, first := withSyncID(context.Background(), syncCtx, "configmap/config")
+	_, second := withSyncID(context.Background(), syncCtx, "configmap/config")
+
+	first.Recorder().Eventf("ConfigMapUpdated", "Updated ConfigMap/%s", "config")
+	first.Recorder().WithComponentSuffix("dry-run").Warning("ConfigMapUpdated", "Updated ConfigMap/config")
+	dryRunSyncContext{SyncContext: second}.Recorder().Event("ConfigMapUpdated", "Updated ConfigMap/config")
+
+	recorded := recorder.Events()
+	if len(recorded) != 3 {
+		t.Fatalf("expected 3 events, got %v", recorded)
+	}
+	firstID := first.(correlatedSyncContext).syncID
+	secondID := second.(correlatedSyncContext).syncID
+	if len(firstID) != syncIDLength || firstID == secondID {
+		t.Fatalf("expected distinct sync IDs, got %q and %q", firstID, secondID)
+	}
+	for i, expectedID := range []string{firstID, firstID, secondID} {
+		if expected := "Updated ConfigMap/config [syncID=" + expectedID + "]"; recorded[i].Message != expected {
+			t.Errorf("event %d: expected message %q, got %q", i, expected, recorded[i].Message)
+		}
+	}
+	if !strings.HasSuffix(recorded[2].Source.Component, "dry-run") {
+		t.Errorf("expected the dry-run component suffix, got %q", recorded[2].Source.Component)
+	}
+}
@@ -0,0 +1,46 @@
+package targetconfigcontroller
+
+import (
+	gocrypto "crypto"
+	"crypto/x509"
+	"fmt"
+
+	"k8s.io/client-go/util/cert"
+	"k8s.io/client-go/util/keyutil"
+)
+
+// parseCSRSigner returns the certificate of the csr-signer key and the other certificates of the tls.crt. A signer
+// provided from outside may come with its intermediates in any order, so the signer is the certificate the private
+// key belongs to rather than the first one. A key that matches none of the certificates, or a matching certificate
+// that is not a CA, is refused: the CSR signing controller would sign with a key the published CA bundle does not
+// verify.
+func parseCSRSigner(certPEM, keyPEM []byte) (*x509.Certificate, []*x509.Certificate, error) {
+	certs, err := cert.ParseCertsPEM(certPEM)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to parse the csr-signer certificates: %v", err)
+	}
+	key, err := keyutil.ParsePrivateKeyPEM(keyPEM)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to parse the csr-signer key: %v", err)
+	}
+	signer, ok := key.(gocrypto.Signer)
+	if !ok {
+		return nil, nil, fmt.Errorf("unsupported csr-signer key type %T", key)
+	}
+	public, ok := signer.Public().(interface{ Equal(gocrypto.PublicKey) bool })
+	if !ok {
+		return nil, nil, fmt.Errorf("unsupported csr-signer key type %T", key)
+	}
+
+	for i, certificate := range certs {
+		if !public.Equal(certificate.PublicKey) {
+			continue
+		}
+		if !certificate.IsCA {
+			return nil, nil, fmt.Errorf("the csr-signer key belongs to %q, which is not a CA", certificate.Subject.CommonName)
+		}
+		intermediates := append(append([]*x509.Certificate{}, certs[:i]...), certs[i+1:]...)
+		return certificate, intermediates, nil
+	}
+	return nil, nil, fmt.Errorf("the csr-signer key does not belong to any of its %d certificates", len(certs))
+}
@@ -0,0 +1,74 @@
+package targetconfigcontroller
+
+import (
+	"testing"
+	"time"
+
+	"k8s.io/apimachinery/pkg/util/sets"
+
+	"github.com/openshift/library-go/pkg/crypto"
+)
+
+func TestParseCSRSigner(t *testing.T) {
+	rootConfig, err := crypto.MakeSelfSignedCAConfigForDuration("root", time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	root := &crypto.CA{Config: rootConfig, SerialGenerator: &crypto.RandomSerialGenerator{}}
+	signerConfig, err := crypto.MakeCAConfigForDuration("csr-signer", time.Hour, root)
+	if err != nil {
+		t.Fatal(err)
+	}
+	signer := &crypto.CA{Config: signerConfig, SerialGenerator: &crypto.RandomSerialGenerator{}}
+	servingConfig, err := signer.MakeServerCertForDuration(sets.NewString("example.com"), time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	encode := func(config *crypto.TLSCertificateConfig) ([]byte, []byte) {
+		certPEM, keyPEM, err := config.GetPEMBytes()
+		if err != nil {
+			t.Fatal(err)
+		}
+		return certPEM, keyPEM
+	}
+	_, signerKey := encode(signerConfig)
+	_, rootKey := encode(rootConfig)
+	_, servingKey := encode(servingConfig)
+	signerFirst, err := crypto.EncodeCertificates(signerConfig.Certs[0], rootConfig.Certs[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	rootFirst, err := crypto.EncodeCertificates(rootConfig.Certs[0], signerConfig.Certs[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	servingChain, err := crypto.EncodeCertificates(servingConfig.Certs[0], signerConfig.Certs[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, certPEM := range [][]byte{signerFirst, rootFirst} {
+		actual, intermediates, err := parseCSRSigner(certPEM, signerKey)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if actual.Subject.CommonName != "csr-signer" {
+			t.Errorf("expected the csr-signer certificate, got %q", actual.Subject.CommonName)
+		}
+		if len(intermediates) != 1 || intermediates[0].Subject.CommonName != "root" {
+			t.Errorf("expected the root as the only intermediate, got %v", intermediates)
+		}
+	}
+
+	onlySigner, err := crypto.EncodeCertificates(signerConfig.Certs[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := parseCSRSigner(onlySigner, rootKey); err == nil {
+		t.Errorf("expected a key of another certificate to be refused")
+	}
+	if _, _, err := parseCSRSigner(servingChain, servingKey); err == nil {
+		t.Errorf("expected a key of a certificate that is not a CA to be refused")
+	}
+}
@@ -0,0 +1,133 @@
+package targetconfigcontroller
+
+import (
+	"crypto/x509"
+	"fmt"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	corev1listers "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/util/cert"
+
+	operatorv1 "github.com/openshift/api/operator/v1"
+
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/csrsignerprovider"
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/operatorclient"
+)
+
+const (
+	csrSignerTrustMissingCondition = "CSRSignerTrustMissing"
+
+	// kubeletClientCAName is the CA bundle the kube-apiserver authenticates the kubelet client certificates with.
+	kubeletClientCAName = "kube-apiserver-client-ca"
+)
+
+// csrSignerTrustCondition checks that the certificates issued by the active csr-signer, or by the signer of the
+// external provider when one is configured, are trusted by the kubelet client CA bundle. When they are not, the node
+// CSRs approved and signed from now on yield client certificates the kube-apiserver rejects, which otherwise only
+// shows up as nodes failing to join.
+func (c *TargetConfigController) csrSignerTrustCondition(provider csrsignerprovider.Provider, now time.Time) operatorv1.OperatorCondition {
+	condition := operatorv1.OperatorCondition{
+		Type:   csrSignerTrustMissingCondition,
+		Status: operatorv1.ConditionUnknown,
+		Reason: "MissingInput",
+	}
+
+	var signerCert []byte
+	source := fmt.Sprintf("secret/csr-signer -n %s", operatorclient.TargetNamespace)
+	if provider != nil {
+		source = "the csr-signer of provider " + provider.Name()
+		certificate, err := provider.Certificate(c.configMapLister)
+		if err != nil {
+			condition.Message = fmt.Sprintf("%s: %v", source, err)
+			return condition
+		}
+		signerCert = certificate
+	} else {
+		signer, err := c.secretLister.Secrets(operatorclient.TargetNamespace).Get("csr-signer")
+		if apierrors.IsNotFound(err) {
+			condition.Message = fmt.Sprintf("%s is missing", source)
+			return condition
+		}
+		if err != nil {
+			condition.Message = err.Error()
+			return condition
+		}
+		signerCert = signer.Data["tls.crt"]
+	}
+	clientCA, err := c.configMapLister.ConfigMaps(operatorclient.GlobalMachineSpecifiedConfigNamespace).Get(kubeletClientCAName)
+	if apierrors.IsNotFound(err) {
+		condition.Message = fmt.Sprintf("configmap/%s -n %s is missing", kubeletClientCAName, operatorclient.GlobalMachineSpecifiedConfigNamespace)
+		return condition
+	}
+	if err != nil {
+		condition.Message = err.Error()
+		return condition
+	}
+
+	return checkCSRSignerTrust(signerCert, source, clientCA, now)
+}
+
+func checkCSRSignerTrust(signerCertBytes []byte, source string, clientCA *corev1.ConfigMap, now time.Time) operatorv1.OperatorCondition {
+	condition := operatorv1.OperatorCondition{
+		Type:   csrSignerTrustMissingCondition,
+		Status: operatorv1.ConditionTrue,
+	}
+
+	signerCerts, err := cert.ParseCertsPEM(signerCertBytes)
+	if err != nil {
+		condition.Status = operatorv1.ConditionUnknown
+		condition.Reason = "InvalidSigner"
+		condition.Message = fmt.Sprintf("failed to parse %s: %v", source, err)
+		return condition
+	}
+	signerCert := signerCerts[0]
+	if now.After(signerCert.NotAfter) {
+		condition.Reason = "SignerExpired"
+		condition.Message = fmt.Sprintf("The csr-signer certificate %q expired at %s, new node CSRs would be rejected", signerCert.Subject.CommonName, signerCert.NotAfter.UTC().Format(time.RFC3339))
+		return condition
+	}
+
+	if err := verifySignerTrust(signerCert, clientCA, now); err != nil {
+		condition.Reason = "SignerNotTrusted"
+		condition.Message = fmt.Sprintf("The csr-signer certificate %q is not trusted by configmap/%s -n %s, new node CSRs would be rejected: %v", signerCert.Subject.CommonName, clientCA.Name, clientCA.Namespace, err)
+		return condition
+	}
+
+	condition.Status = operatorv1.ConditionFalse
+	return condition
+}
+
+// verifySignerTrust checks that the certificates issued by the signer chain up to the kubelet client CA bundle.
+func verifySignerTrust(signerCert *x509.Certificate, clientCA *corev1.ConfigMap, now time.Time) error {
+	roots := x509.NewCertPool()
+	if caCerts, err := cert.ParseCertsPEM([]byte(clientCA.Data["ca-bundle.crt"])); err == nil {
+		for _, caCert := range caCerts {
+			roots.AddCert(caCert)
+		}
+	}
+	_, err := signerCert.Verify(x509.VerifyOptions{
+		Roots: roots,
+		// a signer that starts within the clock skew tolerance is trusted already
+		CurrentTime: skewTolerantTime(signerCert.NotBefore, now),
+		KeyUsages:   []x509.ExtKeyUsage{x509.ExtKeyUsageAny},
+	})
+	return err
+}
+
+// IsTrustedByKubeAPIServer returns whether the client CA bundle the kube-apiserver publishes trusts the signer.
+func IsTrustedByKubeAPIServer(configMapLister corev1listers.ConfigMapLister, signerCertBytes []byte, now time.Time) (bool, error) {
+	signerCerts, err := cert.ParseCertsPEM(signerCertBytes)
+	if err != nil {
+		return false, err
+	}
+	clientCA, err := configMapLister.ConfigMaps(operatorclient.GlobalMachineSpecifiedConfigNamespace).Get(kubeletClientCAName)
+	if apierrors.IsNotFound(err) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return verifySignerTrust(signerCerts[0], clientCA, now) == nil, nil
+}
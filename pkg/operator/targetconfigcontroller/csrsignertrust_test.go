@@ -0,0 +1,85 @@
+package targetconfigcontroller
+
+import (
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	operatorv1 "github.com/openshift/api/operator/v1"
+
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/operatorclient"
+)
+
+func TestCheckCSRSignerTrust(t *testing.T) {
+	now := time.Now()
+	signer := makeCerts(t, now, time.Hour)
+	otherCA := makeCerts(t, now, time.Hour)
+	expiredSigner := makeCerts(t, now.Add(-2*time.Hour), time.Hour)
+
+	clientCA := func(bundles ...[]byte) *corev1.ConfigMap {
+		bundle := ""
+		for _, b := range bundles {
+			bundle += string(b)
+		}
+		return &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Namespace: operatorclient.GlobalMachineSpecifiedConfigNamespace, Name: kubeletClientCAName},
+			Data:       map[string]string{"ca-bundle.crt": bundle},
+		}
+	}
+
+	tests := []struct {
+		name           string
+		signer         map[string][]byte
+		clientCA       *corev1.ConfigMap
+		expectedStatus operatorv1.ConditionStatus
+		expectedReason string
+	}{
+		{
+			name:           "trusted",
+			signer:         signer,
+			clientCA:       clientCA(otherCA["tls.crt"], signer["tls.crt"]),
+			expectedStatus: operatorv1.ConditionFalse,
+		},
+		{
+			name:           "not trusted",
+			signer:         signer,
+			clientCA:       clientCA(otherCA["tls.crt"]),
+			expectedStatus: operatorv1.ConditionTrue,
+			expectedReason: "SignerNotTrusted",
+		},
+		{
+			name:           "empty bundle",
+			signer:         signer,
+			clientCA:       clientCA(),
+			expectedStatus: operatorv1.ConditionTrue,
+			expectedReason: "SignerNotTrusted",
+		},
+		{
+			name:           "expired",
+			signer:         expiredSigner,
+			clientCA:       clientCA(expiredSigner["tls.crt"]),
+			expectedStatus: operatorv1.ConditionTrue,
+			expectedReason: "SignerExpired",
+		},
+		{
+			name:           "invalid signer",
+			signer:         map[string][]byte{"tls.crt": []byte("garbage")},
+			clientCA:       clientCA(signer["tls.crt"]),
+			expectedStatus: operatorv1.ConditionUnknown,
+			expectedReason: "InvalidSigner",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			condition := checkCSRSignerTrust(test.signer["tls.crt"], "secret/csr-signer", test.clientCA, now)
+			if condition.Type != csrSignerTrustMissingCondition {
+				t.Errorf("unexpected condition type %q", condition.Type)
+			}
+			if condition.Status != test.expectedStatus || condition.Reason != test.expectedReason {
+				t.Errorf("expected %s/%s, got %s/%s: %s", test.expectedStatus, test.expectedReason, condition.Status, condition.Reason, condition.Message)
+			}
+		})
+	}
+}
@@ -0,0 +1,161 @@
+package targetconfigcontroller
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/sets"
+	applycorev1 "k8s.io/client-go/applyconfigurations/core/v1"
+	corev1client "k8s.io/client-go/kubernetes/typed/core/v1"
+	"k8s.io/client-go/tools/cache"
+
+	"github.com/openshift/library-go/pkg/controller/factory"
+	"github.com/openshift/library-go/pkg/operator/events"
+)
+
+const (
+	// DryRunAnnotation on the kubecontrollermanager/cluster resource makes the TargetConfigController send all its
+	// writes to the target namespace as server-side dry-run requests, the other controllers ignore it. The resources
+	// that would change are reported in the TargetConfigControllerDryRun condition and in events, so that a change to
+	// the unsupportedConfigOverrides can be previewed before it rolls out a new revision of the control plane.
+	DryRunAnnotation = "kubecontrollermanager.operator.openshift.io/dry-run"
+
+	dryRunCondition = "TargetConfigControllerDryRun"
+)
+
+// isDryRun returns whether the operator resource asks for a dry-run.
+func isDryRun(operatorLister cache.GenericLister) (bool, error) {
+	uncastOperator, err := operatorLister.Get("cluster")
+	if err != nil {
+		return false, err
+	}
+	operator, err := meta.Accessor(uncastOperator)
+	if err != nil {
+		return false, err
+	}
+	return operator.GetAnnotations()[DryRunAnnotation] == "true", nil
+}
+
+// dryRunSyncContext tags the events of a dry-run sync, the resources they mention were not really changed.
+type dryRunSyncContext struct {
+	factory.SyncContext
+}
+
+func (c dryRunSyncContext) Recorder() events.Recorder {
+	return c.SyncContext.Recorder().WithComponentSuffix("dry-run")
+}
+
+// dryRunClient sends the configmap and secret writes as server-side dry-run requests and records the written resources.
+type dryRunClient struct {
+	corev1client.CoreV1Interface
+	changes sets.Set[string]
+}
+
+func newDryRunClient(client corev1client.CoreV1Interface) *dryRunClient {
+	return &dryRunClient{CoreV1Interface: client, changes: sets.New[string]()}
+}
+
+func (c *dryRunClient) ConfigMaps(namespace string) corev1client.ConfigMapInterface {
+	return &dryRunConfigMaps{ConfigMapInterface: c.CoreV1Interface.ConfigMaps(namespace), changes: c.changes}
+}
+
+func (c *dryRunClient) Secrets(namespace string) corev1client.SecretInterface {
+	return &dryRunSecrets{SecretInterface: c.CoreV1Interface.Secrets(namespace), changes: c.changes}
+}
+
+// Changes returns the resources that would have been written, sorted.
+func (c *dryRunClient) Changes() []string {
+	return sets.List(c.changes)
+}
+
+type dryRunConfigMaps struct {
+	corev1client.ConfigMapInterface
+	changes sets.Set[string]
+}
+
+func (c *dryRunConfigMaps) Create(ctx context.Context, configMap *corev1.ConfigMap, opts metav1.CreateOptions) (*corev1.ConfigMap, error) {
+	c.changes.Insert(fmt.Sprintf("configmap/%s", configMap.Name))
+	opts.DryRun = []string{metav1.DryRunAll}
+	return c.ConfigMapInterface.Create(ctx, configMap, opts)
+}
+
+func (c *dryRunConfigMaps) Update(ctx context.Context, configMap *corev1.ConfigMap, opts metav1.UpdateOptions) (*corev1.ConfigMap, error) {
+	c.changes.Insert(fmt.Sprintf("configmap/%s", configMap.Name))
+	opts.DryRun = []string{metav1.DryRunAll}
+	return c.ConfigMapInterface.Update(ctx, configMap, opts)
+}
+
+func (c *dryRunConfigMaps) Delete(ctx context.Context, name string, opts metav1.DeleteOptions) error {
+	c.changes.Insert(fmt.Sprintf("configmap/%s", name))
+	opts.DryRun = []string{metav1.DryRunAll}
+	return c.ConfigMapInterface.Delete(ctx, name, opts)
+}
+
+func (c *dryRunConfigMaps) Patch(ctx context.Context, name string, pt types.PatchType, data []byte, opts metav1.PatchOptions, subresources ...string) (*corev1.ConfigMap, error) {
+	c.changes.Insert(fmt.Sprintf("configmap/%s", name))
+	opts.DryRun = []string{metav1.DryRunAll}
+	return c.ConfigMapInterface.Patch(ctx, name, pt, data, opts, subresources...)
+}
+
+func (c *dryRunConfigMaps) Apply(ctx context.Context, configMap *applycorev1.ConfigMapApplyConfiguration, opts metav1.ApplyOptions) (*corev1.ConfigMap, error) {
+	c.changes.Insert(fmt.Sprintf("configmap/%s", *configMap.Name))
+	opts.DryRun = []string{metav1.DryRunAll}
+	return c.ConfigMapInterface.Apply(ctx, configMap, opts)
+}
+
+type dryRunSecrets struct {
+	corev1client.SecretInterface
+	changes sets.Set[string]
+}
+
+func (c *dryRunSecrets) Create(ctx context.Context, secret *corev1.Secret, opts metav1.CreateOptions) (*corev1.Secret, error) {
+	c.changes.Insert(fmt.Sprintf("secret/%s", secret.Name))
+	opts.DryRun = []string{metav1.DryRunAll}
+	return c.SecretInterface.Create(ctx, secret, opts)
+}
+
+func (c *dryRunSecrets) Update(ctx context.Context, secret *corev1.Secret, opts metav1.UpdateOptions) (*corev1.Secret, error) {
+	c.changes.Insert(fmt.Sprintf("secret/%s", secret.Name))
+	opts.DryRun = []string{metav1.DryRunAll}
+	return c.SecretInterface.Update(ctx, secret, opts)
+}
+
+func (c *dryRunSecrets) Delete(ctx context.Context, name string, opts metav1.DeleteOptions) error {
+	c.changes.Insert(fmt.Sprintf("secret/%s", name))
+	opts.DryRun = []string{metav1.DryRunAll}
+	return c.SecretInterface.Delete(ctx, name, opts)
+}
+
+func (c *dryRunSecrets) Patch(ctx context.Context, name string, pt types.PatchType, data []byte, opts metav1.PatchOptions, subresources ...string) (*corev1.Secret, error) {
+	c.changes.Insert(fmt.Sprintf("secret/%s", name))
+	opts.DryRun = []string{metav1.DryRunAll}
+	return c.SecretInterface.Patch(ctx, name, pt, data, opts, subresources...)
+}
+
+func (c *dryRunSecrets) Apply(ctx context.Context, secret *applycorev1.SecretApplyConfiguration, opts metav1.ApplyOptions) (*corev1.Secret, error) {
+	c.changes.Insert(fmt.Sprintf("secret/%s", *secret.Name))
+	opts.DryRun = []string{metav1.DryRunAll}
+	return c.SecretInterface.Apply(ctx, secret, opts)
+}
+
+// dryRunMessage describes the resources that would change, grouped by syncer in syncer order, and the resources the
+// dry-run covers. Only the writes of the TargetConfigController are dry-run, the KubeconfigController,
+// ResourceSyncController, RollbackController, HostedControlPlaneController and the certificate controllers keep
+// writing theirs.
+func dryRunMessage(syncers []targetConfigSyncer, changes map[string][]string) string {
+	var resources, covered []string
+	for _, syncer := range syncers {
+		resources = append(resources, changes[syncer.name]...)
+		covered = append(covered, syncer.resource)
+	}
+	message := "No resources would change"
+	if len(resources) > 0 {
+		message = fmt.Sprintf("The following resources would change: %s", strings.Join(resources, ", "))
+	}
+	return fmt.Sprintf("%s. Only %s are previewed, the kubeconfigs, copied configmaps and secrets, rollbacks, hosted control plane and certificates are still written", message, strings.Join(covered, ", "))
+}
@@ -0,0 +1,99 @@
+package targetconfigcontroller
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/tools/cache"
+
+	"github.com/openshift/library-go/pkg/operator/events"
+	"github.com/openshift/library-go/pkg/operator/resource/resourceapply"
+
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/operatorclient"
+)
+
+type fakeOperatorLister struct {
+	operator *unstructured.Unstructured
+}
+
+func (l fakeOperatorLister) List(_ labels.Selector) ([]runtime.Object, error) {
+	return []runtime.Object{l.operator}, nil
+}
+
+func (l fakeOperatorLister) Get(_ string) (runtime.Object, error) {
+	return l.operator, nil
+}
+
+func (l fakeOperatorLister) ByNamespace(_ string) cache.GenericNamespaceLister {
+	return nil
+}
+
+func TestIsDryRun(t *testing.T) {
+	for value, expected := range map[string]bool{
+		"":      false,
+		"true":  true,
+		"false": false,
+	} {
+		operator := &unstructured.Unstructured{}
+		operator.SetName("cluster")
+		if len(value) > 0 {
+			operator.SetAnnotations(map[string]string{DryRunAnnotation: value})
+		}
+		actual, err := isDryRun(fakeOperatorLister{operator: operator})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if actual != expected {
+			t.Errorf("annotation %q: expected %v, got %v", value, expected, actual)
+		}
+	}
+}
+
+func TestDryRunClient(t *testing.T) {
+	existing := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Namespace: operatorclient.TargetNamespace, Name: "config"},
+		Data:       map[string]string{"config.yaml": "old"},
+	}
+	unchanged := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Namespace: operatorclient.TargetNamespace, Name: "recycler-config"},
+		Data:       map[string]string{"recycler-pod.yaml": "pod"},
+	}
+	client := newDryRunClient(fake.NewSimpleClientset(existing, unchanged).CoreV1())
+	recorder := events.NewInMemoryRecorder("dry-run")
+
+	for _, required := range []*corev1.ConfigMap{
+		{ObjectMeta: existing.ObjectMeta, Data: map[string]string{"config.yaml": "new"}},
+		unchanged.DeepCopy(),
+		{ObjectMeta: metav1.ObjectMeta{Namespace: operatorclient.TargetNamespace, Name: "serviceaccount-ca"}},
+	} {
+		if _, _, err := resourceapply.ApplyConfigMap(context.TODO(), client, recorder, required); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if expected := []string{"configmap/config", "configmap/serviceaccount-ca"}; !reflect.DeepEqual(expected, client.Changes()) {
+		t.Errorf("expected changes %v, got %v", expected, client.Changes())
+	}
+}
+
+func TestDryRunMessage(t *testing.T) {
+	syncers := []targetConfigSyncer{{name: "config", resource: "configmap/config"}, {name: "pod", resource: "configmap/kube-controller-manager-pod"}}
+	scope := ". Only configmap/config, configmap/kube-controller-manager-pod are previewed, the kubeconfigs, copied configmaps and secrets, rollbacks, hosted control plane and certificates are still written"
+	if actual := dryRunMessage(syncers, map[string][]string{"pod": nil}); actual != "No resources would change"+scope {
+		t.Errorf("unexpected message %q", actual)
+	}
+	actual := dryRunMessage(syncers, map[string][]string{
+		"pod":    {"configmap/kube-controller-manager-pod"},
+		"config": {"configmap/config"},
+	})
+	if expected := "The following resources would change: configmap/config, configmap/kube-controller-manager-pod" + scope; actual != expected {
+		t.Errorf("expected %q, got %q", expected, actual)
+	}
+}
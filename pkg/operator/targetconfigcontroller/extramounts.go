@@ -0,0 +1,144 @@
+package targetconfigcontroller
+
+import (
+	"context"
+	"path"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
+	corev1client "k8s.io/client-go/kubernetes/typed/core/v1"
+
+	operatorv1 "github.com/openshift/api/operator/v1"
+	"github.com/openshift/library-go/pkg/controller/factory"
+	"github.com/openshift/library-go/pkg/operator/events"
+	"github.com/openshift/library-go/pkg/operator/resource/resourceapply"
+
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/operatorclient"
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/tuning"
+)
+
+// extraMountsName is the configmap and the secret in the target namespace that gather the sources of the tuning
+// ExtraMounts. They are revisioned with the pod, so the files show up in the resource dir of the static pod, and every
+// key is mounted from there.
+const extraMountsName = "extra-mounts"
+
+func (c *TargetConfigController) syncExtraMounts(ctx context.Context, syncCtx factory.SyncContext, client corev1client.CoreV1Interface, _ *operatorv1.StaticPodOperatorSpec) error {
+	tuningConfig, err := tuning.Get(c.configMapLister)
+	if err != nil {
+		return err
+	}
+	c.extraMountSecrets.watch(ctx, tuningConfig.ExtraMounts, func() { syncCtx.Queue().Add("extra-mounts") })
+	return manageExtraMounts(ctx, client, syncCtx.Recorder(), tuningConfig.ExtraMounts)
+}
+
+// manageExtraMounts copies the sources of the mounts into the extraMountsName configmap and secret, prefixing their
+// keys with the mount name. Empty ones are deleted so that no revision carries them.
+func manageExtraMounts(ctx context.Context, client corev1client.CoreV1Interface, recorder events.Recorder, mounts []tuning.ExtraMount) error {
+	configMap := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Namespace: operatorclient.TargetNamespace, Name: extraMountsName},
+		Data:       map[string]string{},
+		BinaryData: map[string][]byte{},
+	}
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Namespace: operatorclient.TargetNamespace, Name: extraMountsName},
+		Type:       corev1.SecretTypeOpaque,
+		Data:       map[string][]byte{},
+	}
+	for _, mount := range mounts {
+		if len(mount.ConfigMap) > 0 {
+			source, err := client.ConfigMaps(operatorclient.GlobalUserSpecifiedConfigNamespace).Get(ctx, mount.ConfigMap, metav1.GetOptions{})
+			if err != nil {
+				return err
+			}
+			for key, value := range source.Data {
+				configMap.Data[extraMountKey(mount.Name, key)] = value
+			}
+			for key, value := range source.BinaryData {
+				configMap.BinaryData[extraMountKey(mount.Name, key)] = value
+			}
+			continue
+		}
+		source, err := client.Secrets(operatorclient.GlobalUserSpecifiedConfigNamespace).Get(ctx, mount.Secret, metav1.GetOptions{})
+		if err != nil {
+			return err
+		}
+		for key, value := range source.Data {
+			secret.Data[extraMountKey(mount.Name, key)] = value
+		}
+	}
+
+	if len(configMap.Data) == 0 && len(configMap.BinaryData) == 0 {
+		if _, _, err := resourceapply.DeleteConfigMap(ctx, client, recorder, configMap); err != nil {
+			return err
+		}
+	} else if _, _, err := ApplyConfigMap(ctx, client, recorder, configMap); err != nil {
+		return err
+	}
+	if len(secret.Data) == 0 {
+		_, _, err := resourceapply.DeleteSecret(ctx, client, recorder, secret)
+		return err
+	}
+	_, _, err := ApplySecret(ctx, client, recorder, secret)
+	return err
+}
+
+// extraMountKey is unambiguous, the mount name is a DNS label without dots.
+func extraMountKey(mountName, key string) string {
+	return mountName + "." + key
+}
+
+// addExtraMounts mounts every key of the extraMountsName configmap and secret into the kube-controller-manager
+// container, from the resource dir the installer copies them to.
+func addExtraMounts(ctx context.Context, pod *corev1.Pod, configMapsGetter corev1client.ConfigMapsGetter, secretsGetter corev1client.SecretsGetter, mounts []tuning.ExtraMount) error {
+	if len(mounts) == 0 {
+		return nil
+	}
+	configMapKeys := sets.New[string]()
+	configMap, err := configMapsGetter.ConfigMaps(operatorclient.TargetNamespace).Get(ctx, extraMountsName, metav1.GetOptions{})
+	switch {
+	case apierrors.IsNotFound(err):
+	case err != nil:
+		return err
+	default:
+		configMapKeys.Insert(sets.KeySet(configMap.Data).UnsortedList()...)
+		configMapKeys.Insert(sets.KeySet(configMap.BinaryData).UnsortedList()...)
+	}
+	secretKeys := sets.New[string]()
+	secret, err := secretsGetter.Secrets(operatorclient.TargetNamespace).Get(ctx, extraMountsName, metav1.GetOptions{})
+	switch {
+	case apierrors.IsNotFound(err):
+	case err != nil:
+		return err
+	default:
+		secretKeys.Insert(sets.KeySet(secret.Data).UnsortedList()...)
+	}
+
+	for i := range pod.Spec.Containers {
+		container := &pod.Spec.Containers[i]
+		if container.Name != "kube-controller-manager" {
+			continue
+		}
+		for _, mount := range mounts {
+			resourceDir, keys := "configmaps", configMapKeys
+			if len(mount.Secret) > 0 {
+				resourceDir, keys = "secrets", secretKeys
+			}
+			prefix := extraMountKey(mount.Name, "")
+			for _, key := range sets.List(keys) {
+				if !strings.HasPrefix(key, prefix) {
+					continue
+				}
+				container.VolumeMounts = append(container.VolumeMounts, corev1.VolumeMount{
+					Name:      "resource-dir",
+					MountPath: path.Join(mount.MountPath, strings.TrimPrefix(key, prefix)),
+					SubPath:   path.Join(resourceDir, extraMountsName, key),
+					ReadOnly:  true,
+				})
+			}
+		}
+	}
+	return nil
+}
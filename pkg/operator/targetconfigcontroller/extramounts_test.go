@@ -0,0 +1,111 @@
+package targetconfigcontroller
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/kubernetes/fake"
+
+	"github.com/openshift/library-go/pkg/operator/events"
+
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/operatorclient"
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/tuning"
+)
+
+func TestExtraMounts(t *testing.T) {
+	kubeClient := fake.NewSimpleClientset(
+		&corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Namespace: operatorclient.GlobalUserSpecifiedConfigNamespace, Name: "cloud-ca"},
+			Data:       map[string]string{"ca.crt": "ca"},
+		},
+		&corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Namespace: operatorclient.GlobalUserSpecifiedConfigNamespace, Name: "webhook"},
+			Data:       map[string][]byte{"token": []byte("token"), "config.yaml": []byte("config")},
+		},
+	)
+	recorder := events.NewInMemoryRecorder("extra-mounts")
+	mounts := []tuning.ExtraMount{
+		{Name: "cloud-ca", ConfigMap: "cloud-ca", MountPath: "/etc/cloud-ca"},
+		{Name: "webhook", Secret: "webhook", MountPath: "/etc/webhook"},
+	}
+
+	if err := manageExtraMounts(context.TODO(), kubeClient.CoreV1(), recorder, mounts); err != nil {
+		t.Fatal(err)
+	}
+	pod := &corev1.Pod{Spec: corev1.PodSpec{Containers: []corev1.Container{{Name: "kube-controller-manager"}, {Name: "cluster-policy-controller"}}}}
+	if err := addExtraMounts(context.TODO(), pod, kubeClient.CoreV1(), kubeClient.CoreV1(), mounts); err != nil {
+		t.Fatal(err)
+	}
+	expected := []corev1.VolumeMount{
+		{Name: "resource-dir", MountPath: "/etc/cloud-ca/ca.crt", SubPath: "configmaps/extra-mounts/cloud-ca.ca.crt", ReadOnly: true},
+		{Name: "resource-dir", MountPath: "/etc/webhook/config.yaml", SubPath: "secrets/extra-mounts/webhook.config.yaml", ReadOnly: true},
+		{Name: "resource-dir", MountPath: "/etc/webhook/token", SubPath: "secrets/extra-mounts/webhook.token", ReadOnly: true},
+	}
+	if !reflect.DeepEqual(expected, pod.Spec.Containers[0].VolumeMounts) {
+		t.Errorf("expected mounts %v, got %v", expected, pod.Spec.Containers[0].VolumeMounts)
+	}
+	if len(pod.Spec.Containers[1].VolumeMounts) > 0 {
+		t.Errorf("expected no mounts in the cluster-policy-controller, got %v", pod.Spec.Containers[1].VolumeMounts)
+	}
+
+	// a missing source fails the sync
+	if err := manageExtraMounts(context.TODO(), kubeClient.CoreV1(), recorder, []tuning.ExtraMount{{Name: "missing", ConfigMap: "missing", MountPath: "/etc/missing"}}); !apierrors.IsNotFound(err) {
+		t.Errorf("expected a not found error, got %v", err)
+	}
+
+	// removed mounts delete the copies
+	if err := manageExtraMounts(context.TODO(), kubeClient.CoreV1(), recorder, nil); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := kubeClient.CoreV1().ConfigMaps(operatorclient.TargetNamespace).Get(context.TODO(), extraMountsName, metav1.GetOptions{}); !apierrors.IsNotFound(err) {
+		t.Errorf("expected the configmap to be deleted, got %v", err)
+	}
+	if _, err := kubeClient.CoreV1().Secrets(operatorclient.TargetNamespace).Get(context.TODO(), extraMountsName, metav1.GetOptions{}); !apierrors.IsNotFound(err) {
+		t.Errorf("expected the secret to be deleted, got %v", err)
+	}
+}
+
+func TestExtraMountSecrets(t *testing.T) {
+	kubeClient := fake.NewSimpleClientset(&corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Namespace: operatorclient.GlobalUserSpecifiedConfigNamespace, Name: "webhook", ResourceVersion: "1"},
+		Data:       map[string][]byte{"token": []byte("token")},
+	})
+	ctx, cancel := context.WithCancel(context.TODO())
+	defer cancel()
+	changes := make(chan struct{}, 10)
+	watcher := newExtraMountSecrets(kubeClient)
+
+	watcher.watch(ctx, []tuning.ExtraMount{{Name: "webhook", Secret: "webhook", MountPath: "/etc/webhook"}}, func() { changes <- struct{}{} })
+	select {
+	case <-changes:
+	case <-time.After(wait.ForeverTestTimeout):
+		t.Fatal("expected the mounted secret to queue a sync")
+	}
+	if versions := watcher.resourceVersions(); versions["webhook"] != "1" {
+		t.Errorf("expected the resource version of the mounted secret in the fingerprint, got %v", versions)
+	}
+
+	// an edit of the secret syncs the mounts again
+	if _, err := kubeClient.CoreV1().Secrets(operatorclient.GlobalUserSpecifiedConfigNamespace).Update(ctx, &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Namespace: operatorclient.GlobalUserSpecifiedConfigNamespace, Name: "webhook", ResourceVersion: "2"},
+		Data:       map[string][]byte{"token": []byte("rotated")},
+	}, metav1.UpdateOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case <-changes:
+	case <-time.After(wait.ForeverTestTimeout):
+		t.Fatal("expected an edit of the mounted secret to queue a sync")
+	}
+
+	watcher.watch(ctx, nil, func() { changes <- struct{}{} })
+	if versions := watcher.resourceVersions(); len(versions) > 0 {
+		t.Errorf("expected the secrets no mount names to be unwatched, got %v", versions)
+	}
+}
@@ -0,0 +1,106 @@
+package targetconfigcontroller
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/util/sets"
+	coreinformers "k8s.io/client-go/informers/core/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/klog/v2"
+
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/operatorclient"
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/tuning"
+)
+
+// extraMountSecretsResync matches the resync of the TargetConfigController.
+const extraMountSecretsResync = time.Minute
+
+// extraMountSecrets watches the secrets of GlobalUserSpecifiedConfigNamespace the tuning ExtraMounts name. The shared
+// secret informer of the namespace only watches operatorclient.UserSpecifiedConfigSecretName and a field selector
+// matches a single name, so every mounted secret gets an informer of its own. It is started once a sync of the
+// extra-mounts reads the secret and stopped once no mount names it anymore. A nil extraMountSecrets watches nothing.
+type extraMountSecrets struct {
+	client kubernetes.Interface
+
+	lock      sync.Mutex
+	informers map[string]*extraMountSecretInformer
+}
+
+type extraMountSecretInformer struct {
+	informer cache.SharedIndexInformer
+	stop     context.CancelFunc
+}
+
+func newExtraMountSecrets(client kubernetes.Interface) *extraMountSecrets {
+	return &extraMountSecrets{client: client, informers: map[string]*extraMountSecretInformer{}}
+}
+
+// watch starts the informers of the secrets the mounts name and stops the others. An event of a watched secret calls
+// onChange. The informers run until ctx is done.
+func (w *extraMountSecrets) watch(ctx context.Context, mounts []tuning.ExtraMount, onChange func()) {
+	if w == nil {
+		return
+	}
+	names := sets.New[string]()
+	for _, mount := range mounts {
+		if len(mount.Secret) > 0 {
+			names.Insert(mount.Secret)
+		}
+	}
+
+	w.lock.Lock()
+	defer w.lock.Unlock()
+	for name, watched := range w.informers {
+		if !names.Has(name) {
+			watched.stop()
+			delete(w.informers, name)
+		}
+	}
+	for _, name := range sets.List(names) {
+		if _, ok := w.informers[name]; ok {
+			continue
+		}
+		informer := coreinformers.NewFilteredSecretInformer(w.client, operatorclient.GlobalUserSpecifiedConfigNamespace, extraMountSecretsResync, cache.Indexers{}, func(options *metav1.ListOptions) {
+			options.FieldSelector = fields.OneTermEqualSelector("metadata.name", name).String()
+		})
+		if err := informer.SetTransform(operatorclient.TransformCachedObject); err != nil {
+			klog.FromContext(ctx).Error(err, "Failed to watch the secret of an extra mount", "secret", name)
+			continue
+		}
+		if _, err := informer.AddEventHandler(cache.ResourceEventHandlerFuncs{
+			AddFunc:    func(interface{}) { onChange() },
+			UpdateFunc: func(interface{}, interface{}) { onChange() },
+			DeleteFunc: func(interface{}) { onChange() },
+		}); err != nil {
+			klog.FromContext(ctx).Error(err, "Failed to watch the secret of an extra mount", "secret", name)
+			continue
+		}
+		informerCtx, stop := context.WithCancel(ctx)
+		go informer.Run(informerCtx.Done())
+		w.informers[name] = &extraMountSecretInformer{informer: informer, stop: stop}
+	}
+}
+
+// resourceVersions returns the resource versions of the watched secrets, by name, for the input fingerprint.
+func (w *extraMountSecrets) resourceVersions() map[string]string {
+	versions := map[string]string{}
+	if w == nil {
+		return versions
+	}
+	w.lock.Lock()
+	defer w.lock.Unlock()
+	for name, watched := range w.informers {
+		for _, obj := range watched.informer.GetStore().List() {
+			if secret, ok := obj.(*corev1.Secret); ok {
+				versions[name] = secret.ResourceVersion
+			}
+		}
+	}
+	return versions
+}
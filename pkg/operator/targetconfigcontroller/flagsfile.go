@@ -0,0 +1,65 @@
+package targetconfigcontroller
+
+import (
+	"context"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	corev1client "k8s.io/client-go/kubernetes/typed/core/v1"
+
+	"github.com/openshift/library-go/pkg/operator/events"
+
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/operatorclient"
+)
+
+const (
+	// FlagsConfigMapName is the revisioned configmap holding the kube-controller-manager flags, one per line, when
+	// the tuning configmap selects the flags file. Upstream has no config file for the kube-controller-manager, so
+	// the file is read into the exec line by the startup script.
+	FlagsConfigMapName = "kube-controller-manager-flags"
+	FlagsKey           = "flags"
+
+	flagsFilePath = "/etc/kubernetes/static-pod-resources/configmaps/" + FlagsConfigMapName + "/" + FlagsKey
+	execLine      = "exec hyperkube kube-controller-manager"
+)
+
+// useFlagsFile writes the flags to the FlagsConfigMapName configmap and makes the startup script of the pod pass
+// them to the kube-controller-manager. The pod manifest then stays the same when only the flags change, and the
+// flags of two revisions diff line by line.
+func useFlagsFile(ctx context.Context, client corev1client.ConfigMapsGetter, recorder events.Recorder, pod *corev1.Pod, flags []string) error {
+	content := ""
+	if len(flags) > 0 {
+		content = strings.Join(flags, "\n") + "\n"
+	}
+	if _, _, err := ApplyConfigMap(ctx, client, recorder, &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Namespace: operatorclient.TargetNamespace, Name: FlagsConfigMapName},
+		Data:       map[string]string{FlagsKey: content},
+	}); err != nil {
+		return err
+	}
+
+	script := &pod.Spec.Containers[0].Args[0]
+	*script = strings.Replace(*script, execLine, "mapfile -t flags < "+flagsFilePath+"\n\n"+execLine, 1)
+	*script = strings.TrimSpace(*script) + ` "${flags[@]}"`
+	return nil
+}
+
+// deleteFlagsFile removes the FlagsConfigMapName configmap once the flags are back on the exec line.
+func deleteFlagsFile(ctx context.Context, client corev1client.ConfigMapsGetter, recorder events.Recorder) error {
+	if _, err := client.ConfigMaps(operatorclient.TargetNamespace).Get(ctx, FlagsConfigMapName, metav1.GetOptions{}); apierrors.IsNotFound(err) {
+		return nil
+	} else if err != nil {
+		return err
+	}
+	err := client.ConfigMaps(operatorclient.TargetNamespace).Delete(ctx, FlagsConfigMapName, metav1.DeleteOptions{})
+	if apierrors.IsNotFound(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	recorder.Eventf("ConfigMapDeleted", "Deleted configmap/%s -n %s, the flags are passed on the exec line again", FlagsConfigMapName, operatorclient.TargetNamespace)
+	return nil
+}
@@ -0,0 +1,252 @@
+package targetconfigcontroller
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/client-go/dynamic"
+
+	imagev1 "github.com/openshift/api/image/v1"
+	operatorv1 "github.com/openshift/api/operator/v1"
+
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/operatorclient"
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/tuning"
+)
+
+// operandImageArchitectureMismatchCondition reports whether the images of the operand run on every architecture of
+// the control plane. The images are inspected for a heterogeneous control plane, whose nodes all get the same
+// manifest and so need manifest lists, and for the architectureImages in use. The payload images of a control plane
+// of a single architecture are not.
+const operandImageArchitectureMismatchCondition = "OperandImageArchitectureMismatch"
+
+const (
+	// imageInspectionName is the ImageStreamImport the images are inspected with. It does not import anything and is
+	// not stored.
+	imageInspectionName = "kube-controller-manager-operand-images"
+	// imageInspectionRetry bounds how often a failed inspection is repeated and imageInspectionTagExpiry how long the
+	// architectures of a pull spec by tag are trusted. Those of a pull spec by digest never change.
+	imageInspectionRetry     = 5 * time.Minute
+	imageInspectionTagExpiry = time.Hour
+)
+
+var imageStreamImportsResource = schema.GroupVersionResource{Group: "image.openshift.io", Version: "v1", Resource: "imagestreamimports"}
+
+// imageArchitectures returns the architectures an image runs on. The openshift-apiserver reads the manifest from the
+// registry with the pull secret of the cluster, the operator does not need registry access of its own. A nil
+// imageArchitectures inspects nothing.
+type imageArchitectures struct {
+	client dynamic.Interface
+	now    func() time.Time
+
+	lock      sync.Mutex
+	inspected map[string]inspectedImage
+}
+
+type inspectedImage struct {
+	architectures sets.Set[string]
+	err           error
+	at            time.Time
+}
+
+func newImageArchitectures(client dynamic.Interface) *imageArchitectures {
+	return &imageArchitectures{client: client, now: time.Now, inspected: map[string]inspectedImage{}}
+}
+
+// get returns the architectures of the manifests of a manifest list, or the one of a single manifest.
+func (i *imageArchitectures) get(ctx context.Context, pullSpec string) (sets.Set[string], error) {
+	if i == nil || i.client == nil {
+		return nil, fmt.Errorf("the image API is not available")
+	}
+	i.lock.Lock()
+	defer i.lock.Unlock()
+	now := i.now()
+	if inspected, ok := i.inspected[pullSpec]; ok {
+		switch {
+		case inspected.err != nil && now.Sub(inspected.at) < imageInspectionRetry:
+			return nil, inspected.err
+		case inspected.err == nil && (strings.Contains(pullSpec, "@sha256:") || now.Sub(inspected.at) < imageInspectionTagExpiry):
+			return inspected.architectures, nil
+		}
+	}
+	architectures, err := i.inspect(ctx, pullSpec)
+	i.inspected[pullSpec] = inspectedImage{architectures: architectures, err: err, at: now}
+	return architectures, err
+}
+
+func (i *imageArchitectures) inspect(ctx context.Context, pullSpec string) (sets.Set[string], error) {
+	required := &imagev1.ImageStreamImport{
+		TypeMeta:   metav1.TypeMeta{APIVersion: imagev1.GroupVersion.String(), Kind: "ImageStreamImport"},
+		ObjectMeta: metav1.ObjectMeta{Namespace: operatorclient.TargetNamespace, Name: imageInspectionName},
+		Spec: imagev1.ImageStreamImportSpec{
+			Import: false,
+			Images: []imagev1.ImageImportSpec{{
+				From: corev1.ObjectReference{Kind: "DockerImage", Name: pullSpec},
+				// the manifest list is kept instead of the manifest of the architecture of the openshift-apiserver
+				ImportPolicy: imagev1.TagImportPolicy{ImportMode: imagev1.ImportModePreserveOriginal},
+			}},
+		},
+	}
+	requiredObj, err := runtime.DefaultUnstructuredConverter.ToUnstructured(required)
+	if err != nil {
+		return nil, err
+	}
+	resultObj, err := i.client.Resource(imageStreamImportsResource).Namespace(operatorclient.TargetNamespace).Create(ctx, &unstructured.Unstructured{Object: requiredObj}, metav1.CreateOptions{})
+	if err != nil {
+		return nil, err
+	}
+	result := &imagev1.ImageStreamImport{}
+	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(resultObj.Object, result); err != nil {
+		return nil, err
+	}
+	if len(result.Status.Images) == 0 {
+		return nil, fmt.Errorf("the image was not inspected")
+	}
+	status := result.Status.Images[0]
+	if status.Status.Status != metav1.StatusSuccess || status.Image == nil {
+		return nil, fmt.Errorf("%s", status.Status.Message)
+	}
+
+	architectures := sets.New[string]()
+	for _, manifest := range status.Image.DockerImageManifests {
+		if manifest.OS == "linux" && len(manifest.Architecture) > 0 {
+			architectures.Insert(manifest.Architecture)
+		}
+	}
+	if len(status.Image.DockerImageManifests) > 0 {
+		return architectures, nil
+	}
+	metadata := status.Image.DockerImageMetadata.Raw
+	if len(metadata) == 0 && status.Image.DockerImageMetadata.Object != nil {
+		if metadata, err = json.Marshal(status.Image.DockerImageMetadata.Object); err != nil {
+			return nil, err
+		}
+	}
+	image := struct {
+		Architecture string `json:"architecture"`
+	}{}
+	if err := json.Unmarshal(metadata, &image); err != nil || len(image.Architecture) == 0 {
+		return nil, fmt.Errorf("the architecture of the image is unknown")
+	}
+	return architectures.Insert(image.Architecture), nil
+}
+
+// operandImageNames are the json names of the images of tuning.OperandImages.
+var operandImageNames = []string{"kubeControllerManager", "clusterPolicyController", "operator"}
+
+func operandImage(images tuning.OperandImages, name string) string {
+	switch name {
+	case "kubeControllerManager":
+		return images.KubeControllerManager
+	case "clusterPolicyController":
+		return images.ClusterPolicyController
+	default:
+		return images.Operator
+	}
+}
+
+func setOperandImage(images *tuning.OperandImages, name, pullSpec string) {
+	switch name {
+	case "kubeControllerManager":
+		images.KubeControllerManager = pullSpec
+	case "clusterPolicyController":
+		images.ClusterPolicyController = pullSpec
+	default:
+		images.Operator = pullSpec
+	}
+}
+
+// resolveOperandImages overrides the default images with the ones configured for the architectures of the control
+// plane nodes, and checks that the images run on all of them. The same static pod manifest is installed on every
+// control plane node, so an image of a heterogeneous control plane is only overridden when the architectureImages of
+// all its architectures agree on it, a manifest list. An override lacking an architecture is an error, the previous
+// manifest is kept. A payload image lacking one is only reported, there is no other image to run.
+func resolveOperandImages(ctx context.Context, defaults tuning.OperandImages, architectureImages map[string]tuning.OperandImages, nodes []*corev1.Node, inspect func(context.Context, string) (sets.Set[string], error)) (tuning.OperandImages, operatorv1.OperatorCondition, error) {
+	condition := operatorv1.OperatorCondition{
+		Type:   operandImageArchitectureMismatchCondition,
+		Status: operatorv1.ConditionFalse,
+		Reason: "AsExpected",
+	}
+	architectures := sets.New[string]()
+	for _, node := range nodes {
+		if len(node.Status.NodeInfo.Architecture) > 0 {
+			architectures.Insert(node.Status.NodeInfo.Architecture)
+		}
+	}
+	if architectures.Len() == 0 {
+		// nothing is known about the control plane yet, stay with the release payload
+		condition.Message = "The architectures of the control plane nodes are not known yet"
+		return defaults, condition, nil
+	}
+	architectureList := strings.Join(sets.List(architectures), ", ")
+
+	resolved := defaults
+	overridden := sets.New[string]()
+	for _, name := range operandImageNames {
+		overrides := sets.New[string]()
+		for architecture := range architectures {
+			overrides.Insert(operandImage(architectureImages[architecture], name))
+		}
+		switch {
+		case overrides.Len() > 1:
+			err := fmt.Errorf("architectureImages set different %s images for the architectures of the control plane (%s), every node runs the same manifest, which needs a single manifest list", name, architectureList)
+			condition.Status = operatorv1.ConditionTrue
+			condition.Reason = "ArchitectureMissing"
+			condition.Message = err.Error()
+			return defaults, condition, err
+		case !overrides.Has(""):
+			setOperandImage(&resolved, name, sets.List(overrides)[0])
+			overridden.Insert(name)
+		}
+	}
+
+	var overrideProblems, payloadProblems, unknown []string
+	for _, name := range operandImageNames {
+		if architectures.Len() == 1 && !overridden.Has(name) {
+			// the release payload matches the architecture of a control plane of a single architecture
+			continue
+		}
+		pullSpec := operandImage(resolved, name)
+		imageArchitectures, err := inspect(ctx, pullSpec)
+		if err != nil {
+			unknown = append(unknown, fmt.Sprintf("%s %s: %v", name, pullSpec, err))
+			continue
+		}
+		if lacking := architectures.Difference(imageArchitectures); lacking.Len() > 0 {
+			problem := fmt.Sprintf("%s %s lacks %s", name, pullSpec, strings.Join(sets.List(lacking), ", "))
+			if overridden.Has(name) {
+				overrideProblems = append(overrideProblems, "architectureImages: "+problem)
+			} else {
+				payloadProblems = append(payloadProblems, problem)
+			}
+		}
+	}
+
+	switch {
+	case len(overrideProblems)+len(payloadProblems) > 0:
+		condition.Status = operatorv1.ConditionTrue
+		condition.Reason = "ArchitectureMissing"
+		condition.Message = fmt.Sprintf("The images must run on the control plane architectures %s: %s", architectureList, strings.Join(append(overrideProblems, payloadProblems...), "; "))
+	case len(unknown) > 0:
+		condition.Status = operatorv1.ConditionUnknown
+		condition.Reason = "InspectionFailed"
+		condition.Message = fmt.Sprintf("The images could not be checked for the control plane architectures %s: %s", architectureList, strings.Join(unknown, "; "))
+	case overridden.Len() == 0 && architectures.Len() == 1:
+		condition.Message = fmt.Sprintf("The release payload images run on the %s control plane", architectureList)
+	default:
+		condition.Message = fmt.Sprintf("The images run on the control plane architectures %s", architectureList)
+	}
+	if len(overrideProblems) > 0 {
+		return defaults, condition, fmt.Errorf("%s", strings.Join(overrideProblems, "; "))
+	}
+	return resolved, condition, nil
+}
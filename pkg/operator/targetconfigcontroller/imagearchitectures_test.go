@@ -0,0 +1,109 @@
+package targetconfigcontroller
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/client-go/dynamic"
+
+	imagev1 "github.com/openshift/api/image/v1"
+)
+
+// fakeImageStreamImports answers the creation of an ImageStreamImport with the image of the pull spec.
+type fakeImageStreamImports struct {
+	dynamic.NamespaceableResourceInterface
+	images  map[string]*imagev1.Image
+	creates int
+}
+
+func (f *fakeImageStreamImports) Resource(schema.GroupVersionResource) dynamic.NamespaceableResourceInterface {
+	return f
+}
+
+func (f *fakeImageStreamImports) Namespace(string) dynamic.ResourceInterface {
+	return f
+}
+
+func (f *fakeImageStreamImports) Create(_ context.Context, obj *unstructured.Unstructured, _ metav1.CreateOptions, _ ...string) (*unstructured.Unstructured, error) {
+	f.creates++
+	imageStreamImport := &imagev1.ImageStreamImport{}
+	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(obj.Object, imageStreamImport); err != nil {
+		return nil, err
+	}
+	if imageStreamImport.Spec.Import || imageStreamImport.Spec.Images[0].ImportPolicy.ImportMode != imagev1.ImportModePreserveOriginal {
+		return nil, fmt.Errorf("unexpected spec %#v", imageStreamImport.Spec)
+	}
+	pullSpec := imageStreamImport.Spec.Images[0].From.Name
+	status := imagev1.ImageImportStatus{Status: metav1.Status{Status: metav1.StatusSuccess}, Image: f.images[pullSpec]}
+	if status.Image == nil {
+		status.Status = metav1.Status{Status: metav1.StatusFailure, Message: "manifest unknown"}
+	}
+	imageStreamImport.Status.Images = []imagev1.ImageImportStatus{status}
+	result, err := runtime.DefaultUnstructuredConverter.ToUnstructured(imageStreamImport)
+	if err != nil {
+		return nil, err
+	}
+	return &unstructured.Unstructured{Object: result}, nil
+}
+
+func TestImageArchitectures(t *testing.T) {
+	client := &fakeImageStreamImports{images: map[string]*imagev1.Image{
+		"example.com/multi@sha256:1234": {DockerImageManifests: []imagev1.ImageManifest{
+			{OS: "linux", Architecture: "amd64"},
+			{OS: "linux", Architecture: "arm64"},
+			{OS: "windows", Architecture: "ppc64le"},
+		}},
+		"example.com/single:latest": {DockerImageMetadata: runtime.RawExtension{Raw: []byte(`{"architecture":"arm64"}`)}},
+	}}
+	now := time.Date(2026, 1, 31, 0, 0, 0, 0, time.UTC)
+	architectures := newImageArchitectures(client)
+	architectures.now = func() time.Time { return now }
+
+	tests := []struct {
+		pullSpec      string
+		expected      sets.Set[string]
+		expectedError bool
+	}{
+		{pullSpec: "example.com/multi@sha256:1234", expected: sets.New("amd64", "arm64")},
+		{pullSpec: "example.com/single:latest", expected: sets.New("arm64")},
+		{pullSpec: "example.com/missing:latest", expectedError: true},
+	}
+	for _, test := range tests {
+		t.Run(test.pullSpec, func(t *testing.T) {
+			actual, err := architectures.get(context.TODO(), test.pullSpec)
+			if test.expectedError != (err != nil) {
+				t.Fatalf("expected error %v, got %v", test.expectedError, err)
+			}
+			if !actual.Equal(test.expected) {
+				t.Errorf("expected %v, got %v", sets.List(test.expected), sets.List(actual))
+			}
+		})
+	}
+
+	// the images are inspected again once the tag and the error expired, the digest never
+	now = now.Add(imageInspectionRetry)
+	for _, pullSpec := range []string{"example.com/multi@sha256:1234", "example.com/single:latest", "example.com/missing:latest"} {
+		_, _ = architectures.get(context.TODO(), pullSpec)
+	}
+	if client.creates != 4 {
+		t.Errorf("expected 4 inspections after the retry, got %d", client.creates)
+	}
+	now = now.Add(imageInspectionTagExpiry)
+	for _, pullSpec := range []string{"example.com/multi@sha256:1234", "example.com/single:latest"} {
+		_, _ = architectures.get(context.TODO(), pullSpec)
+	}
+	if client.creates != 5 {
+		t.Errorf("expected 5 inspections after the tag expiry, got %d", client.creates)
+	}
+
+	if _, err := newImageArchitectures(nil).get(context.TODO(), "example.com/multi@sha256:1234"); err == nil {
+		t.Errorf("expected an error without the image API")
+	}
+}
@@ -0,0 +1,233 @@
+package targetconfigcontroller
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	corev1client "k8s.io/client-go/kubernetes/typed/core/v1"
+	corev1listers "k8s.io/client-go/listers/core/v1"
+
+	"github.com/openshift/library-go/pkg/operator/events"
+
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/operatorclient"
+)
+
+const (
+	// inputStatusConfigMapName holds the InputStatus of every configmap and secret the controller read.
+	inputStatusConfigMapName = "kube-controller-manager-target-config-inputs"
+	inputStatusKey           = "inputs.json"
+
+	// inputStatusRefresh bounds how stale the read times in the configmap get. It is written right away when an
+	// input appears, disappears or changes, the read times alone would rewrite it on every resync.
+	inputStatusRefresh = 10 * time.Minute
+	// inputStatusExpiry drops the inputs no sync read for that long, like the source of a removed extra mount. The
+	// syncers skipped as no-op syncs read nothing until noOpSyncExpiry.
+	inputStatusExpiry = 2 * noOpSyncExpiry
+)
+
+// InputStatus is a configmap or secret the TargetConfigController read during its syncs, from its informers or from
+// the kube-apiserver. The syncers also read the current state of the resources they write.
+type InputStatus struct {
+	// Resource is configmaps or secrets.
+	Resource  string `json:"resource"`
+	Namespace string `json:"namespace"`
+	Name      string `json:"name"`
+	// Found is false when the last read did not find the resource or failed.
+	Found           bool   `json:"found"`
+	ResourceVersion string `json:"resourceVersion,omitempty"`
+	// Error is the error of the last read, unless the resource was not found.
+	Error        string      `json:"error,omitempty"`
+	LastReadTime metav1.Time `json:"lastReadTime"`
+}
+
+// inputTracker records the reads of the syncers, they run concurrently.
+type inputTracker struct {
+	lock   sync.Mutex
+	inputs map[string]InputStatus
+	// published is what was last written to the inputStatusConfigMapName, without the read times
+	published   string
+	publishedAt time.Time
+	now         func() time.Time
+}
+
+func newInputTracker() *inputTracker {
+	return &inputTracker{inputs: map[string]InputStatus{}, now: time.Now}
+}
+
+// record records a read of the object. A nil tracker records nothing.
+func (t *inputTracker) record(resource, namespace, name string, obj metav1.Object, err error) {
+	if t == nil {
+		return
+	}
+	input := InputStatus{Resource: resource, Namespace: namespace, Name: name, LastReadTime: metav1.NewTime(t.now())}
+	switch {
+	case err == nil && obj != nil:
+		input.Found = true
+		input.ResourceVersion = obj.GetResourceVersion()
+	case err != nil && !apierrors.IsNotFound(err):
+		input.Error = err.Error()
+	}
+
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	t.inputs[resource+"/"+namespace+"/"+name] = input
+}
+
+// pending returns the inputs read within the inputStatusExpiry, sorted, and whether they need to be published.
+func (t *inputTracker) pending(now time.Time) ([]InputStatus, string, bool) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	var inputs []InputStatus
+	for key, input := range t.inputs {
+		if now.Sub(input.LastReadTime.Time) > inputStatusExpiry {
+			delete(t.inputs, key)
+			continue
+		}
+		inputs = append(inputs, input)
+	}
+	sort.Slice(inputs, func(i, j int) bool {
+		if inputs[i].Namespace != inputs[j].Namespace {
+			return inputs[i].Namespace < inputs[j].Namespace
+		}
+		if inputs[i].Resource != inputs[j].Resource {
+			return inputs[i].Resource < inputs[j].Resource
+		}
+		return inputs[i].Name < inputs[j].Name
+	})
+
+	var states []string
+	for _, input := range inputs {
+		states = append(states, fmt.Sprintf("%s/%s/%s=%v,%s,%s", input.Resource, input.Namespace, input.Name, input.Found, input.ResourceVersion, input.Error))
+	}
+	state := strings.Join(states, "\n")
+	return inputs, state, state != t.published || now.Sub(t.publishedAt) >= inputStatusRefresh
+}
+
+// markPublished remembers the state written to the inputStatusConfigMapName.
+func (t *inputTracker) markPublished(state string, now time.Time) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	t.published = state
+	t.publishedAt = now
+}
+
+// publishInputStatus writes the inputs the syncers read to the inputStatusConfigMapName configmap, which turns finding
+// a missing input into reading a configmap.
+func (c *TargetConfigController) publishInputStatus(ctx context.Context, client corev1client.ConfigMapsGetter, recorder events.Recorder, now time.Time) error {
+	if c.inputs == nil {
+		return nil
+	}
+	inputs, state, changed := c.inputs.pending(now)
+	if !changed {
+		return nil
+	}
+	if inputs == nil {
+		inputs = []InputStatus{}
+	}
+	inputsBytes, err := json.MarshalIndent(inputs, "", "  ")
+	if err != nil {
+		return err
+	}
+	if _, _, err := ApplyConfigMap(ctx, client, recorder, &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Namespace: operatorclient.OperatorNamespace, Name: inputStatusConfigMapName},
+		Data:       map[string]string{inputStatusKey: string(inputsBytes)},
+	}); err != nil {
+		return err
+	}
+	c.inputs.markPublished(state, now)
+	return nil
+}
+
+// inputRecordingConfigMapLister and inputRecordingSecretLister record the Gets of the syncers, their Lists only pick
+// the inputs they then get.
+type inputRecordingConfigMapLister struct {
+	corev1listers.ConfigMapLister
+	inputs *inputTracker
+}
+
+func (l *inputRecordingConfigMapLister) ConfigMaps(namespace string) corev1listers.ConfigMapNamespaceLister {
+	return &inputRecordingConfigMapNamespaceLister{ConfigMapNamespaceLister: l.ConfigMapLister.ConfigMaps(namespace), namespace: namespace, inputs: l.inputs}
+}
+
+type inputRecordingConfigMapNamespaceLister struct {
+	corev1listers.ConfigMapNamespaceLister
+	namespace string
+	inputs    *inputTracker
+}
+
+func (l *inputRecordingConfigMapNamespaceLister) Get(name string) (*corev1.ConfigMap, error) {
+	configMap, err := l.ConfigMapNamespaceLister.Get(name)
+	l.inputs.record("configmaps", l.namespace, name, configMap, err)
+	return configMap, err
+}
+
+type inputRecordingSecretLister struct {
+	corev1listers.SecretLister
+	inputs *inputTracker
+}
+
+func (l *inputRecordingSecretLister) Secrets(namespace string) corev1listers.SecretNamespaceLister {
+	return &inputRecordingSecretNamespaceLister{SecretNamespaceLister: l.SecretLister.Secrets(namespace), namespace: namespace, inputs: l.inputs}
+}
+
+type inputRecordingSecretNamespaceLister struct {
+	corev1listers.SecretNamespaceLister
+	namespace string
+	inputs    *inputTracker
+}
+
+func (l *inputRecordingSecretNamespaceLister) Get(name string) (*corev1.Secret, error) {
+	secret, err := l.SecretNamespaceLister.Get(name)
+	l.inputs.record("secrets", l.namespace, name, secret, err)
+	return secret, err
+}
+
+// inputRecordingClient records the configmaps and secrets the syncers get from the kube-apiserver.
+type inputRecordingClient struct {
+	corev1client.CoreV1Interface
+	inputs *inputTracker
+}
+
+func newInputRecordingClient(client corev1client.CoreV1Interface, inputs *inputTracker) *inputRecordingClient {
+	return &inputRecordingClient{CoreV1Interface: client, inputs: inputs}
+}
+
+func (c *inputRecordingClient) ConfigMaps(namespace string) corev1client.ConfigMapInterface {
+	return &inputRecordingConfigMaps{ConfigMapInterface: c.CoreV1Interface.ConfigMaps(namespace), namespace: namespace, inputs: c.inputs}
+}
+
+func (c *inputRecordingClient) Secrets(namespace string) corev1client.SecretInterface {
+	return &inputRecordingSecrets{SecretInterface: c.CoreV1Interface.Secrets(namespace), namespace: namespace, inputs: c.inputs}
+}
+
+type inputRecordingConfigMaps struct {
+	corev1client.ConfigMapInterface
+	namespace string
+	inputs    *inputTracker
+}
+
+func (c *inputRecordingConfigMaps) Get(ctx context.Context, name string, opts metav1.GetOptions) (*corev1.ConfigMap, error) {
+	configMap, err := c.ConfigMapInterface.Get(ctx, name, opts)
+	c.inputs.record("configmaps", c.namespace, name, configMap, err)
+	return configMap, err
+}
+
+type inputRecordingSecrets struct {
+	corev1client.SecretInterface
+	namespace string
+	inputs    *inputTracker
+}
+
+func (c *inputRecordingSecrets) Get(ctx context.Context, name string, opts metav1.GetOptions) (*corev1.Secret, error) {
+	secret, err := c.SecretInterface.Get(ctx, name, opts)
+	c.inputs.record("secrets", c.namespace, name, secret, err)
+	return secret, err
+}
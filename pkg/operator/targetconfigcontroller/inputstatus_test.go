@@ -0,0 +1,98 @@
+package targetconfigcontroller
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+	corev1listers "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/tools/cache"
+
+	"github.com/openshift/library-go/pkg/operator/events"
+
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/operatorclient"
+)
+
+func TestInputStatus(t *testing.T) {
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	inputs := newInputTracker()
+	inputs.now = func() time.Time { return now }
+
+	indexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{})
+	if err := indexer.Add(&corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Namespace: operatorclient.GlobalMachineSpecifiedConfigNamespace, Name: "kube-apiserver-server-ca", ResourceVersion: "7"}}); err != nil {
+		t.Fatal(err)
+	}
+	configMapLister := &inputRecordingConfigMapLister{ConfigMapLister: corev1listers.NewConfigMapLister(indexer), inputs: inputs}
+	kubeClient := fake.NewSimpleClientset(&corev1.Secret{ObjectMeta: metav1.ObjectMeta{Namespace: operatorclient.TargetNamespace, Name: "serving-cert", ResourceVersion: "3"}})
+	client := newInputRecordingClient(kubeClient.CoreV1(), inputs)
+
+	if _, err := configMapLister.ConfigMaps(operatorclient.GlobalMachineSpecifiedConfigNamespace).Get("kube-apiserver-server-ca"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := configMapLister.ConfigMaps(operatorclient.GlobalMachineSpecifiedConfigNamespace).Get("default-ingress-cert"); err == nil {
+		t.Fatal("expected default-ingress-cert not to be found")
+	}
+	if _, err := client.Secrets(operatorclient.TargetNamespace).Get(context.TODO(), "serving-cert", metav1.GetOptions{}); err != nil {
+		t.Fatal(err)
+	}
+
+	c := &TargetConfigController{inputs: inputs}
+	published := func() []InputStatus {
+		configMap, err := kubeClient.CoreV1().ConfigMaps(operatorclient.OperatorNamespace).Get(context.TODO(), inputStatusConfigMapName, metav1.GetOptions{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		var status []InputStatus
+		if err := json.Unmarshal([]byte(configMap.Data[inputStatusKey]), &status); err != nil {
+			t.Fatal(err)
+		}
+		return status
+	}
+	recorder := events.NewInMemoryRecorder("target-config-controller")
+	if err := c.publishInputStatus(context.TODO(), kubeClient.CoreV1(), recorder, now); err != nil {
+		t.Fatal(err)
+	}
+	expected := []InputStatus{
+		{Resource: "configmaps", Namespace: operatorclient.GlobalMachineSpecifiedConfigNamespace, Name: "default-ingress-cert", LastReadTime: metav1.NewTime(now)},
+		{Resource: "configmaps", Namespace: operatorclient.GlobalMachineSpecifiedConfigNamespace, Name: "kube-apiserver-server-ca", Found: true, ResourceVersion: "7", LastReadTime: metav1.NewTime(now)},
+		{Resource: "secrets", Namespace: operatorclient.TargetNamespace, Name: "serving-cert", Found: true, ResourceVersion: "3", LastReadTime: metav1.NewTime(now)},
+	}
+	status := published()
+	if len(status) != len(expected) {
+		t.Fatalf("expected %d inputs, got %#v", len(expected), status)
+	}
+	for i := range expected {
+		if !status[i].LastReadTime.Equal(&expected[i].LastReadTime) {
+			t.Errorf("expected %#v, got %#v", expected[i], status[i])
+		}
+		status[i].LastReadTime, expected[i].LastReadTime = metav1.Time{}, metav1.Time{}
+		if status[i] != expected[i] {
+			t.Errorf("expected %#v, got %#v", expected[i], status[i])
+		}
+	}
+
+	// a read that changes nothing is only published with the inputStatusRefresh
+	now = now.Add(time.Minute)
+	if _, err := client.Secrets(operatorclient.TargetNamespace).Get(context.TODO(), "serving-cert", metav1.GetOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	if _, _, changed := inputs.pending(now); changed {
+		t.Errorf("expected an unchanged read not to be published")
+	}
+
+	// the inputs nobody read within the inputStatusExpiry are dropped
+	now = now.Add(inputStatusExpiry)
+	if _, err := client.Secrets(operatorclient.TargetNamespace).Get(context.TODO(), "serving-cert", metav1.GetOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.publishInputStatus(context.TODO(), kubeClient.CoreV1(), recorder, now); err != nil {
+		t.Fatal(err)
+	}
+	if status := published(); len(status) != 1 || status[0].Name != "serving-cert" || !status[0].LastReadTime.Time.Equal(now) {
+		t.Errorf("expected only serving-cert, got %#v", status)
+	}
+}
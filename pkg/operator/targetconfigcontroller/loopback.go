@@ -0,0 +1,84 @@
+package targetconfigcontroller
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"strings"
+
+	"github.com/ghodss/yaml"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	corev1client "k8s.io/client-go/kubernetes/typed/core/v1"
+
+	operatorv1 "github.com/openshift/api/operator/v1"
+	"github.com/openshift/library-go/pkg/controller/factory"
+	"github.com/openshift/library-go/pkg/operator/resource/resourceread"
+
+	"github.com/openshift/cluster-kube-controller-manager-operator/bindata"
+)
+
+const (
+	// recoveryKubeconfigServer is the kube-apiserver endpoint of the cert-syncer kubeconfig asset, which the
+	// localhost-recovery serving cert of the kube-apiserver is valid for through the tls-server-name.
+	recoveryKubeconfigServer = "https://localhost:6443"
+	kubeAPIServerPort        = "6443"
+
+	ipv4RecoveryControllerListen = "--listen=0.0.0.0:9443"
+	ipv6RecoveryControllerListen = "--listen=[::]:9443"
+)
+
+// isIPv6Primary tells whether the first service network of the observed config is an IPv6 one. The loopback
+// endpoints of the operand are then rendered with ::1, a host of such a cluster may resolve localhost to 127.0.0.1
+// without serving the kube-apiserver there.
+func isIPv6Primary(observedConfig []byte) (bool, error) {
+	if len(observedConfig) == 0 {
+		return false, nil
+	}
+	config := map[string]interface{}{}
+	if err := yaml.Unmarshal(observedConfig, &config); err != nil {
+		return false, fmt.Errorf("failed to parse the observed config: %v", err)
+	}
+	serviceNetworks, _, err := unstructured.NestedStringSlice(config, "extendedArguments", "service-cluster-ip-range")
+	if err != nil || len(serviceNetworks) == 0 {
+		return false, err
+	}
+	// the flag holds the networks of both families in one comma separated value on dual stack clusters
+	ip, _, err := net.ParseCIDR(strings.Split(serviceNetworks[0], ",")[0])
+	if err != nil {
+		return false, fmt.Errorf("invalid service network %q: %v", serviceNetworks[0], err)
+	}
+	return ip.To4() == nil, nil
+}
+
+// applyIPv6Loopback points the probes of the pod at ::1 and makes the recovery controller listen on the IPv6
+// wildcard address.
+func applyIPv6Loopback(pod *corev1.Pod) {
+	for i := range pod.Spec.Containers {
+		container := &pod.Spec.Containers[i]
+		for _, probe := range []*corev1.Probe{container.StartupProbe, container.LivenessProbe, container.ReadinessProbe} {
+			if probe != nil && probe.HTTPGet != nil {
+				probe.HTTPGet.Host = net.IPv6loopback.String()
+			}
+		}
+		for j := range container.Args {
+			container.Args[j] = strings.ReplaceAll(container.Args[j], ipv4RecoveryControllerListen, ipv6RecoveryControllerListen)
+		}
+	}
+}
+
+// syncCertSyncerKubeconfig renders the kubeconfig the cert-syncer and the recovery controller reach the local
+// kube-apiserver with.
+func (c *TargetConfigController) syncCertSyncerKubeconfig(ctx context.Context, syncCtx factory.SyncContext, client corev1client.CoreV1Interface, operatorSpec *operatorv1.StaticPodOperatorSpec) error {
+	ipv6Primary, err := isIPv6Primary(operatorSpec.ObservedConfig.Raw)
+	if err != nil {
+		return err
+	}
+	required := resourceread.ReadConfigMapV1OrDie(bindata.MustAsset("assets/kube-controller-manager/kubeconfig-cert-syncer.yaml"))
+	if ipv6Primary {
+		server := "https://" + net.JoinHostPort(net.IPv6loopback.String(), kubeAPIServerPort)
+		required.Data["kubeconfig"] = strings.ReplaceAll(required.Data["kubeconfig"], recoveryKubeconfigServer, server)
+	}
+	_, _, err = ApplyConfigMap(ctx, client, syncCtx.Recorder(), required)
+	return err
+}
@@ -0,0 +1,63 @@
+package targetconfigcontroller
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/openshift/library-go/pkg/operator/resource/resourceread"
+
+	"github.com/openshift/cluster-kube-controller-manager-operator/bindata"
+)
+
+func TestIsIPv6Primary(t *testing.T) {
+	tests := []struct {
+		name           string
+		observedConfig string
+		expected       bool
+	}{
+		{name: "no observed config"},
+		{name: "no service network", observedConfig: `{"extendedArguments":{}}`},
+		{name: "IPv4", observedConfig: `{"extendedArguments":{"service-cluster-ip-range":["172.30.0.0/16"]}}`},
+		{name: "IPv6", observedConfig: `{"extendedArguments":{"service-cluster-ip-range":["fd02::/112"]}}`, expected: true},
+		{name: "dual stack IPv4 primary", observedConfig: `{"extendedArguments":{"service-cluster-ip-range":["172.30.0.0/16,fd02::/112"]}}`},
+		{name: "dual stack IPv6 primary", observedConfig: `{"extendedArguments":{"service-cluster-ip-range":["fd02::/112,172.30.0.0/16"]}}`, expected: true},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			actual, err := isIPv6Primary([]byte(test.observedConfig))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if actual != test.expected {
+				t.Errorf("expected %v, got %v", test.expected, actual)
+			}
+		})
+	}
+
+	if _, err := isIPv6Primary([]byte(`{"extendedArguments":{"service-cluster-ip-range":["invalid"]}}`)); err == nil {
+		t.Errorf("expected an error for an invalid service network")
+	}
+}
+
+func TestApplyIPv6Loopback(t *testing.T) {
+	pod := resourceread.ReadPodV1OrDie(bindata.MustAsset("assets/kube-controller-manager/pod.yaml"))
+	applyIPv6Loopback(pod)
+
+	listensOnIPv6 := false
+	for _, container := range pod.Spec.Containers {
+		if container.LivenessProbe != nil && container.LivenessProbe.HTTPGet.Host != "::1" {
+			t.Errorf("expected the liveness probe of %s to target ::1, got %q", container.Name, container.LivenessProbe.HTTPGet.Host)
+		}
+		for _, arg := range container.Args {
+			if strings.Contains(arg, ipv4RecoveryControllerListen) {
+				t.Errorf("expected %s to not listen on IPv4 only: %s", container.Name, arg)
+			}
+			if strings.Contains(arg, ipv6RecoveryControllerListen) {
+				listensOnIPv6 = true
+			}
+		}
+	}
+	if !listensOnIPv6 {
+		t.Errorf("expected the recovery controller to listen on [::]")
+	}
+}
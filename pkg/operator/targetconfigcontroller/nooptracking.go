@@ -0,0 +1,137 @@
+package targetconfigcontroller
+
+import (
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/sets"
+
+	operatorv1 "github.com/openshift/api/operator/v1"
+
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/operatorclient"
+)
+
+// noOpSyncExpiry bounds how long a syncer is skipped for unchanged inputs. The syncers also read a few things the
+// input fingerprint does not cover, like live GETs of the outputs, which are picked up once it expires.
+const noOpSyncExpiry = 10 * time.Minute
+
+// clockDependentSyncers are never skipped, their output changes with time alone: the pod leaves out an expired
+// serving-cert and re-inspects the operand images, the csr-signer rotates by the expiry of the signer and reports the
+// clock skew, and the trusted-ca-bundle warns about expiring CAs.
+var clockDependentSyncers = sets.New("pod", "csr-signer", "trusted-ca-bundle")
+
+// fingerprintNamespaces are the namespaces whose configmaps and secrets the controller watches. The outputs in the
+// TargetNamespace are covered as well, a change to them is synced like any input change.
+var fingerprintNamespaces = []string{
+	operatorclient.TargetNamespace,
+	operatorclient.OperatorNamespace,
+	operatorclient.GlobalUserSpecifiedConfigNamespace,
+	operatorclient.GlobalMachineSpecifiedConfigNamespace,
+}
+
+// syncFingerprint is the input fingerprint of the last successful sync of a syncer.
+type syncFingerprint struct {
+	hash string
+	at   time.Time
+}
+
+// inputFingerprint hashes the operator spec, the latest revision, the resource versions of the watched configmaps,
+// secrets, including the secrets of the extra mounts, and service accounts, and the architectures of the control plane
+// nodes. The informers of the controller trigger every syncer on any of their events and on resync, a syncer with the
+// same fingerprint as on its last successful sync has nothing to do.
+func (c *TargetConfigController) inputFingerprint(operatorSpec *operatorv1.StaticPodOperatorSpec, operatorStatus *operatorv1.StaticPodOperatorStatus) (string, error) {
+	hash := sha256.New()
+	spec, err := json.Marshal(operatorSpec)
+	if err != nil {
+		return "", err
+	}
+	hash.Write(spec)
+	fmt.Fprintf(hash, "\nlatestAvailableRevision=%d\n", operatorStatus.LatestAvailableRevision)
+
+	for _, namespace := range fingerprintNamespaces {
+		configMaps, err := c.configMapLister.ConfigMaps(namespace).List(labels.Everything())
+		if err != nil {
+			return "", err
+		}
+		secrets, err := c.secretLister.Secrets(namespace).List(labels.Everything())
+		if err != nil {
+			return "", err
+		}
+		versions := map[string]string{}
+		for _, configMap := range configMaps {
+			versions["configmap/"+configMap.Name] = configMap.ResourceVersion
+		}
+		for _, secret := range secrets {
+			versions["secret/"+secret.Name] = secret.ResourceVersion
+		}
+		if namespace == operatorclient.TargetNamespace && c.serviceAccountLister != nil {
+			serviceAccounts, err := c.serviceAccountLister.ServiceAccounts(namespace).List(labels.Everything())
+			if err != nil {
+				return "", err
+			}
+			for _, serviceAccount := range serviceAccounts {
+				versions["serviceaccount/"+serviceAccount.Name] = serviceAccount.ResourceVersion
+			}
+		}
+		// json sorts the keys of maps
+		versionsJSON, err := json.Marshal(versions)
+		if err != nil {
+			return "", err
+		}
+		fmt.Fprintf(hash, "%s=%s\n", namespace, versionsJSON)
+	}
+	// the secrets of the extra mounts are watched apart from the other secrets of GlobalUserSpecifiedConfigNamespace
+	extraMountSecretsJSON, err := json.Marshal(c.extraMountSecrets.resourceVersions())
+	if err != nil {
+		return "", err
+	}
+	fmt.Fprintf(hash, "extraMountSecrets=%s\n", extraMountSecretsJSON)
+
+	// the resource versions of the nodes change with every heartbeat, only the architectures pick the operand images
+	architectures := map[string]string{}
+	if c.nodeLister != nil {
+		nodes, err := c.nodeLister.List(labels.SelectorFromSet(labels.Set{"node-role.kubernetes.io/master": ""}))
+		if err != nil {
+			return "", err
+		}
+		for _, node := range nodes {
+			architectures[node.Name] = node.Status.NodeInfo.Architecture
+		}
+	}
+	architecturesJSON, err := json.Marshal(architectures)
+	if err != nil {
+		return "", err
+	}
+	fmt.Fprintf(hash, "controlPlaneArchitectures=%s\n", architecturesJSON)
+	return fmt.Sprintf("%x", hash.Sum(nil)), nil
+}
+
+// isNoOpSync tells whether the last successful sync of the syncer had the same input fingerprint and did not expire.
+func (c *TargetConfigController) isNoOpSync(name, fingerprint string, now time.Time) bool {
+	if clockDependentSyncers.Has(name) {
+		return false
+	}
+	c.syncFingerprintsLock.Lock()
+	defer c.syncFingerprintsLock.Unlock()
+	last, ok := c.syncFingerprints[name]
+	return ok && last.hash == fingerprint && now.Sub(last.at) < noOpSyncExpiry
+}
+
+// recordSyncFingerprint remembers the input fingerprint of a successful sync. A failed sync, or one that scheduled
+// another sync for later, is forgotten so the next sync runs in full.
+func (c *TargetConfigController) recordSyncFingerprint(name, fingerprint string, syncErr error, now time.Time) {
+	c.scheduledSyncsLock.Lock()
+	_, scheduled := c.scheduledSyncs[name]
+	c.scheduledSyncsLock.Unlock()
+
+	c.syncFingerprintsLock.Lock()
+	defer c.syncFingerprintsLock.Unlock()
+	if syncErr != nil || scheduled {
+		delete(c.syncFingerprints, name)
+		return
+	}
+	c.syncFingerprints[name] = syncFingerprint{hash: fingerprint, at: now}
+}
@@ -0,0 +1,114 @@
+package targetconfigcontroller
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	corev1listers "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/tools/cache"
+
+	operatorv1 "github.com/openshift/api/operator/v1"
+
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/operatorclient"
+)
+
+func TestNoOpSync(t *testing.T) {
+	configMaps := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc})
+	secrets := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc})
+	nodes := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{})
+	c := &TargetConfigController{
+		configMapLister:  corev1listers.NewConfigMapLister(configMaps),
+		secretLister:     corev1listers.NewSecretLister(secrets),
+		nodeLister:       corev1listers.NewNodeLister(nodes),
+		scheduledSyncs:   map[string]scheduledSync{},
+		syncFingerprints: map[string]syncFingerprint{},
+	}
+	spec := &operatorv1.StaticPodOperatorSpec{}
+	status := &operatorv1.StaticPodOperatorStatus{}
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	configMap := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Namespace: operatorclient.TargetNamespace, Name: "config", ResourceVersion: "1"}}
+	if err := configMaps.Add(configMap); err != nil {
+		t.Fatal(err)
+	}
+	fingerprint := func() string {
+		actual, err := c.inputFingerprint(spec, status)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return actual
+	}
+
+	first := fingerprint()
+	if c.isNoOpSync("config", first, now) {
+		t.Fatalf("expected the first sync to run")
+	}
+	c.recordSyncFingerprint("config", first, nil, now)
+	if !c.isNoOpSync("config", fingerprint(), now.Add(time.Minute)) {
+		t.Errorf("expected an unchanged sync to be skipped")
+	}
+	if c.isNoOpSync("config", fingerprint(), now.Add(noOpSyncExpiry)) {
+		t.Errorf("expected the fingerprint to expire")
+	}
+
+	// a changed output, spec or revision runs the sync again
+	configMap = configMap.DeepCopy()
+	configMap.ResourceVersion = "2"
+	if err := configMaps.Update(configMap); err != nil {
+		t.Fatal(err)
+	}
+	if c.isNoOpSync("config", fingerprint(), now.Add(time.Minute)) {
+		t.Errorf("expected a changed configmap to be synced")
+	}
+	c.recordSyncFingerprint("config", fingerprint(), nil, now)
+	spec.LogLevel = operatorv1.Debug
+	if c.isNoOpSync("config", fingerprint(), now.Add(time.Minute)) {
+		t.Errorf("expected a changed spec to be synced")
+	}
+	c.recordSyncFingerprint("config", fingerprint(), nil, now)
+	status.LatestAvailableRevision = 2
+	if c.isNoOpSync("config", fingerprint(), now.Add(time.Minute)) {
+		t.Errorf("expected a new revision to be synced")
+	}
+
+	// a control plane node of another architecture runs the sync again, a heartbeat does not
+	node := &corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{Name: "master-0", Labels: map[string]string{"node-role.kubernetes.io/master": ""}, ResourceVersion: "1"},
+		Status:     corev1.NodeStatus{NodeInfo: corev1.NodeSystemInfo{Architecture: "amd64"}},
+	}
+	if err := nodes.Add(node); err != nil {
+		t.Fatal(err)
+	}
+	if c.isNoOpSync("config", fingerprint(), now.Add(time.Minute)) {
+		t.Errorf("expected a new control plane architecture to be synced")
+	}
+	c.recordSyncFingerprint("config", fingerprint(), nil, now)
+	node = node.DeepCopy()
+	node.ResourceVersion = "2"
+	if err := nodes.Update(node); err != nil {
+		t.Fatal(err)
+	}
+	if !c.isNoOpSync("config", fingerprint(), now.Add(time.Minute)) {
+		t.Errorf("expected a node heartbeat to be skipped")
+	}
+
+	// the syncers depending on the clock are never skipped
+	c.recordSyncFingerprint("csr-signer", fingerprint(), nil, now)
+	if c.isNoOpSync("csr-signer", fingerprint(), now.Add(time.Minute)) {
+		t.Errorf("expected the csr-signer to be synced")
+	}
+
+	// failed and rescheduled syncs are not skipped
+	c.recordSyncFingerprint("config", fingerprint(), fmt.Errorf("failed"), now)
+	if c.isNoOpSync("config", fingerprint(), now.Add(time.Minute)) {
+		t.Errorf("expected a failed sync to run again")
+	}
+	c.scheduledSyncs["config"] = scheduledSync{at: now.Add(time.Minute)}
+	c.recordSyncFingerprint("config", fingerprint(), nil, now)
+	if c.isNoOpSync("config", fingerprint(), now.Add(time.Minute)) {
+		t.Errorf("expected a rescheduled sync to run again")
+	}
+}
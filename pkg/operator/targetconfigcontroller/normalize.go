@@ -0,0 +1,85 @@
+package targetconfigcontroller
+
+import (
+	"context"
+
+	"github.com/ghodss/yaml"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	corev1client "k8s.io/client-go/kubernetes/typed/core/v1"
+	"k8s.io/component-base/metrics"
+	"k8s.io/component-base/metrics/legacyregistry"
+
+	"github.com/openshift/library-go/pkg/operator/events"
+)
+
+var renderedConfigMapUpdates = metrics.NewCounterVec(
+	&metrics.CounterOpts{
+		Name:           "kube_controller_manager_operator_rendered_configmap_updates_total",
+		Help:           "Number of changes to the configmaps rendered into the target namespace, by configmap and whether the update was performed or avoided because the content was semantically equal.",
+		StabilityLevel: metrics.ALPHA,
+	},
+	[]string{"configmap", "result"},
+)
+
+func init() {
+	legacyregistry.MustRegister(renderedConfigMapUpdates)
+}
+
+// applyRenderedConfigMap applies a configmap rendered from the operator assets. A key whose rendered content is
+// semantically equal to the existing one, e.g. the same YAML with the keys in another order, keeps the existing
+// content. Every change of a revisioned configmap rolls out a new revision, a formatting difference must not.
+// The configmap is written with server-side apply, see FieldManager.
+func applyRenderedConfigMap(ctx context.Context, client corev1client.ConfigMapsGetter, recorder events.Recorder, required *corev1.ConfigMap) (*corev1.ConfigMap, bool, error) {
+	existing, err := client.ConfigMaps(required.Namespace).Get(ctx, required.Name, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		existing = nil
+	} else if err != nil {
+		return nil, false, err
+	}
+
+	avoided := false
+	if existing != nil {
+		required = required.DeepCopy()
+		for key, value := range required.Data {
+			existingValue, ok := existing.Data[key]
+			if !ok || existingValue == value {
+				continue
+			}
+			if semanticallyEqual(existingValue, value) {
+				required.Data[key] = existingValue
+				avoided = true
+			}
+		}
+	}
+
+	actual, modified, err := serverSideApplyConfigMap(ctx, client, recorder, required, existing)
+	if err != nil {
+		return nil, false, err
+	}
+	switch {
+	case modified:
+		renderedConfigMapUpdates.WithLabelValues(required.Name, "performed").Inc()
+	case avoided:
+		renderedConfigMapUpdates.WithLabelValues(required.Name, "avoided").Inc()
+	}
+	return actual, modified, nil
+}
+
+// semanticallyEqual compares two YAML or JSON documents. Other content, including plain strings, is only equal when
+// identical.
+func semanticallyEqual(a, b string) bool {
+	var aObj, bObj interface{}
+	if err := yaml.Unmarshal([]byte(a), &aObj); err != nil {
+		return false
+	}
+	if err := yaml.Unmarshal([]byte(b), &bObj); err != nil {
+		return false
+	}
+	if _, ok := aObj.(map[string]interface{}); !ok {
+		return false
+	}
+	return equality.Semantic.DeepEqual(aObj, bObj)
+}
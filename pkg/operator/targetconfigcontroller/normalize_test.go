@@ -0,0 +1,79 @@
+package targetconfigcontroller
+
+import (
+	"context"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+	"k8s.io/component-base/metrics/testutil"
+
+	"github.com/openshift/library-go/pkg/operator/events"
+
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/operatorclient"
+)
+
+func TestApplyRenderedConfigMap(t *testing.T) {
+	existing := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Namespace: operatorclient.TargetNamespace, Name: "config"},
+		Data: map[string]string{
+			"config.yaml": `{"apiVersion":"v1","extendedArguments":{"a":["1"],"b":["2"]}}`,
+			"version":     "1",
+		},
+	}
+	kubeClient := fake.NewSimpleClientset(existing)
+	recorder := events.NewInMemoryRecorder("normalize")
+	counter := func(result string) float64 {
+		value, err := testutil.GetCounterMetricValue(renderedConfigMapUpdates.WithLabelValues("config", result))
+		if err != nil {
+			t.Fatal(err)
+		}
+		return value
+	}
+	initialAvoided, initialPerformed := counter("avoided"), counter("performed")
+
+	// the same config rendered as YAML with the keys in another order
+	reordered := existing.DeepCopy()
+	reordered.Data["config.yaml"] = "extendedArguments:\n  b: [\"2\"]\n  a: [\"1\"]\napiVersion: v1\n"
+	if _, modified, err := applyRenderedConfigMap(context.TODO(), kubeClient.CoreV1(), recorder, reordered); err != nil || modified {
+		t.Fatalf("expected no update, got modified %v, err %v", modified, err)
+	}
+	if counter("avoided")-initialAvoided != 1 {
+		t.Errorf("expected an avoided update")
+	}
+
+	changed := existing.DeepCopy()
+	changed.Data["config.yaml"] = `{"apiVersion":"v1","extendedArguments":{"a":["1"],"b":["3"]}}`
+	changed.Data["version"] = "2"
+	if _, modified, err := applyRenderedConfigMap(context.TODO(), kubeClient.CoreV1(), recorder, changed); err != nil || !modified {
+		t.Fatalf("expected an update, got modified %v, err %v", modified, err)
+	}
+	if counter("performed")-initialPerformed != 1 {
+		t.Errorf("expected a performed update")
+	}
+	actual, err := kubeClient.CoreV1().ConfigMaps(operatorclient.TargetNamespace).Get(context.TODO(), "config", metav1.GetOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if actual.Data["config.yaml"] != changed.Data["config.yaml"] || actual.Data["version"] != "2" {
+		t.Errorf("unexpected data %v", actual.Data)
+	}
+}
+
+func TestSemanticallyEqual(t *testing.T) {
+	for _, test := range []struct {
+		a, b     string
+		expected bool
+	}{
+		{a: `{"a":1,"b":2}`, b: "b: 2\na: 1\n", expected: true},
+		{a: `{"a":1}`, b: `{"a":"1"}`},
+		{a: "1", b: "1.0"},
+		{a: "true", b: "True"},
+		{a: "not: [valid", b: "not: [valid"},
+	} {
+		if actual := semanticallyEqual(test.a, test.b); actual != test.expected {
+			t.Errorf("expected semanticallyEqual(%q, %q) to be %v", test.a, test.b, test.expected)
+		}
+	}
+}
@@ -0,0 +1,85 @@
+package targetconfigcontroller
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/ghodss/yaml"
+
+	operatorv1 "github.com/openshift/api/operator/v1"
+	"github.com/openshift/library-go/pkg/operator/v1helpers"
+)
+
+// overrideConflictCondition lists the observed config the unsupportedConfigOverrides replace. The overrides are
+// merged last and win silently, which leaves the admin guessing why a cluster setting like the cluster network has
+// no effect on the kube-controller-manager.
+const overrideConflictCondition = "OverrideConflict"
+
+// updateOverrideConflictCondition reports the observed config paths the unsupportedConfigOverrides set to a
+// different value.
+func (c *TargetConfigController) updateOverrideConflictCondition(ctx context.Context, operatorSpec *operatorv1.StaticPodOperatorSpec) error {
+	conflicts, err := overrideConflicts(operatorSpec.ObservedConfig.Raw, operatorSpec.UnsupportedConfigOverrides.Raw)
+	if err != nil {
+		return err
+	}
+	_, _, err = v1helpers.UpdateStaticPodStatus(ctx, c.operatorClient, v1helpers.UpdateStaticPodConditionFn(overrideConflictConditionFor(conflicts)))
+	return err
+}
+
+// overrideConflicts returns the dotted paths of the observed config the overrides set to a different value, sorted.
+// An override replacing a whole object of the observed config conflicts at the path of the object.
+func overrideConflicts(observedConfig, overrides []byte) ([]string, error) {
+	if len(observedConfig) == 0 || len(overrides) == 0 {
+		return nil, nil
+	}
+	observed := map[string]interface{}{}
+	if err := yaml.Unmarshal(observedConfig, &observed); err != nil {
+		return nil, fmt.Errorf("failed to parse the observed config: %v", err)
+	}
+	overridden := map[string]interface{}{}
+	if err := yaml.Unmarshal(overrides, &overridden); err != nil {
+		return nil, fmt.Errorf("failed to parse the unsupportedConfigOverrides: %v", err)
+	}
+
+	var conflicts []string
+	collectOverrideConflicts(nil, observed, overridden, &conflicts)
+	sort.Strings(conflicts)
+	return conflicts, nil
+}
+
+func collectOverrideConflicts(path []string, observed, overridden map[string]interface{}, conflicts *[]string) {
+	for key, observedValue := range observed {
+		overriddenValue, ok := overridden[key]
+		if !ok {
+			continue
+		}
+		keyPath := append(append([]string{}, path...), key)
+		observedMap, observedIsMap := observedValue.(map[string]interface{})
+		overriddenMap, overriddenIsMap := overriddenValue.(map[string]interface{})
+		if observedIsMap && overriddenIsMap {
+			collectOverrideConflicts(keyPath, observedMap, overriddenMap, conflicts)
+			continue
+		}
+		if !reflect.DeepEqual(observedValue, overriddenValue) {
+			*conflicts = append(*conflicts, strings.Join(keyPath, "."))
+		}
+	}
+}
+
+func overrideConflictConditionFor(conflicts []string) operatorv1.OperatorCondition {
+	if len(conflicts) == 0 {
+		return operatorv1.OperatorCondition{
+			Type:   overrideConflictCondition,
+			Status: operatorv1.ConditionFalse,
+		}
+	}
+	return operatorv1.OperatorCondition{
+		Type:    overrideConflictCondition,
+		Status:  operatorv1.ConditionTrue,
+		Reason:  "ObservedConfigOverridden",
+		Message: fmt.Sprintf("unsupportedConfigOverrides replace the observed %s", strings.Join(conflicts, ", ")),
+	}
+}
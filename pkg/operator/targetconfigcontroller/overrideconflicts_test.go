@@ -0,0 +1,49 @@
+package targetconfigcontroller
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestOverrideConflicts(t *testing.T) {
+	observedConfig := `{"extendedArguments":{"cluster-cidr":["10.128.0.0/14"],"cluster-name":["example"]},"servingInfo":{"minTLSVersion":"VersionTLS12"}}`
+
+	tests := []struct {
+		name      string
+		overrides string
+		expected  []string
+	}{
+		{
+			name: "no overrides",
+		},
+		{
+			name:      "unrelated overrides",
+			overrides: `{"extendedArguments":{"v":["4"]},"EnableDeprecatedAndRemovedServiceCAKeyUntilNextRelease_ThisMakesClusterImpossibleToUpgrade":true}`,
+		},
+		{
+			name:      "same value",
+			overrides: `{"extendedArguments":{"cluster-name":["example"]}}`,
+		},
+		{
+			name:      "overridden flags",
+			overrides: `{"extendedArguments":{"cluster-name":["other"],"cluster-cidr":["10.0.0.0/16"]}}`,
+			expected:  []string{"extendedArguments.cluster-cidr", "extendedArguments.cluster-name"},
+		},
+		{
+			name:      "replaced object",
+			overrides: "servingInfo: null\n",
+			expected:  []string{"servingInfo"},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			actual, err := overrideConflicts([]byte(observedConfig), []byte(test.overrides))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(test.expected, actual) {
+				t.Errorf("expected %v, got %v", test.expected, actual)
+			}
+		})
+	}
+}
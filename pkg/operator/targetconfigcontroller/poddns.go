@@ -0,0 +1,21 @@
+package targetconfigcontroller
+
+import (
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/tuning"
+)
+
+// applyPodDNS adds the host aliases and the DNS settings of the tuning config to the pod. Unset, the pod keeps resolving
+// names like its host, the pod manifest sets neither.
+func applyPodDNS(pod *corev1.Pod, podDNS tuning.PodDNSConfig) {
+	for _, alias := range podDNS.HostAliases {
+		pod.Spec.HostAliases = append(pod.Spec.HostAliases, *alias.DeepCopy())
+	}
+	if len(podDNS.DNSPolicy) > 0 {
+		pod.Spec.DNSPolicy = podDNS.DNSPolicy
+	}
+	if podDNS.DNSConfig != nil {
+		pod.Spec.DNSConfig = podDNS.DNSConfig.DeepCopy()
+	}
+}
@@ -0,0 +1,73 @@
+package targetconfigcontroller
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/kubernetes/fake"
+
+	operatorv1 "github.com/openshift/api/operator/v1"
+	"github.com/openshift/library-go/pkg/operator/events"
+	"github.com/openshift/library-go/pkg/operator/resource/resourceread"
+
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/tuning"
+)
+
+func TestManagePodDNS(t *testing.T) {
+	podDNS := tuning.PodDNSConfig{
+		HostAliases: []corev1.HostAlias{{IP: "192.168.111.5", Hostnames: []string{"api-int.example.com"}}},
+		DNSPolicy:   corev1.DNSNone,
+		DNSConfig:   &corev1.PodDNSConfig{Nameservers: []string{"192.168.111.1"}, Searches: []string{"example.com"}},
+	}
+	if err := (&tuning.Config{PodDNS: podDNS}).Validate(); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, test := range []struct {
+		name   string
+		podDNS tuning.PodDNSConfig
+	}{
+		{name: "host resolution"},
+		{name: "custom resolution", podDNS: podDNS},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			kubeClient := fake.NewSimpleClientset()
+			operatorSpec := &operatorv1.StaticPodOperatorSpec{}
+			operatorSpec.ObservedConfig.Raw = []byte(`{}`)
+
+			cm, _, err := managePod(context.TODO(), nil, kubeClient.CoreV1(), kubeClient.CoreV1(), events.NewInMemoryRecorder("target-config"), operatorSpec, &tuning.Config{PodDNS: test.podDNS}, "kcm", "operator", "cpc", false, true)
+			if err != nil {
+				t.Fatal(err)
+			}
+			pod := resourceread.ReadPodV1OrDie([]byte(cm.Data["pod.yaml"]))
+			if !reflect.DeepEqual(pod.Spec.HostAliases, test.podDNS.HostAliases) {
+				t.Errorf("expected host aliases %v, got %v", test.podDNS.HostAliases, pod.Spec.HostAliases)
+			}
+			if pod.Spec.DNSPolicy != test.podDNS.DNSPolicy {
+				t.Errorf("expected dns policy %q, got %q", test.podDNS.DNSPolicy, pod.Spec.DNSPolicy)
+			}
+			if !reflect.DeepEqual(pod.Spec.DNSConfig, test.podDNS.DNSConfig) {
+				t.Errorf("expected dns config %v, got %v", test.podDNS.DNSConfig, pod.Spec.DNSConfig)
+			}
+		})
+	}
+}
+
+func TestValidatePodDNS(t *testing.T) {
+	for name, podDNS := range map[string]tuning.PodDNSConfig{
+		"invalid ip":         {HostAliases: []corev1.HostAlias{{IP: "api", Hostnames: []string{"api.example.com"}}}},
+		"no hostnames":       {HostAliases: []corev1.HostAlias{{IP: "192.168.111.5"}}},
+		"invalid hostname":   {HostAliases: []corev1.HostAlias{{IP: "192.168.111.5", Hostnames: []string{"API_VIP"}}}},
+		"cluster dns":        {DNSPolicy: corev1.DNSClusterFirstWithHostNet},
+		"none without dns":   {DNSPolicy: corev1.DNSNone},
+		"too many servers":   {DNSConfig: &corev1.PodDNSConfig{Nameservers: []string{"10.0.0.1", "10.0.0.2", "10.0.0.3", "10.0.0.4"}}},
+		"invalid nameserver": {DNSConfig: &corev1.PodDNSConfig{Nameservers: []string{"dns.example.com"}}},
+		"too many aliases":   {HostAliases: make([]corev1.HostAlias, tuning.MaxHostAliases+1)},
+	} {
+		if err := (&tuning.Config{PodDNS: podDNS}).Validate(); err == nil {
+			t.Errorf("%s: expected %v to be rejected", name, podDNS)
+		}
+	}
+}
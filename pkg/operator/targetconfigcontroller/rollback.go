@@ -0,0 +1,92 @@
+package targetconfigcontroller
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/util/sets"
+	corev1client "k8s.io/client-go/kubernetes/typed/core/v1"
+	"k8s.io/client-go/tools/cache"
+
+	operatorv1 "github.com/openshift/api/operator/v1"
+	"github.com/openshift/library-go/pkg/operator/events"
+	"github.com/openshift/library-go/pkg/operator/v1helpers"
+)
+
+// RollbackAnnotation on the kubecontrollermanager/cluster resource names a previous revision whose rendered
+// resources are restored and rolled out as a new revision. The syncers of the restored resources are held while the
+// rollback is in effect, removing the annotation resumes rendering them from the current inputs.
+const RollbackAnnotation = "kubecontrollermanager.operator.openshift.io/rollback-to-revision"
+
+// RevisionRollbackCondition is True while the RollbackController keeps the restored resources of the revision named by
+// the RollbackAnnotation. A rejected revision leaves it False and the resources are rendered as usual.
+const RevisionRollbackCondition = "RevisionRollback"
+
+// RollbackConfigMaps and RollbackSecrets are the revisioned resources rendered by this controller, which a rollback
+// restores. The certificates, keys and kubeconfigs written by the other controllers keep their current content, they
+// are rotated independently of the revisions and an old copy may no longer be valid. So do the serviceaccount-ca and
+// serviceaccount-root-ca bundles: they are combined from CAs rotated by other operators, an old copy may lack the
+// current ones and break the trust of the workloads.
+var (
+	RollbackConfigMaps = []string{
+		"kube-controller-manager-pod",
+		"kube-controller-manager-flags",
+		"config",
+		"cluster-policy-controller-config",
+		"recycler-config",
+		"kube-controller-cert-syncer-kubeconfig",
+		extraMountsName,
+	}
+	RollbackSecrets = []string{
+		extraMountsName,
+	}
+)
+
+// rollbackSyncers are the syncers of the RollbackConfigMaps and RollbackSecrets.
+var rollbackSyncers = sets.New(
+	"pod",
+	"config",
+	"cluster-policy-controller-config",
+	"recycler-config",
+	"cert-syncer-kubeconfig",
+	"extra-mounts",
+)
+
+// RollbackRevision returns the revision named by the RollbackAnnotation, 0 when none is.
+func RollbackRevision(operatorLister cache.GenericLister) (int32, error) {
+	uncastOperator, err := operatorLister.Get("cluster")
+	if err != nil {
+		return 0, err
+	}
+	operator, err := meta.Accessor(uncastOperator)
+	if err != nil {
+		return 0, err
+	}
+	value, ok := operator.GetAnnotations()[RollbackAnnotation]
+	if !ok || len(value) == 0 {
+		return 0, nil
+	}
+	revision, err := strconv.ParseInt(value, 10, 32)
+	if err != nil || revision <= 0 {
+		return 0, fmt.Errorf("%s: %q is not a revision", RollbackAnnotation, value)
+	}
+	return int32(revision), nil
+}
+
+// rollbackInEffect returns the revision of the RollbackAnnotation while the RollbackController keeps it restored.
+func rollbackInEffect(operatorLister cache.GenericLister, operatorStatus *operatorv1.StaticPodOperatorStatus) (int32, bool) {
+	revision, err := RollbackRevision(operatorLister)
+	if err != nil || revision == 0 {
+		return 0, false
+	}
+	return revision, v1helpers.IsOperatorConditionTrue(operatorStatus.Conditions, RevisionRollbackCondition)
+}
+
+// RestoreConfigMap writes a configmap rendered by this controller as its field manager, so that the controller takes
+// it over again without a conflict once the rollback ends.
+func RestoreConfigMap(ctx context.Context, client corev1client.ConfigMapsGetter, recorder events.Recorder, required, existing *corev1.ConfigMap) (*corev1.ConfigMap, bool, error) {
+	return serverSideApplyConfigMap(ctx, client, recorder, required, existing)
+}
@@ -0,0 +1,69 @@
+package targetconfigcontroller
+
+import (
+	"testing"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	operatorv1 "github.com/openshift/api/operator/v1"
+)
+
+func TestRollbackRevision(t *testing.T) {
+	tests := []struct {
+		value       string
+		expected    int32
+		expectedErr bool
+	}{
+		{value: "", expected: 0},
+		{value: "7", expected: 7},
+		{value: "0", expectedErr: true},
+		{value: "latest", expectedErr: true},
+	}
+	for _, test := range tests {
+		operator := &unstructured.Unstructured{}
+		operator.SetName("cluster")
+		if len(test.value) > 0 {
+			operator.SetAnnotations(map[string]string{RollbackAnnotation: test.value})
+		}
+		actual, err := RollbackRevision(fakeOperatorLister{operator: operator})
+		if (err != nil) != test.expectedErr {
+			t.Fatalf("annotation %q: unexpected error %v", test.value, err)
+		}
+		if actual != test.expected {
+			t.Errorf("annotation %q: expected %d, got %d", test.value, test.expected, actual)
+		}
+	}
+}
+
+func TestRollbackInEffect(t *testing.T) {
+	tests := []struct {
+		name         string
+		annotation   string
+		condition    operatorv1.ConditionStatus
+		expected     int32
+		expectedHeld bool
+	}{
+		{name: "no rollback", condition: operatorv1.ConditionFalse},
+		{name: "restored revision", annotation: "7", condition: operatorv1.ConditionTrue, expected: 7, expectedHeld: true},
+		{name: "rejected revision", annotation: "7", condition: operatorv1.ConditionFalse, expected: 7},
+		{name: "revision not checked yet", annotation: "7", expected: 7},
+		{name: "malformed annotation", annotation: "latest", condition: operatorv1.ConditionTrue},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			operator := &unstructured.Unstructured{}
+			operator.SetName("cluster")
+			if len(test.annotation) > 0 {
+				operator.SetAnnotations(map[string]string{RollbackAnnotation: test.annotation})
+			}
+			status := &operatorv1.StaticPodOperatorStatus{}
+			if len(test.condition) > 0 {
+				status.Conditions = []operatorv1.OperatorCondition{{Type: RevisionRollbackCondition, Status: test.condition}}
+			}
+			revision, held := rollbackInEffect(fakeOperatorLister{operator: operator}, status)
+			if held != test.expectedHeld || (held && revision != test.expected) {
+				t.Errorf("expected revision %d held %v, got %d held %v", test.expected, test.expectedHeld, revision, held)
+			}
+		})
+	}
+}
@@ -0,0 +1,87 @@
+package targetconfigcontroller
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	operatorv1 "github.com/openshift/api/operator/v1"
+	"github.com/openshift/library-go/pkg/controller/factory"
+	"github.com/openshift/library-go/pkg/operator/v1helpers"
+
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/tuning"
+)
+
+// syncScheduledCondition lists the syncers waiting for a delayed sync, a signer rotation would otherwise sit in the
+// queue without any trace in the status.
+const syncScheduledCondition = "TargetConfigControllerSyncScheduled"
+
+// scheduledSync is a delayed sync of a syncer.
+type scheduledSync struct {
+	at     time.Time
+	reason string
+}
+
+// csrSignerRequeueDelays applies the requeue delays of the tuning config to DefaultCSRSignerRequeueDelays.
+func csrSignerRequeueDelays(tuningConfig *tuning.Config) CSRSignerRequeueDelays {
+	delays := DefaultCSRSignerRequeueDelays
+	if recheck := tuningConfig.RequeueDelays.CSRSignerTrustRecheck; recheck != nil {
+		delays.TrustRecheckInterval = recheck.Duration
+	}
+	if padding := tuningConfig.RequeueDelays.Padding; padding != nil {
+		delays.Padding = padding.Duration
+	}
+	return delays
+}
+
+// scheduleSync syncs the resource of the sync context again after the delay and records it for the
+// TargetConfigControllerSyncScheduled condition.
+func (c *TargetConfigController) scheduleSync(syncCtx factory.SyncContext, delay time.Duration, reason string) {
+	syncCtx.Queue().AddAfter(syncCtx.QueueKey(), delay)
+
+	c.scheduledSyncsLock.Lock()
+	defer c.scheduledSyncsLock.Unlock()
+	c.scheduledSyncs[syncCtx.QueueKey()] = scheduledSync{at: time.Now().Add(delay), reason: reason}
+}
+
+// clearScheduledSync forgets the delayed sync of a syncer, it is due once the syncer runs.
+func (c *TargetConfigController) clearScheduledSync(name string) {
+	c.scheduledSyncsLock.Lock()
+	defer c.scheduledSyncsLock.Unlock()
+	delete(c.scheduledSyncs, name)
+}
+
+// updateSyncScheduledCondition reports the delayed syncs of all the syncers in TargetConfigControllerSyncScheduled.
+func (c *TargetConfigController) updateSyncScheduledCondition(ctx context.Context) error {
+	c.scheduledSyncsLock.Lock()
+	condition := syncScheduledConditionFor(c.syncers, c.scheduledSyncs)
+	c.scheduledSyncsLock.Unlock()
+
+	_, _, err := v1helpers.UpdateStaticPodStatus(ctx, c.operatorClient, v1helpers.UpdateStaticPodConditionFn(condition))
+	return err
+}
+
+// syncScheduledConditionFor lists the next scheduled sync of every syncer waiting for one, in the order of the syncers.
+func syncScheduledConditionFor(syncers []targetConfigSyncer, scheduled map[string]scheduledSync) operatorv1.OperatorCondition {
+	var lines []string
+	for _, syncer := range syncers {
+		next, ok := scheduled[syncer.name]
+		if !ok {
+			continue
+		}
+		lines = append(lines, fmt.Sprintf("%s: nextScheduledSync %s, %s", syncer.resource, next.at.UTC().Format(time.RFC3339), next.reason))
+	}
+	if len(lines) == 0 {
+		return operatorv1.OperatorCondition{
+			Type:   syncScheduledCondition,
+			Status: operatorv1.ConditionFalse,
+		}
+	}
+	return operatorv1.OperatorCondition{
+		Type:    syncScheduledCondition,
+		Status:  operatorv1.ConditionTrue,
+		Reason:  "DelayedSync",
+		Message: strings.Join(lines, "\n"),
+	}
+}
@@ -0,0 +1,61 @@
+package targetconfigcontroller
+
+import (
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	operatorv1 "github.com/openshift/api/operator/v1"
+
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/tuning"
+)
+
+func TestSyncScheduledConditionFor(t *testing.T) {
+	syncers := (&TargetConfigController{}).newSyncers()
+	at := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name            string
+		scheduled       map[string]scheduledSync
+		expectedStatus  operatorv1.ConditionStatus
+		expectedMessage string
+	}{
+		{
+			name:           "nothing scheduled",
+			expectedStatus: operatorv1.ConditionFalse,
+		},
+		{
+			name: "scheduled syncs in syncer order",
+			scheduled: map[string]scheduledSync{
+				"serviceaccount-ca": {at: at.Add(time.Hour), reason: "held back"},
+				"csr-signer":        {at: at, reason: "not trusted yet"},
+			},
+			expectedStatus:  operatorv1.ConditionTrue,
+			expectedMessage: "secrets/csr-signer: nextScheduledSync 2024-01-01T12:00:00Z, not trusted yet\nconfigmap/serviceaccount-ca: nextScheduledSync 2024-01-01T13:00:00Z, held back",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			actual := syncScheduledConditionFor(syncers, test.scheduled)
+			if actual.Status != test.expectedStatus || actual.Message != test.expectedMessage {
+				t.Errorf("expected %s %q, got %s %q", test.expectedStatus, test.expectedMessage, actual.Status, actual.Message)
+			}
+		})
+	}
+}
+
+func TestCSRSignerRequeueDelays(t *testing.T) {
+	if actual := csrSignerRequeueDelays(&tuning.Config{}); actual != DefaultCSRSignerRequeueDelays {
+		t.Errorf("expected the defaults, got %#v", actual)
+	}
+
+	tuningConfig := &tuning.Config{RequeueDelays: tuning.RequeueDelaysConfig{
+		CSRSignerTrustRecheck: &metav1.Duration{Duration: 5 * time.Second},
+		Padding:               &metav1.Duration{},
+	}}
+	expected := CSRSignerRequeueDelays{TrustRecheckInterval: 5 * time.Second}
+	if actual := csrSignerRequeueDelays(tuningConfig); actual != expected {
+		t.Errorf("expected %#v, got %#v", expected, actual)
+	}
+}
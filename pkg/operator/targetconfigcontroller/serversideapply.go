@@ -0,0 +1,240 @@
+package targetconfigcontroller
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
+	applycorev1 "k8s.io/client-go/applyconfigurations/core/v1"
+	corev1client "k8s.io/client-go/kubernetes/typed/core/v1"
+	"k8s.io/klog/v2"
+
+	"github.com/openshift/library-go/pkg/operator/events"
+)
+
+// FieldManager is the server-side apply field manager of the configmaps and secrets the target config and kubeconfig
+// controllers write. The fields another manager changed are not overwritten, the apply fails with a conflict that is
+// reported in the degraded condition of the controller until the change is reverted.
+const FieldManager = "kube-controller-manager-operator"
+
+// ApplyConfigMap is resourceapply.ApplyConfigMap with server-side apply as FieldManager. The client must read the live
+// configmap, the informers strip the managed fields.
+func ApplyConfigMap(ctx context.Context, client corev1client.ConfigMapsGetter, recorder events.Recorder, required *corev1.ConfigMap) (*corev1.ConfigMap, bool, error) {
+	existing, err := client.ConfigMaps(required.Namespace).Get(ctx, required.Name, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		existing = nil
+	} else if err != nil {
+		return nil, false, err
+	}
+	return serverSideApplyConfigMap(ctx, client, recorder, required, existing)
+}
+
+// ApplySecret is resourceapply.ApplySecret with server-side apply as FieldManager. A secret of another type is
+// recreated, the type is immutable. The client must read the live secret, the informers strip the managed fields.
+func ApplySecret(ctx context.Context, client corev1client.SecretsGetter, recorder events.Recorder, required *corev1.Secret) (*corev1.Secret, bool, error) {
+	existing, err := client.Secrets(required.Namespace).Get(ctx, required.Name, metav1.GetOptions{})
+	switch {
+	case apierrors.IsNotFound(err):
+		existing = nil
+	case err != nil:
+		return nil, false, err
+	case len(required.Type) > 0 && existing.Type != required.Type:
+		if err := client.Secrets(required.Namespace).Delete(ctx, required.Name, metav1.DeleteOptions{}); err != nil && !apierrors.IsNotFound(err) {
+			return nil, false, err
+		}
+		recorder.Eventf("SecretDeleted", "Deleted Secret/%s -n %s to change its type to %s", required.Name, required.Namespace, required.Type)
+		existing = nil
+	}
+
+	if existing == nil {
+		actual, err := client.Secrets(required.Namespace).Create(ctx, required, metav1.CreateOptions{FieldManager: FieldManager})
+		if err != nil {
+			recorder.Warningf("SecretCreateFailed", "Failed to create Secret/%s -n %s: %v", required.Name, required.Namespace, err)
+			return nil, false, err
+		}
+		recorder.Eventf("SecretCreated", "Created Secret/%s -n %s because it was missing", required.Name, required.Namespace)
+		return actual, true, nil
+	}
+	if foreign := foreignDataKeys(existing.ManagedFields, sets.KeySet(existing.Data), sets.KeySet(required.Data)); len(foreign) > 0 {
+		recorder.Warningf("ManagedFieldConflict", "Secret/%s -n %s has keys added outside of the operator, remove them to let the operator update it: %s", required.Name, required.Namespace, strings.Join(foreign, ", "))
+		return nil, false, fmt.Errorf("keys added outside of the operator: %s", strings.Join(foreign, ", "))
+	}
+	if secretApplied(required, existing) {
+		return existing, false, nil
+	}
+
+	force := !appliedByFieldManager(existing.ManagedFields)
+	if force {
+		klog.FromContext(ctx).V(2).Info("Adopting the fields of a secret written before server-side apply", "secret", required.Namespace+"/"+required.Name)
+	}
+	applyConfig := applycorev1.Secret(required.Name, required.Namespace).
+		WithLabels(required.Labels).
+		WithAnnotations(required.Annotations).
+		WithData(required.Data)
+	if len(required.Type) > 0 {
+		applyConfig = applyConfig.WithType(required.Type)
+	}
+	actual, err := client.Secrets(required.Namespace).Apply(ctx, applyConfig, metav1.ApplyOptions{FieldManager: FieldManager, Force: force})
+	if apierrors.IsConflict(err) {
+		recorder.Warningf("ManagedFieldConflict", "Secret/%s -n %s has fields changed outside of the operator, revert them to let the operator update it: %v", required.Name, required.Namespace, err)
+		return nil, false, fmt.Errorf("fields changed outside of the operator: %v", err)
+	}
+	if err != nil {
+		recorder.Warningf("SecretUpdateFailed", "Failed to update Secret/%s -n %s: %v", required.Name, required.Namespace, err)
+		return nil, false, err
+	}
+	modified := !equality.Semantic.DeepEqual(existing.Data, actual.Data) ||
+		!equality.Semantic.DeepEqual(existing.Labels, actual.Labels) ||
+		!equality.Semantic.DeepEqual(existing.Annotations, actual.Annotations)
+	if modified {
+		recorder.Eventf("SecretUpdated", "Updated Secret/%s -n %s because it changed", required.Name, required.Namespace)
+	}
+	return actual, modified, nil
+}
+
+// serverSideApplyConfigMap applies the data, labels and annotations of the required configmap as FieldManager. A
+// configmap the operator wrote before it used server-side apply is adopted by forcing the first apply, which takes
+// over the fields of the previous update manager.
+func serverSideApplyConfigMap(ctx context.Context, client corev1client.ConfigMapsGetter, recorder events.Recorder, required, existing *corev1.ConfigMap) (*corev1.ConfigMap, bool, error) {
+	if existing == nil {
+		actual, err := client.ConfigMaps(required.Namespace).Create(ctx, required, metav1.CreateOptions{FieldManager: FieldManager})
+		if err != nil {
+			recorder.Warningf("ConfigMapCreateFailed", "Failed to create ConfigMap/%s -n %s: %v", required.Name, required.Namespace, err)
+			return nil, false, err
+		}
+		recorder.Eventf("ConfigMapCreated", "Created ConfigMap/%s -n %s because it was missing", required.Name, required.Namespace)
+		return actual, true, nil
+	}
+	existingKeys := sets.KeySet(existing.Data).Union(sets.KeySet(existing.BinaryData))
+	requiredKeys := sets.KeySet(required.Data).Union(sets.KeySet(required.BinaryData))
+	if foreign := foreignDataKeys(existing.ManagedFields, existingKeys, requiredKeys); len(foreign) > 0 {
+		recorder.Warningf("ManagedFieldConflict", "ConfigMap/%s -n %s has keys added outside of the operator, remove them to let the operator update it: %s", required.Name, required.Namespace, strings.Join(foreign, ", "))
+		return nil, false, fmt.Errorf("keys added outside of the operator: %s", strings.Join(foreign, ", "))
+	}
+	if configMapApplied(required, existing) {
+		return existing, false, nil
+	}
+
+	force := !appliedByFieldManager(existing.ManagedFields)
+	if force {
+		klog.FromContext(ctx).V(2).Info("Adopting the fields of a configmap written before server-side apply", "configmap", required.Namespace+"/"+required.Name)
+	}
+	applyConfig := applycorev1.ConfigMap(required.Name, required.Namespace).
+		WithLabels(required.Labels).
+		WithAnnotations(required.Annotations).
+		WithData(required.Data).
+		WithBinaryData(required.BinaryData)
+	actual, err := client.ConfigMaps(required.Namespace).Apply(ctx, applyConfig, metav1.ApplyOptions{FieldManager: FieldManager, Force: force})
+	if apierrors.IsConflict(err) {
+		recorder.Warningf("ManagedFieldConflict", "ConfigMap/%s -n %s has fields changed outside of the operator, revert them to let the operator update it: %v", required.Name, required.Namespace, err)
+		return nil, false, fmt.Errorf("fields changed outside of the operator: %v", err)
+	}
+	if err != nil {
+		recorder.Warningf("ConfigMapUpdateFailed", "Failed to update ConfigMap/%s -n %s: %v", required.Name, required.Namespace, err)
+		return nil, false, err
+	}
+	// the fields of other managers are kept, so a no-op apply can still differ from the required configmap
+	modified := !equality.Semantic.DeepEqual(existing.Data, actual.Data) ||
+		!equality.Semantic.DeepEqual(existing.BinaryData, actual.BinaryData) ||
+		!equality.Semantic.DeepEqual(existing.Labels, actual.Labels) ||
+		!equality.Semantic.DeepEqual(existing.Annotations, actual.Annotations)
+	if modified {
+		recorder.Eventf("ConfigMapUpdated", "Updated ConfigMap/%s -n %s because it changed", required.Name, required.Namespace)
+	}
+	return actual, modified, nil
+}
+
+// configMapApplied returns whether the existing configmap already has the required data, labels and annotations, and
+// no longer has the keys the operator applied before but does not require anymore.
+func configMapApplied(required, existing *corev1.ConfigMap) bool {
+	for key, value := range required.Data {
+		if existingValue, ok := existing.Data[key]; !ok || existingValue != value {
+			return false
+		}
+	}
+	for key, value := range required.BinaryData {
+		if existingValue, ok := existing.BinaryData[key]; !ok || !equality.Semantic.DeepEqual(existingValue, value) {
+			return false
+		}
+	}
+	requiredKeys := sets.KeySet(required.Data).Union(sets.KeySet(required.BinaryData))
+	if appliedDataKeys(existing.ManagedFields).Difference(requiredKeys).Len() > 0 {
+		return false
+	}
+	return metadataApplied(required.ObjectMeta, existing.ObjectMeta)
+}
+
+// secretApplied returns whether the existing secret already has the required data, labels and annotations, and no
+// longer has the keys the operator applied before but does not require anymore.
+func secretApplied(required, existing *corev1.Secret) bool {
+	for key, value := range required.Data {
+		if existingValue, ok := existing.Data[key]; !ok || !equality.Semantic.DeepEqual(existingValue, value) {
+			return false
+		}
+	}
+	if appliedDataKeys(existing.ManagedFields).Difference(sets.KeySet(required.Data)).Len() > 0 {
+		return false
+	}
+	return metadataApplied(required.ObjectMeta, existing.ObjectMeta)
+}
+
+func metadataApplied(required, existing metav1.ObjectMeta) bool {
+	for key, value := range required.Labels {
+		if existingValue, ok := existing.Labels[key]; !ok || existingValue != value {
+			return false
+		}
+	}
+	for key, value := range required.Annotations {
+		if existingValue, ok := existing.Annotations[key]; !ok || existingValue != value {
+			return false
+		}
+	}
+	return true
+}
+
+// foreignDataKeys returns the keys of an existing configmap or secret that the operator neither requires nor applied.
+// Another manager added them, an apply does not remove them, so they are reported instead of silently kept.
+func foreignDataKeys(managedFields []metav1.ManagedFieldsEntry, existingKeys, requiredKeys sets.Set[string]) []string {
+	return sets.List(existingKeys.Difference(requiredKeys).Difference(appliedDataKeys(managedFields)))
+}
+
+// appliedDataKeys returns the data and binaryData keys FieldManager owns by server-side apply.
+func appliedDataKeys(managedFields []metav1.ManagedFieldsEntry) sets.Set[string] {
+	keys := sets.New[string]()
+	for _, entry := range managedFields {
+		if entry.Manager != FieldManager || entry.Operation != metav1.ManagedFieldsOperationApply || entry.FieldsV1 == nil {
+			continue
+		}
+		fields := map[string]json.RawMessage{}
+		if err := json.Unmarshal(entry.FieldsV1.Raw, &fields); err != nil {
+			continue
+		}
+		for _, field := range []string{"f:data", "f:binaryData"} {
+			dataFields := map[string]json.RawMessage{}
+			if err := json.Unmarshal(fields[field], &dataFields); err != nil {
+				continue
+			}
+			for key := range dataFields {
+				if strings.HasPrefix(key, "f:") {
+					keys.Insert(strings.TrimPrefix(key, "f:"))
+				}
+			}
+		}
+	}
+	return keys
+}
+
+func appliedByFieldManager(managedFields []metav1.ManagedFieldsEntry) bool {
+	for _, entry := range managedFields {
+		if entry.Manager == FieldManager && entry.Operation == metav1.ManagedFieldsOperationApply {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,234 @@
+package targetconfigcontroller
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	applycorev1 "k8s.io/client-go/applyconfigurations/core/v1"
+	"k8s.io/client-go/kubernetes/fake"
+	corev1client "k8s.io/client-go/kubernetes/typed/core/v1"
+
+	"github.com/openshift/library-go/pkg/operator/events"
+
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/operatorclient"
+)
+
+// applyRecordingClient records the options of the applies and fails them with a conflict when asked to, the fake
+// clientset does not track field managers.
+type applyRecordingClient struct {
+	corev1client.ConfigMapsGetter
+	applies  []metav1.ApplyOptions
+	conflict bool
+}
+
+func (c *applyRecordingClient) ConfigMaps(namespace string) corev1client.ConfigMapInterface {
+	return &applyRecordingConfigMaps{ConfigMapInterface: c.ConfigMapsGetter.ConfigMaps(namespace), client: c}
+}
+
+type applyRecordingConfigMaps struct {
+	corev1client.ConfigMapInterface
+	client *applyRecordingClient
+}
+
+func (c *applyRecordingConfigMaps) Apply(ctx context.Context, configMap *applycorev1.ConfigMapApplyConfiguration, opts metav1.ApplyOptions) (*corev1.ConfigMap, error) {
+	c.client.applies = append(c.client.applies, opts)
+	if c.client.conflict && !opts.Force {
+		return nil, apierrors.NewConflict(schema.GroupResource{Resource: "configmaps"}, *configMap.Name, nil)
+	}
+	return c.ConfigMapInterface.Apply(ctx, configMap, opts)
+}
+
+func TestServerSideApplyConfigMap(t *testing.T) {
+	required := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Namespace: operatorclient.TargetNamespace, Name: "config", Labels: map[string]string{"app": "kube-controller-manager"}},
+		Data:       map[string]string{"config.yaml": "new"},
+	}
+	existingWith := func(data string, managedFields ...metav1.ManagedFieldsEntry) *corev1.ConfigMap {
+		existing := required.DeepCopy()
+		existing.Data["config.yaml"] = data
+		existing.ManagedFields = managedFields
+		return existing
+	}
+	legacyManager := metav1.ManagedFieldsEntry{Manager: "cluster-kube-controller-manager-operator", Operation: metav1.ManagedFieldsOperationUpdate}
+	applyManager := metav1.ManagedFieldsEntry{Manager: FieldManager, Operation: metav1.ManagedFieldsOperationApply}
+	existingWithKey := func(key string, owner metav1.ManagedFieldsEntry) *corev1.ConfigMap {
+		existing := existingWith("new", applyManager, owner)
+		existing.ManagedFields[0].FieldsV1 = &metav1.FieldsV1{Raw: []byte(`{"f:data":{".":{},"f:config.yaml":{}}}`)}
+		existing.ManagedFields[1].FieldsV1 = &metav1.FieldsV1{Raw: []byte(`{"f:data":{"f:` + key + `":{}}}`)}
+		existing.Data[key] = "value"
+		return existing
+	}
+
+	tests := []struct {
+		name             string
+		existing         *corev1.ConfigMap
+		conflict         bool
+		expectedApplies  []metav1.ApplyOptions
+		expectedModified bool
+		expectedError    bool
+		expectedEvent    string
+	}{
+		{
+			name:             "missing configmap is created",
+			expectedModified: true,
+			expectedEvent:    "ConfigMapCreated",
+		},
+		{
+			name:     "unchanged configmap is not applied",
+			existing: existingWith("new", applyManager),
+		},
+		{
+			name:             "configmap written before server-side apply is adopted",
+			existing:         existingWith("old", legacyManager),
+			expectedApplies:  []metav1.ApplyOptions{{FieldManager: FieldManager, Force: true}},
+			expectedModified: true,
+			expectedEvent:    "ConfigMapUpdated",
+		},
+		{
+			name:             "changed configmap is applied",
+			existing:         existingWith("old", applyManager),
+			expectedApplies:  []metav1.ApplyOptions{{FieldManager: FieldManager}},
+			expectedModified: true,
+			expectedEvent:    "ConfigMapUpdated",
+		},
+		{
+			name:            "fields changed by another manager are reported",
+			existing:        existingWith("edited", applyManager, metav1.ManagedFieldsEntry{Manager: "kubectl-edit", Operation: metav1.ManagedFieldsOperationUpdate}),
+			conflict:        true,
+			expectedApplies: []metav1.ApplyOptions{{FieldManager: FieldManager}},
+			expectedError:   true,
+			expectedEvent:   "ManagedFieldConflict",
+		},
+		{
+			name:          "key added by another manager is reported",
+			existing:      existingWithKey("extra.yaml", metav1.ManagedFieldsEntry{Manager: "kubectl-edit", Operation: metav1.ManagedFieldsOperationUpdate}),
+			expectedError: true,
+			expectedEvent: "ManagedFieldConflict",
+		},
+		{
+			// the apply removes the key, the fake clientset merges the apply patch and keeps it
+			name:            "key the operator no longer requires is applied away",
+			existing:        existingWithKey("stale.yaml", metav1.ManagedFieldsEntry{Manager: FieldManager, Operation: metav1.ManagedFieldsOperationApply}),
+			expectedApplies: []metav1.ApplyOptions{{FieldManager: FieldManager}},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			kubeClient := fake.NewSimpleClientset()
+			if test.existing != nil {
+				kubeClient = fake.NewSimpleClientset(test.existing)
+			}
+			client := &applyRecordingClient{ConfigMapsGetter: kubeClient.CoreV1(), conflict: test.conflict}
+			recorder := events.NewInMemoryRecorder("server-side-apply")
+
+			_, modified, err := serverSideApplyConfigMap(context.TODO(), client, recorder, required, test.existing)
+			if test.expectedError != (err != nil) {
+				t.Fatalf("expected error %v, got %v", test.expectedError, err)
+			}
+			if modified != test.expectedModified {
+				t.Errorf("expected modified %v, got %v", test.expectedModified, modified)
+			}
+			if len(client.applies) != len(test.expectedApplies) {
+				t.Fatalf("expected applies %v, got %v", test.expectedApplies, client.applies)
+			}
+			for i := range client.applies {
+				if client.applies[i].FieldManager != test.expectedApplies[i].FieldManager || client.applies[i].Force != test.expectedApplies[i].Force {
+					t.Errorf("expected apply options %v, got %v", test.expectedApplies[i], client.applies[i])
+				}
+			}
+			var reasons []string
+			for _, event := range recorder.Events() {
+				reasons = append(reasons, event.Reason)
+			}
+			if len(test.expectedEvent) == 0 && len(reasons) > 0 || len(test.expectedEvent) > 0 && (len(reasons) != 1 || reasons[0] != test.expectedEvent) {
+				t.Errorf("expected event %q, got %v", test.expectedEvent, reasons)
+			}
+		})
+	}
+}
+
+func TestApplySecret(t *testing.T) {
+	required := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Namespace: operatorclient.TargetNamespace, Name: "csr-signer"},
+		Type:       corev1.SecretTypeTLS,
+		Data:       map[string][]byte{"tls.crt": []byte("new"), "tls.key": []byte("key")},
+	}
+	existingWith := func(secretType corev1.SecretType, crt string) *corev1.Secret {
+		existing := required.DeepCopy()
+		existing.Type = secretType
+		existing.Data["tls.crt"] = []byte(crt)
+		existing.ManagedFields = []metav1.ManagedFieldsEntry{{Manager: FieldManager, Operation: metav1.ManagedFieldsOperationApply}}
+		return existing
+	}
+
+	tests := []struct {
+		name           string
+		existing       *corev1.Secret
+		expectedVerbs  []string
+		expectedEvents []string
+	}{
+		{
+			name:           "missing secret is created",
+			expectedVerbs:  []string{"get", "create"},
+			expectedEvents: []string{"SecretCreated"},
+		},
+		{
+			name:          "unchanged secret is not applied",
+			existing:      existingWith(corev1.SecretTypeTLS, "new"),
+			expectedVerbs: []string{"get"},
+		},
+		{
+			name:           "changed secret is applied",
+			existing:       existingWith(corev1.SecretTypeTLS, "old"),
+			expectedVerbs:  []string{"get", "patch"},
+			expectedEvents: []string{"SecretUpdated"},
+		},
+		{
+			name:           "secret of another type is recreated",
+			existing:       existingWith(corev1.SecretTypeOpaque, "new"),
+			expectedVerbs:  []string{"get", "delete", "create"},
+			expectedEvents: []string{"SecretDeleted", "SecretCreated"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			kubeClient := fake.NewSimpleClientset()
+			if test.existing != nil {
+				kubeClient = fake.NewSimpleClientset(test.existing)
+			}
+			recorder := events.NewInMemoryRecorder("server-side-apply")
+
+			if _, _, err := ApplySecret(context.TODO(), kubeClient.CoreV1(), recorder, required); err != nil {
+				t.Fatal(err)
+			}
+			var verbs []string
+			for _, action := range kubeClient.Actions() {
+				verbs = append(verbs, action.GetVerb())
+			}
+			if !reflect.DeepEqual(verbs, test.expectedVerbs) {
+				t.Errorf("expected %v, got %v", test.expectedVerbs, verbs)
+			}
+			var reasons []string
+			for _, event := range recorder.Events() {
+				reasons = append(reasons, event.Reason)
+			}
+			if !reflect.DeepEqual(reasons, test.expectedEvents) {
+				t.Errorf("expected events %v, got %v", test.expectedEvents, reasons)
+			}
+			actual, err := kubeClient.CoreV1().Secrets(operatorclient.TargetNamespace).Get(context.TODO(), "csr-signer", metav1.GetOptions{})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if actual.Type != required.Type || string(actual.Data["tls.crt"]) != "new" {
+				t.Errorf("unexpected secret %#v", actual)
+			}
+		})
+	}
+}
@@ -0,0 +1,94 @@
+package targetconfigcontroller
+
+import (
+	"fmt"
+	"strings"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	corev1listers "k8s.io/client-go/listers/core/v1"
+
+	operatorv1 "github.com/openshift/api/operator/v1"
+	"github.com/openshift/library-go/pkg/operator/resourcesynccontroller"
+
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/operatorclient"
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/tuning"
+)
+
+// serviceAccountCASourceMissingCondition is reported while a source of the serviceaccount-ca bundle the tuning config
+// does not exclude is missing. The message lists the availability of every source.
+const serviceAccountCASourceMissingCondition = "ServiceAccountCASourceMissing"
+
+const (
+	caSourceIncluded = "included"
+	caSourceExcluded = "excluded"
+	caSourceMissing  = "missing"
+)
+
+// caSource is the availability of a source of the serviceaccount-ca bundle.
+type caSource struct {
+	name   string
+	policy tuning.CASourcePolicy
+	state  string
+}
+
+// serviceAccountCASources returns the sources of the serviceaccount-ca bundle to combine and the availability of all
+// of them, by the policies of the tuning config. A source without a ca-bundle.crt is missing, a missing Required source
+// fails the bundle.
+func serviceAccountCASources(lister corev1listers.ConfigMapLister, tuningConfig *tuning.Config) ([]resourcesynccontroller.ResourceLocation, []caSource, error) {
+	var locations []resourcesynccontroller.ResourceLocation
+	var sources []caSource
+	var missingRequired []string
+	for _, source := range tuning.ServiceAccountCABundleSources {
+		current := caSource{name: source.Name, policy: tuningConfig.ServiceAccountCASourcePolicy(source.Name), state: caSourceIncluded}
+		if current.policy == tuning.ExcludedCASourcePolicy {
+			current.state = caSourceExcluded
+			sources = append(sources, current)
+			continue
+		}
+		configMap, err := lister.ConfigMaps(operatorclient.GlobalMachineSpecifiedConfigNamespace).Get(source.Name)
+		if err != nil && !apierrors.IsNotFound(err) {
+			return nil, nil, err
+		}
+		if configMap == nil || len(configMap.Data["ca-bundle.crt"]) == 0 {
+			current.state = caSourceMissing
+			if current.policy == tuning.RequiredCASourcePolicy {
+				missingRequired = append(missingRequired, "configmap/"+source.Name)
+			}
+		} else {
+			locations = append(locations, resourcesynccontroller.ResourceLocation{Namespace: operatorclient.GlobalMachineSpecifiedConfigNamespace, Name: source.Name})
+		}
+		sources = append(sources, current)
+	}
+	if len(missingRequired) > 0 {
+		return nil, sources, fmt.Errorf("the required sources %s in %q are missing", strings.Join(missingRequired, ", "), operatorclient.GlobalMachineSpecifiedConfigNamespace)
+	}
+	return locations, sources, nil
+}
+
+// serviceAccountCASourceCondition reports the sources of the serviceaccount-ca bundle that are missing.
+func serviceAccountCASourceCondition(sources []caSource) operatorv1.OperatorCondition {
+	var states []string
+	var missing, missingRequired bool
+	for _, source := range sources {
+		states = append(states, fmt.Sprintf("configmap/%s (%s): %s", source.name, source.policy, source.state))
+		if source.state == caSourceMissing {
+			missing = true
+			missingRequired = missingRequired || source.policy == tuning.RequiredCASourcePolicy
+		}
+	}
+	condition := operatorv1.OperatorCondition{
+		Type:    serviceAccountCASourceMissingCondition,
+		Status:  operatorv1.ConditionFalse,
+		Reason:  "AsExpected",
+		Message: fmt.Sprintf("The sources of configmap/serviceaccount-ca in %q: %s", operatorclient.GlobalMachineSpecifiedConfigNamespace, strings.Join(states, ", ")),
+	}
+	switch {
+	case missingRequired:
+		condition.Status = operatorv1.ConditionTrue
+		condition.Reason = "RequiredSourceMissing"
+	case missing:
+		condition.Status = operatorv1.ConditionTrue
+		condition.Reason = "OptionalSourceMissing"
+	}
+	return condition
+}
@@ -0,0 +1,117 @@
+package targetconfigcontroller
+
+import (
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	corev1listers "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/tools/cache"
+
+	operatorv1 "github.com/openshift/api/operator/v1"
+
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/operatorclient"
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/tuning"
+)
+
+func TestServiceAccountCASources(t *testing.T) {
+	tests := []struct {
+		name              string
+		configMaps        []string
+		policies          map[string]tuning.CASourcePolicy
+		expectedSources   []string
+		expectedError     bool
+		expectedStatus    operatorv1.ConditionStatus
+		expectedReason    string
+		expectedAvailable map[string]string
+	}{
+		{
+			name:              "all sources present",
+			configMaps:        []string{"kube-apiserver-server-ca", "default-ingress-cert"},
+			expectedSources:   []string{"kube-apiserver-server-ca", "default-ingress-cert"},
+			expectedStatus:    operatorv1.ConditionFalse,
+			expectedReason:    "AsExpected",
+			expectedAvailable: map[string]string{"kube-apiserver-server-ca": caSourceIncluded, "default-ingress-cert": caSourceIncluded},
+		},
+		{
+			name:              "no ingress operator",
+			configMaps:        []string{"kube-apiserver-server-ca"},
+			expectedSources:   []string{"kube-apiserver-server-ca"},
+			expectedStatus:    operatorv1.ConditionTrue,
+			expectedReason:    "OptionalSourceMissing",
+			expectedAvailable: map[string]string{"kube-apiserver-server-ca": caSourceIncluded, "default-ingress-cert": caSourceMissing},
+		},
+		{
+			name:              "excluded ingress certificate",
+			configMaps:        []string{"kube-apiserver-server-ca", "default-ingress-cert"},
+			policies:          map[string]tuning.CASourcePolicy{"default-ingress-cert": tuning.ExcludedCASourcePolicy},
+			expectedSources:   []string{"kube-apiserver-server-ca"},
+			expectedStatus:    operatorv1.ConditionFalse,
+			expectedReason:    "AsExpected",
+			expectedAvailable: map[string]string{"kube-apiserver-server-ca": caSourceIncluded, "default-ingress-cert": caSourceExcluded},
+		},
+		{
+			name:              "missing required source",
+			configMaps:        []string{"default-ingress-cert"},
+			policies:          map[string]tuning.CASourcePolicy{"kube-apiserver-server-ca": tuning.RequiredCASourcePolicy},
+			expectedError:     true,
+			expectedStatus:    operatorv1.ConditionTrue,
+			expectedReason:    "RequiredSourceMissing",
+			expectedAvailable: map[string]string{"kube-apiserver-server-ca": caSourceMissing, "default-ingress-cert": caSourceIncluded},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			indexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{})
+			for _, name := range test.configMaps {
+				if err := indexer.Add(&corev1.ConfigMap{
+					ObjectMeta: metav1.ObjectMeta{Namespace: operatorclient.GlobalMachineSpecifiedConfigNamespace, Name: name},
+					Data:       map[string]string{"ca-bundle.crt": "certificates"},
+				}); err != nil {
+					t.Fatal(err)
+				}
+			}
+			tuningConfig := &tuning.Config{ServiceAccountCASources: test.policies}
+			if err := tuningConfig.Validate(); err != nil {
+				t.Fatal(err)
+			}
+
+			locations, sources, err := serviceAccountCASources(corev1listers.NewConfigMapLister(indexer), tuningConfig)
+			if (err != nil) != test.expectedError {
+				t.Fatalf("expected error %v, got %v", test.expectedError, err)
+			}
+			var names []string
+			for _, location := range locations {
+				names = append(names, location.Name)
+			}
+			if len(names) != len(test.expectedSources) {
+				t.Fatalf("expected sources %v, got %v", test.expectedSources, names)
+			}
+			for i := range names {
+				if names[i] != test.expectedSources[i] {
+					t.Errorf("expected sources %v, got %v", test.expectedSources, names)
+				}
+			}
+			for _, source := range sources {
+				if test.expectedAvailable[source.name] != source.state {
+					t.Errorf("expected %s to be %s, got %s", source.name, test.expectedAvailable[source.name], source.state)
+				}
+			}
+			if condition := serviceAccountCASourceCondition(sources); condition.Status != test.expectedStatus || condition.Reason != test.expectedReason {
+				t.Errorf("expected %s/%s, got %#v", test.expectedStatus, test.expectedReason, condition)
+			}
+		})
+	}
+}
+
+func TestValidateServiceAccountCASources(t *testing.T) {
+	for _, sources := range []map[string]tuning.CASourcePolicy{
+		{"router-ca": tuning.AutoCASourcePolicy},
+		{"kube-apiserver-server-ca": tuning.ExcludedCASourcePolicy},
+		{"default-ingress-cert": "Sometimes"},
+	} {
+		if err := (&tuning.Config{ServiceAccountCASources: sources}).Validate(); err == nil {
+			t.Errorf("expected %v to be rejected", sources)
+		}
+	}
+}
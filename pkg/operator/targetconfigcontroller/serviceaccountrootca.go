@@ -0,0 +1,73 @@
+package targetconfigcontroller
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	corev1client "k8s.io/client-go/kubernetes/typed/core/v1"
+	"k8s.io/client-go/util/cert"
+	"k8s.io/klog/v2"
+
+	operatorv1 "github.com/openshift/api/operator/v1"
+	"github.com/openshift/library-go/pkg/controller/factory"
+	"github.com/openshift/library-go/pkg/operator/resource/resourceapply"
+
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/cabundle"
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/operatorclient"
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/tuning"
+)
+
+const (
+	// serviceAccountRootCAConfigMapName is the revisioned copy of the tuning ServiceAccountRootCA, the
+	// kube-controller-manager reads it through --root-ca-file.
+	serviceAccountRootCAConfigMapName = "serviceaccount-root-ca"
+
+	// inClusterAPIServerURL is the kube-apiserver the way the pods reach it with the root CA of their service
+	// account.
+	inClusterAPIServerURL = "https://kubernetes.default.svc"
+)
+
+func (c *TargetConfigController) syncServiceAccountRootCA(ctx context.Context, syncCtx factory.SyncContext, client corev1client.CoreV1Interface, _ *operatorv1.StaticPodOperatorSpec) error {
+	tuningConfig, err := tuning.Get(c.configMapLister)
+	if err != nil {
+		return fmt.Errorf("%q: %v", "configmap/"+tuning.ConfigMapName, err)
+	}
+	required := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Namespace: operatorclient.TargetNamespace, Name: serviceAccountRootCAConfigMapName},
+	}
+	if len(tuningConfig.ServiceAccountRootCA) == 0 {
+		// the root CA file falls back to serviceaccount-ca once the config observer drops the flag
+		_, _, err := resourceapply.DeleteConfigMap(ctx, client, syncCtx.Recorder(), required)
+		return err
+	}
+
+	source, err := c.configMapLister.ConfigMaps(operatorclient.GlobalUserSpecifiedConfigNamespace).Get(tuningConfig.ServiceAccountRootCA)
+	if err != nil {
+		return fmt.Errorf("serviceAccountRootCA: %v", err)
+	}
+	caBundle := source.Data["ca-bundle.crt"]
+	if len(caBundle) == 0 {
+		return fmt.Errorf("serviceAccountRootCA: configmap/%s in %q has no ca-bundle.crt", tuningConfig.ServiceAccountRootCA, operatorclient.GlobalUserSpecifiedConfigNamespace)
+	}
+	if _, err := cert.ParseCertsPEM([]byte(caBundle)); err != nil {
+		return fmt.Errorf("serviceAccountRootCA: configmap/%s in %q is malformed: %v", tuningConfig.ServiceAccountRootCA, operatorclient.GlobalUserSpecifiedConfigNamespace, err)
+	}
+
+	// the pods would only fail once their clients trust the new root CA, a bundle that does not verify the
+	// kube-apiserver is not rolled out. A kube-apiserver the operator cannot reach is not held against the bundle.
+	err = c.verifyServer(inClusterAPIServerURL, []byte(caBundle))
+	var unreachable *cabundle.UnreachableError
+	if errors.As(err, &unreachable) {
+		klog.Warningf("Could not verify the certificate of %s: %v", inClusterAPIServerURL, unreachable.Err)
+		syncCtx.Recorder().Warningf("ServiceAccountRootCAUnverified", "Could not verify the certificate of %s against configmap/%s in %q: %v", inClusterAPIServerURL, tuningConfig.ServiceAccountRootCA, operatorclient.GlobalUserSpecifiedConfigNamespace, unreachable.Err)
+	} else if err != nil {
+		return fmt.Errorf("not rolling out configmap/%s in %q as the service account root CA: %v", tuningConfig.ServiceAccountRootCA, operatorclient.GlobalUserSpecifiedConfigNamespace, err)
+	}
+
+	required.Data = map[string]string{"ca-bundle.crt": caBundle}
+	_, _, err = ApplyConfigMap(ctx, client, syncCtx.Recorder(), required)
+	return err
+}
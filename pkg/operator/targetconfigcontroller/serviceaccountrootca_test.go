@@ -0,0 +1,146 @@
+package targetconfigcontroller
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+	corev1listers "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/tools/cache"
+
+	"github.com/openshift/library-go/pkg/controller/factory"
+	"github.com/openshift/library-go/pkg/crypto"
+	"github.com/openshift/library-go/pkg/operator/events"
+
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/cabundle"
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/operatorclient"
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/tuning"
+)
+
+func TestSyncServiceAccountRootCA(t *testing.T) {
+	caConfig, err := crypto.MakeSelfSignedCAConfigForDuration("proxy-ca", time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	caBundle, _, err := caConfig.GetPEMBytes()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name           string
+		tuningConfig   string
+		source         string
+		verifyErr      error
+		expectedBundle string
+		expectedErr    bool
+		expectedEvent  string
+	}{
+		{
+			name: "unset",
+		},
+		{
+			name:           "verified",
+			tuningConfig:   "serviceAccountRootCA: proxy-ca",
+			source:         string(caBundle),
+			expectedBundle: string(caBundle),
+		},
+		{
+			name:           "unreachable kube-apiserver",
+			tuningConfig:   "serviceAccountRootCA: proxy-ca",
+			source:         string(caBundle),
+			verifyErr:      &cabundle.UnreachableError{Server: inClusterAPIServerURL, Err: fmt.Errorf("connection refused")},
+			expectedBundle: string(caBundle),
+			expectedEvent:  "ServiceAccountRootCAUnverified",
+		},
+		{
+			name:         "not verifying the kube-apiserver",
+			tuningConfig: "serviceAccountRootCA: proxy-ca",
+			source:       string(caBundle),
+			verifyErr:    errors.New("unknown authority"),
+			expectedErr:  true,
+		},
+		{
+			name:         "malformed",
+			tuningConfig: "serviceAccountRootCA: proxy-ca",
+			source:       "not a certificate",
+			expectedErr:  true,
+		},
+		{
+			name:         "missing",
+			tuningConfig: "serviceAccountRootCA: proxy-ca",
+			expectedErr:  true,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			indexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc})
+			if len(test.tuningConfig) > 0 {
+				if err := indexer.Add(&corev1.ConfigMap{
+					ObjectMeta: metav1.ObjectMeta{Namespace: operatorclient.OperatorNamespace, Name: tuning.ConfigMapName},
+					Data:       map[string]string{tuning.ConfigKey: test.tuningConfig},
+				}); err != nil {
+					t.Fatal(err)
+				}
+			}
+			if len(test.source) > 0 {
+				if err := indexer.Add(&corev1.ConfigMap{
+					ObjectMeta: metav1.ObjectMeta{Namespace: operatorclient.GlobalUserSpecifiedConfigNamespace, Name: "proxy-ca"},
+					Data:       map[string]string{"ca-bundle.crt": test.source},
+				}); err != nil {
+					t.Fatal(err)
+				}
+			}
+			// a copy left behind by a previous config
+			kubeClient := fake.NewSimpleClientset(&corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{Namespace: operatorclient.TargetNamespace, Name: serviceAccountRootCAConfigMapName},
+				Data:       map[string]string{"ca-bundle.crt": "previous"},
+			})
+			c := &TargetConfigController{
+				configMapLister: corev1listers.NewConfigMapLister(indexer),
+				verifyServer: func(server string, _ []byte) error {
+					if server != inClusterAPIServerURL {
+						t.Errorf("expected %s to be verified, got %s", inClusterAPIServerURL, server)
+					}
+					return test.verifyErr
+				},
+			}
+			recorder := events.NewInMemoryRecorder("test")
+
+			err := c.syncServiceAccountRootCA(context.TODO(), factory.NewSyncContext("test", recorder), kubeClient.CoreV1(), nil)
+			if test.expectedErr != (err != nil) {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			actual, getErr := kubeClient.CoreV1().ConfigMaps(operatorclient.TargetNamespace).Get(context.TODO(), serviceAccountRootCAConfigMapName, metav1.GetOptions{})
+			switch {
+			case test.expectedErr:
+				if getErr != nil || actual.Data["ca-bundle.crt"] != "previous" {
+					t.Errorf("expected the previous copy to be kept, got %v", getErr)
+				}
+			case len(test.expectedBundle) == 0:
+				if !apierrors.IsNotFound(getErr) {
+					t.Errorf("expected the copy to be deleted, got %v", getErr)
+				}
+			case getErr != nil:
+				t.Fatal(getErr)
+			case actual.Data["ca-bundle.crt"] != test.expectedBundle:
+				t.Errorf("expected the configured bundle, got %q", actual.Data["ca-bundle.crt"])
+			}
+			if len(test.expectedEvent) > 0 {
+				found := false
+				for _, event := range recorder.Events() {
+					found = found || event.Reason == test.expectedEvent
+				}
+				if !found {
+					t.Errorf("expected a %s event, got %v", test.expectedEvent, recorder.Events())
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,64 @@
+package targetconfigcontroller
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+
+	operatorv1 "github.com/openshift/api/operator/v1"
+
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/operatorclient"
+)
+
+const (
+	// servingCertInvalidCondition is reported while the serving-cert cannot be served, the kube-controller-manager
+	// falls back to a self-signed certificate meanwhile.
+	servingCertInvalidCondition = "ServingCertInvalid"
+
+	// servingCertServerName is the name the kube-controller-manager is reached by, see the kube-controller-manager service.
+	servingCertServerName = "kube-controller-manager.openshift-kube-controller-manager.svc"
+)
+
+// validateServingCert checks that the kube-controller-manager can start with the serving-cert: the certificate and the
+// key parse and match, the certificate is valid now and is issued for the servingCertServerName. The kube-controller-manager
+// crash-loops on a certificate it cannot load.
+func validateServingCert(secret *corev1.Secret, now time.Time) error {
+	keyPair, err := tls.X509KeyPair(secret.Data[corev1.TLSCertKey], secret.Data[corev1.TLSPrivateKeyKey])
+	if err != nil {
+		return err
+	}
+	certificate, err := x509.ParseCertificate(keyPair.Certificate[0])
+	if err != nil {
+		return err
+	}
+	if validAt := skewTolerantTime(certificate.NotBefore, now); validAt.Before(certificate.NotBefore) || validAt.After(certificate.NotAfter) {
+		return fmt.Errorf("the certificate is valid from %s to %s", certificate.NotBefore.UTC().Format(time.RFC3339), certificate.NotAfter.UTC().Format(time.RFC3339))
+	}
+	if err := certificate.VerifyHostname(servingCertServerName); err != nil {
+		return err
+	}
+	return nil
+}
+
+// servingCertCondition reports a serving-cert that managePod does not wire into the kube-controller-manager because it
+// is invalid.
+func (c *TargetConfigController) servingCertCondition(now time.Time) operatorv1.OperatorCondition {
+	condition := operatorv1.OperatorCondition{Type: servingCertInvalidCondition, Status: operatorv1.ConditionFalse, Reason: "AsExpected"}
+	secret, err := c.secretLister.Secrets(operatorclient.TargetNamespace).Get("serving-cert")
+	if apierrors.IsNotFound(err) {
+		return condition
+	}
+	if err != nil {
+		return operatorv1.OperatorCondition{Type: servingCertInvalidCondition, Status: operatorv1.ConditionUnknown, Reason: "MissingInput", Message: err.Error()}
+	}
+	if err := validateServingCert(secret, now); err != nil {
+		condition.Status = operatorv1.ConditionTrue
+		condition.Reason = "InvalidCertificate"
+		condition.Message = fmt.Sprintf("The kube-controller-manager serves a self-signed certificate, secret/serving-cert -n %s is invalid: %v", operatorclient.TargetNamespace, err)
+	}
+	return condition
+}
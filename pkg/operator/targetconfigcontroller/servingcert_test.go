@@ -0,0 +1,96 @@
+package targetconfigcontroller
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/client-go/kubernetes/fake"
+
+	operatorv1 "github.com/openshift/api/operator/v1"
+	"github.com/openshift/library-go/pkg/crypto"
+	"github.com/openshift/library-go/pkg/operator/events"
+	"github.com/openshift/library-go/pkg/operator/resource/resourceread"
+
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/operatorclient"
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/tuning"
+)
+
+func TestValidateServingCert(t *testing.T) {
+	caConfig, err := crypto.MakeSelfSignedCAConfigForDuration("service-ca", time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ca := &crypto.CA{Config: caConfig, SerialGenerator: &crypto.RandomSerialGenerator{}}
+	servingCert := func(hostname string) (certPEM, keyPEM []byte) {
+		config, err := ca.MakeServerCertForDuration(sets.NewString(hostname), time.Hour)
+		if err != nil {
+			t.Fatal(err)
+		}
+		certPEM, keyPEM, err = config.GetPEMBytes()
+		if err != nil {
+			t.Fatal(err)
+		}
+		return certPEM, keyPEM
+	}
+	validCert, validKey := servingCert(servingCertServerName)
+	_, otherKey := servingCert(servingCertServerName)
+	wrongHostCert, wrongHostKey := servingCert("example.com")
+
+	now := time.Now()
+	for _, test := range []struct {
+		name          string
+		cert, key     []byte
+		now           time.Time
+		expectedError string
+	}{
+		{name: "valid", cert: validCert, key: validKey, now: now},
+		{name: "empty", expectedError: "failed to find any PEM data"},
+		{name: "bad PEM", cert: []byte("not a certificate"), key: validKey, now: now, expectedError: "failed to find any PEM data"},
+		{name: "mismatched key", cert: validCert, key: otherKey, now: now, expectedError: "private key does not match public key"},
+		{name: "expired", cert: validCert, key: validKey, now: now.Add(2 * time.Hour), expectedError: "the certificate is valid from"},
+		{name: "wrong host", cert: wrongHostCert, key: wrongHostKey, now: now, expectedError: servingCertServerName},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			secret := &corev1.Secret{Data: map[string][]byte{corev1.TLSCertKey: test.cert, corev1.TLSPrivateKeyKey: test.key}}
+			err := validateServingCert(secret, test.now)
+			switch {
+			case len(test.expectedError) == 0 && err != nil:
+				t.Errorf("unexpected error: %v", err)
+			case len(test.expectedError) > 0 && (err == nil || !strings.Contains(err.Error(), test.expectedError)):
+				t.Errorf("expected an error containing %q, got %v", test.expectedError, err)
+			}
+		})
+	}
+
+	// managePod only wires a valid serving-cert
+	for _, test := range []struct {
+		name      string
+		data      map[string][]byte
+		expectTLS bool
+	}{
+		{name: "valid serving-cert", data: map[string][]byte{corev1.TLSCertKey: validCert, corev1.TLSPrivateKeyKey: validKey}, expectTLS: true},
+		{name: "invalid serving-cert", data: map[string][]byte{corev1.TLSCertKey: validCert, corev1.TLSPrivateKeyKey: otherKey}},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			kubeClient := fake.NewSimpleClientset(&corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{Namespace: operatorclient.TargetNamespace, Name: "serving-cert"},
+				Data:       test.data,
+			})
+			operatorSpec := &operatorv1.StaticPodOperatorSpec{}
+			operatorSpec.ObservedConfig.Raw = []byte(`{}`)
+			cm, _, err := managePod(context.TODO(), nil, kubeClient.CoreV1(), kubeClient.CoreV1(), events.NewInMemoryRecorder("target-config"), operatorSpec, &tuning.Config{}, "kcm", "operator", "cpc", false, true)
+			if err != nil {
+				t.Fatal(err)
+			}
+			pod := resourceread.ReadPodV1OrDie([]byte(cm.Data["pod.yaml"]))
+			if actual := strings.Contains(pod.Spec.Containers[0].Args[0], "--tls-cert-file="); actual != test.expectTLS {
+				t.Errorf("expected the serving-cert flags %v, got args %q", test.expectTLS, pod.Spec.Containers[0].Args[0])
+			}
+		})
+	}
+}
@@ -0,0 +1,55 @@
+package targetconfigcontroller
+
+import (
+	"fmt"
+
+	kyaml "k8s.io/apimachinery/pkg/util/yaml"
+
+	operatorv1 "github.com/openshift/api/operator/v1"
+	"github.com/openshift/library-go/pkg/operator/loglevel"
+	"github.com/openshift/library-go/pkg/operator/v1helpers"
+)
+
+// invalidOperatorSpecCondition is reported while the kubecontrollermanager/cluster spec is rejected. The controller
+// does not render anything from a rejected spec, the operand keeps running with the last accepted one.
+const invalidOperatorSpecCondition = "InvalidOperatorSpecDegraded"
+
+// validateOperatorSpec checks the spec values the API server does not validate, or that slip past its validation
+// when the CRD schema is not enforced, e.g. on a resource written before the schema tightened.
+func validateOperatorSpec(spec *operatorv1.StaticPodOperatorSpec) []error {
+	var errs []error
+	switch spec.ManagementState {
+	case "", operatorv1.Managed, operatorv1.Force, operatorv1.Unmanaged, operatorv1.Removed:
+	default:
+		errs = append(errs, fmt.Errorf("unknown managementState %q", spec.ManagementState))
+	}
+	if len(spec.LogLevel) > 0 && !loglevel.ValidLogLevel(spec.LogLevel) {
+		errs = append(errs, fmt.Errorf("unknown logLevel %q", spec.LogLevel))
+	}
+	if len(spec.OperatorLogLevel) > 0 && !loglevel.ValidLogLevel(spec.OperatorLogLevel) {
+		errs = append(errs, fmt.Errorf("unknown operatorLogLevel %q", spec.OperatorLogLevel))
+	}
+	if len(spec.UnsupportedConfigOverrides.Raw) > 0 {
+		overrides := map[string]interface{}{}
+		if err := kyaml.Unmarshal(spec.UnsupportedConfigOverrides.Raw, &overrides); err != nil {
+			errs = append(errs, fmt.Errorf("unsupportedConfigOverrides is not an object: %v", err))
+		}
+	}
+	return errs
+}
+
+// invalidOperatorSpecConditionFor reports the errors of validateOperatorSpec.
+func invalidOperatorSpecConditionFor(errs []error) operatorv1.OperatorCondition {
+	if len(errs) == 0 {
+		return operatorv1.OperatorCondition{
+			Type:   invalidOperatorSpecCondition,
+			Status: operatorv1.ConditionFalse,
+		}
+	}
+	return operatorv1.OperatorCondition{
+		Type:    invalidOperatorSpecCondition,
+		Status:  operatorv1.ConditionTrue,
+		Reason:  "SpecRejected",
+		Message: "The kubecontrollermanager/cluster spec is rejected, nothing is rendered from it until it is fixed: " + v1helpers.NewMultiLineAggregate(errs).Error(),
+	}
+}
@@ -0,0 +1,58 @@
+package targetconfigcontroller
+
+import (
+	"testing"
+
+	"k8s.io/apimachinery/pkg/runtime"
+
+	operatorv1 "github.com/openshift/api/operator/v1"
+)
+
+func TestValidateOperatorSpec(t *testing.T) {
+	tests := []struct {
+		name           string
+		spec           operatorv1.OperatorSpec
+		expectedErrors int
+	}{
+		{
+			name: "empty",
+		},
+		{
+			name: "valid",
+			spec: operatorv1.OperatorSpec{
+				ManagementState:            operatorv1.Managed,
+				LogLevel:                   operatorv1.Debug,
+				OperatorLogLevel:           operatorv1.TraceAll,
+				UnsupportedConfigOverrides: runtime.RawExtension{Raw: []byte(`{"extendedArguments":{"v":["4"]}}`)},
+			},
+		},
+		{
+			name:           "unknown management state",
+			spec:           operatorv1.OperatorSpec{ManagementState: "Paused"},
+			expectedErrors: 1,
+		},
+		{
+			name:           "unknown log levels",
+			spec:           operatorv1.OperatorSpec{LogLevel: "Verbose", OperatorLogLevel: "Quiet"},
+			expectedErrors: 2,
+		},
+		{
+			name:           "overrides that are not an object",
+			spec:           operatorv1.OperatorSpec{UnsupportedConfigOverrides: runtime.RawExtension{Raw: []byte(`["a"]`)}},
+			expectedErrors: 1,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			errs := validateOperatorSpec(&operatorv1.StaticPodOperatorSpec{OperatorSpec: test.spec})
+			if len(errs) != test.expectedErrors {
+				t.Fatalf("expected %d errors, got %v", test.expectedErrors, errs)
+			}
+			condition := invalidOperatorSpecConditionFor(errs)
+			if expected := len(errs) > 0; expected != (condition.Status == operatorv1.ConditionTrue) {
+				t.Errorf("expected the condition to be %v, got %#v", expected, condition)
+			}
+		})
+	}
+}
@@ -6,9 +6,10 @@ import (
 	"crypto/x509"
 	"encoding/json"
 	"fmt"
-	"reflect"
 	"sort"
+	"strconv"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/ghodss/yaml"
@@ -16,41 +17,84 @@ import (
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/intstr"
 	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/client-go/dynamic"
 	"k8s.io/client-go/kubernetes"
 	corev1client "k8s.io/client-go/kubernetes/typed/core/v1"
 	corev1listers "k8s.io/client-go/listers/core/v1"
 	"k8s.io/client-go/tools/cache"
-	"k8s.io/client-go/util/cert"
 	"k8s.io/klog/v2"
 
 	"github.com/openshift/api/annotations"
 	kubecontrolplanev1 "github.com/openshift/api/kubecontrolplane/v1"
 	openshiftcontrolplanev1 "github.com/openshift/api/openshiftcontrolplane/v1"
 	operatorv1 "github.com/openshift/api/operator/v1"
-	configv1informers "github.com/openshift/client-go/config/informers/externalversions/config/v1"
-	configv1listers "github.com/openshift/client-go/config/listers/config/v1"
 	"github.com/openshift/library-go/pkg/controller/factory"
 	"github.com/openshift/library-go/pkg/crypto"
 	"github.com/openshift/library-go/pkg/operator/certrotation"
 	"github.com/openshift/library-go/pkg/operator/events"
+	"github.com/openshift/library-go/pkg/operator/loglevel"
 	"github.com/openshift/library-go/pkg/operator/management"
-	"github.com/openshift/library-go/pkg/operator/resource/resourceapply"
 	"github.com/openshift/library-go/pkg/operator/resource/resourcemerge"
 	"github.com/openshift/library-go/pkg/operator/resource/resourceread"
 	"github.com/openshift/library-go/pkg/operator/resourcesynccontroller"
 	"github.com/openshift/library-go/pkg/operator/v1helpers"
 
 	"github.com/openshift/cluster-kube-controller-manager-operator/bindata"
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/assetoverride"
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/cabundle"
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/certrecovery"
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/configobservation/schema"
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/csrsignerprovider"
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/flagdiff"
 	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/operatorclient"
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/tuning"
 	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/version"
 )
 
 const (
+	// Workers is the number of resources the controller syncs concurrently. The queue never hands the same resource
+	// to two workers, so a slow API call for one resource, e.g. on a loaded API server, doesn't hold up the others.
+	Workers = 4
+
 	ServingCertSecretAnnotation = "service.beta.openshift.io/serving-cert-secret-name"
+
+	// IncludeInCSRCABundleLabel set to "true" on a configmap in openshift-config adds the certificates in its
+	// ca-bundle.crt key to the CSR CA bundle (csr-controller-ca). Layered products use it to add intermediate CAs
+	// to the CSR trust chain.
+	IncludeInCSRCABundleLabel = "kubecontrollermanager.operator.openshift.io/include-in-csr-ca"
+
+	// metricsServerName is the server name prometheus uses to scrape the operand, see the kube-controller-manager-metrics service.
+	metricsServerName = "kube-controller-manager-metrics.openshift-kube-controller-manager.svc"
+
+	// observedConfigMissingCondition is reported while the observed config lacks paths required to render the operand.
+	observedConfigMissingCondition = "ObservedConfigMissing"
+	// observedConfigMissingGracePeriod is how long the observed config may be incomplete before the controller goes
+	// degraded. The config observer needs a few syncs to fill in the config during installs.
+	observedConfigMissingGracePeriod = 5 * time.Minute
+
+	// pausedConditionType is reported while the management of some resources is paused in the tuning config.
+	pausedConditionType = "TargetConfigControllerPaused"
 )
 
+// CSRSignerRequeueDelays are the delays ManageCSRSigner requeues a csr-signer rotation with.
+type CSRSignerRequeueDelays struct {
+	// TrustRecheckInterval is how often a rotated csr-signer waiting for the trust of the kube-apiserver is checked
+	// again.
+	TrustRecheckInterval time.Duration
+	// Padding is waited past the start of the validity of a csr-signer that is not valid yet.
+	Padding time.Duration
+}
+
+// DefaultCSRSignerRequeueDelays are used unless the tuning config overrides them.
+var DefaultCSRSignerRequeueDelays = CSRSignerRequeueDelays{
+	TrustRecheckInterval: time.Minute,
+	Padding:              10 * time.Second,
+}
+
 type TargetConfigController struct {
 	targetImagePullSpec             string
 	operatorImagePullSpec           string
@@ -60,10 +104,54 @@ type TargetConfigController struct {
 	operatorClient v1helpers.StaticPodOperatorClient
 	operatorLister cache.GenericLister
 
-	kubeClient          kubernetes.Interface
-	configMapLister     corev1listers.ConfigMapLister
-	secretLister        corev1listers.SecretLister
-	infrastuctureLister configv1listers.InfrastructureLister
+	kubeClient      kubernetes.Interface
+	configMapLister corev1listers.ConfigMapLister
+	secretLister    corev1listers.SecretLister
+	nodeLister      corev1listers.NodeLister
+	// serviceAccountLister only feeds the input fingerprint
+	serviceAccountLister corev1listers.ServiceAccountLister
+
+	syncers        []targetConfigSyncer
+	syncErrorsLock sync.Mutex
+	syncErrors     map[string]error
+	// syncFailures counts the consecutive failed syncs of every syncer
+	syncFailures  map[string]int32
+	dryRunChanges map[string][]string
+
+	scheduledSyncsLock sync.Mutex
+	scheduledSyncs     map[string]scheduledSync
+
+	syncFingerprintsLock sync.Mutex
+	syncFingerprints     map[string]syncFingerprint
+
+	// apiServerWaits are the reasons the kube-apiserver is unstable, by the syncer held back for it
+	apiServerWaitsLock sync.Mutex
+	apiServerWaits     map[string]string
+
+	// certRecovery tells whether the certRecoverySyncers go first, certRecoverySynced are those that synced since the
+	// cert recovery mode was entered
+	certRecovery       *certrecovery.Detector
+	certRecoveryLock   sync.Mutex
+	certRecoveryActive bool
+	certRecoverySynced sets.String
+
+	// inputs records the configmaps and secrets the syncers read for the inputStatusConfigMapName
+	inputs *inputTracker
+	// extraMountSecrets watches the secrets of the tuning ExtraMounts
+	extraMountSecrets *extraMountSecrets
+	// imageArchitectures inspects the operand images for the architectures of the control plane
+	imageArchitectures *imageArchitectures
+	// mutationReports keeps the ManagedResourceMutated events already emitted across syncs
+	mutationReports *mutationReports
+
+	// caBundles keeps the parsed inputs of the CA bundles across syncs
+	caBundles *cabundle.Registry
+	// trustedCABundle keeps the summary of the injected trusted CA bundle across syncs
+	trustedCABundle trustedCABundleCache
+	// verifyServer checks the server certificate against the service account root CA
+	verifyServer func(server string, caBundle []byte) error
+	// assets returns the pod manifest and the default config, overridden when the tech preview is enabled
+	assets *assetoverride.Source
 }
 
 func NewTargetConfigController(
@@ -72,30 +160,54 @@ func NewTargetConfigController(
 	operatorClient v1helpers.StaticPodOperatorClient,
 	operatorLister cache.GenericLister,
 	kubeClient kubernetes.Interface,
-	infrastuctureInformer configv1informers.InfrastructureInformer,
+	dynamicClient dynamic.Interface,
+	assets *assetoverride.Source,
+	certRecovery *certrecovery.Detector,
 	eventRecorder events.Recorder,
 ) factory.Controller {
+	inputs := newInputTracker()
 	c := &TargetConfigController{
 		targetImagePullSpec:             targetImagePullSpec,
 		operatorImagePullSpec:           operatorImagePullSpec,
 		clusterPolicyControllerPullSpec: clusterPolicyControllerPullSpec,
 		toolsImagePullSpec:              toolsImagePullSpec,
 
-		configMapLister:     kubeInformersForNamespaces.ConfigMapLister(),
-		secretLister:        kubeInformersForNamespaces.SecretLister(),
-		infrastuctureLister: infrastuctureInformer.Lister(),
-		operatorClient:      operatorClient,
-		operatorLister:      operatorLister,
-		kubeClient:          kubeClient,
+		configMapLister: &inputRecordingConfigMapLister{ConfigMapLister: kubeInformersForNamespaces.ConfigMapLister(), inputs: inputs},
+		secretLister:    &inputRecordingSecretLister{SecretLister: kubeInformersForNamespaces.SecretLister(), inputs: inputs},
+		operatorClient:  operatorClient,
+		operatorLister:  operatorLister,
+		kubeClient:      kubeClient,
+
+		// nodes are not watched, their status changes too often. The architectures are picked up on resync.
+		nodeLister:           kubeInformersForNamespaces.InformersFor("").Core().V1().Nodes().Lister(),
+		serviceAccountLister: kubeInformersForNamespaces.InformersFor(operatorclient.TargetNamespace).Core().V1().ServiceAccounts().Lister(),
+
+		syncErrors:     map[string]error{},
+		syncFailures:   map[string]int32{},
+		dryRunChanges:  map[string][]string{},
+		scheduledSyncs: map[string]scheduledSync{},
+
+		syncFingerprints: map[string]syncFingerprint{},
+		apiServerWaits:   map[string]string{},
+
+		certRecovery:       certRecovery,
+		certRecoverySynced: sets.NewString(),
+
+		inputs:             inputs,
+		extraMountSecrets:  newExtraMountSecrets(kubeClient),
+		imageArchitectures: newImageArchitectures(dynamicClient),
+		mutationReports:    newMutationReports(),
+
+		caBundles:    cabundle.NewRegistry(),
+		verifyServer: cabundle.VerifyServerCertificate,
+		assets:       assets,
 	}
+	c.syncers = c.newSyncers()
 
 	return factory.New().WithInformers(
 		// this is for our general configuration input and our status output in case another actor changes it
 		operatorClient.Informer(),
 
-		// We use infrastuctureInformer for observing load balancer URL
-		infrastuctureInformer.Informer(),
-
 		// these are for watching our outputs in case someone changes them
 		kubeInformersForNamespaces.InformersFor(operatorclient.TargetNamespace).Core().V1().ConfigMaps().Informer(),
 		kubeInformersForNamespaces.InformersFor(operatorclient.TargetNamespace).Core().V1().Secrets().Informer(),
@@ -116,51 +228,194 @@ func NewTargetConfigController(
 	).ResyncEvery(time.Minute).WithSync(c.sync).ToController("TargetConfigController", eventRecorder)
 }
 
-func (c TargetConfigController) sync(ctx context.Context, syncCtx factory.SyncContext) error {
-	operatorSpec, _, _, err := c.operatorClient.GetStaticPodOperatorStateWithQuorum(ctx)
+func (c *TargetConfigController) sync(ctx context.Context, syncCtx factory.SyncContext) error {
+	// informer events and resyncs fan out to every resource, each resource is then synced and retried on its own key
+	if syncCtx.QueueKey() == factory.DefaultQueueKey {
+		if err := c.queueSyncers(ctx, syncCtx, time.Now()); err != nil {
+			return err
+		}
+		if err := c.updateOperatorConditions(ctx, syncCtx); err != nil {
+			return err
+		}
+		// the inputs read by the syncers of the previous fan-out
+		return c.publishInputStatus(ctx, c.kubeClient.CoreV1(), syncCtx.Recorder(), time.Now())
+	}
+	syncer, ok := c.syncerFor(syncCtx.QueueKey())
+	if !ok {
+		return nil
+	}
+	// the events and log lines of this pass carry its ID, the operator resource version ties it to the input
+	ctx, syncCtx = withSyncID(ctx, syncCtx, syncer.resource)
+
+	// the fan-out read the operator with quorum and reported the conditions shared by the resources, a resource only
+	// reads the cached operator and writes nothing before the no-op check
+	operatorSpec, operatorStatus, resourceVersion, err := c.operatorClient.GetStaticPodOperatorState()
 	if err != nil {
 		return err
 	}
+	klog.FromContext(ctx).V(4).Info("Syncing target config", "operatorResourceVersion", resourceVersion)
 
+	// an unknown management state would otherwise be treated as Managed
+	if len(validateOperatorSpec(operatorSpec)) > 0 {
+		return nil
+	}
 	if !management.IsOperatorManaged(operatorSpec.ManagementState) {
 		return nil
 	}
+	// the certRecoverySyncers queue the syncer again once they synced
+	if c.waitsForCertRecovery(syncer.name) {
+		klog.FromContext(ctx).V(4).Info("Skipping target config sync until the csr-signer and the service account trust are recovered")
+		return nil
+	}
 
 	// block until config is observed and specific paths are present
 	if err := isRequiredConfigPresent(operatorSpec.ObservedConfig.Raw); err != nil {
-		syncCtx.Recorder().Warning("ConfigMissing", err.Error())
 		return err
 	}
 
-	// TODO this entire block should become a configobserver, but that requires changes to the observedconfig format.
-	//  I would do that in 4.9, not 4.8.
-	// we need to get at the content of the kcm operator resource itself.  The operatorClient should be improved to return this
-	uncastOperator, err := c.operatorLister.Get("cluster")
+	// an unreadable tuning config may have dropped a pause, so nothing is synced until it is fixed
+	tuningConfig, err := tuning.Get(c.configMapLister)
+	if err != nil {
+		syncErr := fmt.Errorf("%q: %v", "configmap/"+tuning.ConfigMapName, err)
+		if err := c.updateDegradedCondition(ctx, syncer.name, syncErr, tuning.DefaultDegradedSyncThreshold); err != nil {
+			return err
+		}
+		return syncErr
+	}
+	degradedSyncThreshold := tuningConfig.DegradedSyncThresholdOrDefault()
+	if sets.NewString(tuningConfig.PausedResources...).Has(syncer.name) {
+		// the previous errors of a paused resource are stale
+		return c.updateDegradedCondition(ctx, syncer.name, nil, degradedSyncThreshold)
+	}
+	// a malformed or rejected rollback annotation is reported by the RollbackController and restores nothing
+	if rollbackRevision, ok := rollbackInEffect(c.operatorLister, operatorStatus); ok && rollbackSyncers.Has(syncer.name) {
+		klog.FromContext(ctx).V(4).Info("Skipping target config sync during the rollback", "revision", rollbackRevision)
+		return c.updateDegradedCondition(ctx, syncer.name, nil, degradedSyncThreshold)
+	}
+
+	dryRun, err := isDryRun(c.operatorLister)
 	if err != nil {
 		return err
 	}
-	rawOperator := uncastOperator.(*unstructured.Unstructured)
-	kcmOperator := &operatorv1.KubeControllerManager{}
-	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(rawOperator.UnstructuredContent(), kcmOperator); err != nil {
+	// a delayed sync is due once the syncer runs, the syncer schedules the next one if it still waits
+	c.clearScheduledSync(syncer.name)
+	if !dryRun {
+		fingerprint, err := c.inputFingerprint(operatorSpec, operatorStatus)
+		if err != nil {
+			return err
+		}
+		recovering := c.isCertRecoverySync(syncer.name, time.Now())
+		if !recovering && c.isNoOpSync(syncer.name, fingerprint, time.Now()) {
+			klog.FromContext(ctx).V(4).Info("Skipping target config sync, the inputs did not change")
+			return nil
+		}
+		if !recovering {
+			heldBack, err := c.holdBackForUnstableAPIServer(ctx, syncCtx, syncer, operatorSpec, operatorStatus.LatestAvailableRevision)
+			if err != nil {
+				return err
+			}
+			if heldBack {
+				return c.updateSyncScheduledCondition(ctx)
+			}
+		}
+		var client corev1client.CoreV1Interface = newInputRecordingClient(c.kubeClient.CoreV1(), c.inputs)
+		if syncer.verifyContent {
+			client = newContentHashClient(client, syncCtx.Recorder(), strings.TrimPrefix(syncer.resource, "configmap/"), c.mutationReports)
+		}
+		syncErr := syncer.sync(ctx, syncCtx, client, operatorSpec)
+		if syncErr != nil {
+			syncErr = fmt.Errorf("%q: %v", syncer.resource, syncErr)
+		}
+		c.recordSyncFingerprint(syncer.name, fingerprint, syncErr, time.Now())
+		if syncErr == nil && recovering {
+			c.recordCertRecoverySync(syncCtx, syncer.name)
+		}
+		if err := c.updateSyncScheduledCondition(ctx); err != nil {
+			return err
+		}
+		if err := c.updateDegradedCondition(ctx, syncer.name, syncErr, degradedSyncThreshold); err != nil {
+			return err
+		}
+		return syncErr
+	}
+
+	// the degraded condition is left alone, a dry-run doesn't fix or break the real resources
+	client := newDryRunClient(newInputRecordingClient(c.kubeClient.CoreV1(), c.inputs))
+	syncErr := syncer.sync(ctx, dryRunSyncContext{SyncContext: syncCtx}, client, operatorSpec)
+	if syncErr != nil {
+		syncErr = fmt.Errorf("%q: %v", syncer.resource, syncErr)
+	}
+	if err := c.updateSyncScheduledCondition(ctx); err != nil {
 		return err
 	}
-	// because of the way the injector ratchets and only allows injecting the "secure" approach, we only need see a positive value.
-	// in the case of an upgraded cluster, this value is empty to begin and may eventually become "good".
-	// in the case of a new cluster, the first instance ever created will be "good", so there is no possibility to accidentally create a "bad" set of flags.
-	useSecureServiceCA := kcmOperator.Spec.UseMoreSecureServiceCA
+	if err := c.updateDryRunCondition(ctx, syncer.name, client.Changes()); err != nil {
+		return err
+	}
+	return syncErr
+}
 
-	requeue, err := createTargetConfigController(ctx, syncCtx, c, operatorSpec, useSecureServiceCA)
+// updateOperatorConditions reports the conditions shared by every resource once per fan-out: the validation of the
+// operator spec, the observed config, the override conflicts, the asset overrides, the end of a dry-run and the paused
+// resources.
+func (c *TargetConfigController) updateOperatorConditions(ctx context.Context, syncCtx factory.SyncContext) error {
+	operatorSpec, operatorStatus, _, err := c.operatorClient.GetStaticPodOperatorStateWithQuorum(ctx)
 	if err != nil {
 		return err
 	}
 
-	if requeue {
-		return factory.SyntheticRequeueError
+	specErrs := validateOperatorSpec(operatorSpec)
+	specCondition := invalidOperatorSpecConditionFor(specErrs)
+	if len(specErrs) > 0 {
+		if previous := v1helpers.FindOperatorCondition(operatorStatus.Conditions, invalidOperatorSpecCondition); previous == nil || previous.Message != specCondition.Message {
+			syncCtx.Recorder().Warning("OperatorSpecRejected", specCondition.Message)
+		}
+	}
+	if _, _, err := v1helpers.UpdateStaticPodStatus(ctx, c.operatorClient, v1helpers.UpdateStaticPodConditionFn(specCondition)); err != nil {
+		return err
+	}
+	if len(specErrs) > 0 || !management.IsOperatorManaged(operatorSpec.ManagementState) {
+		return nil
 	}
 
-	return nil
-}
+	if err := isRequiredConfigPresent(operatorSpec.ObservedConfig.Raw); err != nil {
+		syncCtx.Recorder().Warning("ConfigMissing", err.Error())
+		var updateFuncs []v1helpers.UpdateStaticPodStatusFunc
+		for _, condition := range observedConfigMissingConditions(operatorStatus, err, time.Now()) {
+			updateFuncs = append(updateFuncs, v1helpers.UpdateStaticPodConditionFn(condition))
+		}
+		_, _, updateErr := v1helpers.UpdateStaticPodStatus(ctx, c.operatorClient, updateFuncs...)
+		return updateErr
+	}
+	if _, _, err := v1helpers.UpdateStaticPodStatus(ctx, c.operatorClient, v1helpers.UpdateStaticPodConditionFn(operatorv1.OperatorCondition{
+		Type:   observedConfigMissingCondition,
+		Status: operatorv1.ConditionFalse,
+	})); err != nil {
+		return err
+	}
+	if err := c.updateOverrideConflictCondition(ctx, operatorSpec); err != nil {
+		return err
+	}
+	if err := c.updateAssetOverridesCondition(ctx); err != nil {
+		return err
+	}
+	dryRun, err := isDryRun(c.operatorLister)
+	if err != nil {
+		return err
+	}
+	if !dryRun {
+		if err := c.clearDryRunCondition(ctx); err != nil {
+			return err
+		}
+	}
 
+	// an unreadable tuning config is reported in the degraded condition of every resource
+	tuningConfig, err := tuning.Get(c.configMapLister)
+	if err != nil {
+		return nil
+	}
+	_, _, err = v1helpers.UpdateStaticPodStatus(ctx, c.operatorClient, v1helpers.UpdateStaticPodConditionFn(pausedCondition(c.syncers, tuningConfig.PausedResources)))
+	return err
+}
 func isRequiredConfigPresent(config []byte) error {
 	if len(config) == 0 {
 		return fmt.Errorf("no observedConfig")
@@ -199,48 +454,332 @@ func isRequiredConfigPresent(config []byte) error {
 	return nil
 }
 
-// createTargetConfigController takes care of synchronizing (not upgrading) the thing we're managing.
-func createTargetConfigController(ctx context.Context, syncCtx factory.SyncContext, c TargetConfigController, operatorSpec *operatorv1.StaticPodOperatorSpec, useSecureServiceCA bool) (bool, error) {
-	errors := []error{}
+// observedConfigMissingConditions returns the conditions to report when the observed config is incomplete.
+// ObservedConfigMissing names the missing path right away, while TargetConfigControllerDegraded is only raised once
+// the config has been missing for longer than observedConfigMissingGracePeriod, to avoid flapping during installs.
+func observedConfigMissingConditions(status *operatorv1.StaticPodOperatorStatus, missingErr error, now time.Time) []operatorv1.OperatorCondition {
+	missingSince := now
+	if existing := v1helpers.FindOperatorCondition(status.Conditions, observedConfigMissingCondition); existing != nil && existing.Status == operatorv1.ConditionTrue {
+		missingSince = existing.LastTransitionTime.Time
+	}
 
-	_, _, err := manageKubeControllerManagerConfig(ctx, c.kubeClient.CoreV1(), syncCtx.Recorder(), operatorSpec)
-	if err != nil {
-		errors = append(errors, fmt.Errorf("%q: %v", "configmap", err))
+	// the message only carries the start time so that it stays stable across syncs
+	message := fmt.Sprintf("%v since %s", missingErr, missingSince.UTC().Format(time.RFC3339))
+	conditions := []operatorv1.OperatorCondition{
+		{
+			Type:    observedConfigMissingCondition,
+			Status:  operatorv1.ConditionTrue,
+			Reason:  "RequiredConfigMissing",
+			Message: message,
+		},
 	}
-	_, _, err = manageClusterPolicyControllerConfig(ctx, c.kubeClient.CoreV1(), syncCtx.Recorder(), operatorSpec)
-	if err != nil {
-		errors = append(errors, fmt.Errorf("%q: %v", "configmap/cluster-policy-controller-config", err))
+	if now.Sub(missingSince) >= observedConfigMissingGracePeriod {
+		conditions = append(conditions, operatorv1.OperatorCondition{
+			Type:    "TargetConfigControllerDegraded",
+			Status:  operatorv1.ConditionTrue,
+			Reason:  "ObservedConfigMissing",
+			Message: message,
+		})
+	}
+	return conditions
+}
+
+// targetConfigSyncer synchronizes (not upgrades) one of the resources we're managing. Every syncer runs under its own
+// queue key, so a failing resource is retried with its own backoff without re-applying all the others.
+type targetConfigSyncer struct {
+	// name is the queue key of the syncer
+	name string
+	// resource is reported in the degraded condition when the sync fails
+	resource string
+	// verifyContent marks a configmap rendered entirely from the operator assets, which nobody else is expected to
+	// modify. Its content hash is tracked in the ContentHashAnnotation.
+	verifyContent bool
+	sync          func(ctx context.Context, syncCtx factory.SyncContext, client corev1client.CoreV1Interface, operatorSpec *operatorv1.StaticPodOperatorSpec) error
+}
+
+func (c *TargetConfigController) newSyncers() []targetConfigSyncer {
+	return []targetConfigSyncer{
+		{name: "config", resource: "configmap/config", verifyContent: true, sync: c.syncKubeControllerManagerConfig},
+		{name: "cluster-policy-controller-config", resource: "configmap/cluster-policy-controller-config", verifyContent: true, sync: c.syncClusterPolicyControllerConfig},
+		{name: "recycler-config", resource: "configmap/recycler-config", verifyContent: true, sync: c.syncRecycler},
+		{name: "extra-mounts", resource: "configmap/extra-mounts", sync: c.syncExtraMounts},
+		{name: "csr-signer", resource: "secrets/csr-signer", sync: c.syncCSRSigner},
+		{name: "serviceaccount-ca", resource: "configmap/serviceaccount-ca", sync: c.syncServiceAccountCABundle},
+		{name: "serviceaccount-root-ca", resource: "configmap/serviceaccount-root-ca", sync: c.syncServiceAccountRootCA},
+		{name: "localhost-recovery-client", resource: "serviceaccount/localhost-recovery-client", sync: c.syncLocalhostRecoverySAToken},
+		{name: "trusted-ca-bundle", resource: "configmap/trusted-ca-bundle", sync: c.syncTrustedCA},
+		{name: "cert-syncer-kubeconfig", resource: "configmap/kube-controller-cert-syncer-kubeconfig", sync: c.syncCertSyncerKubeconfig},
+		{name: "pod", resource: "configmap/kube-controller-manager-pod", verifyContent: true, sync: c.syncPod},
+		{name: "observed-config-schema", resource: "configmap/" + schema.ConfigMapName, sync: c.syncObservedConfigSchema},
+	}
+}
+
+func (c *TargetConfigController) syncerFor(name string) (targetConfigSyncer, bool) {
+	for _, syncer := range c.syncers {
+		if syncer.name == name {
+			return syncer, true
+		}
 	}
-	_, _, err = manageRecycler(ctx, c.kubeClient.CoreV1(), syncCtx.Recorder(), c.toolsImagePullSpec)
+	return targetConfigSyncer{}, false
+}
+
+// updateDegradedCondition records the result of a syncer and reports the errors of all the syncers in
+// TargetConfigControllerDegraded. The error of a syncer is only reported once it failed threshold consecutive syncs.
+func (c *TargetConfigController) updateDegradedCondition(ctx context.Context, name string, syncErr error, threshold int32) error {
+	errors := c.recordSyncError(name, syncErr, threshold)
+	if len(errors) > 0 {
+		condition := operatorv1.OperatorCondition{
+			Type:    "TargetConfigControllerDegraded",
+			Status:  operatorv1.ConditionTrue,
+			Reason:  "SynchronizationError",
+			Message: v1helpers.NewMultiLineAggregate(errors).Error(),
+		}
+		_, _, err := v1helpers.UpdateStaticPodStatus(ctx, c.operatorClient, v1helpers.UpdateStaticPodConditionFn(condition))
+		return err
+	}
+
+	if err := removeCloudControllerOwnerCondition(ctx, c); err != nil {
+		return err
+	}
+
+	condition := operatorv1.OperatorCondition{
+		Type:   "TargetConfigControllerDegraded",
+		Status: operatorv1.ConditionFalse,
+	}
+	_, _, err := v1helpers.UpdateStaticPodStatus(ctx, c.operatorClient, v1helpers.UpdateStaticPodConditionFn(condition))
+	return err
+}
+
+// pausedCondition reports the resources whose management is paused in the tuning config.
+func pausedCondition(syncers []targetConfigSyncer, pausedResources []string) operatorv1.OperatorCondition {
+	paused := sets.NewString(pausedResources...)
+	var resources []string
+	for _, syncer := range syncers {
+		if paused.Has(syncer.name) {
+			resources = append(resources, syncer.resource)
+			paused.Delete(syncer.name)
+		}
+	}
+	if len(resources) == 0 && paused.Len() == 0 {
+		return operatorv1.OperatorCondition{
+			Type:   pausedConditionType,
+			Status: operatorv1.ConditionFalse,
+		}
+	}
+
+	message := fmt.Sprintf("The management of %s is paused", strings.Join(resources, ", "))
+	if len(resources) == 0 {
+		message = "No resource is paused"
+	}
+	if paused.Len() > 0 {
+		message += fmt.Sprintf(", unknown paused resources: %s", strings.Join(paused.List(), ", "))
+	}
+	return operatorv1.OperatorCondition{
+		Type:    pausedConditionType,
+		Status:  operatorv1.ConditionTrue,
+		Reason:  "PausedByUser",
+		Message: message,
+	}
+}
+
+// recordSyncError records the result of a syncer and returns the errors of all the syncers, in syncer order.
+// The syncers run on several workers, so this is the only place the errors are touched.
+func (c *TargetConfigController) recordSyncError(name string, syncErr error, threshold int32) []error {
+	c.syncErrorsLock.Lock()
+	defer c.syncErrorsLock.Unlock()
+
+	if syncErr != nil {
+		c.syncFailures[name]++
+		_, reported := c.syncErrors[name]
+		if reported || c.syncFailures[name] >= threshold {
+			c.syncErrors[name] = syncErr
+		} else {
+			klog.V(2).Infof("Not reporting the failed sync %d of %d of %s yet: %v", c.syncFailures[name], threshold, name, syncErr)
+		}
+	} else {
+		delete(c.syncFailures, name)
+		delete(c.syncErrors, name)
+	}
+	var errors []error
+	for _, syncer := range c.syncers {
+		if err := c.syncErrors[syncer.name]; err != nil {
+			errors = append(errors, err)
+		}
+	}
+	return errors
+}
+
+// updateDryRunCondition records the resources a syncer would change and reports the changes of all the syncers in
+// TargetConfigControllerDryRun.
+func (c *TargetConfigController) updateDryRunCondition(ctx context.Context, name string, changes []string) error {
+	c.syncErrorsLock.Lock()
+	c.dryRunChanges[name] = changes
+	message := dryRunMessage(c.syncers, c.dryRunChanges)
+	c.syncErrorsLock.Unlock()
+
+	condition := operatorv1.OperatorCondition{
+		Type:    dryRunCondition,
+		Status:  operatorv1.ConditionTrue,
+		Reason:  "DryRun",
+		Message: message,
+	}
+	_, _, err := v1helpers.UpdateStaticPodStatus(ctx, c.operatorClient, v1helpers.UpdateStaticPodConditionFn(condition))
+	return err
+}
+
+// clearDryRunCondition forgets the changes of a previous dry-run and removes its condition.
+func (c *TargetConfigController) clearDryRunCondition(ctx context.Context) error {
+	c.syncErrorsLock.Lock()
+	c.dryRunChanges = map[string][]string{}
+	c.syncErrorsLock.Unlock()
+
+	_, status, _, err := c.operatorClient.GetStaticPodOperatorState()
 	if err != nil {
-		errors = append(errors, fmt.Errorf("%q: %v", "configmap/recycler-config", err))
+		return err
+	}
+	if v1helpers.FindOperatorCondition(status.Conditions, dryRunCondition) == nil {
+		return nil
 	}
-	_, _, err = ManageCSRIntermediateCABundle(ctx, c.secretLister, c.kubeClient.CoreV1(), syncCtx.Recorder())
+	_, _, err = v1helpers.UpdateStaticPodStatus(ctx, c.operatorClient, func(oldStatus *operatorv1.StaticPodOperatorStatus) error {
+		v1helpers.RemoveOperatorCondition(&oldStatus.Conditions, dryRunCondition)
+		return nil
+	})
+	return err
+}
+
+func (c *TargetConfigController) syncKubeControllerManagerConfig(ctx context.Context, syncCtx factory.SyncContext, client corev1client.CoreV1Interface, operatorSpec *operatorv1.StaticPodOperatorSpec) error {
+	_, _, err := manageKubeControllerManagerConfig(ctx, c.assets, client, syncCtx.Recorder(), operatorSpec)
+	return err
+}
+
+func (c *TargetConfigController) syncClusterPolicyControllerConfig(ctx context.Context, syncCtx factory.SyncContext, client corev1client.CoreV1Interface, operatorSpec *operatorv1.StaticPodOperatorSpec) error {
+	_, _, err := manageClusterPolicyControllerConfig(ctx, client, syncCtx.Recorder(), operatorSpec)
+	return err
+}
+
+func (c *TargetConfigController) syncRecycler(ctx context.Context, syncCtx factory.SyncContext, client corev1client.CoreV1Interface, _ *operatorv1.StaticPodOperatorSpec) error {
+	_, _, err := manageRecycler(ctx, client, syncCtx.Recorder(), c.toolsImagePullSpec)
+	return err
+}
+
+// syncCSRSigner manages the csr-signer secret together with the CA bundles derived from it. With an external
+// provider only the CA bundles are managed, from the certificate of the provider, the kube-controller-manager reads
+// the signer from the provider once the observed config points it there.
+func (c *TargetConfigController) syncCSRSigner(ctx context.Context, syncCtx factory.SyncContext, client corev1client.CoreV1Interface, _ *operatorv1.StaticPodOperatorSpec) error {
+	tuningConfig, err := tuning.Get(c.configMapLister)
 	if err != nil {
+		return fmt.Errorf("%q: %v", "configmap/"+tuning.ConfigMapName, err)
+	}
+	provider := csrsignerprovider.New(tuningConfig.CSRSigner)
+
+	errors := []error{}
+	if provider != nil {
+		if _, _, err := manageProviderCSRIntermediateCABundle(ctx, c.caBundles, c.configMapLister, client, syncCtx.Recorder(), provider); err != nil {
+			errors = append(errors, fmt.Errorf("%q: %v", "configmap/csr-intermediate-ca", err))
+		}
+	} else if _, _, err := ManageCSRIntermediateCABundle(ctx, c.caBundles, c.secretLister, client, syncCtx.Recorder()); err != nil {
 		errors = append(errors, fmt.Errorf("%q: %v", "configmap/csr-intermediate-ca", err))
 	}
-	_, _, err = ManageCSRCABundle(ctx, c.configMapLister, c.kubeClient.CoreV1(), syncCtx.Recorder())
-	if err != nil {
+	if _, _, err := ManageCSRCABundle(ctx, c.caBundles, c.configMapLister, client, syncCtx.Recorder()); err != nil {
 		errors = append(errors, fmt.Errorf("%q: %v", "configmap/csr-controller-ca", err))
 	}
-	_, requeueDelay, _, err := ManageCSRSigner(ctx, c.secretLister, c.kubeClient.CoreV1(), syncCtx.Recorder())
+	if provider == nil {
+		delays := csrSignerRequeueDelays(tuningConfig)
+		if c.isCertRecoverySync("csr-signer", time.Now()) {
+			delays = certRecoveryCSRSignerRequeueDelays(delays)
+		}
+		_, requeueDelay, _, err := ManageCSRSigner(ctx, c.secretLister, c.configMapLister, client, syncCtx.Recorder(), delays)
+		if err != nil {
+			errors = append(errors, err)
+		}
+		if requeueDelay > 0 {
+			c.scheduleSync(syncCtx, requeueDelay, "the rotated csr-signer is not valid or not trusted by the kube-apiserver yet")
+		}
+	}
+	now := time.Now()
+	if _, _, err := v1helpers.UpdateStaticPodStatus(ctx, c.operatorClient,
+		v1helpers.UpdateStaticPodConditionFn(c.csrSignerTrustCondition(provider, now)),
+		v1helpers.UpdateStaticPodConditionFn(c.clockSkewCondition(now)),
+	); err != nil {
+		errors = append(errors, err)
+	}
+	return v1helpers.NewMultiLineAggregate(errors)
+}
+
+func (c *TargetConfigController) syncServiceAccountCABundle(ctx context.Context, syncCtx factory.SyncContext, client corev1client.CoreV1Interface, _ *operatorv1.StaticPodOperatorSpec) error {
+	tuningConfig, err := tuning.Get(c.configMapLister)
+	if err != nil {
+		return fmt.Errorf("%q: %v", "configmap/"+tuning.ConfigMapName, err)
+	}
+	sources, availability, err := serviceAccountCASources(c.configMapLister, tuningConfig)
+	if availability != nil {
+		if _, _, updateErr := v1helpers.UpdateStaticPodStatus(ctx, c.operatorClient, v1helpers.UpdateStaticPodConditionFn(serviceAccountCASourceCondition(availability))); updateErr != nil {
+			return updateErr
+		}
+	}
+	if err != nil {
+		return err
+	}
+	// the kube-controller-manager reads the root CA file on startup only, every change rolls out a revision
+	required, err := serviceAccountCABundle(c.caBundles, c.configMapLister, syncCtx.Recorder(), sources)
 	if err != nil {
-		errors = append(errors, fmt.Errorf("%q: %v", "secrets/csr-signer", err))
+		return err
+	}
+	// a trust change is written right away, the operand must verify the tokens of a rotated CA as soon as it can
+	if err := c.removeCertOnlyRolloutDeferredCondition(ctx); err != nil {
+		return err
 	}
-	if requeueDelay > 0 {
-		syncCtx.Queue().AddAfter(syncCtx.QueueKey(), requeueDelay)
+	_, _, err = ApplyConfigMap(ctx, client, syncCtx.Recorder(), required)
+	return err
+}
+
+func (c *TargetConfigController) syncLocalhostRecoverySAToken(ctx context.Context, syncCtx factory.SyncContext, client corev1client.CoreV1Interface, _ *operatorv1.StaticPodOperatorSpec) error {
+	return ensureLocalhostRecoverySAToken(ctx, client, syncCtx.Recorder())
+}
+
+func (c *TargetConfigController) syncTrustedCA(ctx context.Context, syncCtx factory.SyncContext, client corev1client.CoreV1Interface, _ *operatorv1.StaticPodOperatorSpec) error {
+	if err := ensureKubeControllerManagerTrustedCA(ctx, client, syncCtx.Recorder()); err != nil {
+		return err
 	}
-	_, _, err = manageServiceAccountCABundle(ctx, c.configMapLister, c.kubeClient.CoreV1(), syncCtx.Recorder())
+	_, _, err := v1helpers.UpdateStaticPodStatus(ctx, c.operatorClient, v1helpers.UpdateStaticPodConditionFn(c.trustedCABundleCondition(time.Now())))
+	return err
+}
+
+// syncObservedConfigSchema publishes the schema of the observed config, which shows what the pruning of the rendered
+// configs keeps of every observed path.
+func (c *TargetConfigController) syncObservedConfigSchema(ctx context.Context, syncCtx factory.SyncContext, client corev1client.CoreV1Interface, _ *operatorv1.StaticPodOperatorSpec) error {
+	observedConfigSchema, err := schema.JSONSchema()
 	if err != nil {
-		errors = append(errors, fmt.Errorf("%q: %v", "configmap/serviceaccount-ca", err))
+		return err
 	}
-	err = ensureLocalhostRecoverySAToken(ctx, c.kubeClient.CoreV1(), syncCtx.Recorder())
+	_, _, err = ApplyConfigMap(ctx, client, syncCtx.Recorder(), &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Namespace: operatorclient.OperatorNamespace, Name: schema.ConfigMapName},
+		Data:       map[string]string{schema.ConfigKey: string(observedConfigSchema)},
+	})
+	return err
+}
+
+func (c *TargetConfigController) syncPod(ctx context.Context, syncCtx factory.SyncContext, client corev1client.CoreV1Interface, operatorSpec *operatorv1.StaticPodOperatorSpec) error {
+	// TODO this entire block should become a configobserver, but that requires changes to the observedconfig format.
+	//  I would do that in 4.9, not 4.8.
+	// we need to get at the content of the kcm operator resource itself.  The operatorClient should be improved to return this
+	uncastOperator, err := c.operatorLister.Get("cluster")
 	if err != nil {
-		errors = append(errors, fmt.Errorf("%q: %v", "serviceaccount/localhost-recovery-client", err))
+		return err
+	}
+	rawOperator := uncastOperator.(*unstructured.Unstructured)
+	kcmOperator := &operatorv1.KubeControllerManager{}
+	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(rawOperator.UnstructuredContent(), kcmOperator); err != nil {
+		return err
 	}
-	_, _, err = manageControllerManagerKubeconfig(ctx, c.kubeClient.CoreV1(), c.infrastuctureLister, syncCtx.Recorder())
+	// because of the way the injector ratchets and only allows injecting the "secure" approach, we only need see a positive value.
+	// in the case of an upgraded cluster, this value is empty to begin and may eventually become "good".
+	// in the case of a new cluster, the first instance ever created will be "good", so there is no possibility to accidentally create a "bad" set of flags.
+	useSecureServiceCA := kcmOperator.Spec.UseMoreSecureServiceCA
+
+	tuningConfig, err := tuning.Get(c.configMapLister)
 	if err != nil {
-		errors = append(errors, fmt.Errorf("%q: %v", "configmap/controller-manager-kubeconfig", err))
+		return fmt.Errorf("%q: %v", "configmap/"+tuning.ConfigMapName, err)
 	}
 
 	// Allow the addition of the service ca to token secrets to be enabled by setting an
@@ -255,20 +794,9 @@ func createTargetConfigController(ctx context.Context, syncCtx factory.SyncConte
 			EnableDeprecatedAndRemovedServiceCAKeyUntilNextRelease_ThisMakesClusterImpossibleToUpgrade bool
 		}{}
 		if err := json.Unmarshal(operatorSpec.UnsupportedConfigOverrides.Raw, &cmConfigOverride); err != nil {
-			errors = append(errors, fmt.Errorf("failed to load EnableDeprecatedAndRemovedServiceCAKeyUntilNextRelease_ThisMakesClusterImpossibleToUpgrade from UnsupportedConfigOverride: %v", err))
-		} else {
-			addServingServiceCAToTokenSecrets = cmConfigOverride.EnableDeprecatedAndRemovedServiceCAKeyUntilNextRelease_ThisMakesClusterImpossibleToUpgrade
+			return fmt.Errorf("failed to load EnableDeprecatedAndRemovedServiceCAKeyUntilNextRelease_ThisMakesClusterImpossibleToUpgrade from UnsupportedConfigOverride: %v", err)
 		}
-	}
-
-	_, _, err = managePod(ctx, c.kubeClient.CoreV1(), c.kubeClient.CoreV1(), syncCtx.Recorder(), operatorSpec, c.targetImagePullSpec, c.operatorImagePullSpec, c.clusterPolicyControllerPullSpec, addServingServiceCAToTokenSecrets, useSecureServiceCA)
-	if err != nil {
-		errors = append(errors, fmt.Errorf("%q: %v", "configmap/kube-controller-manager-pod", err))
-	}
-
-	err = ensureKubeControllerManagerTrustedCA(ctx, c.kubeClient.CoreV1(), syncCtx.Recorder())
-	if err != nil {
-		errors = append(errors, fmt.Errorf("%q: %v", "configmap/trusted-ca-bundle", err))
+		addServingServiceCAToTokenSecrets = cmConfigOverride.EnableDeprecatedAndRemovedServiceCAKeyUntilNextRelease_ThisMakesClusterImpossibleToUpgrade
 	}
 
 	// The operator is not upgradeable if serving service CA addition to token secrets is enabled
@@ -291,35 +819,37 @@ func createTargetConfigController(ctx context.Context, syncCtx factory.SyncConte
 		}
 	}
 	if _, _, err := v1helpers.UpdateStaticPodStatus(ctx, c.operatorClient, v1helpers.UpdateStaticPodConditionFn(upgradeableCondition)); err != nil {
-		return true, err
+		return err
 	}
 
-	if len(errors) > 0 {
-		condition := operatorv1.OperatorCondition{
-			Type:    "TargetConfigControllerDegraded",
-			Status:  operatorv1.ConditionTrue,
-			Reason:  "SynchronizationError",
-			Message: v1helpers.NewMultiLineAggregate(errors).Error(),
-		}
-		if _, _, err := v1helpers.UpdateStaticPodStatus(ctx, c.operatorClient, v1helpers.UpdateStaticPodConditionFn(condition)); err != nil {
-			return true, err
-		}
-		return true, nil
+	images, err := c.resolveOperandImages(ctx, tuningConfig)
+	if err != nil {
+		return err
 	}
-
-	if err = removeCloudControllerOwnerCondition(ctx, c); err != nil {
-		return true, err
+	if _, _, err := managePod(ctx, c.assets, client, client, syncCtx.Recorder(), operatorSpec, tuningConfig, images.KubeControllerManager, images.Operator, images.ClusterPolicyController, addServingServiceCAToTokenSecrets, useSecureServiceCA); err != nil {
+		return err
 	}
+	_, _, err = v1helpers.UpdateStaticPodStatus(ctx, c.operatorClient, v1helpers.UpdateStaticPodConditionFn(c.servingCertCondition(time.Now())))
+	return err
+}
 
-	condition := operatorv1.OperatorCondition{
-		Type:   "TargetConfigControllerDegraded",
-		Status: operatorv1.ConditionFalse,
+// resolveOperandImages returns the images of the operand for the architectures of the control plane nodes and
+// updates the OperandImageArchitectureMismatch condition.
+func (c *TargetConfigController) resolveOperandImages(ctx context.Context, tuningConfig *tuning.Config) (tuning.OperandImages, error) {
+	defaults := tuning.OperandImages{
+		KubeControllerManager:   c.targetImagePullSpec,
+		ClusterPolicyController: c.clusterPolicyControllerPullSpec,
+		Operator:                c.operatorImagePullSpec,
+	}
+	masterNodes, err := c.nodeLister.List(labels.SelectorFromSet(labels.Set{"node-role.kubernetes.io/master": ""}))
+	if err != nil {
+		return defaults, err
 	}
-	if _, _, err := v1helpers.UpdateStaticPodStatus(ctx, c.operatorClient, v1helpers.UpdateStaticPodConditionFn(condition)); err != nil {
-		return true, err
+	images, condition, err := resolveOperandImages(ctx, defaults, tuningConfig.ArchitectureImages, masterNodes, c.imageArchitectures.get)
+	if _, _, updateErr := v1helpers.UpdateStaticPodStatus(ctx, c.operatorClient, v1helpers.UpdateStaticPodConditionFn(condition)); updateErr != nil {
+		return defaults, updateErr
 	}
-
-	return false, nil
+	return images, err
 }
 
 // clearCloudControllerOwnerCondition removes the CloudControllerOwner condition if it exists.
@@ -327,7 +857,7 @@ func createTargetConfigController(ctx context.Context, syncCtx factory.SyncConte
 // cloud controllers. After 4.15 this condition is no longer needed as the external cloud
 // controllers are now the default.
 // TODO remove this function for version 4.17
-func removeCloudControllerOwnerCondition(ctx context.Context, c TargetConfigController) error {
+func removeCloudControllerOwnerCondition(ctx context.Context, c *TargetConfigController) error {
 	_, status, _, err := c.operatorClient.GetStaticPodOperatorState()
 	if err != nil {
 		return fmt.Errorf("could not get operator state: %v", err)
@@ -352,9 +882,12 @@ func removeCloudControllerOwnerCondition(ctx context.Context, c TargetConfigCont
 	return nil
 }
 
-func manageKubeControllerManagerConfig(ctx context.Context, client corev1client.ConfigMapsGetter, recorder events.Recorder, operatorSpec *operatorv1.StaticPodOperatorSpec) (*corev1.ConfigMap, bool, error) {
+func manageKubeControllerManagerConfig(ctx context.Context, assets *assetoverride.Source, client corev1client.ConfigMapsGetter, recorder events.Recorder, operatorSpec *operatorv1.StaticPodOperatorSpec) (*corev1.ConfigMap, bool, error) {
 	configMap := resourceread.ReadConfigMapV1OrDie(bindata.MustAsset("assets/kube-controller-manager/cm.yaml"))
-	defaultConfig := bindata.MustAsset("assets/config/defaultconfig.yaml")
+	defaultConfig, err := assets.Asset("assets/config/defaultconfig.yaml")
+	if err != nil {
+		return nil, false, err
+	}
 	requiredConfigMap, _, err := resourcemerge.MergePrunedConfigMap(
 		&kubecontrolplanev1.KubeControllerManagerConfig{},
 		configMap,
@@ -366,7 +899,36 @@ func manageKubeControllerManagerConfig(ctx context.Context, client corev1client.
 	if err != nil {
 		return nil, false, err
 	}
-	return resourceapply.ApplyConfigMap(ctx, client, recorder, requiredConfigMap)
+	existing, err := client.ConfigMaps(requiredConfigMap.Namespace).Get(ctx, requiredConfigMap.Name, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		existing = nil
+	} else if err != nil {
+		return nil, false, err
+	}
+	actual, modified, err := applyRenderedConfigMap(ctx, client, recorder, requiredConfigMap)
+	if err != nil {
+		return nil, false, err
+	}
+	if modified && existing != nil {
+		recordExtendedArgumentChanges(recorder, existing, actual)
+	}
+	return actual, modified, nil
+}
+
+// recordExtendedArgumentChanges reports the extendedArguments added, removed or changed by an update of the config,
+// with their old and new values, which the generic ConfigMapUpdated event leaves out.
+func recordExtendedArgumentChanges(recorder events.Recorder, previous, current *corev1.ConfigMap) {
+	previousArguments, err := flagdiff.ExtendedArguments(previous.Data["config.yaml"])
+	if err != nil {
+		return
+	}
+	currentArguments, err := flagdiff.ExtendedArguments(current.Data["config.yaml"])
+	if err != nil {
+		return
+	}
+	if changes := flagdiff.Diff(previousArguments, currentArguments); len(changes) > 0 {
+		recorder.Eventf("ExtendedArgumentsChanged", "The extendedArguments of ConfigMap/%s -n %s changed: %s", current.Name, current.Namespace, flagdiff.Describe(changes))
+	}
 }
 
 func manageClusterPolicyControllerConfig(ctx context.Context, client corev1client.CoreV1Interface, recorder events.Recorder, operatorSpec *operatorv1.StaticPodOperatorSpec) (*corev1.ConfigMap, bool, error) {
@@ -396,7 +958,7 @@ func manageClusterPolicyControllerConfig(ctx context.Context, client corev1clien
 		// Then service-ca controller should start and create serving-cert.
 		// We will put the serving-cert into the config as soon as it appears which will then trigger new installer.
 
-		klog.V(1).Info("serving-cert not found: falling back to default self-signed certificate in cluster-policy-controller")
+		klog.FromContext(ctx).V(1).Info("serving-cert not found: falling back to default self-signed certificate in cluster-policy-controller")
 		configOverride := "{\"servingInfo\": { \"certFile\": \"\", \"keyFile\": \"\"} }"
 		// this will trigger defaulting here https://github.com/openshift/library-go/blob/512c504748ee57ea97f6014e8fe3085c8dd5b144/pkg/controller/controllercmd/cmd.go#L204
 		configYamls = append(configYamls, []byte(configOverride))
@@ -413,7 +975,7 @@ func manageClusterPolicyControllerConfig(ctx context.Context, client corev1clien
 	if err != nil {
 		return nil, false, err
 	}
-	return resourceapply.ApplyConfigMap(ctx, client, recorder, requiredConfigMap)
+	return applyRenderedConfigMap(ctx, client, recorder, requiredConfigMap)
 }
 
 func ensureLocalhostRecoverySAToken(ctx context.Context, client corev1client.CoreV1Interface, recorder events.Recorder) error {
@@ -443,7 +1005,11 @@ func ensureLocalhostRecoverySAToken(ctx context.Context, client corev1client.Cor
 	}
 
 	if uid != string(serviceAccount.UID) {
-		return fmt.Errorf("secret %s/%s hasn't been populated with current SA token yet: SA UID mismatch", token.Namespace, token.Name)
+		// the SA was recreated, the secret holds the token of the previous one and is not repopulated
+		if err := recreateLocalhostRecoverySAToken(ctx, secretsClient, recorder, token, requiredToken); err != nil {
+			return err
+		}
+		return fmt.Errorf("secret %s/%s was recreated for the current SA: waiting for its token", token.Namespace, token.Name)
 	}
 
 	if len(token.Data) == 0 {
@@ -465,26 +1031,19 @@ func ensureLocalhostRecoverySAToken(ctx context.Context, client corev1client.Cor
 	return err
 }
 
-func manageControllerManagerKubeconfig(ctx context.Context, client corev1client.CoreV1Interface, infrastructureLister configv1listers.InfrastructureLister, recorder events.Recorder) (*corev1.ConfigMap, bool, error) {
-	cmString := string(bindata.MustAsset("assets/kube-controller-manager/kubeconfig-cm.yaml"))
-
-	infrastructure, err := infrastructureLister.Get("cluster")
-	if err != nil {
-		return nil, false, err
-	}
-	apiServerInternalURL := infrastructure.Status.APIServerInternalURL
-	if len(apiServerInternalURL) == 0 {
-		return nil, false, fmt.Errorf("infrastucture/cluster: missing APIServerInternalURL")
+// recreateLocalhostRecoverySAToken replaces a token secret of a previous SA with an empty one, which the token
+// controller populates for the current SA. The delete is guarded by the UID of the stale secret, a secret recreated
+// concurrently is kept.
+func recreateLocalhostRecoverySAToken(ctx context.Context, client corev1client.SecretInterface, recorder events.Recorder, stale, required *corev1.Secret) error {
+	err := client.Delete(ctx, stale.Name, metav1.DeleteOptions{Preconditions: &metav1.Preconditions{UID: &stale.UID}})
+	if err != nil && !apierrors.IsNotFound(err) && !apierrors.IsConflict(err) {
+		return err
 	}
-
-	for pattern, value := range map[string]string{
-		"$LB_INT_URL": apiServerInternalURL,
-	} {
-		cmString = strings.ReplaceAll(cmString, pattern, value)
+	if _, err := client.Create(ctx, required, metav1.CreateOptions{}); err != nil && !apierrors.IsAlreadyExists(err) {
+		return err
 	}
-
-	requiredCM := resourceread.ReadConfigMapV1OrDie([]byte(cmString))
-	return resourceapply.ApplyConfigMap(ctx, client, recorder, requiredCM)
+	recorder.Eventf("LocalhostRecoveryTokenRecreated", "Recreated secret/%s -n %s, it held the token of a previous %s service account", required.Name, required.Namespace, required.Annotations[corev1.ServiceAccountNameKey])
+	return nil
 }
 
 // manageRecycler applies a ConfigMap containing the recycler config.
@@ -497,11 +1056,18 @@ func manageRecycler(ctx context.Context, configMapsGetter corev1client.ConfigMap
 		cmString = strings.ReplaceAll(cmString, pattern, value)
 	}
 	requiredCM := resourceread.ReadConfigMapV1OrDie([]byte(cmString))
-	return resourceapply.ApplyConfigMap(ctx, configMapsGetter, recorder, requiredCM)
+	return applyRenderedConfigMap(ctx, configMapsGetter, recorder, requiredCM)
 }
 
-func managePod(ctx context.Context, configMapsGetter corev1client.ConfigMapsGetter, secretsGetter corev1client.SecretsGetter, recorder events.Recorder, operatorSpec *operatorv1.StaticPodOperatorSpec, imagePullSpec, operatorImagePullSpec, clusterPolicyControllerPullSpec string, addServingServiceCAToTokenSecrets, useSecureServiceCA bool) (*corev1.ConfigMap, bool, error) {
-	required := resourceread.ReadPodV1OrDie(bindata.MustAsset("assets/kube-controller-manager/pod.yaml"))
+func managePod(ctx context.Context, assets *assetoverride.Source, configMapsGetter corev1client.ConfigMapsGetter, secretsGetter corev1client.SecretsGetter, recorder events.Recorder, operatorSpec *operatorv1.StaticPodOperatorSpec, tuningConfig *tuning.Config, imagePullSpec, operatorImagePullSpec, clusterPolicyControllerPullSpec string, addServingServiceCAToTokenSecrets, useSecureServiceCA bool) (*corev1.ConfigMap, bool, error) {
+	podManifest, err := assets.Asset("assets/kube-controller-manager/pod.yaml")
+	if err != nil {
+		return nil, false, err
+	}
+	required, err := resourceread.ReadPodV1(podManifest)
+	if err != nil {
+		return nil, false, err
+	}
 	// TODO: If the image pull spec is not specified, the "${IMAGE}" will be used as value and the pod will fail to start.
 	images := map[string]string{
 		"${IMAGE}":                           imagePullSpec,
@@ -527,35 +1093,36 @@ func managePod(ctx context.Context, configMapsGetter corev1client.ConfigMapsGett
 		}
 	}
 
-	// This section sets the log levels for all containers that take a "1-line" argument
-	logLevel := 2
-	switch operatorSpec.LogLevel {
-	case operatorv1.Normal:
-		logLevel = 2
-	case operatorv1.Debug:
-		logLevel = 4
-	case operatorv1.Trace:
-		logLevel = 6
-	case operatorv1.TraceAll:
-		logLevel = 8
-	default:
-		logLevel = 2
+	applyProbeProfile(required, tuningConfig.ProbeProfile)
+	applyPodDNS(required, tuningConfig.PodDNS)
+	ipv6Primary, err := isIPv6Primary(operatorSpec.ObservedConfig.Raw)
+	if err != nil {
+		return nil, false, err
+	}
+	if ipv6Primary {
+		applyIPv6Loopback(required)
 	}
+
+	// This section sets the log levels for all containers that take a "1-line" argument.
+	// spec.logLevel applies to the whole operand unless the tuning config overrides it for a single component.
 	// containers[0] = kube-controller-manager
 	// containers[1] = cluster-policy-controller
 	// containers[2] = kube-controller-manager-cert-syncer
 	// containers[3] = kube-controller-manager-recovery-controller
-	containerNames := sets.NewString("kube-controller-manager", "cluster-policy-controller", "kube-controller-manager-recovery-controller")
 	for i := 0; i < len(required.Spec.Containers); i++ {
-		if !containerNames.Has(required.Spec.Containers[i].Name) {
+		if !tuning.LogLevelComponents.Has(required.Spec.Containers[i].Name) {
 			continue
 		}
 		containerArgsWithLoglevel := required.Spec.Containers[i].Args
 		if argsCount := len(containerArgsWithLoglevel); argsCount > 1 {
 			return nil, false, fmt.Errorf("expected only one container argument, got %d", argsCount)
 		}
+		logLevel := operatorSpec.LogLevel
+		if componentLogLevel, ok := tuningConfig.ComponentLogLevels[required.Spec.Containers[i].Name]; ok && len(componentLogLevel) > 0 {
+			logLevel = componentLogLevel
+		}
 		containerArgsWithLoglevel[0] = strings.TrimSpace(containerArgsWithLoglevel[0])
-		containerArgsWithLoglevel[0] += fmt.Sprintf(" -v=%d", logLevel)
+		containerArgsWithLoglevel[0] += fmt.Sprintf(" -v=%d", loglevel.LogLevelToVerbosity(logLevel))
 	}
 
 	// now we are only handling args for the main KCM container
@@ -566,11 +1133,29 @@ func managePod(ctx context.Context, configMapsGetter corev1client.ConfigMapsGett
 
 	kcmContainerArgsWithLoglevel[0] = strings.TrimSpace(kcmContainerArgsWithLoglevel[0])
 
-	if _, err := secretsGetter.Secrets(required.Namespace).Get(ctx, "serving-cert", metav1.GetOptions{}); err != nil && !apierrors.IsNotFound(err) {
+	// the flags rendered from the config, passed on the exec line or in the flags file
+	var kcmFlags []string
+
+	servingCert, err := secretsGetter.Secrets(required.Namespace).Get(ctx, "serving-cert", metav1.GetOptions{})
+	switch {
+	case apierrors.IsNotFound(err):
+	case err != nil:
+		return nil, false, err
+	default:
+		// an invalid serving-cert is left out for a self-signed certificate, see the ServingCertInvalid condition
+		if err := validateServingCert(servingCert, time.Now()); err != nil {
+			klog.Warningf("Not serving secret/serving-cert -n %s: %v", required.Namespace, err)
+		} else {
+			kcmFlags = append(kcmFlags, "--tls-cert-file=/etc/kubernetes/static-pod-resources/secrets/serving-cert/tls.crt")
+			kcmFlags = append(kcmFlags, "--tls-private-key-file=/etc/kubernetes/static-pod-resources/secrets/serving-cert/tls.key")
+		}
+	}
+
+	// the metrics are served on the secure port with their own certificate, prometheus selects it by server name
+	if _, err := secretsGetter.Secrets(required.Namespace).Get(ctx, "metrics-serving-cert", metav1.GetOptions{}); err != nil && !apierrors.IsNotFound(err) {
 		return nil, false, err
 	} else if err == nil {
-		kcmContainerArgsWithLoglevel[0] += " --tls-cert-file=/etc/kubernetes/static-pod-resources/secrets/serving-cert/tls.crt"
-		kcmContainerArgsWithLoglevel[0] += " --tls-private-key-file=/etc/kubernetes/static-pod-resources/secrets/serving-cert/tls.key"
+		kcmFlags = append(kcmFlags, fmt.Sprintf("--tls-sni-cert-key=/etc/kubernetes/static-pod-certs/secrets/metrics-serving-cert/tls.crt,/etc/kubernetes/static-pod-certs/secrets/metrics-serving-cert/tls.key:%s", metricsServerName))
 	}
 
 	kubeControllerManagerConfigMap, err := configMapsGetter.ConfigMaps(required.Namespace).Get(ctx, "config", metav1.GetOptions{})
@@ -582,8 +1167,14 @@ func managePod(ctx context.Context, configMapsGetter corev1client.ConfigMapsGett
 		if err := yaml.Unmarshal([]byte(kubeControllerManagerConfigMap.Data["config.yaml"]), &kubeControllerManagerConfig); err != nil {
 			return nil, false, fmt.Errorf("failed to unmarshal the kube-controller-manager config: %v", err)
 		}
-		if extendedArguments := GetKubeControllerManagerArgs(kubeControllerManagerConfig); len(extendedArguments) > 0 {
-			kcmContainerArgsWithLoglevel[0] += " " + strings.Join(extendedArguments, " ")
+		kcmFlags = append(kcmFlags, GetKubeControllerManagerArgs(kubeControllerManagerConfig)...)
+		// the probes follow the secure port the kube-controller-manager is started with
+		if securePort, _, _ := unstructured.NestedStringSlice(kubeControllerManagerConfig, "extendedArguments", "secure-port"); len(securePort) > 0 {
+			port, err := strconv.Atoi(securePort[0])
+			if err != nil {
+				return nil, false, fmt.Errorf("invalid secure-port %q: %v", securePort[0], err)
+			}
+			applySecurePort(required, int32(port))
 		}
 	}
 
@@ -603,13 +1194,20 @@ func managePod(ctx context.Context, configMapsGetter corev1client.ConfigMapsGett
 	}
 
 	if cipherSuitesFound && len(cipherSuites) > 0 {
-		kcmContainerArgsWithLoglevel[0] += fmt.Sprintf(" --tls-cipher-suites=%s", strings.Join(cipherSuites, ","))
+		kcmFlags = append(kcmFlags, fmt.Sprintf("--tls-cipher-suites=%s", strings.Join(cipherSuites, ",")))
 	}
 
 	if minTLSVersionFound && len(minTLSVersion) > 0 {
-		kcmContainerArgsWithLoglevel[0] += fmt.Sprintf(" --tls-min-version=%s", minTLSVersion)
+		kcmFlags = append(kcmFlags, fmt.Sprintf("--tls-min-version=%s", minTLSVersion))
 	}
 
+	if tuningConfig.FlagsFile {
+		if err := useFlagsFile(ctx, configMapsGetter, recorder, required, kcmFlags); err != nil {
+			return nil, false, err
+		}
+	} else if len(kcmFlags) > 0 {
+		kcmContainerArgsWithLoglevel[0] += " " + strings.Join(kcmFlags, " ")
+	}
 	kcmContainerArgsWithLoglevel[0] = strings.TrimSpace(kcmContainerArgsWithLoglevel[0])
 
 	proxyConfig, _, err := unstructured.NestedStringMap(observedConfig, "targetconfigcontroller", "proxy")
@@ -618,8 +1216,13 @@ func managePod(ctx context.Context, configMapsGetter corev1client.ConfigMapsGett
 	}
 
 	proxyEnvVars := proxyMapToEnvVars(proxyConfig)
+	for i := range required.Spec.Containers {
+		setProxyEnvVars(&required.Spec.Containers[i], proxyEnvVars)
+	}
+
+	tuningEnvVars := tuningEnvToEnvVars(tuningConfig.Env)
 	for i, container := range required.Spec.Containers {
-		required.Spec.Containers[i].Env = append(container.Env, proxyEnvVars...)
+		required.Spec.Containers[i].Env = append(container.Env, tuningEnvVars...)
 	}
 
 	// set the env var to indicate that we want this vulnerable behavior.
@@ -648,11 +1251,82 @@ func managePod(ctx context.Context, configMapsGetter corev1client.ConfigMapsGett
 		}
 	}
 
+	if err := addExtraMounts(ctx, required, configMapsGetter, secretsGetter, tuningConfig.ExtraMounts); err != nil {
+		return nil, false, err
+	}
+	// the signer of an external provider is mounted once the observed config switched the kube-controller-manager to
+	// it, which waits until the kube-apiserver trusts the signer
+	if provider := csrsignerprovider.New(tuningConfig.CSRSigner); provider != nil {
+		_, keyFile := provider.Files()
+		if observedKeyFile, _, _ := unstructured.NestedStringSlice(observedConfig, "extendedArguments", "cluster-signing-key-file"); len(observedKeyFile) > 0 && observedKeyFile[0] == keyFile {
+			provider.Mount(required)
+		}
+	}
+	applyWorkloadPartitioning(required)
+
+	if err := recordRemovedProxyEnvVars(ctx, configMapsGetter, recorder, required); err != nil {
+		return nil, false, err
+	}
+
 	configMap := resourceread.ReadConfigMapV1OrDie(bindata.MustAsset("assets/kube-controller-manager/pod-cm.yaml"))
 	configMap.Data["pod.yaml"] = resourceread.WritePodV1OrDie(required)
 	configMap.Data["forceRedeploymentReason"] = operatorSpec.ForceRedeploymentReason
 	configMap.Data["version"] = version.Get().String()
-	return resourceapply.ApplyConfigMap(ctx, configMapsGetter, recorder, configMap)
+	podConfigMap, changed, err := applyRenderedConfigMap(ctx, configMapsGetter, recorder, configMap)
+	if err != nil {
+		return nil, false, err
+	}
+	// the flags file goes once no pod reads it anymore
+	if !tuningConfig.FlagsFile {
+		if err := deleteFlagsFile(ctx, configMapsGetter, recorder); err != nil {
+			return nil, false, err
+		}
+	}
+	return podConfigMap, changed, nil
+}
+
+// applyProbeProfile adjusts the probe timings of all the containers in the pod according to the selected profile.
+// The Default profile keeps the values from the pod manifest.
+func applyProbeProfile(pod *corev1.Pod, profile tuning.ProbeProfile) {
+	if profile != tuning.SlowStorageProbeProfile {
+		return
+	}
+	for i := range pod.Spec.Containers {
+		container := &pod.Spec.Containers[i]
+		if container.StartupProbe != nil {
+			container.StartupProbe.FailureThreshold = 10
+		}
+		if container.LivenessProbe != nil {
+			container.LivenessProbe.InitialDelaySeconds = 90
+			container.LivenessProbe.FailureThreshold = 6
+		}
+		if container.ReadinessProbe != nil {
+			container.ReadinessProbe.InitialDelaySeconds = 30
+			container.ReadinessProbe.FailureThreshold = 6
+		}
+	}
+}
+
+// applySecurePort moves the port the kube-controller-manager container exposes, waits for and is probed on off the
+// tuning.DefaultSecurePort of the pod manifest.
+func applySecurePort(pod *corev1.Pod, securePort int32) {
+	if securePort == tuning.DefaultSecurePort {
+		return
+	}
+	container := &pod.Spec.Containers[0]
+	for i := range container.Ports {
+		if container.Ports[i].ContainerPort == tuning.DefaultSecurePort {
+			container.Ports[i].ContainerPort = securePort
+		}
+	}
+	for _, probe := range []*corev1.Probe{container.StartupProbe, container.LivenessProbe, container.ReadinessProbe} {
+		if probe != nil && probe.HTTPGet != nil && probe.HTTPGet.Port.IntValue() == int(tuning.DefaultSecurePort) {
+			probe.HTTPGet.Port = intstr.FromInt32(securePort)
+		}
+	}
+	for i := range container.Args {
+		container.Args[i] = strings.ReplaceAll(container.Args[i], fmt.Sprintf("sport = %d", tuning.DefaultSecurePort), fmt.Sprintf("sport = %d", securePort))
+	}
 }
 
 func GetKubeControllerManagerArgs(config map[string]interface{}) []string {
@@ -672,44 +1346,86 @@ func GetKubeControllerManagerArgs(config map[string]interface{}) []string {
 	return args
 }
 
-func manageServiceAccountCABundle(ctx context.Context, lister corev1listers.ConfigMapLister, client corev1client.ConfigMapsGetter, recorder events.Recorder) (*corev1.ConfigMap, bool, error) {
-	requiredConfigMap, err := resourcesynccontroller.CombineCABundleConfigMaps(
+// serviceAccountCABundle combines the sources selected by serviceAccountCASources: the ca bundle needed to recognize
+// the server and the one needed to recognize the default certificates generated by cluster-ingress-operator.
+func serviceAccountCABundle(registry *cabundle.Registry, lister corev1listers.ConfigMapLister, recorder events.Recorder, sources []resourcesynccontroller.ResourceLocation) (*corev1.ConfigMap, error) {
+	return registry.CombineConfigMaps(
+		recorder,
 		resourcesynccontroller.ResourceLocation{Namespace: operatorclient.TargetNamespace, Name: "serviceaccount-ca"},
 		lister,
 		certrotation.AdditionalAnnotations{
 			JiraComponent: "kube-controller-manager",
 		},
-		// include the ca bundle needed to recognize the server
-		resourcesynccontroller.ResourceLocation{Namespace: operatorclient.GlobalMachineSpecifiedConfigNamespace, Name: "kube-apiserver-server-ca"},
-		// include the ca bundle needed to recognize default
-		// certificates generated by cluster-ingress-operator
-		resourcesynccontroller.ResourceLocation{Namespace: operatorclient.GlobalMachineSpecifiedConfigNamespace, Name: "default-ingress-cert"},
+		sources...,
 	)
+}
+
+func ManageCSRCABundle(ctx context.Context, registry *cabundle.Registry, lister corev1listers.ConfigMapLister, client corev1client.ConfigMapsGetter, recorder events.Recorder) (*corev1.ConfigMap, bool, error) {
+	inputConfigMaps := []resourcesynccontroller.ResourceLocation{
+		// include the CA we use to sign CSRs
+		{Namespace: operatorclient.OperatorNamespace, Name: "csr-signer-ca"},
+		// include the CA we use to sign the cert key pairs from from csr-signer
+		{Namespace: operatorclient.OperatorNamespace, Name: "csr-controller-signer-ca"},
+	}
+	additionalCAs, err := additionalCSRCABundleLocations(lister)
 	if err != nil {
 		return nil, false, err
 	}
-	return resourceapply.ApplyConfigMap(ctx, client, recorder, requiredConfigMap)
-}
+	inputConfigMaps = append(inputConfigMaps, additionalCAs...)
 
-func ManageCSRCABundle(ctx context.Context, lister corev1listers.ConfigMapLister, client corev1client.ConfigMapsGetter, recorder events.Recorder) (*corev1.ConfigMap, bool, error) {
-	requiredConfigMap, err := resourcesynccontroller.CombineCABundleConfigMaps(
+	requiredConfigMap, err := registry.CombineConfigMaps(
+		recorder,
 		resourcesynccontroller.ResourceLocation{Namespace: operatorclient.OperatorNamespace, Name: "csr-controller-ca"},
 		lister,
 		certrotation.AdditionalAnnotations{
 			JiraComponent: "kube-controller-manager",
 		},
-		// include the CA we use to sign CSRs
-		resourcesynccontroller.ResourceLocation{Namespace: operatorclient.OperatorNamespace, Name: "csr-signer-ca"},
-		// include the CA we use to sign the cert key pairs from from csr-signer
-		resourcesynccontroller.ResourceLocation{Namespace: operatorclient.OperatorNamespace, Name: "csr-controller-signer-ca"},
+		inputConfigMaps...,
 	)
 	if err != nil {
 		return nil, false, err
 	}
-	return resourceapply.ApplyConfigMap(ctx, client, recorder, requiredConfigMap)
+	return ApplyConfigMap(ctx, client, recorder, requiredConfigMap)
+}
+
+// additionalCSRCABundleLocations returns the configmaps in openshift-config that ask, through IncludeInCSRCABundleLabel,
+// for their ca-bundle.crt to be added to the CSR CA bundle, sorted by name to keep the bundle stable.
+func additionalCSRCABundleLocations(lister corev1listers.ConfigMapLister) ([]resourcesynccontroller.ResourceLocation, error) {
+	configMaps, err := lister.ConfigMaps(operatorclient.GlobalUserSpecifiedConfigNamespace).List(labels.SelectorFromSet(labels.Set{IncludeInCSRCABundleLabel: "true"}))
+	if err != nil {
+		return nil, err
+	}
+	names := make([]string, 0, len(configMaps))
+	for _, configMap := range configMaps {
+		names = append(names, configMap.Name)
+	}
+	sort.Strings(names)
+
+	locations := make([]resourcesynccontroller.ResourceLocation, 0, len(names))
+	for _, name := range names {
+		locations = append(locations, resourcesynccontroller.ResourceLocation{Namespace: operatorclient.GlobalUserSpecifiedConfigNamespace, Name: name})
+	}
+	return locations, nil
 }
 
-func ManageCSRSigner(ctx context.Context, lister corev1listers.SecretLister, client corev1client.SecretsGetter, recorder events.Recorder) (*corev1.Secret, time.Duration, bool, error) {
+// CSRSignerReplaceReasonAnnotation on the csr-signer of the target namespace is why ManageCSRSigner replaced the signer
+// last, one of CSRSignerCreated, CSRSignerPreviousExpired and CSRSignerRotated.
+const CSRSignerReplaceReasonAnnotation = "kubecontrollermanager.operator.openshift.io/csr-signer-replace-reason"
+
+const (
+	// CSRSignerCreated is the reason of the first csr-signer of the target namespace.
+	CSRSignerCreated = "Created"
+	// CSRSignerPreviousExpired is the reason of a csr-signer replacing an expired one without waiting for the trust of
+	// the kube-apiserver.
+	CSRSignerPreviousExpired = "PreviousExpired"
+	// CSRSignerRotated is the reason of a rotated csr-signer the kube-apiserver trusts.
+	CSRSignerRotated = "Rotated"
+)
+
+// ManageCSRSigner copies the csr-signer from the operator namespace into the target namespace. A rotated signer is only
+// switched to once the client CA bundle of the kube-apiserver trusts it, the client certificates it issues before
+// would be rejected. An expired or missing signer is replaced right away.
+func ManageCSRSigner(ctx context.Context, lister corev1listers.SecretLister, configMapLister corev1listers.ConfigMapLister, client corev1client.SecretsGetter, recorder events.Recorder, delays CSRSignerRequeueDelays) (*corev1.Secret, time.Duration, bool, error) {
 	// get the certkey pair we will sign with. We're going to add the cert to a ca bundle so we can recognize the chain it signs back to the signer
 	csrSigner, err := lister.Secrets(operatorclient.OperatorNamespace).Get("csr-signer")
 	if apierrors.IsNotFound(err) {
@@ -725,32 +1441,61 @@ func ManageCSRSigner(ctx context.Context, lister corev1listers.SecretLister, cli
 		return nil, 0, false, err
 	}
 
-	// make sure we wait five minutes to propagate the change to other components, like kas for trust
-	useAfter = useAfter.Add(5 * time.Minute)
 	now := time.Now()
 
 	oldSigner, err := client.Secrets(operatorclient.TargetNamespace).Get(ctx, "csr-signer", metav1.GetOptions{})
-	_, _, _, oldUseBefore, _ := extractSigner(oldSigner)
+	oldCertBytes, _, _, oldUseBefore, _ := extractSigner(oldSigner)
+	var replaceReason string
 	switch {
 	case apierrors.IsNotFound(err):
 		// apply the secret
+		replaceReason = CSRSignerCreated
 
 	case oldUseBefore.Before(now):
 		// apply the secret
+		replaceReason = CSRSignerPreviousExpired
 
-	case now.After(useAfter):
-		// apply the secret
+	case bytes.Equal(oldCertBytes, certBytes):
+		// apply the secret, the kube-apiserver already trusts it
 
-	default:
+	case now.Before(useAfter) && useAfter.Sub(now) > ClockSkewTolerance:
+		// the clocks are skewed, which the ClockSkewDetected condition reports. Check again regularly instead of
+		// sleeping until the useAfter, the clocks may be corrected or the signer rotated again meanwhile.
+		return nil, delays.TrustRecheckInterval, false, nil
+
+	case now.Before(useAfter):
 		// wait a little while longer until after the useAfter
-		return nil, useAfter.Sub(now) + 10*time.Second, false, nil
+		return nil, useAfter.Sub(now) + delays.Padding, false, nil
+
+	default:
+		trusted, err := IsTrustedByKubeAPIServer(configMapLister, certBytes, now)
+		if err != nil {
+			return nil, 0, false, err
+		}
+		if !trusted {
+			// the configmap informer requeues as soon as the bundle changes, this is a fallback
+			klog.FromContext(ctx).V(2).Info("Waiting for the kube-apiserver to trust the new csr-signer", "configmap", operatorclient.GlobalMachineSpecifiedConfigNamespace+"/"+kubeletClientCAName)
+			return nil, delays.TrustRecheckInterval, false, nil
+		}
+		replaceReason = CSRSignerRotated
+	}
+	if bytes.Equal(oldCertBytes, certBytes) {
+		// not replaced, the reason of the last replacement stays
+		replaceReason = oldSigner.Annotations[CSRSignerReplaceReasonAnnotation]
 	}
 
+	annotations := map[string]string{}
+	for key, value := range csrSigner.Annotations {
+		annotations[key] = value
+	}
+	if len(replaceReason) > 0 {
+		annotations[CSRSignerReplaceReasonAnnotation] = replaceReason
+	}
 	csrSigner = &corev1.Secret{
 		ObjectMeta: metav1.ObjectMeta{
 			Namespace:   operatorclient.TargetNamespace,
 			Name:        "csr-signer",
-			Annotations: csrSigner.Annotations,
+			Annotations: annotations,
 		},
 		Data: map[string][]byte{
 			"tls.crt": certBytes,
@@ -758,7 +1503,7 @@ func ManageCSRSigner(ctx context.Context, lister corev1listers.SecretLister, cli
 		},
 		Type: corev1.SecretTypeTLS,
 	}
-	secret, modified, err := resourceapply.ApplySecret(ctx, client, recorder, csrSigner)
+	secret, modified, err := ApplySecret(ctx, client, recorder, csrSigner)
 	return secret, 0, modified, err
 }
 
@@ -778,22 +1523,22 @@ func extractSigner(csrSigner *corev1.Secret) ([]byte, []byte, time.Time, time.Ti
 	if len(signingKey) == 0 {
 		return nil, nil, useAfter, useBefore, nil
 	}
-	signingCertKeyPair, err := crypto.GetCAFromBytes(signingCert, signingKey)
+	signerCert, _, err := parseCSRSigner(signingCert, signingKey)
 	if err != nil {
 		return nil, nil, useAfter, useBefore, err
 	}
-	certBytes, err := crypto.EncodeCertificates(signingCertKeyPair.Config.Certs[0])
+	certBytes, err := crypto.EncodeCertificates(signerCert)
 	if err != nil {
 		return nil, nil, useAfter, useBefore, err
 	}
 
-	useAfter = signingCertKeyPair.Config.Certs[0].NotBefore
-	useBefore = signingCertKeyPair.Config.Certs[0].NotAfter
+	useAfter = signerCert.NotBefore
+	useBefore = signerCert.NotAfter
 
 	return certBytes, signingKey, useAfter, useBefore, nil
 }
 
-func ManageCSRIntermediateCABundle(ctx context.Context, lister corev1listers.SecretLister, client corev1client.ConfigMapsGetter, recorder events.Recorder) (*corev1.ConfigMap, bool, error) {
+func ManageCSRIntermediateCABundle(ctx context.Context, registry *cabundle.Registry, lister corev1listers.SecretLister, client corev1client.ConfigMapsGetter, recorder events.Recorder) (*corev1.ConfigMap, bool, error) {
 	// get the certkey pair we will sign with. We're going to add the cert to a ca bundle so we can recognize the chain it signs back to the signer
 	csrSigner, err := lister.Secrets(operatorclient.OperatorNamespace).Get("csr-signer")
 	if apierrors.IsNotFound(err) {
@@ -807,14 +1552,36 @@ func ManageCSRIntermediateCABundle(ctx context.Context, lister corev1listers.Sec
 		return nil, false, nil
 	}
 	signingKey := csrSigner.Data["tls.key"]
-	if len(signingCert) == 0 {
+	if len(signingKey) == 0 {
 		return nil, false, nil
 	}
-	signingCertKeyPair, err := crypto.GetCAFromBytes(signingCert, signingKey)
+	// the intermediates of a signer chain are published next to the signer, the certificates it signs chain up
+	// through them
+	signerCert, intermediates, err := parseCSRSigner(signingCert, signingKey)
+	if err != nil {
+		return nil, false, err
+	}
+	// the signer goes first, followed by its intermediates
+	signerChain, err := crypto.EncodeCertificates(append([]*x509.Certificate{signerCert}, intermediates...)...)
+	if err != nil {
+		return nil, false, err
+	}
+	return applyCSRSignerCA(ctx, registry, client, recorder, fmt.Sprintf("secret/csr-signer in %q", operatorclient.OperatorNamespace), signerChain)
+}
+
+// manageProviderCSRIntermediateCABundle publishes the certificate of the external provider of the csr-signer in the
+// csr-signer-ca bundle. The certificates of the csr-signer of the operator stay in the bundle until they expire, so
+// that the certificates it issued before the switch keep being trusted.
+func manageProviderCSRIntermediateCABundle(ctx context.Context, registry *cabundle.Registry, lister corev1listers.ConfigMapLister, client corev1client.ConfigMapsGetter, recorder events.Recorder, provider csrsignerprovider.Provider) (*corev1.ConfigMap, bool, error) {
+	signerChain, err := provider.Certificate(lister)
 	if err != nil {
 		return nil, false, err
 	}
+	return applyCSRSignerCA(ctx, registry, client, recorder, "the csr-signer of provider "+provider.Name(), signerChain)
+}
 
+// applyCSRSignerCA adds the signer chain to the csr-signer-ca bundle of the operator namespace.
+func applyCSRSignerCA(ctx context.Context, registry *cabundle.Registry, client corev1client.ConfigMapsGetter, recorder events.Recorder, signerKey string, signerChain []byte) (*corev1.ConfigMap, bool, error) {
 	csrSignerCA, err := client.ConfigMaps(operatorclient.OperatorNamespace).Get(ctx, "csr-signer-ca", metav1.GetOptions{})
 	if apierrors.IsNotFound(err) {
 		csrSignerCA = &corev1.ConfigMap{
@@ -831,62 +1598,122 @@ func ManageCSRIntermediateCABundle(ctx context.Context, lister corev1listers.Sec
 		return nil, false, err
 	}
 
-	certificates := []*x509.Certificate{}
-	caBundle := csrSignerCA.Data["ca-bundle.crt"]
-	if len(caBundle) > 0 {
-		var err error
-		certificates, err = cert.ParseCertsPEM([]byte(caBundle))
-		if err != nil {
-			return nil, false, err
-		}
-	}
-	certificates = append(certificates, signingCertKeyPair.Config.Certs...)
-	certificates = crypto.FilterExpiredCerts(certificates...)
-
-	finalCertificates := []*x509.Certificate{}
-	// now check for duplicates. n^2, but super simple
-	for i := range certificates {
-		found := false
-		for j := range finalCertificates {
-			if reflect.DeepEqual(certificates[i].Raw, finalCertificates[j].Raw) {
-				found = true
-				break
-			}
-		}
-		if !found {
-			finalCertificates = append(finalCertificates, certificates[i])
-		}
-	}
-
-	caBytes, err := crypto.EncodeCertificates(finalCertificates...)
+	caBytes, err := registry.Combine(recorder,
+		resourcesynccontroller.ResourceLocation{Namespace: operatorclient.OperatorNamespace, Name: "csr-signer-ca"},
+		cabundle.Input{Key: fmt.Sprintf("configmap/csr-signer-ca in %q", operatorclient.OperatorNamespace), Content: csrSignerCA.Data["ca-bundle.crt"]},
+		cabundle.Input{Key: signerKey, Content: string(signerChain)},
+	)
 	if err != nil {
 		return nil, false, err
 	}
 	csrSignerCA.Data["ca-bundle.crt"] = string(caBytes)
 
-	return resourceapply.ApplyConfigMap(ctx, client, recorder, csrSignerCA)
+	return ApplyConfigMap(ctx, client, recorder, csrSignerCA)
 }
 
+// ensureKubeControllerManagerTrustedCA makes sure the trusted-ca-bundle configmap exists and carries the label that gets
+// the trusted CA bundle injected into it. Existing labels and annotations are merged rather than overwritten, so that
+// metadata added by users or other tooling survives, and the injected data is never touched. A configmap created by
+// someone else is adopted by setting the owning component annotation.
 func ensureKubeControllerManagerTrustedCA(ctx context.Context, client corev1client.CoreV1Interface, recorder events.Recorder) error {
 	required := resourceread.ReadConfigMapV1OrDie(bindata.MustAsset("assets/kube-controller-manager/trusted-ca-cm.yaml"))
 	cmCLient := client.ConfigMaps(operatorclient.TargetNamespace)
 
-	cm, err := cmCLient.Get(ctx, "trusted-ca-bundle", metav1.GetOptions{})
+	cm, err := cmCLient.Get(ctx, required.Name, metav1.GetOptions{})
 	if err != nil {
 		if apierrors.IsNotFound(err) {
 			_, err = cmCLient.Create(ctx, required, metav1.CreateOptions{})
+			if err == nil {
+				recorder.Eventf("ConfigMapCreated", "Created ConfigMap/%s -n %s because it was missing", required.Name, required.Namespace)
+			}
 		}
 		return err
 	}
 
-	// update if modified by the user
-	if val, ok := cm.Labels["config.openshift.io/inject-trusted-cabundle"]; !ok || val != "true" {
-		cm.Labels["config.openshift.io/inject-trusted-cabundle"] = "true"
-		_, err = cmCLient.Update(ctx, cm, metav1.UpdateOptions{})
+	adopted := cm.Annotations[annotations.OpenShiftComponent] != required.Annotations[annotations.OpenShiftComponent]
+	modified := false
+	updated := cm.DeepCopy()
+	resourcemerge.MergeMap(&modified, &updated.Labels, required.Labels)
+	resourcemerge.MergeMap(&modified, &updated.Annotations, required.Annotations)
+	if !modified {
+		return nil
+	}
+
+	if _, err := cmCLient.Update(ctx, updated, metav1.UpdateOptions{}); err != nil {
 		return err
 	}
+	if adopted {
+		recorder.Eventf("TrustedCABundleAdopted", "Adopted the existing ConfigMap/%s -n %s", updated.Name, updated.Namespace)
+	} else {
+		recorder.Eventf("ConfigMapUpdated", "Updated the labels and annotations of ConfigMap/%s -n %s", updated.Name, updated.Namespace)
+	}
+	return nil
+}
 
-	return err
+// tuningEnvToEnvVars returns the env vars of the tuning config sorted by name, so the pod does not change between syncs.
+func tuningEnvToEnvVars(env map[string]string) []corev1.EnvVar {
+	envVars := []corev1.EnvVar{}
+	for _, name := range sets.List(sets.KeySet(env)) {
+		envVars = append(envVars, corev1.EnvVar{Name: name, Value: env[name]})
+	}
+	return envVars
+}
+
+// proxyEnvVarNames are the env vars of the proxy config, the lower case variants included since the pod may have
+// been rendered by an older operator or edited by hand.
+var proxyEnvVarNames = sets.New("HTTP_PROXY", "HTTPS_PROXY", "NO_PROXY", "http_proxy", "https_proxy", "no_proxy")
+
+// setProxyEnvVars replaces the proxy env vars of the container with the ones of the proxy config. The ones the proxy
+// config no longer sets are dropped, so that a removed proxy does not linger in the pod.
+func setProxyEnvVars(container *corev1.Container, proxyEnvVars []corev1.EnvVar) {
+	env := make([]corev1.EnvVar, 0, len(container.Env)+len(proxyEnvVars))
+	for _, envVar := range container.Env {
+		if !proxyEnvVarNames.Has(envVar.Name) {
+			env = append(env, envVar)
+		}
+	}
+	container.Env = append(env, proxyEnvVars...)
+}
+
+// removedProxyEnvVars returns the proxy env vars of the containers of the current pod the required pod drops, as
+// container/name sorted.
+func removedProxyEnvVars(current, required *corev1.Pod) []string {
+	requiredEnvVars := sets.New[string]()
+	for _, container := range required.Spec.Containers {
+		for _, envVar := range container.Env {
+			requiredEnvVars.Insert(container.Name + "/" + envVar.Name)
+		}
+	}
+	removed := sets.New[string]()
+	for _, container := range current.Spec.Containers {
+		for _, envVar := range container.Env {
+			if proxyEnvVarNames.Has(envVar.Name) && !requiredEnvVars.Has(container.Name+"/"+envVar.Name) {
+				removed.Insert(container.Name + "/" + envVar.Name)
+			}
+		}
+	}
+	return sets.List(removed)
+}
+
+// recordRemovedProxyEnvVars reports the proxy env vars the new revision drops from the pod, the rollout of the
+// revision restarts the operand without them.
+func recordRemovedProxyEnvVars(ctx context.Context, configMapsGetter corev1client.ConfigMapsGetter, recorder events.Recorder, required *corev1.Pod) error {
+	current, err := configMapsGetter.ConfigMaps(operatorclient.TargetNamespace).Get(ctx, "kube-controller-manager-pod", metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	currentPod, err := resourceread.ReadPodV1([]byte(current.Data["pod.yaml"]))
+	if err != nil {
+		// rewritten below
+		return nil
+	}
+	if removed := removedProxyEnvVars(currentPod, required); len(removed) > 0 {
+		recorder.Eventf("ProxyEnvRemoved", "Removing the proxy env vars %s from the kube-controller-manager pod", strings.Join(removed, ", "))
+	}
+	return nil
 }
 
 func proxyMapToEnvVars(proxyConfig map[string]string) []corev1.EnvVar {
@@ -11,14 +11,25 @@ import (
 	"math/big"
 	"reflect"
 	"strings"
+	"sync"
 	"testing"
 	"time"
 
+	operatorv1 "github.com/openshift/api/operator/v1"
+	"github.com/openshift/cluster-kube-controller-manager-operator/bindata"
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/cabundle"
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/conditiontest"
 	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/operatorclient"
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/tuning"
 	"github.com/openshift/library-go/pkg/crypto"
 	"github.com/openshift/library-go/pkg/operator/events"
+	"github.com/openshift/library-go/pkg/operator/resource/resourceread"
+	"github.com/openshift/library-go/pkg/operator/v1helpers"
 	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
 	"k8s.io/client-go/kubernetes/fake"
 	corev1listers "k8s.io/client-go/listers/core/v1"
 	"k8s.io/client-go/tools/cache"
@@ -104,12 +115,13 @@ func TestIsRequiredConfigPresent(t *testing.T) {
 
 func TestManageCSRSigner(t *testing.T) {
 	type Test struct {
-		name           string
-		secret         *corev1.Secret
-		target         *corev1.Secret
-		expectedDelay  time.Duration
-		expectedChange bool
-		expectedError  bool
+		name                   string
+		secret                 *corev1.Secret
+		target                 *corev1.Secret
+		trustedByKubeAPIServer bool
+		expectedDelay          time.Duration
+		expectedChange         bool
+		expectedError          bool
 	}
 
 	tests := []Test{
@@ -139,29 +151,56 @@ func TestManageCSRSigner(t *testing.T) {
 			expectedError:  false,
 		},
 		{
-			name: "input certificate with start validity now - expect delay",
+			name: "rotated certificate not yet trusted by the kube-apiserver - expect delay",
 			secret: &corev1.Secret{
 				ObjectMeta: metav1.ObjectMeta{Name: "csr-signer", Namespace: operatorclient.OperatorNamespace},
-				Data:       makeCerts(t, time.Now(), 1*time.Hour),
+				Data:       makeCerts(t, time.Now().Add(-10*time.Minute), 1*time.Hour),
 				Type:       corev1.SecretTypeTLS,
 			},
-			expectedDelay:  5 * time.Minute,
+			target: &corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{Name: "csr-signer", Namespace: operatorclient.TargetNamespace},
+				Data:       makeCerts(t, time.Now().Add(-30*time.Minute), 1*time.Hour),
+				Type:       corev1.SecretTypeTLS,
+			},
+			expectedDelay:  DefaultCSRSignerRequeueDelays.TrustRecheckInterval,
 			expectedChange: false,
 			expectedError:  false,
 		},
 		{
-			name: "input certificate with start validity from the past but within propagation delay - expect delay",
+			name: "rotated certificate trusted by the kube-apiserver",
+			secret: &corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{Name: "csr-signer", Namespace: operatorclient.OperatorNamespace},
+				Data:       makeCerts(t, time.Now().Add(-10*time.Second), 1*time.Hour),
+				Type:       corev1.SecretTypeTLS,
+			},
+			target: &corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{Name: "csr-signer", Namespace: operatorclient.TargetNamespace},
+				Data:       makeCerts(t, time.Now().Add(-30*time.Minute), 1*time.Hour),
+				Type:       corev1.SecretTypeTLS,
+			},
+			trustedByKubeAPIServer: true,
+			expectedDelay:          0,
+			expectedChange:         true,
+			expectedError:          false,
+		},
+		{
+			name: "input certificate with start validity from the future within the clock skew tolerance - expect delay",
 			secret: &corev1.Secret{
 				ObjectMeta: metav1.ObjectMeta{Name: "csr-signer", Namespace: operatorclient.OperatorNamespace},
-				Data:       makeCerts(t, time.Now().Add(-3*time.Minute), 1*time.Hour),
+				Data:       makeCerts(t, time.Now().Add(2*time.Minute), 1*time.Hour),
+				Type:       corev1.SecretTypeTLS,
+			},
+			target: &corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{Name: "csr-signer", Namespace: operatorclient.TargetNamespace},
+				Data:       makeCerts(t, time.Now().Add(-10*time.Minute), 1*time.Hour),
 				Type:       corev1.SecretTypeTLS,
 			},
-			expectedDelay:  2 * time.Minute,
+			expectedDelay:  2*time.Minute + DefaultCSRSignerRequeueDelays.Padding,
 			expectedChange: false,
 			expectedError:  false,
 		},
 		{
-			name: "input certificate with start validity from the future a lot - expect delay",
+			name: "input certificate with start validity from the future a lot - expect a recheck",
 			secret: &corev1.Secret{
 				ObjectMeta: metav1.ObjectMeta{Name: "csr-signer", Namespace: operatorclient.OperatorNamespace},
 				Data:       makeCerts(t, time.Now().Add(1*time.Hour), 1*time.Hour),
@@ -172,7 +211,7 @@ func TestManageCSRSigner(t *testing.T) {
 				Data:       makeCerts(t, time.Now().Add(-10*time.Minute), 1*time.Hour),
 				Type:       corev1.SecretTypeTLS,
 			},
-			expectedDelay:  65 * time.Minute,
+			expectedDelay:  DefaultCSRSignerRequeueDelays.TrustRecheckInterval,
 			expectedChange: false,
 			expectedError:  false,
 		},
@@ -305,7 +344,17 @@ func TestManageCSRSigner(t *testing.T) {
 				t.Fatal(err.Error())
 			}
 			lister := corev1listers.NewSecretLister(indexer)
-			_, delay, changed, err := ManageCSRSigner(context.Background(), lister, client.CoreV1(), events.NewInMemoryRecorder("target-config-controller"))
+			configMapIndexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc})
+			if test.trustedByKubeAPIServer {
+				if err := configMapIndexer.Add(&corev1.ConfigMap{
+					ObjectMeta: metav1.ObjectMeta{Namespace: operatorclient.GlobalMachineSpecifiedConfigNamespace, Name: kubeletClientCAName},
+					Data:       map[string]string{"ca-bundle.crt": string(test.secret.Data["tls.crt"])},
+				}); err != nil {
+					t.Fatal(err)
+				}
+			}
+			configMapLister := corev1listers.NewConfigMapLister(configMapIndexer)
+			_, delay, changed, err := ManageCSRSigner(context.Background(), lister, configMapLister, client.CoreV1(), events.NewInMemoryRecorder("target-config-controller"), DefaultCSRSignerRequeueDelays)
 			// there's a 10s difference we need to account for to avoid flakes
 			offset := 10 * time.Second
 			if delay < test.expectedDelay-offset || delay > test.expectedDelay+offset {
@@ -320,6 +369,15 @@ func TestManageCSRSigner(t *testing.T) {
 			if test.expectedError && err == nil {
 				t.Error("Expected error but got none")
 			}
+			if changed {
+				replaced, err := client.CoreV1().Secrets(operatorclient.TargetNamespace).Get(context.Background(), "csr-signer", metav1.GetOptions{})
+				if err != nil {
+					t.Fatal(err)
+				}
+				if len(replaced.Annotations[CSRSignerReplaceReasonAnnotation]) == 0 {
+					t.Errorf("Expected the replace reason to be recorded, got annotations %v", replaced.Annotations)
+				}
+			}
 		})
 	}
 
@@ -430,3 +488,912 @@ func TestReadKubeControllerManagerArgs(t *testing.T) {
 		})
 	}
 }
+
+func TestApplyProbeProfile(t *testing.T) {
+	newPod := func() *corev1.Pod {
+		return resourceread.ReadPodV1OrDie(bindata.MustAsset("assets/kube-controller-manager/pod.yaml"))
+	}
+
+	t.Run("default", func(t *testing.T) {
+		pod := newPod()
+		applyProbeProfile(pod, tuning.DefaultProbeProfile)
+		if !reflect.DeepEqual(pod, newPod()) {
+			t.Errorf("expected the Default profile to keep the pod manifest unchanged")
+		}
+	})
+
+	t.Run("slow-storage", func(t *testing.T) {
+		pod := newPod()
+		applyProbeProfile(pod, tuning.SlowStorageProbeProfile)
+		for _, container := range pod.Spec.Containers {
+			if container.LivenessProbe == nil {
+				continue
+			}
+			if container.LivenessProbe.InitialDelaySeconds != 90 || container.LivenessProbe.FailureThreshold != 6 {
+				t.Errorf("unexpected liveness probe for %s: %#v", container.Name, container.LivenessProbe)
+			}
+			if container.ReadinessProbe.InitialDelaySeconds != 30 || container.ReadinessProbe.FailureThreshold != 6 {
+				t.Errorf("unexpected readiness probe for %s: %#v", container.Name, container.ReadinessProbe)
+			}
+			if container.StartupProbe.FailureThreshold != 10 {
+				t.Errorf("unexpected startup probe for %s: %#v", container.Name, container.StartupProbe)
+			}
+		}
+	})
+}
+
+func TestManagePodSecurePort(t *testing.T) {
+	kubeClient := fake.NewSimpleClientset(&corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Namespace: operatorclient.TargetNamespace, Name: "config"},
+		Data:       map[string]string{"config.yaml": `{"extendedArguments":{"secure-port":["10258"]}}`},
+	})
+	operatorSpec := &operatorv1.StaticPodOperatorSpec{}
+	operatorSpec.ObservedConfig.Raw = []byte(`{}`)
+
+	cm, _, err := managePod(context.TODO(), nil, kubeClient.CoreV1(), kubeClient.CoreV1(), events.NewInMemoryRecorder("target-config"), operatorSpec, &tuning.Config{}, "kcm", "operator", "cpc", false, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	container := resourceread.ReadPodV1OrDie([]byte(cm.Data["pod.yaml"])).Spec.Containers[0]
+	if port := container.Ports[0].ContainerPort; port != 10258 {
+		t.Errorf("expected container port 10258, got %d", port)
+	}
+	for _, probe := range []*corev1.Probe{container.StartupProbe, container.LivenessProbe, container.ReadinessProbe} {
+		if port := probe.HTTPGet.Port.IntValue(); port != 10258 {
+			t.Errorf("expected the probes on port 10258, got %d", port)
+		}
+	}
+	if !strings.Contains(container.Args[0], "sport = 10258") || !strings.Contains(container.Args[0], "--secure-port=10258") {
+		t.Errorf("expected the kube-controller-manager to wait for and listen on port 10258, got %q", container.Args[0])
+	}
+}
+
+func TestObservedConfigMissingConditions(t *testing.T) {
+	now := time.Now()
+	missingErr := fmt.Errorf("extendedArguments.cluster-name missing from config")
+
+	tests := []struct {
+		name             string
+		conditions       []operatorv1.OperatorCondition
+		expectedDegraded bool
+		expectedSince    time.Time
+	}{
+		{
+			name:          "first time missing",
+			expectedSince: now,
+		},
+		{
+			name: "previously present",
+			conditions: []operatorv1.OperatorCondition{
+				{Type: observedConfigMissingCondition, Status: operatorv1.ConditionFalse, LastTransitionTime: metav1.NewTime(now.Add(-time.Hour))},
+			},
+			expectedSince: now,
+		},
+		{
+			name: "missing within grace period",
+			conditions: []operatorv1.OperatorCondition{
+				{Type: observedConfigMissingCondition, Status: operatorv1.ConditionTrue, LastTransitionTime: metav1.NewTime(now.Add(-time.Minute))},
+			},
+			expectedSince: now.Add(-time.Minute),
+		},
+		{
+			name: "missing beyond grace period",
+			conditions: []operatorv1.OperatorCondition{
+				{Type: observedConfigMissingCondition, Status: operatorv1.ConditionTrue, LastTransitionTime: metav1.NewTime(now.Add(-10 * time.Minute))},
+			},
+			expectedDegraded: true,
+			expectedSince:    now.Add(-10 * time.Minute),
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			status := &operatorv1.StaticPodOperatorStatus{OperatorStatus: operatorv1.OperatorStatus{Conditions: test.conditions}}
+			conditions := observedConfigMissingConditions(status, missingErr, now)
+
+			missing := v1helpers.FindOperatorCondition(conditions, observedConfigMissingCondition)
+			if missing == nil || missing.Status != operatorv1.ConditionTrue {
+				t.Fatalf("expected %s=True, got %v", observedConfigMissingCondition, conditions)
+			}
+			if expected := test.expectedSince.UTC().Format(time.RFC3339); !strings.Contains(missing.Message, expected) || !strings.Contains(missing.Message, "cluster-name") {
+				t.Errorf("expected message to name the missing path and %s, got %q", expected, missing.Message)
+			}
+
+			degraded := v1helpers.FindOperatorCondition(conditions, "TargetConfigControllerDegraded")
+			if test.expectedDegraded != (degraded != nil && degraded.Status == operatorv1.ConditionTrue) {
+				t.Errorf("expected degraded %v, got %v", test.expectedDegraded, conditions)
+			}
+		})
+	}
+}
+
+func TestResolveOperandImages(t *testing.T) {
+	defaults := tuning.OperandImages{
+		KubeControllerManager:   "payload/hyperkube",
+		ClusterPolicyController: "payload/cpc",
+		Operator:                "payload/operator",
+	}
+	node := func(architecture string) *corev1.Node {
+		return &corev1.Node{Status: corev1.NodeStatus{NodeInfo: corev1.NodeSystemInfo{Architecture: architecture}}}
+	}
+	manifestLists := map[string]sets.Set[string]{
+		"payload/hyperkube":  sets.New("amd64", "arm64"),
+		"payload/cpc":        sets.New("amd64", "arm64"),
+		"payload/operator":   sets.New("amd64", "arm64"),
+		"custom/cpc-arm64":   sets.New("arm64"),
+		"custom/cpc-multi":   sets.New("amd64", "arm64"),
+		"custom/cpc-ppc64le": sets.New("ppc64le"),
+	}
+	inspect := func(_ context.Context, pullSpec string) (sets.Set[string], error) {
+		architectures, ok := manifestLists[pullSpec]
+		if !ok {
+			return nil, fmt.Errorf("manifest unknown")
+		}
+		return architectures, nil
+	}
+
+	tests := []struct {
+		name               string
+		architectureImages map[string]tuning.OperandImages
+		nodes              []*corev1.Node
+		expected           tuning.OperandImages
+		expectedCondition  operatorv1.ConditionStatus
+		expectedError      bool
+	}{
+		{
+			name:              "no nodes",
+			expected:          defaults,
+			expectedCondition: operatorv1.ConditionFalse,
+		},
+		{
+			name:              "architecture without overrides",
+			nodes:             []*corev1.Node{node("amd64"), node("amd64")},
+			expected:          defaults,
+			expectedCondition: operatorv1.ConditionFalse,
+		},
+		{
+			name:               "architecture with overrides",
+			architectureImages: map[string]tuning.OperandImages{"arm64": {ClusterPolicyController: "custom/cpc-arm64"}},
+			nodes:              []*corev1.Node{node("arm64"), node("arm64"), node("arm64")},
+			expected: tuning.OperandImages{
+				KubeControllerManager:   "payload/hyperkube",
+				ClusterPolicyController: "custom/cpc-arm64",
+				Operator:                "payload/operator",
+			},
+			expectedCondition: operatorv1.ConditionFalse,
+		},
+		{
+			name:               "override of another architecture",
+			architectureImages: map[string]tuning.OperandImages{"arm64": {ClusterPolicyController: "custom/cpc-ppc64le"}},
+			nodes:              []*corev1.Node{node("arm64")},
+			expected:           defaults,
+			expectedCondition:  operatorv1.ConditionTrue,
+			expectedError:      true,
+		},
+		{
+			name:               "override that cannot be inspected",
+			architectureImages: map[string]tuning.OperandImages{"arm64": {ClusterPolicyController: "custom/missing"}},
+			nodes:              []*corev1.Node{node("arm64")},
+			expected: tuning.OperandImages{
+				KubeControllerManager:   "payload/hyperkube",
+				ClusterPolicyController: "custom/missing",
+				Operator:                "payload/operator",
+			},
+			expectedCondition: operatorv1.ConditionUnknown,
+		},
+		{
+			name:              "heterogeneous control plane with manifest lists",
+			nodes:             []*corev1.Node{node("arm64"), node("amd64")},
+			expected:          defaults,
+			expectedCondition: operatorv1.ConditionFalse,
+		},
+		{
+			name: "heterogeneous control plane with a manifest list override",
+			architectureImages: map[string]tuning.OperandImages{
+				"amd64": {ClusterPolicyController: "custom/cpc-multi"},
+				"arm64": {ClusterPolicyController: "custom/cpc-multi"},
+			},
+			nodes: []*corev1.Node{node("arm64"), node("amd64")},
+			expected: tuning.OperandImages{
+				KubeControllerManager:   "payload/hyperkube",
+				ClusterPolicyController: "custom/cpc-multi",
+				Operator:                "payload/operator",
+			},
+			expectedCondition: operatorv1.ConditionFalse,
+		},
+		{
+			name: "heterogeneous control plane with an override lacking an architecture",
+			architectureImages: map[string]tuning.OperandImages{
+				"amd64": {ClusterPolicyController: "custom/cpc-arm64"},
+				"arm64": {ClusterPolicyController: "custom/cpc-arm64"},
+			},
+			nodes:             []*corev1.Node{node("arm64"), node("amd64")},
+			expected:          defaults,
+			expectedCondition: operatorv1.ConditionTrue,
+			expectedError:     true,
+		},
+		{
+			name:               "heterogeneous control plane with different overrides",
+			architectureImages: map[string]tuning.OperandImages{"arm64": {ClusterPolicyController: "custom/cpc-arm64"}},
+			nodes:              []*corev1.Node{node("arm64"), node("amd64")},
+			expected:           defaults,
+			expectedCondition:  operatorv1.ConditionTrue,
+			expectedError:      true,
+		},
+		{
+			name:              "heterogeneous control plane with payload images lacking an architecture",
+			nodes:             []*corev1.Node{node("ppc64le"), node("amd64")},
+			expected:          defaults,
+			expectedCondition: operatorv1.ConditionTrue,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			actual, condition, err := resolveOperandImages(context.TODO(), defaults, test.architectureImages, test.nodes, inspect)
+			if test.expectedError != (err != nil) {
+				t.Errorf("expected error %v, got %v", test.expectedError, err)
+			}
+			if actual != test.expected {
+				t.Errorf("expected %#v, got %#v", test.expected, actual)
+			}
+			if condition.Type != operandImageArchitectureMismatchCondition || condition.Status != test.expectedCondition {
+				t.Errorf("expected %s to be %s, got %#v", operandImageArchitectureMismatchCondition, test.expectedCondition, condition)
+			}
+		})
+	}
+}
+
+func TestUpdateDegradedCondition(t *testing.T) {
+	operatorClient := v1helpers.NewFakeStaticPodOperatorClient(
+		&operatorv1.StaticPodOperatorSpec{},
+		&operatorv1.StaticPodOperatorStatus{},
+		nil,
+		nil,
+	)
+	c := &TargetConfigController{
+		operatorClient: operatorClient,
+		syncErrors:     map[string]error{},
+		syncFailures:   map[string]int32{},
+	}
+	c.syncers = c.newSyncers()
+
+	degradedCondition := func() *operatorv1.OperatorCondition {
+		_, status, _, err := operatorClient.GetStaticPodOperatorState()
+		if err != nil {
+			t.Fatal(err)
+		}
+		return v1helpers.FindOperatorCondition(status.Conditions, "TargetConfigControllerDegraded")
+	}
+
+	if err := c.updateDegradedCondition(context.TODO(), "pod", fmt.Errorf("pod failed"), 1); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.updateDegradedCondition(context.TODO(), "config", fmt.Errorf("config failed"), 1); err != nil {
+		t.Fatal(err)
+	}
+	if condition := degradedCondition(); condition.Status != operatorv1.ConditionTrue || condition.Message != "config failed\npod failed" {
+		t.Fatalf("expected both errors in syncer order, got %#v", condition)
+	}
+
+	// a successful sync of another resource keeps the recorded errors
+	if err := c.updateDegradedCondition(context.TODO(), "recycler-config", nil, 1); err != nil {
+		t.Fatal(err)
+	}
+	if condition := degradedCondition(); condition.Message != "config failed\npod failed" {
+		t.Fatalf("unexpected message %q", condition.Message)
+	}
+
+	if err := c.updateDegradedCondition(context.TODO(), "config", nil, 1); err != nil {
+		t.Fatal(err)
+	}
+	if condition := degradedCondition(); condition.Status != operatorv1.ConditionTrue || condition.Message != "pod failed" {
+		t.Fatalf("expected only the pod error, got %#v", condition)
+	}
+
+	if err := c.updateDegradedCondition(context.TODO(), "pod", nil, 1); err != nil {
+		t.Fatal(err)
+	}
+	if condition := degradedCondition(); condition.Status != operatorv1.ConditionFalse {
+		t.Fatalf("expected not degraded, got %#v", condition)
+	}
+}
+
+func TestUpdateDegradedConditionDampening(t *testing.T) {
+	operatorClient := v1helpers.NewFakeStaticPodOperatorClient(
+		&operatorv1.StaticPodOperatorSpec{},
+		&operatorv1.StaticPodOperatorStatus{},
+		nil,
+		nil,
+	)
+	c := &TargetConfigController{
+		operatorClient: operatorClient,
+		syncErrors:     map[string]error{},
+		syncFailures:   map[string]int32{},
+	}
+	c.syncers = c.newSyncers()
+	recorder := conditiontest.NewStatusRecorder(t, operatorClient)
+
+	update := func(syncErr error, expected operatorv1.ConditionStatus) {
+		t.Helper()
+		if err := c.updateDegradedCondition(context.TODO(), "csr-signer", syncErr, 3); err != nil {
+			t.Fatal(err)
+		}
+		recorder.Check(t)
+		_, status, _, err := operatorClient.GetStaticPodOperatorState()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if condition := v1helpers.FindOperatorCondition(status.Conditions, "TargetConfigControllerDegraded"); condition == nil || condition.Status != expected {
+			t.Fatalf("expected TargetConfigControllerDegraded=%s, got %#v", expected, condition)
+		}
+	}
+
+	// a failure during a rotation that the next sync fixes is never reported
+	update(fmt.Errorf("csr-signer failed"), operatorv1.ConditionFalse)
+	update(fmt.Errorf("csr-signer failed"), operatorv1.ConditionFalse)
+	update(nil, operatorv1.ConditionFalse)
+
+	// the count starts over after a success
+	update(fmt.Errorf("csr-signer failed"), operatorv1.ConditionFalse)
+	update(fmt.Errorf("csr-signer failed"), operatorv1.ConditionFalse)
+	update(fmt.Errorf("csr-signer failed"), operatorv1.ConditionTrue)
+	update(fmt.Errorf("csr-signer still failing"), operatorv1.ConditionTrue)
+
+	// a success clears the condition at once
+	update(nil, operatorv1.ConditionFalse)
+}
+
+func TestManagePodMetricsServingCert(t *testing.T) {
+	for _, test := range []struct {
+		name     string
+		secrets  []string
+		expected bool
+	}{
+		{name: "no metrics serving cert yet", secrets: []string{"serving-cert"}},
+		{name: "metrics serving cert", secrets: []string{"serving-cert", "metrics-serving-cert"}, expected: true},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			kubeClient := fake.NewSimpleClientset()
+			for _, name := range test.secrets {
+				if _, err := kubeClient.CoreV1().Secrets(operatorclient.TargetNamespace).Create(context.TODO(), &corev1.Secret{
+					ObjectMeta: metav1.ObjectMeta{Namespace: operatorclient.TargetNamespace, Name: name},
+				}, metav1.CreateOptions{}); err != nil {
+					t.Fatal(err)
+				}
+			}
+			operatorSpec := &operatorv1.StaticPodOperatorSpec{}
+			operatorSpec.ObservedConfig.Raw = []byte(`{}`)
+
+			cm, _, err := managePod(context.TODO(), nil, kubeClient.CoreV1(), kubeClient.CoreV1(), events.NewInMemoryRecorder("target-config"), operatorSpec, &tuning.Config{}, "kcm", "operator", "cpc", false, true)
+			if err != nil {
+				t.Fatal(err)
+			}
+			pod := resourceread.ReadPodV1OrDie([]byte(cm.Data["pod.yaml"]))
+			actual := strings.Contains(pod.Spec.Containers[0].Args[0], "--tls-sni-cert-key=/etc/kubernetes/static-pod-certs/secrets/metrics-serving-cert/tls.crt,/etc/kubernetes/static-pod-certs/secrets/metrics-serving-cert/tls.key:"+metricsServerName)
+			if actual != test.expected {
+				t.Errorf("expected the metrics sni cert %v, got args %q", test.expected, pod.Spec.Containers[0].Args[0])
+			}
+		})
+	}
+}
+
+func TestManagePodComponentLogLevels(t *testing.T) {
+	for _, test := range []struct {
+		name               string
+		logLevel           operatorv1.LogLevel
+		componentLogLevels map[string]operatorv1.LogLevel
+		expected           map[string]string
+	}{
+		{
+			name:     "default",
+			expected: map[string]string{"kube-controller-manager": "-v=2", "cluster-policy-controller": "-v=2", "kube-controller-manager-recovery-controller": "-v=2"},
+		},
+		{
+			name:     "operand log level",
+			logLevel: operatorv1.Debug,
+			expected: map[string]string{"kube-controller-manager": "-v=4", "cluster-policy-controller": "-v=4", "kube-controller-manager-recovery-controller": "-v=4"},
+		},
+		{
+			name:               "component override",
+			logLevel:           operatorv1.Debug,
+			componentLogLevels: map[string]operatorv1.LogLevel{"cluster-policy-controller": operatorv1.TraceAll, "kube-controller-manager-recovery-controller": operatorv1.Normal},
+			expected:           map[string]string{"kube-controller-manager": "-v=4", "cluster-policy-controller": "-v=8", "kube-controller-manager-recovery-controller": "-v=2"},
+		},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			kubeClient := fake.NewSimpleClientset()
+			operatorSpec := &operatorv1.StaticPodOperatorSpec{OperatorSpec: operatorv1.OperatorSpec{LogLevel: test.logLevel, OperatorLogLevel: operatorv1.TraceAll}}
+			operatorSpec.ObservedConfig.Raw = []byte(`{}`)
+
+			cm, _, err := managePod(context.TODO(), nil, kubeClient.CoreV1(), kubeClient.CoreV1(), events.NewInMemoryRecorder("target-config"), operatorSpec, &tuning.Config{ComponentLogLevels: test.componentLogLevels}, "kcm", "operator", "cpc", false, true)
+			if err != nil {
+				t.Fatal(err)
+			}
+			pod := resourceread.ReadPodV1OrDie([]byte(cm.Data["pod.yaml"]))
+			for _, container := range pod.Spec.Containers {
+				expected, ok := test.expected[container.Name]
+				if !ok {
+					continue
+				}
+				if !strings.Contains(container.Args[0], " "+expected) {
+					t.Errorf("expected %s in the %s args, got %q", expected, container.Name, container.Args[0])
+				}
+			}
+		})
+	}
+}
+
+func TestManagePodTuningEnv(t *testing.T) {
+	kubeClient := fake.NewSimpleClientset()
+	operatorSpec := &operatorv1.StaticPodOperatorSpec{}
+	operatorSpec.ObservedConfig.Raw = []byte(`{"targetconfigcontroller":{"proxy":{"HTTPS_PROXY":"https://proxy"}}}`)
+
+	cm, _, err := managePod(context.TODO(), nil, kubeClient.CoreV1(), kubeClient.CoreV1(), events.NewInMemoryRecorder("target-config"), operatorSpec, &tuning.Config{Env: map[string]string{"GOGC": "200", "AWS_REGION": "us-east-1"}}, "kcm", "operator", "cpc", false, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pod := resourceread.ReadPodV1OrDie([]byte(cm.Data["pod.yaml"]))
+	for _, container := range pod.Spec.Containers {
+		var names []string
+		for _, env := range container.Env {
+			names = append(names, env.Name)
+		}
+		if got := strings.Join(names, ","); !strings.HasSuffix(got, "HTTPS_PROXY,AWS_REGION,GOGC") {
+			t.Errorf("expected the tuning env after the proxy env in %s, got %s", container.Name, got)
+		}
+	}
+}
+
+func TestManagePodProxyRemoved(t *testing.T) {
+	kubeClient := fake.NewSimpleClientset()
+	operatorSpec := &operatorv1.StaticPodOperatorSpec{}
+	operatorSpec.ObservedConfig.Raw = []byte(`{"targetconfigcontroller":{"proxy":{"HTTPS_PROXY":"https://proxy","NO_PROXY":".cluster.local"}}}`)
+	if _, _, err := managePod(context.TODO(), nil, kubeClient.CoreV1(), kubeClient.CoreV1(), events.NewInMemoryRecorder("target-config"), operatorSpec, &tuning.Config{}, "kcm", "operator", "cpc", false, true); err != nil {
+		t.Fatal(err)
+	}
+
+	// the proxy is removed from proxy/cluster
+	operatorSpec.ObservedConfig.Raw = []byte(`{}`)
+	recorder := events.NewInMemoryRecorder("target-config")
+	cm, changed, err := managePod(context.TODO(), nil, kubeClient.CoreV1(), kubeClient.CoreV1(), recorder, operatorSpec, &tuning.Config{}, "kcm", "operator", "cpc", false, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !changed {
+		t.Errorf("expected the pod to change for a new revision")
+	}
+	pod := resourceread.ReadPodV1OrDie([]byte(cm.Data["pod.yaml"]))
+	for _, container := range pod.Spec.Containers {
+		for _, env := range container.Env {
+			if proxyEnvVarNames.Has(env.Name) {
+				t.Errorf("expected no proxy env in %s, got %s", container.Name, env.Name)
+			}
+		}
+	}
+	var removedEvent bool
+	for _, event := range recorder.Events() {
+		if event.Reason == "ProxyEnvRemoved" {
+			removedEvent = strings.Contains(event.Message, "kube-controller-manager/HTTPS_PROXY") && strings.Contains(event.Message, "kube-controller-manager/NO_PROXY")
+		}
+	}
+	if !removedEvent {
+		t.Errorf("expected a ProxyEnvRemoved event listing the removed env vars, got %v", recorder.Events())
+	}
+}
+
+func TestSetProxyEnvVars(t *testing.T) {
+	container := &corev1.Container{Env: []corev1.EnvVar{
+		{Name: "POD_NAME"},
+		{Name: "HTTP_PROXY", Value: "http://stale"},
+		{Name: "no_proxy", Value: "stale"},
+		{Name: "GOGC", Value: "200"},
+	}}
+	setProxyEnvVars(container, []corev1.EnvVar{{Name: "HTTPS_PROXY", Value: "https://proxy"}})
+	expected := []corev1.EnvVar{{Name: "POD_NAME"}, {Name: "GOGC", Value: "200"}, {Name: "HTTPS_PROXY", Value: "https://proxy"}}
+	if !reflect.DeepEqual(container.Env, expected) {
+		t.Errorf("expected %v, got %v", expected, container.Env)
+	}
+
+	setProxyEnvVars(container, nil)
+	if len(container.Env) != 2 {
+		t.Errorf("expected the proxy env to be dropped, got %v", container.Env)
+	}
+}
+
+func TestEnsureKubeControllerManagerTrustedCA(t *testing.T) {
+	tests := []struct {
+		name          string
+		existing      *corev1.ConfigMap
+		expected      *corev1.ConfigMap
+		expectedEvent string
+	}{
+		{
+			name: "missing",
+			expected: &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{
+				Labels:      map[string]string{"config.openshift.io/inject-trusted-cabundle": "true"},
+				Annotations: map[string]string{"openshift.io/owning-component": "kube-controller-manager"},
+			}},
+			expectedEvent: "ConfigMapCreated",
+		},
+		{
+			name: "user metadata and injected data are preserved",
+			existing: &corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{
+					Labels:      map[string]string{"team": "infra"},
+					Annotations: map[string]string{"openshift.io/owning-component": "kube-controller-manager", "note": "keep"},
+				},
+				Data: map[string]string{"ca-bundle.crt": "injected"},
+			},
+			expected: &corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{
+					Labels:      map[string]string{"team": "infra", "config.openshift.io/inject-trusted-cabundle": "true"},
+					Annotations: map[string]string{"openshift.io/owning-component": "kube-controller-manager", "note": "keep"},
+				},
+				Data: map[string]string{"ca-bundle.crt": "injected"},
+			},
+			expectedEvent: "ConfigMapUpdated",
+		},
+		{
+			name: "created by other tooling",
+			existing: &corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{
+					Labels: map[string]string{"config.openshift.io/inject-trusted-cabundle": "true"},
+				},
+			},
+			expected: &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{
+				Labels:      map[string]string{"config.openshift.io/inject-trusted-cabundle": "true"},
+				Annotations: map[string]string{"openshift.io/owning-component": "kube-controller-manager"},
+			}},
+			expectedEvent: "TrustedCABundleAdopted",
+		},
+		{
+			name: "up to date",
+			existing: &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{
+				Labels:      map[string]string{"config.openshift.io/inject-trusted-cabundle": "true"},
+				Annotations: map[string]string{"openshift.io/owning-component": "kube-controller-manager"},
+			}},
+			expected: &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{
+				Labels:      map[string]string{"config.openshift.io/inject-trusted-cabundle": "true"},
+				Annotations: map[string]string{"openshift.io/owning-component": "kube-controller-manager"},
+			}},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			kubeClient := fake.NewSimpleClientset()
+			if test.existing != nil {
+				test.existing.Namespace, test.existing.Name = operatorclient.TargetNamespace, "trusted-ca-bundle"
+				kubeClient = fake.NewSimpleClientset(test.existing)
+			}
+			recorder := events.NewInMemoryRecorder("target-config")
+
+			if err := ensureKubeControllerManagerTrustedCA(context.TODO(), kubeClient.CoreV1(), recorder); err != nil {
+				t.Fatal(err)
+			}
+
+			actual, err := kubeClient.CoreV1().ConfigMaps(operatorclient.TargetNamespace).Get(context.TODO(), "trusted-ca-bundle", metav1.GetOptions{})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(test.expected.Labels, actual.Labels) || !reflect.DeepEqual(test.expected.Annotations, actual.Annotations) || !reflect.DeepEqual(test.expected.Data, actual.Data) {
+				t.Errorf("unexpected configmap: %#v", actual)
+			}
+
+			var reasons []string
+			for _, event := range recorder.Events() {
+				reasons = append(reasons, event.Reason)
+			}
+			if actual := strings.Join(reasons, ","); actual != test.expectedEvent {
+				t.Errorf("expected event %q, got %q", test.expectedEvent, actual)
+			}
+		})
+	}
+}
+
+func TestEnsureLocalhostRecoverySAToken(t *testing.T) {
+	serviceAccount := &corev1.ServiceAccount{ObjectMeta: metav1.ObjectMeta{Namespace: operatorclient.TargetNamespace, Name: "localhost-recovery-client", UID: "current"}}
+	tokenFor := func(uid string) *corev1.Secret {
+		return &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace:   operatorclient.TargetNamespace,
+				Name:        "localhost-recovery-client-token",
+				Annotations: map[string]string{corev1.ServiceAccountNameKey: serviceAccount.Name, corev1.ServiceAccountUIDKey: uid},
+			},
+			Data: map[string][]byte{"token": []byte("token"), "ca.crt": []byte("ca")},
+		}
+	}
+
+	tests := []struct {
+		name            string
+		token           *corev1.Secret
+		expectedError   string
+		expectRecreated bool
+	}{
+		{
+			name:  "token of the current SA",
+			token: tokenFor("current"),
+		},
+		{
+			name:            "token of a previous SA is recreated",
+			token:           tokenFor("previous"),
+			expectedError:   "was recreated for the current SA",
+			expectRecreated: true,
+		},
+		{
+			name:          "token not populated yet",
+			token:         tokenFor(""),
+			expectedError: "missing SA UID",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			kubeClient := fake.NewSimpleClientset(serviceAccount, test.token)
+			recorder := events.NewInMemoryRecorder("target-config-controller")
+
+			err := ensureLocalhostRecoverySAToken(context.TODO(), kubeClient.CoreV1(), recorder)
+			if len(test.expectedError) == 0 && err != nil || len(test.expectedError) > 0 && (err == nil || !strings.Contains(err.Error(), test.expectedError)) {
+				t.Fatalf("expected error %q, got %v", test.expectedError, err)
+			}
+
+			token, err := kubeClient.CoreV1().Secrets(operatorclient.TargetNamespace).Get(context.TODO(), test.token.Name, metav1.GetOptions{})
+			if err != nil {
+				t.Fatal(err)
+			}
+			recreated := len(token.Data) == 0 && len(token.Annotations[corev1.ServiceAccountUIDKey]) == 0
+			if recreated != test.expectRecreated {
+				t.Errorf("expected recreated %v, got %#v", test.expectRecreated, token)
+			}
+			if recreated && (len(recorder.Events()) != 1 || recorder.Events()[0].Reason != "LocalhostRecoveryTokenRecreated") {
+				t.Errorf("expected a LocalhostRecoveryTokenRecreated event, got %v", recorder.Events())
+			}
+		})
+	}
+}
+
+func TestManageCSRCABundleAdditionalCAs(t *testing.T) {
+	signerCA := makeCerts(t, time.Now(), time.Hour)["tls.crt"]
+	includedCA := makeCerts(t, time.Now(), time.Hour)["tls.crt"]
+	ignoredCA := makeCerts(t, time.Now(), time.Hour)["tls.crt"]
+
+	indexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{})
+	for _, cm := range []*corev1.ConfigMap{
+		{
+			ObjectMeta: metav1.ObjectMeta{Namespace: operatorclient.OperatorNamespace, Name: "csr-signer-ca"},
+			Data:       map[string]string{"ca-bundle.crt": string(signerCA)},
+		},
+		{
+			ObjectMeta: metav1.ObjectMeta{Namespace: operatorclient.GlobalUserSpecifiedConfigNamespace, Name: "layered-product-ca", Labels: map[string]string{IncludeInCSRCABundleLabel: "true"}},
+			Data:       map[string]string{"ca-bundle.crt": string(includedCA)},
+		},
+		{
+			ObjectMeta: metav1.ObjectMeta{Namespace: operatorclient.GlobalUserSpecifiedConfigNamespace, Name: "unrelated-ca"},
+			Data:       map[string]string{"ca-bundle.crt": string(ignoredCA)},
+		},
+	} {
+		if err := indexer.Add(cm); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	kubeClient := fake.NewSimpleClientset()
+	bundle, _, err := ManageCSRCABundle(context.TODO(), cabundle.NewRegistry(), corev1listers.NewConfigMapLister(indexer), kubeClient.CoreV1(), events.NewInMemoryRecorder("target-config"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expected := string(signerCA) + string(includedCA); bundle.Data["ca-bundle.crt"] != expected {
+		t.Errorf("expected the signer and the labeled CA in the bundle, got:\n%s", bundle.Data["ca-bundle.crt"])
+	}
+}
+
+func TestRecordSyncErrorConcurrently(t *testing.T) {
+	c := &TargetConfigController{syncErrors: map[string]error{}, syncFailures: map[string]int32{}}
+	c.syncers = c.newSyncers()
+
+	var wg sync.WaitGroup
+	for _, syncer := range c.syncers {
+		wg.Add(1)
+		go func(name string) {
+			defer wg.Done()
+			c.recordSyncError(name, fmt.Errorf("%s failed", name), 1)
+		}(syncer.name)
+	}
+	wg.Wait()
+
+	errors := c.recordSyncError("pod", fmt.Errorf("pod failed"), 1)
+	if len(errors) != len(c.syncers) {
+		t.Fatalf("expected %d errors, got %v", len(c.syncers), errors)
+	}
+	for i, syncer := range c.syncers {
+		if expected := syncer.name + " failed"; errors[i].Error() != expected {
+			t.Errorf("expected %q at %d, got %q", expected, i, errors[i])
+		}
+	}
+}
+
+func TestPausedCondition(t *testing.T) {
+	c := &TargetConfigController{}
+	syncers := c.newSyncers()
+
+	tests := []struct {
+		name            string
+		pausedResources []string
+		expected        operatorv1.OperatorCondition
+	}{
+		{
+			name:     "nothing paused",
+			expected: operatorv1.OperatorCondition{Type: pausedConditionType, Status: operatorv1.ConditionFalse},
+		},
+		{
+			name:            "pod paused",
+			pausedResources: []string{"pod", "config"},
+			expected: operatorv1.OperatorCondition{
+				Type:    pausedConditionType,
+				Status:  operatorv1.ConditionTrue,
+				Reason:  "PausedByUser",
+				Message: "The management of configmap/config, configmap/kube-controller-manager-pod is paused",
+			},
+		},
+		{
+			name:            "unknown resource",
+			pausedResources: []string{"deployment"},
+			expected: operatorv1.OperatorCondition{
+				Type:    pausedConditionType,
+				Status:  operatorv1.ConditionTrue,
+				Reason:  "PausedByUser",
+				Message: "No resource is paused, unknown paused resources: deployment",
+			},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if actual := pausedCondition(syncers, test.pausedResources); !reflect.DeepEqual(test.expected, actual) {
+				t.Errorf("expected %#v, got %#v", test.expected, actual)
+			}
+		})
+	}
+}
+
+func TestApplyWorkloadPartitioning(t *testing.T) {
+	pod := &corev1.Pod{Spec: corev1.PodSpec{
+		InitContainers: []corev1.Container{{Name: "setup"}},
+		Containers: []corev1.Container{
+			{
+				Name: "kube-controller-manager",
+				Resources: corev1.ResourceRequirements{
+					Requests: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("60m")},
+					Limits:   corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("60m"), corev1.ResourceMemory: resource.MustParse("1Gi")},
+				},
+			},
+		},
+	}}
+	applyWorkloadPartitioning(pod)
+
+	if value := pod.Annotations[managementWorkloadAnnotation]; value != managementWorkloadAnnotationValue {
+		t.Errorf("expected the management workload annotation, got %q", value)
+	}
+	setup := pod.Spec.InitContainers[0].Resources
+	if cpu := setup.Requests[corev1.ResourceCPU]; cpu.String() != "5m" {
+		t.Errorf("expected the default CPU request for the init container, got %s", cpu.String())
+	}
+	kcm := pod.Spec.Containers[0].Resources
+	if cpu := kcm.Requests[corev1.ResourceCPU]; cpu.String() != "60m" {
+		t.Errorf("expected the CPU request to be kept, got %s", cpu.String())
+	}
+	if memory := kcm.Requests[corev1.ResourceMemory]; memory.String() != "50Mi" {
+		t.Errorf("expected the default memory request, got %s", memory.String())
+	}
+	if _, ok := kcm.Limits[corev1.ResourceCPU]; ok {
+		t.Errorf("expected the CPU limit to be removed")
+	}
+	if _, ok := kcm.Limits[corev1.ResourceMemory]; !ok {
+		t.Errorf("expected the memory limit to be kept")
+	}
+}
+
+func TestManagePodFlagsFile(t *testing.T) {
+	kubeClient := fake.NewSimpleClientset(&corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Namespace: operatorclient.TargetNamespace, Name: "config"},
+		Data:       map[string]string{"config.yaml": `{"extendedArguments":{"cluster-name":["example"],"secure-port":["10258"]}}`},
+	})
+	operatorSpec := &operatorv1.StaticPodOperatorSpec{}
+	operatorSpec.ObservedConfig.Raw = []byte(`{}`)
+
+	cm, _, err := managePod(context.TODO(), nil, kubeClient.CoreV1(), kubeClient.CoreV1(), events.NewInMemoryRecorder("target-config"), operatorSpec, &tuning.Config{FlagsFile: true}, "kcm", "operator", "cpc", false, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	script := resourceread.ReadPodV1OrDie([]byte(cm.Data["pod.yaml"])).Spec.Containers[0].Args[0]
+	if strings.Contains(script, "--cluster-name") || !strings.Contains(script, "mapfile -t flags < "+flagsFilePath) || !strings.HasSuffix(script, `"${flags[@]}"`) {
+		t.Errorf("expected the flags to be read from %s, got %q", flagsFilePath, script)
+	}
+	// the port the startup waits for still follows the flags
+	if !strings.Contains(script, "sport = 10258") {
+		t.Errorf("expected the startup to wait for port 10258, got %q", script)
+	}
+	flags, err := kubeClient.CoreV1().ConfigMaps(operatorclient.TargetNamespace).Get(context.TODO(), FlagsConfigMapName, metav1.GetOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expected := "--cluster-name=example\n--secure-port=10258\n"; flags.Data[FlagsKey] != expected {
+		t.Errorf("expected flags %q, got %q", expected, flags.Data[FlagsKey])
+	}
+
+	cm, _, err = managePod(context.TODO(), nil, kubeClient.CoreV1(), kubeClient.CoreV1(), events.NewInMemoryRecorder("target-config"), operatorSpec, &tuning.Config{}, "kcm", "operator", "cpc", false, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	script = resourceread.ReadPodV1OrDie([]byte(cm.Data["pod.yaml"])).Spec.Containers[0].Args[0]
+	if !strings.Contains(script, "--cluster-name=example --secure-port=10258") || strings.Contains(script, "mapfile") {
+		t.Errorf("expected the flags on the exec line, got %q", script)
+	}
+	if _, err := kubeClient.CoreV1().ConfigMaps(operatorclient.TargetNamespace).Get(context.TODO(), FlagsConfigMapName, metav1.GetOptions{}); !apierrors.IsNotFound(err) {
+		t.Errorf("expected the flags configmap to be deleted, got %v", err)
+	}
+}
+
+func TestClockSkewCondition(t *testing.T) {
+	now := time.Now()
+	tests := []struct {
+		name           string
+		notBefore      time.Time
+		expectedStatus operatorv1.ConditionStatus
+	}{
+		{
+			name:           "valid certificates",
+			notBefore:      now.Add(-time.Hour),
+			expectedStatus: operatorv1.ConditionFalse,
+		},
+		{
+			name:           "within the tolerance",
+			notBefore:      now.Add(2 * time.Minute),
+			expectedStatus: operatorv1.ConditionFalse,
+		},
+		{
+			name:           "beyond the tolerance",
+			notBefore:      now.Add(time.Hour),
+			expectedStatus: operatorv1.ConditionTrue,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			indexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc})
+			if err := indexer.Add(&corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{Name: "csr-signer", Namespace: operatorclient.OperatorNamespace},
+				Data:       makeCerts(t, test.notBefore, time.Hour),
+			}); err != nil {
+				t.Fatal(err)
+			}
+			c := &TargetConfigController{secretLister: corev1listers.NewSecretLister(indexer)}
+			condition := c.clockSkewCondition(now)
+			if condition.Status != test.expectedStatus {
+				t.Errorf("expected %s, got %s: %s", test.expectedStatus, condition.Status, condition.Message)
+			}
+		})
+	}
+}
+
+func TestRecordExtendedArgumentChanges(t *testing.T) {
+	configMap := func(config string) *corev1.ConfigMap {
+		return &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Namespace: operatorclient.TargetNamespace, Name: "config"},
+			Data:       map[string]string{"config.yaml": config},
+		}
+	}
+	recorder := events.NewInMemoryRecorder("target-config")
+	recordExtendedArgumentChanges(recorder,
+		configMap(`{"extendedArguments":{"cluster-signing-duration":["720h"],"pv-recycler-pod-template-filepath-nfs":["/recycler.yaml"]}}`),
+		configMap(`{"extendedArguments":{"cluster-signing-duration":["8760h"]}}`),
+	)
+	recordExtendedArgumentChanges(recorder, configMap(`{"extendedArguments":{}}`), configMap(`{"extendedArguments":{}}`))
+
+	if len(recorder.Events()) != 1 {
+		t.Fatalf("expected a single event, got %v", recorder.Events())
+	}
+	event := recorder.Events()[0]
+	expected := "changed --cluster-signing-duration from 720h to 8760h; removed --pv-recycler-pod-template-filepath-nfs=/recycler.yaml"
+	if event.Reason != "ExtendedArgumentsChanged" || !strings.HasSuffix(event.Message, expected) {
+		t.Errorf("expected an ExtendedArgumentsChanged event ending in %q, got %s: %s", expected, event.Reason, event.Message)
+	}
+}
@@ -0,0 +1,144 @@
+package targetconfigcontroller
+
+import (
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+
+	operatorv1 "github.com/openshift/api/operator/v1"
+
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/operatorclient"
+)
+
+const (
+	trustedCABundleWarningCondition = "TrustedCABundleWarning"
+
+	trustedCABundleName = "trusted-ca-bundle"
+	trustedCABundleKey  = "ca-bundle.crt"
+
+	// trustedCABundleSizeLimit leaves room below the 1MiB limit of a configmap. The bundle is copied into every
+	// revision and read by every instance of the operand, a bundle beyond it is likely unintended.
+	trustedCABundleSizeLimit = 512 * 1024
+
+	// maxListedExpiredCertificates bounds the expired certificates named in the condition message.
+	maxListedExpiredCertificates = 5
+)
+
+// trustedCABundleSummary is what the condition needs of the injected bundle, the certificates are not kept.
+type trustedCABundleSummary struct {
+	size         int
+	certificates []trustedCertificate
+	// malformed counts the PEM blocks that are not a parseable certificate
+	malformed int
+}
+
+type trustedCertificate struct {
+	subject  string
+	notAfter time.Time
+}
+
+// trustedCABundleCache keeps the summary of the last parsed bundle. The bundle is injected by the network operator and
+// can hold hundreds of certificates, it is only parsed again once its resource version changes.
+type trustedCABundleCache struct {
+	lock            sync.Mutex
+	uid             types.UID
+	resourceVersion string
+	summary         *trustedCABundleSummary
+}
+
+func (c *trustedCABundleCache) summaryFor(configMap *corev1.ConfigMap) *trustedCABundleSummary {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	if c.summary != nil && c.uid == configMap.UID && c.resourceVersion == configMap.ResourceVersion {
+		return c.summary
+	}
+	c.uid = configMap.UID
+	c.resourceVersion = configMap.ResourceVersion
+	c.summary = summarizeTrustedCABundle([]byte(configMap.Data[trustedCABundleKey]))
+	return c.summary
+}
+
+// summarizeTrustedCABundle parses the bundle a PEM block at a time, so that a malformed block is counted rather than
+// failing the whole bundle.
+func summarizeTrustedCABundle(data []byte) *trustedCABundleSummary {
+	summary := &trustedCABundleSummary{size: len(data)}
+	for {
+		var block *pem.Block
+		block, data = pem.Decode(data)
+		if block == nil {
+			break
+		}
+		if block.Type != "CERTIFICATE" {
+			continue
+		}
+		certificate, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			summary.malformed++
+			continue
+		}
+		summary.certificates = append(summary.certificates, trustedCertificate{
+			subject:  certificate.Subject.String(),
+			notAfter: certificate.NotAfter,
+		})
+	}
+	return summary
+}
+
+// trustedCABundleCondition warns about an injected trusted CA bundle that is oversized, holds expired certificates or
+// malformed blocks. None of it stops the operand, the condition does not degrade the operator.
+func (c *TargetConfigController) trustedCABundleCondition(now time.Time) operatorv1.OperatorCondition {
+	configMap, err := c.configMapLister.ConfigMaps(operatorclient.TargetNamespace).Get(trustedCABundleName)
+	if apierrors.IsNotFound(err) {
+		return operatorv1.OperatorCondition{Type: trustedCABundleWarningCondition, Status: operatorv1.ConditionFalse, Reason: "NoBundle"}
+	}
+	if err != nil {
+		return operatorv1.OperatorCondition{Type: trustedCABundleWarningCondition, Status: operatorv1.ConditionUnknown, Reason: "Error", Message: err.Error()}
+	}
+	return trustedCABundleConditionFor(c.trustedCABundle.summaryFor(configMap), now)
+}
+
+func trustedCABundleConditionFor(summary *trustedCABundleSummary, now time.Time) operatorv1.OperatorCondition {
+	condition := operatorv1.OperatorCondition{
+		Type:   trustedCABundleWarningCondition,
+		Status: operatorv1.ConditionFalse,
+		Reason: "AsExpected",
+	}
+
+	var reasons, messages []string
+	if summary.size > trustedCABundleSizeLimit {
+		reasons = append(reasons, "BundleTooLarge")
+		messages = append(messages, fmt.Sprintf("configmap/%s -n %s holds %d bytes of certificates, more than %d", trustedCABundleName, operatorclient.TargetNamespace, summary.size, trustedCABundleSizeLimit))
+	}
+	var expired []string
+	for _, certificate := range summary.certificates {
+		if !certificate.notAfter.After(now) {
+			expired = append(expired, fmt.Sprintf("%q expired at %s", certificate.subject, certificate.notAfter.UTC().Format(time.RFC3339)))
+		}
+	}
+	if len(expired) > 0 {
+		reasons = append(reasons, "ExpiredCertificates")
+		listed := expired
+		if len(listed) > maxListedExpiredCertificates {
+			listed = append(listed[:maxListedExpiredCertificates:maxListedExpiredCertificates], fmt.Sprintf("%d more", len(expired)-maxListedExpiredCertificates))
+		}
+		messages = append(messages, fmt.Sprintf("%d of the %d certificates of configmap/%s -n %s are expired: %s", len(expired), len(summary.certificates), trustedCABundleName, operatorclient.TargetNamespace, strings.Join(listed, ", ")))
+	}
+	if summary.malformed > 0 {
+		reasons = append(reasons, "MalformedCertificates")
+		messages = append(messages, fmt.Sprintf("%d PEM blocks of configmap/%s -n %s are not valid certificates", summary.malformed, trustedCABundleName, operatorclient.TargetNamespace))
+	}
+	if len(reasons) == 0 {
+		return condition
+	}
+	condition.Status = operatorv1.ConditionTrue
+	condition.Reason = strings.Join(reasons, "And")
+	condition.Message = strings.Join(messages, "\n")
+	return condition
+}
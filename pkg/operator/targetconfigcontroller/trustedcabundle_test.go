@@ -0,0 +1,115 @@
+package targetconfigcontroller
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	operatorv1 "github.com/openshift/api/operator/v1"
+	"github.com/openshift/library-go/pkg/crypto"
+)
+
+func TestSummarizeTrustedCABundle(t *testing.T) {
+	var bundle []byte
+	for _, name := range []string{"corporate-root", "corporate-intermediate"} {
+		caConfig, err := crypto.MakeSelfSignedCAConfigForDuration(name, time.Hour)
+		if err != nil {
+			t.Fatal(err)
+		}
+		certPEM, _, err := caConfig.GetPEMBytes()
+		if err != nil {
+			t.Fatal(err)
+		}
+		bundle = append(bundle, certPEM...)
+	}
+	bundle = append(bundle, []byte("-----BEGIN CERTIFICATE-----\nbm90IGEgY2VydGlmaWNhdGU=\n-----END CERTIFICATE-----\n")...)
+
+	summary := summarizeTrustedCABundle(bundle)
+	if summary.size != len(bundle) || len(summary.certificates) != 2 || summary.malformed != 1 {
+		t.Errorf("unexpected summary %+v", summary)
+	}
+	if summary.certificates[0].subject != "CN=corporate-root" {
+		t.Errorf("unexpected subject %q", summary.certificates[0].subject)
+	}
+}
+
+func TestTrustedCABundleCache(t *testing.T) {
+	cache := &trustedCABundleCache{}
+	configMap := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{UID: "uid", ResourceVersion: "1"},
+		Data:       map[string]string{trustedCABundleKey: "a"},
+	}
+	first := cache.summaryFor(configMap)
+
+	// the same resource version is not parsed again
+	configMap.Data[trustedCABundleKey] = "ab"
+	if cache.summaryFor(configMap) != first {
+		t.Errorf("expected the cached summary for the same resource version")
+	}
+
+	configMap.ResourceVersion = "2"
+	if summary := cache.summaryFor(configMap); summary == first || summary.size != 2 {
+		t.Errorf("expected a new summary for a new resource version, got %+v", summary)
+	}
+}
+
+func TestTrustedCABundleConditionFor(t *testing.T) {
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	valid := trustedCertificate{subject: "CN=valid", notAfter: now.Add(time.Hour)}
+	expired := func(i int) trustedCertificate {
+		return trustedCertificate{subject: "CN=expired-" + string(rune('a'+i)), notAfter: now.Add(-time.Hour)}
+	}
+
+	tests := []struct {
+		name             string
+		summary          *trustedCABundleSummary
+		expectedStatus   operatorv1.ConditionStatus
+		expectedReason   string
+		expectedMessages []string
+	}{
+		{
+			name:           "healthy",
+			summary:        &trustedCABundleSummary{size: 1024, certificates: []trustedCertificate{valid}},
+			expectedStatus: operatorv1.ConditionFalse,
+			expectedReason: "AsExpected",
+		},
+		{
+			name:             "too large",
+			summary:          &trustedCABundleSummary{size: trustedCABundleSizeLimit + 1, certificates: []trustedCertificate{valid}},
+			expectedStatus:   operatorv1.ConditionTrue,
+			expectedReason:   "BundleTooLarge",
+			expectedMessages: []string{"more than 524288"},
+		},
+		{
+			name: "expired and malformed",
+			summary: &trustedCABundleSummary{
+				size:         1024,
+				certificates: []trustedCertificate{valid, expired(0), expired(1), expired(2), expired(3), expired(4), expired(5), expired(6)},
+				malformed:    2,
+			},
+			expectedStatus: operatorv1.ConditionTrue,
+			expectedReason: "ExpiredCertificatesAndMalformedCertificates",
+			expectedMessages: []string{
+				`7 of the 8 certificates`,
+				`"CN=expired-e" expired at 2024-01-01T11:00:00Z, 2 more`,
+				"2 PEM blocks",
+			},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			condition := trustedCABundleConditionFor(test.summary, now)
+			if condition.Status != test.expectedStatus || condition.Reason != test.expectedReason {
+				t.Errorf("expected %s/%s, got %v", test.expectedStatus, test.expectedReason, condition)
+			}
+			for _, message := range test.expectedMessages {
+				if !strings.Contains(condition.Message, message) {
+					t.Errorf("expected %q in the message, got %q", message, condition.Message)
+				}
+			}
+		})
+	}
+}
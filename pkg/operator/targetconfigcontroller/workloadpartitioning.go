@@ -0,0 +1,44 @@
+package targetconfigcontroller
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+const (
+	// managementWorkloadAnnotation makes the kubelet of a cluster with workload partitioning run the pod on the
+	// CPUs reserved for management workloads. It is ignored elsewhere.
+	managementWorkloadAnnotation      = "target.workload.openshift.io/management"
+	managementWorkloadAnnotationValue = `{"effect": "PreferredDuringScheduling"}`
+)
+
+// defaultWorkloadRequests are the requests of the sidecars of the pod manifest, given to a container without any.
+var defaultWorkloadRequests = corev1.ResourceList{
+	corev1.ResourceCPU:    resource.MustParse("5m"),
+	corev1.ResourceMemory: resource.MustParse("50Mi"),
+}
+
+// applyWorkloadPartitioning makes the pod a management workload the kubelet can account to the management
+// partition: it is annotated, every container requests CPU and memory, which the kubelet turns into CPU shares of
+// the partition, and no container has a CPU limit, the kubelet leaves pods of the Guaranteed QoS class alone.
+func applyWorkloadPartitioning(pod *corev1.Pod) {
+	if pod.Annotations == nil {
+		pod.Annotations = map[string]string{}
+	}
+	pod.Annotations[managementWorkloadAnnotation] = managementWorkloadAnnotationValue
+
+	for _, containers := range [][]corev1.Container{pod.Spec.InitContainers, pod.Spec.Containers} {
+		for i := range containers {
+			resources := &containers[i].Resources
+			if resources.Requests == nil {
+				resources.Requests = corev1.ResourceList{}
+			}
+			for name, quantity := range defaultWorkloadRequests {
+				if _, ok := resources.Requests[name]; !ok {
+					resources.Requests[name] = quantity.DeepCopy()
+				}
+			}
+			delete(resources.Limits, corev1.ResourceCPU)
+		}
+	}
+}
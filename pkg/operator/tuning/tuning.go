@@ -0,0 +1,965 @@
+package tuning
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/ghodss/yaml"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/apimachinery/pkg/util/validation"
+	corev1client "k8s.io/client-go/kubernetes/typed/core/v1"
+	corev1listers "k8s.io/client-go/listers/core/v1"
+
+	operatorv1 "github.com/openshift/api/operator/v1"
+	"github.com/openshift/library-go/pkg/operator/loglevel"
+
+	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/operator/operatorclient"
+)
+
+const (
+	// ConfigMapName is the name of the configmap in the operator namespace that holds the
+	// supported tuning knobs for the kube-controller-manager operand.
+	ConfigMapName = "kube-controller-manager-tuning"
+	// ConfigKey is the key in ConfigMapName that holds the serialized Config.
+	ConfigKey = "config.yaml"
+)
+
+// ProbeProfile selects the timings used for the operand's startup, liveness and readiness probes.
+type ProbeProfile string
+
+const (
+	// DefaultProbeProfile keeps the probe timings shipped in the pod manifest.
+	DefaultProbeProfile ProbeProfile = "Default"
+	// SlowStorageProbeProfile relaxes the probe timings for clusters with slow disks, where
+	// etcd compaction can stall the operand long enough to trip the default probes.
+	SlowStorageProbeProfile ProbeProfile = "SlowStorage"
+)
+
+// WorkloadProfile selects controller concurrency and resync settings suited to the kind of workload the cluster runs.
+type WorkloadProfile string
+
+const (
+	// DefaultWorkloadProfile keeps the upstream kube-controller-manager defaults.
+	DefaultWorkloadProfile WorkloadProfile = "Default"
+	// CIWorkloadProfile is meant for ephemeral, namespace-heavy clusters such as CI, where namespaces and
+	// pods are created and deleted at a high rate and namespace deletion would otherwise back up.
+	CIWorkloadProfile WorkloadProfile = "CI"
+)
+
+// LoggingFormat selects the log format of the kube-controller-manager.
+type LoggingFormat string
+
+const (
+	// TextLoggingFormat keeps the default klog text format.
+	TextLoggingFormat LoggingFormat = "text"
+	// JSONLoggingFormat writes structured JSON logs, for sites that feed the control plane logs into a SIEM.
+	JSONLoggingFormat LoggingFormat = "json"
+)
+
+// CASourcePolicy selects whether a source of a CA bundle is combined into it.
+type CASourcePolicy string
+
+const (
+	// AutoCASourcePolicy includes the source while it exists and skips it otherwise.
+	AutoCASourcePolicy CASourcePolicy = "Auto"
+	// RequiredCASourcePolicy fails the bundle while the source is missing.
+	RequiredCASourcePolicy CASourcePolicy = "Required"
+	// ExcludedCASourcePolicy leaves the source out even when it exists.
+	ExcludedCASourcePolicy CASourcePolicy = "Excluded"
+)
+
+// Config holds the tuning knobs read from the ConfigMapName configmap.
+// The zero value means no tuning was requested.
+type Config struct {
+	// ProbeProfile adjusts the health probes of the operand containers.
+	ProbeProfile ProbeProfile `json:"probeProfile,omitempty"`
+
+	// WorkloadProfile adjusts the worker counts and resync periods of the operand controllers.
+	WorkloadProfile WorkloadProfile `json:"workloadProfile,omitempty"`
+
+	// LoggingFormat sets the log format of the kube-controller-manager container.
+	LoggingFormat LoggingFormat `json:"loggingFormat,omitempty"`
+
+	// FastFailover shortens the leader election lease and the node monitor period of the kube-controller-manager, for
+	// metro-DR clusters where the default failover is too slow. It cannot be combined with the SlowStorage probe
+	// profile, a cluster with a slow etcd would lose the lease on every latency spike.
+	FastFailover bool `json:"fastFailover,omitempty"`
+
+	// FlagsFile passes the kube-controller-manager flags rendered from the config in a file, one per line, instead of
+	// on the exec line of the pod manifest. It keeps long flag lists below the argument length limit and makes the
+	// flag changes of a revision readable.
+	FlagsFile bool `json:"flagsFile,omitempty"`
+
+	// DisabledControllers lists kube-controller-manager controllers to turn off, e.g. nodeipam when an external IPAM
+	// assigns the pod CIDRs. Only the controllers in DisableableControllers are accepted.
+	DisabledControllers []string `json:"disabledControllers,omitempty"`
+
+	// ArchitectureImages maps a node architecture, as reported in node.status.nodeInfo.architecture, to the images
+	// to run on control plane nodes of that architecture. Unset images fall back to the release payload. Every node of
+	// a heterogeneous control plane runs the same manifest, so an image is only overridden there when all its
+	// architectures name the same manifest list.
+	ArchitectureImages map[string]OperandImages `json:"architectureImages,omitempty"`
+
+	// PausedResources lists the resources in the target namespace the operator stops updating, by the name of their
+	// target config syncer, e.g. "pod" for the kube-controller-manager-pod configmap. It lets an engineer hand-edit a
+	// resource while debugging without the operator reverting the change.
+	PausedResources []string `json:"pausedResources,omitempty"`
+
+	// InternalAPIServerCA names a configmap of the openshift-config namespace whose ca-bundle.crt signed the serving
+	// certificate of the internal API load balancer, for clusters where that load balancer does not present the
+	// certificate of the kube-apiserver. The kube-controller-manager then trusts it in addition to the kube-apiserver
+	// serving CAs.
+	InternalAPIServerCA string `json:"internalAPIServerCA,omitempty"`
+
+	// ServiceAccountRootCA names a configmap of the openshift-config namespace whose ca-bundle.crt replaces the
+	// serviceaccount-ca bundle as the root CA published in the service account token secrets and the kube-root-ca.crt
+	// configmaps, for clusters where the pods reach the kube-apiserver through a proxy with its own CA. The bundle
+	// must verify the certificate the kube-apiserver presents to the pods.
+	ServiceAccountRootCA string `json:"serviceAccountRootCA,omitempty"`
+
+	// ServiceAccountCASources sets the policy of the sources of the serviceaccount-ca bundle by name, see
+	// ServiceAccountCABundleSources. A source defaults to Auto, which includes it while it exists. Excluded leaves out
+	// e.g. the default-ingress-cert of a cluster without an ingress operator, Required fails the bundle without it.
+	ServiceAccountCASources map[string]CASourcePolicy `json:"serviceAccountCASources,omitempty"`
+
+	// HostedControlPlaneNamespace is the namespace the operand runs in as a deployment when the control plane
+	// topology is External. It is required for that topology and ignored otherwise.
+	HostedControlPlaneNamespace string `json:"hostedControlPlaneNamespace,omitempty"`
+
+	// CompletedPodRetention is how long the completed installer and pruner pods of superseded revisions are kept
+	// before they are deleted. It defaults to a day.
+	CompletedPodRetention *metav1.Duration `json:"completedPodRetention,omitempty"`
+
+	// ComponentLogLevels overrides spec.logLevel for individual operand containers, e.g. to debug the
+	// cluster-policy-controller without raising the verbosity of the kube-controller-manager. The operator itself
+	// follows spec.operatorLogLevel.
+	ComponentLogLevels map[string]operatorv1.LogLevel `json:"componentLogLevels,omitempty"`
+
+	// Env holds environment variables set in all the operand containers, e.g. GOGC for performance experiments or
+	// AWS_REGION for the cloud SDK. Only the names in AllowedEnvVars are accepted.
+	Env map[string]string `json:"env,omitempty"`
+
+	// ExtraMounts mounts configmaps and secrets of the openshift-config namespace into the kube-controller-manager
+	// container, e.g. a custom cloud CA file or a webhook token config referenced by an extended argument.
+	ExtraMounts []ExtraMount `json:"extraMounts,omitempty"`
+
+	// PodDNS adjusts the name resolution of the kube-controller-manager pod, for disconnected clusters whose control
+	// plane hosts do not resolve the API VIP or whose resolv.conf does not suit the operand.
+	PodDNS PodDNSConfig `json:"podDNS,omitempty"`
+
+	// Storage holds the sync periods of the volume controllers of the kube-controller-manager, for clusters with
+	// many persistent volumes where the defaults either lag or load the kube-apiserver.
+	Storage StorageConfig `json:"storage,omitempty"`
+
+	// GarbageCollector sizes the workers of the garbage collector of the kube-controller-manager, which falls behind
+	// on clusters with millions of objects at its default worker count.
+	GarbageCollector GarbageCollectorConfig `json:"garbageCollector,omitempty"`
+
+	// AuthWebhookCache sets how long the kube-controller-manager caches the TokenReviews and SubjectAccessReviews of the
+	// requests to its secure port, e.g. the metrics scrapes, for sites where a revoked token must lose its access
+	// sooner than the 10s default. Shorter TTLs cost more requests to the kube-apiserver.
+	AuthWebhookCache AuthWebhookCacheConfig `json:"authWebhookCache,omitempty"`
+
+	// CSRSigner sources the signer of the kubelet and client certificates the kube-controller-manager issues from an
+	// external provider, for clusters whose CA keys must stay in an HSM or a KMS. Unset, the kube-controller-manager
+	// signs with the csr-signer the operator rotates.
+	CSRSigner CSRSignerConfig `json:"csrSigner,omitempty"`
+
+	// CanaryRollout installs every new revision on the first node of the rollout only, and holds the other nodes until
+	// the kube-controller-manager there proved healthy. A revision failing on the canary node is not rolled out any
+	// further.
+	CanaryRollout CanaryRolloutConfig `json:"canaryRollout,omitempty"`
+
+	// ClusterPolicyController holds the supported knobs of the cluster-policy-controller, which has its own config
+	// file and is not covered by the kube-controller-manager settings above.
+	ClusterPolicyController ClusterPolicyControllerConfig `json:"clusterPolicyController,omitempty"`
+
+	// RequeueDelays shortens the waits of the signer rotations, for test clusters where the fixed waits slow down
+	// the e2e suites.
+	RequeueDelays RequeueDelaysConfig `json:"requeueDelays,omitempty"`
+
+	// InformerResync sets the resync periods of the informers of the operator, spreading the resyncs of the control
+	// plane operators on large clusters. It is only read when the operator starts.
+	InformerResync InformerResyncConfig `json:"informerResync,omitempty"`
+
+	// Scheduling places the operator deployment, for managed services that run the control plane operators on
+	// dedicated infra nodes of the control plane. The installer pods run on the node they install the operand on and
+	// tolerate every taint already.
+	Scheduling SchedulingConfig `json:"scheduling,omitempty"`
+
+	// SecurePort moves the secure port of the kube-controller-manager, which also serves its metrics, off 10257 when
+	// a third-party agent on the control plane hosts needs that port. The probes and the services follow it. It is
+	// only read when the operator starts.
+	SecurePort *int32 `json:"securePort,omitempty"`
+
+	// Profiling turns the profiling endpoints of the kube-controller-manager off, or enables contention profiling
+	// for a performance investigation.
+	Profiling ProfilingConfig `json:"profiling,omitempty"`
+
+	// ExpectedNodeCount is the number of nodes the cluster is planned to grow to. The node CIDRs the
+	// kube-controller-manager allocates, for the network plugins that rely on it, are sized so that the cluster networks
+	// cover it, or the current node count when that is larger, and ConfigObservationDegraded reports when they cannot.
+	ExpectedNodeCount int32 `json:"expectedNodeCount,omitempty"`
+
+	// RolloutNodeOrder lists control plane nodes by name in the order they receive new revisions, e.g. a canary node
+	// first or the node of a separate failure domain last. The nodes not listed follow in their current order. The
+	// order is applied between rollouts, a rollout in progress keeps its order.
+	RolloutNodeOrder []string `json:"rolloutNodeOrder,omitempty"`
+
+	// DegradedSyncThreshold is the number of consecutive failed syncs of a resource before TargetConfigControllerDegraded
+	// reports it. A successful sync clears the resource at once. It keeps the short failures during routine certificate
+	// rotations from flapping the Degraded condition and defaults to DefaultDegradedSyncThreshold, 1 reports every failure.
+	DegradedSyncThreshold *int32 `json:"degradedSyncThreshold,omitempty"`
+}
+
+// DefaultDegradedSyncThreshold is the number of consecutive failed syncs reported as degraded unless
+// DegradedSyncThreshold is set.
+const DefaultDegradedSyncThreshold int32 = 3
+
+// DefaultSecurePort is the secure port of the kube-controller-manager unless SecurePort is set.
+const DefaultSecurePort int32 = 10257
+
+// ReservedPorts are the host ports of the control plane nodes SecurePort must not take, by their user.
+var ReservedPorts = map[int32]string{
+	2379:  "etcd",
+	2380:  "etcd",
+	6443:  "kube-apiserver",
+	9443:  "kube-controller-manager-recovery-controller",
+	10250: "kubelet",
+	10259: "kube-scheduler",
+	10357: "cluster-policy-controller",
+}
+
+// SecurePortOrDefault returns SecurePort, or DefaultSecurePort when it is unset.
+func (c *Config) SecurePortOrDefault() int32 {
+	if c.SecurePort == nil {
+		return DefaultSecurePort
+	}
+	return *c.SecurePort
+}
+
+// DegradedSyncThresholdOrDefault returns DegradedSyncThreshold, or DefaultDegradedSyncThreshold when it is unset.
+func (c *Config) DegradedSyncThresholdOrDefault() int32 {
+	if c.DegradedSyncThreshold == nil {
+		return DefaultDegradedSyncThreshold
+	}
+	return *c.DegradedSyncThreshold
+}
+
+// ServiceAccountCASourcePolicy returns the policy of a source of the serviceaccount-ca bundle, AutoCASourcePolicy unless
+// ServiceAccountCASources sets it.
+func (c *Config) ServiceAccountCASourcePolicy(source string) CASourcePolicy {
+	if policy, ok := c.ServiceAccountCASources[source]; ok {
+		return policy
+	}
+	return AutoCASourcePolicy
+}
+
+// InformerResyncConfig holds the resync periods of the informers of the operator.
+// Unset values keep the defaults.
+type InformerResyncConfig struct {
+	// Period is the resync period of the informers of the namespaces not listed in NamespacePeriods. It defaults to
+	// 10m.
+	Period *metav1.Duration `json:"period,omitempty"`
+	// NamespacePeriods overrides Period for the informers of individual namespaces.
+	NamespacePeriods map[string]metav1.Duration `json:"namespacePeriods,omitempty"`
+	// JitterFactor lengthens every period by a random fraction of up to JitterFactor, so that the operators do not
+	// resync in lockstep. It defaults to 0.1.
+	JitterFactor *float64 `json:"jitterFactor,omitempty"`
+}
+
+// ProfilingConfig holds the profiling flags of the kube-controller-manager.
+// Unset values keep the kube-controller-manager defaults, profiling on and contention profiling off.
+type ProfilingConfig struct {
+	// Disabled turns off the /debug/pprof endpoints of the secure port, the --profiling flag, for sites whose
+	// security baseline forbids them.
+	Disabled bool `json:"disabled,omitempty"`
+	// Contention records the blocking of goroutines on mutexes and channels, the --contention-profiling flag. It
+	// costs CPU on every contended lock and is meant to be turned on temporarily.
+	Contention bool `json:"contention,omitempty"`
+}
+
+// StorageConfig holds the supported knobs of the volume controllers of the kube-controller-manager.
+// Unset values keep the kube-controller-manager defaults.
+type StorageConfig struct {
+	// PVBinderSyncPeriod is how often the persistent volume binder resyncs all the volumes and claims, the
+	// --pv-binder-sync-period flag. It defaults to 15s.
+	PVBinderSyncPeriod *metav1.Duration `json:"pvBinderSyncPeriod,omitempty"`
+	// AttachDetachReconcileSyncPeriod is how often the attach/detach controller reconciles the attached volumes with
+	// the nodes, the --attach-detach-reconcile-sync-period flag. It defaults to 1m.
+	AttachDetachReconcileSyncPeriod *metav1.Duration `json:"attachDetachReconcileSyncPeriod,omitempty"`
+	// ConcurrentEphemeralVolumeSyncs is the number of generic ephemeral volumes whose claims are created
+	// concurrently, the --concurrent-ephemeralvolume-syncs flag. It defaults to 5.
+	ConcurrentEphemeralVolumeSyncs int32 `json:"concurrentEphemeralVolumeSyncs,omitempty"`
+}
+
+// The bounds of the StorageConfig values. A shorter period keeps the kube-apiserver busy with the volumes of a large
+// cluster, a longer one delays the recovery from missed events for too long.
+const (
+	MinPVBinderSyncPeriod                    = 5 * time.Second
+	MaxPVBinderSyncPeriod                    = 10 * time.Minute
+	MinAttachDetachReconcileSyncPeriod       = time.Second
+	MaxAttachDetachReconcileSyncPeriod       = 10 * time.Minute
+	MaxConcurrentEphemeralVolumeSyncs  int32 = 50
+)
+
+// GarbageCollectorConfig holds the worker count of the garbage collector of the kube-controller-manager.
+// Unset values keep the worker count of the workload profile.
+type GarbageCollectorConfig struct {
+	// ConcurrentSyncs is the number of objects garbage collected concurrently, the --concurrent-gc-syncs flag. It
+	// defaults to 20, or 50 with the CI workload profile, and cannot be combined with AutoScale.
+	ConcurrentSyncs int32 `json:"concurrentSyncs,omitempty"`
+	// AutoScale sizes the workers to the number of objects stored in the cluster, as counted by the
+	// apiserver_storage_objects metric of the kube-apiserver. The workers grow in steps as the object count grows,
+	// never below the worker count of the workload profile and never above MaxConcurrentSyncs.
+	AutoScale bool `json:"autoScale,omitempty"`
+	// MaxConcurrentSyncs bounds the workers AutoScale sets. It defaults to MaxConcurrentGCSyncs.
+	MaxConcurrentSyncs int32 `json:"maxConcurrentSyncs,omitempty"`
+}
+
+// MaxConcurrentGCSyncs bounds the GarbageCollectorConfig worker counts. Every worker issues its own requests to the
+// kube-apiserver, more of them load it without speeding up the garbage collection.
+const MaxConcurrentGCSyncs int32 = 100
+
+// MaxConcurrentSyncsOrDefault returns MaxConcurrentSyncs, or MaxConcurrentGCSyncs when it is unset.
+func (c GarbageCollectorConfig) MaxConcurrentSyncsOrDefault() int32 {
+	if c.MaxConcurrentSyncs == 0 {
+		return MaxConcurrentGCSyncs
+	}
+	return c.MaxConcurrentSyncs
+}
+
+// AuthWebhookCacheConfig holds the cache TTLs of the delegated authentication and authorization of the secure port
+// of the kube-controller-manager. Unset values keep the kube-controller-manager defaults of 10s, 0 turns the cache off.
+type AuthWebhookCacheConfig struct {
+	// TokenTTL is how long an authenticated token is cached, the --authentication-token-webhook-cache-ttl flag.
+	TokenTTL *metav1.Duration `json:"tokenTTL,omitempty"`
+	// AuthorizedTTL is how long an allowed request is cached, the --authorization-webhook-cache-authorized-ttl flag.
+	AuthorizedTTL *metav1.Duration `json:"authorizedTTL,omitempty"`
+	// UnauthorizedTTL is how long a denied request is cached, the --authorization-webhook-cache-unauthorized-ttl flag.
+	UnauthorizedTTL *metav1.Duration `json:"unauthorizedTTL,omitempty"`
+}
+
+// MaxAuthWebhookCacheTTL bounds the AuthWebhookCacheConfig TTLs, a revoked token would keep its access for that long.
+const MaxAuthWebhookCacheTTL = 5 * time.Minute
+
+// CSRSignerProvider is the kind of external provider the csr-signer is sourced from.
+type CSRSignerProvider string
+
+const (
+	// HostPathCSRSignerProvider reads the signer from a directory of the control plane hosts, which an agent of the HSM
+	// or the KMS keeps populated, e.g. the node plugin of a secrets store CSI driver writing to a tmpfs.
+	HostPathCSRSignerProvider CSRSignerProvider = "HostPath"
+)
+
+// CSRSignerConfig selects the external provider of the csr-signer. The key of an external signer never reaches the
+// kube-apiserver, only its certificate is published in the CA bundles.
+type CSRSignerConfig struct {
+	// Provider is the kind of the external provider. Unset, the csr-signer of the operator is used.
+	Provider CSRSignerProvider `json:"provider,omitempty"`
+	// HostPath is the absolute directory of the control plane hosts holding the tls.crt and the tls.key of the
+	// signer. It is required by the HostPath provider.
+	HostPath string `json:"hostPath,omitempty"`
+	// CertificateConfigMap names a configmap of the openshift-config namespace whose tls.crt holds the certificate of
+	// the signer followed by its intermediates. The operator does not see the files of the hosts, the CA bundles
+	// trusting the signer are built from it. It is required by every provider.
+	CertificateConfigMap string `json:"certificateConfigMap,omitempty"`
+}
+
+// CanaryRolloutConfig turns on the canary rollout of the revisions and sets how long the canary node is validated.
+type CanaryRolloutConfig struct {
+	// Enabled holds a new revision at the first node of the rollout until the validation passes there.
+	Enabled bool `json:"enabled,omitempty"`
+	// ValidationPeriod is how long the kube-controller-manager on the canary node has to stay ready before the other
+	// nodes follow. It defaults to DefaultCanaryValidationPeriod and is at most MaxCanaryValidationPeriod.
+	ValidationPeriod *metav1.Duration `json:"validationPeriod,omitempty"`
+	// Timeout is how long the canary node may take to install the revision and pass the validation before the
+	// rollout is aborted. It defaults to DefaultCanaryTimeout and is at least the ValidationPeriod.
+	Timeout *metav1.Duration `json:"timeout,omitempty"`
+}
+
+const (
+	// DefaultCanaryValidationPeriod is the CanaryRolloutConfig ValidationPeriod unless it is set.
+	DefaultCanaryValidationPeriod = 2 * time.Minute
+	// MaxCanaryValidationPeriod bounds the ValidationPeriod, the installer of the next node waits for the canary no
+	// longer than its rollout barrier, 4 minutes, before it fails and is retried.
+	MaxCanaryValidationPeriod = 3 * time.Minute
+	// DefaultCanaryTimeout is the CanaryRolloutConfig Timeout unless it is set.
+	DefaultCanaryTimeout = 10 * time.Minute
+)
+
+// ValidationPeriodOrDefault returns ValidationPeriod, or DefaultCanaryValidationPeriod when it is unset.
+func (c CanaryRolloutConfig) ValidationPeriodOrDefault() time.Duration {
+	if c.ValidationPeriod == nil {
+		return DefaultCanaryValidationPeriod
+	}
+	return c.ValidationPeriod.Duration
+}
+
+// TimeoutOrDefault returns Timeout, or DefaultCanaryTimeout when it is unset.
+func (c CanaryRolloutConfig) TimeoutOrDefault() time.Duration {
+	if c.Timeout == nil {
+		return DefaultCanaryTimeout
+	}
+	return c.Timeout.Duration
+}
+
+// SchedulingConfig holds the node selector and tolerations added to the operator deployment.
+type SchedulingConfig struct {
+	// NodeSelector is added to the node selector of the operator deployment. The keys the deployment manifest sets
+	// are not overridden.
+	NodeSelector map[string]string `json:"nodeSelector,omitempty"`
+	// Tolerations are added to the tolerations of the operator deployment.
+	Tolerations []corev1.Toleration `json:"tolerations,omitempty"`
+}
+
+// PodDNSConfig holds the host aliases and the DNS settings of the kube-controller-manager pod. The pod runs on the host
+// network and resolves names with the resolv.conf of the host unless they are set.
+type PodDNSConfig struct {
+	// HostAliases are added to the /etc/hosts of the pod, e.g. the name of the API VIP. At most MaxHostAliases.
+	HostAliases []corev1.HostAlias `json:"hostAliases,omitempty"`
+	// DNSPolicy is Default, the resolv.conf of the host, or None, only DNSConfig. The cluster DNS is not accepted,
+	// it depends on the kube-controller-manager.
+	DNSPolicy corev1.DNSPolicy `json:"dnsPolicy,omitempty"`
+	// DNSConfig is merged into the resolv.conf selected by the DNSPolicy. It is required by the None policy.
+	DNSConfig *corev1.PodDNSConfig `json:"dnsConfig,omitempty"`
+}
+
+// RequeueDelaysConfig holds the delays the signer rotations wait for before they are synced again.
+// Unset values keep the defaults.
+type RequeueDelaysConfig struct {
+	// SATokenSignerPropagation is how long a new service account token signer is published to the kube-apiserver
+	// before the kube-controller-manager signs tokens with it. It defaults to 5m.
+	SATokenSignerPropagation *metav1.Duration `json:"saTokenSignerPropagation,omitempty"`
+	// CSRSignerTrustRecheck is how often a rotated csr-signer waiting for the trust of the kube-apiserver is checked
+	// again. It defaults to 1m.
+	CSRSignerTrustRecheck *metav1.Duration `json:"csrSignerTrustRecheck,omitempty"`
+	// Padding is added to the time a new signer becomes usable before it is checked again. It defaults to 10s.
+	Padding *metav1.Duration `json:"padding,omitempty"`
+}
+
+// ClusterPolicyControllerConfig holds the supported knobs of the cluster-policy-controller.
+// Unset values keep the cluster-policy-controller defaults.
+type ClusterPolicyControllerConfig struct {
+	// ResourceQuotaConcurrentSyncs is the number of cluster resource quotas synced concurrently.
+	ResourceQuotaConcurrentSyncs int32 `json:"resourceQuotaConcurrentSyncs,omitempty"`
+	// ResourceQuotaSyncPeriod is how often the usage of all the cluster resource quotas is recalculated.
+	ResourceQuotaSyncPeriod *metav1.Duration `json:"resourceQuotaSyncPeriod,omitempty"`
+	// ResourceQuotaMinResyncPeriod is the minimum resync period of the informers backing the quota evaluation.
+	ResourceQuotaMinResyncPeriod *metav1.Duration `json:"resourceQuotaMinResyncPeriod,omitempty"`
+}
+
+// LogLevelComponents are the operand containers whose verbosity follows the log level.
+var LogLevelComponents = sets.New[string](
+	"kube-controller-manager",
+	"cluster-policy-controller",
+	"kube-controller-manager-recovery-controller",
+)
+
+// AllowedEnvVars are the environment variables that can be set in the operand containers. Variables the operator
+// sets itself, such as the proxy ones, are deliberately absent.
+var AllowedEnvVars = sets.New[string](
+	"GODEBUG",
+	"GOGC",
+	"GOMAXPROCS",
+	"GOMEMLIMIT",
+	"AWS_REGION",
+	"AWS_STS_REGIONAL_ENDPOINTS",
+)
+
+// DisableableControllers maps the kube-controller-manager controllers that can be turned off to the warning
+// reported while they are. Controllers the cluster cannot work without are deliberately absent.
+var DisableableControllers = map[string]string{
+	"nodeipam":                 "nodes are no longer assigned pod CIDRs, an external IPAM must allocate them",
+	"ttl":                      "node objects are no longer annotated with the cache TTL for the kubelets",
+	"ttl-after-finished":       "finished jobs are no longer deleted after their ttlSecondsAfterFinished",
+	"route":                    "cloud routes for the pod CIDRs are no longer created",
+	"service":                  "cloud load balancers are no longer created for LoadBalancer services",
+	"cloud-node-lifecycle":     "nodes deleted in the cloud are no longer removed from the cluster",
+	"horizontalpodautoscaling": "horizontal pod autoscalers are no longer reconciled",
+}
+
+// ServiceAccountCABundleSources are the configmaps of openshift-config-managed the serviceaccount-ca bundle is combined
+// from, in order, and whether they can be excluded. The kube-apiserver-server-ca verifies the kube-apiserver, the
+// default-ingress-cert the routes served with the default ingress certificate.
+var ServiceAccountCABundleSources = []struct {
+	Name       string
+	Excludable bool
+}{
+	{Name: "kube-apiserver-server-ca"},
+	{Name: "default-ingress-cert", Excludable: true},
+}
+
+// MaxHostAliases bounds the number of PodDNS host aliases, and MaxHostAliasHostnames the hostnames of each of them.
+const (
+	MaxHostAliases        = 8
+	MaxHostAliasHostnames = 8
+
+	// the resolv.conf of the pod takes 3 nameservers, the search domains are bounded like the classic resolv.conf
+	maxDNSNameservers = 3
+	maxDNSSearches    = 6
+)
+
+// MaxExtraMounts bounds the number of ExtraMounts, every key of their sources is a separate mount in the operand.
+const MaxExtraMounts = 8
+
+// reservedMountPaths are the directories of the operand the ExtraMounts cannot be mounted in or over.
+var reservedMountPaths = []string{
+	"/etc/kubernetes/static-pod-resources",
+	"/etc/kubernetes/static-pod-certs",
+}
+
+// ExtraMount mounts every key of a configmap or secret of the openshift-config namespace as a file in MountPath.
+type ExtraMount struct {
+	// Name identifies the mount, it must be a DNS label.
+	Name string `json:"name"`
+	// ConfigMap is the name of the source configmap. Exactly one of ConfigMap and Secret is set.
+	ConfigMap string `json:"configMap,omitempty"`
+	// Secret is the name of the source secret. Exactly one of ConfigMap and Secret is set.
+	Secret string `json:"secret,omitempty"`
+	// MountPath is the absolute directory the files are mounted in.
+	MountPath string `json:"mountPath"`
+}
+
+// OperandImages holds the pull specs of the images that make up the operand static pod.
+type OperandImages struct {
+	// KubeControllerManager is the image of the kube-controller-manager container.
+	KubeControllerManager string `json:"kubeControllerManager,omitempty"`
+	// ClusterPolicyController is the image of the cluster-policy-controller container.
+	ClusterPolicyController string `json:"clusterPolicyController,omitempty"`
+	// Operator is the image of the cert-syncer and recovery-controller containers.
+	Operator string `json:"operator,omitempty"`
+}
+
+// Get returns the tuning config from the operator namespace. A missing configmap or key yields an empty config.
+func Get(lister corev1listers.ConfigMapLister) (*Config, error) {
+	cm, err := lister.ConfigMaps(operatorclient.OperatorNamespace).Get(ConfigMapName)
+	if apierrors.IsNotFound(err) {
+		return &Config{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return Parse([]byte(cm.Data[ConfigKey]))
+}
+
+// Read returns the tuning config from the operator namespace without an informer, for the settings needed before
+// the informers are started. A missing configmap or key yields an empty config.
+func Read(ctx context.Context, client corev1client.ConfigMapsGetter) (*Config, error) {
+	cm, err := client.ConfigMaps(operatorclient.OperatorNamespace).Get(ctx, ConfigMapName, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		return &Config{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return Parse([]byte(cm.Data[ConfigKey]))
+}
+
+// Parse decodes and validates a serialized Config.
+func Parse(data []byte) (*Config, error) {
+	config := &Config{}
+	if len(data) == 0 {
+		return config, nil
+	}
+	if err := yaml.Unmarshal(data, config); err != nil {
+		return nil, fmt.Errorf("failed to parse %s/%s[%s]: %v", operatorclient.OperatorNamespace, ConfigMapName, ConfigKey, err)
+	}
+	if err := config.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid %s/%s[%s]: %v", operatorclient.OperatorNamespace, ConfigMapName, ConfigKey, err)
+	}
+	return config, nil
+}
+
+// Validate checks that all the values set in the config are supported.
+func (c *Config) Validate() error {
+	switch c.ProbeProfile {
+	case "", DefaultProbeProfile, SlowStorageProbeProfile:
+	default:
+		return fmt.Errorf("unknown probeProfile %q", c.ProbeProfile)
+	}
+	switch c.WorkloadProfile {
+	case "", DefaultWorkloadProfile, CIWorkloadProfile:
+	default:
+		return fmt.Errorf("unknown workloadProfile %q", c.WorkloadProfile)
+	}
+	switch c.LoggingFormat {
+	case "", TextLoggingFormat, JSONLoggingFormat:
+	default:
+		return fmt.Errorf("unknown loggingFormat %q", c.LoggingFormat)
+	}
+	if c.Profiling.Disabled && c.Profiling.Contention {
+		return fmt.Errorf("profiling: contention profiling requires profiling, it cannot be combined with disabled")
+	}
+	if c.ExpectedNodeCount < 0 {
+		return fmt.Errorf("expectedNodeCount must not be negative")
+	}
+	rolloutNodes := sets.New[string]()
+	for _, node := range c.RolloutNodeOrder {
+		if len(node) == 0 {
+			return fmt.Errorf("rolloutNodeOrder: empty node name")
+		}
+		if rolloutNodes.Has(node) {
+			return fmt.Errorf("rolloutNodeOrder: node %q is listed twice", node)
+		}
+		rolloutNodes.Insert(node)
+	}
+	if c.FastFailover && c.ProbeProfile == SlowStorageProbeProfile {
+		return fmt.Errorf("fastFailover cannot be combined with the %s probeProfile", SlowStorageProbeProfile)
+	}
+	for _, resource := range c.PausedResources {
+		if len(resource) == 0 {
+			return fmt.Errorf("pausedResources: empty resource")
+		}
+	}
+	if len(c.HostedControlPlaneNamespace) > 0 {
+		if errs := validation.IsDNS1123Label(c.HostedControlPlaneNamespace); len(errs) > 0 {
+			return fmt.Errorf("invalid hostedControlPlaneNamespace %q: %s", c.HostedControlPlaneNamespace, strings.Join(errs, ", "))
+		}
+	}
+	if len(c.InternalAPIServerCA) > 0 {
+		if errs := validation.IsDNS1123Subdomain(c.InternalAPIServerCA); len(errs) > 0 {
+			return fmt.Errorf("invalid internalAPIServerCA %q: %s", c.InternalAPIServerCA, strings.Join(errs, ", "))
+		}
+	}
+	if len(c.ServiceAccountRootCA) > 0 {
+		if errs := validation.IsDNS1123Subdomain(c.ServiceAccountRootCA); len(errs) > 0 {
+			return fmt.Errorf("invalid serviceAccountRootCA %q: %s", c.ServiceAccountRootCA, strings.Join(errs, ", "))
+		}
+	}
+	if err := validateServiceAccountCASources(c.ServiceAccountCASources); err != nil {
+		return err
+	}
+	if c.CompletedPodRetention != nil && c.CompletedPodRetention.Duration < 0 {
+		return fmt.Errorf("negative completedPodRetention %s", c.CompletedPodRetention.Duration)
+	}
+	if c.ClusterPolicyController.ResourceQuotaConcurrentSyncs < 0 {
+		return fmt.Errorf("negative clusterPolicyController.resourceQuotaConcurrentSyncs %d", c.ClusterPolicyController.ResourceQuotaConcurrentSyncs)
+	}
+	if period := c.ClusterPolicyController.ResourceQuotaSyncPeriod; period != nil && period.Duration <= 0 {
+		return fmt.Errorf("non-positive clusterPolicyController.resourceQuotaSyncPeriod %s", period.Duration)
+	}
+	if period := c.ClusterPolicyController.ResourceQuotaMinResyncPeriod; period != nil && period.Duration <= 0 {
+		return fmt.Errorf("non-positive clusterPolicyController.resourceQuotaMinResyncPeriod %s", period.Duration)
+	}
+	if err := c.Storage.validate(); err != nil {
+		return err
+	}
+	if err := c.GarbageCollector.validate(); err != nil {
+		return err
+	}
+	if err := c.PodDNS.validate(); err != nil {
+		return err
+	}
+	if err := c.Scheduling.validate(); err != nil {
+		return err
+	}
+	if err := c.AuthWebhookCache.validate(); err != nil {
+		return err
+	}
+	if err := c.CSRSigner.validate(); err != nil {
+		return err
+	}
+	if err := c.CanaryRollout.validate(); err != nil {
+		return err
+	}
+	if delay := c.RequeueDelays.SATokenSignerPropagation; delay != nil && delay.Duration <= 0 {
+		return fmt.Errorf("non-positive requeueDelays.saTokenSignerPropagation %s", delay.Duration)
+	}
+	if delay := c.RequeueDelays.CSRSignerTrustRecheck; delay != nil && delay.Duration <= 0 {
+		return fmt.Errorf("non-positive requeueDelays.csrSignerTrustRecheck %s", delay.Duration)
+	}
+	if delay := c.RequeueDelays.Padding; delay != nil && delay.Duration < 0 {
+		return fmt.Errorf("negative requeueDelays.padding %s", delay.Duration)
+	}
+	if period := c.InformerResync.Period; period != nil && period.Duration <= 0 {
+		return fmt.Errorf("non-positive informerResync.period %s", period.Duration)
+	}
+	for namespace, period := range c.InformerResync.NamespacePeriods {
+		if period.Duration <= 0 {
+			return fmt.Errorf("non-positive informerResync.namespacePeriods[%s] %s", namespace, period.Duration)
+		}
+	}
+	if factor := c.InformerResync.JitterFactor; factor != nil && (*factor < 0 || *factor > 1) {
+		return fmt.Errorf("informerResync.jitterFactor %v is not between 0 and 1", *factor)
+	}
+	if threshold := c.DegradedSyncThreshold; threshold != nil && *threshold < 1 {
+		return fmt.Errorf("degradedSyncThreshold %d is less than 1", *threshold)
+	}
+	if port := c.SecurePort; port != nil {
+		if *port < 1024 || *port > 65535 {
+			return fmt.Errorf("securePort %d is not between 1024 and 65535", *port)
+		}
+		if user, ok := ReservedPorts[*port]; ok {
+			return fmt.Errorf("securePort %d is used by the %s", *port, user)
+		}
+	}
+	for component, logLevel := range c.ComponentLogLevels {
+		if !LogLevelComponents.Has(component) {
+			return fmt.Errorf("componentLogLevels: unknown component %q, expected one of %s", component, strings.Join(sets.List(LogLevelComponents), ", "))
+		}
+		if !loglevel.ValidLogLevel(logLevel) {
+			return fmt.Errorf("componentLogLevels[%s]: unknown log level %q", component, logLevel)
+		}
+	}
+	for name := range c.Env {
+		if !AllowedEnvVars.Has(name) {
+			return fmt.Errorf("env: unsupported variable %q, expected one of %s", name, strings.Join(sets.List(AllowedEnvVars), ", "))
+		}
+	}
+	disabledControllers := sets.New[string]()
+	for _, controller := range c.DisabledControllers {
+		if _, ok := DisableableControllers[controller]; !ok {
+			return fmt.Errorf("disabledControllers: controller %q cannot be disabled, expected one of %s", controller, strings.Join(sets.List(sets.KeySet(DisableableControllers)), ", "))
+		}
+		if disabledControllers.Has(controller) {
+			return fmt.Errorf("disabledControllers: duplicate controller %q", controller)
+		}
+		disabledControllers.Insert(controller)
+	}
+	if err := validateExtraMounts(c.ExtraMounts); err != nil {
+		return err
+	}
+	for architecture, images := range c.ArchitectureImages {
+		if len(architecture) == 0 {
+			return fmt.Errorf("architectureImages: empty architecture")
+		}
+		if images == (OperandImages{}) {
+			return fmt.Errorf("architectureImages[%s]: no images set", architecture)
+		}
+	}
+	return nil
+}
+
+func (c StorageConfig) validate() error {
+	if period := c.PVBinderSyncPeriod; period != nil && (period.Duration < MinPVBinderSyncPeriod || period.Duration > MaxPVBinderSyncPeriod) {
+		return fmt.Errorf("storage.pvBinderSyncPeriod %s is not between %s and %s", period.Duration, MinPVBinderSyncPeriod, MaxPVBinderSyncPeriod)
+	}
+	if period := c.AttachDetachReconcileSyncPeriod; period != nil && (period.Duration < MinAttachDetachReconcileSyncPeriod || period.Duration > MaxAttachDetachReconcileSyncPeriod) {
+		return fmt.Errorf("storage.attachDetachReconcileSyncPeriod %s is not between %s and %s", period.Duration, MinAttachDetachReconcileSyncPeriod, MaxAttachDetachReconcileSyncPeriod)
+	}
+	if syncs := c.ConcurrentEphemeralVolumeSyncs; syncs < 0 || syncs > MaxConcurrentEphemeralVolumeSyncs {
+		return fmt.Errorf("storage.concurrentEphemeralVolumeSyncs %d is not between 1 and %d", syncs, MaxConcurrentEphemeralVolumeSyncs)
+	}
+	return nil
+}
+
+func (c GarbageCollectorConfig) validate() error {
+	if syncs := c.ConcurrentSyncs; syncs < 0 || syncs > MaxConcurrentGCSyncs {
+		return fmt.Errorf("garbageCollector.concurrentSyncs %d is not between 1 and %d", syncs, MaxConcurrentGCSyncs)
+	}
+	if syncs := c.MaxConcurrentSyncs; syncs < 0 || syncs > MaxConcurrentGCSyncs {
+		return fmt.Errorf("garbageCollector.maxConcurrentSyncs %d is not between 1 and %d", syncs, MaxConcurrentGCSyncs)
+	}
+	if c.AutoScale && c.ConcurrentSyncs > 0 {
+		return fmt.Errorf("garbageCollector.concurrentSyncs cannot be combined with autoScale")
+	}
+	if !c.AutoScale && c.MaxConcurrentSyncs > 0 {
+		return fmt.Errorf("garbageCollector.maxConcurrentSyncs requires autoScale")
+	}
+	return nil
+}
+
+func (c AuthWebhookCacheConfig) validate() error {
+	for _, ttl := range []struct {
+		name  string
+		value *metav1.Duration
+	}{
+		{name: "tokenTTL", value: c.TokenTTL},
+		{name: "authorizedTTL", value: c.AuthorizedTTL},
+		{name: "unauthorizedTTL", value: c.UnauthorizedTTL},
+	} {
+		if ttl.value != nil && (ttl.value.Duration < 0 || ttl.value.Duration > MaxAuthWebhookCacheTTL) {
+			return fmt.Errorf("authWebhookCache.%s %s is not between 0s and %s", ttl.name, ttl.value.Duration, MaxAuthWebhookCacheTTL)
+		}
+	}
+	return nil
+}
+
+func (c CSRSignerConfig) validate() error {
+	switch c.Provider {
+	case "":
+		if len(c.HostPath) > 0 || len(c.CertificateConfigMap) > 0 {
+			return fmt.Errorf("csrSigner: hostPath and certificateConfigMap require a provider")
+		}
+		return nil
+	case HostPathCSRSignerProvider:
+		if !path.IsAbs(c.HostPath) || path.Clean(c.HostPath) != c.HostPath || c.HostPath == "/" {
+			return fmt.Errorf("csrSigner.hostPath %q is not a clean absolute directory", c.HostPath)
+		}
+	default:
+		return fmt.Errorf("unknown csrSigner.provider %q", c.Provider)
+	}
+	if errs := validation.IsDNS1123Subdomain(c.CertificateConfigMap); len(errs) > 0 {
+		return fmt.Errorf("invalid csrSigner.certificateConfigMap %q: %s", c.CertificateConfigMap, strings.Join(errs, ", "))
+	}
+	return nil
+}
+
+func (c CanaryRolloutConfig) validate() error {
+	if c.ValidationPeriod != nil && (c.ValidationPeriod.Duration <= 0 || c.ValidationPeriod.Duration > MaxCanaryValidationPeriod) {
+		return fmt.Errorf("canaryRollout.validationPeriod %s is not between 0s and %s", c.ValidationPeriod.Duration, MaxCanaryValidationPeriod)
+	}
+	if timeout := c.TimeoutOrDefault(); timeout < c.ValidationPeriodOrDefault() {
+		return fmt.Errorf("canaryRollout.timeout %s is shorter than the validationPeriod %s", timeout, c.ValidationPeriodOrDefault())
+	}
+	return nil
+}
+
+func (c SchedulingConfig) validate() error {
+	for key, value := range c.NodeSelector {
+		if errs := validation.IsQualifiedName(key); len(errs) > 0 {
+			return fmt.Errorf("scheduling.nodeSelector: invalid key %q: %s", key, strings.Join(errs, ", "))
+		}
+		if errs := validation.IsValidLabelValue(value); len(errs) > 0 {
+			return fmt.Errorf("scheduling.nodeSelector[%s]: invalid value %q: %s", key, value, strings.Join(errs, ", "))
+		}
+	}
+	for i, toleration := range c.Tolerations {
+		if len(toleration.Key) > 0 {
+			if errs := validation.IsQualifiedName(toleration.Key); len(errs) > 0 {
+				return fmt.Errorf("scheduling.tolerations[%d]: invalid key %q: %s", i, toleration.Key, strings.Join(errs, ", "))
+			}
+		}
+		switch toleration.Operator {
+		case "", corev1.TolerationOpEqual:
+			if len(toleration.Key) == 0 {
+				return fmt.Errorf("scheduling.tolerations[%d]: the Equal operator requires a key", i)
+			}
+		case corev1.TolerationOpExists:
+			if len(toleration.Value) > 0 {
+				return fmt.Errorf("scheduling.tolerations[%d]: the Exists operator does not take a value", i)
+			}
+		default:
+			return fmt.Errorf("scheduling.tolerations[%d]: unknown operator %q", i, toleration.Operator)
+		}
+		switch toleration.Effect {
+		case "", corev1.TaintEffectNoSchedule, corev1.TaintEffectPreferNoSchedule, corev1.TaintEffectNoExecute:
+		default:
+			return fmt.Errorf("scheduling.tolerations[%d]: unknown effect %q", i, toleration.Effect)
+		}
+		if toleration.TolerationSeconds != nil && toleration.Effect != corev1.TaintEffectNoExecute {
+			return fmt.Errorf("scheduling.tolerations[%d]: tolerationSeconds requires the NoExecute effect", i)
+		}
+	}
+	return nil
+}
+
+func (c PodDNSConfig) validate() error {
+	if len(c.HostAliases) > MaxHostAliases {
+		return fmt.Errorf("podDNS.hostAliases: %d host aliases, at most %d are allowed", len(c.HostAliases), MaxHostAliases)
+	}
+	for i, alias := range c.HostAliases {
+		if net.ParseIP(alias.IP) == nil {
+			return fmt.Errorf("podDNS.hostAliases[%d]: invalid ip %q", i, alias.IP)
+		}
+		if len(alias.Hostnames) == 0 || len(alias.Hostnames) > MaxHostAliasHostnames {
+			return fmt.Errorf("podDNS.hostAliases[%d]: %d hostnames, between 1 and %d are allowed", i, len(alias.Hostnames), MaxHostAliasHostnames)
+		}
+		for _, hostname := range alias.Hostnames {
+			if errs := validation.IsDNS1123Subdomain(hostname); len(errs) > 0 {
+				return fmt.Errorf("podDNS.hostAliases[%d]: invalid hostname %q: %s", i, hostname, strings.Join(errs, ", "))
+			}
+		}
+	}
+	switch c.DNSPolicy {
+	case "", corev1.DNSDefault:
+	case corev1.DNSNone:
+		if c.DNSConfig == nil || len(c.DNSConfig.Nameservers) == 0 {
+			return fmt.Errorf("podDNS.dnsPolicy: the %s policy requires dnsConfig.nameservers", corev1.DNSNone)
+		}
+	default:
+		return fmt.Errorf("podDNS.dnsPolicy: unsupported policy %q, expected %s or %s", c.DNSPolicy, corev1.DNSDefault, corev1.DNSNone)
+	}
+	if c.DNSConfig == nil {
+		return nil
+	}
+	if len(c.DNSConfig.Nameservers) > maxDNSNameservers {
+		return fmt.Errorf("podDNS.dnsConfig.nameservers: %d nameservers, at most %d are allowed", len(c.DNSConfig.Nameservers), maxDNSNameservers)
+	}
+	for _, nameserver := range c.DNSConfig.Nameservers {
+		if net.ParseIP(nameserver) == nil {
+			return fmt.Errorf("podDNS.dnsConfig.nameservers: invalid ip %q", nameserver)
+		}
+	}
+	if len(c.DNSConfig.Searches) > maxDNSSearches {
+		return fmt.Errorf("podDNS.dnsConfig.searches: %d search domains, at most %d are allowed", len(c.DNSConfig.Searches), maxDNSSearches)
+	}
+	for _, search := range c.DNSConfig.Searches {
+		if errs := validation.IsDNS1123Subdomain(strings.TrimSuffix(search, ".")); len(errs) > 0 {
+			return fmt.Errorf("podDNS.dnsConfig.searches: invalid search domain %q: %s", search, strings.Join(errs, ", "))
+		}
+	}
+	for i, option := range c.DNSConfig.Options {
+		if len(option.Name) == 0 {
+			return fmt.Errorf("podDNS.dnsConfig.options[%d]: empty name", i)
+		}
+	}
+	return nil
+}
+
+func validateServiceAccountCASources(sources map[string]CASourcePolicy) error {
+	for name, policy := range sources {
+		var known, excludable bool
+		var names []string
+		for _, source := range ServiceAccountCABundleSources {
+			names = append(names, source.Name)
+			if source.Name == name {
+				known, excludable = true, source.Excludable
+			}
+		}
+		if !known {
+			return fmt.Errorf("serviceAccountCASources: unknown source %q, expected one of %s", name, strings.Join(names, ", "))
+		}
+		switch policy {
+		case AutoCASourcePolicy, RequiredCASourcePolicy:
+		case ExcludedCASourcePolicy:
+			if !excludable {
+				return fmt.Errorf("serviceAccountCASources[%s]: the source cannot be excluded", name)
+			}
+		default:
+			return fmt.Errorf("serviceAccountCASources[%s]: unknown policy %q", name, policy)
+		}
+	}
+	return nil
+}
+
+func validateExtraMounts(mounts []ExtraMount) error {
+	if len(mounts) > MaxExtraMounts {
+		return fmt.Errorf("extraMounts: %d mounts, at most %d are supported", len(mounts), MaxExtraMounts)
+	}
+	names := sets.New[string]()
+	mountPaths := sets.New[string]()
+	for _, mount := range mounts {
+		if errs := validation.IsDNS1123Label(mount.Name); len(errs) > 0 {
+			return fmt.Errorf("extraMounts: invalid name %q: %s", mount.Name, strings.Join(errs, ", "))
+		}
+		if names.Has(mount.Name) {
+			return fmt.Errorf("extraMounts: duplicate name %q", mount.Name)
+		}
+		names.Insert(mount.Name)
+		if (len(mount.ConfigMap) > 0) == (len(mount.Secret) > 0) {
+			return fmt.Errorf("extraMounts[%s]: exactly one of configMap and secret must be set", mount.Name)
+		}
+		if !path.IsAbs(mount.MountPath) || path.Clean(mount.MountPath) != mount.MountPath {
+			return fmt.Errorf("extraMounts[%s]: mountPath %q is not a clean absolute path", mount.Name, mount.MountPath)
+		}
+		for _, reserved := range reservedMountPaths {
+			if mount.MountPath == reserved || strings.HasPrefix(mount.MountPath, reserved+"/") || strings.HasPrefix(reserved, mount.MountPath+"/") || mount.MountPath == "/" {
+				return fmt.Errorf("extraMounts[%s]: mountPath %q overlaps %s", mount.Name, mount.MountPath, reserved)
+			}
+		}
+		if mountPaths.Has(mount.MountPath) {
+			return fmt.Errorf("extraMounts[%s]: duplicate mountPath %q", mount.Name, mount.MountPath)
+		}
+		mountPaths.Insert(mount.MountPath)
+	}
+	return nil
+}
@@ -1,9 +1,11 @@
 package test
 
 import (
+	"strings"
 	"testing"
 
 	"github.com/ghodss/yaml"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 
 	"github.com/openshift/cluster-kube-controller-manager-operator/pkg/cmd/render"
 	"github.com/openshift/library-go/pkg/assets"
@@ -28,3 +30,39 @@ func readAllYaml(path string, t *testing.T) {
 		}
 	}
 }
+
+// TestClusterProfiles checks that every cluster profile gets exactly one operator deployment and that the managed
+// profile, which has no control plane nodes, does not pin it to them.
+func TestClusterProfiles(t *testing.T) {
+	manifests, err := assets.New("../../manifests/", render.TemplateData{}, nil, assets.OnlyYaml)
+	if err != nil {
+		t.Fatal(err)
+	}
+	deployments := map[string][]map[string]interface{}{}
+	for _, m := range manifests {
+		manifest := map[string]interface{}{}
+		if err := yaml.Unmarshal(m.Data, &manifest); err != nil {
+			t.Fatalf("Unexpected error unmarshaling %s: %v", m.Name, err)
+		}
+		if manifest["kind"] != "Deployment" {
+			continue
+		}
+		annotations, _, _ := unstructured.NestedStringMap(manifest, "metadata", "annotations")
+		for annotation, value := range annotations {
+			if profile, ok := strings.CutPrefix(annotation, "include.release.openshift.io/"); ok && value == "true" {
+				deployments[profile] = append(deployments[profile], manifest)
+			}
+		}
+	}
+
+	for _, profile := range []string{"self-managed-high-availability", "single-node-developer", "ibm-cloud-managed"} {
+		if len(deployments[profile]) != 1 {
+			t.Errorf("expected one operator deployment for the %s profile, got %d", profile, len(deployments[profile]))
+		}
+	}
+	for _, deployment := range deployments["ibm-cloud-managed"] {
+		if _, found, _ := unstructured.NestedFieldNoCopy(deployment, "spec", "template", "spec", "nodeSelector"); found {
+			t.Errorf("expected no node selector in the ibm-cloud-managed deployment")
+		}
+	}
+}